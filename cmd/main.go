@@ -1,6 +1,11 @@
 package main
 
-import "github.com/saufiroja/fin-ai/internal/app"
+import (
+	// The runtime image has no zoneinfo, user time zones are resolved from the embedded database
+	_ "time/tzdata"
+
+	"github.com/saufiroja/fin-ai/internal/app"
+)
 
 func main() {
 	apps := app.NewApp()
@@ -0,0 +1,287 @@
+// Command receipt_eval scores the receipt extraction on a labelled dataset, see pkg/receipteval for the
+// dataset layout.
+//
+// The live mode sends the images to Gemini, -record keeps the responses next to the labels. The replay
+// mode, the default, parses the recorded responses again so prompt parsing and scoring can be checked
+// without an API key. The command exits with status 1 when a score drops more than -threshold below the
+// -baseline.
+//
+//	go run ./cmd/receipt_eval -dataset ./receipts -mode live -record
+//	go run ./cmd/receipt_eval -dataset ./receipts -baseline ./receipts/baseline.json
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	_ "time/tzdata"
+
+	"github.com/saufiroja/fin-ai/config"
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/constants/prompt"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/categories"
+	"github.com/saufiroja/fin-ai/internal/domains/forecast"
+	"github.com/saufiroja/fin-ai/internal/domains/transaction"
+	"github.com/saufiroja/fin-ai/internal/i18n"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/internal/services"
+	"github.com/saufiroja/fin-ai/pkg/fx"
+	"github.com/saufiroja/fin-ai/pkg/llm"
+	logging "github.com/saufiroja/fin-ai/pkg/loggings"
+	"github.com/saufiroja/fin-ai/pkg/receipteval"
+	"google.golang.org/genai"
+)
+
+const (
+	modeLive   = "live"
+	modeReplay = "replay"
+)
+
+// receiptPromptKeys are the prompts of the extraction call that -prompts can replace
+var receiptPromptKeys = []prompt.Key{
+	prompt.ReceiptExtractionSystem,
+	prompt.ReceiptExtractionUser,
+	prompt.ReceiptPreferences,
+}
+
+type options struct {
+	dataset        string
+	mode           string
+	record         bool
+	model          string
+	locale         string
+	currency       string
+	timezone       string
+	promptsDir     string
+	promptVersion  string
+	baseline       string
+	updateBaseline bool
+	threshold      float64
+}
+
+func main() {
+	opts := options{}
+	flag.StringVar(&opts.dataset, "dataset", "", "directory of the labelled receipts")
+	flag.StringVar(&opts.mode, "mode", modeReplay, "live sends the images to Gemini, replay parses the recorded responses")
+	flag.BoolVar(&opts.record, "record", false, "live mode: write the responses as <name>.response.txt for later replays")
+	flag.StringVar(&opts.model, "model", "", "live mode: Gemini model, the model of the receipt upload by default")
+	flag.StringVar(&opts.locale, "locale", i18n.Default, "locale of the prompts")
+	flag.StringVar(&opts.currency, "currency", constants.DefaultCurrencyCode, "base currency given to the model")
+	flag.StringVar(&opts.timezone, "timezone", models.DefaultUserPreferences().Timezone, "time zone given to the model")
+	flag.StringVar(&opts.promptsDir, "prompts", "", "directory of prompt versions laid out like PROMPTS_DIR")
+	flag.StringVar(&opts.promptVersion, "prompt-version", "", "version in -prompts replacing the built-in receipt prompts")
+	flag.StringVar(&opts.baseline, "baseline", "", "JSON file of the scores to compare with")
+	flag.BoolVar(&opts.updateBaseline, "update-baseline", false, "write the scores of this run to -baseline")
+	flag.Float64Var(&opts.threshold, "threshold", 0.02, "largest drop of a precision or recall below the baseline")
+	flag.Parse()
+
+	if err := run(opts); err != nil {
+		fmt.Fprintln(os.Stderr, "receipt_eval:", err)
+		os.Exit(1)
+	}
+}
+
+func run(opts options) error {
+	if opts.dataset == "" {
+		return errors.New("-dataset is required")
+	}
+	if opts.mode != modeLive && opts.mode != modeReplay {
+		return fmt.Errorf("unknown -mode %q, use %s or %s", opts.mode, modeLive, modeReplay)
+	}
+	if opts.record && opts.mode != modeLive {
+		return errors.New("-record needs -mode live")
+	}
+	if opts.updateBaseline && opts.baseline == "" {
+		return errors.New("-update-baseline needs -baseline")
+	}
+
+	dataset, err := receipteval.LoadDataset(opts.dataset)
+	if err != nil {
+		return err
+	}
+
+	currency, err := fx.Lookup(opts.currency)
+	if err != nil {
+		return err
+	}
+	locale := i18n.Match(i18n.Default, opts.locale)
+	prefs := &responses.UserPreferences{BaseCurrency: currency, UserPreferences: models.DefaultUserPreferences()}
+	prefs.Timezone = opts.timezone
+	prefs.Language = locale
+
+	overrides, err := loadPromptOverrides(opts.promptsDir, opts.promptVersion, locale)
+	if err != nil {
+		return err
+	}
+
+	replay := &replayClient{}
+	var client llm.Gemini = replay
+	if opts.mode == modeLive {
+		conf := &config.AppConfig{}
+		conf.Gemini.ApiKey = os.Getenv("GEMINI_API_KEY")
+		if conf.Gemini.ApiKey == "" {
+			return errors.New("GEMINI_API_KEY is required in live mode")
+		}
+		client = llm.NewGemini(conf)
+	}
+
+	extractor := services.NewReceiptExtractor(client, opts.model, logging.NewLogrusAdapter())
+	report := receipteval.NewReport()
+
+	for _, sample := range dataset.Samples {
+		var image []byte
+		switch opts.mode {
+		case modeLive:
+			if sample.ImagePath == "" {
+				return fmt.Errorf("sample %s has no image", sample.Name)
+			}
+			image, err = os.ReadFile(sample.ImagePath)
+			if err != nil {
+				return fmt.Errorf("failed to read image of %s: %w", sample.Name, err)
+			}
+		case modeReplay:
+			if !sample.HasResponse() {
+				return fmt.Errorf("sample %s has no recorded response, run once with -mode live -record", sample.Name)
+			}
+			recorded, err := os.ReadFile(sample.ResponsePath)
+			if err != nil {
+				return fmt.Errorf("failed to read response of %s: %w", sample.Name, err)
+			}
+			replay.response = string(recorded)
+		}
+
+		filename := filepath.Base(sample.ImagePath)
+		if filename == "." {
+			filename = sample.Name + ".jpg"
+		}
+
+		result, err := extractor.Extract(image, filename, dataset.CategoriesString(), prefs, locale, overrides)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: extraction failed: %v\n", sample.Name, err)
+			report.CallFailures++
+			report.Add(&sample.Expected, nil)
+			continue
+		}
+
+		report.InputTokens += result.InputTokens
+		report.OutputTokens += result.OutputTokens
+		if result.ParseErr != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", sample.Name, result.ParseErr)
+			report.ParseFailures++
+		}
+		report.Add(&sample.Expected, result.Receipt)
+
+		if opts.record {
+			if err := os.WriteFile(sample.ResponsePath, []byte(result.Response), 0o644); err != nil {
+				return fmt.Errorf("failed to record response of %s: %w", sample.Name, err)
+			}
+		}
+	}
+
+	scores := report.Scores()
+	var baseline receipteval.Scores
+	if opts.baseline != "" && !opts.updateBaseline {
+		baseline, err = receipteval.LoadScores(opts.baseline)
+		if err != nil {
+			return fmt.Errorf("failed to load baseline: %w", err)
+		}
+	}
+	printReport(report, scores, baseline)
+
+	if opts.updateBaseline {
+		if err := scores.Save(opts.baseline); err != nil {
+			return fmt.Errorf("failed to write baseline: %w", err)
+		}
+		fmt.Printf("\nBaseline written to %s\n", opts.baseline)
+		return nil
+	}
+
+	if regressions := receipteval.Regressions(baseline, scores, opts.threshold); len(regressions) > 0 {
+		fmt.Printf("\nRegressions past %.3f:\n", opts.threshold)
+		for _, regression := range regressions {
+			fmt.Println("  " + regression)
+		}
+		return fmt.Errorf("%d scores regressed", len(regressions))
+	}
+	return nil
+}
+
+// loadPromptOverrides reads <dir>/<prompt_key>/<version>/<locale>.txt for the receipt prompts, prompts
+// without a file keep the built-in text
+func loadPromptOverrides(dir, version, locale string) (map[prompt.Key]string, error) {
+	if dir == "" || version == "" {
+		if dir != "" || version != "" {
+			return nil, errors.New("-prompts and -prompt-version go together")
+		}
+		return nil, nil
+	}
+
+	overrides := map[prompt.Key]string{}
+	for _, key := range receiptPromptKeys {
+		content, err := os.ReadFile(filepath.Join(dir, string(key), version, locale+".txt"))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := prompt.CheckPlaceholders(key, string(content)); err != nil {
+			return nil, err
+		}
+		overrides[key] = string(content)
+	}
+	if len(overrides) == 0 {
+		return nil, fmt.Errorf("no receipt prompt of version %s in locale %s under %s", version, locale, dir)
+	}
+	return overrides, nil
+}
+
+func printReport(report *receipteval.Report, scores, baseline receipteval.Scores) {
+	fmt.Printf("\nSamples: %d, call failures: %d, parse failures: %d, tokens: %d in / %d out\n\n",
+		report.Samples, report.CallFailures, report.ParseFailures, report.InputTokens, report.OutputTokens)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	header := "FIELD\tTP\tFP\tFN\tPRECISION\tRECALL"
+	if baseline != nil {
+		header += "\tBASE PRECISION\tBASE RECALL"
+	}
+	fmt.Fprintln(w, header)
+
+	for _, field := range receipteval.Fields {
+		counts := report.Counts[field]
+		line := fmt.Sprintf("%s\t%d\t%d\t%d\t%.3f\t%.3f", field,
+			counts.TruePositives, counts.FalsePositives, counts.FalseNegatives,
+			scores[field].Precision, scores[field].Recall)
+		if base, ok := baseline[field]; ok {
+			line += fmt.Sprintf("\t%.3f\t%.3f", base.Precision, base.Recall)
+		} else if baseline != nil {
+			line += "\t-\t-"
+		}
+		fmt.Fprintln(w, line)
+	}
+	w.Flush()
+}
+
+// replayClient answers the extraction call with the recorded response of the current sample
+type replayClient struct {
+	response string
+}
+
+func (r *replayClient) Run(ctx context.Context, modelName string, messages []*genai.Content) (*responses.ResponseAI, error) {
+	return &responses.ResponseAI{Response: r.response}, nil
+}
+
+func (r *replayClient) RunAgent(ctx context.Context, message string, userId string) (*responses.ResponseAI, error) {
+	return nil, errors.New("the replay client only answers the receipt extraction")
+}
+
+func (r *replayClient) SetTransactionService(transactionService transaction.TransactionManager) {}
+
+func (r *replayClient) SetCategoryService(categoryService categories.CategoryManager) {}
+
+func (r *replayClient) SetForecastService(forecastService forecast.ForecastManager) {}
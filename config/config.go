@@ -2,7 +2,10 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/joho/godotenv"
 	logging "github.com/saufiroja/fin-ai/pkg/loggings"
@@ -45,6 +48,31 @@ type AppConfig struct {
 	Gemini struct {
 		ApiKey string
 	}
+	Review struct {
+		ConfidenceThreshold float64
+	}
+	FX struct {
+		RatesFile string
+	}
+	Prompts struct {
+		Dir string
+	}
+	Admin struct {
+		Emails []string
+	}
+	LLM struct {
+		CassetteMode string
+		CassettePath string
+	}
+	Scheduler struct {
+		Enabled              bool
+		RecurringInterval    time.Duration
+		SubscriptionInterval time.Duration
+		AnomalyInterval      time.Duration
+		ReportInterval       time.Duration
+		AccountPurgeInterval time.Duration
+		EmbeddingInterval    time.Duration
+	}
 }
 
 var appConfig *AppConfig
@@ -69,6 +97,12 @@ func NewAppConfig(logging logging.Logger) *AppConfig {
 			appConfig.initRedis()
 			appConfig.initMinio()
 			appConfig.initGemini()
+			appConfig.initReview()
+			appConfig.initFX()
+			appConfig.initPrompts()
+			appConfig.initAdmin()
+			appConfig.initLLM(logging)
+			appConfig.initScheduler()
 		} else {
 			logging.LogInfo("AppConfig already created")
 		}
@@ -152,3 +186,97 @@ func (c *AppConfig) initGemini() {
 		panic("Gemini API key not found")
 	}
 }
+
+func (c *AppConfig) initReview() {
+	c.Review.ConfidenceThreshold = 0.7
+	threshold, err := strconv.ParseFloat(os.Getenv("REVIEW_CONFIDENCE_THRESHOLD"), 64)
+	if err == nil && threshold > 0 && threshold <= 1 {
+		c.Review.ConfidenceThreshold = threshold
+	}
+}
+
+// initFX reads the optional CSV of exchange rates used when a pair is not stored in the database
+func (c *AppConfig) initFX() {
+	c.FX.RatesFile = os.Getenv("FX_RATES_FILE")
+}
+
+// initPrompts reads the optional directory of prompt versions, laid out as <prompt_key>/<version>/<locale>.txt
+func (c *AppConfig) initPrompts() {
+	c.Prompts.Dir = os.Getenv("PROMPTS_DIR")
+}
+
+// initAdmin reads the comma separated emails of the users allowed on the admin endpoints
+func (c *AppConfig) initAdmin() {
+	c.Admin.Emails = nil
+	for _, email := range strings.Split(os.Getenv("ADMIN_EMAILS"), ",") {
+		email = strings.ToLower(strings.TrimSpace(email))
+		if email != "" {
+			c.Admin.Emails = append(c.Admin.Emails, email)
+		}
+	}
+}
+
+// initLLM reads the optional cassette that records or replays the HTTP traffic of the LLM clients,
+// see pkg/llm/cassette
+func (c *AppConfig) initLLM(logging logging.Logger) {
+	c.LLM.CassetteMode = strings.ToLower(strings.TrimSpace(os.Getenv("LLM_CASSETTE_MODE")))
+	c.LLM.CassettePath = os.Getenv("LLM_CASSETTE_PATH")
+
+	switch c.LLM.CassetteMode {
+	case "", "off":
+		c.LLM.CassetteMode = ""
+		return
+	case "record", "replay":
+	default:
+		logging.LogPanic("LLM_CASSETTE_MODE must be off, record or replay")
+	}
+	if c.App.Env == "production" {
+		logging.LogPanic("LLM cassettes cannot be used in production")
+	}
+	if c.LLM.CassettePath == "" {
+		logging.LogPanic("LLM_CASSETTE_PATH is required when LLM_CASSETTE_MODE is set")
+	}
+}
+
+func (c *AppConfig) initScheduler() {
+	c.Scheduler.Enabled = os.Getenv("SCHEDULER_ENABLED") != "false"
+
+	c.Scheduler.RecurringInterval = time.Hour
+	interval, err := time.ParseDuration(os.Getenv("SCHEDULER_RECURRING_INTERVAL"))
+	if err == nil && interval > 0 {
+		c.Scheduler.RecurringInterval = interval
+	}
+
+	c.Scheduler.SubscriptionInterval = 24 * time.Hour
+	interval, err = time.ParseDuration(os.Getenv("SCHEDULER_SUBSCRIPTION_INTERVAL"))
+	if err == nil && interval > 0 {
+		c.Scheduler.SubscriptionInterval = interval
+	}
+
+	c.Scheduler.AnomalyInterval = 24 * time.Hour
+	interval, err = time.ParseDuration(os.Getenv("SCHEDULER_ANOMALY_INTERVAL"))
+	if err == nil && interval > 0 {
+		c.Scheduler.AnomalyInterval = interval
+	}
+
+	// Reports are made for the previous month, users who already have one are skipped
+	c.Scheduler.ReportInterval = 24 * time.Hour
+	interval, err = time.ParseDuration(os.Getenv("SCHEDULER_REPORT_INTERVAL"))
+	if err == nil && interval > 0 {
+		c.Scheduler.ReportInterval = interval
+	}
+
+	// Accounts whose deletion grace period has passed are purged with their files
+	c.Scheduler.AccountPurgeInterval = time.Hour
+	interval, err = time.ParseDuration(os.Getenv("SCHEDULER_ACCOUNT_PURGE_INTERVAL"))
+	if err == nil && interval > 0 {
+		c.Scheduler.AccountPurgeInterval = interval
+	}
+
+	// Rows stored without an embedding, e.g. receipt items saved before item names were embedded
+	c.Scheduler.EmbeddingInterval = time.Hour
+	interval, err = time.ParseDuration(os.Getenv("SCHEDULER_EMBEDDING_INTERVAL"))
+	if err == nil && interval > 0 {
+		c.Scheduler.EmbeddingInterval = interval
+	}
+}
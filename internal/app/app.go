@@ -9,11 +9,13 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/saufiroja/fin-ai/internal/middleware"
 )
 
 type App struct {
 	*fiber.App
 	container *Container
+	scheduler *Scheduler
 }
 
 func NewApp() *App {
@@ -34,6 +36,9 @@ func (a *App) Start() {
 		return c.Next()
 	})
 
+	// Accept-Language
+	a.Use(middleware.Localization())
+
 	// Initialize container
 	container := NewContainer()
 	a.container = container
@@ -46,6 +51,13 @@ func (a *App) Start() {
 	routes := NewRoutes(a.App, container)
 	routes.Setup()
 
+	// Start background jobs
+	container.Services.Anomaly.Start()
+	if deps.Config.Scheduler.Enabled {
+		a.scheduler = a.setupScheduler(container)
+		a.scheduler.Start()
+	}
+
 	// Start server
 	deps.Logger.LogInfo(fmt.Sprintf("Starting server on %s", container.GetServerAddress()))
 	if err := a.Listen(container.GetServerAddress()); err != nil {
@@ -93,7 +105,17 @@ func (a *App) performCleanup(deps *Dependencies) {
 		deps.Logger.LogError(fmt.Sprintf("failed to shutdown fiber app gracefully: %v", err))
 	}
 
-	// 2. Close database connections
+	// 2. Stop background jobs before their dependencies go away
+	if a.scheduler != nil {
+		deps.Logger.LogInfo("Stopping scheduler...")
+		a.scheduler.Stop()
+	}
+	if a.container != nil {
+		deps.Logger.LogInfo("Stopping anomaly checks...")
+		a.container.Services.Anomaly.Stop()
+	}
+
+	// 3. Close database connections
 	deps.Logger.LogInfo("Closing database connections...")
 	if deps.Postgres != nil {
 		if err := deps.Postgres.CloseConnection(); err != nil {
@@ -103,7 +125,7 @@ func (a *App) performCleanup(deps *Dependencies) {
 		}
 	}
 
-	// 3. Close Redis connection
+	// 4. Close Redis connection
 	deps.Logger.LogInfo("Closing Redis connection...")
 	if deps.Redis != nil {
 		if err := deps.Redis.Close(); err != nil {
@@ -113,7 +135,7 @@ func (a *App) performCleanup(deps *Dependencies) {
 		}
 	}
 
-	// 4. Close other resources if any (MinIO, etc.)
+	// 5. Close other resources if any (MinIO, etc.)
 	if deps.MinioClient != nil {
 		deps.Logger.LogInfo("MinIO client cleanup completed")
 		// MinIO client usually doesn't need explicit closing
@@ -121,3 +143,60 @@ func (a *App) performCleanup(deps *Dependencies) {
 
 	deps.Logger.LogInfo("All cleanup operations completed")
 }
+
+func (a *App) setupScheduler(container *Container) *Scheduler {
+	deps := container.Dependencies
+	scheduler := NewScheduler(deps.Logger)
+
+	scheduler.Register(Job{
+		Name:     "materialize-recurring-transactions",
+		Interval: deps.Config.Scheduler.RecurringInterval,
+		Run: func(ctx context.Context) error {
+			_, err := container.Services.Recurring.MaterializeDueTransactions(ctx, time.Now())
+			return err
+		},
+	})
+
+	scheduler.Register(Job{
+		Name:     "detect-subscriptions",
+		Interval: deps.Config.Scheduler.SubscriptionInterval,
+		Run: func(ctx context.Context) error {
+			return container.Services.Subscription.DetectSubscriptionsForAllUsers(ctx, time.Now())
+		},
+	})
+
+	scheduler.Register(Job{
+		Name:     "scan-anomalies",
+		Interval: deps.Config.Scheduler.AnomalyInterval,
+		Run: func(ctx context.Context) error {
+			return container.Services.Anomaly.ScanAnomaliesForAllUsers(ctx, time.Now())
+		},
+	})
+
+	scheduler.Register(Job{
+		Name:     "generate-monthly-reports",
+		Interval: deps.Config.Scheduler.ReportInterval,
+		Run: func(ctx context.Context) error {
+			return container.Services.Report.GenerateReportsForAllUsers(ctx, time.Now())
+		},
+	})
+
+	scheduler.Register(Job{
+		Name:     "purge-deleted-accounts",
+		Interval: deps.Config.Scheduler.AccountPurgeInterval,
+		Run: func(ctx context.Context) error {
+			return container.Services.User.PurgeDueAccounts(ctx, time.Now())
+		},
+	})
+
+	scheduler.Register(Job{
+		Name:     "embed-receipt-items",
+		Interval: deps.Config.Scheduler.EmbeddingInterval,
+		Run: func(ctx context.Context) error {
+			_, err := container.Services.Receipt.BackfillItemEmbeddings(ctx)
+			return err
+		},
+	})
+
+	return scheduler
+}
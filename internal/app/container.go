@@ -4,12 +4,14 @@ import (
 	"fmt"
 
 	"github.com/saufiroja/fin-ai/config"
+	"github.com/saufiroja/fin-ai/internal/constants/prompt"
 	"github.com/saufiroja/fin-ai/internal/controllers"
 	"github.com/saufiroja/fin-ai/internal/middleware"
 	"github.com/saufiroja/fin-ai/internal/repositories"
 	"github.com/saufiroja/fin-ai/internal/services"
 	"github.com/saufiroja/fin-ai/internal/utils"
 	"github.com/saufiroja/fin-ai/pkg/databases"
+	"github.com/saufiroja/fin-ai/pkg/fx"
 	"github.com/saufiroja/fin-ai/pkg/llm"
 	logging "github.com/saufiroja/fin-ai/pkg/loggings"
 	"github.com/saufiroja/fin-ai/pkg/minio"
@@ -38,6 +40,11 @@ func (c *Container) initializeDependencies() *Dependencies {
 	logger := logging.NewLogrusAdapter()
 	conf := config.NewAppConfig(logger)
 
+	// Every supported locale must translate every prompt
+	if err := prompt.Validate(); err != nil {
+		logger.LogPanic(err.Error())
+	}
+
 	postgresInstance := databases.NewPostgres(conf, logger)
 
 	minioClient := minio.NewMinioClient(conf, logger)
@@ -52,6 +59,7 @@ func (c *Container) initializeDependencies() *Dependencies {
 	validator := utils.NewValidator()
 	tokenGenerator := utils.NewJWTTokenGenerator(conf)
 	authMiddleware := middleware.Authorization(conf)
+	adminMiddleware := middleware.Admin(conf)
 
 	return &Dependencies{
 		Logger:         logger,
@@ -64,18 +72,36 @@ func (c *Container) initializeDependencies() *Dependencies {
 		TokenGen:       tokenGenerator,
 		AuthMiddleware: authMiddleware,
 		GeminiClient:   geminiClient,
+
+		AdminMiddleware: adminMiddleware,
 	}
 }
 
 func (c *Container) initializeRepositories() *Repositories {
 	return &Repositories{
-		User:          repositories.NewUserRepository(c.Dependencies.Postgres),
-		Chat:          repositories.NewChatRepository(c.Dependencies.Postgres),
-		ModelRegistry: repositories.NewModelRegistryRepository(c.Dependencies.Postgres),
-		LogMessage:    repositories.NewLogMessageRepository(c.Dependencies.Postgres),
-		Transaction:   repositories.NewTransactionRepository(c.Dependencies.Postgres),
-		Category:      repositories.NewCategoryRepository(c.Dependencies.Postgres),
-		Receipt:       repositories.NewReceiptRepository(c.Dependencies.Postgres),
+		User:           repositories.NewUserRepository(c.Dependencies.Postgres),
+		Chat:           repositories.NewChatRepository(c.Dependencies.Postgres),
+		ModelRegistry:  repositories.NewModelRegistryRepository(c.Dependencies.Postgres),
+		LogMessage:     repositories.NewLogMessageRepository(c.Dependencies.Postgres),
+		Transaction:    repositories.NewTransactionRepository(c.Dependencies.Postgres),
+		Category:       repositories.NewCategoryRepository(c.Dependencies.Postgres),
+		Receipt:        repositories.NewReceiptRepository(c.Dependencies.Postgres),
+		Review:         repositories.NewReviewRepository(c.Dependencies.Postgres),
+		Search:         repositories.NewSearchRepository(c.Dependencies.Postgres),
+		Analytics:      repositories.NewAnalyticsRepository(c.Dependencies.Postgres),
+		Recurring:      repositories.NewRecurringRepository(c.Dependencies.Postgres),
+		Recommendation: repositories.NewRecommendationRepository(c.Dependencies.Postgres),
+		Subscription:   repositories.NewSubscriptionRepository(c.Dependencies.Postgres),
+		Anomaly:        repositories.NewAnomalyRepository(c.Dependencies.Postgres),
+		Forecast:       repositories.NewForecastRepository(c.Dependencies.Postgres),
+		Account:        repositories.NewAccountRepository(c.Dependencies.Postgres),
+		Transfer:       repositories.NewTransferRepository(c.Dependencies.Postgres),
+		Tag:            repositories.NewTagRepository(c.Dependencies.Postgres),
+		Import:         repositories.NewTransactionImportRepository(c.Dependencies.Postgres),
+		Report:         repositories.NewReportRepository(c.Dependencies.Postgres),
+		Currency:       repositories.NewCurrencyRepository(c.Dependencies.Postgres),
+		PromptRegistry: repositories.NewPromptRegistryRepository(c.Dependencies.Postgres),
+		Usage:          repositories.NewUsageRepository(c.Dependencies.Postgres),
 	}
 }
 
@@ -86,17 +112,69 @@ func (c *Container) initializeServices() *Services {
 		c.Dependencies.TokenGen,
 		c.Dependencies.Config,
 	)
-	userService := services.NewUserService(c.Repositories.User, c.Dependencies.Logger)
+	currencyService := services.NewCurrencyService(
+		c.Repositories.Currency,
+		c.initializeRateProvider(),
+		c.Dependencies.Logger,
+	)
+	promptRegistryService := services.NewPromptRegistryService(c.Repositories.PromptRegistry, c.Dependencies.Logger)
+	if dir := c.Dependencies.Config.Prompts.Dir; dir != "" {
+		// Prompt files are recorded once, the built-in prompts keep serving when the sync fails
+		if err := promptRegistryService.SyncPromptFiles(dir); err != nil {
+			c.Dependencies.Logger.LogError(fmt.Sprintf("Failed to sync prompt files from %s: %v", dir, err))
+		}
+	}
+	userService := services.NewUserService(c.Repositories.User, c.Dependencies.MinioClient, currencyService, c.Dependencies.Logger)
 	logMessageService := services.NewLogMessageService(c.Repositories.LogMessage, c.Dependencies.Logger)
+	categoryService := services.NewCategoryService(
+		c.Repositories.Category,
+		c.Dependencies.Logger,
+		c.Dependencies.OpenAIClient,
+		logMessageService,
+	)
+	recommendationService := services.NewRecommendationService(
+		c.Repositories.Recommendation,
+		c.Dependencies.Logger,
+		c.Dependencies.OpenAIClient,
+		logMessageService,
+	)
+	anomalyService := services.NewAnomalyService(
+		c.Repositories.Anomaly,
+		recommendationService,
+		c.Dependencies.Logger,
+		c.Dependencies.Config,
+	)
+	accountService := services.NewAccountService(c.Repositories.Account, currencyService, c.Dependencies.Logger)
 	transactionService := services.NewTransactionService(
 		c.Repositories.Transaction,
+		categoryService,
 		c.Dependencies.Logger,
 		c.Dependencies.OpenAIClient,
+		logMessageService,
+		anomalyService,
+		accountService,
+		currencyService,
 	)
-	categoryService := services.NewCategoryService(
-		c.Repositories.Category,
-		c.Dependencies.Logger,
+	transferService := services.NewTransferService(
+		c.Repositories.Transfer,
+		accountService,
+		categoryService,
 		c.Dependencies.OpenAIClient,
+		logMessageService,
+		c.Dependencies.Logger,
+	)
+	recurringService := services.NewRecurringService(
+		c.Repositories.Recurring,
+		transactionService,
+		categoryService,
+		currencyService,
+		c.Dependencies.Logger,
+	)
+	forecastService := services.NewForecastService(
+		c.Repositories.Forecast,
+		recurringService,
+		currencyService,
+		c.Dependencies.Logger,
 	)
 	// Uncomment the following line if you have a Chat service
 	receiptService := services.NewReceiptService(
@@ -108,6 +186,8 @@ func (c *Container) initializeServices() *Services {
 		c.Dependencies.Logger,
 		c.Dependencies.OpenAIClient,
 		c.Dependencies.GeminiClient,
+		userService,
+		promptRegistryService,
 	)
 	chatService := services.NewChatService(
 		c.Repositories.Chat,
@@ -115,34 +195,145 @@ func (c *Container) initializeServices() *Services {
 		c.Dependencies.GeminiClient,
 		c.Dependencies.OpenAIClient,
 		c.Repositories.ModelRegistry,
-		c.Repositories.LogMessage,
+		logMessageService,
 		transactionService,
 		categoryService,
 		receiptService,
+		forecastService,
+		userService,
+		promptRegistryService,
+	)
+
+	reviewService := services.NewReviewService(
+		c.Repositories.Review,
+		categoryService,
+		c.Dependencies.Logger,
+		c.Dependencies.Config,
+	)
+
+	searchService := services.NewSearchService(
+		c.Repositories.Search,
+		c.Dependencies.Logger,
+		c.Dependencies.OpenAIClient,
+		logMessageService,
+	)
+
+	analyticsService := services.NewAnalyticsService(
+		c.Repositories.Analytics,
+		transactionService,
+		currencyService,
+		c.Dependencies.Logger,
+	)
+
+	tagService := services.NewTagService(
+		c.Repositories.Tag,
+		c.Dependencies.OpenAIClient,
+		logMessageService,
+		c.Dependencies.Logger,
+	)
+
+	importService := services.NewTransactionImportService(
+		c.Repositories.Import,
+		categoryService,
+		accountService,
+		c.Dependencies.OpenAIClient,
+		logMessageService,
+		c.Dependencies.Logger,
+	)
+
+	reportService := services.NewReportService(
+		c.Repositories.Report,
+		analyticsService,
+		userService,
+		c.Dependencies.MinioClient,
+		c.Dependencies.OpenAIClient,
+		logMessageService,
+		promptRegistryService,
+		c.Dependencies.Logger,
 	)
 
+	subscriptionService := services.NewSubscriptionService(
+		c.Repositories.Subscription,
+		recurringService,
+		recommendationService,
+		currencyService,
+		c.Dependencies.Logger,
+	)
+
+	usageService := services.NewUsageService(c.Repositories.Usage, c.Dependencies.Logger)
+
 	return &Services{
-		Auth:        authService,
-		User:        userService,
-		LogMessage:  logMessageService,
-		Chat:        chatService,
-		Transaction: transactionService,
-		Category:    categoryService,
-		Receipt:     receiptService,
+		Auth:           authService,
+		User:           userService,
+		LogMessage:     logMessageService,
+		Chat:           chatService,
+		Transaction:    transactionService,
+		Category:       categoryService,
+		Receipt:        receiptService,
+		Review:         reviewService,
+		Search:         searchService,
+		Analytics:      analyticsService,
+		Recurring:      recurringService,
+		Recommendation: recommendationService,
+		Subscription:   subscriptionService,
+		Anomaly:        anomalyService,
+		Forecast:       forecastService,
+		Account:        accountService,
+		Transfer:       transferService,
+		Tag:            tagService,
+		Import:         importService,
+		Report:         reportService,
+		Currency:       currencyService,
+		PromptRegistry: promptRegistryService,
+		Usage:          usageService,
 	}
 }
 
 func (c *Container) initializeControllers() *Controllers {
 	return &Controllers{
-		Auth:        controllers.NewAuthController(c.Services.Auth, c.Dependencies.Validator),
-		User:        controllers.NewUserController(c.Services.User),
-		Chat:        controllers.NewChatController(c.Services.Chat, c.Dependencies.Validator),
-		Transaction: controllers.NewTransactionController(c.Services.Transaction),
-		Category:    controllers.NewCategoryController(c.Services.Category),
-		Receipt:     controllers.NewReceiptController(c.Services.Receipt),
+		Auth:           controllers.NewAuthController(c.Services.Auth, c.Dependencies.Validator),
+		User:           controllers.NewUserController(c.Services.User, c.Dependencies.Validator),
+		Chat:           controllers.NewChatController(c.Services.Chat, c.Dependencies.Validator),
+		Transaction:    controllers.NewTransactionController(c.Services.Transaction, c.Dependencies.Validator),
+		Category:       controllers.NewCategoryController(c.Services.Category),
+		Receipt:        controllers.NewReceiptController(c.Services.Receipt),
+		Review:         controllers.NewReviewController(c.Services.Review, c.Dependencies.Validator),
+		Search:         controllers.NewSearchController(c.Services.Search, c.Dependencies.Validator),
+		Analytics:      controllers.NewAnalyticsController(c.Services.Analytics, c.Dependencies.Validator),
+		Recurring:      controllers.NewRecurringController(c.Services.Recurring, c.Dependencies.Validator),
+		Recommendation: controllers.NewRecommendationController(c.Services.Recommendation, c.Dependencies.Validator),
+		Subscription:   controllers.NewSubscriptionController(c.Services.Subscription),
+		Anomaly:        controllers.NewAnomalyController(c.Services.Anomaly, c.Dependencies.Validator),
+		Forecast:       controllers.NewForecastController(c.Services.Forecast, c.Dependencies.Validator),
+		Account:        controllers.NewAccountController(c.Services.Account, c.Dependencies.Validator),
+		Transfer:       controllers.NewTransferController(c.Services.Transfer, c.Dependencies.Validator),
+		Tag:            controllers.NewTagController(c.Services.Tag, c.Dependencies.Validator),
+		Import:         controllers.NewTransactionImportController(c.Services.Import, c.Dependencies.Validator),
+		Report:         controllers.NewReportController(c.Services.Report, c.Dependencies.Validator),
+		Currency:       controllers.NewCurrencyController(c.Services.Currency, c.Dependencies.Validator),
+		PromptRegistry: controllers.NewPromptRegistryController(c.Services.PromptRegistry, c.Dependencies.Validator),
+		Usage:          controllers.NewUsageController(c.Services.Usage, c.Dependencies.Validator),
 	}
 }
 
+// initializeRateProvider looks up exchange rates in the database first, then in the optional rates file
+func (c *Container) initializeRateProvider() fx.Provider {
+	provider := fx.Chain{fx.NewStoreProvider(c.Repositories.Currency)}
+
+	path := c.Dependencies.Config.FX.RatesFile
+	if path == "" {
+		return provider
+	}
+
+	table, err := fx.LoadFile(path)
+	if err != nil {
+		c.Dependencies.Logger.LogError(fmt.Sprintf("Failed to load exchange rates file %s: %v", path, err))
+		return provider
+	}
+
+	return append(provider, table)
+}
+
 func (c *Container) GetServerAddress() string {
 	return fmt.Sprintf("0.0.0.0:%s", c.Dependencies.Config.Http.Port)
 }
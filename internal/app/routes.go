@@ -24,6 +24,22 @@ func (r *Routes) Setup() {
 	r.setupTransactionRoutes()
 	r.setupCategoryRoutes()
 	r.setupReceiptRoutes()
+	r.setupReviewRoutes()
+	r.setupSearchRoutes()
+	r.setupAnalyticsRoutes()
+	r.setupRecurringRoutes()
+	r.setupRecommendationRoutes()
+	r.setupSubscriptionRoutes()
+	r.setupAnomalyRoutes()
+	r.setupForecastRoutes()
+	r.setupAccountRoutes()
+	r.setupTransferRoutes()
+	r.setupTagRoutes()
+	r.setupImportRoutes()
+	r.setupReportRoutes()
+	r.setupCurrencyRoutes()
+	r.setupPromptRoutes()
+	r.setupUsageRoutes()
 }
 
 func (r *Routes) setupHealthCheck() {
@@ -51,6 +67,10 @@ func (r *Routes) setupUserRoutes() {
 	userGroup := globalApi.Group("/user")
 
 	userGroup.Get("/me", r.container.Dependencies.AuthMiddleware, r.container.Controllers.User.GetMe)
+	userGroup.Get("/me/export", r.container.Dependencies.AuthMiddleware, r.container.Controllers.User.ExportUserData)
+	userGroup.Post("/me/deletion/cancel", r.container.Dependencies.AuthMiddleware, r.container.Controllers.User.CancelAccountDeletion)
+	userGroup.Get("/me/preferences", r.container.Dependencies.AuthMiddleware, r.container.Controllers.User.GetPreferences)
+	userGroup.Put("/me/preferences", r.container.Dependencies.AuthMiddleware, r.container.Controllers.User.UpdatePreferences)
 	userGroup.Put("/:user_id", r.container.Dependencies.AuthMiddleware, r.container.Controllers.User.UpdateUserById)
 	userGroup.Delete("/:user_id", r.container.Dependencies.AuthMiddleware, r.container.Controllers.User.DeleteUserById)
 }
@@ -80,6 +100,15 @@ func (r *Routes) setupTransactionRoutes() {
 	transactionGroup.Get("/overviews",
 		r.container.Dependencies.AuthMiddleware,
 		r.container.Controllers.Transaction.OverviewTransactions)
+	transactionGroup.Get("/stats",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Transaction.GetTransactionsStats)
+	transactionGroup.Post("/filter",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Transaction.FilterTransactions)
+	transactionGroup.Get("/export",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Transaction.ExportTransactions)
 	transactionGroup.Get("/:transaction_id",
 		r.container.Dependencies.AuthMiddleware,
 		r.container.Controllers.Transaction.GetDetailedTransaction)
@@ -126,3 +155,302 @@ func (r *Routes) setupReceiptRoutes() {
 		r.container.Dependencies.AuthMiddleware,
 		r.container.Controllers.Receipt.UpdateReceiptConfirmed)
 }
+
+func (r *Routes) setupReviewRoutes() {
+	globalApi := r.app.Group("/api/v1")
+	reviewGroup := globalApi.Group("/review")
+
+	reviewGroup.Get("/",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Review.GetReviewQueue)
+	reviewGroup.Post("/accept",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Review.AcceptReviewItems)
+	reviewGroup.Post("/recategorize",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Review.RecategorizeReviewItems)
+}
+
+func (r *Routes) setupSearchRoutes() {
+	globalApi := r.app.Group("/api/v1")
+
+	globalApi.Get("/search",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Search.Search)
+}
+
+func (r *Routes) setupAnalyticsRoutes() {
+	globalApi := r.app.Group("/api/v1")
+	analyticsGroup := globalApi.Group("/analytics")
+
+	analyticsGroup.Get("/timeseries",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Analytics.GetTimeSeries)
+	analyticsGroup.Get("/categories",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Analytics.GetCategoryBreakdown)
+	analyticsGroup.Get("/sources",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Analytics.GetTopSources)
+	analyticsGroup.Get("/tags",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Analytics.GetTagBreakdown)
+	analyticsGroup.Get("/comparison",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Analytics.GetPeriodComparison)
+}
+
+func (r *Routes) setupRecurringRoutes() {
+	globalApi := r.app.Group("/api/v1")
+	recurringGroup := globalApi.Group("/recurring")
+
+	recurringGroup.Post("/",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Recurring.CreateRecurringRule)
+	recurringGroup.Get("/",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Recurring.GetRecurringRules)
+	recurringGroup.Get("/upcoming",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Recurring.GetUpcomingBills)
+	recurringGroup.Get("/:recurring_rule_id",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Recurring.GetRecurringRuleById)
+	recurringGroup.Put("/:recurring_rule_id",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Recurring.UpdateRecurringRule)
+	recurringGroup.Delete("/:recurring_rule_id",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Recurring.DeleteRecurringRule)
+	recurringGroup.Post("/:recurring_rule_id/skip",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Recurring.SkipOccurrence)
+	recurringGroup.Put("/:recurring_rule_id/occurrence",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Recurring.EditOccurrence)
+}
+
+func (r *Routes) setupRecommendationRoutes() {
+	globalApi := r.app.Group("/api/v1")
+	recommendationGroup := globalApi.Group("/recommendations")
+
+	recommendationGroup.Get("/",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Recommendation.GetRecommendations)
+	recommendationGroup.Put("/:recommendation_id/read",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Recommendation.MarkRecommendationAsRead)
+}
+
+func (r *Routes) setupSubscriptionRoutes() {
+	globalApi := r.app.Group("/api/v1")
+	subscriptionGroup := globalApi.Group("/subscriptions")
+
+	subscriptionGroup.Get("/",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Subscription.GetSubscriptions)
+	subscriptionGroup.Post("/detect",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Subscription.DetectSubscriptions)
+}
+
+func (r *Routes) setupAnomalyRoutes() {
+	globalApi := r.app.Group("/api/v1")
+	anomalyGroup := globalApi.Group("/anomalies")
+
+	anomalyGroup.Get("/",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Anomaly.GetAnomalies)
+	anomalyGroup.Post("/scan",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Anomaly.ScanAnomalies)
+}
+
+func (r *Routes) setupForecastRoutes() {
+	globalApi := r.app.Group("/api/v1")
+	forecastGroup := globalApi.Group("/forecast")
+
+	forecastGroup.Get("/",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Forecast.GetForecast)
+}
+
+func (r *Routes) setupAccountRoutes() {
+	globalApi := r.app.Group("/api/v1")
+	accountGroup := globalApi.Group("/accounts")
+
+	accountGroup.Post("/",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Account.CreateAccount)
+	accountGroup.Get("/",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Account.GetAccounts)
+	accountGroup.Get("/:account_id",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Account.GetAccountById)
+	accountGroup.Put("/:account_id",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Account.UpdateAccount)
+	accountGroup.Delete("/:account_id",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Account.DeleteAccount)
+	accountGroup.Get("/:account_id/ledger",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Account.GetLedger)
+	accountGroup.Post("/:account_id/transactions",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Account.AssignTransactions)
+	accountGroup.Post("/:account_id/reconcile",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Account.ReconcileAccount)
+	accountGroup.Get("/:account_id/reconciliations",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Account.GetReconciliations)
+}
+
+func (r *Routes) setupTransferRoutes() {
+	globalApi := r.app.Group("/api/v1")
+	transferGroup := globalApi.Group("/transfers")
+
+	transferGroup.Post("/",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Transfer.CreateTransfer)
+	transferGroup.Get("/",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Transfer.GetTransfers)
+	transferGroup.Get("/:transfer_id",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Transfer.GetTransferById)
+	transferGroup.Delete("/:transfer_id",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Transfer.DeleteTransfer)
+}
+
+func (r *Routes) setupTagRoutes() {
+	globalApi := r.app.Group("/api/v1")
+	tagGroup := globalApi.Group("/tags")
+
+	tagGroup.Post("/",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Tag.CreateTag)
+	tagGroup.Get("/",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Tag.GetTags)
+	tagGroup.Post("/suggestions",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Tag.SuggestTags)
+	tagGroup.Put("/transactions/:transaction_id",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Tag.SetTransactionTags)
+	tagGroup.Put("/receipts/:receipt_id",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Tag.SetReceiptTags)
+	tagGroup.Put("/:tag_id",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Tag.UpdateTag)
+	tagGroup.Delete("/:tag_id",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Tag.DeleteTag)
+}
+
+func (r *Routes) setupImportRoutes() {
+	globalApi := r.app.Group("/api/v1")
+	importGroup := globalApi.Group("/imports")
+
+	importGroup.Post("/",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Import.UploadImport)
+	importGroup.Get("/",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Import.GetImports)
+	importGroup.Get("/:import_id",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Import.GetImportById)
+	importGroup.Post("/:import_id/validate",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Import.ValidateImport)
+	importGroup.Post("/:import_id/commit",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Import.CommitImport)
+	importGroup.Post("/:import_id/revert",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Import.RevertImport)
+}
+
+func (r *Routes) setupReportRoutes() {
+	globalApi := r.app.Group("/api/v1")
+	reportGroup := globalApi.Group("/reports")
+
+	reportGroup.Post("/monthly",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Report.GenerateMonthlyReport)
+	reportGroup.Get("/",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Report.GetReports)
+	reportGroup.Get("/:report_id",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Report.GetReportById)
+}
+
+func (r *Routes) setupCurrencyRoutes() {
+	globalApi := r.app.Group("/api/v1")
+	currencyGroup := globalApi.Group("/currencies")
+
+	currencyGroup.Get("/",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Currency.GetCurrencies)
+	currencyGroup.Put("/base",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Currency.SetBaseCurrency)
+	currencyGroup.Get("/rates",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Currency.GetRate)
+	currencyGroup.Post("/rates",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Controllers.Currency.UpsertRates)
+}
+
+func (r *Routes) setupPromptRoutes() {
+	globalApi := r.app.Group("/api/v1")
+	promptGroup := globalApi.Group("/prompts")
+
+	promptGroup.Get("/experiments",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Dependencies.AdminMiddleware,
+		r.container.Controllers.PromptRegistry.GetPromptExperiments)
+	promptGroup.Post("/experiments",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Dependencies.AdminMiddleware,
+		r.container.Controllers.PromptRegistry.StartPromptExperiment)
+	promptGroup.Delete("/experiments/:id",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Dependencies.AdminMiddleware,
+		r.container.Controllers.PromptRegistry.EndPromptExperiment)
+	promptGroup.Get("/experiments/:id/results",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Dependencies.AdminMiddleware,
+		r.container.Controllers.PromptRegistry.GetPromptExperimentResult)
+	promptGroup.Get("/",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Dependencies.AdminMiddleware,
+		r.container.Controllers.PromptRegistry.GetPrompts)
+	promptGroup.Post("/",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Dependencies.AdminMiddleware,
+		r.container.Controllers.PromptRegistry.CreatePromptVersion)
+	promptGroup.Put("/:key/active",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Dependencies.AdminMiddleware,
+		r.container.Controllers.PromptRegistry.ActivatePromptVersion)
+}
+
+func (r *Routes) setupUsageRoutes() {
+	globalApi := r.app.Group("/api/v1")
+	usageGroup := globalApi.Group("/usage")
+
+	usageGroup.Get("/me", r.container.Dependencies.AuthMiddleware, r.container.Controllers.Usage.GetMyUsage)
+	usageGroup.Get("/",
+		r.container.Dependencies.AuthMiddleware,
+		r.container.Dependencies.AdminMiddleware,
+		r.container.Controllers.Usage.GetUsage)
+}
@@ -0,0 +1,82 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	logging "github.com/saufiroja/fin-ai/pkg/loggings"
+)
+
+// Job is a background task run on a fixed interval
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+type Scheduler struct {
+	logger logging.Logger
+	jobs   []Job
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewScheduler(logger logging.Logger) *Scheduler {
+	return &Scheduler{
+		logger: logger,
+	}
+}
+
+func (s *Scheduler) Register(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start runs every job once immediately, then on its interval until Stop is called
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go func(job Job) {
+			defer s.wg.Done()
+			s.logger.LogInfo(fmt.Sprintf("Starting scheduled job %s every %s", job.Name, job.Interval))
+
+			ticker := time.NewTicker(job.Interval)
+			defer ticker.Stop()
+
+			for {
+				s.runJob(ctx, job)
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}(job)
+	}
+}
+
+func (s *Scheduler) runJob(ctx context.Context, job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.LogError(fmt.Sprintf("Panic in scheduled job %s: %v", job.Name, r))
+		}
+	}()
+
+	if err := job.Run(ctx); err != nil {
+		s.logger.LogError(fmt.Sprintf("Scheduled job %s failed: %v", job.Name, err))
+	}
+}
+
+// Stop cancels running jobs and waits for them to return
+func (s *Scheduler) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	s.wg.Wait()
+}
@@ -3,13 +3,29 @@ package app
 import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/saufiroja/fin-ai/config"
+	"github.com/saufiroja/fin-ai/internal/domains/account"
+	"github.com/saufiroja/fin-ai/internal/domains/analytics"
+	"github.com/saufiroja/fin-ai/internal/domains/anomaly"
 	"github.com/saufiroja/fin-ai/internal/domains/auth"
 	"github.com/saufiroja/fin-ai/internal/domains/categories"
 	"github.com/saufiroja/fin-ai/internal/domains/chat"
+	"github.com/saufiroja/fin-ai/internal/domains/currency"
+	"github.com/saufiroja/fin-ai/internal/domains/forecast"
 	"github.com/saufiroja/fin-ai/internal/domains/log_message"
 	"github.com/saufiroja/fin-ai/internal/domains/model_registry"
+	"github.com/saufiroja/fin-ai/internal/domains/prompt_registry"
 	"github.com/saufiroja/fin-ai/internal/domains/receipt"
+	"github.com/saufiroja/fin-ai/internal/domains/recommendation"
+	"github.com/saufiroja/fin-ai/internal/domains/recurring"
+	"github.com/saufiroja/fin-ai/internal/domains/report"
+	"github.com/saufiroja/fin-ai/internal/domains/review"
+	"github.com/saufiroja/fin-ai/internal/domains/search"
+	"github.com/saufiroja/fin-ai/internal/domains/subscription"
+	"github.com/saufiroja/fin-ai/internal/domains/tag"
 	"github.com/saufiroja/fin-ai/internal/domains/transaction"
+	"github.com/saufiroja/fin-ai/internal/domains/transaction_import"
+	"github.com/saufiroja/fin-ai/internal/domains/transfer"
+	"github.com/saufiroja/fin-ai/internal/domains/usage"
 	"github.com/saufiroja/fin-ai/internal/domains/user"
 	"github.com/saufiroja/fin-ai/internal/utils"
 	"github.com/saufiroja/fin-ai/pkg/databases"
@@ -30,33 +46,84 @@ type Dependencies struct {
 	TokenGen       utils.TokenGenerator
 	AuthMiddleware fiber.Handler
 	GeminiClient   llm.Gemini
+
+	// AdminMiddleware runs after AuthMiddleware on the endpoints reserved to ADMIN_EMAILS
+	AdminMiddleware fiber.Handler
 }
 
 type Repositories struct {
-	User          user.UserStorer
-	Chat          chat.ChatStorer
-	ModelRegistry model_registry.ModelRegistryStorer
-	LogMessage    log_message.LogMessageStorer
-	Transaction   transaction.TransactionStorer
-	Category      categories.CategoryStorer
-	Receipt       receipt.ReceiptStorer
+	User           user.UserStorer
+	Chat           chat.ChatStorer
+	ModelRegistry  model_registry.ModelRegistryStorer
+	LogMessage     log_message.LogMessageStorer
+	Transaction    transaction.TransactionStorer
+	Category       categories.CategoryStorer
+	Receipt        receipt.ReceiptStorer
+	Review         review.ReviewStorer
+	Search         search.SearchStorer
+	Analytics      analytics.AnalyticsStorer
+	Recurring      recurring.RecurringStorer
+	Recommendation recommendation.RecommendationStorer
+	Subscription   subscription.SubscriptionStorer
+	Anomaly        anomaly.AnomalyStorer
+	Forecast       forecast.ForecastStorer
+	Account        account.AccountStorer
+	Transfer       transfer.TransferStorer
+	Tag            tag.TagStorer
+	Import         transaction_import.TransactionImportStorer
+	Report         report.ReportStorer
+	Currency       currency.CurrencyStorer
+	PromptRegistry prompt_registry.PromptRegistryStorer
+	Usage          usage.UsageStorer
 }
 
 type Services struct {
-	Auth        auth.AuthManager
-	User        user.UserManager
-	Chat        chat.ChatManager
-	LogMessage  log_message.LogMessageManager
-	Transaction transaction.TransactionManager
-	Category    categories.CategoryManager
-	Receipt     receipt.ReceiptManager
+	Auth           auth.AuthManager
+	User           user.UserManager
+	Chat           chat.ChatManager
+	LogMessage     log_message.LogMessageManager
+	Transaction    transaction.TransactionManager
+	Category       categories.CategoryManager
+	Receipt        receipt.ReceiptManager
+	Review         review.ReviewManager
+	Search         search.SearchManager
+	Analytics      analytics.AnalyticsManager
+	Recurring      recurring.RecurringManager
+	Recommendation recommendation.RecommendationManager
+	Subscription   subscription.SubscriptionManager
+	Anomaly        anomaly.AnomalyManager
+	Forecast       forecast.ForecastManager
+	Account        account.AccountManager
+	Transfer       transfer.TransferManager
+	Tag            tag.TagManager
+	Import         transaction_import.TransactionImportManager
+	Report         report.ReportManager
+	Currency       currency.CurrencyManager
+	PromptRegistry prompt_registry.PromptRegistryManager
+	Usage          usage.UsageManager
 }
 
 type Controllers struct {
-	Auth        auth.AuthController
-	User        user.UserController
-	Chat        chat.ChatController
-	Transaction transaction.TransactionController
-	Category    categories.CategoryController
-	Receipt     receipt.ReceiptController
+	Auth           auth.AuthController
+	User           user.UserController
+	Chat           chat.ChatController
+	Transaction    transaction.TransactionController
+	Category       categories.CategoryController
+	Receipt        receipt.ReceiptController
+	Review         review.ReviewController
+	Search         search.SearchController
+	Analytics      analytics.AnalyticsController
+	Recurring      recurring.RecurringController
+	Recommendation recommendation.RecommendationController
+	Subscription   subscription.SubscriptionController
+	Anomaly        anomaly.AnomalyController
+	Forecast       forecast.ForecastController
+	Account        account.AccountController
+	Transfer       transfer.TransferController
+	Tag            tag.TagController
+	Import         transaction_import.TransactionImportController
+	Report         report.ReportController
+	Currency       currency.CurrencyController
+	PromptRegistry prompt_registry.PromptRegistryController
+	Usage          usage.UsageController
 }
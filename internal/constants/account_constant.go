@@ -0,0 +1,11 @@
+package constants
+
+type AccountType string
+
+const (
+	AccountTypeCash       AccountType = "cash"
+	AccountTypeBank       AccountType = "bank"
+	AccountTypeEWallet    AccountType = "e_wallet"
+	AccountTypeCreditCard AccountType = "credit_card"
+	AccountTypeOther      AccountType = "other"
+)
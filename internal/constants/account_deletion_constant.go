@@ -0,0 +1,38 @@
+package constants
+
+import "time"
+
+const (
+	// AccountDeletionGracePeriod is how long a deletion request can still be cancelled
+	AccountDeletionGracePeriod = 30 * 24 * time.Hour
+	ReceiptBucket              = "receipts"
+	UserDataExportContentType  = "application/zip"
+)
+
+// UserDataTables are exported as data/<table>.json in the personal data archive, in this order
+var UserDataTables = []string{
+	"users",
+	"accounts",
+	"account_reconciliations",
+	"transfers",
+	"transactions",
+	"transaction_splits",
+	"transaction_tags",
+	"tags",
+	"transaction_imports",
+	"transaction_anomalies",
+	"recurring_rules",
+	"recurring_occurrences",
+	"receipts",
+	"receipt_items",
+	"receipt_tags",
+	"budgets",
+	"financial_goals",
+	"chat_sessions",
+	"chat_messages",
+	"log_messages",
+	"insights",
+	"ai_summaries",
+	"ai_recommendations",
+	"monthly_reports",
+}
@@ -0,0 +1,37 @@
+package constants
+
+import "time"
+
+type AnomalyType string
+
+const (
+	AnomalyTypeCategoryAmount AnomalyType = "category_amount" // Amount far above the user's norm for the category
+	AnomalyTypeMerchantAmount AnomalyType = "merchant_amount" // Amount far above the user's norm for the same merchant
+	AnomalyTypeUnusualTime    AnomalyType = "unusual_time"    // Made at an hour the user rarely spends
+	AnomalyTypeNewMerchant    AnomalyType = "new_merchant"    // First charge from a merchant and it is large
+	AnomalyTypeCategorySpike  AnomalyType = "category_spike"  // Month-to-date category spending well above the usual month
+)
+
+const (
+	AnomalyLookbackDays          = 180  // History used as the user's norm
+	AnomalyNewMerchantDays       = 365  // A merchant is new when it has no similar charge in this window
+	AnomalyMinSamples            = 5    // Minimum history before amount outliers are judged
+	AnomalyMinHourSamples        = 20   // Minimum timed expenses before unusual hours are judged
+	AnomalyRobustZThreshold      = 3.5  // Modified z-score (Iglewicz and Hoaglin) above which an amount is an outlier
+	AnomalyMinAmountRatio        = 2.0  // An outlier must also be at least this multiple of the median
+	AnomalyHighPriorityRatio     = 5.0  // Ratio to the median from which the warning is high priority
+	AnomalyMerchantSimilarity    = 0.85 // Description embedding similarity treated as the same merchant
+	AnomalyNewMerchantPercentile = 90   // A new merchant charge is large above this percentile of expenses
+	AnomalyUnusualHourShare      = 0.02 // Share of expenses in the surrounding hours below which the time is unusual
+	AnomalySpikeBaselineMonths   = 3
+	AnomalySpikeMinActiveMonths  = 2   // Baseline months that must have spending in the category
+	AnomalySpikeRatio            = 1.5 // Month-to-date spending over the average month that counts as a spike
+	AnomalyScanDefaultDays       = 30  // Batch scan window when no range is given
+	AnomalyRecommendationTTLDays = 14
+
+	AnomalyCheckWorkers   = 2   // Goroutines checking newly inserted transactions
+	AnomalyCheckQueueSize = 256 // Checks waiting for a worker, more are left to the scheduled scan when it runs
+)
+
+// AnomalyCheckQueueWait is how long a check waits for room in a full queue when the scheduled scan is disabled
+const AnomalyCheckQueueWait = 5 * time.Second
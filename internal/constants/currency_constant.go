@@ -0,0 +1,4 @@
+package constants
+
+// DefaultCurrencyCode is the base currency of new users, see users.base_currency
+const DefaultCurrencyCode = "IDR"
@@ -0,0 +1,30 @@
+package constants
+
+// TransactionExportColumns is the column order of transaction exports. It is part of the API, new
+// columns are only appended at the end.
+var TransactionExportColumns = []string{
+	"transaction_id",
+	"transaction_date",
+	"type",
+	"amount",
+	"discount",
+	"description",
+	"category_id",
+	"category_name",
+	"splits",
+	"payment_method",
+	"source",
+	"account_id",
+	"account_name",
+	"tags",
+	"receipt_id",
+	"receipt_url",
+	"transfer_id",
+	"confirmed",
+	"created_at",
+	"currency_code",
+	"original_amount",
+}
+
+// ReceiptDetailPath is the API path of a receipt, used as the receipt link in exports
+const ReceiptDetailPath = "/api/v1/receipts/detail/user/"
@@ -0,0 +1,9 @@
+package constants
+
+const (
+	ForecastHistoryDays      = 90 // History used to estimate day-to-day spending
+	ForecastDefaultMonths    = 1  // The current month
+	ForecastMaxMonths        = 3
+	ForecastConfidenceLevel  = 0.8    // Share of outcomes expected inside low-high
+	ForecastConfidenceZScore = 1.2816 // Normal quantile for ForecastConfidenceLevel
+)
@@ -0,0 +1,26 @@
+package constants
+
+type ImportStatus string
+
+const (
+	ImportStatusPending   ImportStatus = "pending"   // Uploaded, can be validated and committed
+	ImportStatusCommitted ImportStatus = "committed" // Transactions were inserted, can be reverted
+	ImportStatusReverted  ImportStatus = "reverted"  // Transactions were deleted again
+)
+
+type ImportRowStatus string
+
+const (
+	ImportRowValid     ImportRowStatus = "valid"
+	ImportRowDuplicate ImportRowStatus = "duplicate" // Matches an existing transaction and is skipped on commit
+	ImportRowInvalid   ImportRowStatus = "invalid"
+)
+
+const (
+	ImportSource             = "import" // transactions.source when the file has no merchant column
+	ImportMaxFileSize        = 5 * 1024 * 1024
+	ImportMaxRows            = 5000
+	ImportSampleRows         = 10  // Raw rows returned after upload to help build the mapping
+	ImportPreviewValidRows   = 20  // Valid rows echoed by validation, invalid and duplicate rows are always listed
+	ImportEmbeddingBatchSize = 100 // Descriptions per embedding request on commit
+)
@@ -0,0 +1,51 @@
+package constants
+
+// Topics of the log_messages rows, one for every place that calls an LLM or creates an embedding
+const (
+	TopicChat                    = "chat"                     // ask mode reply
+	TopicAgentChat               = "agent_chat"               // agent mode reply
+	TopicChatEmbedding           = "chat_embedding"           // chat messages and the queries of the knowledge retrieval
+	TopicReceiptExtraction       = "receipt_extraction"       // receipt image read by Gemini
+	TopicReceiptEmbedding        = "receipt_embedding"        // merchant of a saved receipt
+	TopicReceiptItemEmbedding    = "receipt_item_embedding"   // item names of a saved receipt
+	TopicTransactionEmbedding    = "transaction_embedding"    // description of a created or edited transaction
+	TopicCategoryConfidence      = "category_confidence"      // confidence score of an auto categorization
+	TopicTransactionFilter       = "transaction_filter"       // natural-language transaction filter
+	TopicImportEmbedding         = "import_embedding"         // descriptions of imported transactions
+	TopicTransferEmbedding       = "transfer_embedding"       // descriptions of the two legs of a transfer
+	TopicCategoryEmbedding       = "category_embedding"       // category names, not tied to a user
+	TopicSearchEmbedding         = "search_embedding"         // semantic search query
+	TopicRecommendationEmbedding = "recommendation_embedding" // saved AI recommendation
+	TopicTagSuggestion           = "tag_suggestion"
+	TopicMonthlyReport           = "monthly_report" // narrative of the monthly report
+)
+
+// ModelPrice is the USD list price of a million tokens
+type ModelPrice struct {
+	Input  float64
+	Output float64
+}
+
+// ModelPrices are the prices of the models the app calls. A dated model like gpt-4o-mini-2024-07-18 uses
+// the price of the longest name it starts with, calls of a model missing here cost 0.
+var ModelPrices = map[string]ModelPrice{
+	"gemini-2.5-flash":       {Input: 0.30, Output: 2.50},
+	"gemini-2.5-flash-lite":  {Input: 0.10, Output: 0.40},
+	"gemini-2.5-pro":         {Input: 1.25, Output: 10.00},
+	"gpt-4o":                 {Input: 2.50, Output: 10.00},
+	"gpt-4o-mini":            {Input: 0.15, Output: 0.60},
+	"text-embedding-3-small": {Input: 0.02},
+}
+
+// Groupings of the usage endpoints
+const (
+	UsageGroupByUser  = "user"
+	UsageGroupByTopic = "topic"
+	UsageGroupByModel = "model"
+	UsageGroupByDay   = "day"
+)
+
+const (
+	UsageDefaultDays  = 30  // period of the usage endpoints without from
+	UsageDefaultLimit = 100 // groups returned without limit
+)
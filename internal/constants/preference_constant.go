@@ -0,0 +1,39 @@
+package constants
+
+import "github.com/saufiroja/fin-ai/internal/i18n"
+
+// Languages the AI can reply in
+const (
+	LanguageIndonesian = i18n.Indonesian
+	LanguageEnglish    = i18n.English
+)
+
+// Tones of the AI replies
+const (
+	ToneFriendly     = "friendly"
+	ToneProfessional = "professional"
+	ToneConcise      = "concise"
+)
+
+// Preferences of a user who has not changed them
+const (
+	DefaultLocale    = "id-ID"
+	DefaultTimezone  = "Asia/Jakarta"
+	DefaultLanguage  = "" // follow the Accept-Language of the request, i18n.Default without one
+	DefaultTone      = ToneFriendly
+	DefaultChatModel = "gemini-2.5-flash"
+)
+
+// ChatModels are the Gemini models a user can choose for chat
+var ChatModels = []string{
+	"gemini-2.5-flash",
+	"gemini-2.5-flash-lite",
+	"gemini-2.5-pro",
+}
+
+// Keys of the notification settings in users.ai_preferences
+const (
+	NotificationMonthlyReport      = "monthly_report"
+	NotificationAnomalyAlerts      = "anomaly_alerts"
+	NotificationSubscriptionAlerts = "subscription_alerts"
+)
@@ -0,0 +1,124 @@
+package prompt
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/saufiroja/fin-ai/internal/i18n"
+)
+
+// Key names a prompt that is translated in every supported locale
+type Key string
+
+const (
+	// ChatSystem is the system prompt of the ask mode
+	ChatSystem Key = "chat.system"
+	// ChatAgentSystem is the system prompt of the agent mode
+	ChatAgentSystem Key = "chat.agent_system"
+
+	// Preferences is appended to system prompts. Placeholders: the language name, the tone instruction,
+	// the currency code, the decimal places of the currency, an example amount, the user's local time
+	Preferences Key = "preferences"
+
+	// ReceiptExtractionSystem explains the receipt extraction task
+	ReceiptExtractionSystem Key = "receipt.extraction_system"
+	// ReceiptExtractionUser asks for the receipt JSON. Placeholders: the available categories
+	ReceiptExtractionUser Key = "receipt.extraction_user"
+	// ReceiptPreferences is appended to the receipt prompt.
+	// Placeholders: the currency code, its decimal places, the user's time zone
+	ReceiptPreferences Key = "receipt.preferences"
+
+	// MonthlySummarySystem is the system prompt for the narrative of the monthly report
+	MonthlySummarySystem Key = "report.monthly_summary_system"
+	// MonthlySummaryUser carries the report figures. Placeholders: the period, the report data as JSON
+	MonthlySummaryUser Key = "report.monthly_summary_user"
+)
+
+// LanguageKey names a reply language in the Preferences prompt
+func LanguageKey(language string) Key {
+	return Key("language." + language)
+}
+
+// ToneKey describes a reply tone in the Preferences prompt
+func ToneKey(tone string) Key {
+	return Key("tone." + tone)
+}
+
+// Keys lists every prompt a locale must translate
+var Keys = []Key{
+	ChatSystem,
+	ChatAgentSystem,
+	Preferences,
+	LanguageKey(i18n.English),
+	LanguageKey(i18n.Indonesian),
+	ToneKey("friendly"),
+	ToneKey("professional"),
+	ToneKey("concise"),
+	ReceiptExtractionSystem,
+	ReceiptExtractionUser,
+	ReceiptPreferences,
+	MonthlySummarySystem,
+	MonthlySummaryUser,
+}
+
+var catalog = map[string]map[Key]string{
+	i18n.English:    english,
+	i18n.Indonesian: indonesian,
+}
+
+// Get returns the prompt in the locale, a locale without the prompt falls back to the default locale and
+// then to English
+func Get(locale string, key Key) string {
+	if prompt, ok := catalog[i18n.Match(i18n.Default, locale)][key]; ok {
+		return prompt
+	}
+	if prompt, ok := catalog[i18n.Default][key]; ok {
+		return prompt
+	}
+	return catalog[i18n.English][key]
+}
+
+var verbPattern = regexp.MustCompile(`%[-+# 0]*[0-9]*(?:\.[0-9]+)?[a-zA-Z]`)
+
+// IsKey reports whether the key names a prompt of the catalog
+func IsKey(key string) bool {
+	return slices.Contains(Keys, Key(key))
+}
+
+// CheckPlaceholders reports an error when a text of the prompt does not have the placeholders of the English one
+func CheckPlaceholders(key Key, text string) error {
+	want := verbPattern.FindAllString(strings.ReplaceAll(catalog[i18n.English][key], "%%", ""), -1)
+	got := verbPattern.FindAllString(strings.ReplaceAll(text, "%%", ""), -1)
+	if strings.Join(want, " ") != strings.Join(got, " ") {
+		return fmt.Errorf("%s has placeholders %v, want %v", key, got, want)
+	}
+	return nil
+}
+
+// Validate checks that every supported locale translates every prompt with the same placeholders as English
+func Validate() error {
+	var problems []string
+	for _, locale := range i18n.Supported {
+		prompts, ok := catalog[locale]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("locale %s has no prompts", locale))
+			continue
+		}
+		for _, key := range Keys {
+			prompt, ok := prompts[key]
+			if !ok || strings.TrimSpace(prompt) == "" {
+				problems = append(problems, fmt.Sprintf("%s is missing in locale %s", key, locale))
+				continue
+			}
+			if err := CheckPlaceholders(key, prompt); err != nil {
+				problems = append(problems, fmt.Sprintf("locale %s: %v", locale, err))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid prompt catalog: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package prompt
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/saufiroja/fin-ai/internal/i18n"
+)
+
+func TestCatalogTranslatesEveryKey(t *testing.T) {
+	for _, locale := range i18n.Supported {
+		prompts, ok := catalog[locale]
+		if !ok {
+			t.Errorf("locale %s has no prompts", locale)
+			continue
+		}
+		for _, key := range Keys {
+			text, ok := prompts[key]
+			if !ok {
+				t.Errorf("%s is missing in locale %s", key, locale)
+				continue
+			}
+			if strings.TrimSpace(text) == "" {
+				t.Errorf("%s is empty in locale %s", key, locale)
+			}
+			if err := CheckPlaceholders(key, text); err != nil {
+				t.Errorf("locale %s: %v", locale, err)
+			}
+		}
+		for key := range prompts {
+			if !IsKey(string(key)) {
+				t.Errorf("locale %s translates %s which is not listed in Keys", locale, key)
+			}
+		}
+	}
+
+	if err := Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestGetFallsBackToDefaultLocale(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   string
+	}{
+		{i18n.English, english[ChatSystem]},
+		{i18n.Indonesian, indonesian[ChatSystem]},
+		{"en-GB", english[ChatSystem]},
+		{"fr", catalog[i18n.Default][ChatSystem]},
+		{"", catalog[i18n.Default][ChatSystem]},
+	}
+
+	for _, tt := range tests {
+		if got := Get(tt.locale, ChatSystem); got != tt.want {
+			t.Errorf("Get(%q) returned the prompt of another locale", tt.locale)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package prompt
+
+const (
+	// TagSuggestionSystemPromptTemplate is the system prompt for suggesting tags for a transaction description.
+	// Placeholders: the user's existing tags
+	TagSuggestionSystemPromptTemplate = `You suggest short tags for a personal finance transaction in Indonesia.
+Tags capture context that categories miss: trips and events ("trip-bali-2026"), reimbursable work expenses ("reimbursable"), costs shared with someone ("shared-with-partner"), projects or gifts.
+The user's existing tags: %s.
+Rules:
+- Prefer existing tags whenever they fit; only invent a new tag when none of them fit and the description clearly implies the context.
+- Tags are lowercase, use dashes instead of spaces and are at most 50 characters.
+- Do not repeat the spending category itself (e.g. "food", "transport").
+- Return at most 5 tags, or an empty list when nothing fits.`
+)
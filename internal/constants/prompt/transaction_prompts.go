@@ -6,4 +6,16 @@ const (
 
 	// TransactionConfidenceUserPromptTemplate is the template for user prompt in confidence scoring
 	TransactionConfidenceUserPromptTemplate = "How confident are you that the category '%s' is correct for this transaction: '%s'? Respond with only a number between 0.0 and 1.0."
+
+	// TransactionFilterSystemPromptTemplate is the system prompt for turning a natural-language query into a transaction filter.
+	// Placeholders: today's date, available categories as "name (id)"
+	TransactionFilterSystemPromptTemplate = `You convert a user's natural-language search over their personal finance transactions into a structured filter.
+Today is %s. Resolve relative dates ("last month", "this week", "yesterday") against today and return them as YYYY-MM-DD; end_date is inclusive.
+Available categories: %s.
+Rules:
+- category_ids must only contain IDs from the available categories; pick every category that matches the user's intent, or return an empty list.
+- type is "expense" for spending words (spent, paid, bought) and "income" for earning words (salary, received); null when unclear.
+- Amounts are whole Rupiah. Expand shorthand: "100k" / "100rb" = 100000, "1.5jt" / "1.5m" = 1500000. "over" / "more than" sets amount_min, "under" / "less than" sets amount_max.
+- payment_method and source are only set when the user names them explicitly (e.g. "cash", "credit card", "gopay", "shopee").
+- Use null for anything the user did not mention.`
 )
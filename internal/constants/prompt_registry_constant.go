@@ -0,0 +1,15 @@
+package constants
+
+import "time"
+
+// PromptVersionBuiltin is the version of the prompts compiled into internal/constants/prompt
+const PromptVersionBuiltin = "builtin"
+
+// Where a prompt version was recorded from, see prompt_versions.source
+const (
+	PromptSourceAPI  = "api"
+	PromptSourceFile = "file"
+)
+
+// PromptCacheTTL is how long the prompt registry serves versions and experiments before reading them again
+const PromptCacheTTL = time.Minute
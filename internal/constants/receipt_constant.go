@@ -0,0 +1,7 @@
+package constants
+
+// ReceiptExtractionModel is the Gemini model that reads receipt images
+const ReceiptExtractionModel = "gemini-2.5-flash"
+
+// ReceiptItemEmbeddingBatch is how many item names the backfill embeds per round
+const ReceiptItemEmbeddingBatch = 200
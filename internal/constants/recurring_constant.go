@@ -0,0 +1,8 @@
+package constants
+
+const (
+	RecurringSource            = "recurring" // transactions.source for materialized recurring rules
+	RecurringUpcomingDays      = 30          // Default window for the upcoming bills endpoint
+	RecurringMaxUpcomingDays   = 365
+	RecurringMaxCatchUpPerRule = 366 // Oldest due occurrences recorded per rule and run, the rest waits for the next run
+)
@@ -0,0 +1,10 @@
+package constants
+
+import "time"
+
+const (
+	ReportBucket       = "reports"
+	ReportLinkExpiry   = 24 * time.Hour // Lifetime of the presigned download link
+	ReportTopMerchants = 5
+	ReportContentType  = "application/pdf"
+)
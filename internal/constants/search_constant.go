@@ -0,0 +1,7 @@
+package constants
+
+const (
+	SearchCandidateLimit      = 50  // Candidates pulled from each ranker before fusion
+	SearchRRFK                = 60  // Reciprocal rank fusion constant, dampens the weight of top ranks
+	SearchSimilarityThreshold = 0.3 // Minimum cosine similarity for a semantic candidate
+)
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/saufiroja/fin-ai/internal/constants/prompt"
+	"github.com/saufiroja/fin-ai/internal/i18n"
 )
 
 const (
@@ -16,6 +17,14 @@ const (
 	TitleTimeout         = 30 * time.Second
 )
 
+const (
+	RAGTransactionTopK     = 15  // Maximum relevant transactions pulled into the chat context
+	RAGReceiptTopK         = 10  // Maximum relevant receipts pulled into the chat context
+	RAGReceiptItemTopK     = 15  // Maximum relevant receipt items pulled into the chat context
+	RAGSimilarityThreshold = 0.5 // Minimum cosine similarity for an item to be considered relevant
+	RAGHNSWEfSearch        = 100 // hnsw.ef_search used for scoped vector search
+)
+
 // SystemPrompt is the main system prompt for chat - moved to prompt package
 // Keeping this for backward compatibility
-var SystemPrompt = prompt.ChatSystemPrompt
+var SystemPrompt = prompt.Get(i18n.Default, prompt.ChatSystem)
@@ -0,0 +1,15 @@
+package constants
+
+const (
+	SubscriptionLookbackDays          = 400  // Enough history to see a yearly charge twice
+	SubscriptionMaxCharges            = 5000 // Upper bound on expenses loaded per user
+	SubscriptionMinOccurrences        = 3    // Weekly and monthly charges
+	SubscriptionMinYearlyOccurrences  = 2
+	SubscriptionSimilarityThreshold   = 0.85 // Cosine similarity to merge description variants of one merchant
+	SubscriptionAmountTolerance       = 0.25 // Max relative change between consecutive charges, besides the price steps
+	SubscriptionMaxPriceSteps         = 1    // Changes beyond the tolerance kept as a new price, e.g. a plan upgrade
+	SubscriptionMinRegularity         = 0.75 // Share of intervals that must match the detected frequency
+	SubscriptionPriceChangeThreshold  = 0.03 // Relative change treated as a price change rather than noise
+	SubscriptionPriceIncreaseDays     = 90   // Only recent price increases become recommendations
+	SubscriptionRecommendationTTLDays = 30
+)
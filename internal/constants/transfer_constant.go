@@ -0,0 +1,5 @@
+package constants
+
+const (
+	TransferSource = "transfer" // transactions.source and payment_method of transfer legs
+)
@@ -5,6 +5,9 @@ type TypeCategory string
 const (
 	IncomeCategory  TypeCategory = "income"
 	ExpenseCategory TypeCategory = "expense"
+	// Transfer legs are transaction types only, they never count as income or expense
+	TransferInType  TypeCategory = "transfer_in"
+	TransferOutType TypeCategory = "transfer_out"
 )
 
 type PeriodType string
@@ -31,3 +34,20 @@ const (
 	RecommendationTypeSavingTips      RecommendationType = "saving tip"
 	RecommendationTypeSpendingWarning RecommendationType = "spending warning"
 )
+
+type SearchResultType string
+
+const (
+	SearchResultTransaction SearchResultType = "transaction"
+	SearchResultReceipt     SearchResultType = "receipt"
+	SearchResultReceiptItem SearchResultType = "receipt_item"
+)
+
+type OccurrenceStatus string
+
+const (
+	OccurrenceStatusSkipped      OccurrenceStatus = "skipped"
+	OccurrenceStatusOverridden   OccurrenceStatus = "overridden"
+	OccurrenceStatusMaterialized OccurrenceStatus = "materialized"
+	OccurrenceStatusPending      OccurrenceStatus = "pending" // claimed by the scheduler, the transaction is being inserted
+)
@@ -0,0 +1,32 @@
+package requests
+
+import "github.com/saufiroja/fin-ai/internal/constants"
+
+type AccountRequest struct {
+	Name           string                `json:"name" validate:"required,max=100"`
+	Type           constants.AccountType `json:"type" validate:"required,oneof=cash bank e_wallet credit_card other"`
+	Institution    string                `json:"institution" validate:"omitempty,max=100"`
+	OpeningBalance int64                 `json:"opening_balance"`
+	OpeningDate    string                `json:"opening_date" validate:"omitempty,datetime=2006-01-02"`
+	IsArchived     *bool                 `json:"is_archived"`
+	// CurrencyCode defaults to the user's base currency, an update without it keeps the current one
+	CurrencyCode string `json:"currency_code" validate:"omitempty,len=3"`
+}
+
+type AccountQuery struct {
+	IncludeArchived bool `query:"include_archived"`
+}
+
+type AccountLedgerQuery struct {
+	StartDate string `query:"start_date" validate:"omitempty,datetime=2006-01-02"`
+	EndDate   string `query:"end_date" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type AssignAccountTransactionsRequest struct {
+	TransactionIds []string `json:"transaction_ids" validate:"required,min=1,max=500,dive,required"`
+}
+
+type ReconcileAccountRequest struct {
+	StatementDate    string `json:"statement_date" validate:"required,datetime=2006-01-02"`
+	StatementBalance *int64 `json:"statement_balance" validate:"required"`
+}
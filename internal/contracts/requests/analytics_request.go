@@ -0,0 +1,10 @@
+package requests
+
+type AnalyticsQuery struct {
+	StartDate  string `query:"start_date" validate:"omitempty,datetime=2006-01-02"`
+	EndDate    string `query:"end_date" validate:"omitempty,datetime=2006-01-02"`
+	CategoryId string `query:"category_id" validate:"omitempty"`
+	Interval   string `query:"interval" validate:"omitempty,oneof=daily weekly monthly"`
+	Type       string `query:"type" validate:"omitempty,oneof=income expense"`
+	Limit      int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
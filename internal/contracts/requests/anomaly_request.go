@@ -0,0 +1,16 @@
+package requests
+
+import "github.com/saufiroja/fin-ai/internal/constants"
+
+type AnomalyQuery struct {
+	Limit     int                   `query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset    int                   `query:"offset" validate:"omitempty,min=0"`
+	Type      constants.AnomalyType `query:"type" validate:"omitempty,oneof=category_amount merchant_amount unusual_time new_merchant category_spike"`
+	StartDate string                `query:"start_date" validate:"omitempty,datetime=2006-01-02"`
+	EndDate   string                `query:"end_date" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type AnomalyScanRequest struct {
+	StartDate string `json:"start_date" validate:"omitempty,datetime=2006-01-02"`
+	EndDate   string `json:"end_date" validate:"omitempty,datetime=2006-01-02"`
+}
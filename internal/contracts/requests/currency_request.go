@@ -0,0 +1,23 @@
+package requests
+
+type BaseCurrencyRequest struct {
+	CurrencyCode string `json:"currency_code" validate:"required,len=3"`
+}
+
+type ExchangeRateQuery struct {
+	Base  string `query:"base" validate:"required,len=3"`
+	Quote string `query:"quote" validate:"required,len=3"`
+	Date  string `query:"date" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type UpsertExchangeRatesRequest struct {
+	Rates []ExchangeRateRequest `json:"rates" validate:"required,min=1,max=500,dive"`
+}
+
+// ExchangeRateRequest is the price of one unit of Base in Quote, valid from Date
+type ExchangeRateRequest struct {
+	Base  string  `json:"base" validate:"required,len=3"`
+	Quote string  `json:"quote" validate:"required,len=3"`
+	Date  string  `json:"date" validate:"required,datetime=2006-01-02"`
+	Rate  float64 `json:"rate" validate:"required,gt=0"`
+}
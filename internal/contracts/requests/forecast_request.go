@@ -0,0 +1,7 @@
+package requests
+
+type ForecastQuery struct {
+	Months int `query:"months" validate:"omitempty,min=1,max=3"`
+	// CurrentBalance overrides the balance computed from all recorded transactions
+	CurrentBalance string `query:"current_balance" validate:"omitempty,numeric"`
+}
@@ -0,0 +1,23 @@
+package requests
+
+// CreatePromptVersionRequest records a new version of a prompt, the same version can be sent once per locale
+type CreatePromptVersionRequest struct {
+	PromptKey   string `json:"prompt_key" validate:"required,max=100"`
+	Version     string `json:"version" validate:"required,max=50"`
+	Locale      string `json:"locale" validate:"required,oneof=id en"`
+	Content     string `json:"content" validate:"required"`
+	Description string `json:"description"`
+}
+
+// ActivatePromptVersionRequest picks the version served outside experiments, "builtin" goes back to the
+// prompt compiled into the binary
+type ActivatePromptVersionRequest struct {
+	Version string `json:"version" validate:"required,max=50"`
+}
+
+type StartPromptExperimentRequest struct {
+	PromptKey        string `json:"prompt_key" validate:"required,max=100"`
+	ControlVersion   string `json:"control_version" validate:"required,max=50"`
+	CandidateVersion string `json:"candidate_version" validate:"required,max=50,nefield=ControlVersion"`
+	CandidatePercent int    `json:"candidate_percent" validate:"required,min=1,max=99"`
+}
@@ -0,0 +1,10 @@
+package requests
+
+import "github.com/saufiroja/fin-ai/internal/constants"
+
+type RecommendationQuery struct {
+	Limit      int                          `query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset     int                          `query:"offset" validate:"omitempty,min=0"`
+	Type       constants.RecommendationType `query:"type" validate:"omitempty,oneof='budget alert' 'saving tip' 'spending warning'"`
+	UnreadOnly bool                         `query:"unread_only"`
+}
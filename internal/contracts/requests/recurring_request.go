@@ -0,0 +1,26 @@
+package requests
+
+import "github.com/saufiroja/fin-ai/internal/constants"
+
+type RecurringRuleRequest struct {
+	CategoryId    string                 `json:"category_id" validate:"required"`
+	Type          constants.TypeCategory `json:"type" validate:"required,oneof=income expense"`
+	Description   string                 `json:"description" validate:"required,max=255"`
+	Amount        int64                  `json:"amount" validate:"required,min=1"`
+	PaymentMethod string                 `json:"payment_method" validate:"omitempty,max=255"`
+	Frequency     constants.PeriodType   `json:"frequency" validate:"required,oneof=daily weekly monthly yearly"`
+	DayOfMonth    *int                   `json:"day_of_month" validate:"omitempty,min=1,max=31"`
+	StartDate     string                 `json:"start_date" validate:"required,datetime=2006-01-02"`
+	EndDate       string                 `json:"end_date" validate:"omitempty,datetime=2006-01-02"`
+	IsActive      *bool                  `json:"is_active"`
+}
+
+type RecurringOccurrenceRequest struct {
+	Date        string `json:"date" validate:"required,datetime=2006-01-02"`
+	Amount      int64  `json:"amount" validate:"omitempty,min=1"`
+	Description string `json:"description" validate:"omitempty,max=255"`
+}
+
+type UpcomingBillsQuery struct {
+	Days int `query:"days" validate:"omitempty,min=1,max=365"`
+}
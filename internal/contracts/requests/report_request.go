@@ -0,0 +1,14 @@
+package requests
+
+type GenerateReportRequest struct {
+	Year  int `json:"year" validate:"required,min=2000,max=2100"`
+	Month int `json:"month" validate:"required,min=1,max=12"`
+	// RefreshSummary writes a new AI narrative instead of reusing the stored one
+	RefreshSummary bool `json:"refresh_summary"`
+}
+
+type ReportQuery struct {
+	Limit  int `query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset int `query:"offset" validate:"omitempty,min=0"`
+	Year   int `query:"year" validate:"omitempty,min=2000,max=2100"`
+}
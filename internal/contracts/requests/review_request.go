@@ -0,0 +1,14 @@
+package requests
+
+type ReviewQueueQuery struct {
+	Limit     int     `query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset    int     `query:"offset" validate:"omitempty,min=0"`
+	Threshold float64 `query:"threshold" validate:"omitempty,gt=0,lte=1"`
+	Type      string  `query:"type" validate:"omitempty,oneof=transactions receipt_items"`
+}
+
+type ReviewActionRequest struct {
+	TransactionIds []string `json:"transaction_ids" validate:"omitempty,dive,required"`
+	ReceiptItemIds []string `json:"receipt_item_ids" validate:"omitempty,dive,required"`
+	CategoryId     string   `json:"category_id"`
+}
@@ -0,0 +1,11 @@
+package requests
+
+type SearchQuery struct {
+	Query      string `query:"q" validate:"required"`
+	Limit      int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset     int    `query:"offset" validate:"omitempty,min=0"`
+	CategoryId string `query:"category_id" validate:"omitempty"`
+	StartDate  string `query:"start_date" validate:"omitempty,datetime=2006-01-02"`
+	EndDate    string `query:"end_date" validate:"omitempty,datetime=2006-01-02"`
+	Type       string `query:"type" validate:"omitempty,oneof=transaction receipt receipt_item"`
+}
@@ -0,0 +1,14 @@
+package requests
+
+type TagRequest struct {
+	Name string `json:"name" validate:"required,max=50"`
+}
+
+// AssignTagsRequest replaces every tag of a transaction or receipt, unknown names are created on the fly
+type AssignTagsRequest struct {
+	Tags []string `json:"tags" validate:"max=20"`
+}
+
+type TagSuggestionRequest struct {
+	Description string `json:"description" validate:"required,max=255"`
+}
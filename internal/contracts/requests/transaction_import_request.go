@@ -0,0 +1,25 @@
+package requests
+
+import "github.com/saufiroja/fin-ai/pkg/importer"
+
+type ImportMappingRequest struct {
+	Mapping importer.Mapping `json:"mapping"`
+	// DateFormat is a Go layout such as "01/02/2006", common day-first formats are detected when empty
+	DateFormat string `json:"date_format" validate:"omitempty,max=50"`
+	AccountId  string `json:"account_id" validate:"omitempty"`
+	// Default categories are used for rows without a recognized category
+	DefaultExpenseCategoryId string `json:"default_expense_category_id" validate:"omitempty"`
+	DefaultIncomeCategoryId  string `json:"default_income_category_id" validate:"omitempty"`
+}
+
+type CommitImportRequest struct {
+	ImportMappingRequest
+	// SkipInvalid commits the valid rows when some rows fail validation, otherwise the commit is refused
+	SkipInvalid bool `json:"skip_invalid"`
+}
+
+type ImportQuery struct {
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset int    `query:"offset" validate:"omitempty,min=0"`
+	Status string `query:"status" validate:"omitempty"`
+}
@@ -22,6 +22,13 @@ type TransactionRequest struct {
 	Confirmed            bool                   `json:"confirmed"`
 	Discount             int64                  `json:"discount" validate:"omitempty,min=0"`
 	PaymentMethod        string                 `json:"payment_method"`
+	AccountId            string                 `json:"account_id"`
+	ReceiptId            string                 `json:"-"`
+	// Splits spreads the amount over several categories, the lines must add up to the amount
+	Splits []TransactionSplitRequest `json:"splits" validate:"omitempty,dive"`
+	// CurrencyCode is the currency of amount, discount and splits. It defaults to the currency of the
+	// account, or the user's base currency without an account.
+	CurrencyCode string `json:"currency_code" validate:"omitempty,len=3"`
 }
 
 type UpdateTransactionRequest struct {
@@ -38,6 +45,17 @@ type UpdateTransactionRequest struct {
 	Confirmed            bool                   `json:"confirmed" validate:"omitempty"`
 	Discount             int64                  `json:"discount" validate:"omitempty,min=0"`
 	PaymentMethod        string                 `json:"payment_method" validate:"omitempty,max=255"`
+	AccountId            string                 `json:"account_id" validate:"omitempty"`
+	// Splits replaces the split lines when present, an empty list removes them
+	Splits []TransactionSplitRequest `json:"splits" validate:"omitempty,dive"`
+	// CurrencyCode defaults to the current currency of the transaction
+	CurrencyCode string `json:"currency_code" validate:"omitempty,len=3"`
+}
+
+type TransactionSplitRequest struct {
+	CategoryId string `json:"category_id" validate:"required"`
+	Amount     int64  `json:"amount" validate:"required,min=1"`
+	Note       string `json:"note" validate:"omitempty,max=255"`
 }
 
 type GetAllTransactionsQuery struct {
@@ -47,6 +65,20 @@ type GetAllTransactionsQuery struct {
 	Search     string `query:"search" validate:"omitempty"`
 	StartDate  string `query:"start_date" validate:"omitempty,datetime=2006-01-02"`
 	EndDate    string `query:"end_date" validate:"omitempty,datetime=2006-01-02"`
+	AccountId  string `query:"account_id" validate:"omitempty"`
+	// Tags only keeps transactions carrying every listed tag, repeat the parameter for several tags
+	Tags []string `query:"tags" validate:"omitempty"`
+}
+
+// ExportTransactionsQuery takes the filters of GetAllTransactionsQuery, every matching transaction is exported
+type ExportTransactionsQuery struct {
+	Format     string   `query:"format" validate:"omitempty,oneof=csv xlsx json"`
+	CategoryId string   `query:"category_id" validate:"omitempty"`
+	Search     string   `query:"search" validate:"omitempty"`
+	StartDate  string   `query:"start_date" validate:"omitempty,datetime=2006-01-02"`
+	EndDate    string   `query:"end_date" validate:"omitempty,datetime=2006-01-02"`
+	AccountId  string   `query:"account_id" validate:"omitempty"`
+	Tags       []string `query:"tags" validate:"omitempty"`
 }
 
 type OverviewTransactionsQuery struct {
@@ -54,3 +86,22 @@ type OverviewTransactionsQuery struct {
 	EndDate    string `query:"end_date" validate:"omitempty,datetime=2006-01-02"`
 	CategoryId string `query:"category_id" validate:"omitempty"`
 }
+
+type TransactionFilter struct {
+	StartDate     string   `json:"start_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	EndDate       string   `json:"end_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	CategoryIds   []string `json:"category_ids" validate:"omitempty,dive,required"`
+	Type          string   `json:"type,omitempty" validate:"omitempty,oneof=income expense"`
+	AmountMin     *int64   `json:"amount_min,omitempty" validate:"omitempty,min=0"`
+	AmountMax     *int64   `json:"amount_max,omitempty" validate:"omitempty,min=0"`
+	PaymentMethod string   `json:"payment_method,omitempty" validate:"omitempty,max=255"`
+	Source        string   `json:"source,omitempty" validate:"omitempty,max=255"`
+}
+
+// TransactionFilterRequest carries either a natural-language query or an already parsed (possibly user edited) filter
+type TransactionFilterRequest struct {
+	Query  string             `json:"query" validate:"omitempty,max=500"`
+	Filter *TransactionFilter `json:"filter" validate:"omitempty"`
+	Limit  int                `json:"limit" validate:"omitempty,min=1,max=100"`
+	Offset int                `json:"offset" validate:"omitempty,min=0"`
+}
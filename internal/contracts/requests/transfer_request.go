@@ -0,0 +1,18 @@
+package requests
+
+type TransferRequest struct {
+	FromAccountId string `json:"from_account_id" validate:"required"`
+	ToAccountId   string `json:"to_account_id" validate:"required"`
+	Amount        int64  `json:"amount" validate:"required,min=1"`
+	Fee           int64  `json:"fee" validate:"omitempty,min=0"`
+	// FeeCategoryId is the expense category of the fee, required when a fee is charged
+	FeeCategoryId string `json:"fee_category_id" validate:"omitempty"`
+	Description   string `json:"description" validate:"omitempty,max=255"`
+	TransferDate  string `json:"transfer_date" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type TransferQuery struct {
+	Limit     int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset    int    `query:"offset" validate:"omitempty,min=0"`
+	AccountId string `query:"account_id" validate:"omitempty"`
+}
@@ -0,0 +1,11 @@
+package requests
+
+type UsageQuery struct {
+	GroupBy string `query:"group_by" validate:"omitempty,oneof=user topic model day"`
+	From    string `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To      string `query:"to" validate:"omitempty,datetime=2006-01-02"` // inclusive
+	UserId  string `query:"user_id" validate:"omitempty"`
+	Topic   string `query:"topic" validate:"omitempty"`
+	Model   string `query:"model" validate:"omitempty"`
+	Limit   int    `query:"limit" validate:"omitempty,min=1,max=1000"`
+}
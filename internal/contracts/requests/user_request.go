@@ -15,3 +15,21 @@ type UpdateUserRequest struct {
 	FullName string `json:"full_name" validate:"required"`
 	Email    string `json:"email" validate:"required,email"`
 }
+
+// UpdatePreferencesRequest changes only the fields that are sent, an empty string restores the default
+type UpdatePreferencesRequest struct {
+	BaseCurrency    *string                         `json:"base_currency" validate:"omitempty,len=3"`
+	Locale          *string                         `json:"locale"`
+	Timezone        *string                         `json:"timezone"`
+	Language        *string                         `json:"language" validate:"omitempty,oneof=id en"`
+	Tone            *string                         `json:"tone" validate:"omitempty,oneof=friendly professional concise"`
+	DefaultChatMode *string                         `json:"default_chat_mode" validate:"omitempty,oneof=ask agent"`
+	DefaultModel    *string                         `json:"default_model"`
+	Notifications   *NotificationPreferencesRequest `json:"notifications"`
+}
+
+type NotificationPreferencesRequest struct {
+	MonthlyReport      *bool `json:"monthly_report"`
+	AnomalyAlerts      *bool `json:"anomaly_alerts"`
+	SubscriptionAlerts *bool `json:"subscription_alerts"`
+}
@@ -0,0 +1,32 @@
+package responses
+
+import "github.com/saufiroja/fin-ai/internal/models"
+
+type AccountsResponse struct {
+	Currency     CurrencyMeta                `json:"currency"`
+	TotalBalance int64                       `json:"total_balance"`
+	Accounts     []models.AccountWithBalance `json:"accounts"`
+}
+
+type AccountLedgerResponse struct {
+	Currency       CurrencyMeta                `json:"currency"`
+	AccountId      string                      `json:"account_id"`
+	StartDate      string                      `json:"start_date"`
+	EndDate        string                      `json:"end_date"`
+	OpeningBalance int64                       `json:"opening_balance"` // Balance at the start of the range
+	ClosingBalance int64                       `json:"closing_balance"`
+	Entries        []models.AccountLedgerEntry `json:"entries"`
+}
+
+type AssignAccountTransactionsResponse struct {
+	Assigned int64 `json:"assigned"`
+}
+
+type ReconcileAccountResponse struct {
+	Reconciliation *models.AccountReconciliation `json:"reconciliation"`
+	Matched        bool                          `json:"matched"`
+	// PreviousMatch is the last reconciliation that matched, the gap lies in the transactions after it
+	PreviousMatch *models.AccountReconciliation `json:"previous_match,omitempty"`
+	// Entries are the transactions between the previous match and the statement date
+	Entries []models.AccountLedgerEntry `json:"entries"`
+}
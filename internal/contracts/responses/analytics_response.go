@@ -0,0 +1,111 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/saufiroja/fin-ai/pkg/fx"
+)
+
+type CurrencyMeta struct {
+	Code     string `json:"code"`
+	Symbol   string `json:"symbol"`
+	Decimals int    `json:"decimals"`
+}
+
+func NewCurrencyMeta(currency fx.Currency) CurrencyMeta {
+	return CurrencyMeta{
+		Code:     currency.Code,
+		Symbol:   currency.Symbol,
+		Decimals: currency.Decimals,
+	}
+}
+
+type TransactionStatsResponse struct {
+	Currency          CurrencyMeta `json:"currency"`
+	StartDate         string       `json:"start_date"`
+	EndDate           string       `json:"end_date"`
+	Days              int          `json:"days"`
+	TotalIncome       int64        `json:"total_income"`
+	TotalExpense      int64        `json:"total_expense"`
+	Net               int64        `json:"net"`
+	TransactionCount  int64        `json:"transaction_count"`
+	AverageDailySpend int64        `json:"average_daily_spend"`
+}
+
+type TimeSeriesPoint struct {
+	PeriodStart      time.Time `json:"period_start"`
+	Income           int64     `json:"income"`
+	Expense          int64     `json:"expense"`
+	Net              int64     `json:"net"`
+	TransactionCount int64     `json:"transaction_count"`
+}
+
+type TimeSeriesResponse struct {
+	Currency  CurrencyMeta      `json:"currency"`
+	Interval  string            `json:"interval"`
+	StartDate string            `json:"start_date"`
+	EndDate   string            `json:"end_date"`
+	Points    []TimeSeriesPoint `json:"points"`
+}
+
+type CategoryBreakdownItem struct {
+	CategoryId       string  `json:"category_id"`
+	CategoryName     string  `json:"category_name"`
+	Total            int64   `json:"total"`
+	TransactionCount int64   `json:"transaction_count"`
+	Percentage       float64 `json:"percentage"`
+}
+
+type CategoryBreakdownResponse struct {
+	Currency   CurrencyMeta            `json:"currency"`
+	Type       string                  `json:"type"`
+	StartDate  string                  `json:"start_date"`
+	EndDate    string                  `json:"end_date"`
+	Total      int64                   `json:"total"`
+	Categories []CategoryBreakdownItem `json:"categories"`
+}
+
+type TagBreakdownItem struct {
+	TagId            string  `json:"tag_id"`
+	TagName          string  `json:"tag_name"`
+	Total            int64   `json:"total"`
+	TransactionCount int64   `json:"transaction_count"`
+	Percentage       float64 `json:"percentage"` // share of all transactions of the type in the period
+}
+
+// TagBreakdownResponse totals per tag, a transaction with several tags counts towards each of them
+type TagBreakdownResponse struct {
+	Currency      CurrencyMeta       `json:"currency"`
+	Type          string             `json:"type"`
+	StartDate     string             `json:"start_date"`
+	EndDate       string             `json:"end_date"`
+	Total         int64              `json:"total"`
+	TaggedTotal   int64              `json:"tagged_total"`
+	UntaggedTotal int64              `json:"untagged_total"`
+	Tags          []TagBreakdownItem `json:"tags"`
+}
+
+type TopSourceItem struct {
+	Source           string  `json:"source"`
+	Total            int64   `json:"total"`
+	TransactionCount int64   `json:"transaction_count"`
+	Percentage       float64 `json:"percentage"`
+}
+
+type TopSourcesResponse struct {
+	Currency  CurrencyMeta    `json:"currency"`
+	Type      string          `json:"type"`
+	StartDate string          `json:"start_date"`
+	EndDate   string          `json:"end_date"`
+	Sources   []TopSourceItem `json:"sources"`
+}
+
+type PeriodComparisonResponse struct {
+	Current          *TransactionStatsResponse `json:"current"`
+	Previous         *TransactionStatsResponse `json:"previous"`
+	IncomeChange     int64                     `json:"income_change"`
+	ExpenseChange    int64                     `json:"expense_change"`
+	NetChange        int64                     `json:"net_change"`
+	IncomeChangePct  *float64                  `json:"income_change_pct"`  // nil when the previous period had no income
+	ExpenseChangePct *float64                  `json:"expense_change_pct"` // nil when the previous period had no expense
+}
@@ -0,0 +1,18 @@
+package responses
+
+import "github.com/saufiroja/fin-ai/internal/models"
+
+type AnomaliesResponse struct {
+	Anomalies   []models.TransactionAnomaly `json:"anomalies"`
+	CurrentPage int64                       `json:"current_page"`
+	TotalPages  int64                       `json:"total_pages"`
+	Total       int64                       `json:"total"`
+}
+
+type AnomalyScanResponse struct {
+	StartDate              string                      `json:"start_date"`
+	EndDate                string                      `json:"end_date"`
+	TransactionsScanned    int                         `json:"transactions_scanned"`
+	Anomalies              []models.TransactionAnomaly `json:"anomalies"` // Newly recorded during this scan
+	RecommendationsCreated int                         `json:"recommendations_created"`
+}
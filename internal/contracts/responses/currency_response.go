@@ -0,0 +1,12 @@
+package responses
+
+import "github.com/saufiroja/fin-ai/pkg/fx"
+
+type CurrenciesResponse struct {
+	BaseCurrency CurrencyMeta  `json:"base_currency"`
+	Currencies   []fx.Currency `json:"currencies"`
+}
+
+type UpsertExchangeRatesResponse struct {
+	Saved int `json:"saved"`
+}
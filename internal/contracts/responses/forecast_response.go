@@ -0,0 +1,46 @@
+package responses
+
+type ForecastBand struct {
+	Expected int64 `json:"expected"`
+	Low      int64 `json:"low"`
+	High     int64 `json:"high"`
+}
+
+type ForecastCategory struct {
+	CategoryId   string       `json:"category_id"`
+	CategoryName string       `json:"category_name"`
+	Spend        ForecastBand `json:"spend"`
+	Actual       int64        `json:"actual"`
+	Scheduled    int64        `json:"scheduled"`
+}
+
+type ForecastMonth struct {
+	Month            string             `json:"month"` // YYYY-MM
+	StartDate        string             `json:"start_date"`
+	EndDate          string             `json:"end_date"`
+	Income           ForecastBand       `json:"income"`
+	Expense          ForecastBand       `json:"expense"`
+	ScheduledIncome  int64              `json:"scheduled_income"`
+	ScheduledExpense int64              `json:"scheduled_expense"`
+	EndBalance       ForecastBand       `json:"end_balance"`
+	Categories       []ForecastCategory `json:"categories"`
+}
+
+type ForecastDay struct {
+	Date    string       `json:"date"`
+	Balance ForecastBand `json:"balance"`
+}
+
+type ForecastResponse struct {
+	Currency        CurrencyMeta    `json:"currency"`
+	AsOf            string          `json:"as_of"`
+	HistoryDays     int             `json:"history_days"`
+	ConfidenceLevel float64         `json:"confidence_level"`
+	StartingBalance int64           `json:"starting_balance"`
+	Months          []ForecastMonth `json:"months"`
+	Daily           []ForecastDay   `json:"daily"`
+	LowestBalance   ForecastDay     `json:"lowest_balance"`
+	FirstNegative   *ForecastDay    `json:"first_negative,omitempty"` // Expected balance below zero
+	FirstAtRisk     *ForecastDay    `json:"first_at_risk,omitempty"`  // Low end of the band below zero
+	NextPayday      *ForecastDay    `json:"next_payday,omitempty"`    // First scheduled income, with the balance the day before it
+}
@@ -0,0 +1,26 @@
+package responses
+
+import "github.com/saufiroja/fin-ai/internal/models"
+
+// PromptSummary lists the versions recorded for a prompt and the one served outside experiments
+type PromptSummary struct {
+	PromptKey     string                 `json:"prompt_key"`
+	ActiveVersion string                 `json:"active_version"`
+	Versions      []models.PromptVersion `json:"versions"`
+}
+
+// PromptExperimentResult compares the calls served by the two versions of an experiment
+type PromptExperimentResult struct {
+	Experiment models.PromptExperiment `json:"experiment"`
+	Control    PromptVersionResult     `json:"control"`
+	Candidate  PromptVersionResult     `json:"candidate"`
+}
+
+// PromptVersionResult adds the rates of an outcome, a rate is nil while the version has no calls
+type PromptVersionResult struct {
+	models.PromptVersionOutcome
+	ParseFailureRate    *float64 `json:"parse_failure_rate"`
+	CorrectionRate      *float64 `json:"correction_rate"`
+	AverageInputTokens  *float64 `json:"average_input_tokens"`
+	AverageOutputTokens *float64 `json:"average_output_tokens"`
+}
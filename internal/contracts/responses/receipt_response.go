@@ -41,6 +41,7 @@ type DetailReceiptUserResponse struct {
 	UpdatedAt       time.Time             `json:"updated_at"`
 	Items           []*models.ReceiptItem `json:"items"`
 	Confirmed       bool                  `json:"confirmed"`
+	Tags            []string              `json:"tags"`
 }
 
 type ReceiptResponse struct {
@@ -0,0 +1,10 @@
+package responses
+
+import "github.com/saufiroja/fin-ai/internal/models"
+
+type RecommendationsResponse struct {
+	Recommendations []models.AIRecommendation `json:"recommendations"`
+	CurrentPage     int64                     `json:"current_page"`
+	TotalPages      int64                     `json:"total_pages"`
+	Total           int64                     `json:"total"`
+}
@@ -0,0 +1,32 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/constants"
+)
+
+type UpcomingBill struct {
+	RecurringRuleId string                 `json:"recurring_rule_id"`
+	CategoryId      string                 `json:"category_id"`
+	Type            constants.TypeCategory `json:"type"`
+	Description     string                 `json:"description"`
+	Amount          int64                  `json:"amount"`
+	DueDate         time.Time              `json:"due_date"`
+	Overridden      bool                   `json:"overridden"`
+}
+
+type UpcomingBillsResponse struct {
+	Currency     CurrencyMeta   `json:"currency"`
+	StartDate    string         `json:"start_date"`
+	EndDate      string         `json:"end_date"`
+	TotalExpense int64          `json:"total_expense"`
+	TotalIncome  int64          `json:"total_income"`
+	Bills        []UpcomingBill `json:"bills"`
+}
+
+type MaterializeRecurringResult struct {
+	RulesProcessed int `json:"rules_processed"`
+	Created        int `json:"created"`
+	Skipped        int `json:"skipped"`
+}
@@ -0,0 +1,21 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/models"
+)
+
+type MonthlyReportResponse struct {
+	models.MonthlyReport
+	// DownloadURL is a presigned link that stops working at DownloadExpiresAt
+	DownloadURL       string    `json:"download_url"`
+	DownloadExpiresAt time.Time `json:"download_expires_at"`
+}
+
+type MonthlyReportsResponse struct {
+	TotalPages  int64                  `json:"total_pages"`
+	CurrentPage int64                  `json:"current_page"`
+	Total       int64                  `json:"total"`
+	Reports     []models.MonthlyReport `json:"reports"`
+}
@@ -17,10 +17,20 @@ type ResponseAI struct {
 	Response    any `json:"response"`
 	InputToken  int `json:"input_token"`
 	OutputToken int `json:"output_token"`
+
+	Model string `json:"model"` // model that answered, the key of constants.ModelPrices
 }
 
 type ResponseEmbedding struct {
 	Embeddings  string `json:"embeddings"`
 	InputToken  int    `json:"input_token"`
-	OutputToken int    `json:"output_token"`
+	OutputToken int    `json:"output_token"` // always 0, embeddings are billed on the input only
+	Model       string `json:"model"`
+}
+
+type ResponseBatchEmbedding struct {
+	Embeddings  []string `json:"embeddings"`
+	InputToken  int      `json:"input_token"`
+	OutputToken int      `json:"output_token"` // always 0, embeddings are billed on the input only
+	Model       string   `json:"model"`
 }
@@ -0,0 +1,32 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/models"
+)
+
+type ReviewReceiptItem struct {
+	ReceiptItemId        string    `json:"receipt_item_id"`
+	ReceiptId            string    `json:"receipt_id"`
+	MerchantName         string    `json:"merchant_name"`
+	ItemName             string    `json:"item_name"`
+	ItemQuantity         int       `json:"item_quantity"`
+	ItemPriceTotal       int64     `json:"item_price_total"`
+	CategoryId           *string   `json:"category_id"`
+	AiCategoryConfidence float64   `json:"ai_category_confidence"`
+	TransactionDate      time.Time `json:"transaction_date"`
+}
+
+type ReviewQueueResponse struct {
+	Threshold         float64              `json:"threshold"`
+	TotalTransactions int64                `json:"total_transactions"`
+	TotalReceiptItems int64                `json:"total_receipt_items"`
+	Transactions      []models.Transaction `json:"transactions"`
+	ReceiptItems      []*ReviewReceiptItem `json:"receipt_items"`
+}
+
+type ReviewActionResponse struct {
+	UpdatedTransactions int64 `json:"updated_transactions"`
+	UpdatedReceiptItems int64 `json:"updated_receipt_items"`
+}
@@ -0,0 +1,28 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/models"
+)
+
+type SearchResult struct {
+	Type            constants.SearchResultType `json:"type"`
+	Id              string                     `json:"id"`
+	Score           float64                    `json:"score"`
+	KeywordRank     int                        `json:"keyword_rank,omitempty"`
+	SemanticRank    int                        `json:"semantic_rank,omitempty"`
+	TransactionDate time.Time                  `json:"transaction_date"`
+	MerchantName    string                     `json:"merchant_name,omitempty"`
+	Transaction     *models.Transaction        `json:"transaction,omitempty"`
+	Receipt         *models.Receipt            `json:"receipt,omitempty"`
+	ReceiptItem     *models.ReceiptItem        `json:"receipt_item,omitempty"`
+}
+
+type SearchResponse struct {
+	TotalPages  int64           `json:"total_pages"`
+	CurrentPage int64           `json:"current_page"`
+	Total       int64           `json:"total"`
+	Results     []*SearchResult `json:"results"`
+}
@@ -0,0 +1,43 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+)
+
+type PriceIncrease struct {
+	PreviousAmount int64     `json:"previous_amount"`
+	NewAmount      int64     `json:"new_amount"`
+	ChangePercent  float64   `json:"change_percent"`
+	ChangedAt      time.Time `json:"changed_at"`
+}
+
+type DetectedSubscription struct {
+	Merchant            string                         `json:"merchant"`
+	Descriptions        []string                       `json:"descriptions"`
+	Frequency           constants.PeriodType           `json:"frequency"`
+	AverageIntervalDays float64                        `json:"average_interval_days"`
+	Occurrences         int                            `json:"occurrences"`
+	LatestAmount        int64                          `json:"latest_amount"`
+	AverageAmount       int64                          `json:"average_amount"`
+	EstimatedYearlyCost int64                          `json:"estimated_yearly_cost"`
+	FirstChargeDate     time.Time                      `json:"first_charge_date"`
+	LastChargeDate      time.Time                      `json:"last_charge_date"`
+	NextExpectedDate    time.Time                      `json:"next_expected_date"`
+	IsActive            bool                           `json:"is_active"`
+	PriceIncrease       *PriceIncrease                 `json:"price_increase,omitempty"`
+	ExistingRuleId      string                         `json:"existing_rule_id,omitempty"`
+	SuggestedRule       *requests.RecurringRuleRequest `json:"suggested_rule,omitempty"`
+	TransactionIds      []string                       `json:"transaction_ids"`
+}
+
+type SubscriptionDetectionResponse struct {
+	Currency               CurrencyMeta           `json:"currency"`
+	Subscriptions          []DetectedSubscription `json:"subscriptions"`
+	ActiveCount            int                    `json:"active_count"`
+	EstimatedMonthlyCost   int64                  `json:"estimated_monthly_cost"`
+	EstimatedYearlyCost    int64                  `json:"estimated_yearly_cost"`
+	RecommendationsCreated int                    `json:"recommendations_created"`
+}
@@ -0,0 +1,14 @@
+package responses
+
+type TagAssignmentResponse struct {
+	Tags []string `json:"tags"`
+}
+
+type TagSuggestion struct {
+	Name     string `json:"name"`
+	Existing bool   `json:"existing"` // false when the tag would be created on assignment
+}
+
+type TagSuggestionResponse struct {
+	Suggestions []TagSuggestion `json:"suggestions"`
+}
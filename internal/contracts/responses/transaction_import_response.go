@@ -0,0 +1,51 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/pkg/importer"
+)
+
+type ImportUploadResponse struct {
+	models.TransactionImport
+	DetectedMapping importer.Mapping `json:"detected_mapping"`
+	SampleRows      [][]string       `json:"sample_rows"`
+}
+
+// ImportRowResult is the outcome of one data row, Row counts from 1 and skips the header and blank lines
+type ImportRowResult struct {
+	Row         int                       `json:"row"`
+	Status      constants.ImportRowStatus `json:"status"`
+	Errors      []string                  `json:"errors,omitempty"`
+	Transaction *ImportRowTransaction     `json:"transaction,omitempty"`
+}
+
+type ImportRowTransaction struct {
+	TransactionDate time.Time              `json:"transaction_date"`
+	Description     string                 `json:"description"`
+	Amount          int64                  `json:"amount"`
+	Type            constants.TypeCategory `json:"type"`
+	CategoryId      string                 `json:"category_id"`
+	CategoryName    string                 `json:"category_name"`
+	PaymentMethod   string                 `json:"payment_method"`
+	Source          string                 `json:"source"`
+}
+
+type ImportValidationResponse struct {
+	ImportId      string `json:"import_id"`
+	TotalRows     int    `json:"total_rows"`
+	ValidRows     int    `json:"valid_rows"`
+	DuplicateRows int    `json:"duplicate_rows"`
+	InvalidRows   int    `json:"invalid_rows"`
+	// Rows lists every invalid and duplicate row and the first valid ones as a preview
+	Rows []ImportRowResult `json:"rows"`
+}
+
+type TransactionImportsResponse struct {
+	TotalPages  int64                      `json:"total_pages"`
+	CurrentPage int64                      `json:"current_page"`
+	Total       int64                      `json:"total"`
+	Imports     []models.TransactionImport `json:"imports"`
+}
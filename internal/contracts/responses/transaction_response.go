@@ -1,6 +1,12 @@
 package responses
 
-import "github.com/saufiroja/fin-ai/internal/models"
+import (
+	"io"
+
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/pkg/exporter"
+)
 
 type GetAllTransactionsResponse struct {
 	TotalPages   int64                `json:"total_pages"`
@@ -10,8 +16,9 @@ type GetAllTransactionsResponse struct {
 }
 
 type OverviewTransactions struct {
-	TotalIncome  int64 `json:"total_income"`
-	TotalExpense int64 `json:"total_expense"`
+	TotalIncome      int64 `json:"total_income"`
+	TotalExpense     int64 `json:"total_expense"`
+	TransactionCount int64 `json:"transaction_count"`
 }
 
 type OverviewTransactionsResponse struct {
@@ -19,3 +26,18 @@ type OverviewTransactionsResponse struct {
 	TotalExpense      string `json:"total_expense"`
 	TotalTransactions string `json:"total_transactions"`
 }
+
+type TransactionFilterResponse struct {
+	Filter       *requests.TransactionFilter `json:"filter"`
+	TotalPages   int64                       `json:"total_pages"`
+	CurrentPage  int64                       `json:"current_page"`
+	Total        int64                       `json:"total"`
+	Transactions []models.Transaction        `json:"transactions"`
+}
+
+// TransactionExport is a prepared export, Write streams the file once the response headers are sent
+type TransactionExport struct {
+	Format   exporter.Format
+	FileName string
+	Write    func(w io.Writer) error
+}
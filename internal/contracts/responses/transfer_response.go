@@ -0,0 +1,16 @@
+package responses
+
+import "github.com/saufiroja/fin-ai/internal/models"
+
+type TransferResponse struct {
+	models.TransferWithAccounts
+	// Transactions are the legs: transfer_out on the source account, transfer_in on the destination and the optional fee expense
+	Transactions []models.Transaction `json:"transactions"`
+}
+
+type TransfersResponse struct {
+	TotalPages  int64                         `json:"total_pages"`
+	CurrentPage int64                         `json:"current_page"`
+	Total       int64                         `json:"total"`
+	Transfers   []models.TransferWithAccounts `json:"transfers"`
+}
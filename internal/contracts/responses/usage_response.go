@@ -0,0 +1,11 @@
+package responses
+
+import "github.com/saufiroja/fin-ai/internal/models"
+
+type UsageResponse struct {
+	GroupBy string              `json:"group_by"`
+	From    string              `json:"from"`
+	To      string              `json:"to"`
+	Total   models.UsageGroup   `json:"total"` // every call of the period, also those of the groups past the limit
+	Groups  []models.UsageGroup `json:"groups"`
+}
@@ -1,6 +1,15 @@
 package responses
 
-import "time"
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/i18n"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/pkg/fx"
+)
 
 type LoginResponse struct {
 	AccessToken           string    `json:"access_token"`
@@ -13,4 +22,47 @@ type FindUserById struct {
 	UserId   string `json:"user_id" validate:"required"`
 	FullName string `json:"full_name" validate:"required"`
 	Email    string `json:"email" validate:"required,email"`
+	// Set while an account deletion request is pending
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
+}
+
+type AccountDeletion struct {
+	UserId              string    `json:"user_id"`
+	DeletionRequestedAt time.Time `json:"deletion_requested_at"`
+	DeletionScheduledAt time.Time `json:"deletion_scheduled_at"`
+}
+
+// UserDataExport is a prepared personal data archive, Write streams the zip once the response
+// headers are sent
+type UserDataExport struct {
+	FileName string
+	Write    func(w io.Writer) error
+}
+
+// UserPreferences are the stored preferences together with the base currency of the user
+type UserPreferences struct {
+	BaseCurrency fx.Currency `json:"base_currency"`
+	models.UserPreferences
+}
+
+// FormatAmount writes an amount in the base currency with the separators of the user's locale
+func (p *UserPreferences) FormatAmount(amount int64) string {
+	return fx.FormatLocale(amount, p.BaseCurrency, p.Locale)
+}
+
+// Location is the time zone of the user, the default time zone when the stored one cannot be loaded
+func (p *UserPreferences) Location() *time.Location {
+	if location, err := time.LoadLocation(p.Timezone); err == nil {
+		return location
+	}
+	if location, err := time.LoadLocation(constants.DefaultTimezone); err == nil {
+		return location
+	}
+	return time.UTC
+}
+
+// PromptLocale is the locale of the AI prompts: the language the user chose, otherwise the one the request
+// asked for with Accept-Language, otherwise the default locale
+func (p *UserPreferences) PromptLocale(ctx context.Context) string {
+	return i18n.Match(i18n.Default, p.Language, i18n.FromContext(ctx))
 }
@@ -0,0 +1,278 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/account"
+	"github.com/saufiroja/fin-ai/internal/utils"
+	"github.com/saufiroja/fin-ai/pkg/fx"
+)
+
+type accountController struct {
+	accountService account.AccountManager
+	validator      utils.Validator
+}
+
+func NewAccountController(accountService account.AccountManager, validator utils.Validator) account.AccountController {
+	return &accountController{
+		accountService: accountService,
+		validator:      validator,
+	}
+}
+
+// errorStatus maps account domain errors to HTTP status codes
+func (a *accountController) errorStatus(err error) int {
+	switch {
+	case errors.Is(err, account.ErrAccountNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, account.ErrAccountNameTaken):
+		return fiber.StatusConflict
+	case errors.Is(err, account.ErrInvalidReconciliation),
+		errors.Is(err, account.ErrAccountTransactionsOwner),
+		errors.Is(err, fx.ErrUnknownCurrency):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// errorResponse writes the error with its mapped status, internal errors get the fallback message
+func (a *accountController) errorResponse(ctx *fiber.Ctx, err error, fallback string) error {
+	status := a.errorStatus(err)
+	message := fallback
+	if status != fiber.StatusInternalServerError {
+		message = err.Error()
+	}
+	return ctx.Status(status).JSON(responses.Response{
+		Status:  status,
+		Message: message,
+	})
+}
+
+func (a *accountController) CreateAccount(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	req := &requests.AccountRequest{}
+	if err := ctx.BodyParser(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid request body",
+		})
+	}
+
+	if err := a.validator.ValidateStruct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := a.accountService.CreateAccount(userId, req)
+	if err != nil {
+		return a.errorResponse(ctx, err, "Failed to create account")
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(responses.Response{
+		Status:  fiber.StatusCreated,
+		Message: "Account created successfully",
+		Data:    result,
+	})
+}
+
+func (a *accountController) GetAccounts(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	query := &requests.AccountQuery{}
+	if err := ctx.QueryParser(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid query parameters",
+		})
+	}
+
+	result, err := a.accountService.GetAccounts(userId, query)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
+			Status:  fiber.StatusInternalServerError,
+			Message: "Failed to retrieve accounts",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Accounts retrieved successfully",
+		Data:    result,
+	})
+}
+
+func (a *accountController) GetAccountById(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	accountId := ctx.Params("account_id")
+
+	result, err := a.accountService.GetAccountById(userId, accountId)
+	if err != nil {
+		return a.errorResponse(ctx, err, "Failed to retrieve account")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Account retrieved successfully",
+		Data:    result,
+	})
+}
+
+func (a *accountController) UpdateAccount(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	accountId := ctx.Params("account_id")
+	req := &requests.AccountRequest{}
+	if err := ctx.BodyParser(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid request body",
+		})
+	}
+
+	if err := a.validator.ValidateStruct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := a.accountService.UpdateAccount(userId, accountId, req)
+	if err != nil {
+		return a.errorResponse(ctx, err, "Failed to update account")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Account updated successfully",
+		Data:    result,
+	})
+}
+
+func (a *accountController) DeleteAccount(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	accountId := ctx.Params("account_id")
+
+	if err := a.accountService.DeleteAccount(userId, accountId); err != nil {
+		return a.errorResponse(ctx, err, "Failed to delete account")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Account deleted successfully",
+	})
+}
+
+func (a *accountController) GetLedger(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	accountId := ctx.Params("account_id")
+	query := &requests.AccountLedgerQuery{}
+	if err := ctx.QueryParser(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid query parameters",
+		})
+	}
+
+	if err := a.validator.ValidateStruct(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := a.accountService.GetLedger(userId, accountId, query)
+	if err != nil {
+		return a.errorResponse(ctx, err, "Failed to retrieve account ledger")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Account ledger retrieved successfully",
+		Data:    result,
+	})
+}
+
+func (a *accountController) AssignTransactions(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	accountId := ctx.Params("account_id")
+	req := &requests.AssignAccountTransactionsRequest{}
+	if err := ctx.BodyParser(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid request body",
+		})
+	}
+
+	if err := a.validator.ValidateStruct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := a.accountService.AssignTransactions(userId, accountId, req)
+	if err != nil {
+		return a.errorResponse(ctx, err, "Failed to assign transactions")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Transactions assigned successfully",
+		Data:    result,
+	})
+}
+
+func (a *accountController) ReconcileAccount(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	accountId := ctx.Params("account_id")
+	req := &requests.ReconcileAccountRequest{}
+	if err := ctx.BodyParser(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid request body",
+		})
+	}
+
+	if err := a.validator.ValidateStruct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := a.accountService.ReconcileAccount(userId, accountId, req)
+	if err != nil {
+		return a.errorResponse(ctx, err, "Failed to reconcile account")
+	}
+
+	message := "Account balance matches the statement"
+	if !result.Matched {
+		message = "Account balance differs from the statement"
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: message,
+		Data:    result,
+	})
+}
+
+func (a *accountController) GetReconciliations(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	accountId := ctx.Params("account_id")
+
+	result, err := a.accountService.GetReconciliations(userId, accountId)
+	if err != nil {
+		return a.errorResponse(ctx, err, "Failed to retrieve reconciliations")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Reconciliations retrieved successfully",
+		Data:    result,
+	})
+}
@@ -0,0 +1,181 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/analytics"
+	"github.com/saufiroja/fin-ai/internal/utils"
+)
+
+type analyticsController struct {
+	analyticsService analytics.AnalyticsManager
+	validator        utils.Validator
+}
+
+func NewAnalyticsController(analyticsService analytics.AnalyticsManager, validator utils.Validator) analytics.AnalyticsController {
+	return &analyticsController{
+		analyticsService: analyticsService,
+		validator:        validator,
+	}
+}
+
+func (a *analyticsController) GetTimeSeries(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	query := &requests.AnalyticsQuery{}
+	if err := ctx.QueryParser(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid query parameters",
+		})
+	}
+
+	if err := a.validator.ValidateStruct(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := a.analyticsService.GetTimeSeries(userId, query)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
+			Status:  fiber.StatusInternalServerError,
+			Message: "Failed to retrieve time series",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Time series retrieved successfully",
+		Data:    result,
+	})
+}
+
+func (a *analyticsController) GetCategoryBreakdown(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	query := &requests.AnalyticsQuery{}
+	if err := ctx.QueryParser(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid query parameters",
+		})
+	}
+
+	if err := a.validator.ValidateStruct(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := a.analyticsService.GetCategoryBreakdown(userId, query)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
+			Status:  fiber.StatusInternalServerError,
+			Message: "Failed to retrieve category breakdown",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Category breakdown retrieved successfully",
+		Data:    result,
+	})
+}
+
+func (a *analyticsController) GetTagBreakdown(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	query := &requests.AnalyticsQuery{}
+	if err := ctx.QueryParser(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid query parameters",
+		})
+	}
+
+	if err := a.validator.ValidateStruct(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := a.analyticsService.GetTagBreakdown(userId, query)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
+			Status:  fiber.StatusInternalServerError,
+			Message: "Failed to retrieve tag breakdown",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Tag breakdown retrieved successfully",
+		Data:    result,
+	})
+}
+
+func (a *analyticsController) GetTopSources(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	query := &requests.AnalyticsQuery{}
+	if err := ctx.QueryParser(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid query parameters",
+		})
+	}
+
+	if err := a.validator.ValidateStruct(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := a.analyticsService.GetTopSources(userId, query)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
+			Status:  fiber.StatusInternalServerError,
+			Message: "Failed to retrieve top sources",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Top sources retrieved successfully",
+		Data:    result,
+	})
+}
+
+func (a *analyticsController) GetPeriodComparison(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	query := &requests.AnalyticsQuery{}
+	if err := ctx.QueryParser(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid query parameters",
+		})
+	}
+
+	if err := a.validator.ValidateStruct(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := a.analyticsService.GetPeriodComparison(userId, query)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
+			Status:  fiber.StatusInternalServerError,
+			Message: "Failed to retrieve period comparison",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Period comparison retrieved successfully",
+		Data:    result,
+	})
+}
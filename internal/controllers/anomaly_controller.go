@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/anomaly"
+	"github.com/saufiroja/fin-ai/internal/utils"
+)
+
+type anomalyController struct {
+	anomalyService anomaly.AnomalyManager
+	validator      utils.Validator
+}
+
+func NewAnomalyController(anomalyService anomaly.AnomalyManager, validator utils.Validator) anomaly.AnomalyController {
+	return &anomalyController{
+		anomalyService: anomalyService,
+		validator:      validator,
+	}
+}
+
+func (a *anomalyController) GetAnomalies(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	query := &requests.AnomalyQuery{
+		Limit:  10, // Default limit
+		Offset: 1,  // Default offset
+	}
+	if err := ctx.QueryParser(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid query parameters",
+		})
+	}
+
+	if err := a.validator.ValidateStruct(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := a.anomalyService.GetAnomalies(userId, query)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
+			Status:  fiber.StatusInternalServerError,
+			Message: "Failed to retrieve anomalies",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Anomalies retrieved successfully",
+		Data:    result.Anomalies,
+		Pagination: &responses.Pagination{
+			Total:       result.Total,
+			CurrentPage: result.CurrentPage,
+			TotalPages:  result.TotalPages,
+		},
+	})
+}
+
+func (a *anomalyController) ScanAnomalies(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	req := &requests.AnomalyScanRequest{}
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(req); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+				Status:  fiber.StatusBadRequest,
+				Message: "Invalid request body",
+			})
+		}
+	}
+
+	if err := a.validator.ValidateStruct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := a.anomalyService.ScanAnomalies(ctx.Context(), userId, req)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
+			Status:  fiber.StatusInternalServerError,
+			Message: "Failed to scan anomalies",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Anomaly scan completed successfully",
+		Data:    result,
+	})
+}
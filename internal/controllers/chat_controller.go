@@ -120,7 +120,7 @@ func (c *chatController) SendChatMessage(ctx *fiber.Ctx) error {
 		})
 	}
 
-	response, err := c.chatService.SendChatMessage(ctx.Context(), message)
+	response, err := c.chatService.SendChatMessage(ctx.UserContext(), message)
 	if err != nil {
 		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
 			Status:  fiber.StatusInternalServerError,
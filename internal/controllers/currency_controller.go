@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/currency"
+	"github.com/saufiroja/fin-ai/internal/domains/user"
+	"github.com/saufiroja/fin-ai/internal/utils"
+	"github.com/saufiroja/fin-ai/pkg/fx"
+)
+
+type currencyController struct {
+	currencyService currency.CurrencyManager
+	validator       utils.Validator
+}
+
+func NewCurrencyController(currencyService currency.CurrencyManager, validator utils.Validator) currency.CurrencyController {
+	return &currencyController{
+		currencyService: currencyService,
+		validator:       validator,
+	}
+}
+
+// errorStatus maps currency domain errors to HTTP status codes
+func (c *currencyController) errorStatus(err error) int {
+	switch {
+	case errors.Is(err, user.ErrUserNotFound), errors.Is(err, fx.ErrRateNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, currency.ErrBaseCurrencyLocked):
+		return fiber.StatusConflict
+	case errors.Is(err, fx.ErrUnknownCurrency), errors.Is(err, currency.ErrInvalidRate):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// errorResponse writes the error with its mapped status, internal errors get the fallback message
+func (c *currencyController) errorResponse(ctx *fiber.Ctx, err error, fallback string) error {
+	status := c.errorStatus(err)
+	message := fallback
+	if status != fiber.StatusInternalServerError {
+		message = err.Error()
+	}
+	return ctx.Status(status).JSON(responses.Response{
+		Status:  status,
+		Message: message,
+	})
+}
+
+func (c *currencyController) GetCurrencies(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+
+	result, err := c.currencyService.GetCurrencies(userId)
+	if err != nil {
+		return c.errorResponse(ctx, err, "Failed to retrieve currencies")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Currencies retrieved successfully",
+		Data:    result,
+	})
+}
+
+func (c *currencyController) SetBaseCurrency(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	req := &requests.BaseCurrencyRequest{}
+	if err := ctx.BodyParser(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid request body",
+		})
+	}
+
+	if err := c.validator.ValidateStruct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := c.currencyService.SetBaseCurrency(userId, req)
+	if err != nil {
+		return c.errorResponse(ctx, err, "Failed to set base currency")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Base currency updated successfully",
+		Data:    result,
+	})
+}
+
+func (c *currencyController) GetRate(ctx *fiber.Ctx) error {
+	query := &requests.ExchangeRateQuery{}
+	if err := ctx.QueryParser(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid query parameters",
+		})
+	}
+
+	if err := c.validator.ValidateStruct(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := c.currencyService.GetRate(ctx.UserContext(), query)
+	if err != nil {
+		return c.errorResponse(ctx, err, "Failed to retrieve exchange rate")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Exchange rate retrieved successfully",
+		Data:    result,
+	})
+}
+
+func (c *currencyController) UpsertRates(ctx *fiber.Ctx) error {
+	req := &requests.UpsertExchangeRatesRequest{}
+	if err := ctx.BodyParser(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid request body",
+		})
+	}
+
+	if err := c.validator.ValidateStruct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := c.currencyService.UpsertRates(req)
+	if err != nil {
+		return c.errorResponse(ctx, err, "Failed to save exchange rates")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Exchange rates saved successfully",
+		Data:    result,
+	})
+}
@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/forecast"
+	"github.com/saufiroja/fin-ai/internal/utils"
+)
+
+type forecastController struct {
+	forecastService forecast.ForecastManager
+	validator       utils.Validator
+}
+
+func NewForecastController(forecastService forecast.ForecastManager, validator utils.Validator) forecast.ForecastController {
+	return &forecastController{
+		forecastService: forecastService,
+		validator:       validator,
+	}
+}
+
+func (f *forecastController) GetForecast(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	query := &requests.ForecastQuery{
+		Months: constants.ForecastDefaultMonths, // Default months
+	}
+	if err := ctx.QueryParser(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid query parameters",
+		})
+	}
+
+	if err := f.validator.ValidateStruct(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := f.forecastService.GetForecast(userId, query, time.Now())
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
+			Status:  fiber.StatusInternalServerError,
+			Message: "Failed to forecast cash flow",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Cash flow forecast retrieved successfully",
+		Data:    result,
+	})
+}
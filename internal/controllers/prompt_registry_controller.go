@@ -0,0 +1,190 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/prompt_registry"
+	"github.com/saufiroja/fin-ai/internal/utils"
+)
+
+type promptRegistryController struct {
+	promptRegistryService prompt_registry.PromptRegistryManager
+	validator             utils.Validator
+}
+
+func NewPromptRegistryController(promptRegistryService prompt_registry.PromptRegistryManager, validator utils.Validator) prompt_registry.PromptRegistryController {
+	return &promptRegistryController{
+		promptRegistryService: promptRegistryService,
+		validator:             validator,
+	}
+}
+
+// errorStatus maps prompt registry domain errors to HTTP status codes
+func (p *promptRegistryController) errorStatus(err error) int {
+	switch {
+	case errors.Is(err, prompt_registry.ErrPromptVersionNotFound), errors.Is(err, prompt_registry.ErrPromptExperimentNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, prompt_registry.ErrPromptVersionExists), errors.Is(err, prompt_registry.ErrPromptExperimentRunning),
+		errors.Is(err, prompt_registry.ErrPromptExperimentEnded):
+		return fiber.StatusConflict
+	case errors.Is(err, prompt_registry.ErrInvalidPromptVersion):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// errorResponse writes the error with its mapped status, internal errors get the fallback message
+func (p *promptRegistryController) errorResponse(ctx *fiber.Ctx, err error, fallback string) error {
+	status := p.errorStatus(err)
+	message := fallback
+	if status != fiber.StatusInternalServerError {
+		message = err.Error()
+	}
+	return ctx.Status(status).JSON(responses.Response{
+		Status:  status,
+		Message: message,
+	})
+}
+
+func (p *promptRegistryController) GetPrompts(ctx *fiber.Ctx) error {
+	result, err := p.promptRegistryService.GetPrompts()
+	if err != nil {
+		return p.errorResponse(ctx, err, "Failed to retrieve prompts")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Prompts retrieved successfully",
+		Data:    result,
+	})
+}
+
+func (p *promptRegistryController) CreatePromptVersion(ctx *fiber.Ctx) error {
+	req := &requests.CreatePromptVersionRequest{}
+	if err := ctx.BodyParser(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid request body",
+		})
+	}
+
+	if err := p.validator.ValidateStruct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := p.promptRegistryService.CreatePromptVersion(req)
+	if err != nil {
+		return p.errorResponse(ctx, err, "Failed to create prompt version")
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(responses.Response{
+		Status:  fiber.StatusCreated,
+		Message: "Prompt version created successfully",
+		Data:    result,
+	})
+}
+
+func (p *promptRegistryController) ActivatePromptVersion(ctx *fiber.Ctx) error {
+	promptKey := ctx.Params("key")
+	req := &requests.ActivatePromptVersionRequest{}
+	if err := ctx.BodyParser(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid request body",
+		})
+	}
+
+	if err := p.validator.ValidateStruct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	if err := p.promptRegistryService.ActivatePromptVersion(promptKey, req); err != nil {
+		return p.errorResponse(ctx, err, "Failed to activate prompt version")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Prompt version activated successfully",
+	})
+}
+
+func (p *promptRegistryController) GetPromptExperiments(ctx *fiber.Ctx) error {
+	result, err := p.promptRegistryService.GetPromptExperiments()
+	if err != nil {
+		return p.errorResponse(ctx, err, "Failed to retrieve prompt experiments")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Prompt experiments retrieved successfully",
+		Data:    result,
+	})
+}
+
+func (p *promptRegistryController) StartPromptExperiment(ctx *fiber.Ctx) error {
+	req := &requests.StartPromptExperimentRequest{}
+	if err := ctx.BodyParser(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid request body",
+		})
+	}
+
+	if err := p.validator.ValidateStruct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := p.promptRegistryService.StartPromptExperiment(req)
+	if err != nil {
+		return p.errorResponse(ctx, err, "Failed to start prompt experiment")
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(responses.Response{
+		Status:  fiber.StatusCreated,
+		Message: "Prompt experiment started successfully",
+		Data:    result,
+	})
+}
+
+func (p *promptRegistryController) EndPromptExperiment(ctx *fiber.Ctx) error {
+	experimentId := ctx.Params("id")
+
+	result, err := p.promptRegistryService.EndPromptExperiment(experimentId)
+	if err != nil {
+		return p.errorResponse(ctx, err, "Failed to end prompt experiment")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Prompt experiment ended successfully",
+		Data:    result,
+	})
+}
+
+func (p *promptRegistryController) GetPromptExperimentResult(ctx *fiber.Ctx) error {
+	experimentId := ctx.Params("id")
+
+	result, err := p.promptRegistryService.GetPromptExperimentResult(experimentId)
+	if err != nil {
+		return p.errorResponse(ctx, err, "Failed to retrieve prompt experiment result")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Prompt experiment result retrieved successfully",
+		Data:    result,
+	})
+}
@@ -1,10 +1,13 @@
 package controllers
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/saufiroja/fin-ai/internal/contracts/requests"
 	"github.com/saufiroja/fin-ai/internal/contracts/responses"
 	"github.com/saufiroja/fin-ai/internal/domains/receipt"
+	"github.com/saufiroja/fin-ai/internal/domains/transaction"
 )
 
 type receiptController struct {
@@ -28,7 +31,7 @@ func (r *receiptController) UploadReceipt(c *fiber.Ctx) error {
 
 	userId := c.Locals("user_id").(string)
 
-	receipt, err := r.receiptService.UploadReceipt(file, userId)
+	receipt, err := r.receiptService.UploadReceipt(c.UserContext(), file, userId)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(responses.Response{
 			Status:  fiber.StatusInternalServerError,
@@ -101,10 +104,17 @@ func (r *receiptController) GetDetailReceiptUserById(c *fiber.Ctx) error {
 func (r *receiptController) UpdateReceiptConfirmed(c *fiber.Ctx) error {
 	receiptId := c.Params("receipt_id")
 	confirmed := c.Query("confirmed") == "true"
+	split := c.Query("split") == "true"
 	userId := c.Locals("user_id").(string)
 
-	err := r.receiptService.UpdateReceiptConfirmed(userId, receiptId, confirmed)
+	err := r.receiptService.UpdateReceiptConfirmed(userId, receiptId, confirmed, split)
 	if err != nil {
+		if errors.Is(err, receipt.ErrReceiptNotSplittable) || errors.Is(err, transaction.ErrInvalidSplits) {
+			return c.Status(fiber.StatusBadRequest).JSON(responses.Response{
+				Status:  fiber.StatusBadRequest,
+				Message: err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(responses.Response{
 			Status:  fiber.StatusInternalServerError,
 			Message: "Failed to update receipt confirmation",
@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/recommendation"
+	"github.com/saufiroja/fin-ai/internal/utils"
+)
+
+type recommendationController struct {
+	recommendationService recommendation.RecommendationManager
+	validator             utils.Validator
+}
+
+func NewRecommendationController(recommendationService recommendation.RecommendationManager, validator utils.Validator) recommendation.RecommendationController {
+	return &recommendationController{
+		recommendationService: recommendationService,
+		validator:             validator,
+	}
+}
+
+func (r *recommendationController) GetRecommendations(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	query := &requests.RecommendationQuery{
+		Limit:  10, // Default limit
+		Offset: 1,  // Default offset
+	}
+	if err := ctx.QueryParser(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid query parameters",
+		})
+	}
+
+	if err := r.validator.ValidateStruct(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := r.recommendationService.GetRecommendations(userId, query)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
+			Status:  fiber.StatusInternalServerError,
+			Message: "Failed to retrieve recommendations",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Recommendations retrieved successfully",
+		Data:    result.Recommendations,
+		Pagination: &responses.Pagination{
+			Total:       result.Total,
+			CurrentPage: result.CurrentPage,
+			TotalPages:  result.TotalPages,
+		},
+	})
+}
+
+func (r *recommendationController) MarkRecommendationAsRead(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	recommendationId := ctx.Params("recommendation_id")
+
+	if err := r.recommendationService.MarkRecommendationAsRead(userId, recommendationId); err != nil {
+		if errors.Is(err, recommendation.ErrRecommendationNotFound) {
+			return ctx.Status(fiber.StatusNotFound).JSON(responses.Response{
+				Status:  fiber.StatusNotFound,
+				Message: "Recommendation not found",
+			})
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
+			Status:  fiber.StatusInternalServerError,
+			Message: "Failed to mark recommendation as read",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Recommendation marked as read",
+	})
+}
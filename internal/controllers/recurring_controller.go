@@ -0,0 +1,287 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/recurring"
+	"github.com/saufiroja/fin-ai/internal/utils"
+)
+
+type recurringController struct {
+	recurringService recurring.RecurringManager
+	validator        utils.Validator
+}
+
+func NewRecurringController(recurringService recurring.RecurringManager, validator utils.Validator) recurring.RecurringController {
+	return &recurringController{
+		recurringService: recurringService,
+		validator:        validator,
+	}
+}
+
+// errorStatus maps recurring domain errors to HTTP status codes
+func (r *recurringController) errorStatus(err error) int {
+	switch {
+	case errors.Is(err, recurring.ErrRecurringRuleNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, recurring.ErrInvalidRecurringRule),
+		errors.Is(err, recurring.ErrOccurrenceNotScheduled),
+		errors.Is(err, recurring.ErrOccurrenceMaterialized):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+func (r *recurringController) CreateRecurringRule(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	req := &requests.RecurringRuleRequest{}
+	if err := ctx.BodyParser(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid request body",
+		})
+	}
+
+	if err := r.validator.ValidateStruct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	rule, err := r.recurringService.CreateRecurringRule(userId, req)
+	if err != nil {
+		status := r.errorStatus(err)
+		message := "Failed to create recurring rule"
+		if status != fiber.StatusInternalServerError {
+			message = err.Error()
+		}
+		return ctx.Status(status).JSON(responses.Response{
+			Status:  status,
+			Message: message,
+		})
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(responses.Response{
+		Status:  fiber.StatusCreated,
+		Message: "Recurring rule created successfully",
+		Data:    rule,
+	})
+}
+
+func (r *recurringController) GetRecurringRules(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+
+	rules, err := r.recurringService.GetRecurringRules(userId)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
+			Status:  fiber.StatusInternalServerError,
+			Message: "Failed to retrieve recurring rules",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Recurring rules retrieved successfully",
+		Data:    rules,
+	})
+}
+
+func (r *recurringController) GetRecurringRuleById(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	ruleId := ctx.Params("recurring_rule_id")
+
+	rule, err := r.recurringService.GetRecurringRuleById(userId, ruleId)
+	if err != nil {
+		status := r.errorStatus(err)
+		message := "Failed to retrieve recurring rule"
+		if status != fiber.StatusInternalServerError {
+			message = err.Error()
+		}
+		return ctx.Status(status).JSON(responses.Response{
+			Status:  status,
+			Message: message,
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Recurring rule retrieved successfully",
+		Data:    rule,
+	})
+}
+
+func (r *recurringController) UpdateRecurringRule(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	ruleId := ctx.Params("recurring_rule_id")
+	req := &requests.RecurringRuleRequest{}
+	if err := ctx.BodyParser(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid request body",
+		})
+	}
+
+	if err := r.validator.ValidateStruct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	rule, err := r.recurringService.UpdateRecurringRule(userId, ruleId, req)
+	if err != nil {
+		status := r.errorStatus(err)
+		message := "Failed to update recurring rule"
+		if status != fiber.StatusInternalServerError {
+			message = err.Error()
+		}
+		return ctx.Status(status).JSON(responses.Response{
+			Status:  status,
+			Message: message,
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Recurring rule updated successfully",
+		Data:    rule,
+	})
+}
+
+func (r *recurringController) DeleteRecurringRule(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	ruleId := ctx.Params("recurring_rule_id")
+
+	if err := r.recurringService.DeleteRecurringRule(userId, ruleId); err != nil {
+		status := r.errorStatus(err)
+		message := "Failed to delete recurring rule"
+		if status != fiber.StatusInternalServerError {
+			message = err.Error()
+		}
+		return ctx.Status(status).JSON(responses.Response{
+			Status:  status,
+			Message: message,
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Recurring rule deleted successfully",
+	})
+}
+
+func (r *recurringController) SkipOccurrence(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	ruleId := ctx.Params("recurring_rule_id")
+	req := &requests.RecurringOccurrenceRequest{}
+	if err := ctx.BodyParser(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid request body",
+		})
+	}
+
+	if err := r.validator.ValidateStruct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	if err := r.recurringService.SkipOccurrence(userId, ruleId, req); err != nil {
+		status := r.errorStatus(err)
+		message := "Failed to skip occurrence"
+		if status != fiber.StatusInternalServerError {
+			message = err.Error()
+		}
+		return ctx.Status(status).JSON(responses.Response{
+			Status:  status,
+			Message: message,
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Occurrence skipped successfully",
+	})
+}
+
+func (r *recurringController) EditOccurrence(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	ruleId := ctx.Params("recurring_rule_id")
+	req := &requests.RecurringOccurrenceRequest{}
+	if err := ctx.BodyParser(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid request body",
+		})
+	}
+
+	if err := r.validator.ValidateStruct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	if req.Amount == 0 && req.Description == "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Amount or description is required",
+		})
+	}
+
+	if err := r.recurringService.EditOccurrence(userId, ruleId, req); err != nil {
+		status := r.errorStatus(err)
+		message := "Failed to edit occurrence"
+		if status != fiber.StatusInternalServerError {
+			message = err.Error()
+		}
+		return ctx.Status(status).JSON(responses.Response{
+			Status:  status,
+			Message: message,
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Occurrence updated successfully",
+	})
+}
+
+func (r *recurringController) GetUpcomingBills(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	query := &requests.UpcomingBillsQuery{}
+	if err := ctx.QueryParser(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid query parameters",
+		})
+	}
+
+	if err := r.validator.ValidateStruct(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	bills, err := r.recurringService.GetUpcomingBills(userId, query.Days)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
+			Status:  fiber.StatusInternalServerError,
+			Message: "Failed to retrieve upcoming bills",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Upcoming bills retrieved successfully",
+		Data:    bills,
+	})
+}
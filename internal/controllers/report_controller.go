@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/report"
+	"github.com/saufiroja/fin-ai/internal/utils"
+)
+
+type reportController struct {
+	reportService report.ReportManager
+	validator     utils.Validator
+}
+
+func NewReportController(reportService report.ReportManager, validator utils.Validator) report.ReportController {
+	return &reportController{
+		reportService: reportService,
+		validator:     validator,
+	}
+}
+
+// errorStatus maps report domain errors to HTTP status codes
+func (r *reportController) errorStatus(err error) int {
+	switch {
+	case errors.Is(err, report.ErrReportNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, report.ErrInvalidReportPeriod):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// errorResponse writes the error with its mapped status, internal errors get the fallback message
+func (r *reportController) errorResponse(ctx *fiber.Ctx, err error, fallback string) error {
+	status := r.errorStatus(err)
+	message := fallback
+	if status != fiber.StatusInternalServerError {
+		message = err.Error()
+	}
+	return ctx.Status(status).JSON(responses.Response{
+		Status:  status,
+		Message: message,
+	})
+}
+
+func (r *reportController) GenerateMonthlyReport(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	req := &requests.GenerateReportRequest{}
+	if err := ctx.BodyParser(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid request body",
+		})
+	}
+
+	if err := r.validator.ValidateStruct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := r.reportService.GenerateMonthlyReport(ctx.UserContext(), userId, req)
+	if err != nil {
+		return r.errorResponse(ctx, err, "Failed to generate report")
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(responses.Response{
+		Status:  fiber.StatusCreated,
+		Message: "Report generated successfully",
+		Data:    result,
+	})
+}
+
+func (r *reportController) GetReports(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	query := &requests.ReportQuery{
+		Limit:  10,
+		Offset: 1,
+	}
+	if err := ctx.QueryParser(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid query parameters",
+		})
+	}
+
+	if err := r.validator.ValidateStruct(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := r.reportService.GetReports(userId, query)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
+			Status:  fiber.StatusInternalServerError,
+			Message: "Failed to retrieve reports",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Reports retrieved successfully",
+		Data:    result.Reports,
+		Pagination: &responses.Pagination{
+			TotalPages:  result.TotalPages,
+			CurrentPage: result.CurrentPage,
+			Total:       result.Total,
+		},
+	})
+}
+
+func (r *reportController) GetReportById(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	reportId := ctx.Params("report_id")
+
+	result, err := r.reportService.GetReportById(userId, reportId)
+	if err != nil {
+		return r.errorResponse(ctx, err, "Failed to retrieve report")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Report retrieved successfully",
+		Data:    result,
+	})
+}
@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/review"
+	"github.com/saufiroja/fin-ai/internal/utils"
+)
+
+type reviewController struct {
+	reviewService review.ReviewManager
+	validator     utils.Validator
+}
+
+func NewReviewController(reviewService review.ReviewManager, validator utils.Validator) review.ReviewController {
+	return &reviewController{
+		reviewService: reviewService,
+		validator:     validator,
+	}
+}
+
+// errorStatus maps review domain errors to HTTP status codes
+func (r *reviewController) errorStatus(err error) int {
+	switch {
+	case errors.Is(err, review.ErrCategoryNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, review.ErrNoItemsSelected),
+		errors.Is(err, review.ErrCategoryIdRequired):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// errorResponse writes the error with its mapped status, internal errors get the fallback message
+func (r *reviewController) errorResponse(ctx *fiber.Ctx, err error, fallback string) error {
+	status := r.errorStatus(err)
+	message := fallback
+	if status != fiber.StatusInternalServerError {
+		message = err.Error()
+	}
+	return ctx.Status(status).JSON(responses.Response{
+		Status:  status,
+		Message: message,
+	})
+}
+
+func (r *reviewController) GetReviewQueue(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	query := &requests.ReviewQueueQuery{
+		Limit:  10, // Default limit
+		Offset: 1,  // Default offset
+	}
+	if err := ctx.QueryParser(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid query parameters",
+		})
+	}
+
+	if err := r.validator.ValidateStruct(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	queue, err := r.reviewService.GetReviewQueue(userId, query)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
+			Status:  fiber.StatusInternalServerError,
+			Message: "Failed to retrieve review queue",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Review queue retrieved successfully",
+		Data:    queue,
+	})
+}
+
+func (r *reviewController) AcceptReviewItems(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	req := &requests.ReviewActionRequest{}
+	if err := ctx.BodyParser(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid request body",
+		})
+	}
+
+	if err := r.validator.ValidateStruct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := r.reviewService.AcceptReviewItems(userId, req)
+	if err != nil {
+		return r.errorResponse(ctx, err, "Failed to accept review items")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Review items accepted successfully",
+		Data:    result,
+	})
+}
+
+func (r *reviewController) RecategorizeReviewItems(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	req := &requests.ReviewActionRequest{}
+	if err := ctx.BodyParser(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid request body",
+		})
+	}
+
+	if err := r.validator.ValidateStruct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := r.reviewService.RecategorizeReviewItems(userId, req)
+	if err != nil {
+		return r.errorResponse(ctx, err, "Failed to recategorize review items")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Review items recategorized successfully",
+		Data:    result,
+	})
+}
@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/search"
+	"github.com/saufiroja/fin-ai/internal/utils"
+)
+
+type searchController struct {
+	searchService search.SearchManager
+	validator     utils.Validator
+}
+
+func NewSearchController(searchService search.SearchManager, validator utils.Validator) search.SearchController {
+	return &searchController{
+		searchService: searchService,
+		validator:     validator,
+	}
+}
+
+func (s *searchController) Search(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	query := &requests.SearchQuery{
+		Limit:  10, // Default limit
+		Offset: 1,  // Default offset
+	}
+	if err := ctx.QueryParser(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid query parameters",
+		})
+	}
+
+	if err := s.validator.ValidateStruct(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := s.searchService.Search(userId, query)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
+			Status:  fiber.StatusInternalServerError,
+			Message: "Failed to search",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Search results retrieved successfully",
+		Data:    result.Results,
+		Pagination: &responses.Pagination{
+			Total:       result.Total,
+			CurrentPage: result.CurrentPage,
+			TotalPages:  result.TotalPages,
+		},
+	})
+}
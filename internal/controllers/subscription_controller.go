@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/subscription"
+)
+
+type subscriptionController struct {
+	subscriptionService subscription.SubscriptionManager
+}
+
+func NewSubscriptionController(subscriptionService subscription.SubscriptionManager) subscription.SubscriptionController {
+	return &subscriptionController{
+		subscriptionService: subscriptionService,
+	}
+}
+
+func (s *subscriptionController) GetSubscriptions(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+
+	result, err := s.subscriptionService.DetectSubscriptions(ctx.Context(), userId, time.Now(), false)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
+			Status:  fiber.StatusInternalServerError,
+			Message: "Failed to detect subscriptions",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Subscriptions detected successfully",
+		Data:    result,
+	})
+}
+
+func (s *subscriptionController) DetectSubscriptions(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+
+	result, err := s.subscriptionService.DetectSubscriptions(ctx.Context(), userId, time.Now(), true)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
+			Status:  fiber.StatusInternalServerError,
+			Message: "Failed to detect subscriptions",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Subscriptions detected and recommendations saved successfully",
+		Data:    result,
+	})
+}
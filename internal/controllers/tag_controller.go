@@ -0,0 +1,233 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/tag"
+	"github.com/saufiroja/fin-ai/internal/utils"
+)
+
+type tagController struct {
+	tagService tag.TagManager
+	validator  utils.Validator
+}
+
+func NewTagController(tagService tag.TagManager, validator utils.Validator) tag.TagController {
+	return &tagController{
+		tagService: tagService,
+		validator:  validator,
+	}
+}
+
+// errorStatus maps tag domain errors to HTTP status codes
+func (t *tagController) errorStatus(err error) int {
+	switch {
+	case errors.Is(err, tag.ErrTagNotFound), errors.Is(err, tag.ErrTagTargetNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, tag.ErrTagNameTaken):
+		return fiber.StatusConflict
+	case errors.Is(err, tag.ErrInvalidTagName):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// errorResponse writes the error with its mapped status, internal errors get the fallback message
+func (t *tagController) errorResponse(ctx *fiber.Ctx, err error, fallback string) error {
+	status := t.errorStatus(err)
+	message := fallback
+	if status != fiber.StatusInternalServerError {
+		message = err.Error()
+	}
+	return ctx.Status(status).JSON(responses.Response{
+		Status:  status,
+		Message: message,
+	})
+}
+
+func (t *tagController) CreateTag(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	req := &requests.TagRequest{}
+	if err := ctx.BodyParser(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid request body",
+		})
+	}
+
+	if err := t.validator.ValidateStruct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := t.tagService.CreateTag(userId, req)
+	if err != nil {
+		return t.errorResponse(ctx, err, "Failed to create tag")
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(responses.Response{
+		Status:  fiber.StatusCreated,
+		Message: "Tag created successfully",
+		Data:    result,
+	})
+}
+
+func (t *tagController) GetTags(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+
+	result, err := t.tagService.GetTags(userId)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
+			Status:  fiber.StatusInternalServerError,
+			Message: "Failed to retrieve tags",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Tags retrieved successfully",
+		Data:    result,
+	})
+}
+
+func (t *tagController) UpdateTag(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	tagId := ctx.Params("tag_id")
+	req := &requests.TagRequest{}
+	if err := ctx.BodyParser(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid request body",
+		})
+	}
+
+	if err := t.validator.ValidateStruct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := t.tagService.UpdateTag(userId, tagId, req)
+	if err != nil {
+		return t.errorResponse(ctx, err, "Failed to update tag")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Tag updated successfully",
+		Data:    result,
+	})
+}
+
+func (t *tagController) DeleteTag(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	tagId := ctx.Params("tag_id")
+
+	if err := t.tagService.DeleteTag(userId, tagId); err != nil {
+		return t.errorResponse(ctx, err, "Failed to delete tag")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Tag deleted successfully",
+	})
+}
+
+func (t *tagController) SetTransactionTags(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	transactionId := ctx.Params("transaction_id")
+	req := &requests.AssignTagsRequest{}
+	if err := ctx.BodyParser(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid request body",
+		})
+	}
+
+	if err := t.validator.ValidateStruct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := t.tagService.SetTransactionTags(userId, transactionId, req)
+	if err != nil {
+		return t.errorResponse(ctx, err, "Failed to tag transaction")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Transaction tags updated successfully",
+		Data:    result,
+	})
+}
+
+func (t *tagController) SetReceiptTags(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	receiptId := ctx.Params("receipt_id")
+	req := &requests.AssignTagsRequest{}
+	if err := ctx.BodyParser(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid request body",
+		})
+	}
+
+	if err := t.validator.ValidateStruct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := t.tagService.SetReceiptTags(userId, receiptId, req)
+	if err != nil {
+		return t.errorResponse(ctx, err, "Failed to tag receipt")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Receipt tags updated successfully",
+		Data:    result,
+	})
+}
+
+func (t *tagController) SuggestTags(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	req := &requests.TagSuggestionRequest{}
+	if err := ctx.BodyParser(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid request body",
+		})
+	}
+
+	if err := t.validator.ValidateStruct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := t.tagService.SuggestTags(userId, req)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
+			Status:  fiber.StatusInternalServerError,
+			Message: "Failed to suggest tags",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Tag suggestions retrieved successfully",
+		Data:    result,
+	})
+}
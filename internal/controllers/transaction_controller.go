@@ -1,19 +1,29 @@
 package controllers
 
 import (
+	"bufio"
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/saufiroja/fin-ai/internal/contracts/requests"
 	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/account"
+	"github.com/saufiroja/fin-ai/internal/domains/tag"
 	"github.com/saufiroja/fin-ai/internal/domains/transaction"
+	"github.com/saufiroja/fin-ai/internal/utils"
+	"github.com/saufiroja/fin-ai/pkg/exporter"
+	"github.com/saufiroja/fin-ai/pkg/fx"
 )
 
 type transactionController struct {
 	transactionService transaction.TransactionManager
+	validator          utils.Validator
 }
 
-func NewTransactionController(transactionService transaction.TransactionManager) transaction.TransactionController {
+func NewTransactionController(transactionService transaction.TransactionManager, validator utils.Validator) transaction.TransactionController {
 	return &transactionController{
 		transactionService: transactionService,
+		validator:          validator,
 	}
 }
 
@@ -36,6 +46,12 @@ func (t *transactionController) GetAllTransactions(ctx *fiber.Ctx) error {
 
 	transactions, err := t.transactionService.GetAllTransactions(transactionQuery, userId)
 	if err != nil {
+		if errors.Is(err, tag.ErrInvalidTagName) {
+			return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+				Status:  fiber.StatusBadRequest,
+				Message: err.Error(),
+			})
+		}
 		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
 			Status:  fiber.StatusInternalServerError,
 			Message: "Failed to retrieve transactions",
@@ -67,6 +83,16 @@ func (t *transactionController) CreateTransaction(ctx *fiber.Ctx) error {
 	}
 
 	if err := t.transactionService.InsertTransaction(req); err != nil {
+		if errors.Is(err, account.ErrAccountNotFound) ||
+			errors.Is(err, transaction.ErrInvalidTransactionType) ||
+			errors.Is(err, transaction.ErrInvalidSplits) ||
+			errors.Is(err, fx.ErrUnknownCurrency) ||
+			errors.Is(err, fx.ErrRateNotFound) {
+			return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+				Status:  fiber.StatusBadRequest,
+				Message: err.Error(),
+			})
+		}
 		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
 			Status:  fiber.StatusInternalServerError,
 			Message: "Failed to create transaction",
@@ -88,7 +114,7 @@ func (t *transactionController) DeleteTransaction(ctx *fiber.Ctx) error {
 		})
 	}
 
-	if err := t.transactionService.DeleteTransaction(transactionId); err != nil {
+	if err := t.transactionService.DeleteTransaction(ctx.Locals("user_id").(string), transactionId); err != nil {
 		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
 			Status:  fiber.StatusInternalServerError,
 			Message: "Failed to delete transaction",
@@ -146,6 +172,16 @@ func (t *transactionController) UpdateTransaction(ctx *fiber.Ctx) error {
 	}
 
 	if err := t.transactionService.UpdateTransaction(transactionId, req); err != nil {
+		if errors.Is(err, account.ErrAccountNotFound) ||
+			errors.Is(err, transaction.ErrTransferLegUpdate) ||
+			errors.Is(err, transaction.ErrInvalidSplits) ||
+			errors.Is(err, fx.ErrUnknownCurrency) ||
+			errors.Is(err, fx.ErrRateNotFound) {
+			return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+				Status:  fiber.StatusBadRequest,
+				Message: err.Error(),
+			})
+		}
 		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
 			Status:  fiber.StatusInternalServerError,
 			Message: "Failed to update transaction",
@@ -185,3 +221,123 @@ func (t *transactionController) OverviewTransactions(ctx *fiber.Ctx) error {
 		Data:    overview,
 	})
 }
+
+func (t *transactionController) FilterTransactions(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	req := &requests.TransactionFilterRequest{
+		Limit:  10, // Default limit
+		Offset: 1,  // Default offset
+	}
+	if err := ctx.BodyParser(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid request body",
+		})
+	}
+
+	if err := t.validator.ValidateStruct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	if req.Query == "" && req.Filter == nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Query or filter is required",
+		})
+	}
+
+	result, err := t.transactionService.FilterTransactions(userId, req)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
+			Status:  fiber.StatusInternalServerError,
+			Message: "Failed to filter transactions",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Transactions filtered successfully",
+		Data:    result,
+		Pagination: &responses.Pagination{
+			Total:       result.Total,
+			CurrentPage: result.CurrentPage,
+			TotalPages:  result.TotalPages,
+		},
+	})
+}
+
+func (t *transactionController) GetTransactionsStats(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	query := &requests.OverviewTransactionsQuery{}
+	if err := ctx.QueryParser(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid query parameters",
+		})
+	}
+
+	if err := t.validator.ValidateStruct(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	stats, err := t.transactionService.GetTransactionsStats(userId, query)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
+			Status:  fiber.StatusInternalServerError,
+			Message: "Failed to retrieve transaction stats",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Transaction stats retrieved successfully",
+		Data:    stats,
+	})
+}
+
+func (t *transactionController) ExportTransactions(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	query := &requests.ExportTransactionsQuery{}
+	if err := ctx.QueryParser(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid query parameters",
+		})
+	}
+
+	if err := t.validator.ValidateStruct(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	export, err := t.transactionService.ExportTransactions(userId, query)
+	if err != nil {
+		if errors.Is(err, exporter.ErrUnsupportedFormat) || errors.Is(err, tag.ErrInvalidTagName) {
+			return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+				Status:  fiber.StatusBadRequest,
+				Message: err.Error(),
+			})
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
+			Status:  fiber.StatusInternalServerError,
+			Message: "Failed to export transactions",
+		})
+	}
+
+	// The rows are written after the headers are sent, a failure midway is logged by the service and
+	// ends the download early
+	ctx.Attachment(export.FileName)
+	ctx.Set(fiber.HeaderContentType, export.Format.ContentType())
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		_ = export.Write(w)
+	})
+	return nil
+}
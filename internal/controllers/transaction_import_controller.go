@@ -0,0 +1,208 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/account"
+	"github.com/saufiroja/fin-ai/internal/domains/transaction_import"
+	"github.com/saufiroja/fin-ai/internal/utils"
+)
+
+type transactionImportController struct {
+	importService transaction_import.TransactionImportManager
+	validator     utils.Validator
+}
+
+func NewTransactionImportController(importService transaction_import.TransactionImportManager, validator utils.Validator) transaction_import.TransactionImportController {
+	return &transactionImportController{
+		importService: importService,
+		validator:     validator,
+	}
+}
+
+// errorStatus maps import domain errors to HTTP status codes
+func (t *transactionImportController) errorStatus(err error) int {
+	switch {
+	case errors.Is(err, transaction_import.ErrImportNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, transaction_import.ErrImportNotPending),
+		errors.Is(err, transaction_import.ErrImportNotCommitted):
+		return fiber.StatusConflict
+	case errors.Is(err, transaction_import.ErrInvalidImport),
+		errors.Is(err, transaction_import.ErrImportHasInvalidRows),
+		errors.Is(err, account.ErrAccountNotFound):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// errorResponse writes the error with its mapped status, internal errors get the fallback message
+func (t *transactionImportController) errorResponse(ctx *fiber.Ctx, err error, fallback string) error {
+	status := t.errorStatus(err)
+	message := fallback
+	if status != fiber.StatusInternalServerError {
+		message = err.Error()
+	}
+	return ctx.Status(status).JSON(responses.Response{
+		Status:  status,
+		Message: message,
+	})
+}
+
+func (t *transactionImportController) UploadImport(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Failed to get file from request",
+		})
+	}
+
+	result, err := t.importService.UploadImport(userId, file, ctx.FormValue("format"))
+	if err != nil {
+		return t.errorResponse(ctx, err, "Failed to upload import file")
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(responses.Response{
+		Status:  fiber.StatusCreated,
+		Message: "Import file uploaded successfully",
+		Data:    result,
+	})
+}
+
+func (t *transactionImportController) GetImports(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	query := &requests.ImportQuery{
+		Limit:  10,
+		Offset: 1,
+	}
+	if err := ctx.QueryParser(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid query parameters",
+		})
+	}
+
+	if err := t.validator.ValidateStruct(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := t.importService.GetImports(userId, query)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
+			Status:  fiber.StatusInternalServerError,
+			Message: "Failed to retrieve imports",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Imports retrieved successfully",
+		Data:    result.Imports,
+		Pagination: &responses.Pagination{
+			TotalPages:  result.TotalPages,
+			CurrentPage: result.CurrentPage,
+			Total:       result.Total,
+		},
+	})
+}
+
+func (t *transactionImportController) GetImportById(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	importId := ctx.Params("import_id")
+
+	result, err := t.importService.GetImportById(userId, importId)
+	if err != nil {
+		return t.errorResponse(ctx, err, "Failed to retrieve import")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Import retrieved successfully",
+		Data:    result,
+	})
+}
+
+func (t *transactionImportController) ValidateImport(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	importId := ctx.Params("import_id")
+	req := &requests.ImportMappingRequest{}
+	if err := ctx.BodyParser(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid request body",
+		})
+	}
+
+	if err := t.validator.ValidateStruct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := t.importService.ValidateImport(userId, importId, req)
+	if err != nil {
+		return t.errorResponse(ctx, err, "Failed to validate import")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Import validated successfully",
+		Data:    result,
+	})
+}
+
+func (t *transactionImportController) CommitImport(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	importId := ctx.Params("import_id")
+	req := &requests.CommitImportRequest{}
+	if err := ctx.BodyParser(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid request body",
+		})
+	}
+
+	if err := t.validator.ValidateStruct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := t.importService.CommitImport(userId, importId, req)
+	if err != nil {
+		return t.errorResponse(ctx, err, "Failed to commit import")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Import committed successfully",
+		Data:    result,
+	})
+}
+
+func (t *transactionImportController) RevertImport(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	importId := ctx.Params("import_id")
+
+	result, err := t.importService.RevertImport(userId, importId)
+	if err != nil {
+		return t.errorResponse(ctx, err, "Failed to revert import")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Import reverted successfully",
+		Data:    result,
+	})
+}
@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/account"
+	"github.com/saufiroja/fin-ai/internal/domains/transfer"
+	"github.com/saufiroja/fin-ai/internal/utils"
+)
+
+type transferController struct {
+	transferService transfer.TransferManager
+	validator       utils.Validator
+}
+
+func NewTransferController(transferService transfer.TransferManager, validator utils.Validator) transfer.TransferController {
+	return &transferController{
+		transferService: transferService,
+		validator:       validator,
+	}
+}
+
+// errorStatus maps transfer domain errors to HTTP status codes
+func (t *transferController) errorStatus(err error) int {
+	switch {
+	case errors.Is(err, transfer.ErrTransferNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, transfer.ErrInvalidTransfer),
+		errors.Is(err, account.ErrAccountNotFound):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// errorResponse writes the error with its mapped status, internal errors get the fallback message
+func (t *transferController) errorResponse(ctx *fiber.Ctx, err error, fallback string) error {
+	status := t.errorStatus(err)
+	message := fallback
+	if status != fiber.StatusInternalServerError {
+		message = err.Error()
+	}
+	return ctx.Status(status).JSON(responses.Response{
+		Status:  status,
+		Message: message,
+	})
+}
+
+func (t *transferController) CreateTransfer(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	req := &requests.TransferRequest{}
+	if err := ctx.BodyParser(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid request body",
+		})
+	}
+
+	if err := t.validator.ValidateStruct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := t.transferService.CreateTransfer(userId, req)
+	if err != nil {
+		return t.errorResponse(ctx, err, "Failed to create transfer")
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(responses.Response{
+		Status:  fiber.StatusCreated,
+		Message: "Transfer created successfully",
+		Data:    result,
+	})
+}
+
+func (t *transferController) GetTransfers(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	query := &requests.TransferQuery{
+		Limit:  10,
+		Offset: 1,
+	}
+	if err := ctx.QueryParser(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid query parameters",
+		})
+	}
+
+	if err := t.validator.ValidateStruct(query); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	result, err := t.transferService.GetTransfers(userId, query)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
+			Status:  fiber.StatusInternalServerError,
+			Message: "Failed to retrieve transfers",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Transfers retrieved successfully",
+		Data:    result.Transfers,
+		Pagination: &responses.Pagination{
+			TotalPages:  result.TotalPages,
+			CurrentPage: result.CurrentPage,
+			Total:       result.Total,
+		},
+	})
+}
+
+func (t *transferController) GetTransferById(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	transferId := ctx.Params("transfer_id")
+
+	result, err := t.transferService.GetTransferById(userId, transferId)
+	if err != nil {
+		return t.errorResponse(ctx, err, "Failed to retrieve transfer")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Transfer retrieved successfully",
+		Data:    result,
+	})
+}
+
+func (t *transferController) DeleteTransfer(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	transferId := ctx.Params("transfer_id")
+
+	if err := t.transferService.DeleteTransfer(userId, transferId); err != nil {
+		return t.errorResponse(ctx, err, "Failed to delete transfer")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Transfer deleted successfully",
+	})
+}
@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/usage"
+	"github.com/saufiroja/fin-ai/internal/utils"
+)
+
+type usageController struct {
+	usageService usage.UsageManager
+	validator    utils.Validator
+}
+
+func NewUsageController(usageService usage.UsageManager, validator utils.Validator) usage.UsageController {
+	return &usageController{
+		usageService: usageService,
+		validator:    validator,
+	}
+}
+
+func (u *usageController) GetUsage(ctx *fiber.Ctx) error {
+	query, message := u.parseQuery(ctx)
+	if message != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: message,
+		})
+	}
+
+	result, err := u.usageService.GetUsage(query)
+	if err != nil {
+		return u.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Usage retrieved successfully",
+		Data:    result,
+	})
+}
+
+func (u *usageController) GetMyUsage(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	query, message := u.parseQuery(ctx)
+	if message != "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: message,
+		})
+	}
+
+	result, err := u.usageService.GetUserUsage(userId, query)
+	if err != nil {
+		return u.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Usage retrieved successfully",
+		Data:    result,
+	})
+}
+
+// parseQuery reads and validates the query, the message explains a bad query
+func (u *usageController) parseQuery(ctx *fiber.Ctx) (*requests.UsageQuery, string) {
+	query := &requests.UsageQuery{}
+	if err := ctx.QueryParser(query); err != nil {
+		return nil, "Invalid query parameters"
+	}
+
+	if err := u.validator.ValidateStruct(query); err != nil {
+		return nil, "Validation error: " + err.Error()
+	}
+	return query, ""
+}
+
+func (u *usageController) errorResponse(ctx *fiber.Ctx, err error) error {
+	if errors.Is(err, usage.ErrInvalidUsagePeriod) {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: err.Error(),
+		})
+	}
+	return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
+		Status:  fiber.StatusInternalServerError,
+		Message: "Failed to retrieve usage",
+	})
+}
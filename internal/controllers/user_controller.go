@@ -1,19 +1,28 @@
 package controllers
 
 import (
+	"bufio"
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/saufiroja/fin-ai/internal/constants"
 	"github.com/saufiroja/fin-ai/internal/contracts/requests"
 	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/currency"
 	"github.com/saufiroja/fin-ai/internal/domains/user"
+	"github.com/saufiroja/fin-ai/internal/utils"
+	"github.com/saufiroja/fin-ai/pkg/fx"
 )
 
 type userController struct {
 	UserService user.UserManager
+	validator   utils.Validator
 }
 
-func NewUserController(userService user.UserManager) user.UserController {
+func NewUserController(userService user.UserManager, validator utils.Validator) user.UserController {
 	return &userController{
 		UserService: userService,
+		validator:   validator,
 	}
 }
 
@@ -48,6 +57,7 @@ func (c *userController) UpdateUserById(ctx *fiber.Ctx) error {
 	})
 }
 
+// DeleteUserById schedules the account for deletion, the data is removed after the grace period
 func (c *userController) DeleteUserById(ctx *fiber.Ctx) error {
 	userId := ctx.Params("user_id")
 	if userId == "" {
@@ -57,18 +67,78 @@ func (c *userController) DeleteUserById(ctx *fiber.Ctx) error {
 		})
 	}
 
-	err := c.UserService.DeleteUserById(userId)
+	if userId != ctx.Locals("user_id").(string) {
+		return ctx.Status(fiber.StatusForbidden).JSON(responses.Response{
+			Status:  fiber.StatusForbidden,
+			Message: "You can only delete your own account",
+		})
+	}
+
+	deletion, err := c.UserService.RequestAccountDeletion(userId)
 	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(responses.Response{
-			Status:  fiber.StatusInternalServerError,
+		status := fiber.StatusInternalServerError
+		if errors.Is(err, user.ErrUserNotFound) {
+			status = fiber.StatusNotFound
+		}
+		return ctx.Status(status).JSON(responses.Response{
+			Status:  status,
+			Message: err.Error(),
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Account deletion scheduled successfully",
+		Data:    deletion,
+	})
+}
+
+func (c *userController) CancelAccountDeletion(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+
+	err := c.UserService.CancelAccountDeletion(userId)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		if errors.Is(err, user.ErrAccountDeletionNotScheduled) {
+			status = fiber.StatusConflict
+		}
+		return ctx.Status(status).JSON(responses.Response{
+			Status:  status,
 			Message: err.Error(),
 		})
 	}
 
 	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
 		Status:  fiber.StatusOK,
-		Message: "user deleted successfully",
+		Message: "Account deletion cancelled successfully",
+	})
+}
+
+func (c *userController) ExportUserData(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+
+	export, err := c.UserService.ExportUserData(userId)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		message := "Failed to export personal data"
+		if errors.Is(err, user.ErrUserNotFound) {
+			status = fiber.StatusNotFound
+			message = err.Error()
+		}
+		return ctx.Status(status).JSON(responses.Response{
+			Status:  status,
+			Message: message,
+		})
+	}
+
+	// The archive is written after the headers are sent, a failure midway is logged by the service and
+	// leaves an incomplete zip
+	ctx.Attachment(export.FileName)
+	ctx.Set(fiber.HeaderContentType, constants.UserDataExportContentType)
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		_ = export.Write(w)
 	})
+	return nil
 }
 
 func (c *userController) GetMe(ctx *fiber.Ctx) error {
@@ -96,3 +166,69 @@ func (c *userController) GetMe(ctx *fiber.Ctx) error {
 		Data:    user,
 	})
 }
+
+func (c *userController) GetPreferences(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+
+	prefs, err := c.UserService.GetPreferences(userId)
+	if err != nil {
+		return c.preferencesError(ctx, err, "Failed to get preferences")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Preferences retrieved successfully",
+		Data:    prefs,
+	})
+}
+
+func (c *userController) UpdatePreferences(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("user_id").(string)
+	req := &requests.UpdatePreferencesRequest{}
+	if err := ctx.BodyParser(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Invalid request body",
+		})
+	}
+
+	if err := c.validator.ValidateStruct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(responses.Response{
+			Status:  fiber.StatusBadRequest,
+			Message: "Validation error: " + err.Error(),
+		})
+	}
+
+	prefs, err := c.UserService.UpdatePreferences(userId, req)
+	if err != nil {
+		return c.preferencesError(ctx, err, "Failed to update preferences")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(responses.Response{
+		Status:  fiber.StatusOK,
+		Message: "Preferences updated successfully",
+		Data:    prefs,
+	})
+}
+
+// preferencesError writes the error with its mapped status, internal errors get the fallback message
+func (c *userController) preferencesError(ctx *fiber.Ctx, err error, fallback string) error {
+	status := fiber.StatusInternalServerError
+	message := fallback
+	switch {
+	case errors.Is(err, user.ErrUserNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, currency.ErrBaseCurrencyLocked):
+		status = fiber.StatusConflict
+	case errors.Is(err, user.ErrInvalidPreference), errors.Is(err, fx.ErrUnknownCurrency):
+		status = fiber.StatusBadRequest
+	}
+	if status != fiber.StatusInternalServerError {
+		message = err.Error()
+	}
+
+	return ctx.Status(status).JSON(responses.Response{
+		Status:  status,
+		Message: message,
+	})
+}
@@ -0,0 +1,15 @@
+package account
+
+import "github.com/gofiber/fiber/v2"
+
+type AccountController interface {
+	CreateAccount(ctx *fiber.Ctx) error
+	GetAccounts(ctx *fiber.Ctx) error
+	GetAccountById(ctx *fiber.Ctx) error
+	UpdateAccount(ctx *fiber.Ctx) error
+	DeleteAccount(ctx *fiber.Ctx) error
+	GetLedger(ctx *fiber.Ctx) error
+	AssignTransactions(ctx *fiber.Ctx) error
+	ReconcileAccount(ctx *fiber.Ctx) error
+	GetReconciliations(ctx *fiber.Ctx) error
+}
@@ -0,0 +1,23 @@
+package account
+
+import (
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/models"
+)
+
+type AccountStorer interface {
+	InsertAccount(account *models.Account) error
+	GetAccountsByUserId(userId string, includeArchived bool) ([]models.AccountWithBalance, error)
+	GetAccountById(userId, accountId string) (*models.Account, error)
+	IsAccountNameTaken(userId, name, excludeAccountId string) (bool, error)
+	UpdateAccount(account *models.Account) error
+	DeleteAccount(userId, accountId string) error
+	CountAccountTransactions(accountId string) (int64, error)
+	GetAccountBalance(accountId string, until time.Time) (int64, error)
+	GetLedgerEntries(accountId string, start, end time.Time) ([]models.AccountLedgerEntry, error)
+	AssignTransactions(userId, accountId string, transactionIds []string) (int64, error)
+	InsertReconciliation(reconciliation *models.AccountReconciliation) error
+	GetLastMatchedReconciliation(accountId string, before time.Time) (*models.AccountReconciliation, error)
+	GetReconciliations(accountId string) ([]models.AccountReconciliation, error)
+}
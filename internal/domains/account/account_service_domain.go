@@ -0,0 +1,28 @@
+package account
+
+import (
+	"errors"
+
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/models"
+)
+
+var (
+	ErrAccountNotFound          = errors.New("account not found")
+	ErrAccountNameTaken         = errors.New("an account with this name already exists")
+	ErrInvalidReconciliation    = errors.New("statement date is before the account opening date")
+	ErrAccountTransactionsOwner = errors.New("some transactions do not exist or belong to another user")
+)
+
+type AccountManager interface {
+	CreateAccount(userId string, req *requests.AccountRequest) (*models.Account, error)
+	GetAccounts(userId string, req *requests.AccountQuery) (*responses.AccountsResponse, error)
+	GetAccountById(userId, accountId string) (*models.AccountWithBalance, error)
+	UpdateAccount(userId, accountId string, req *requests.AccountRequest) (*models.Account, error)
+	DeleteAccount(userId, accountId string) error
+	GetLedger(userId, accountId string, req *requests.AccountLedgerQuery) (*responses.AccountLedgerResponse, error)
+	AssignTransactions(userId, accountId string, req *requests.AssignAccountTransactionsRequest) (*responses.AssignAccountTransactionsResponse, error)
+	ReconcileAccount(userId, accountId string, req *requests.ReconcileAccountRequest) (*responses.ReconcileAccountResponse, error)
+	GetReconciliations(userId, accountId string) ([]models.AccountReconciliation, error)
+}
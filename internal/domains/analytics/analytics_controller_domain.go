@@ -0,0 +1,11 @@
+package analytics
+
+import "github.com/gofiber/fiber/v2"
+
+type AnalyticsController interface {
+	GetTimeSeries(ctx *fiber.Ctx) error
+	GetCategoryBreakdown(ctx *fiber.Ctx) error
+	GetTopSources(ctx *fiber.Ctx) error
+	GetTagBreakdown(ctx *fiber.Ctx) error
+	GetPeriodComparison(ctx *fiber.Ctx) error
+}
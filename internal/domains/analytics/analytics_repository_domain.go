@@ -0,0 +1,16 @@
+package analytics
+
+import (
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+)
+
+type AnalyticsStorer interface {
+	GetTimeSeries(userId string, req *requests.AnalyticsQuery, truncUnit string, start, end time.Time) ([]responses.TimeSeriesPoint, error)
+	GetCategoryBreakdown(userId string, req *requests.AnalyticsQuery, start, end time.Time) ([]responses.CategoryBreakdownItem, error)
+	GetTopSources(userId string, req *requests.AnalyticsQuery, start, end time.Time) ([]responses.TopSourceItem, error)
+	GetTagBreakdown(userId string, req *requests.AnalyticsQuery, start, end time.Time) ([]responses.TagBreakdownItem, error)
+	GetTaggedTotals(userId string, req *requests.AnalyticsQuery, start, end time.Time) (int64, int64, error)
+}
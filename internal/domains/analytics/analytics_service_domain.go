@@ -0,0 +1,14 @@
+package analytics
+
+import (
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+)
+
+type AnalyticsManager interface {
+	GetTimeSeries(userId string, req *requests.AnalyticsQuery) (*responses.TimeSeriesResponse, error)
+	GetCategoryBreakdown(userId string, req *requests.AnalyticsQuery) (*responses.CategoryBreakdownResponse, error)
+	GetTopSources(userId string, req *requests.AnalyticsQuery) (*responses.TopSourcesResponse, error)
+	GetTagBreakdown(userId string, req *requests.AnalyticsQuery) (*responses.TagBreakdownResponse, error)
+	GetPeriodComparison(userId string, req *requests.AnalyticsQuery) (*responses.PeriodComparisonResponse, error)
+}
@@ -0,0 +1,8 @@
+package anomaly
+
+import "github.com/gofiber/fiber/v2"
+
+type AnomalyController interface {
+	GetAnomalies(ctx *fiber.Ctx) error
+	ScanAnomalies(ctx *fiber.Ctx) error
+}
@@ -0,0 +1,24 @@
+package anomaly
+
+import (
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/models"
+)
+
+// Time ranges are half-open: since is inclusive, until is exclusive
+type AnomalyStorer interface {
+	GetCandidate(transactionId string) (*models.AnomalyCandidate, error)
+	GetCandidates(userId string, since, until time.Time) ([]models.AnomalyCandidate, error)
+	GetCategoryAmounts(userId, categoryId, excludeTransactionId string, since, until time.Time) ([]int64, error)
+	GetSimilarDescriptionAmounts(userId, transactionId string, since, until time.Time, minSimilarity float64) ([]int64, error)
+	GetExpenseAmounts(userId, excludeTransactionId string, since, until time.Time) ([]int64, error)
+	GetExpenseHours(userId, excludeTransactionId string, since, until time.Time) ([]int, error)
+	SumCategoryExpenses(userId, categoryId string, since, until time.Time) (int64, error)
+	GetCategoryMonthlyTotals(userId, categoryId string, since, until time.Time) ([]int64, error)
+	InsertAnomaly(anomaly *models.TransactionAnomaly) (bool, error)
+	GetAnomalies(userId string, req *requests.AnomalyQuery, since, until time.Time) ([]models.TransactionAnomaly, error)
+	CountAnomalies(userId string, req *requests.AnomalyQuery, since, until time.Time) (int64, error)
+	GetUserIdsWithExpensesSince(since time.Time) ([]string, error)
+}
@@ -0,0 +1,23 @@
+package anomaly
+
+import (
+	"context"
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/models"
+)
+
+type AnomalyManager interface {
+	// CheckTransaction runs every detector for a newly inserted transaction and records what it finds
+	CheckTransaction(ctx context.Context, transactionId string) ([]models.TransactionAnomaly, error)
+	// QueueCheck schedules CheckTransaction on a background worker without blocking the caller
+	QueueCheck(transactionId string)
+	// Start runs the workers of QueueCheck, Stop waits for the check in progress and discards the queue
+	Start()
+	Stop()
+	ScanAnomalies(ctx context.Context, userId string, req *requests.AnomalyScanRequest) (*responses.AnomalyScanResponse, error)
+	ScanAnomaliesForAllUsers(ctx context.Context, now time.Time) error
+	GetAnomalies(userId string, req *requests.AnomalyQuery) (*responses.AnomaliesResponse, error)
+}
@@ -0,0 +1,10 @@
+package currency
+
+import "github.com/gofiber/fiber/v2"
+
+type CurrencyController interface {
+	GetCurrencies(ctx *fiber.Ctx) error
+	SetBaseCurrency(ctx *fiber.Ctx) error
+	GetRate(ctx *fiber.Ctx) error
+	UpsertRates(ctx *fiber.Ctx) error
+}
@@ -0,0 +1,11 @@
+package currency
+
+import "github.com/saufiroja/fin-ai/pkg/fx"
+
+type CurrencyStorer interface {
+	fx.RateStore
+	UpsertRates(rates []fx.Rate) error
+	GetBaseCurrency(userId string) (string, error)
+	SetBaseCurrency(userId, code string) error
+	CountUserTransactions(userId string) (int64, error)
+}
@@ -0,0 +1,27 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/pkg/fx"
+)
+
+var (
+	ErrBaseCurrencyLocked = errors.New("the base currency can only be changed before the first transaction is recorded")
+	ErrInvalidRate        = errors.New("invalid exchange rate")
+)
+
+type CurrencyManager interface {
+	GetCurrencies(userId string) (*responses.CurrenciesResponse, error)
+	// GetBaseCurrency is the currency the user's amounts, budgets and analytics are kept in
+	GetBaseCurrency(userId string) (fx.Currency, error)
+	SetBaseCurrency(userId string, req *requests.BaseCurrencyRequest) (*responses.CurrencyMeta, error)
+	GetRate(ctx context.Context, req *requests.ExchangeRateQuery) (*fx.Rate, error)
+	UpsertRates(req *requests.UpsertExchangeRatesRequest) (*responses.UpsertExchangeRatesResponse, error)
+	// ToBase converts an amount in minor units of code into the user's base currency at the rate of date
+	ToBase(ctx context.Context, userId string, amount int64, code string, date time.Time) (*fx.Conversion, error)
+}
@@ -0,0 +1,7 @@
+package forecast
+
+import "github.com/gofiber/fiber/v2"
+
+type ForecastController interface {
+	GetForecast(ctx *fiber.Ctx) error
+}
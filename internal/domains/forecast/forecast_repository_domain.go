@@ -0,0 +1,14 @@
+package forecast
+
+import (
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/models"
+)
+
+type ForecastStorer interface {
+	GetBalance(userId string, until time.Time) (int64, error)
+	GetFirstTransactionDate(userId string) (*time.Time, error)
+	GetDailyEntries(userId string, since, until time.Time, excludeRecurring bool) ([]models.ForecastEntry, error)
+	GetCategoryNames(categoryIds []string) (map[string]string, error)
+}
@@ -0,0 +1,12 @@
+package forecast
+
+import (
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+)
+
+type ForecastManager interface {
+	GetForecast(userId string, req *requests.ForecastQuery, now time.Time) (*responses.ForecastResponse, error)
+}
@@ -4,4 +4,7 @@ import "github.com/saufiroja/fin-ai/internal/models"
 
 type LogMessageManager interface {
 	InsertLogMessage(logMessage *models.LogMessage) error
+	// LogUsage accounts a call without keeping its prompt and response, e.g. embeddings and classifications.
+	// A failure is only logged so it never breaks the flow that made the call.
+	LogUsage(userId, topic, model string, inputTokens, outputTokens int)
 }
@@ -0,0 +1,13 @@
+package prompt_registry
+
+import "github.com/gofiber/fiber/v2"
+
+type PromptRegistryController interface {
+	GetPrompts(ctx *fiber.Ctx) error
+	CreatePromptVersion(ctx *fiber.Ctx) error
+	ActivatePromptVersion(ctx *fiber.Ctx) error
+	GetPromptExperiments(ctx *fiber.Ctx) error
+	StartPromptExperiment(ctx *fiber.Ctx) error
+	EndPromptExperiment(ctx *fiber.Ctx) error
+	GetPromptExperimentResult(ctx *fiber.Ctx) error
+}
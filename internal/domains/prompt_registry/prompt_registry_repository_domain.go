@@ -0,0 +1,23 @@
+package prompt_registry
+
+import (
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/models"
+)
+
+type PromptRegistryStorer interface {
+	GetPromptVersions() ([]models.PromptVersion, error)
+	// InsertPromptVersion reports false when the key, version and locale are already recorded
+	InsertPromptVersion(version *models.PromptVersion) (bool, error)
+	// ActivatePromptVersion serves the version of the key outside experiments, an empty version
+	// deactivates every version of the key
+	ActivatePromptVersion(promptKey, version string) error
+	GetPromptExperiments() ([]models.PromptExperiment, error)
+	GetPromptExperimentById(experimentId string) (*models.PromptExperiment, error)
+	// InsertPromptExperiment starts the experiment now, the database clock is used like for log_messages
+	InsertPromptExperiment(experiment *models.PromptExperiment) error
+	EndPromptExperiment(experimentId string) error
+	// GetPromptVersionOutcomes sums the log_messages rows that used the versions of the key between from and to
+	GetPromptVersionOutcomes(promptKey string, versions []string, from time.Time, to *time.Time) ([]models.PromptVersionOutcome, error)
+}
@@ -0,0 +1,35 @@
+package prompt_registry
+
+import (
+	"errors"
+
+	"github.com/saufiroja/fin-ai/internal/constants/prompt"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/models"
+)
+
+var (
+	ErrInvalidPromptVersion     = errors.New("invalid prompt version")
+	ErrPromptVersionExists      = errors.New("prompt version already exists")
+	ErrPromptVersionNotFound    = errors.New("prompt version not found")
+	ErrPromptExperimentRunning  = errors.New("an experiment is already running for this prompt")
+	ErrPromptExperimentNotFound = errors.New("prompt experiment not found")
+	ErrPromptExperimentEnded    = errors.New("prompt experiment already ended")
+)
+
+type PromptRegistryManager interface {
+	// Resolve returns the prompt the user gets in the locale: the version of a running experiment, the
+	// active version or the built-in prompt. It never fails, the built-in prompt is served when the
+	// registry cannot be read.
+	Resolve(userId, locale string, key prompt.Key) models.ResolvedPrompt
+	// SyncPromptFiles records the versions found in dir as <prompt_key>/<version>/<locale>.txt
+	SyncPromptFiles(dir string) error
+	GetPrompts() ([]responses.PromptSummary, error)
+	CreatePromptVersion(req *requests.CreatePromptVersionRequest) (*models.PromptVersion, error)
+	ActivatePromptVersion(promptKey string, req *requests.ActivatePromptVersionRequest) error
+	GetPromptExperiments() ([]models.PromptExperiment, error)
+	StartPromptExperiment(req *requests.StartPromptExperimentRequest) (*models.PromptExperiment, error)
+	EndPromptExperiment(experimentId string) (*models.PromptExperiment, error)
+	GetPromptExperimentResult(experimentId string) (*responses.PromptExperimentResult, error)
+}
@@ -14,4 +14,9 @@ type ReceiptStorer interface {
 	UpdateReceiptConfirmed(receiptId string, confirmed bool) error
 	CountReceiptsByUserId(userId string, req *requests.GetAllReceiptsQuery) (int64, error)
 	GetAllReceiptsByUserId(userId string, req *requests.GetAllReceiptsQuery) ([]*models.Receipt, error)
+	SearchReceiptsByEmbedding(userId, embedding string, limit int, threshold float64) ([]models.ReceiptWithScore, error)
+	SearchReceiptItemsByEmbedding(userId, embedding string, limit int, threshold float64) ([]models.ReceiptItemWithScore, error)
+	GetUnembeddedReceiptItems(limit int) ([]models.UnembeddedReceiptItem, error)
+	// UpdateReceiptItemEmbeddings sets item_name_embedding by receipt item id
+	UpdateReceiptItemEmbeddings(embeddings map[string]string) error
 }
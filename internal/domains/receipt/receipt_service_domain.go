@@ -1,6 +1,8 @@
 package receipt
 
 import (
+	"context"
+	"errors"
 	"mime/multipart"
 
 	"github.com/saufiroja/fin-ai/internal/contracts/requests"
@@ -8,10 +10,16 @@ import (
 	"github.com/saufiroja/fin-ai/internal/models"
 )
 
+var ErrReceiptNotSplittable = errors.New("receipt cannot be confirmed as a split transaction")
+
 type ReceiptManager interface {
-	UploadReceipt(filePath *multipart.FileHeader, userId string) (*models.Receipt, error)
+	UploadReceipt(ctx context.Context, filePath *multipart.FileHeader, userId string) (*models.Receipt, error)
 	GetReceiptsByUserId(userId string) ([]*models.Receipt, error)
 	GetDetailReceiptUserById(userId string, receiptId string) (*responses.DetailReceiptUserResponse, error)
-	UpdateReceiptConfirmed(userId, receiptId string, confirmed bool) error
+	UpdateReceiptConfirmed(userId, receiptId string, confirmed, split bool) error
 	GetAllReceiptsByUserId(userId string, req *requests.GetAllReceiptsQuery) (*responses.ReceiptResponse, error)
+	SearchSimilarReceipts(userId, embedding string, limit int, threshold float64) ([]models.ReceiptWithScore, error)
+	SearchSimilarReceiptItems(userId, embedding string, limit int, threshold float64) ([]models.ReceiptItemWithScore, error)
+	// BackfillItemEmbeddings embeds the names of receipt items stored without an embedding, so item search finds them
+	BackfillItemEmbeddings(ctx context.Context) (int, error)
 }
@@ -0,0 +1,8 @@
+package recommendation
+
+import "github.com/gofiber/fiber/v2"
+
+type RecommendationController interface {
+	GetRecommendations(ctx *fiber.Ctx) error
+	MarkRecommendationAsRead(ctx *fiber.Ctx) error
+}
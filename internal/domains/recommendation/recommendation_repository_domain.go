@@ -0,0 +1,16 @@
+package recommendation
+
+import (
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/models"
+)
+
+type RecommendationStorer interface {
+	InsertRecommendation(recommendation *models.AIRecommendation) error
+	HasActiveRecommendation(userId, referenceKey string, now time.Time) (bool, error)
+	GetRecommendations(userId string, req *requests.RecommendationQuery, now time.Time) ([]models.AIRecommendation, error)
+	CountRecommendations(userId string, req *requests.RecommendationQuery, now time.Time) (int64, error)
+	MarkRecommendationAsRead(userId, recommendationId string) (int64, error)
+}
@@ -0,0 +1,20 @@
+package recommendation
+
+import (
+	"context"
+	"errors"
+
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/models"
+)
+
+var ErrRecommendationNotFound = errors.New("recommendation not found")
+
+type RecommendationManager interface {
+	// SaveRecommendation stores the recommendation unless an unexpired one with the same reference key exists.
+	// It reports whether a new recommendation was created.
+	SaveRecommendation(ctx context.Context, recommendation *models.AIRecommendation) (bool, error)
+	GetRecommendations(userId string, req *requests.RecommendationQuery) (*responses.RecommendationsResponse, error)
+	MarkRecommendationAsRead(userId, recommendationId string) error
+}
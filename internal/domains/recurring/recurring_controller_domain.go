@@ -0,0 +1,14 @@
+package recurring
+
+import "github.com/gofiber/fiber/v2"
+
+type RecurringController interface {
+	CreateRecurringRule(ctx *fiber.Ctx) error
+	GetRecurringRules(ctx *fiber.Ctx) error
+	GetRecurringRuleById(ctx *fiber.Ctx) error
+	UpdateRecurringRule(ctx *fiber.Ctx) error
+	DeleteRecurringRule(ctx *fiber.Ctx) error
+	SkipOccurrence(ctx *fiber.Ctx) error
+	EditOccurrence(ctx *fiber.Ctx) error
+	GetUpcomingBills(ctx *fiber.Ctx) error
+}
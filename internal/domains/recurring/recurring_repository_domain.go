@@ -0,0 +1,21 @@
+package recurring
+
+import (
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/models"
+)
+
+type RecurringStorer interface {
+	InsertRecurringRule(rule *models.RecurringRule) error
+	GetRecurringRulesByUserId(userId string) ([]*models.RecurringRule, error)
+	GetRecurringRuleById(userId, ruleId string) (*models.RecurringRule, error)
+	UpdateRecurringRule(rule *models.RecurringRule) error
+	DeleteRecurringRule(userId, ruleId string) error
+	FindDueRecurringRules(date time.Time) ([]*models.RecurringRule, error)
+	UpdateNextRunDate(ruleId string, nextRunDate *time.Time, isActive bool) error
+	GetOccurrences(ruleId string, start, end time.Time) ([]*models.RecurringOccurrence, error)
+	UpsertOccurrence(occurrence *models.RecurringOccurrence) error
+	ClaimOccurrence(occurrence *models.RecurringOccurrence) (bool, error)
+	DeletePendingOccurrence(ruleId string, date time.Time) error
+}
@@ -0,0 +1,30 @@
+package recurring
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/models"
+)
+
+var (
+	ErrRecurringRuleNotFound  = errors.New("recurring rule not found")
+	ErrInvalidRecurringRule   = errors.New("invalid recurring rule")
+	ErrOccurrenceNotScheduled = errors.New("date is not an occurrence of this rule")
+	ErrOccurrenceMaterialized = errors.New("occurrence has already been recorded as a transaction")
+)
+
+type RecurringManager interface {
+	CreateRecurringRule(userId string, req *requests.RecurringRuleRequest) (*models.RecurringRule, error)
+	GetRecurringRules(userId string) ([]*models.RecurringRule, error)
+	GetRecurringRuleById(userId, ruleId string) (*models.RecurringRule, error)
+	UpdateRecurringRule(userId, ruleId string, req *requests.RecurringRuleRequest) (*models.RecurringRule, error)
+	DeleteRecurringRule(userId, ruleId string) error
+	SkipOccurrence(userId, ruleId string, req *requests.RecurringOccurrenceRequest) error
+	EditOccurrence(userId, ruleId string, req *requests.RecurringOccurrenceRequest) error
+	GetUpcomingBills(userId string, days int) (*responses.UpcomingBillsResponse, error)
+	MaterializeDueTransactions(ctx context.Context, now time.Time) (*responses.MaterializeRecurringResult, error)
+}
@@ -0,0 +1,9 @@
+package report
+
+import "github.com/gofiber/fiber/v2"
+
+type ReportController interface {
+	GenerateMonthlyReport(ctx *fiber.Ctx) error
+	GetReports(ctx *fiber.Ctx) error
+	GetReportById(ctx *fiber.Ctx) error
+}
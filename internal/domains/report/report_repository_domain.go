@@ -0,0 +1,21 @@
+package report
+
+import (
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/models"
+)
+
+type ReportStorer interface {
+	// UpsertReport inserts the report or replaces the one of the same month, the stored ID is written back
+	UpsertReport(report *models.MonthlyReport) error
+	GetReports(userId string, year, limit, offset int) ([]models.MonthlyReport, error)
+	CountReports(userId string, year int) (int64, error)
+	GetReportById(userId, reportId string) (*models.MonthlyReport, error)
+	GetReportByPeriod(userId string, year, month int) (*models.MonthlyReport, error)
+	GetBudgetUsage(userId string, year, month int) ([]models.BudgetUsage, error)
+	GetGoals(userId string) ([]models.FinancialGoal, error)
+	GetPeriodSummary(userId string, periodStart time.Time) (*models.PeriodSummary, error)
+	InsertPeriodSummary(summary *models.AISummary) error
+	GetUserIdsWithTransactionsBetween(start, end time.Time) ([]string, error)
+}
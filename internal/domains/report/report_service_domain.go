@@ -0,0 +1,23 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+)
+
+var (
+	ErrReportNotFound      = errors.New("report not found")
+	ErrInvalidReportPeriod = errors.New("reports can only be generated for the current or a past month")
+)
+
+type ReportManager interface {
+	GenerateMonthlyReport(ctx context.Context, userId string, req *requests.GenerateReportRequest) (*responses.MonthlyReportResponse, error)
+	GetReports(userId string, req *requests.ReportQuery) (*responses.MonthlyReportsResponse, error)
+	GetReportById(userId, reportId string) (*responses.MonthlyReportResponse, error)
+	// GenerateReportsForAllUsers creates the report of the previous month for users that have none yet
+	GenerateReportsForAllUsers(ctx context.Context, now time.Time) error
+}
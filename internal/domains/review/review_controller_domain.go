@@ -0,0 +1,9 @@
+package review
+
+import "github.com/gofiber/fiber/v2"
+
+type ReviewController interface {
+	GetReviewQueue(ctx *fiber.Ctx) error
+	AcceptReviewItems(ctx *fiber.Ctx) error
+	RecategorizeReviewItems(ctx *fiber.Ctx) error
+}
@@ -0,0 +1,18 @@
+package review
+
+import (
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/models"
+)
+
+type ReviewStorer interface {
+	FindTransactionsForReview(userId string, req *requests.ReviewQueueQuery) ([]models.Transaction, error)
+	CountTransactionsForReview(userId string, threshold float64) (int64, error)
+	FindReceiptItemsForReview(userId string, req *requests.ReviewQueueQuery) ([]*responses.ReviewReceiptItem, error)
+	CountReceiptItemsForReview(userId string, threshold float64) (int64, error)
+	ConfirmTransactions(userId string, transactionIds []string) (int64, error)
+	ConfirmReceiptItems(userId string, receiptItemIds []string) (int64, error)
+	RecategorizeTransactions(userId string, transactionIds []string, categoryId string) (int64, error)
+	RecategorizeReceiptItems(userId string, receiptItemIds []string, categoryId string) (int64, error)
+}
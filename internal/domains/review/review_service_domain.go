@@ -0,0 +1,20 @@
+package review
+
+import (
+	"errors"
+
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+)
+
+var (
+	ErrNoItemsSelected    = errors.New("no items selected for review")
+	ErrCategoryIdRequired = errors.New("category id is required")
+	ErrCategoryNotFound   = errors.New("category not found")
+)
+
+type ReviewManager interface {
+	GetReviewQueue(userId string, req *requests.ReviewQueueQuery) (*responses.ReviewQueueResponse, error)
+	AcceptReviewItems(userId string, req *requests.ReviewActionRequest) (*responses.ReviewActionResponse, error)
+	RecategorizeReviewItems(userId string, req *requests.ReviewActionRequest) (*responses.ReviewActionResponse, error)
+}
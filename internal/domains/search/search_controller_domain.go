@@ -0,0 +1,7 @@
+package search
+
+import "github.com/gofiber/fiber/v2"
+
+type SearchController interface {
+	Search(ctx *fiber.Ctx) error
+}
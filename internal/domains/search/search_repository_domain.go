@@ -0,0 +1,15 @@
+package search
+
+import (
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/models"
+)
+
+type SearchStorer interface {
+	KeywordSearchTransactions(userId string, req *requests.SearchQuery, limit int) ([]models.TransactionWithScore, error)
+	SemanticSearchTransactions(userId, embedding string, req *requests.SearchQuery, limit int) ([]models.TransactionWithScore, error)
+	KeywordSearchReceipts(userId string, req *requests.SearchQuery, limit int) ([]models.ReceiptWithScore, error)
+	SemanticSearchReceipts(userId, embedding string, req *requests.SearchQuery, limit int) ([]models.ReceiptWithScore, error)
+	KeywordSearchReceiptItems(userId string, req *requests.SearchQuery, limit int) ([]models.ReceiptItemWithScore, error)
+	SemanticSearchReceiptItems(userId, embedding string, req *requests.SearchQuery, limit int) ([]models.ReceiptItemWithScore, error)
+}
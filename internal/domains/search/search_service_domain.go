@@ -0,0 +1,10 @@
+package search
+
+import (
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+)
+
+type SearchManager interface {
+	Search(userId string, req *requests.SearchQuery) (*responses.SearchResponse, error)
+}
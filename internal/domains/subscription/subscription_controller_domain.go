@@ -0,0 +1,8 @@
+package subscription
+
+import "github.com/gofiber/fiber/v2"
+
+type SubscriptionController interface {
+	GetSubscriptions(ctx *fiber.Ctx) error
+	DetectSubscriptions(ctx *fiber.Ctx) error
+}
@@ -0,0 +1,12 @@
+package subscription
+
+import (
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/models"
+)
+
+type SubscriptionStorer interface {
+	GetExpenseCharges(userId string, since time.Time, limit int) ([]models.SubscriptionCharge, error)
+	GetUserIdsWithExpensesSince(since time.Time) ([]string, error)
+}
@@ -0,0 +1,14 @@
+package subscription
+
+import (
+	"context"
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+)
+
+type SubscriptionManager interface {
+	// DetectSubscriptions finds repeating charges, saveRecommendations also stores saving tips for them
+	DetectSubscriptions(ctx context.Context, userId string, now time.Time, saveRecommendations bool) (*responses.SubscriptionDetectionResponse, error)
+	DetectSubscriptionsForAllUsers(ctx context.Context, now time.Time) error
+}
@@ -0,0 +1,13 @@
+package tag
+
+import "github.com/gofiber/fiber/v2"
+
+type TagController interface {
+	CreateTag(ctx *fiber.Ctx) error
+	GetTags(ctx *fiber.Ctx) error
+	UpdateTag(ctx *fiber.Ctx) error
+	DeleteTag(ctx *fiber.Ctx) error
+	SetTransactionTags(ctx *fiber.Ctx) error
+	SetReceiptTags(ctx *fiber.Ctx) error
+	SuggestTags(ctx *fiber.Ctx) error
+}
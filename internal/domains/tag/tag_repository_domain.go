@@ -0,0 +1,16 @@
+package tag
+
+import "github.com/saufiroja/fin-ai/internal/models"
+
+type TagStorer interface {
+	InsertTag(tag *models.Tag) error
+	GetTagsByUserId(userId string) ([]models.TagWithUsage, error)
+	GetTagById(userId, tagId string) (*models.Tag, error)
+	IsTagNameTaken(userId, name, excludeTagId string) (bool, error)
+	UpdateTag(tag *models.Tag) error
+	DeleteTag(userId, tagId string) error
+	TransactionExists(userId, transactionId string) (bool, error)
+	ReceiptExists(userId, receiptId string) (bool, error)
+	ReplaceTransactionTags(userId, transactionId string, names []string) error
+	ReplaceReceiptTags(userId, receiptId string, names []string) error
+}
@@ -0,0 +1,26 @@
+package tag
+
+import (
+	"errors"
+
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/models"
+)
+
+var (
+	ErrTagNotFound       = errors.New("tag not found")
+	ErrTagNameTaken      = errors.New("tag name already exists")
+	ErrInvalidTagName    = errors.New("tag name may only contain letters, numbers, dashes and underscores")
+	ErrTagTargetNotFound = errors.New("transaction or receipt not found")
+)
+
+type TagManager interface {
+	CreateTag(userId string, req *requests.TagRequest) (*models.Tag, error)
+	GetTags(userId string) ([]models.TagWithUsage, error)
+	UpdateTag(userId, tagId string, req *requests.TagRequest) (*models.Tag, error)
+	DeleteTag(userId, tagId string) error
+	SetTransactionTags(userId, transactionId string, req *requests.AssignTagsRequest) (*responses.TagAssignmentResponse, error)
+	SetReceiptTags(userId, receiptId string, req *requests.AssignTagsRequest) (*responses.TagAssignmentResponse, error)
+	SuggestTags(userId string, req *requests.TagSuggestionRequest) (*responses.TagSuggestionResponse, error)
+}
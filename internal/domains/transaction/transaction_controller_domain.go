@@ -9,4 +9,7 @@ type TransactionController interface {
 	UpdateTransaction(ctx *fiber.Ctx) error
 	DeleteTransaction(ctx *fiber.Ctx) error
 	OverviewTransactions(ctx *fiber.Ctx) error
+	FilterTransactions(ctx *fiber.Ctx) error
+	GetTransactionsStats(ctx *fiber.Ctx) error
+	ExportTransactions(ctx *fiber.Ctx) error
 }
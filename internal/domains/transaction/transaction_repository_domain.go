@@ -10,8 +10,13 @@ type TransactionStorer interface {
 	InsertTransaction(transaction *models.Transaction) error
 	GetTransactionByID(id string) (*models.Transaction, error)
 	UpdateTransaction(transaction *models.Transaction) error
-	DeleteTransaction(id string) error
+	DeleteTransaction(userId, id string) error
 	GetAllTransactions(req *requests.GetAllTransactionsQuery, userId string) ([]models.Transaction, error)
 	CountAllTransactions(req *requests.GetAllTransactionsQuery, userId string) (int64, error)
 	GetTransactionsStats(userId string, req *requests.OverviewTransactionsQuery) (*responses.OverviewTransactions, error)
+	FilterTransactions(userId string, filter *requests.TransactionFilter, limit, offset int) ([]models.Transaction, error)
+	CountFilteredTransactions(userId string, filter *requests.TransactionFilter) (int64, error)
+	SearchTransactionsByEmbedding(userId, embedding string, limit int, threshold float64) ([]models.TransactionWithScore, error)
+	GetTransactionSplits(transactionIds []string) ([]models.TransactionSplit, error)
+	ExportTransactions(req *requests.GetAllTransactionsQuery, userId string, fn func(row *models.TransactionExport) error) error
 }
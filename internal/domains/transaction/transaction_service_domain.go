@@ -1,17 +1,28 @@
 package transaction
 
 import (
+	"errors"
+
 	"github.com/saufiroja/fin-ai/internal/contracts/requests"
 	"github.com/saufiroja/fin-ai/internal/contracts/responses"
 	"github.com/saufiroja/fin-ai/internal/models"
 )
 
+var (
+	ErrInvalidTransactionType = errors.New("transaction type must be income or expense, use transfers to move money between accounts")
+	ErrTransferLegUpdate      = errors.New("transaction is part of a transfer, edit or delete the transfer instead")
+	ErrInvalidSplits          = errors.New("invalid split lines")
+)
+
 type TransactionManager interface {
 	InsertTransaction(req *requests.TransactionRequest) error
 	UpdateTransaction(transactionId string, req *requests.UpdateTransactionRequest) error
-	DeleteTransaction(id string) error
+	DeleteTransaction(userId, id string) error
 	GetAllTransactions(req *requests.GetAllTransactionsQuery, userId string) (*responses.GetAllTransactionsResponse, error)
-	GetTransactionsStats() (*models.Transaction, error)
+	GetTransactionsStats(userId string, req *requests.OverviewTransactionsQuery) (*responses.TransactionStatsResponse, error)
 	GetDetailedTransaction(id string) (*models.Transaction, error)
 	OverviewTransactions(userId string, req *requests.OverviewTransactionsQuery) (*responses.OverviewTransactionsResponse, error)
+	FilterTransactions(userId string, req *requests.TransactionFilterRequest) (*responses.TransactionFilterResponse, error)
+	SearchSimilarTransactions(userId, embedding string, limit int, threshold float64) ([]models.TransactionWithScore, error)
+	ExportTransactions(userId string, req *requests.ExportTransactionsQuery) (*responses.TransactionExport, error)
 }
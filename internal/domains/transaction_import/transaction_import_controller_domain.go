@@ -0,0 +1,12 @@
+package transaction_import
+
+import "github.com/gofiber/fiber/v2"
+
+type TransactionImportController interface {
+	UploadImport(ctx *fiber.Ctx) error
+	GetImports(ctx *fiber.Ctx) error
+	GetImportById(ctx *fiber.Ctx) error
+	ValidateImport(ctx *fiber.Ctx) error
+	CommitImport(ctx *fiber.Ctx) error
+	RevertImport(ctx *fiber.Ctx) error
+}
@@ -0,0 +1,19 @@
+package transaction_import
+
+import (
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/models"
+)
+
+type TransactionImportStorer interface {
+	InsertImport(imp *models.TransactionImport) error
+	GetImports(userId, status string, limit, offset int) ([]models.TransactionImport, error)
+	CountImports(userId, status string) (int64, error)
+	GetImportById(userId, importId string) (*models.TransactionImport, error)
+	// GetExistingTransactions returns the income and expense transactions in [start, end) for duplicate detection
+	GetExistingTransactions(userId string, start, end time.Time) ([]models.Transaction, error)
+	GetExistingExternalIds(userId string, externalIds []string) ([]string, error)
+	CommitImport(imp *models.TransactionImport, transactions []*models.Transaction) error
+	RevertImport(userId, importId string, revertedAt time.Time) (int64, error)
+}
@@ -0,0 +1,28 @@
+package transaction_import
+
+import (
+	"errors"
+	"mime/multipart"
+
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/models"
+)
+
+var (
+	ErrImportNotFound       = errors.New("import not found")
+	ErrInvalidImport        = errors.New("invalid import")
+	ErrImportNotPending     = errors.New("import was already committed or reverted")
+	ErrImportNotCommitted   = errors.New("only a committed import can be reverted")
+	ErrImportHasInvalidRows = errors.New("import has invalid rows, fix the mapping or set skip_invalid")
+)
+
+type TransactionImportManager interface {
+	// UploadImport reads a CSV/XLSX sheet or a bank statement, format forces a bank statement parser
+	UploadImport(userId string, file *multipart.FileHeader, format string) (*responses.ImportUploadResponse, error)
+	GetImports(userId string, req *requests.ImportQuery) (*responses.TransactionImportsResponse, error)
+	GetImportById(userId, importId string) (*models.TransactionImport, error)
+	ValidateImport(userId, importId string, req *requests.ImportMappingRequest) (*responses.ImportValidationResponse, error)
+	CommitImport(userId, importId string, req *requests.CommitImportRequest) (*models.TransactionImport, error)
+	RevertImport(userId, importId string) (*models.TransactionImport, error)
+}
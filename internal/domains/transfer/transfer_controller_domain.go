@@ -0,0 +1,10 @@
+package transfer
+
+import "github.com/gofiber/fiber/v2"
+
+type TransferController interface {
+	CreateTransfer(ctx *fiber.Ctx) error
+	GetTransfers(ctx *fiber.Ctx) error
+	GetTransferById(ctx *fiber.Ctx) error
+	DeleteTransfer(ctx *fiber.Ctx) error
+}
@@ -0,0 +1,12 @@
+package transfer
+
+import "github.com/saufiroja/fin-ai/internal/models"
+
+type TransferStorer interface {
+	InsertTransfer(transfer *models.Transfer, legs []*models.Transaction) error
+	GetTransfers(userId, accountId string, limit, offset int) ([]models.TransferWithAccounts, error)
+	CountTransfers(userId, accountId string) (int64, error)
+	GetTransferById(userId, transferId string) (*models.TransferWithAccounts, error)
+	GetTransferLegs(transferId string) ([]models.Transaction, error)
+	DeleteTransfer(userId, transferId string) error
+}
@@ -0,0 +1,20 @@
+package transfer
+
+import (
+	"errors"
+
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+)
+
+var (
+	ErrTransferNotFound = errors.New("transfer not found")
+	ErrInvalidTransfer  = errors.New("invalid transfer")
+)
+
+type TransferManager interface {
+	CreateTransfer(userId string, req *requests.TransferRequest) (*responses.TransferResponse, error)
+	GetTransfers(userId string, req *requests.TransferQuery) (*responses.TransfersResponse, error)
+	GetTransferById(userId, transferId string) (*responses.TransferResponse, error)
+	DeleteTransfer(userId, transferId string) error
+}
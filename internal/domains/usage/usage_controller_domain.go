@@ -0,0 +1,8 @@
+package usage
+
+import "github.com/gofiber/fiber/v2"
+
+type UsageController interface {
+	GetUsage(ctx *fiber.Ctx) error
+	GetMyUsage(ctx *fiber.Ctx) error
+}
@@ -0,0 +1,8 @@
+package usage
+
+import "github.com/saufiroja/fin-ai/internal/models"
+
+type UsageStorer interface {
+	GetUsageGroups(filter *models.UsageFilter) ([]models.UsageGroup, error)
+	GetUsageTotal(filter *models.UsageFilter) (*models.UsageGroup, error)
+}
@@ -0,0 +1,17 @@
+package usage
+
+import (
+	"errors"
+
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+)
+
+var ErrInvalidUsagePeriod = errors.New("from must not be after to")
+
+type UsageManager interface {
+	// GetUsage sums the logged LLM and embedding calls of every user
+	GetUsage(query *requests.UsageQuery) (*responses.UsageResponse, error)
+	// GetUserUsage sums the calls made for one user, query.UserId is ignored
+	GetUserUsage(userId string, query *requests.UsageQuery) (*responses.UsageResponse, error)
+}
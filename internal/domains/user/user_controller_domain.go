@@ -6,4 +6,8 @@ type UserController interface {
 	GetMe(c *fiber.Ctx) error
 	UpdateUserById(ctx *fiber.Ctx) error
 	DeleteUserById(ctx *fiber.Ctx) error
+	CancelAccountDeletion(ctx *fiber.Ctx) error
+	ExportUserData(ctx *fiber.Ctx) error
+	GetPreferences(ctx *fiber.Ctx) error
+	UpdatePreferences(ctx *fiber.Ctx) error
 }
@@ -1,6 +1,9 @@
 package user
 
 import (
+	"encoding/json"
+	"time"
+
 	"github.com/saufiroja/fin-ai/internal/contracts/requests"
 	"github.com/saufiroja/fin-ai/internal/contracts/responses"
 	"github.com/saufiroja/fin-ai/internal/models"
@@ -12,4 +15,12 @@ type UserStorer interface {
 	FindUserById(userId string) (*responses.FindUserById, error)
 	UpdateUserById(userId string, req *requests.UpdateUserRequest) error
 	DeleteUserById(userId string) error
+	ScheduleDeletion(deletion *responses.AccountDeletion) error
+	CancelDeletion(userId string) (bool, error)
+	GetUserIdsDueForDeletion(now time.Time) ([]string, error)
+	ExportUserTable(userId, table string, fn func(row json.RawMessage) error) error
+	// GetPreferences reads users.ai_preferences over the defaults
+	GetPreferences(userId string) (*models.UserPreferences, error)
+	// UpdatePreferences also sets the base currency when it is not empty, in the same transaction
+	UpdatePreferences(userId string, prefs *models.UserPreferences, baseCurrency string) error
 }
@@ -1,12 +1,29 @@
 package user
 
 import (
+	"context"
+	"errors"
+	"time"
+
 	"github.com/saufiroja/fin-ai/internal/contracts/requests"
 	"github.com/saufiroja/fin-ai/internal/contracts/responses"
 )
 
+var (
+	ErrUserNotFound                = errors.New("user not found")
+	ErrAccountDeletionNotScheduled = errors.New("no account deletion is scheduled")
+	ErrInvalidPreference           = errors.New("invalid preference")
+)
+
 type UserManager interface {
 	UpdateUserById(userId string, req *requests.UpdateUserRequest) error
-	DeleteUserById(userId string) error
+	// RequestAccountDeletion schedules the account for deletion after the grace period
+	RequestAccountDeletion(userId string) (*responses.AccountDeletion, error)
+	CancelAccountDeletion(userId string) error
+	ExportUserData(userId string) (*responses.UserDataExport, error)
+	// PurgeDueAccounts deletes the accounts whose grace period has passed, including their stored files
+	PurgeDueAccounts(ctx context.Context, now time.Time) error
 	GetMe(userId string) (*responses.FindUserById, error)
+	GetPreferences(userId string) (*responses.UserPreferences, error)
+	UpdatePreferences(userId string, req *requests.UpdatePreferencesRequest) (*responses.UserPreferences, error)
 }
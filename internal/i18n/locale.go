@@ -0,0 +1,58 @@
+// Package i18n resolves the locale of a request and translates the API messages. The API is written in
+// English, Indonesian is the default locale of the AI prompts.
+package i18n
+
+import (
+	"context"
+
+	"golang.org/x/text/language"
+)
+
+// Supported locales
+const (
+	English    = "en"
+	Indonesian = "id"
+)
+
+// Default is the locale used when neither the user nor the request asks for one
+const Default = Indonesian
+
+// Supported lists every supported locale
+var Supported = []string{English, Indonesian}
+
+var matcher = language.NewMatcher([]language.Tag{language.English, language.Indonesian})
+
+// Match returns the supported locale of the first candidate that matches one, or the fallback. A candidate
+// is a language tag ("en", "id-ID") or an Accept-Language header value ("id-ID,id;q=0.9,en;q=0.8").
+func Match(fallback string, candidates ...string) string {
+	for _, candidate := range candidates {
+		if candidate == "" {
+			continue
+		}
+		tags, _, err := language.ParseAcceptLanguage(candidate)
+		if err != nil || len(tags) == 0 {
+			continue
+		}
+		_, index, confidence := matcher.Match(tags...)
+		if confidence != language.No {
+			return Supported[index]
+		}
+	}
+	return fallback
+}
+
+type localeKey struct{}
+
+// WithLocale stores the locale the request asked for in the context
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// FromContext returns the locale the request asked for, empty when it did not ask for a supported one
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	locale, _ := ctx.Value(localeKey{}).(string)
+	return locale
+}
@@ -0,0 +1,206 @@
+package i18n
+
+import "strings"
+
+// messages translates the English API messages, keyed by locale and then by the English message.
+// English is the source language so it has no entries.
+var messages = map[string]map[string]string{
+	Indonesian: {
+		// request and authorization errors
+		"Invalid request":                      "Permintaan tidak valid",
+		"Invalid request body":                 "Body permintaan tidak valid",
+		"Invalid query parameters":             "Parameter query tidak valid",
+		"Validation error":                     "Kesalahan validasi",
+		"Unauthorized":                         "Tidak terautentikasi",
+		"No token provided":                    "Token tidak ditemukan",
+		"Invalid token":                        "Token tidak valid",
+		"Invalid signing method":               "Metode tanda tangan tidak valid",
+		"Invalid refresh token":                "Refresh token tidak valid",
+		"Refresh token not found":              "Refresh token tidak ditemukan",
+		"User ID is required":                  "ID user wajib diisi",
+		"User ID not found in token":           "ID user tidak ditemukan di token",
+		"Category ID is required":              "ID kategori wajib diisi",
+		"Transaction ID is required":           "ID transaksi wajib diisi",
+		"Amount or description is required":    "Nominal atau deskripsi wajib diisi",
+		"Query or filter is required":          "Query atau filter wajib diisi",
+		"Recommendation not found":             "Rekomendasi tidak ditemukan",
+		"You can only delete your own account": "Kamu hanya bisa menghapus akunmu sendiri",
+
+		// fallback messages of internal errors
+		"Failed to accept review items":            "Gagal menerima item review",
+		"Failed to assign transactions":            "Gagal menetapkan transaksi",
+		"Failed to commit import":                  "Gagal menyimpan impor",
+		"Failed to create account":                 "Gagal membuat rekening",
+		"Failed to create category":                "Gagal membuat kategori",
+		"Failed to create chat session":            "Gagal membuat sesi chat",
+		"Failed to create recurring rule":          "Gagal membuat aturan berulang",
+		"Failed to create tag":                     "Gagal membuat tag",
+		"Failed to create transaction":             "Gagal membuat transaksi",
+		"Failed to create transfer":                "Gagal membuat transfer",
+		"Failed to delete account":                 "Gagal menghapus rekening",
+		"Failed to delete category":                "Gagal menghapus kategori",
+		"Failed to delete recurring rule":          "Gagal menghapus aturan berulang",
+		"Failed to delete tag":                     "Gagal menghapus tag",
+		"Failed to delete transaction":             "Gagal menghapus transaksi",
+		"Failed to delete transfer":                "Gagal menghapus transfer",
+		"Failed to detect subscriptions":           "Gagal mendeteksi langganan",
+		"Failed to edit occurrence":                "Gagal mengubah kejadian",
+		"Failed to export personal data":           "Gagal mengekspor data pribadi",
+		"Failed to export transactions":            "Gagal mengekspor transaksi",
+		"Failed to filter transactions":            "Gagal memfilter transaksi",
+		"Failed to find chat sessions":             "Gagal mencari sesi chat",
+		"Failed to forecast cash flow":             "Gagal memproyeksikan arus kas",
+		"Failed to generate report":                "Gagal membuat laporan",
+		"Failed to get file from request":          "Gagal mengambil file dari permintaan",
+		"Failed to get preferences":                "Gagal mengambil preferensi",
+		"Failed to get receipt details":            "Gagal mengambil detail struk",
+		"Failed to get receipts":                   "Gagal mengambil struk",
+		"Failed to get user information":           "Gagal mengambil informasi user",
+		"Failed to login user":                     "Gagal login",
+		"Failed to logout user":                    "Gagal logout",
+		"Failed to mark recommendation as read":    "Gagal menandai rekomendasi sebagai dibaca",
+		"Failed to recategorize review items":      "Gagal mengubah kategori item review",
+		"Failed to reconcile account":              "Gagal merekonsiliasi rekening",
+		"Failed to register user":                  "Gagal mendaftarkan user",
+		"Failed to retrieve account":               "Gagal mengambil rekening",
+		"Failed to retrieve account ledger":        "Gagal mengambil buku besar rekening",
+		"Failed to retrieve accounts":              "Gagal mengambil daftar rekening",
+		"Failed to retrieve anomalies":             "Gagal mengambil anomali",
+		"Failed to retrieve categories":            "Gagal mengambil kategori",
+		"Failed to retrieve category breakdown":    "Gagal mengambil rincian kategori",
+		"Failed to retrieve chat session details":  "Gagal mengambil detail sesi chat",
+		"Failed to retrieve currencies":            "Gagal mengambil daftar mata uang",
+		"Failed to retrieve exchange rate":         "Gagal mengambil kurs",
+		"Failed to retrieve import":                "Gagal mengambil impor",
+		"Failed to retrieve imports":               "Gagal mengambil daftar impor",
+		"Failed to retrieve overview transactions": "Gagal mengambil ringkasan transaksi",
+		"Failed to retrieve period comparison":     "Gagal mengambil perbandingan periode",
+		"Failed to retrieve recommendations":       "Gagal mengambil rekomendasi",
+		"Failed to retrieve reconciliations":       "Gagal mengambil rekonsiliasi",
+		"Failed to retrieve recurring rule":        "Gagal mengambil aturan berulang",
+		"Failed to retrieve recurring rules":       "Gagal mengambil daftar aturan berulang",
+		"Failed to retrieve report":                "Gagal mengambil laporan",
+		"Failed to retrieve reports":               "Gagal mengambil daftar laporan",
+		"Failed to retrieve review queue":          "Gagal mengambil antrean review",
+		"Failed to retrieve tag breakdown":         "Gagal mengambil rincian tag",
+		"Failed to retrieve tags":                  "Gagal mengambil tag",
+		"Failed to retrieve time series":           "Gagal mengambil deret waktu",
+		"Failed to retrieve top sources":           "Gagal mengambil sumber teratas",
+		"Failed to retrieve transaction details":   "Gagal mengambil detail transaksi",
+		"Failed to retrieve transaction stats":     "Gagal mengambil statistik transaksi",
+		"Failed to retrieve transactions":          "Gagal mengambil transaksi",
+		"Failed to retrieve transfer":              "Gagal mengambil transfer",
+		"Failed to retrieve transfers":             "Gagal mengambil daftar transfer",
+		"Failed to retrieve upcoming bills":        "Gagal mengambil tagihan mendatang",
+		"Failed to revert import":                  "Gagal membatalkan impor",
+		"Failed to save exchange rates":            "Gagal menyimpan kurs",
+		"Failed to scan anomalies":                 "Gagal memindai anomali",
+		"Failed to search":                         "Gagal melakukan pencarian",
+		"Failed to send chat message":              "Gagal mengirim pesan chat",
+		"Failed to set base currency":              "Gagal mengatur mata uang dasar",
+		"Failed to skip occurrence":                "Gagal melewati kejadian",
+		"Failed to suggest tags":                   "Gagal menyarankan tag",
+		"Failed to tag receipt":                    "Gagal memberi tag pada struk",
+		"Failed to tag transaction":                "Gagal memberi tag pada transaksi",
+		"Failed to update account":                 "Gagal memperbarui rekening",
+		"Failed to update category":                "Gagal memperbarui kategori",
+		"Failed to update preferences":             "Gagal memperbarui preferensi",
+		"Failed to update receipt confirmation":    "Gagal memperbarui konfirmasi struk",
+		"Failed to update recurring rule":          "Gagal memperbarui aturan berulang",
+		"Failed to update tag":                     "Gagal memperbarui tag",
+		"Failed to update transaction":             "Gagal memperbarui transaksi",
+		"Failed to upload import file":             "Gagal mengunggah file impor",
+		"Failed to upload receipt":                 "Gagal mengunggah struk",
+		"Failed to validate import":                "Gagal memvalidasi impor",
+
+		// domain errors returned to the client
+		"account not found":                        "rekening tidak ditemukan",
+		"an account with this name already exists": "rekening dengan nama ini sudah ada",
+		"amount":                                                             "nominal",
+		"both debit and credit have a value":                                 "debit dan kredit sama-sama terisi",
+		"debit and credit are empty":                                         "debit dan kredit kosong",
+		"value is zero":                                                      "nilainya nol",
+		"category id is required":                                            "ID kategori wajib diisi",
+		"chat session not found":                                             "sesi chat tidak ditemukan",
+		"date is not an occurrence of this rule":                             "tanggal ini bukan kejadian dari aturan ini",
+		"file exceeds the maximum size limit of 10MB":                        "ukuran file melebihi batas maksimum 10MB",
+		"import has invalid rows, fix the mapping or set skip_invalid":       "impor memiliki baris tidak valid, perbaiki pemetaan atau atur skip_invalid",
+		"import not found":                                                   "impor tidak ditemukan",
+		"import was already committed or reverted":                           "impor sudah disimpan atau dibatalkan",
+		"invalid email or password":                                          "email atau password salah",
+		"invalid exchange rate":                                              "kurs tidak valid",
+		"invalid import":                                                     "impor tidak valid",
+		"invalid preference":                                                 "preferensi tidak valid",
+		"invalid recurring rule":                                             "aturan berulang tidak valid",
+		"invalid refresh token":                                              "refresh token tidak valid",
+		"invalid split lines":                                                "rincian split tidak valid",
+		"invalid token claims":                                               "klaim token tidak valid",
+		"invalid transfer":                                                   "transfer tidak valid",
+		"no account deletion is scheduled":                                   "tidak ada penghapusan akun yang dijadwalkan",
+		"no exchange rate available":                                         "kurs tidak tersedia",
+		"no items selected for review":                                       "tidak ada item yang dipilih untuk direview",
+		"occurrence has already been recorded as a transaction":              "kejadian ini sudah dicatat sebagai transaksi",
+		"only a committed import can be reverted":                            "hanya impor yang sudah disimpan yang bisa dibatalkan",
+		"query or filter is required":                                        "query atau filter wajib diisi",
+		"receipt cannot be confirmed as a split transaction":                 "struk tidak bisa dikonfirmasi sebagai transaksi split",
+		"recommendation not found":                                           "rekomendasi tidak ditemukan",
+		"recurring rule not found":                                           "aturan berulang tidak ditemukan",
+		"report not found":                                                   "laporan tidak ditemukan",
+		"reports can only be generated for the current or a past month":      "laporan hanya bisa dibuat untuk bulan ini atau bulan sebelumnya",
+		"some transactions do not exist or belong to another user":           "beberapa transaksi tidak ada atau milik user lain",
+		"statement date is before the account opening date":                  "tanggal rekening koran sebelum tanggal pembukaan rekening",
+		"tag name already exists":                                            "nama tag sudah ada",
+		"tag name may only contain letters, numbers, dashes and underscores": "nama tag hanya boleh berisi huruf, angka, tanda hubung dan garis bawah",
+		"tag not found":                                                      "tag tidak ditemukan",
+		"the base currency can only be changed before the first transaction is recorded": "mata uang dasar hanya bisa diubah sebelum transaksi pertama dicatat",
+		"transaction is part of a transfer, edit or delete the transfer instead":         "transaksi ini bagian dari transfer, ubah atau hapus transfernya",
+		"transaction not found":            "transaksi tidak ditemukan",
+		"transaction or receipt not found": "transaksi atau struk tidak ditemukan",
+		"transaction type must be income or expense, use transfers to move money between accounts": "tipe transaksi harus income atau expense, gunakan transfer untuk memindahkan uang antar rekening",
+		"transfer not found":        "transfer tidak ditemukan",
+		"unsupported currency code": "kode mata uang tidak didukung",
+		"user not found":            "user tidak ditemukan",
+
+		// admin endpoints
+		"Forbidden":                                        "Akses ditolak",
+		"Admin access required":                            "Perlu akses admin",
+		"Failed to activate prompt version":                "Gagal mengaktifkan versi prompt",
+		"Failed to create prompt version":                  "Gagal membuat versi prompt",
+		"Failed to end prompt experiment":                  "Gagal menghentikan eksperimen prompt",
+		"Failed to retrieve prompt experiment result":      "Gagal mengambil hasil eksperimen prompt",
+		"Failed to retrieve prompt experiments":            "Gagal mengambil daftar eksperimen prompt",
+		"Failed to retrieve prompts":                       "Gagal mengambil daftar prompt",
+		"Failed to start prompt experiment":                "Gagal memulai eksperimen prompt",
+		"an experiment is already running for this prompt": "eksperimen untuk prompt ini sedang berjalan",
+		"invalid prompt version":                           "versi prompt tidak valid",
+		"prompt experiment already ended":                  "eksperimen prompt sudah dihentikan",
+		"prompt experiment not found":                      "eksperimen prompt tidak ditemukan",
+		"prompt version already exists":                    "versi prompt sudah ada",
+		"prompt version not found":                         "versi prompt tidak ditemukan",
+
+		// usage endpoints
+		"Failed to retrieve usage":  "Gagal mengambil data pemakaian AI",
+		"from must not be after to": "from tidak boleh setelah to",
+	},
+}
+
+// Translate returns the message in the locale. A message made of parts separated by ": ", like a wrapped
+// error, is translated part by part, parts without a translation are kept in English.
+func Translate(locale, message string) string {
+	catalog, ok := messages[locale]
+	if !ok {
+		return message
+	}
+	if translated, ok := catalog[message]; ok {
+		return translated
+	}
+
+	prefix, rest, found := strings.Cut(message, ": ")
+	if !found {
+		return message
+	}
+	if translated, ok := catalog[prefix]; ok {
+		prefix = translated
+	}
+	return prefix + ": " + Translate(locale, rest)
+}
@@ -0,0 +1,61 @@
+package i18n
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMessagesTranslateEveryKey(t *testing.T) {
+	// English is the source language, every other locale must translate every message known to any locale
+	keys := make(map[string]bool)
+	for locale, catalog := range messages {
+		if !slices.Contains(Supported, locale) {
+			t.Errorf("messages has unsupported locale %s", locale)
+		}
+		for key := range catalog {
+			keys[key] = true
+		}
+	}
+
+	for _, locale := range Supported {
+		if locale == English {
+			continue
+		}
+		catalog, ok := messages[locale]
+		if !ok {
+			t.Errorf("locale %s has no messages", locale)
+			continue
+		}
+		for key := range keys {
+			translated, ok := catalog[key]
+			if !ok {
+				t.Errorf("%q is missing in locale %s", key, locale)
+				continue
+			}
+			if strings.TrimSpace(translated) == "" {
+				t.Errorf("%q is empty in locale %s", key, locale)
+			}
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		locale  string
+		message string
+		want    string
+	}{
+		{English, "Invalid request body", "Invalid request body"},
+		{Indonesian, "Invalid request body", "Body permintaan tidak valid"},
+		{Indonesian, "Validation error: amount is required", "Kesalahan validasi: amount is required"},
+		{Indonesian, "Something new", "Something new"},
+		{"fr", "Invalid request body", "Invalid request body"},
+	}
+
+	for _, tt := range tests {
+		if got := Translate(tt.locale, tt.message); got != tt.want {
+			t.Errorf("Translate(%q, %q) = %q, want %q", tt.locale, tt.message, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/saufiroja/fin-ai/config"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+)
+
+// Admin lets through the users whose email is listed in ADMIN_EMAILS, it runs after Authorization
+func Admin(conf *config.AppConfig) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		email, _ := ctx.Locals("email").(string)
+		if email == "" || !slices.Contains(conf.Admin.Emails, strings.ToLower(email)) {
+			return ctx.Status(fiber.StatusForbidden).JSON(responses.Response{
+				Status:  fiber.StatusForbidden,
+				Message: "Forbidden: Admin access required",
+			})
+		}
+
+		return ctx.Next()
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/saufiroja/fin-ai/internal/i18n"
+)
+
+// localizedResponse mirrors responses.Response but keeps data and pagination as they were encoded
+type localizedResponse struct {
+	Status     int             `json:"status"`
+	Message    string          `json:"message"`
+	Data       json.RawMessage `json:"data,omitempty"`
+	Pagination json.RawMessage `json:"pagination,omitempty"`
+}
+
+// Localization reads the locale from the Accept-Language header. The locale is stored in the user context
+// for the AI prompts and in the "locale" local, error messages of the response are translated to it.
+func Localization() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		requested := i18n.Match("", ctx.Get(fiber.HeaderAcceptLanguage))
+		ctx.SetUserContext(i18n.WithLocale(ctx.UserContext(), requested))
+		ctx.Locals("locale", requested)
+		ctx.Vary(fiber.HeaderAcceptLanguage)
+
+		if err := ctx.Next(); err != nil {
+			return err
+		}
+
+		if requested == "" || requested == i18n.English || ctx.Response().StatusCode() < fiber.StatusBadRequest {
+			return nil
+		}
+		if !strings.HasPrefix(string(ctx.Response().Header.ContentType()), fiber.MIMEApplicationJSON) {
+			return nil
+		}
+
+		var body localizedResponse
+		if err := json.Unmarshal(ctx.Response().Body(), &body); err != nil || body.Message == "" {
+			return nil
+		}
+		body.Message = i18n.Translate(requested, body.Message)
+		return ctx.JSON(body)
+	}
+}
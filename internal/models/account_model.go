@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/constants"
+)
+
+type Account struct {
+	AccountId      string                `json:"account_id"`
+	UserId         string                `json:"user_id"`
+	Name           string                `json:"name"`
+	Type           constants.AccountType `json:"type"`
+	Institution    string                `json:"institution"`
+	OpeningBalance int64                 `json:"opening_balance"`
+	OpeningDate    time.Time             `json:"opening_date"`
+	IsArchived     bool                  `json:"is_archived"`
+	// CurrencyCode is the default currency of transactions paid from the account, balances are kept
+	// in the user's base currency
+	CurrencyCode string    `json:"currency_code"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// AccountWithBalance is an account with the balance of all its transactions since the opening date
+type AccountWithBalance struct {
+	Account
+	Balance          int64 `json:"balance"`
+	TransactionCount int64 `json:"transaction_count"`
+}
+
+// AccountLedgerEntry is a transaction with the account balance right after it
+type AccountLedgerEntry struct {
+	TransactionId   string                 `json:"transaction_id"`
+	CategoryId      string                 `json:"category_id"`
+	Type            constants.TypeCategory `json:"type"`
+	Description     string                 `json:"description"`
+	Amount          int64                  `json:"amount"`
+	TransactionDate time.Time              `json:"transaction_date"`
+	RunningBalance  int64                  `json:"running_balance"`
+}
+
+type AccountReconciliation struct {
+	ReconciliationId string    `json:"reconciliation_id"`
+	AccountId        string    `json:"account_id"`
+	UserId           string    `json:"user_id"`
+	StatementDate    time.Time `json:"statement_date"`
+	StatementBalance int64     `json:"statement_balance"`
+	ComputedBalance  int64     `json:"computed_balance"`
+	Difference       int64     `json:"difference"`
+	CreatedAt        time.Time `json:"created_at"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/constants"
+)
+
+type TransactionAnomaly struct {
+	AnomalyId     string                `json:"anomaly_id"`
+	UserId        string                `json:"user_id"`
+	TransactionId *string               `json:"transaction_id,omitempty"`
+	CategoryId    *string               `json:"category_id,omitempty"`
+	AnomalyType   constants.AnomalyType `json:"anomaly_type"`
+	ReferenceKey  string                `json:"-"`
+	Reason        string                `json:"reason"`
+	ObservedValue int64                 `json:"observed_value"`
+	ExpectedValue int64                 `json:"expected_value"`
+	Score         float64               `json:"score"`
+	SampleSize    int                   `json:"sample_size"`
+	OccurredAt    time.Time             `json:"occurred_at"`
+	DetectedAt    time.Time             `json:"detected_at"`
+	Description   string                `json:"description,omitempty"`   // Transaction description, joined for display
+	CategoryName  string                `json:"category_name,omitempty"` // Joined for display
+}
+
+// AnomalyCandidate is an expense transaction being checked for anomalies
+type AnomalyCandidate struct {
+	TransactionId   string
+	UserId          string
+	CategoryId      string
+	CategoryName    string
+	Type            constants.TypeCategory
+	Description     string
+	Amount          int64
+	Source          string
+	TransactionDate time.Time
+	BaseCurrency    string // Currency the amounts are stored in
+}
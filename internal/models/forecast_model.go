@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/constants"
+)
+
+// ForecastEntry is a daily total per category and type used as forecast input
+type ForecastEntry struct {
+	Date       time.Time
+	CategoryId string
+	Type       constants.TypeCategory
+	Amount     int64
+}
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type UserKnowledge struct {
 	Transactions []*Transaction `json:"transactions,omitempty"`
 	Receipts     []*Receipt     `json:"receipts,omitempty"`
@@ -12,6 +14,7 @@ type UserKnowledge struct {
 type RelevantFinancialData struct {
 	Transactions []TransactionWithScore `json:"transactions,omitempty"`
 	Receipts     []ReceiptWithScore     `json:"receipts,omitempty"`
+	ReceiptItems []ReceiptItemWithScore `json:"receipt_items,omitempty"`
 }
 
 type TransactionWithScore struct {
@@ -23,3 +26,10 @@ type ReceiptWithScore struct {
 	Receipt *Receipt `json:"receipt"`
 	Score   float64  `json:"score"`
 }
+
+type ReceiptItemWithScore struct {
+	ReceiptItem     *ReceiptItem `json:"receipt_item"`
+	MerchantName    string       `json:"merchant_name"`
+	TransactionDate time.Time    `json:"transaction_date"`
+	Score           float64      `json:"score"`
+}
@@ -13,4 +13,9 @@ type LogMessage struct {
 	Model        string    `json:"model"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
+
+	PromptVersions PromptVersions `json:"prompt_versions"` // versions of the prompts sent in the call
+	ParseFailed    bool           `json:"parse_failed"`    // the response did not have the expected structure
+
+	Cost float64 `json:"cost"` // USD, from constants.ModelPrices
 }
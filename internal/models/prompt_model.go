@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// PromptVersion is one version of a prompt in one locale
+type PromptVersion struct {
+	PromptVersionId string     `json:"prompt_version_id"`
+	PromptKey       string     `json:"prompt_key"`
+	Version         string     `json:"version"`
+	Locale          string     `json:"locale"`
+	Content         string     `json:"content"`
+	Description     string     `json:"description,omitempty"`
+	Source          string     `json:"source"`
+	IsActive        bool       `json:"is_active"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       *time.Time `json:"updated_at,omitempty"`
+}
+
+// PromptExperiment serves the candidate version to CandidatePercent of the users and the control version
+// to the others, a user always gets the same version while the experiment runs
+type PromptExperiment struct {
+	PromptExperimentId string     `json:"prompt_experiment_id"`
+	PromptKey          string     `json:"prompt_key"`
+	ControlVersion     string     `json:"control_version"`
+	CandidateVersion   string     `json:"candidate_version"`
+	CandidatePercent   int        `json:"candidate_percent"`
+	StartedAt          time.Time  `json:"started_at"`
+	EndedAt            *time.Time `json:"ended_at,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          *time.Time `json:"updated_at,omitempty"`
+}
+
+// PromptVersionOutcome sums the log_messages rows of the calls that used a version
+type PromptVersionOutcome struct {
+	Version        string `json:"version"`
+	Calls          int64  `json:"calls"`
+	Users          int64  `json:"users"`
+	ParseFailures  int64  `json:"parse_failures"`
+	CorrectedCalls int64  `json:"corrected_calls"`
+	InputTokens    int64  `json:"input_tokens"`
+	OutputTokens   int64  `json:"output_tokens"`
+}
+
+// ResolvedPrompt is the text of a prompt a user gets and the version it comes from
+type ResolvedPrompt struct {
+	Key     string
+	Version string
+	Content string
+}
+
+// PromptVersions maps the prompt keys used by an AI call to their versions, see log_messages.prompt_versions
+type PromptVersions map[string]string
+
+// Add records the version of a resolved prompt
+func (v PromptVersions) Add(prompts ...ResolvedPrompt) PromptVersions {
+	for _, p := range prompts {
+		v[p.Key] = p.Version
+	}
+	return v
+}
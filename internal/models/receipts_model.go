@@ -16,12 +16,15 @@ type Receipt struct {
 	TransactionDate           time.Time `json:"transaction_date"`
 	CreatedAt                 time.Time `json:"created_at"`
 	UpdatedAt                 time.Time `json:"updated_at"`
+	Tags                      []string  `json:"tags,omitempty"`
+	LogMessageId              string    `json:"-"` // extraction call that read the receipt
 }
 
 type ReceiptItem struct {
 	ReceiptItemId        string    `json:"receipt_item_id"`
 	ReceiptId            string    `json:"receipt_id"`
 	ItemName             string    `json:"item_name"`
+	ItemNameEmbedding    any       `json:"-"`
 	ItemQuantity         int       `json:"item_quantity"`
 	ItemPrice            int64     `json:"item_price"`
 	ItemPriceTotal       int64     `json:"item_price_total"`
@@ -30,6 +33,14 @@ type ReceiptItem struct {
 	UpdatedAt            time.Time `json:"updated_at"`
 	CategoryId           *string   `json:"category_id,omitempty"`
 	AiCategoryConfidence float64   `json:"ai_category_confidence,omitempty"`
+	Confirmed            bool      `json:"confirmed"`
+}
+
+// UnembeddedReceiptItem is a receipt item saved before item names were embedded, or whose embedding failed
+type UnembeddedReceiptItem struct {
+	ReceiptItemId string
+	UserId        string
+	ItemName      string
 }
 
 type MetaData struct {
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/constants"
+)
+
+type RecurringRule struct {
+	RecurringRuleId string                 `json:"recurring_rule_id"`
+	UserId          string                 `json:"user_id"`
+	CategoryId      string                 `json:"category_id"`
+	Type            constants.TypeCategory `json:"type"`
+	Description     string                 `json:"description"`
+	Amount          int64                  `json:"amount"`
+	PaymentMethod   string                 `json:"payment_method"`
+	Frequency       constants.PeriodType   `json:"frequency"`
+	DayOfMonth      *int                   `json:"day_of_month,omitempty"`
+	StartDate       time.Time              `json:"start_date"`
+	EndDate         *time.Time             `json:"end_date,omitempty"`
+	NextRunDate     *time.Time             `json:"next_run_date,omitempty"`
+	IsActive        bool                   `json:"is_active"`
+	CreatedAt       time.Time              `json:"created_at"`
+	UpdatedAt       time.Time              `json:"updated_at"`
+}
+
+type RecurringOccurrence struct {
+	RecurringOccurrenceId string                     `json:"recurring_occurrence_id"`
+	RecurringRuleId       string                     `json:"recurring_rule_id"`
+	OccurrenceDate        time.Time                  `json:"occurrence_date"`
+	Status                constants.OccurrenceStatus `json:"status"`
+	Amount                *int64                     `json:"amount,omitempty"`
+	Description           *string                    `json:"description,omitempty"`
+	TransactionId         *string                    `json:"transaction_id,omitempty"`
+	CreatedAt             time.Time                  `json:"created_at"`
+	UpdatedAt             time.Time                  `json:"updated_at"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+type MonthlyReport struct {
+	ReportId     string    `json:"report_id"`
+	UserId       string    `json:"user_id"`
+	Year         int       `json:"year"`
+	Month        int       `json:"month"`
+	ObjectName   string    `json:"-"` // Object key in the reports bucket
+	FileSize     int64     `json:"file_size"`
+	TotalIncome  int64     `json:"total_income"`
+	TotalExpense int64     `json:"total_expense"`
+	GeneratedAt  time.Time `json:"generated_at"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// BudgetUsage is a budget with the expenses booked on its category in the budget month
+type BudgetUsage struct {
+	BudgetId     string `json:"budget_id"`
+	CategoryId   string `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	AmountLimit  int64  `json:"amount_limit"`
+	Spent        int64  `json:"spent"`
+}
+
+// PeriodSummary is the summary_data stored in ai_summaries for a period
+type PeriodSummary struct {
+	Narrative    string `json:"narrative"`
+	TotalIncome  int64  `json:"total_income"`
+	TotalExpense int64  `json:"total_expense"`
+}
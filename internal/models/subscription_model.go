@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// SubscriptionCharge is an expense transaction considered by subscription detection
+type SubscriptionCharge struct {
+	TransactionId   string    `json:"transaction_id"`
+	CategoryId      string    `json:"category_id"`
+	Description     string    `json:"description"`
+	Amount          int64     `json:"amount"`
+	PaymentMethod   string    `json:"payment_method"`
+	TransactionDate time.Time `json:"transaction_date"`
+	Embedding       []float64 `json:"-"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+type Tag struct {
+	TagId     string    `json:"tag_id"`
+	UserId    string    `json:"user_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type TagWithUsage struct {
+	Tag
+	TransactionCount int64 `json:"transaction_count"`
+	ReceiptCount     int64 `json:"receipt_count"`
+}
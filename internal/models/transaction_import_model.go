@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/pkg/importer"
+)
+
+type TransactionImport struct {
+	ImportId        string                 `json:"import_id"`
+	UserId          string                 `json:"user_id"`
+	FileName        string                 `json:"file_name"`
+	FileType        string                 `json:"file_type"`
+	StatementFormat string                 `json:"statement_format,omitempty"` // Bank statement parser, empty for generic CSV/XLSX files
+	Status          constants.ImportStatus `json:"status"`
+	Headers         []string               `json:"headers"`
+	Rows            [][]string             `json:"-"`
+	Mapping         *importer.Mapping      `json:"mapping"` // The mapping used on commit
+	TotalRows       int                    `json:"total_rows"`
+	ImportedRows    int                    `json:"imported_rows"`
+	DuplicateRows   int                    `json:"duplicate_rows"`
+	InvalidRows     int                    `json:"invalid_rows"`
+	CreatedAt       time.Time              `json:"created_at"`
+	UpdatedAt       time.Time              `json:"updated_at"`
+	CommittedAt     *time.Time             `json:"committed_at"`
+	RevertedAt      *time.Time             `json:"reverted_at"`
+}
@@ -23,4 +23,39 @@ type Transaction struct {
 	Confirmed            bool                   `json:"confirmed"`
 	Discount             int64                  `json:"discount" validate:"omitempty,min=0"`
 	PaymentMethod        string                 `json:"payment_method"`
+	AccountId            string                 `json:"account_id"`
+	TransferId           string                 `json:"transfer_id"`
+	// ExternalId is set on transactions imported from a bank statement
+	ExternalId string `json:"external_id,omitempty"`
+	// ReceiptId is set on transactions created by confirming a receipt
+	ReceiptId string `json:"receipt_id,omitempty"`
+	// Splits is nil when the transaction is booked on its own category only
+	Splits []TransactionSplit `json:"splits,omitempty"`
+	Tags   []string           `json:"tags,omitempty"`
+	// Amount is in the user's base currency, a payment in another currency keeps what was paid in
+	// OriginalAmount (minor units of CurrencyCode) and the rate of the transaction date in FxRate. OriginalAmount
+	// is stored as NULL for a payment in the base currency and read back as Amount.
+	CurrencyCode   string  `json:"currency_code"`
+	OriginalAmount int64   `json:"original_amount"`
+	FxRate         float64 `json:"fx_rate"`
+}
+
+// TransactionExport is a transaction with the names it references, read row by row for exports
+type TransactionExport struct {
+	Transaction
+	CategoryName string
+	AccountName  string
+	// SplitLines are "category name: amount" per split line
+	SplitLines []string
+}
+
+type TransactionSplit struct {
+	SplitId       string    `json:"split_id"`
+	TransactionId string    `json:"transaction_id"`
+	UserId        string    `json:"user_id"`
+	CategoryId    string    `json:"category_id"`
+	CategoryName  string    `json:"category_name"`
+	Amount        int64     `json:"amount"`
+	Note          string    `json:"note"`
+	CreatedAt     time.Time `json:"created_at"`
 }
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+type Transfer struct {
+	TransferId    string    `json:"transfer_id"`
+	UserId        string    `json:"user_id"`
+	FromAccountId string    `json:"from_account_id"`
+	ToAccountId   string    `json:"to_account_id"`
+	Amount        int64     `json:"amount"`
+	Fee           int64     `json:"fee"`
+	Description   string    `json:"description"`
+	TransferDate  time.Time `json:"transfer_date"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// TransferWithAccounts is a transfer with the names of both accounts
+type TransferWithAccounts struct {
+	Transfer
+	FromAccountName string `json:"from_account_name"`
+	ToAccountName   string `json:"to_account_name"`
+}
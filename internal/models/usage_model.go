@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// UsageFilter selects the log_messages rows summed by the usage endpoints, empty fields do not filter
+type UsageFilter struct {
+	GroupBy string    // one of the constants.UsageGroupBy values
+	From    time.Time // inclusive
+	To      time.Time // exclusive
+	UserId  string
+	Topic   string
+	Model   string
+	Limit   int
+}
+
+// UsageGroup sums the calls of a user, topic, model or day
+type UsageGroup struct {
+	Key          string  `json:"key"`             // empty for calls not made for a user, e.g. category embeddings
+	Email        string  `json:"email,omitempty"` // grouped by user
+	Calls        int64   `json:"calls"`
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	Cost         float64 `json:"cost"` // USD
+}
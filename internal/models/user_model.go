@@ -1,13 +1,52 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/constants"
+)
 
 type User struct {
-	UserId        string    `json:"user_id"`
-	FullName      string    `json:"full_name"`
-	Email         string    `json:"email"`
-	Password      string    `json:"password"`
-	AiPreferences any       `json:"ai_preferences,omitempty"` // Use 'any' for flexible AI preferences storage
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	UserId        string          `json:"user_id"`
+	FullName      string          `json:"full_name"`
+	Email         string          `json:"email"`
+	Password      string          `json:"password"`
+	AiPreferences UserPreferences `json:"ai_preferences"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// UserPreferences is stored as JSON in users.ai_preferences. Keys missing from the stored document keep
+// the value of DefaultUserPreferences, so a new setting needs no migration.
+type UserPreferences struct {
+	Locale          string                  `json:"locale"`   // BCP 47 tag used to format amounts, e.g. id-ID
+	Timezone        string                  `json:"timezone"` // IANA name, e.g. Asia/Jakarta
+	Language        string                  `json:"language"` // Language the AI replies in, empty follows the request
+	Tone            string                  `json:"tone"`
+	DefaultChatMode Mode                    `json:"default_chat_mode"`
+	DefaultModel    string                  `json:"default_model"` // Gemini model used in ask mode
+	Notifications   NotificationPreferences `json:"notifications"`
+}
+
+// NotificationPreferences switch the background jobs that create reports and recommendations for the user
+type NotificationPreferences struct {
+	MonthlyReport      bool `json:"monthly_report"`
+	AnomalyAlerts      bool `json:"anomaly_alerts"`
+	SubscriptionAlerts bool `json:"subscription_alerts"`
+}
+
+func DefaultUserPreferences() UserPreferences {
+	return UserPreferences{
+		Locale:          constants.DefaultLocale,
+		Timezone:        constants.DefaultTimezone,
+		Language:        constants.DefaultLanguage,
+		Tone:            constants.DefaultTone,
+		DefaultChatMode: ModeChat,
+		DefaultModel:    constants.DefaultChatModel,
+		Notifications: NotificationPreferences{
+			MonthlyReport:      true,
+			AnomalyAlerts:      true,
+			SubscriptionAlerts: true,
+		},
+	}
 }
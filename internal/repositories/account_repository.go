@@ -0,0 +1,381 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/saufiroja/fin-ai/internal/domains/account"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/pkg/databases"
+)
+
+type accountRepository struct {
+	DB databases.PostgresManager
+}
+
+func NewAccountRepository(db databases.PostgresManager) account.AccountStorer {
+	return &accountRepository{
+		DB: db,
+	}
+}
+
+const accountColumns = `
+        a.account_id, a.user_id, a.name, a.type, COALESCE(a.institution, ''),
+        a.opening_balance, a.opening_date, a.is_archived, a.currency_code, a.created_at,
+        COALESCE(a.updated_at, a.created_at)`
+
+// accountSignedAmount is the effect of a transaction on its account balance
+const accountSignedAmount = `CASE WHEN t.type IN ('income', 'transfer_in') THEN t.amount ELSE -t.amount END`
+
+func (r *accountRepository) scanAccount(scanner interface{ Scan(...any) error }, extra ...any) (*models.Account, error) {
+	account := &models.Account{}
+	dest := []any{
+		&account.AccountId,
+		&account.UserId,
+		&account.Name,
+		&account.Type,
+		&account.Institution,
+		&account.OpeningBalance,
+		&account.OpeningDate,
+		&account.IsArchived,
+		&account.CurrencyCode,
+		&account.CreatedAt,
+		&account.UpdatedAt,
+	}
+	if err := scanner.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+func (r *accountRepository) InsertAccount(account *models.Account) error {
+	db := r.DB.Connection()
+
+	query := `
+    INSERT INTO accounts (
+        account_id, user_id, name, type, institution, opening_balance,
+        opening_date, is_archived, currency_code, created_at, updated_at
+    )
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	_, err := db.Exec(query,
+		account.AccountId,
+		account.UserId,
+		account.Name,
+		account.Type,
+		account.Institution,
+		account.OpeningBalance,
+		account.OpeningDate,
+		account.IsArchived,
+		account.CurrencyCode,
+		account.CreatedAt,
+		account.UpdatedAt,
+	)
+
+	return err
+}
+
+func (r *accountRepository) GetAccountsByUserId(userId string, includeArchived bool) ([]models.AccountWithBalance, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT ` + accountColumns + `,
+        a.opening_balance + COALESCE(SUM(` + accountSignedAmount + `), 0) AS balance,
+        COUNT(t.transaction_id) AS transaction_count
+    FROM accounts a
+    LEFT JOIN transactions t
+        ON t.account_id = a.account_id
+        AND t.transaction_date >= a.opening_date
+        AND t.transaction_date <= NOW()
+    WHERE a.user_id = $1
+    AND ($2 OR a.is_archived = FALSE)
+    GROUP BY a.account_id
+    ORDER BY a.created_at ASC`
+
+	rows, err := db.Query(query, userId, includeArchived)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []models.AccountWithBalance
+	for rows.Next() {
+		var balance, count int64
+		account, err := r.scanAccount(rows, &balance, &count)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, models.AccountWithBalance{
+			Account:          *account,
+			Balance:          balance,
+			TransactionCount: count,
+		})
+	}
+
+	return accounts, rows.Err()
+}
+
+func (r *accountRepository) GetAccountById(userId, accountId string) (*models.Account, error) {
+	db := r.DB.Connection()
+
+	query := `SELECT ` + accountColumns + ` FROM accounts a WHERE a.user_id = $1 AND a.account_id = $2`
+
+	return r.scanAccount(db.QueryRow(query, userId, accountId))
+}
+
+func (r *accountRepository) IsAccountNameTaken(userId, name, excludeAccountId string) (bool, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT EXISTS (
+        SELECT 1 FROM accounts
+        WHERE user_id = $1
+        AND LOWER(name) = LOWER($2)
+        AND account_id <> $3
+    )`
+
+	var taken bool
+	err := db.QueryRow(query, userId, name, excludeAccountId).Scan(&taken)
+
+	return taken, err
+}
+
+func (r *accountRepository) UpdateAccount(account *models.Account) error {
+	db := r.DB.Connection()
+
+	query := `
+    UPDATE accounts
+    SET
+        name = $1,
+        type = $2,
+        institution = $3,
+        opening_balance = $4,
+        opening_date = $5,
+        is_archived = $6,
+        updated_at = $7,
+        currency_code = $10
+    WHERE account_id = $8 AND user_id = $9`
+
+	_, err := db.Exec(query,
+		account.Name,
+		account.Type,
+		account.Institution,
+		account.OpeningBalance,
+		account.OpeningDate,
+		account.IsArchived,
+		account.UpdatedAt,
+		account.AccountId,
+		account.UserId,
+		account.CurrencyCode,
+	)
+
+	return err
+}
+
+func (r *accountRepository) DeleteAccount(userId, accountId string) error {
+	db := r.DB.Connection()
+
+	query := `DELETE FROM accounts WHERE user_id = $1 AND account_id = $2`
+
+	_, err := db.Exec(query, userId, accountId)
+
+	return err
+}
+
+func (r *accountRepository) CountAccountTransactions(accountId string) (int64, error) {
+	db := r.DB.Connection()
+
+	query := `SELECT COUNT(*) FROM transactions WHERE account_id = $1`
+
+	var count int64
+	err := db.QueryRow(query, accountId).Scan(&count)
+
+	return count, err
+}
+
+func (r *accountRepository) GetAccountBalance(accountId string, until time.Time) (int64, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT a.opening_balance + COALESCE(SUM(` + accountSignedAmount + `), 0)
+    FROM accounts a
+    LEFT JOIN transactions t
+        ON t.account_id = a.account_id
+        AND t.transaction_date >= a.opening_date
+        AND t.transaction_date < $2
+    WHERE a.account_id = $1
+    GROUP BY a.account_id`
+
+	var balance int64
+	err := db.QueryRow(query, accountId, until).Scan(&balance)
+
+	return balance, err
+}
+
+func (r *accountRepository) GetLedgerEntries(accountId string, start, end time.Time) ([]models.AccountLedgerEntry, error) {
+	db := r.DB.Connection()
+
+	// The running balance is computed over the whole account history before the range is applied
+	query := `
+    WITH ledger AS (
+        SELECT
+            t.transaction_id, COALESCE(t.category_id, '') AS category_id, t.type,
+            t.description, t.amount, t.transaction_date,
+            a.opening_balance + SUM(` + accountSignedAmount + `)
+                OVER (ORDER BY t.transaction_date, t.transaction_id) AS running_balance
+        FROM transactions t
+        JOIN accounts a ON a.account_id = t.account_id
+        WHERE t.account_id = $1
+        AND t.transaction_date >= a.opening_date
+        AND t.transaction_date < $3
+    )
+    SELECT transaction_id, category_id, type, description, amount, transaction_date, running_balance
+    FROM ledger
+    WHERE transaction_date >= $2
+    ORDER BY transaction_date, transaction_id`
+
+	rows, err := db.Query(query, accountId, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.AccountLedgerEntry
+	for rows.Next() {
+		entry := models.AccountLedgerEntry{}
+		err := rows.Scan(
+			&entry.TransactionId,
+			&entry.CategoryId,
+			&entry.Type,
+			&entry.Description,
+			&entry.Amount,
+			&entry.TransactionDate,
+			&entry.RunningBalance,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+func (r *accountRepository) AssignTransactions(userId, accountId string, transactionIds []string) (int64, error) {
+	db := r.DB.Connection()
+
+	// All or nothing: nothing is updated when any of the ids is missing or owned by another user
+	query := `
+    UPDATE transactions
+    SET account_id = $1, updated_at = NOW()
+    WHERE user_id = $2
+    AND transaction_id = ANY($3)
+    AND (
+        SELECT COUNT(*) FROM transactions
+        WHERE user_id = $2 AND transaction_id = ANY($3)
+    ) = cardinality($3::text[])`
+
+	result, err := db.Exec(query, accountId, userId, pq.Array(transactionIds))
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func (r *accountRepository) InsertReconciliation(reconciliation *models.AccountReconciliation) error {
+	db := r.DB.Connection()
+
+	query := `
+    INSERT INTO account_reconciliations (
+        reconciliation_id, account_id, user_id, statement_date,
+        statement_balance, computed_balance, difference, created_at
+    )
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err := db.Exec(query,
+		reconciliation.ReconciliationId,
+		reconciliation.AccountId,
+		reconciliation.UserId,
+		reconciliation.StatementDate,
+		reconciliation.StatementBalance,
+		reconciliation.ComputedBalance,
+		reconciliation.Difference,
+		reconciliation.CreatedAt,
+	)
+
+	return err
+}
+
+const reconciliationColumns = `
+        reconciliation_id, account_id, user_id, statement_date,
+        statement_balance, computed_balance, difference, created_at`
+
+func (r *accountRepository) scanReconciliation(scanner interface{ Scan(...any) error }) (*models.AccountReconciliation, error) {
+	reconciliation := &models.AccountReconciliation{}
+	err := scanner.Scan(
+		&reconciliation.ReconciliationId,
+		&reconciliation.AccountId,
+		&reconciliation.UserId,
+		&reconciliation.StatementDate,
+		&reconciliation.StatementBalance,
+		&reconciliation.ComputedBalance,
+		&reconciliation.Difference,
+		&reconciliation.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return reconciliation, nil
+}
+
+func (r *accountRepository) GetLastMatchedReconciliation(accountId string, before time.Time) (*models.AccountReconciliation, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT ` + reconciliationColumns + `
+    FROM account_reconciliations
+    WHERE account_id = $1
+    AND statement_date < $2
+    AND difference = 0
+    ORDER BY statement_date DESC, created_at DESC
+    LIMIT 1`
+
+	reconciliation, err := r.scanReconciliation(db.QueryRow(query, accountId, before))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	return reconciliation, err
+}
+
+func (r *accountRepository) GetReconciliations(accountId string) ([]models.AccountReconciliation, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT ` + reconciliationColumns + `
+    FROM account_reconciliations
+    WHERE account_id = $1
+    ORDER BY statement_date DESC, created_at DESC`
+
+	rows, err := db.Query(query, accountId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reconciliations []models.AccountReconciliation
+	for rows.Next() {
+		reconciliation, err := r.scanReconciliation(rows)
+		if err != nil {
+			return nil, err
+		}
+		reconciliations = append(reconciliations, *reconciliation)
+	}
+
+	return reconciliations, rows.Err()
+}
@@ -0,0 +1,223 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/analytics"
+	"github.com/saufiroja/fin-ai/pkg/databases"
+)
+
+type analyticsRepository struct {
+	DB databases.PostgresManager
+}
+
+func NewAnalyticsRepository(db databases.PostgresManager) analytics.AnalyticsStorer {
+	return &analyticsRepository{
+		DB: db,
+	}
+}
+
+func (a *analyticsRepository) GetTimeSeries(userId string, req *requests.AnalyticsQuery, truncUnit string, start, end time.Time) ([]responses.TimeSeriesPoint, error) {
+	db := a.DB.Connection()
+
+	// generate_series keeps empty buckets so charts get a continuous axis
+	query := `
+    WITH buckets AS (
+        SELECT generate_series(
+            date_trunc($2, $3::timestamp),
+            date_trunc($2, $4::timestamp),
+            ('1 ' || $2)::interval
+        ) AS period_start
+    )
+    SELECT
+        b.period_start,
+        COALESCE(SUM(CASE WHEN t.type = 'income' THEN t.amount ELSE 0 END), 0) AS income,
+        COALESCE(SUM(CASE WHEN t.type = 'expense' THEN t.amount ELSE 0 END), 0) AS expense,
+        COUNT(DISTINCT t.transaction_id) AS transaction_count
+    FROM buckets b
+    LEFT JOIN ` + transactionLines + ` t
+        ON date_trunc($2, t.transaction_date) = b.period_start
+        AND t.user_id = $1
+        AND t.transaction_date >= $3::timestamp
+        AND t.transaction_date < $4::timestamp + INTERVAL '1 day'
+        AND t.type IN ('income', 'expense')
+        AND ($5 = '' OR t.category_id = $5)
+    GROUP BY b.period_start
+    ORDER BY b.period_start`
+
+	rows, err := db.Query(query, userId, truncUnit, start, end, req.CategoryId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []responses.TimeSeriesPoint
+	for rows.Next() {
+		var point responses.TimeSeriesPoint
+		if err := rows.Scan(
+			&point.PeriodStart,
+			&point.Income,
+			&point.Expense,
+			&point.TransactionCount,
+		); err != nil {
+			return nil, err
+		}
+		point.Net = point.Income - point.Expense
+		points = append(points, point)
+	}
+
+	return points, nil
+}
+
+func (a *analyticsRepository) GetCategoryBreakdown(userId string, req *requests.AnalyticsQuery, start, end time.Time) ([]responses.CategoryBreakdownItem, error) {
+	db := a.DB.Connection()
+
+	query := `
+    SELECT
+        COALESCE(t.category_id, ''),
+        COALESCE(c.name, 'Uncategorized'),
+        SUM(t.amount) AS total,
+        COUNT(DISTINCT t.transaction_id) AS transaction_count
+    FROM ` + transactionLines + ` t
+    LEFT JOIN categories c ON c.category_id = t.category_id
+    WHERE t.user_id = $1
+    AND t.type = $2
+    AND t.transaction_date >= $3::timestamp
+    AND t.transaction_date < $4::timestamp + INTERVAL '1 day'
+    AND ($5 = '' OR t.category_id = $5)
+    GROUP BY t.category_id, c.name
+    ORDER BY total DESC`
+
+	rows, err := db.Query(query, userId, req.Type, start, end, req.CategoryId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []responses.CategoryBreakdownItem
+	for rows.Next() {
+		var item responses.CategoryBreakdownItem
+		if err := rows.Scan(
+			&item.CategoryId,
+			&item.CategoryName,
+			&item.Total,
+			&item.TransactionCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (a *analyticsRepository) GetTopSources(userId string, req *requests.AnalyticsQuery, start, end time.Time) ([]responses.TopSourceItem, error) {
+	db := a.DB.Connection()
+
+	query := `
+    SELECT
+        COALESCE(NULLIF(TRIM(source), ''), 'Unknown') AS merchant,
+        SUM(amount) AS total,
+        COUNT(DISTINCT transaction_id) AS transaction_count,
+        COALESCE(SUM(amount) * 100.0 / NULLIF(SUM(SUM(amount)) OVER (), 0), 0) AS percentage
+    FROM ` + transactionLines + ` lines
+    WHERE user_id = $1
+    AND type = $2
+    AND transaction_date >= $3::timestamp
+    AND transaction_date < $4::timestamp + INTERVAL '1 day'
+    AND ($5 = '' OR category_id = $5)
+    GROUP BY merchant
+    ORDER BY total DESC
+    LIMIT $6`
+
+	rows, err := db.Query(query, userId, req.Type, start, end, req.CategoryId, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []responses.TopSourceItem
+	for rows.Next() {
+		var item responses.TopSourceItem
+		if err := rows.Scan(
+			&item.Source,
+			&item.Total,
+			&item.TransactionCount,
+			&item.Percentage,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (a *analyticsRepository) GetTagBreakdown(userId string, req *requests.AnalyticsQuery, start, end time.Time) ([]responses.TagBreakdownItem, error) {
+	db := a.DB.Connection()
+
+	// With a category filter only the split lines in that category count towards the tag
+	query := `
+    SELECT
+        tg.tag_id,
+        tg.name,
+        SUM(t.amount) AS total,
+        COUNT(DISTINCT t.transaction_id) AS transaction_count
+    FROM ` + transactionLines + ` t
+    JOIN transaction_tags tt ON tt.transaction_id = t.transaction_id
+    JOIN tags tg ON tg.tag_id = tt.tag_id
+    WHERE t.user_id = $1
+    AND t.type = $2
+    AND t.transaction_date >= $3::timestamp
+    AND t.transaction_date < $4::timestamp + INTERVAL '1 day'
+    AND ($5 = '' OR t.category_id = $5)
+    GROUP BY tg.tag_id, tg.name
+    ORDER BY total DESC`
+
+	rows, err := db.Query(query, userId, req.Type, start, end, req.CategoryId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []responses.TagBreakdownItem
+	for rows.Next() {
+		var item responses.TagBreakdownItem
+		if err := rows.Scan(
+			&item.TagId,
+			&item.TagName,
+			&item.Total,
+			&item.TransactionCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// GetTaggedTotals returns the total of the period and the part of it carried by transactions with at least one tag
+func (a *analyticsRepository) GetTaggedTotals(userId string, req *requests.AnalyticsQuery, start, end time.Time) (int64, int64, error) {
+	db := a.DB.Connection()
+
+	query := `
+    SELECT
+        COALESCE(SUM(t.amount), 0),
+        COALESCE(SUM(t.amount) FILTER (
+            WHERE EXISTS (SELECT 1 FROM transaction_tags tt WHERE tt.transaction_id = t.transaction_id)
+        ), 0)
+    FROM ` + transactionLines + ` t
+    WHERE t.user_id = $1
+    AND t.type = $2
+    AND t.transaction_date >= $3::timestamp
+    AND t.transaction_date < $4::timestamp + INTERVAL '1 day'
+    AND ($5 = '' OR t.category_id = $5)`
+
+	var total, tagged int64
+	err := db.QueryRow(query, userId, req.Type, start, end, req.CategoryId).Scan(&total, &tagged)
+
+	return total, tagged, err
+}
@@ -0,0 +1,368 @@
+package repositories
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/domains/anomaly"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/pkg/databases"
+)
+
+type anomalyRepository struct {
+	DB databases.PostgresManager
+}
+
+func NewAnomalyRepository(db databases.PostgresManager) anomaly.AnomalyStorer {
+	return &anomalyRepository{
+		DB: db,
+	}
+}
+
+const anomalyCandidateColumns = `
+    t.transaction_id, t.user_id, COALESCE(t.category_id, ''), COALESCE(c.name, ''),
+    t.type, t.description, t.amount, t.source, t.transaction_date,
+    COALESCE((SELECT u.base_currency FROM users u WHERE u.user_id = t.user_id), 'IDR')`
+
+func (a *anomalyRepository) scanCandidate(row interface{ Scan(...any) error }) (*models.AnomalyCandidate, error) {
+	candidate := &models.AnomalyCandidate{}
+	err := row.Scan(
+		&candidate.TransactionId,
+		&candidate.UserId,
+		&candidate.CategoryId,
+		&candidate.CategoryName,
+		&candidate.Type,
+		&candidate.Description,
+		&candidate.Amount,
+		&candidate.Source,
+		&candidate.TransactionDate,
+		&candidate.BaseCurrency,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return candidate, nil
+}
+
+func (a *anomalyRepository) GetCandidate(transactionId string) (*models.AnomalyCandidate, error) {
+	db := a.DB.Connection()
+
+	query := `
+    SELECT` + anomalyCandidateColumns + `
+    FROM transactions t
+    LEFT JOIN categories c ON c.category_id = t.category_id
+    WHERE t.transaction_id = $1`
+
+	return a.scanCandidate(db.QueryRow(query, transactionId))
+}
+
+func (a *anomalyRepository) GetCandidates(userId string, since, until time.Time) ([]models.AnomalyCandidate, error) {
+	db := a.DB.Connection()
+
+	query := `
+    SELECT` + anomalyCandidateColumns + `
+    FROM transactions t
+    LEFT JOIN categories c ON c.category_id = t.category_id
+    WHERE t.user_id = $1
+    AND t.type = 'expense'
+    AND t.source <> $2
+    AND t.transaction_date >= $3
+    AND t.transaction_date < $4
+    ORDER BY t.transaction_date ASC`
+
+	rows, err := db.Query(query, userId, constants.RecurringSource, since, until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []models.AnomalyCandidate
+	for rows.Next() {
+		candidate, err := a.scanCandidate(rows)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, *candidate)
+	}
+
+	return candidates, nil
+}
+
+func (a *anomalyRepository) queryAmounts(query string, args ...any) ([]int64, error) {
+	db := a.DB.Connection()
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var amounts []int64
+	for rows.Next() {
+		var amount int64
+		if err := rows.Scan(&amount); err != nil {
+			return nil, err
+		}
+		amounts = append(amounts, amount)
+	}
+
+	return amounts, nil
+}
+
+func (a *anomalyRepository) GetCategoryAmounts(userId, categoryId, excludeTransactionId string, since, until time.Time) ([]int64, error) {
+	query := `
+    SELECT amount
+    FROM ` + transactionLines + ` lines
+    WHERE user_id = $1
+    AND category_id = $2
+    AND transaction_id <> $3
+    AND type = 'expense'
+    AND transaction_date >= $4
+    AND transaction_date < $5`
+
+	return a.queryAmounts(query, userId, categoryId, excludeTransactionId, since, until)
+}
+
+func (a *anomalyRepository) GetSimilarDescriptionAmounts(userId, transactionId string, since, until time.Time, minSimilarity float64) ([]int64, error) {
+	query := `
+    SELECT t.amount
+    FROM transactions t, (
+        SELECT description_embedding FROM transactions WHERE transaction_id = $2
+    ) target
+    WHERE t.user_id = $1
+    AND t.transaction_id <> $2
+    AND t.type = 'expense'
+    AND t.transaction_date >= $3
+    AND t.transaction_date < $4
+    AND 1 - (t.description_embedding <=> target.description_embedding) >= $5`
+
+	return a.queryAmounts(query, userId, transactionId, since, until, minSimilarity)
+}
+
+func (a *anomalyRepository) GetExpenseAmounts(userId, excludeTransactionId string, since, until time.Time) ([]int64, error) {
+	query := `
+    SELECT amount
+    FROM transactions
+    WHERE user_id = $1
+    AND transaction_id <> $2
+    AND type = 'expense'
+    AND transaction_date >= $3
+    AND transaction_date < $4`
+
+	return a.queryAmounts(query, userId, excludeTransactionId, since, until)
+}
+
+// GetExpenseHours ignores midnight timestamps, those are date-only entries without a real time
+func (a *anomalyRepository) GetExpenseHours(userId, excludeTransactionId string, since, until time.Time) ([]int, error) {
+	db := a.DB.Connection()
+
+	query := `
+    SELECT EXTRACT(HOUR FROM transaction_date)::int
+    FROM transactions
+    WHERE user_id = $1
+    AND transaction_id <> $2
+    AND type = 'expense'
+    AND transaction_date >= $3
+    AND transaction_date < $4
+    AND transaction_date::time <> '00:00:00'`
+
+	rows, err := db.Query(query, userId, excludeTransactionId, since, until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hours []int
+	for rows.Next() {
+		var hour int
+		if err := rows.Scan(&hour); err != nil {
+			return nil, err
+		}
+		hours = append(hours, hour)
+	}
+
+	return hours, nil
+}
+
+func (a *anomalyRepository) SumCategoryExpenses(userId, categoryId string, since, until time.Time) (int64, error) {
+	db := a.DB.Connection()
+
+	query := `
+    SELECT COALESCE(SUM(amount), 0)
+    FROM ` + transactionLines + ` lines
+    WHERE user_id = $1
+    AND category_id = $2
+    AND type = 'expense'
+    AND transaction_date >= $3
+    AND transaction_date < $4`
+
+	var total int64
+	err := db.QueryRow(query, userId, categoryId, since, until).Scan(&total)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// GetCategoryMonthlyTotals returns one total per month that had spending in the category
+func (a *anomalyRepository) GetCategoryMonthlyTotals(userId, categoryId string, since, until time.Time) ([]int64, error) {
+	query := `
+    SELECT SUM(amount)
+    FROM ` + transactionLines + ` lines
+    WHERE user_id = $1
+    AND category_id = $2
+    AND type = 'expense'
+    AND transaction_date >= $3
+    AND transaction_date < $4
+    GROUP BY date_trunc('month', transaction_date)
+    ORDER BY date_trunc('month', transaction_date)`
+
+	return a.queryAmounts(query, userId, categoryId, since, until)
+}
+
+func (a *anomalyRepository) InsertAnomaly(anomaly *models.TransactionAnomaly) (bool, error) {
+	db := a.DB.Connection()
+
+	query := `
+    INSERT INTO transaction_anomalies (
+        anomaly_id, user_id, transaction_id, category_id, anomaly_type,
+        reference_key, reason, observed_value, expected_value, score,
+        sample_size, occurred_at, detected_at
+    ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+    ON CONFLICT (user_id, reference_key) DO NOTHING`
+
+	result, err := db.Exec(query,
+		anomaly.AnomalyId,
+		anomaly.UserId,
+		anomaly.TransactionId,
+		anomaly.CategoryId,
+		anomaly.AnomalyType,
+		anomaly.ReferenceKey,
+		anomaly.Reason,
+		anomaly.ObservedValue,
+		anomaly.ExpectedValue,
+		anomaly.Score,
+		anomaly.SampleSize,
+		anomaly.OccurredAt,
+		anomaly.DetectedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// anomalyConditions filters $1 user, $2 type (” for any) and the $3 - $4 occurred_at range
+const anomalyConditions = `
+    WHERE a.user_id = $1
+    AND ($2 = '' OR a.anomaly_type = $2)
+    AND a.occurred_at >= $3
+    AND a.occurred_at < $4`
+
+func (a *anomalyRepository) GetAnomalies(userId string, req *requests.AnomalyQuery, since, until time.Time) ([]models.TransactionAnomaly, error) {
+	db := a.DB.Connection()
+
+	query := `
+    SELECT
+        a.anomaly_id, a.user_id, a.transaction_id, a.category_id, a.anomaly_type,
+        a.reason, a.observed_value, a.expected_value, a.score, a.sample_size,
+        a.occurred_at, a.detected_at, COALESCE(t.description, ''), COALESCE(c.name, '')
+    FROM transaction_anomalies a
+    LEFT JOIN transactions t ON t.transaction_id = a.transaction_id
+    LEFT JOIN categories c ON c.category_id = a.category_id` + anomalyConditions + `
+    ORDER BY a.occurred_at DESC, a.detected_at DESC
+    LIMIT $5 OFFSET $6`
+
+	rows, err := db.Query(query, userId, string(req.Type), since, until, req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var anomalies []models.TransactionAnomaly
+	for rows.Next() {
+		anomaly := models.TransactionAnomaly{}
+		var transactionId, categoryId sql.NullString
+		err := rows.Scan(
+			&anomaly.AnomalyId,
+			&anomaly.UserId,
+			&transactionId,
+			&categoryId,
+			&anomaly.AnomalyType,
+			&anomaly.Reason,
+			&anomaly.ObservedValue,
+			&anomaly.ExpectedValue,
+			&anomaly.Score,
+			&anomaly.SampleSize,
+			&anomaly.OccurredAt,
+			&anomaly.DetectedAt,
+			&anomaly.Description,
+			&anomaly.CategoryName,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if transactionId.Valid {
+			anomaly.TransactionId = &transactionId.String
+		}
+		if categoryId.Valid {
+			anomaly.CategoryId = &categoryId.String
+		}
+		anomalies = append(anomalies, anomaly)
+	}
+
+	return anomalies, nil
+}
+
+func (a *anomalyRepository) CountAnomalies(userId string, req *requests.AnomalyQuery, since, until time.Time) (int64, error) {
+	db := a.DB.Connection()
+
+	query := `SELECT COUNT(*) FROM transaction_anomalies a` + anomalyConditions
+
+	var count int64
+	err := db.QueryRow(query, userId, string(req.Type), since, until).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (a *anomalyRepository) GetUserIdsWithExpensesSince(since time.Time) ([]string, error) {
+	db := a.DB.Connection()
+
+	query := `
+    SELECT DISTINCT user_id
+    FROM transactions
+    WHERE type = 'expense'
+    AND transaction_date >= $1
+    AND user_id IS NOT NULL
+    AND ` + notificationEnabled("transactions.user_id", constants.NotificationAnomalyAlerts)
+
+	rows, err := db.Query(query, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIds []string
+	for rows.Next() {
+		var userId string
+		if err := rows.Scan(&userId); err != nil {
+			return nil, err
+		}
+		userIds = append(userIds, userId)
+	}
+
+	return userIds, nil
+}
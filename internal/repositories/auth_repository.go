@@ -1,8 +1,14 @@
 package repositories
 
 import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
 	"github.com/saufiroja/fin-ai/internal/contracts/requests"
 	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/currency"
 	"github.com/saufiroja/fin-ai/internal/domains/user"
 	"github.com/saufiroja/fin-ai/internal/models"
 	"github.com/saufiroja/fin-ai/pkg/databases"
@@ -45,14 +51,18 @@ func (r *userRepository) FindUserByEmail(email string) (*models.User, error) {
 
 func (r *userRepository) FindUserById(userId string) (*responses.FindUserById, error) {
 	db := r.DB.Connection()
-	query := `SELECT user_id, full_name, email FROM users WHERE user_id = $1`
+	query := `SELECT user_id, full_name, email, deletion_scheduled_at FROM users WHERE user_id = $1`
 	row := db.QueryRow(query, userId)
 
 	var user responses.FindUserById
-	err := row.Scan(&user.UserId, &user.FullName, &user.Email)
+	var deletionScheduledAt sql.NullTime
+	err := row.Scan(&user.UserId, &user.FullName, &user.Email, &deletionScheduledAt)
 	if err != nil {
 		return nil, err
 	}
+	if deletionScheduledAt.Valid {
+		user.DeletionScheduledAt = &deletionScheduledAt.Time
+	}
 
 	return &user, nil
 }
@@ -72,13 +82,231 @@ func (r *userRepository) UpdateUserById(userId string, req *requests.UpdateUserR
 	return nil
 }
 
+// userDeleteQueries remove everything a user owns, children first. Rows that reference the deleted
+// rows with ON DELETE CASCADE (splits, tags, occurrences) go with their parent.
+var userDeleteQueries = []string{
+	`DELETE FROM transactions WHERE user_id = $1`,
+	`DELETE FROM transaction_splits WHERE user_id = $1`,
+	`DELETE FROM transaction_anomalies WHERE user_id = $1`,
+	`DELETE FROM transfers WHERE user_id = $1`,
+	`DELETE FROM recurring_rules WHERE user_id = $1`,
+	`DELETE FROM receipt_items WHERE receipt_id IN (SELECT receipt_id FROM receipts WHERE user_id = $1)`,
+	`DELETE FROM receipts WHERE user_id = $1`,
+	`DELETE FROM chat_messages WHERE chat_session_id IN (SELECT chat_session_id FROM chat_sessions WHERE user_id = $1)`,
+	`DELETE FROM chat_sessions WHERE user_id = $1`,
+	`DELETE FROM log_messages WHERE user_id = $1`,
+	`DELETE FROM insights WHERE user_id = $1`,
+	`DELETE FROM budgets WHERE user_id = $1`,
+	`DELETE FROM financial_goals WHERE user_id = $1`,
+	`DELETE FROM ai_summaries WHERE user_id = $1`,
+	`DELETE FROM ai_recommendations WHERE user_id = $1`,
+	`DELETE FROM account_reconciliations WHERE user_id = $1`,
+	`DELETE FROM accounts WHERE user_id = $1`,
+	`DELETE FROM tags WHERE user_id = $1`,
+	`DELETE FROM transaction_imports WHERE user_id = $1`,
+	`DELETE FROM monthly_reports WHERE user_id = $1`,
+	`DELETE FROM users WHERE user_id = $1`,
+}
+
+// DeleteUserById removes the user and all of their rows in one transaction
 func (r *userRepository) DeleteUserById(userId string) error {
+	tx, err := r.DB.StartTransaction()
+	if err != nil {
+		return err
+	}
+	defer r.DB.RollbackTransaction(tx)
+
+	for _, query := range userDeleteQueries {
+		if _, err := tx.Exec(query, userId); err != nil {
+			return err
+		}
+	}
+
+	return r.DB.CommitTransaction(tx)
+}
+
+// ScheduleDeletion stores the deletion schedule, a request that is already pending keeps its
+// original dates and those are written back into deletion
+func (r *userRepository) ScheduleDeletion(deletion *responses.AccountDeletion) error {
+	db := r.DB.Connection()
+	query := `UPDATE users SET
+			deletion_requested_at = COALESCE(deletion_requested_at, $1),
+			deletion_scheduled_at = COALESCE(deletion_scheduled_at, $2),
+			updated_at = NOW()
+			WHERE user_id = $3
+			RETURNING deletion_requested_at, deletion_scheduled_at`
+	return db.QueryRow(query, deletion.DeletionRequestedAt, deletion.DeletionScheduledAt, deletion.UserId).
+		Scan(&deletion.DeletionRequestedAt, &deletion.DeletionScheduledAt)
+}
+
+// CancelDeletion clears a pending deletion, it reports false when none was scheduled
+func (r *userRepository) CancelDeletion(userId string) (bool, error) {
+	db := r.DB.Connection()
+	query := `UPDATE users SET
+			deletion_requested_at = NULL,
+			deletion_scheduled_at = NULL,
+			updated_at = NOW()
+			WHERE user_id = $1 AND deletion_scheduled_at IS NOT NULL`
+	result, err := db.Exec(query, userId)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *userRepository) GetUserIdsDueForDeletion(now time.Time) ([]string, error) {
+	db := r.DB.Connection()
+	query := `SELECT user_id FROM users WHERE deletion_scheduled_at <= $1 ORDER BY deletion_scheduled_at`
+
+	rows, err := db.Query(query, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIds []string
+	for rows.Next() {
+		var userId string
+		if err := rows.Scan(&userId); err != nil {
+			return nil, err
+		}
+		userIds = append(userIds, userId)
+	}
+
+	return userIds, rows.Err()
+}
+
+// userDataQueries select the rows of each table in constants.UserDataTables as JSON. Secrets and
+// derived columns (password hash, embeddings, search vectors) are left out.
+var userDataQueries = map[string]string{
+	"users":                   `SELECT to_jsonb(u) - 'password' FROM users u WHERE u.user_id = $1`,
+	"accounts":                `SELECT to_jsonb(a) FROM accounts a WHERE a.user_id = $1 ORDER BY a.created_at`,
+	"account_reconciliations": `SELECT to_jsonb(ar) FROM account_reconciliations ar WHERE ar.user_id = $1 ORDER BY ar.created_at`,
+	"transfers":               `SELECT to_jsonb(tf) FROM transfers tf WHERE tf.user_id = $1 ORDER BY tf.created_at`,
+	"transactions":            `SELECT to_jsonb(t) - 'description_embedding' - 'search_vector' FROM transactions t WHERE t.user_id = $1 ORDER BY t.transaction_date, t.created_at`,
+	"transaction_splits":      `SELECT to_jsonb(ts) FROM transaction_splits ts WHERE ts.user_id = $1 ORDER BY ts.created_at`,
+	"transaction_tags":        `SELECT to_jsonb(tt) FROM transaction_tags tt JOIN transactions t ON t.transaction_id = tt.transaction_id WHERE t.user_id = $1 ORDER BY tt.created_at`,
+	"tags":                    `SELECT to_jsonb(tg) FROM tags tg WHERE tg.user_id = $1 ORDER BY tg.name`,
+	"transaction_imports":     `SELECT to_jsonb(ti) FROM transaction_imports ti WHERE ti.user_id = $1 ORDER BY ti.created_at`,
+	"transaction_anomalies":   `SELECT to_jsonb(ta) FROM transaction_anomalies ta WHERE ta.user_id = $1 ORDER BY ta.detected_at`,
+	"recurring_rules":         `SELECT to_jsonb(rr) FROM recurring_rules rr WHERE rr.user_id = $1 ORDER BY rr.created_at`,
+	"recurring_occurrences":   `SELECT to_jsonb(ro) FROM recurring_occurrences ro JOIN recurring_rules rr ON rr.recurring_rule_id = ro.recurring_rule_id WHERE rr.user_id = $1 ORDER BY ro.occurrence_date`,
+	"receipts":                `SELECT to_jsonb(r) - 'extracted_receipt_embedding' - 'search_vector' FROM receipts r WHERE r.user_id = $1 ORDER BY r.created_at`,
+	"receipt_items":           `SELECT to_jsonb(ri) - 'item_name_embedding' - 'search_vector' FROM receipt_items ri JOIN receipts r ON r.receipt_id = ri.receipt_id WHERE r.user_id = $1 ORDER BY ri.created_at`,
+	"receipt_tags":            `SELECT to_jsonb(rt) FROM receipt_tags rt JOIN receipts r ON r.receipt_id = rt.receipt_id WHERE r.user_id = $1 ORDER BY rt.created_at`,
+	"budgets":                 `SELECT to_jsonb(b) FROM budgets b WHERE b.user_id = $1 ORDER BY b.created_at`,
+	"financial_goals":         `SELECT to_jsonb(fg) FROM financial_goals fg WHERE fg.user_id = $1 ORDER BY fg.created_at`,
+	"chat_sessions":           `SELECT to_jsonb(cs) FROM chat_sessions cs WHERE cs.user_id = $1 ORDER BY cs.created_at`,
+	"chat_messages":           `SELECT to_jsonb(cm) - 'message_embedding' FROM chat_messages cm JOIN chat_sessions cs ON cs.chat_session_id = cm.chat_session_id WHERE cs.user_id = $1 ORDER BY cm.created_at`,
+	"log_messages":            `SELECT to_jsonb(lm) FROM log_messages lm WHERE lm.user_id = $1 ORDER BY lm.created_at`,
+	"insights":                `SELECT to_jsonb(i) - 'content_embedding' FROM insights i WHERE i.user_id = $1 ORDER BY i.created_at`,
+	"ai_summaries":            `SELECT to_jsonb(s) FROM ai_summaries s WHERE s.user_id = $1 ORDER BY s.created_at`,
+	"ai_recommendations":      `SELECT to_jsonb(ar) - 'content_embedding' FROM ai_recommendations ar WHERE ar.user_id = $1 ORDER BY ar.created_at`,
+	"monthly_reports":         `SELECT to_jsonb(mr) FROM monthly_reports mr WHERE mr.user_id = $1 ORDER BY mr.year, mr.month`,
+}
+
+// ExportUserTable streams the user's rows of one table as JSON objects
+func (r *userRepository) ExportUserTable(userId, table string, fn func(row json.RawMessage) error) error {
+	query, ok := userDataQueries[table]
+	if !ok {
+		return fmt.Errorf("no export query for table %s", table)
+	}
+
 	db := r.DB.Connection()
-	query := `DELETE FROM users WHERE user_id = $1`
-	_, err := db.Exec(query, userId)
+	rows, err := db.Query(query, userId)
 	if err != nil {
 		return err
 	}
+	defer rows.Close()
 
-	return nil
+	for rows.Next() {
+		var row []byte
+		if err := rows.Scan(&row); err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *userRepository) GetPreferences(userId string) (*models.UserPreferences, error) {
+	db := r.DB.Connection()
+	query := `SELECT COALESCE(ai_preferences, '{}'::JSONB) FROM users WHERE user_id = $1`
+
+	var data []byte
+	if err := db.QueryRow(query, userId).Scan(&data); err != nil {
+		return nil, err
+	}
+
+	prefs := models.DefaultUserPreferences()
+	if err := json.Unmarshal(data, &prefs); err != nil {
+		return nil, fmt.Errorf("invalid ai_preferences of user %s: %w", userId, err)
+	}
+	return &prefs, nil
+}
+
+// UpdatePreferences writes the preferences and, when baseCurrency is set, the base currency in one
+// transaction. The base currency only changes while the user has no transactions.
+func (r *userRepository) UpdatePreferences(userId string, prefs *models.UserPreferences, baseCurrency string) error {
+	data, err := json.Marshal(prefs)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.DB.StartTransaction()
+	if err != nil {
+		return err
+	}
+	defer r.DB.RollbackTransaction(tx)
+
+	query := `UPDATE users SET ai_preferences = $1, updated_at = NOW() WHERE user_id = $2`
+	result, err := tx.Exec(query, data, userId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if baseCurrency != "" {
+		query = `UPDATE users SET base_currency = $1
+        WHERE user_id = $2 AND NOT EXISTS (SELECT 1 FROM transactions WHERE user_id = $2)`
+		result, err = tx.Exec(query, baseCurrency, userId)
+		if err != nil {
+			return err
+		}
+
+		affected, err = result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return currency.ErrBaseCurrencyLocked
+		}
+	}
+
+	return r.DB.CommitTransaction(tx)
+}
+
+// notificationEnabled is a condition that skips the users who switched the notification off in
+// users.ai_preferences, userIdColumn is the user id column of the outer query. A missing key counts as on.
+func notificationEnabled(userIdColumn, notification string) string {
+	return `NOT EXISTS (
+        SELECT 1 FROM users u
+        WHERE u.user_id = ` + userIdColumn + `
+        AND u.ai_preferences->'notifications'->>'` + notification + `' = 'false'
+    )`
 }
@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/domains/currency"
+	"github.com/saufiroja/fin-ai/pkg/databases"
+	"github.com/saufiroja/fin-ai/pkg/fx"
+)
+
+type currencyRepository struct {
+	DB databases.PostgresManager
+}
+
+func NewCurrencyRepository(db databases.PostgresManager) currency.CurrencyStorer {
+	return &currencyRepository{
+		DB: db,
+	}
+}
+
+func (r *currencyRepository) LatestRates(date time.Time) ([]fx.Rate, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT DISTINCT ON (base_code, quote_code)
+        base_code, quote_code, rate_date, rate, source
+    FROM fx_rates
+    WHERE rate_date <= $1::date
+    ORDER BY base_code, quote_code, rate_date DESC`
+
+	rows, err := db.Query(query, date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rates []fx.Rate
+	for rows.Next() {
+		var rate fx.Rate
+		if err := rows.Scan(&rate.Base, &rate.Quote, &rate.Date, &rate.Value, &rate.Source); err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+
+	return rates, rows.Err()
+}
+
+func (r *currencyRepository) UpsertRates(rates []fx.Rate) error {
+	tx, err := r.DB.StartTransaction()
+	if err != nil {
+		return err
+	}
+	defer r.DB.RollbackTransaction(tx)
+
+	query := `
+    INSERT INTO fx_rates (base_code, quote_code, rate_date, rate, source, created_at, updated_at)
+    VALUES ($1, $2, $3::date, $4, $5, NOW(), NOW())
+    ON CONFLICT (base_code, quote_code, rate_date) DO UPDATE SET
+        rate = EXCLUDED.rate,
+        source = EXCLUDED.source,
+        updated_at = EXCLUDED.updated_at`
+
+	for _, rate := range rates {
+		if _, err := tx.Exec(query, rate.Base, rate.Quote, rate.Date, rate.Value, rate.Source); err != nil {
+			return err
+		}
+	}
+
+	return r.DB.CommitTransaction(tx)
+}
+
+func (r *currencyRepository) GetBaseCurrency(userId string) (string, error) {
+	db := r.DB.Connection()
+
+	var code string
+	err := db.QueryRow(`SELECT base_currency FROM users WHERE user_id = $1`, userId).Scan(&code)
+	if err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+func (r *currencyRepository) SetBaseCurrency(userId, code string) error {
+	db := r.DB.Connection()
+
+	query := `UPDATE users SET base_currency = $1, updated_at = NOW() WHERE user_id = $2`
+	_, err := db.Exec(query, code, userId)
+	return err
+}
+
+func (r *currencyRepository) CountUserTransactions(userId string) (int64, error) {
+	db := r.DB.Connection()
+
+	var count int64
+	err := db.QueryRow(`SELECT COUNT(*) FROM transactions WHERE user_id = $1`, userId).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/domains/forecast"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/pkg/databases"
+)
+
+type forecastRepository struct {
+	DB databases.PostgresManager
+}
+
+func NewForecastRepository(db databases.PostgresManager) forecast.ForecastStorer {
+	return &forecastRepository{
+		DB: db,
+	}
+}
+
+func (f *forecastRepository) GetBalance(userId string, until time.Time) (int64, error) {
+	db := f.DB.Connection()
+
+	query := `
+    SELECT COALESCE(SUM(CASE WHEN type = 'income' THEN amount WHEN type = 'expense' THEN -amount ELSE 0 END), 0)
+    FROM transactions
+    WHERE user_id = $1
+    AND transaction_date < $2`
+
+	var balance int64
+	err := db.QueryRow(query, userId, until).Scan(&balance)
+	if err != nil {
+		return 0, err
+	}
+
+	return balance, nil
+}
+
+func (f *forecastRepository) GetFirstTransactionDate(userId string) (*time.Time, error) {
+	db := f.DB.Connection()
+
+	query := `SELECT MIN(transaction_date) FROM transactions WHERE user_id = $1`
+
+	var first sql.NullTime
+	err := db.QueryRow(query, userId).Scan(&first)
+	if err != nil {
+		return nil, err
+	}
+	if !first.Valid {
+		return nil, nil
+	}
+
+	return &first.Time, nil
+}
+
+func (f *forecastRepository) GetDailyEntries(userId string, since, until time.Time, excludeRecurring bool) ([]models.ForecastEntry, error) {
+	db := f.DB.Connection()
+
+	query := `
+    SELECT
+        date_trunc('day', transaction_date) AS day,
+        COALESCE(category_id, ''),
+        type,
+        SUM(amount)
+    FROM ` + transactionLines + ` lines
+    WHERE user_id = $1
+    AND transaction_date >= $2
+    AND transaction_date < $3
+    AND type IN ('income', 'expense')
+    AND (NOT $4 OR source <> $5)
+    GROUP BY day, category_id, type
+    ORDER BY day`
+
+	rows, err := db.Query(query, userId, since, until, excludeRecurring, constants.RecurringSource)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.ForecastEntry
+	for rows.Next() {
+		entry := models.ForecastEntry{}
+		err := rows.Scan(
+			&entry.Date,
+			&entry.CategoryId,
+			&entry.Type,
+			&entry.Amount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func (f *forecastRepository) GetCategoryNames(categoryIds []string) (map[string]string, error) {
+	db := f.DB.Connection()
+
+	query := `SELECT category_id, name FROM categories WHERE category_id = ANY($1)`
+
+	rows, err := db.Query(query, pq.Array(categoryIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := make(map[string]string, len(categoryIds))
+	for rows.Next() {
+		var id, name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, err
+		}
+		names[id] = name
+	}
+
+	return names, nil
+}
@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"encoding/json"
+
 	"github.com/saufiroja/fin-ai/internal/domains/log_message"
 	"github.com/saufiroja/fin-ai/internal/models"
 	"github.com/saufiroja/fin-ai/pkg/databases"
@@ -19,11 +21,22 @@ func NewLogMessageRepository(db databases.PostgresManager) log_message.LogMessag
 func (r *logMessageRepository) InsertLogMessage(logMessage *models.LogMessage) error {
 	db := r.DB.Connection()
 
-	query := `INSERT INTO log_messages (log_messages_id, user_id, message, response, input_token, output_token, topic, model, created_at, updated_at)
-        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())`
+	promptVersions := []byte("{}")
+	if len(logMessage.PromptVersions) > 0 {
+		encoded, err := json.Marshal(logMessage.PromptVersions)
+		if err != nil {
+			return err
+		}
+		promptVersions = encoded
+	}
+
+	// Calls that are not made for a user are stored without user_id
+	query := `INSERT INTO log_messages (log_messages_id, user_id, message, response, input_token, output_token, topic, model, prompt_versions, parse_failed, cost, created_at, updated_at)
+        VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7, $8, $9, $10, $11, NOW(), NOW())`
 
 	_, err := db.Exec(query, logMessage.LogMessageId, logMessage.UserId, logMessage.Message, logMessage.Response,
-		logMessage.InputToken, logMessage.OutputToken, logMessage.Topic, logMessage.Model)
+		logMessage.InputToken, logMessage.OutputToken, logMessage.Topic, logMessage.Model, promptVersions, logMessage.ParseFailed,
+		logMessage.Cost)
 	if err != nil {
 		return err
 	}
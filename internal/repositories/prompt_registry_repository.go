@@ -0,0 +1,274 @@
+package repositories
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/saufiroja/fin-ai/internal/domains/prompt_registry"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/pkg/databases"
+)
+
+type promptRegistryRepository struct {
+	DB databases.PostgresManager
+}
+
+func NewPromptRegistryRepository(db databases.PostgresManager) prompt_registry.PromptRegistryStorer {
+	return &promptRegistryRepository{
+		DB: db,
+	}
+}
+
+const promptExperimentColumns = `
+        prompt_experiment_id, prompt_key, control_version, candidate_version, candidate_percent,
+        started_at, ended_at, created_at, updated_at`
+
+func (r *promptRegistryRepository) scanExperiment(scanner interface{ Scan(...any) error }) (*models.PromptExperiment, error) {
+	experiment := &models.PromptExperiment{}
+	err := scanner.Scan(
+		&experiment.PromptExperimentId,
+		&experiment.PromptKey,
+		&experiment.ControlVersion,
+		&experiment.CandidateVersion,
+		&experiment.CandidatePercent,
+		&experiment.StartedAt,
+		&experiment.EndedAt,
+		&experiment.CreatedAt,
+		&experiment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return experiment, nil
+}
+
+func (r *promptRegistryRepository) GetPromptVersions() ([]models.PromptVersion, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT prompt_version_id, prompt_key, version, locale, content, COALESCE(description, ''), source,
+        is_active, created_at, updated_at
+    FROM prompt_versions
+    ORDER BY prompt_key, created_at, locale`
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []models.PromptVersion
+	for rows.Next() {
+		var version models.PromptVersion
+		err := rows.Scan(
+			&version.PromptVersionId,
+			&version.PromptKey,
+			&version.Version,
+			&version.Locale,
+			&version.Content,
+			&version.Description,
+			&version.Source,
+			&version.IsActive,
+			&version.CreatedAt,
+			&version.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+
+	return versions, rows.Err()
+}
+
+func (r *promptRegistryRepository) InsertPromptVersion(version *models.PromptVersion) (bool, error) {
+	db := r.DB.Connection()
+
+	// A version that was activated before keeps serving every locale it is recorded in
+	query := `
+    INSERT INTO prompt_versions (
+        prompt_version_id, prompt_key, version, locale, content, description, source, is_active, created_at
+    )
+    VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, EXISTS (
+        SELECT 1 FROM prompt_versions WHERE prompt_key = $2 AND version = $3 AND is_active
+    ), $8)
+    ON CONFLICT (prompt_key, version, locale) DO NOTHING`
+
+	result, err := db.Exec(query, version.PromptVersionId, version.PromptKey, version.Version, version.Locale,
+		version.Content, version.Description, version.Source, version.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *promptRegistryRepository) ActivatePromptVersion(promptKey, version string) error {
+	tx, err := r.DB.StartTransaction()
+	if err != nil {
+		return err
+	}
+	defer r.DB.RollbackTransaction(tx)
+
+	query := `
+    UPDATE prompt_versions
+    SET is_active = FALSE, updated_at = NOW()
+    WHERE prompt_key = $1 AND is_active`
+
+	if _, err := tx.Exec(query, promptKey); err != nil {
+		return err
+	}
+
+	if version != "" {
+		query = `
+        UPDATE prompt_versions
+        SET is_active = TRUE, updated_at = NOW()
+        WHERE prompt_key = $1 AND version = $2`
+
+		result, err := tx.Exec(query, promptKey, version)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+	}
+
+	return r.DB.CommitTransaction(tx)
+}
+
+func (r *promptRegistryRepository) GetPromptExperiments() ([]models.PromptExperiment, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT ` + promptExperimentColumns + `
+    FROM prompt_experiments
+    ORDER BY started_at DESC`
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var experiments []models.PromptExperiment
+	for rows.Next() {
+		experiment, err := r.scanExperiment(rows)
+		if err != nil {
+			return nil, err
+		}
+		experiments = append(experiments, *experiment)
+	}
+
+	return experiments, rows.Err()
+}
+
+func (r *promptRegistryRepository) GetPromptExperimentById(experimentId string) (*models.PromptExperiment, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT ` + promptExperimentColumns + `
+    FROM prompt_experiments
+    WHERE prompt_experiment_id = $1`
+
+	return r.scanExperiment(db.QueryRow(query, experimentId))
+}
+
+func (r *promptRegistryRepository) InsertPromptExperiment(experiment *models.PromptExperiment) error {
+	db := r.DB.Connection()
+
+	query := `
+    INSERT INTO prompt_experiments (
+        prompt_experiment_id, prompt_key, control_version, candidate_version, candidate_percent,
+        started_at, created_at
+    )
+    VALUES ($1, $2, $3, $4, $5, NOW(), NOW())`
+
+	_, err := db.Exec(query, experiment.PromptExperimentId, experiment.PromptKey, experiment.ControlVersion,
+		experiment.CandidateVersion, experiment.CandidatePercent)
+	return err
+}
+
+func (r *promptRegistryRepository) EndPromptExperiment(experimentId string) error {
+	db := r.DB.Connection()
+
+	query := `
+    UPDATE prompt_experiments
+    SET ended_at = NOW(), updated_at = NOW()
+    WHERE prompt_experiment_id = $1 AND ended_at IS NULL`
+
+	result, err := db.Exec(query, experimentId)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *promptRegistryRepository) GetPromptVersionOutcomes(promptKey string, versions []string, from time.Time, to *time.Time) ([]models.PromptVersionOutcome, error) {
+	db := r.DB.Connection()
+
+	// A call counts as corrected when the user recategorized an item of the receipt it extracted
+	query := `
+    SELECT
+        lm.prompt_versions ->> $1 AS version,
+        COUNT(*),
+        COUNT(DISTINCT lm.user_id),
+        COUNT(*) FILTER (WHERE lm.parse_failed),
+        COUNT(corrections.log_message_id),
+        COALESCE(SUM(lm.input_token), 0),
+        COALESCE(SUM(lm.output_token), 0)
+    FROM log_messages lm
+    LEFT JOIN LATERAL (
+        SELECT r.log_message_id
+        FROM receipts r
+        JOIN receipt_items ri ON ri.receipt_id = r.receipt_id
+        WHERE r.log_message_id = lm.log_messages_id AND ri.corrected
+        LIMIT 1
+    ) corrections ON TRUE
+    WHERE lm.prompt_versions ->> $1 = ANY($2)
+    AND lm.created_at >= $3
+    AND ($4::timestamp IS NULL OR lm.created_at < $4)
+    GROUP BY 1`
+
+	rows, err := db.Query(query, promptKey, pq.Array(versions), from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var outcomes []models.PromptVersionOutcome
+	for rows.Next() {
+		var outcome models.PromptVersionOutcome
+		err := rows.Scan(
+			&outcome.Version,
+			&outcome.Calls,
+			&outcome.Users,
+			&outcome.ParseFailures,
+			&outcome.CorrectedCalls,
+			&outcome.InputTokens,
+			&outcome.OutputTokens,
+		)
+		if err != nil {
+			return nil, err
+		}
+		outcomes = append(outcomes, outcome)
+	}
+
+	return outcomes, rows.Err()
+}
@@ -3,6 +3,8 @@ package repositories
 import (
 	"fmt"
 
+	"github.com/lib/pq"
+	"github.com/saufiroja/fin-ai/internal/constants"
 	"github.com/saufiroja/fin-ai/internal/contracts/requests"
 	"github.com/saufiroja/fin-ai/internal/domains/receipt"
 	"github.com/saufiroja/fin-ai/internal/models"
@@ -35,11 +37,12 @@ func (r *receiptRepository) InsertReceipt(receipt *models.Receipt) error {
     confirmed,
     transaction_date, 
     created_at, 
-    updated_at
+    updated_at,
+    log_message_id
     ) 
-    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, NULLIF($14, ''))`
 
-	_, err := db.Exec(query, receipt.ReceiptId, receipt.UserId, receipt.MerchantName, receipt.SubTotal, receipt.TotalDiscount, receipt.TotalShopping, receipt.MetaData, receipt.ExtractedReceipt, receipt.ExtractedReceiptEmbedding, receipt.Confirmed, receipt.TransactionDate, receipt.CreatedAt, receipt.UpdatedAt)
+	_, err := db.Exec(query, receipt.ReceiptId, receipt.UserId, receipt.MerchantName, receipt.SubTotal, receipt.TotalDiscount, receipt.TotalShopping, receipt.MetaData, receipt.ExtractedReceipt, receipt.ExtractedReceiptEmbedding, receipt.Confirmed, receipt.TransactionDate, receipt.CreatedAt, receipt.UpdatedAt, receipt.LogMessageId)
 	if err != nil {
 		return err
 	}
@@ -62,11 +65,12 @@ func (r *receiptRepository) InsertReceiptItem(receiptItem *models.ReceiptItem) e
     created_at, 
     updated_at,
 	category_id,
-	ai_category_confidence
+	ai_category_confidence,
+	item_name_embedding
     )
-    VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW(), $8, $9)`
+    VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW(), $8, $9, $10)`
 
-	_, err := db.Exec(query, receiptItem.ReceiptItemId, receiptItem.ReceiptId, receiptItem.ItemName, receiptItem.ItemQuantity, receiptItem.ItemPrice, receiptItem.ItemPriceTotal, receiptItem.ItemDiscount, receiptItem.CategoryId, receiptItem.AiCategoryConfidence)
+	_, err := db.Exec(query, receiptItem.ReceiptItemId, receiptItem.ReceiptId, receiptItem.ItemName, receiptItem.ItemQuantity, receiptItem.ItemPrice, receiptItem.ItemPriceTotal, receiptItem.ItemDiscount, receiptItem.CategoryId, receiptItem.AiCategoryConfidence, receiptItem.ItemNameEmbedding)
 	if err != nil {
 		return err
 	}
@@ -193,7 +197,13 @@ func (r *receiptRepository) GetDetailReceiptUserById(userId string, receiptId st
         r.confirmed,
         r.transaction_date,
         r.created_at,
-        r.updated_at        
+        r.updated_at,
+        COALESCE((
+            SELECT array_agg(tg.name ORDER BY tg.name)
+            FROM receipt_tags rt
+            JOIN tags tg ON tg.tag_id = rt.tag_id
+            WHERE rt.receipt_id = r.receipt_id
+        ), '{}')
     FROM receipts r
     WHERE r.user_id = $1 AND r.receipt_id = $2`
 
@@ -211,6 +221,7 @@ func (r *receiptRepository) GetDetailReceiptUserById(userId string, receiptId st
 		&receipt.TransactionDate,
 		&receipt.CreatedAt,
 		&receipt.UpdatedAt,
+		pq.Array(&receipt.Tags),
 	); err != nil {
 		return nil, err
 	}
@@ -232,7 +243,8 @@ func (r *receiptRepository) GetReceiptItemsByReceiptId(receiptId string) ([]*mod
         created_at, 
         updated_at,
 		category_id,
-		ai_category_confidence
+		ai_category_confidence,
+		confirmed
     FROM receipt_items
     WHERE receipt_id = $1`
 
@@ -245,7 +257,7 @@ func (r *receiptRepository) GetReceiptItemsByReceiptId(receiptId string) ([]*mod
 	var items []*models.ReceiptItem
 	for rows.Next() {
 		var item models.ReceiptItem
-		if err := rows.Scan(&item.ReceiptItemId, &item.ReceiptId, &item.ItemName, &item.ItemQuantity, &item.ItemPrice, &item.ItemPriceTotal, &item.ItemDiscount, &item.CreatedAt, &item.UpdatedAt, &item.CategoryId, &item.AiCategoryConfidence); err != nil {
+		if err := rows.Scan(&item.ReceiptItemId, &item.ReceiptId, &item.ItemName, &item.ItemQuantity, &item.ItemPrice, &item.ItemPriceTotal, &item.ItemDiscount, &item.CreatedAt, &item.UpdatedAt, &item.CategoryId, &item.AiCategoryConfidence, &item.Confirmed); err != nil {
 			return nil, err
 		}
 		items = append(items, &item)
@@ -287,3 +299,196 @@ func (r *receiptRepository) CountReceiptsByUserId(userId string, req *requests.G
 
 	return count, nil
 }
+
+func (r *receiptRepository) SearchReceiptsByEmbedding(userId, embedding string, limit int, threshold float64) ([]models.ReceiptWithScore, error) {
+	tx, err := r.DB.StartTransaction()
+	if err != nil {
+		return nil, err
+	}
+	defer r.DB.RollbackTransaction(tx)
+
+	// Widen the HNSW candidate list so the user_id filter still leaves enough rows
+	if _, err := tx.Exec(fmt.Sprintf("SET LOCAL hnsw.ef_search = %d", constants.RAGHNSWEfSearch)); err != nil {
+		return nil, err
+	}
+
+	query := `
+    SELECT
+        receipt_id,
+        user_id,
+        merchant_name,
+        sub_total,
+        total_discount,
+        total_shopping,
+        confirmed,
+        transaction_date,
+        created_at,
+        updated_at,
+        1 - (extracted_receipt_embedding <=> $2::vector) AS score
+    FROM receipts
+    WHERE user_id = $1
+    AND 1 - (extracted_receipt_embedding <=> $2::vector) >= $3
+    ORDER BY extracted_receipt_embedding <=> $2::vector
+    LIMIT $4`
+
+	rows, err := tx.Query(query, userId, embedding, threshold, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var receipts []models.ReceiptWithScore
+	for rows.Next() {
+		receipt := &models.Receipt{}
+		var score float64
+		if err := rows.Scan(
+			&receipt.ReceiptId,
+			&receipt.UserId,
+			&receipt.MerchantName,
+			&receipt.SubTotal,
+			&receipt.TotalDiscount,
+			&receipt.TotalShopping,
+			&receipt.Confirmed,
+			&receipt.TransactionDate,
+			&receipt.CreatedAt,
+			&receipt.UpdatedAt,
+			&score,
+		); err != nil {
+			return nil, err
+		}
+		receipts = append(receipts, models.ReceiptWithScore{
+			Receipt: receipt,
+			Score:   score,
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return receipts, r.DB.CommitTransaction(tx)
+}
+
+func (r *receiptRepository) SearchReceiptItemsByEmbedding(userId, embedding string, limit int, threshold float64) ([]models.ReceiptItemWithScore, error) {
+	tx, err := r.DB.StartTransaction()
+	if err != nil {
+		return nil, err
+	}
+	defer r.DB.RollbackTransaction(tx)
+
+	// Widen the HNSW candidate list so the user_id filter still leaves enough rows
+	if _, err := tx.Exec(fmt.Sprintf("SET LOCAL hnsw.ef_search = %d", constants.RAGHNSWEfSearch)); err != nil {
+		return nil, err
+	}
+
+	query := `
+    SELECT
+        ri.receipt_item_id,
+        ri.receipt_id,
+        ri.item_name,
+        ri.item_quantity,
+        ri.item_price,
+        ri.item_price_total,
+        ri.item_discount,
+        ri.created_at,
+        ri.updated_at,
+        ri.category_id,
+        ri.ai_category_confidence,
+        ri.confirmed,
+        COALESCE(r.merchant_name, ''),
+        r.transaction_date,
+        1 - (ri.item_name_embedding <=> $2::vector) AS score
+    FROM receipt_items ri
+    JOIN receipts r ON r.receipt_id = ri.receipt_id
+    WHERE r.user_id = $1
+    AND ri.item_name_embedding IS NOT NULL
+    AND 1 - (ri.item_name_embedding <=> $2::vector) >= $3
+    ORDER BY ri.item_name_embedding <=> $2::vector
+    LIMIT $4`
+
+	rows, err := tx.Query(query, userId, embedding, threshold, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.ReceiptItemWithScore
+	for rows.Next() {
+		item := &models.ReceiptItem{}
+		result := models.ReceiptItemWithScore{ReceiptItem: item}
+		if err := rows.Scan(
+			&item.ReceiptItemId,
+			&item.ReceiptId,
+			&item.ItemName,
+			&item.ItemQuantity,
+			&item.ItemPrice,
+			&item.ItemPriceTotal,
+			&item.ItemDiscount,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+			&item.CategoryId,
+			&item.AiCategoryConfidence,
+			&item.Confirmed,
+			&result.MerchantName,
+			&result.TransactionDate,
+			&result.Score,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, r.DB.CommitTransaction(tx)
+}
+
+// GetUnembeddedReceiptItems returns items without a name embedding, grouped by user
+func (r *receiptRepository) GetUnembeddedReceiptItems(limit int) ([]models.UnembeddedReceiptItem, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT ri.receipt_item_id, r.user_id, ri.item_name
+    FROM receipt_items ri
+    JOIN receipts r ON r.receipt_id = ri.receipt_id
+    WHERE ri.item_name_embedding IS NULL
+    AND TRIM(ri.item_name) <> ''
+    ORDER BY r.user_id, ri.receipt_item_id
+    LIMIT $1`
+
+	rows, err := db.Query(query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.UnembeddedReceiptItem
+	for rows.Next() {
+		var item models.UnembeddedReceiptItem
+		if err := rows.Scan(&item.ReceiptItemId, &item.UserId, &item.ItemName); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+func (r *receiptRepository) UpdateReceiptItemEmbeddings(embeddings map[string]string) error {
+	tx, err := r.DB.StartTransaction()
+	if err != nil {
+		return err
+	}
+	defer r.DB.RollbackTransaction(tx)
+
+	query := `UPDATE receipt_items SET item_name_embedding = $1, updated_at = NOW() WHERE receipt_item_id = $2`
+	for receiptItemId, embedding := range embeddings {
+		if _, err := tx.Exec(query, embedding, receiptItemId); err != nil {
+			return err
+		}
+	}
+
+	return r.DB.CommitTransaction(tx)
+}
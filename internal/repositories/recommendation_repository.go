@@ -0,0 +1,151 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/domains/recommendation"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/pkg/databases"
+)
+
+type recommendationRepository struct {
+	DB databases.PostgresManager
+}
+
+func NewRecommendationRepository(db databases.PostgresManager) recommendation.RecommendationStorer {
+	return &recommendationRepository{
+		DB: db,
+	}
+}
+
+func (r *recommendationRepository) InsertRecommendation(rec *models.AIRecommendation) error {
+	db := r.DB.Connection()
+
+	query := `
+    INSERT INTO ai_recommendations (
+        recommendation_id, user_id, recommendation_type, title, content,
+        content_embedding, priority, is_read, expires_at, reference_key,
+        created_at, updated_at
+    ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''), $11, $12)`
+
+	_, err := db.Exec(query,
+		rec.RecommendationId,
+		rec.UserId,
+		rec.RecommendationType,
+		rec.Title,
+		rec.Content,
+		rec.ContentEmbedding,
+		rec.Priority,
+		rec.IsRead,
+		rec.ExpiredAt,
+		rec.ReferenceKey,
+		rec.CreatedAt,
+		rec.UpdatedAt,
+	)
+
+	return err
+}
+
+func (r *recommendationRepository) HasActiveRecommendation(userId, referenceKey string, now time.Time) (bool, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT EXISTS (
+        SELECT 1
+        FROM ai_recommendations
+        WHERE user_id = $1
+        AND reference_key = $2
+        AND (expires_at IS NULL OR expires_at > $3)
+    )`
+
+	var exists bool
+	err := db.QueryRow(query, userId, referenceKey, now).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// recommendationConditions filters $1 user, $2 now, $3 type (” for any) and $4 unread only
+const recommendationConditions = `
+    WHERE user_id = $1
+    AND (expires_at IS NULL OR expires_at > $2)
+    AND ($3 = '' OR recommendation_type::text = $3)
+    AND (NOT $4 OR is_read = FALSE)`
+
+func (r *recommendationRepository) GetRecommendations(userId string, req *requests.RecommendationQuery, now time.Time) ([]models.AIRecommendation, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT
+        recommendation_id, user_id, recommendation_type, title, content,
+        priority, is_read, expires_at, COALESCE(reference_key, ''),
+        created_at, COALESCE(updated_at, created_at)
+    FROM ai_recommendations` + recommendationConditions + `
+    ORDER BY is_read ASC,
+        CASE priority WHEN 'high' THEN 0 WHEN 'medium' THEN 1 ELSE 2 END,
+        created_at DESC
+    LIMIT $5 OFFSET $6`
+
+	rows, err := db.Query(query, userId, now, string(req.Type), req.UnreadOnly, req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recommendations []models.AIRecommendation
+	for rows.Next() {
+		rec := models.AIRecommendation{}
+		err := rows.Scan(
+			&rec.RecommendationId,
+			&rec.UserId,
+			&rec.RecommendationType,
+			&rec.Title,
+			&rec.Content,
+			&rec.Priority,
+			&rec.IsRead,
+			&rec.ExpiredAt,
+			&rec.ReferenceKey,
+			&rec.CreatedAt,
+			&rec.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		recommendations = append(recommendations, rec)
+	}
+
+	return recommendations, nil
+}
+
+func (r *recommendationRepository) CountRecommendations(userId string, req *requests.RecommendationQuery, now time.Time) (int64, error) {
+	db := r.DB.Connection()
+
+	query := `SELECT COUNT(*) FROM ai_recommendations` + recommendationConditions
+
+	var count int64
+	err := db.QueryRow(query, userId, now, string(req.Type), req.UnreadOnly).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *recommendationRepository) MarkRecommendationAsRead(userId, recommendationId string) (int64, error) {
+	db := r.DB.Connection()
+
+	query := `
+    UPDATE ai_recommendations
+    SET is_read = TRUE, updated_at = NOW()
+    WHERE user_id = $1 AND recommendation_id = $2`
+
+	result, err := db.Exec(query, userId, recommendationId)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
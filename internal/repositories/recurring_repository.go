@@ -0,0 +1,355 @@
+package repositories
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/domains/recurring"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/pkg/databases"
+)
+
+type recurringRepository struct {
+	DB databases.PostgresManager
+}
+
+func NewRecurringRepository(db databases.PostgresManager) recurring.RecurringStorer {
+	return &recurringRepository{
+		DB: db,
+	}
+}
+
+const recurringRuleColumns = `
+        recurring_rule_id, user_id, category_id, type, description, amount,
+        COALESCE(payment_method, ''), frequency, day_of_month, start_date, end_date,
+        next_run_date, is_active, created_at, COALESCE(updated_at, created_at)`
+
+func (r *recurringRepository) scanRecurringRule(scanner interface{ Scan(...any) error }) (*models.RecurringRule, error) {
+	rule := &models.RecurringRule{}
+	var dayOfMonth sql.NullInt64
+	var endDate, nextRunDate sql.NullTime
+	err := scanner.Scan(
+		&rule.RecurringRuleId,
+		&rule.UserId,
+		&rule.CategoryId,
+		&rule.Type,
+		&rule.Description,
+		&rule.Amount,
+		&rule.PaymentMethod,
+		&rule.Frequency,
+		&dayOfMonth,
+		&rule.StartDate,
+		&endDate,
+		&nextRunDate,
+		&rule.IsActive,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if dayOfMonth.Valid {
+		day := int(dayOfMonth.Int64)
+		rule.DayOfMonth = &day
+	}
+	if endDate.Valid {
+		rule.EndDate = &endDate.Time
+	}
+	if nextRunDate.Valid {
+		rule.NextRunDate = &nextRunDate.Time
+	}
+
+	return rule, nil
+}
+
+func (r *recurringRepository) InsertRecurringRule(rule *models.RecurringRule) error {
+	db := r.DB.Connection()
+
+	query := `
+    INSERT INTO recurring_rules (
+        recurring_rule_id, user_id, category_id, type, description, amount,
+        payment_method, frequency, day_of_month, start_date, end_date,
+        next_run_date, is_active, created_at, updated_at
+    )
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`
+
+	_, err := db.Exec(query,
+		rule.RecurringRuleId,
+		rule.UserId,
+		rule.CategoryId,
+		rule.Type,
+		rule.Description,
+		rule.Amount,
+		rule.PaymentMethod,
+		rule.Frequency,
+		rule.DayOfMonth,
+		rule.StartDate,
+		rule.EndDate,
+		rule.NextRunDate,
+		rule.IsActive,
+		rule.CreatedAt,
+		rule.UpdatedAt,
+	)
+
+	return err
+}
+
+func (r *recurringRepository) GetRecurringRulesByUserId(userId string) ([]*models.RecurringRule, error) {
+	db := r.DB.Connection()
+
+	query := `SELECT` + recurringRuleColumns + `
+    FROM recurring_rules
+    WHERE user_id = $1
+    ORDER BY next_run_date ASC NULLS LAST, created_at DESC`
+
+	rows, err := db.Query(query, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*models.RecurringRule
+	for rows.Next() {
+		rule, err := r.scanRecurringRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func (r *recurringRepository) GetRecurringRuleById(userId, ruleId string) (*models.RecurringRule, error) {
+	db := r.DB.Connection()
+
+	query := `SELECT` + recurringRuleColumns + `
+    FROM recurring_rules
+    WHERE user_id = $1 AND recurring_rule_id = $2`
+
+	return r.scanRecurringRule(db.QueryRow(query, userId, ruleId))
+}
+
+func (r *recurringRepository) UpdateRecurringRule(rule *models.RecurringRule) error {
+	db := r.DB.Connection()
+
+	query := `
+    UPDATE recurring_rules
+    SET category_id = $3,
+    type = $4,
+    description = $5,
+    amount = $6,
+    payment_method = $7,
+    frequency = $8,
+    day_of_month = $9,
+    start_date = $10,
+    end_date = $11,
+    next_run_date = $12,
+    is_active = $13,
+    updated_at = $14
+    WHERE user_id = $1 AND recurring_rule_id = $2`
+
+	_, err := db.Exec(query,
+		rule.UserId,
+		rule.RecurringRuleId,
+		rule.CategoryId,
+		rule.Type,
+		rule.Description,
+		rule.Amount,
+		rule.PaymentMethod,
+		rule.Frequency,
+		rule.DayOfMonth,
+		rule.StartDate,
+		rule.EndDate,
+		rule.NextRunDate,
+		rule.IsActive,
+		rule.UpdatedAt,
+	)
+
+	return err
+}
+
+func (r *recurringRepository) DeleteRecurringRule(userId, ruleId string) error {
+	db := r.DB.Connection()
+
+	query := `DELETE FROM recurring_rules WHERE user_id = $1 AND recurring_rule_id = $2`
+
+	_, err := db.Exec(query, userId, ruleId)
+	return err
+}
+
+func (r *recurringRepository) FindDueRecurringRules(date time.Time) ([]*models.RecurringRule, error) {
+	db := r.DB.Connection()
+
+	query := `SELECT` + recurringRuleColumns + `
+    FROM recurring_rules
+    WHERE is_active = TRUE
+    AND next_run_date IS NOT NULL
+    AND next_run_date <= $1::date
+    ORDER BY next_run_date ASC`
+
+	rows, err := db.Query(query, date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*models.RecurringRule
+	for rows.Next() {
+		rule, err := r.scanRecurringRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func (r *recurringRepository) UpdateNextRunDate(ruleId string, nextRunDate *time.Time, isActive bool) error {
+	db := r.DB.Connection()
+
+	query := `
+    UPDATE recurring_rules
+    SET next_run_date = $2, is_active = $3, updated_at = NOW()
+    WHERE recurring_rule_id = $1`
+
+	_, err := db.Exec(query, ruleId, nextRunDate, isActive)
+	return err
+}
+
+func (r *recurringRepository) GetOccurrences(ruleId string, start, end time.Time) ([]*models.RecurringOccurrence, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT
+        recurring_occurrence_id, recurring_rule_id, occurrence_date, status,
+        amount, description, transaction_id, created_at, COALESCE(updated_at, created_at)
+    FROM recurring_occurrences
+    WHERE recurring_rule_id = $1
+    AND occurrence_date BETWEEN $2::date AND $3::date
+    ORDER BY occurrence_date ASC`
+
+	rows, err := db.Query(query, ruleId, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var occurrences []*models.RecurringOccurrence
+	for rows.Next() {
+		occurrence := &models.RecurringOccurrence{}
+		var amount sql.NullInt64
+		var description, transactionId sql.NullString
+		if err := rows.Scan(
+			&occurrence.RecurringOccurrenceId,
+			&occurrence.RecurringRuleId,
+			&occurrence.OccurrenceDate,
+			&occurrence.Status,
+			&amount,
+			&description,
+			&transactionId,
+			&occurrence.CreatedAt,
+			&occurrence.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		if amount.Valid {
+			occurrence.Amount = &amount.Int64
+		}
+		if description.Valid {
+			occurrence.Description = &description.String
+		}
+		if transactionId.Valid {
+			occurrence.TransactionId = &transactionId.String
+		}
+		occurrences = append(occurrences, occurrence)
+	}
+
+	return occurrences, nil
+}
+
+func (r *recurringRepository) UpsertOccurrence(occurrence *models.RecurringOccurrence) error {
+	db := r.DB.Connection()
+
+	query := `
+    INSERT INTO recurring_occurrences (
+        recurring_occurrence_id, recurring_rule_id, occurrence_date, status,
+        amount, description, transaction_id, created_at, updated_at
+    )
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+    ON CONFLICT (recurring_rule_id, occurrence_date) DO UPDATE
+    SET status = EXCLUDED.status,
+    amount = EXCLUDED.amount,
+    description = EXCLUDED.description,
+    transaction_id = EXCLUDED.transaction_id,
+    updated_at = EXCLUDED.updated_at`
+
+	_, err := db.Exec(query,
+		occurrence.RecurringOccurrenceId,
+		occurrence.RecurringRuleId,
+		occurrence.OccurrenceDate,
+		occurrence.Status,
+		occurrence.Amount,
+		occurrence.Description,
+		occurrence.TransactionId,
+		occurrence.CreatedAt,
+		occurrence.UpdatedAt,
+	)
+
+	return err
+}
+
+// ClaimOccurrence records the occurrence as pending unless it is already skipped, pending or materialized.
+// The unique (recurring_rule_id, occurrence_date) constraint lets only one run claim a date, it returns false
+// when the date was not claimed.
+func (r *recurringRepository) ClaimOccurrence(occurrence *models.RecurringOccurrence) (bool, error) {
+	db := r.DB.Connection()
+
+	query := `
+    INSERT INTO recurring_occurrences (
+        recurring_occurrence_id, recurring_rule_id, occurrence_date, status,
+        amount, description, created_at, updated_at
+    )
+    VALUES ($1, $2, $3, 'pending', $4, $5, $6, $7)
+    ON CONFLICT (recurring_rule_id, occurrence_date) DO UPDATE
+    SET status = 'pending',
+    amount = EXCLUDED.amount,
+    description = EXCLUDED.description,
+    updated_at = EXCLUDED.updated_at
+    WHERE recurring_occurrences.status = 'overridden'`
+
+	result, err := db.Exec(query,
+		occurrence.RecurringOccurrenceId,
+		occurrence.RecurringRuleId,
+		occurrence.OccurrenceDate,
+		occurrence.Amount,
+		occurrence.Description,
+		occurrence.CreatedAt,
+		occurrence.UpdatedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return claimed > 0, nil
+}
+
+func (r *recurringRepository) DeletePendingOccurrence(ruleId string, date time.Time) error {
+	db := r.DB.Connection()
+
+	query := `
+    DELETE FROM recurring_occurrences
+    WHERE recurring_rule_id = $1
+    AND occurrence_date = $2::date
+    AND status = 'pending'`
+
+	_, err := db.Exec(query, ruleId, date)
+	return err
+}
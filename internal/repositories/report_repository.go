@@ -0,0 +1,315 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/domains/report"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/pkg/databases"
+)
+
+type reportRepository struct {
+	DB databases.PostgresManager
+}
+
+func NewReportRepository(db databases.PostgresManager) report.ReportStorer {
+	return &reportRepository{
+		DB: db,
+	}
+}
+
+const monthlyReportColumns = `
+        report_id, user_id, year, month, object_name, file_size, total_income, total_expense,
+        generated_at, created_at, COALESCE(updated_at, created_at)`
+
+func (r *reportRepository) scanReport(scanner interface{ Scan(...any) error }) (*models.MonthlyReport, error) {
+	rep := &models.MonthlyReport{}
+	err := scanner.Scan(
+		&rep.ReportId,
+		&rep.UserId,
+		&rep.Year,
+		&rep.Month,
+		&rep.ObjectName,
+		&rep.FileSize,
+		&rep.TotalIncome,
+		&rep.TotalExpense,
+		&rep.GeneratedAt,
+		&rep.CreatedAt,
+		&rep.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return rep, nil
+}
+
+func (r *reportRepository) UpsertReport(rep *models.MonthlyReport) error {
+	db := r.DB.Connection()
+
+	query := `
+    INSERT INTO monthly_reports (
+        report_id, user_id, year, month, object_name, file_size, total_income, total_expense,
+        generated_at, created_at, updated_at
+    )
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+    ON CONFLICT (user_id, year, month) DO UPDATE SET
+        object_name = EXCLUDED.object_name,
+        file_size = EXCLUDED.file_size,
+        total_income = EXCLUDED.total_income,
+        total_expense = EXCLUDED.total_expense,
+        generated_at = EXCLUDED.generated_at,
+        updated_at = EXCLUDED.updated_at
+    RETURNING report_id, created_at`
+
+	return db.QueryRow(query,
+		rep.ReportId,
+		rep.UserId,
+		rep.Year,
+		rep.Month,
+		rep.ObjectName,
+		rep.FileSize,
+		rep.TotalIncome,
+		rep.TotalExpense,
+		rep.GeneratedAt,
+		rep.CreatedAt,
+		rep.UpdatedAt,
+	).Scan(&rep.ReportId, &rep.CreatedAt)
+}
+
+func (r *reportRepository) GetReports(userId string, year, limit, offset int) ([]models.MonthlyReport, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT ` + monthlyReportColumns + `
+    FROM monthly_reports
+    WHERE user_id = $1 AND ($2 = 0 OR year = $2)
+    ORDER BY year DESC, month DESC
+    LIMIT $3 OFFSET $4`
+
+	rows, err := db.Query(query, userId, year, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reports []models.MonthlyReport
+	for rows.Next() {
+		rep, err := r.scanReport(rows)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, *rep)
+	}
+
+	return reports, rows.Err()
+}
+
+func (r *reportRepository) CountReports(userId string, year int) (int64, error) {
+	db := r.DB.Connection()
+
+	query := `SELECT COUNT(*) FROM monthly_reports WHERE user_id = $1 AND ($2 = 0 OR year = $2)`
+
+	var count int64
+	err := db.QueryRow(query, userId, year).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *reportRepository) GetReportById(userId, reportId string) (*models.MonthlyReport, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT ` + monthlyReportColumns + `
+    FROM monthly_reports
+    WHERE user_id = $1 AND report_id = $2`
+
+	return r.scanReport(db.QueryRow(query, userId, reportId))
+}
+
+func (r *reportRepository) GetReportByPeriod(userId string, year, month int) (*models.MonthlyReport, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT ` + monthlyReportColumns + `
+    FROM monthly_reports
+    WHERE user_id = $1 AND year = $2 AND month = $3`
+
+	return r.scanReport(db.QueryRow(query, userId, year, month))
+}
+
+func (r *reportRepository) GetBudgetUsage(userId string, year, month int) ([]models.BudgetUsage, error) {
+	db := r.DB.Connection()
+
+	// Split lines count towards the budget of their own category
+	query := `
+    SELECT
+        b.budget_id,
+        COALESCE(b.category_id, ''),
+        COALESCE(c.name, 'Uncategorized'),
+        b.amount_limit,
+        COALESCE((
+            SELECT SUM(t.amount)
+            FROM ` + transactionLines + ` t
+            WHERE t.user_id = b.user_id
+            AND t.category_id = b.category_id
+            AND t.type = 'expense'
+            AND t.transaction_date >= make_date(b.year, b.month, 1)
+            AND t.transaction_date < make_date(b.year, b.month, 1) + INTERVAL '1 month'
+        ), 0) AS spent
+    FROM budgets b
+    LEFT JOIN categories c ON c.category_id = b.category_id
+    WHERE b.user_id = $1 AND b.year = $2 AND b.month = $3
+    ORDER BY spent DESC, c.name`
+
+	rows, err := db.Query(query, userId, year, month)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var budgets []models.BudgetUsage
+	for rows.Next() {
+		var budget models.BudgetUsage
+		if err := rows.Scan(
+			&budget.BudgetId,
+			&budget.CategoryId,
+			&budget.CategoryName,
+			&budget.AmountLimit,
+			&budget.Spent,
+		); err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, budget)
+	}
+
+	return budgets, rows.Err()
+}
+
+func (r *reportRepository) GetGoals(userId string) ([]models.FinancialGoal, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT
+        financial_goal_id, user_id, title, COALESCE(description, ''), target_amount,
+        COALESCE(current_amount, 0), target_date, COALESCE(status, 'active'), created_at,
+        COALESCE(updated_at, created_at)
+    FROM financial_goals
+    WHERE user_id = $1
+    ORDER BY status = 'completed', target_date NULLS LAST, created_at`
+
+	rows, err := db.Query(query, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var goals []models.FinancialGoal
+	for rows.Next() {
+		var goal models.FinancialGoal
+		var targetDate sql.NullTime
+		if err := rows.Scan(
+			&goal.FinancialGoalId,
+			&goal.UserId,
+			&goal.Title,
+			&goal.Description,
+			&goal.TargetAmount,
+			&goal.CurrentAmount,
+			&targetDate,
+			&goal.Status,
+			&goal.CreatedAt,
+			&goal.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		goal.TargetDate = targetDate.Time
+		goals = append(goals, goal)
+	}
+
+	return goals, rows.Err()
+}
+
+// GetPeriodSummary returns the latest monthly summary starting at periodStart
+func (r *reportRepository) GetPeriodSummary(userId string, periodStart time.Time) (*models.PeriodSummary, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT summary_data
+    FROM ai_summaries
+    WHERE user_id = $1 AND period_type = $2 AND period_start = $3::date
+    ORDER BY created_at DESC
+    LIMIT 1`
+
+	var data []byte
+	if err := db.QueryRow(query, userId, constants.PeriodTypeMonthly, periodStart).Scan(&data); err != nil {
+		return nil, err
+	}
+
+	summary := &models.PeriodSummary{}
+	if err := json.Unmarshal(data, summary); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+func (r *reportRepository) InsertPeriodSummary(summary *models.AISummary) error {
+	db := r.DB.Connection()
+
+	data, err := json.Marshal(summary.SummaryData)
+	if err != nil {
+		return err
+	}
+
+	query := `
+    INSERT INTO ai_summaries (
+        summary_id, user_id, period_type, period_start, period_end, summary_data, created_at, updated_at
+    )
+    VALUES ($1, $2, $3, $4::date, $5::date, $6, $7, $8)`
+
+	_, err = db.Exec(query,
+		summary.SummaryId,
+		summary.UserId,
+		summary.PeriodType,
+		summary.PeriodStart,
+		summary.PeriodEnd,
+		data,
+		summary.CreatedAt,
+		summary.UpdatedAt,
+	)
+
+	return err
+}
+
+func (r *reportRepository) GetUserIdsWithTransactionsBetween(start, end time.Time) ([]string, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT DISTINCT user_id
+    FROM transactions
+    WHERE transaction_date >= $1
+    AND transaction_date < $2
+    AND user_id IS NOT NULL
+    AND ` + notificationEnabled("transactions.user_id", constants.NotificationMonthlyReport)
+
+	rows, err := db.Query(query, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIds []string
+	for rows.Next() {
+		var userId string
+		if err := rows.Scan(&userId); err != nil {
+			return nil, err
+		}
+		userIds = append(userIds, userId)
+	}
+
+	return userIds, rows.Err()
+}
@@ -0,0 +1,238 @@
+package repositories
+
+import (
+	"github.com/lib/pq"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/review"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/pkg/databases"
+)
+
+type reviewRepository struct {
+	DB databases.PostgresManager
+}
+
+func NewReviewRepository(db databases.PostgresManager) review.ReviewStorer {
+	return &reviewRepository{
+		DB: db,
+	}
+}
+
+func (r *reviewRepository) FindTransactionsForReview(userId string, req *requests.ReviewQueueQuery) ([]models.Transaction, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT
+        transaction_id, user_id, category_id, type, amount,
+        description, source, transaction_date,
+        ai_category_confidence, is_auto_categorized, created_at, updated_at,
+        confirmed, discount, payment_method
+    FROM transactions
+    WHERE user_id = $1
+    AND is_auto_categorized = TRUE
+    AND confirmed = FALSE
+    AND ai_category_confidence < $2
+    ORDER BY ai_category_confidence ASC, transaction_date DESC
+    LIMIT $3 OFFSET $4`
+
+	rows, err := db.Query(query, userId, req.Threshold, req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []models.Transaction
+	for rows.Next() {
+		transaction := models.Transaction{}
+		err := rows.Scan(
+			&transaction.TransactionId,
+			&transaction.UserId,
+			&transaction.CategoryId,
+			&transaction.Type,
+			&transaction.Amount,
+			&transaction.Description,
+			&transaction.Source,
+			&transaction.TransactionDate,
+			&transaction.AiCategoryConfidence,
+			&transaction.IsAutoCategorized,
+			&transaction.CreatedAt,
+			&transaction.UpdatedAt,
+			&transaction.Confirmed,
+			&transaction.Discount,
+			&transaction.PaymentMethod,
+		)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, nil
+}
+
+func (r *reviewRepository) CountTransactionsForReview(userId string, threshold float64) (int64, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT COUNT(*)
+    FROM transactions
+    WHERE user_id = $1
+    AND is_auto_categorized = TRUE
+    AND confirmed = FALSE
+    AND ai_category_confidence < $2`
+
+	var count int64
+	err := db.QueryRow(query, userId, threshold).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *reviewRepository) FindReceiptItemsForReview(userId string, req *requests.ReviewQueueQuery) ([]*responses.ReviewReceiptItem, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT
+        ri.receipt_item_id,
+        ri.receipt_id,
+        COALESCE(r.merchant_name, ''),
+        ri.item_name,
+        ri.item_quantity,
+        ri.item_price_total,
+        ri.category_id,
+        ri.ai_category_confidence,
+        r.transaction_date
+    FROM receipt_items ri
+    JOIN receipts r ON r.receipt_id = ri.receipt_id
+    WHERE r.user_id = $1
+    AND ri.confirmed = FALSE
+    AND ri.ai_category_confidence < $2
+    ORDER BY ri.ai_category_confidence ASC, r.transaction_date DESC
+    LIMIT $3 OFFSET $4`
+
+	rows, err := db.Query(query, userId, req.Threshold, req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*responses.ReviewReceiptItem
+	for rows.Next() {
+		var item responses.ReviewReceiptItem
+		if err := rows.Scan(
+			&item.ReceiptItemId,
+			&item.ReceiptId,
+			&item.MerchantName,
+			&item.ItemName,
+			&item.ItemQuantity,
+			&item.ItemPriceTotal,
+			&item.CategoryId,
+			&item.AiCategoryConfidence,
+			&item.TransactionDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &item)
+	}
+
+	return items, nil
+}
+
+func (r *reviewRepository) CountReceiptItemsForReview(userId string, threshold float64) (int64, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT COUNT(*)
+    FROM receipt_items ri
+    JOIN receipts r ON r.receipt_id = ri.receipt_id
+    WHERE r.user_id = $1
+    AND ri.confirmed = FALSE
+    AND ri.ai_category_confidence < $2`
+
+	var count int64
+	err := db.QueryRow(query, userId, threshold).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *reviewRepository) ConfirmTransactions(userId string, transactionIds []string) (int64, error) {
+	db := r.DB.Connection()
+
+	query := `
+    UPDATE transactions
+    SET confirmed = TRUE, updated_at = NOW()
+    WHERE user_id = $1 AND transaction_id = ANY($2)`
+
+	result, err := db.Exec(query, userId, pq.Array(transactionIds))
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func (r *reviewRepository) ConfirmReceiptItems(userId string, receiptItemIds []string) (int64, error) {
+	db := r.DB.Connection()
+
+	query := `
+    UPDATE receipt_items ri
+    SET confirmed = TRUE, updated_at = NOW()
+    FROM receipts r
+    WHERE r.receipt_id = ri.receipt_id
+    AND r.user_id = $1
+    AND ri.receipt_item_id = ANY($2)`
+
+	result, err := db.Exec(query, userId, pq.Array(receiptItemIds))
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func (r *reviewRepository) RecategorizeTransactions(userId string, transactionIds []string, categoryId string) (int64, error) {
+	db := r.DB.Connection()
+
+	query := `
+    UPDATE transactions
+    SET category_id = $3,
+    is_auto_categorized = FALSE,
+    confirmed = TRUE,
+    updated_at = NOW()
+    WHERE user_id = $1 AND transaction_id = ANY($2)`
+
+	result, err := db.Exec(query, userId, pq.Array(transactionIds), categoryId)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func (r *reviewRepository) RecategorizeReceiptItems(userId string, receiptItemIds []string, categoryId string) (int64, error) {
+	db := r.DB.Connection()
+
+	query := `
+    UPDATE receipt_items ri
+    SET category_id = $3,
+    corrected = ri.corrected OR ri.category_id IS DISTINCT FROM $3,
+    confirmed = TRUE,
+    updated_at = NOW()
+    FROM receipts r
+    WHERE r.receipt_id = ri.receipt_id
+    AND r.user_id = $1
+    AND ri.receipt_item_id = ANY($2)`
+
+	result, err := db.Exec(query, userId, pq.Array(receiptItemIds), categoryId)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,359 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/domains/search"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/pkg/databases"
+)
+
+type searchRepository struct {
+	DB databases.PostgresManager
+}
+
+func NewSearchRepository(db databases.PostgresManager) search.SearchStorer {
+	return &searchRepository{
+		DB: db,
+	}
+}
+
+// withVectorSearch runs fn inside a transaction with a wider HNSW candidate list,
+// so the user_id and filter predicates still leave enough rows after the index scan
+func (r *searchRepository) withVectorSearch(fn func(tx *sql.Tx) error) error {
+	tx, err := r.DB.StartTransaction()
+	if err != nil {
+		return err
+	}
+	defer r.DB.RollbackTransaction(tx)
+
+	if _, err := tx.Exec(fmt.Sprintf("SET LOCAL hnsw.ef_search = %d", constants.RAGHNSWEfSearch)); err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return r.DB.CommitTransaction(tx)
+}
+
+func (r *searchRepository) KeywordSearchTransactions(userId string, req *requests.SearchQuery, limit int) ([]models.TransactionWithScore, error) {
+	db := r.DB.Connection()
+
+	// A transaction created from a receipt also matches on the merchant and item names, so a query can
+	// mix both (e.g. "indomaret susu") while the description only says "Belanja"
+	query := `
+    SELECT
+        t.transaction_id, t.user_id, COALESCE(t.category_id, ''), t.type, t.amount,
+        t.description, t.source, t.transaction_date,
+        t.ai_category_confidence, t.is_auto_categorized, t.created_at, t.updated_at,
+        t.confirmed, t.discount, t.payment_method,
+        ts_rank_cd(doc.search_vector, websearch_to_tsquery('simple', $2)) AS score
+    FROM transactions t
+    LEFT JOIN receipts r ON r.receipt_id = t.receipt_id
+    LEFT JOIN LATERAL (
+        SELECT to_tsvector('simple', string_agg(ri.item_name, ' ')) AS search_vector
+        FROM receipt_items ri
+        WHERE ri.receipt_id = t.receipt_id
+    ) items ON true
+    CROSS JOIN LATERAL (
+        SELECT t.search_vector
+            || COALESCE(r.search_vector, ''::tsvector)
+            || COALESCE(items.search_vector, ''::tsvector) AS search_vector
+    ) doc
+    WHERE t.user_id = $1
+    AND doc.search_vector @@ websearch_to_tsquery('simple', $2)
+    AND ($3 = '' OR t.category_id = $3)
+    AND (NULLIF($4, '') IS NULL OR NULLIF($5, '') IS NULL OR
+         t.transaction_date BETWEEN
+         ($4::date + INTERVAL '0 hours')::timestamp AND
+         ($5::date + INTERVAL '23 hours 59 minutes 59 seconds')::timestamp)
+    ORDER BY score DESC, t.transaction_date DESC
+    LIMIT $6`
+
+	rows, err := db.Query(query, userId, req.Query, req.CategoryId, req.StartDate, req.EndDate, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanTransactions(rows)
+}
+
+func (r *searchRepository) SemanticSearchTransactions(userId, embedding string, req *requests.SearchQuery, limit int) ([]models.TransactionWithScore, error) {
+	query := `
+    SELECT
+        transaction_id, user_id, COALESCE(category_id, ''), type, amount,
+        description, source, transaction_date,
+        ai_category_confidence, is_auto_categorized, created_at, updated_at,
+        confirmed, discount, payment_method,
+        1 - (description_embedding <=> $2::vector) AS score
+    FROM transactions
+    WHERE user_id = $1
+    AND 1 - (description_embedding <=> $2::vector) >= $7
+    AND ($3 = '' OR category_id = $3)
+    AND (NULLIF($4, '') IS NULL OR NULLIF($5, '') IS NULL OR
+         transaction_date BETWEEN
+         ($4::date + INTERVAL '0 hours')::timestamp AND
+         ($5::date + INTERVAL '23 hours 59 minutes 59 seconds')::timestamp)
+    ORDER BY description_embedding <=> $2::vector
+    LIMIT $6`
+
+	var transactions []models.TransactionWithScore
+	err := r.withVectorSearch(func(tx *sql.Tx) error {
+		rows, err := tx.Query(query, userId, embedding, req.CategoryId, req.StartDate, req.EndDate, limit, constants.SearchSimilarityThreshold)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		transactions, err = r.scanTransactions(rows)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+func (r *searchRepository) scanTransactions(rows *sql.Rows) ([]models.TransactionWithScore, error) {
+	var transactions []models.TransactionWithScore
+	for rows.Next() {
+		transaction := &models.Transaction{}
+		var score float64
+		err := rows.Scan(
+			&transaction.TransactionId,
+			&transaction.UserId,
+			&transaction.CategoryId,
+			&transaction.Type,
+			&transaction.Amount,
+			&transaction.Description,
+			&transaction.Source,
+			&transaction.TransactionDate,
+			&transaction.AiCategoryConfidence,
+			&transaction.IsAutoCategorized,
+			&transaction.CreatedAt,
+			&transaction.UpdatedAt,
+			&transaction.Confirmed,
+			&transaction.Discount,
+			&transaction.PaymentMethod,
+			&score,
+		)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, models.TransactionWithScore{
+			Transaction: transaction,
+			Score:       score,
+		})
+	}
+
+	return transactions, rows.Err()
+}
+
+func (r *searchRepository) KeywordSearchReceipts(userId string, req *requests.SearchQuery, limit int) ([]models.ReceiptWithScore, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT
+        r.receipt_id, r.user_id, r.merchant_name, r.sub_total,
+        r.total_discount, r.total_shopping, r.confirmed,
+        r.transaction_date, r.created_at, r.updated_at,
+        ts_rank_cd(r.search_vector, websearch_to_tsquery('simple', $2)) AS score
+    FROM receipts r
+    WHERE r.user_id = $1
+    AND r.search_vector @@ websearch_to_tsquery('simple', $2)
+    AND ($3 = '' OR EXISTS (
+        SELECT 1 FROM receipt_items ri
+        WHERE ri.receipt_id = r.receipt_id AND ri.category_id = $3
+    ))
+    AND (NULLIF($4, '') IS NULL OR NULLIF($5, '') IS NULL OR
+         r.transaction_date BETWEEN
+         ($4::date + INTERVAL '0 hours')::timestamp AND
+         ($5::date + INTERVAL '23 hours 59 minutes 59 seconds')::timestamp)
+    ORDER BY score DESC, r.transaction_date DESC
+    LIMIT $6`
+
+	rows, err := db.Query(query, userId, req.Query, req.CategoryId, req.StartDate, req.EndDate, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanReceipts(rows)
+}
+
+func (r *searchRepository) SemanticSearchReceipts(userId, embedding string, req *requests.SearchQuery, limit int) ([]models.ReceiptWithScore, error) {
+	query := `
+    SELECT
+        r.receipt_id, r.user_id, r.merchant_name, r.sub_total,
+        r.total_discount, r.total_shopping, r.confirmed,
+        r.transaction_date, r.created_at, r.updated_at,
+        1 - (r.extracted_receipt_embedding <=> $2::vector) AS score
+    FROM receipts r
+    WHERE r.user_id = $1
+    AND 1 - (r.extracted_receipt_embedding <=> $2::vector) >= $7
+    AND ($3 = '' OR EXISTS (
+        SELECT 1 FROM receipt_items ri
+        WHERE ri.receipt_id = r.receipt_id AND ri.category_id = $3
+    ))
+    AND (NULLIF($4, '') IS NULL OR NULLIF($5, '') IS NULL OR
+         r.transaction_date BETWEEN
+         ($4::date + INTERVAL '0 hours')::timestamp AND
+         ($5::date + INTERVAL '23 hours 59 minutes 59 seconds')::timestamp)
+    ORDER BY r.extracted_receipt_embedding <=> $2::vector
+    LIMIT $6`
+
+	var receipts []models.ReceiptWithScore
+	err := r.withVectorSearch(func(tx *sql.Tx) error {
+		rows, err := tx.Query(query, userId, embedding, req.CategoryId, req.StartDate, req.EndDate, limit, constants.SearchSimilarityThreshold)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		receipts, err = r.scanReceipts(rows)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return receipts, nil
+}
+
+func (r *searchRepository) scanReceipts(rows *sql.Rows) ([]models.ReceiptWithScore, error) {
+	var receipts []models.ReceiptWithScore
+	for rows.Next() {
+		receipt := &models.Receipt{}
+		var score float64
+		if err := rows.Scan(
+			&receipt.ReceiptId,
+			&receipt.UserId,
+			&receipt.MerchantName,
+			&receipt.SubTotal,
+			&receipt.TotalDiscount,
+			&receipt.TotalShopping,
+			&receipt.Confirmed,
+			&receipt.TransactionDate,
+			&receipt.CreatedAt,
+			&receipt.UpdatedAt,
+			&score,
+		); err != nil {
+			return nil, err
+		}
+		receipts = append(receipts, models.ReceiptWithScore{
+			Receipt: receipt,
+			Score:   score,
+		})
+	}
+
+	return receipts, rows.Err()
+}
+
+func (r *searchRepository) KeywordSearchReceiptItems(userId string, req *requests.SearchQuery, limit int) ([]models.ReceiptItemWithScore, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT
+        ri.receipt_item_id, ri.receipt_id, ri.item_name, ri.item_quantity,
+        ri.item_price, ri.item_price_total, ri.item_discount,
+        ri.created_at, ri.updated_at, ri.category_id,
+        ri.ai_category_confidence, ri.confirmed,
+        COALESCE(r.merchant_name, ''), r.transaction_date,
+        ts_rank_cd(ri.search_vector, websearch_to_tsquery('simple', $2)) AS score
+    FROM receipt_items ri
+    JOIN receipts r ON r.receipt_id = ri.receipt_id
+    WHERE r.user_id = $1
+    AND ri.search_vector @@ websearch_to_tsquery('simple', $2)
+    AND ($3 = '' OR ri.category_id = $3)
+    AND (NULLIF($4, '') IS NULL OR NULLIF($5, '') IS NULL OR
+         r.transaction_date BETWEEN
+         ($4::date + INTERVAL '0 hours')::timestamp AND
+         ($5::date + INTERVAL '23 hours 59 minutes 59 seconds')::timestamp)
+    ORDER BY score DESC, r.transaction_date DESC
+    LIMIT $6`
+
+	rows, err := db.Query(query, userId, req.Query, req.CategoryId, req.StartDate, req.EndDate, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanReceiptItems(rows)
+}
+
+func (r *searchRepository) SemanticSearchReceiptItems(userId, embedding string, req *requests.SearchQuery, limit int) ([]models.ReceiptItemWithScore, error) {
+	query := `
+    SELECT
+        ri.receipt_item_id, ri.receipt_id, ri.item_name, ri.item_quantity,
+        ri.item_price, ri.item_price_total, ri.item_discount,
+        ri.created_at, ri.updated_at, ri.category_id,
+        ri.ai_category_confidence, ri.confirmed,
+        COALESCE(r.merchant_name, ''), r.transaction_date,
+        1 - (ri.item_name_embedding <=> $2::vector) AS score
+    FROM receipt_items ri
+    JOIN receipts r ON r.receipt_id = ri.receipt_id
+    WHERE r.user_id = $1
+    AND ri.item_name_embedding IS NOT NULL
+    AND 1 - (ri.item_name_embedding <=> $2::vector) >= $7
+    AND ($3 = '' OR ri.category_id = $3)
+    AND (NULLIF($4, '') IS NULL OR NULLIF($5, '') IS NULL OR
+         r.transaction_date BETWEEN
+         ($4::date + INTERVAL '0 hours')::timestamp AND
+         ($5::date + INTERVAL '23 hours 59 minutes 59 seconds')::timestamp)
+    ORDER BY ri.item_name_embedding <=> $2::vector
+    LIMIT $6`
+
+	var items []models.ReceiptItemWithScore
+	err := r.withVectorSearch(func(tx *sql.Tx) error {
+		rows, err := tx.Query(query, userId, embedding, req.CategoryId, req.StartDate, req.EndDate, limit, constants.SearchSimilarityThreshold)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		items, err = r.scanReceiptItems(rows)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *searchRepository) scanReceiptItems(rows *sql.Rows) ([]models.ReceiptItemWithScore, error) {
+	var items []models.ReceiptItemWithScore
+	for rows.Next() {
+		item := &models.ReceiptItem{}
+		result := models.ReceiptItemWithScore{ReceiptItem: item}
+		if err := rows.Scan(
+			&item.ReceiptItemId,
+			&item.ReceiptId,
+			&item.ItemName,
+			&item.ItemQuantity,
+			&item.ItemPrice,
+			&item.ItemPriceTotal,
+			&item.ItemDiscount,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+			&item.CategoryId,
+			&item.AiCategoryConfidence,
+			&item.Confirmed,
+			&result.MerchantName,
+			&result.TransactionDate,
+			&result.Score,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, result)
+	}
+
+	return items, rows.Err()
+}
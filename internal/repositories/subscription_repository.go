@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/domains/subscription"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/internal/utils"
+	"github.com/saufiroja/fin-ai/pkg/databases"
+)
+
+type subscriptionRepository struct {
+	DB databases.PostgresManager
+}
+
+func NewSubscriptionRepository(db databases.PostgresManager) subscription.SubscriptionStorer {
+	return &subscriptionRepository{
+		DB: db,
+	}
+}
+
+// GetExpenseCharges skips transactions created by recurring rules, those are already tracked.
+// Only the latest charge of each distinct description carries its embedding to keep the payload small.
+func (s *subscriptionRepository) GetExpenseCharges(userId string, since time.Time, limit int) ([]models.SubscriptionCharge, error) {
+	db := s.DB.Connection()
+
+	query := `
+    SELECT
+        transaction_id, COALESCE(category_id, ''), description, amount,
+        COALESCE(payment_method, ''), transaction_date,
+        CASE WHEN ROW_NUMBER() OVER (PARTITION BY description ORDER BY transaction_date DESC) = 1
+            THEN description_embedding::text ELSE '' END
+    FROM transactions
+    WHERE user_id = $1
+    AND type = 'expense'
+    AND source <> $2
+    AND transaction_date >= $3
+    ORDER BY transaction_date DESC
+    LIMIT $4`
+
+	rows, err := db.Query(query, userId, constants.RecurringSource, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var charges []models.SubscriptionCharge
+	for rows.Next() {
+		charge := models.SubscriptionCharge{}
+		var embedding string
+		err := rows.Scan(
+			&charge.TransactionId,
+			&charge.CategoryId,
+			&charge.Description,
+			&charge.Amount,
+			&charge.PaymentMethod,
+			&charge.TransactionDate,
+			&embedding,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		charge.Embedding, err = utils.ParsePgVector(embedding)
+		if err != nil {
+			return nil, err
+		}
+		charges = append(charges, charge)
+	}
+
+	return charges, nil
+}
+
+func (s *subscriptionRepository) GetUserIdsWithExpensesSince(since time.Time) ([]string, error) {
+	db := s.DB.Connection()
+
+	query := `
+    SELECT DISTINCT user_id
+    FROM transactions
+    WHERE type = 'expense'
+    AND transaction_date >= $1
+    AND user_id IS NOT NULL
+    AND ` + notificationEnabled("transactions.user_id", constants.NotificationSubscriptionAlerts)
+
+	rows, err := db.Query(query, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIds []string
+	for rows.Next() {
+		var userId string
+		if err := rows.Scan(&userId); err != nil {
+			return nil, err
+		}
+		userIds = append(userIds, userId)
+	}
+
+	return userIds, nil
+}
@@ -0,0 +1,217 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/oklog/ulid/v2"
+	"github.com/saufiroja/fin-ai/internal/domains/tag"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/pkg/databases"
+)
+
+type tagRepository struct {
+	DB databases.PostgresManager
+}
+
+func NewTagRepository(db databases.PostgresManager) tag.TagStorer {
+	return &tagRepository{
+		DB: db,
+	}
+}
+
+// transactionTagNames selects the sorted tag names of the transaction in the outer query as a text array
+const transactionTagNames = `COALESCE((
+            SELECT array_agg(tg.name ORDER BY tg.name)
+            FROM transaction_tags tt
+            JOIN tags tg ON tg.tag_id = tt.tag_id
+            WHERE tt.transaction_id = transactions.transaction_id
+        ), '{}')`
+
+// transactionHasAllTags matches transactions carrying every tag name in the given text array parameter
+const transactionHasAllTags = `(cardinality(%[1]s::text[]) = 0 OR (
+        SELECT COUNT(DISTINCT tg.name)
+        FROM transaction_tags tt
+        JOIN tags tg ON tg.tag_id = tt.tag_id
+        WHERE tt.transaction_id = transactions.transaction_id AND tg.name = ANY(%[1]s)
+    ) = cardinality(%[1]s::text[]))`
+
+func (r *tagRepository) InsertTag(tag *models.Tag) error {
+	db := r.DB.Connection()
+
+	query := `
+    INSERT INTO tags (tag_id, user_id, name, created_at, updated_at)
+    VALUES ($1, $2, $3, $4, $5)`
+
+	_, err := db.Exec(query, tag.TagId, tag.UserId, tag.Name, tag.CreatedAt, tag.UpdatedAt)
+
+	return err
+}
+
+func (r *tagRepository) GetTagsByUserId(userId string) ([]models.TagWithUsage, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT
+        tg.tag_id, tg.user_id, tg.name, tg.created_at, COALESCE(tg.updated_at, tg.created_at),
+        (SELECT COUNT(*) FROM transaction_tags tt WHERE tt.tag_id = tg.tag_id),
+        (SELECT COUNT(*) FROM receipt_tags rt WHERE rt.tag_id = tg.tag_id)
+    FROM tags tg
+    WHERE tg.user_id = $1
+    ORDER BY tg.name`
+
+	rows, err := db.Query(query, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []models.TagWithUsage
+	for rows.Next() {
+		tag := models.TagWithUsage{}
+		err := rows.Scan(
+			&tag.TagId,
+			&tag.UserId,
+			&tag.Name,
+			&tag.CreatedAt,
+			&tag.UpdatedAt,
+			&tag.TransactionCount,
+			&tag.ReceiptCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+func (r *tagRepository) GetTagById(userId, tagId string) (*models.Tag, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT tag_id, user_id, name, created_at, COALESCE(updated_at, created_at)
+    FROM tags
+    WHERE user_id = $1 AND tag_id = $2`
+
+	tag := &models.Tag{}
+	err := db.QueryRow(query, userId, tagId).Scan(
+		&tag.TagId,
+		&tag.UserId,
+		&tag.Name,
+		&tag.CreatedAt,
+		&tag.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+func (r *tagRepository) IsTagNameTaken(userId, name, excludeTagId string) (bool, error) {
+	db := r.DB.Connection()
+
+	query := `SELECT EXISTS (SELECT 1 FROM tags WHERE user_id = $1 AND name = $2 AND tag_id <> $3)`
+
+	var taken bool
+	err := db.QueryRow(query, userId, name, excludeTagId).Scan(&taken)
+
+	return taken, err
+}
+
+func (r *tagRepository) UpdateTag(tag *models.Tag) error {
+	db := r.DB.Connection()
+
+	query := `UPDATE tags SET name = $1, updated_at = $2 WHERE tag_id = $3 AND user_id = $4`
+
+	_, err := db.Exec(query, tag.Name, tag.UpdatedAt, tag.TagId, tag.UserId)
+
+	return err
+}
+
+func (r *tagRepository) DeleteTag(userId, tagId string) error {
+	db := r.DB.Connection()
+
+	// Assignments are removed by ON DELETE CASCADE
+	query := `DELETE FROM tags WHERE user_id = $1 AND tag_id = $2`
+
+	_, err := db.Exec(query, userId, tagId)
+
+	return err
+}
+
+func (r *tagRepository) TransactionExists(userId, transactionId string) (bool, error) {
+	db := r.DB.Connection()
+
+	query := `SELECT EXISTS (SELECT 1 FROM transactions WHERE user_id = $1 AND transaction_id = $2)`
+
+	var exists bool
+	err := db.QueryRow(query, userId, transactionId).Scan(&exists)
+
+	return exists, err
+}
+
+func (r *tagRepository) ReceiptExists(userId, receiptId string) (bool, error) {
+	db := r.DB.Connection()
+
+	query := `SELECT EXISTS (SELECT 1 FROM receipts WHERE user_id = $1 AND receipt_id = $2)`
+
+	var exists bool
+	err := db.QueryRow(query, userId, receiptId).Scan(&exists)
+
+	return exists, err
+}
+
+func (r *tagRepository) ReplaceTransactionTags(userId, transactionId string, names []string) error {
+	return r.replaceTags(userId, "transaction_tags", "transaction_id", transactionId, names)
+}
+
+func (r *tagRepository) ReplaceReceiptTags(userId, receiptId string, names []string) error {
+	return r.replaceTags(userId, "receipt_tags", "receipt_id", receiptId, names)
+}
+
+// replaceTags creates the missing tags and swaps the assignments of one row in a single transaction,
+// linkTable and linkColumn are constants from this file and never user input
+func (r *tagRepository) replaceTags(userId, linkTable, linkColumn, id string, names []string) error {
+	tx, err := r.DB.StartTransaction()
+	if err != nil {
+		return err
+	}
+	defer r.DB.RollbackTransaction(tx)
+
+	if err := r.ensureTags(tx, userId, names); err != nil {
+		return err
+	}
+
+	deleteQuery := fmt.Sprintf(`DELETE FROM %s WHERE %s = $1`, linkTable, linkColumn)
+	if _, err := tx.Exec(deleteQuery, id); err != nil {
+		return err
+	}
+
+	insertQuery := fmt.Sprintf(`
+    INSERT INTO %s (%s, tag_id)
+    SELECT $1, tag_id FROM tags WHERE user_id = $2 AND name = ANY($3)`, linkTable, linkColumn)
+	if _, err := tx.Exec(insertQuery, id, userId, pq.Array(names)); err != nil {
+		return err
+	}
+
+	return r.DB.CommitTransaction(tx)
+}
+
+func (r *tagRepository) ensureTags(tx *sql.Tx, userId string, names []string) error {
+	query := `
+    INSERT INTO tags (tag_id, user_id, name, created_at, updated_at)
+    VALUES ($1, $2, $3, NOW(), NOW())
+    ON CONFLICT (user_id, name) DO NOTHING`
+
+	for _, name := range names {
+		if _, err := tx.Exec(query, ulid.Make().String(), userId, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,350 @@
+package repositories
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/domains/transaction_import"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/pkg/databases"
+)
+
+type transactionImportRepository struct {
+	DB databases.PostgresManager
+}
+
+func NewTransactionImportRepository(db databases.PostgresManager) transaction_import.TransactionImportStorer {
+	return &transactionImportRepository{
+		DB: db,
+	}
+}
+
+// transactionImportColumns leaves out raw_rows, only the detail query loads the sheet
+const transactionImportColumns = `
+        import_id, user_id, file_name, file_type, COALESCE(statement_format, ''), status, headers, column_mapping,
+        total_rows, imported_rows, duplicate_rows, invalid_rows,
+        created_at, COALESCE(updated_at, created_at), committed_at, reverted_at`
+
+func (r *transactionImportRepository) scanImport(scanner interface{ Scan(...any) error }, extra ...any) (*models.TransactionImport, error) {
+	imp := &models.TransactionImport{}
+	var headers, mapping []byte
+	var committedAt, revertedAt *time.Time
+	dest := []any{
+		&imp.ImportId,
+		&imp.UserId,
+		&imp.FileName,
+		&imp.FileType,
+		&imp.StatementFormat,
+		&imp.Status,
+		&headers,
+		&mapping,
+		&imp.TotalRows,
+		&imp.ImportedRows,
+		&imp.DuplicateRows,
+		&imp.InvalidRows,
+		&imp.CreatedAt,
+		&imp.UpdatedAt,
+		&committedAt,
+		&revertedAt,
+	}
+	if err := scanner.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(headers, &imp.Headers); err != nil {
+		return nil, err
+	}
+	if mapping != nil {
+		if err := json.Unmarshal(mapping, &imp.Mapping); err != nil {
+			return nil, err
+		}
+	}
+	imp.CommittedAt = committedAt
+	imp.RevertedAt = revertedAt
+
+	return imp, nil
+}
+
+func (r *transactionImportRepository) InsertImport(imp *models.TransactionImport) error {
+	db := r.DB.Connection()
+
+	headers, err := json.Marshal(imp.Headers)
+	if err != nil {
+		return err
+	}
+	rows, err := json.Marshal(imp.Rows)
+	if err != nil {
+		return err
+	}
+
+	query := `
+    INSERT INTO transaction_imports (
+        import_id, user_id, file_name, file_type, statement_format, status, headers, raw_rows,
+        total_rows, created_at, updated_at
+    )
+    VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9, $10, $11)`
+
+	_, err = db.Exec(query,
+		imp.ImportId,
+		imp.UserId,
+		imp.FileName,
+		imp.FileType,
+		imp.StatementFormat,
+		imp.Status,
+		headers,
+		rows,
+		imp.TotalRows,
+		imp.CreatedAt,
+		imp.UpdatedAt,
+	)
+
+	return err
+}
+
+func (r *transactionImportRepository) GetImports(userId, status string, limit, offset int) ([]models.TransactionImport, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT ` + transactionImportColumns + `
+    FROM transaction_imports
+    WHERE user_id = $1 AND ($2 = '' OR status = $2)
+    ORDER BY created_at DESC, import_id DESC
+    LIMIT $3 OFFSET $4`
+
+	rows, err := db.Query(query, userId, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var imports []models.TransactionImport
+	for rows.Next() {
+		imp, err := r.scanImport(rows)
+		if err != nil {
+			return nil, err
+		}
+		imports = append(imports, *imp)
+	}
+
+	return imports, rows.Err()
+}
+
+func (r *transactionImportRepository) CountImports(userId, status string) (int64, error) {
+	db := r.DB.Connection()
+
+	query := `SELECT COUNT(*) FROM transaction_imports WHERE user_id = $1 AND ($2 = '' OR status = $2)`
+
+	var count int64
+	err := db.QueryRow(query, userId, status).Scan(&count)
+
+	return count, err
+}
+
+func (r *transactionImportRepository) GetImportById(userId, importId string) (*models.TransactionImport, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT ` + transactionImportColumns + `, raw_rows
+    FROM transaction_imports
+    WHERE user_id = $1 AND import_id = $2`
+
+	var rows []byte
+	imp, err := r.scanImport(db.QueryRow(query, userId, importId), &rows)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(rows, &imp.Rows); err != nil {
+		return nil, err
+	}
+
+	return imp, nil
+}
+
+func (r *transactionImportRepository) GetExistingTransactions(userId string, start, end time.Time) ([]models.Transaction, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT transaction_date, amount, type, description
+    FROM transactions
+    WHERE user_id = $1
+    AND type IN ('income', 'expense')
+    AND transaction_date >= $2
+    AND transaction_date < $3`
+
+	rows, err := db.Query(query, userId, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []models.Transaction
+	for rows.Next() {
+		transaction := models.Transaction{}
+		err := rows.Scan(
+			&transaction.TransactionDate,
+			&transaction.Amount,
+			&transaction.Type,
+			&transaction.Description,
+		)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, rows.Err()
+}
+
+func (r *transactionImportRepository) GetExistingExternalIds(userId string, externalIds []string) ([]string, error) {
+	db := r.DB.Connection()
+
+	query := `SELECT external_id FROM transactions WHERE user_id = $1 AND external_id = ANY($2)`
+
+	rows, err := db.Query(query, userId, pq.Array(externalIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var existing []string
+	for rows.Next() {
+		var externalId string
+		if err := rows.Scan(&externalId); err != nil {
+			return nil, err
+		}
+		existing = append(existing, externalId)
+	}
+
+	return existing, rows.Err()
+}
+
+// CommitImport inserts every transaction and marks the import committed atomically, it fails with
+// ErrImportNotPending when another request committed the import first
+func (r *transactionImportRepository) CommitImport(imp *models.TransactionImport, transactions []*models.Transaction) error {
+	tx, err := r.DB.StartTransaction()
+	if err != nil {
+		return err
+	}
+	defer r.DB.RollbackTransaction(tx)
+
+	mapping, err := json.Marshal(imp.Mapping)
+	if err != nil {
+		return err
+	}
+
+	importQuery := `
+    UPDATE transaction_imports
+    SET status = $1, column_mapping = $2, imported_rows = $3, duplicate_rows = $4,
+        invalid_rows = $5, committed_at = $6, updated_at = $6
+    WHERE import_id = $7 AND user_id = $8 AND status = $9`
+
+	result, err := tx.Exec(importQuery,
+		imp.Status,
+		mapping,
+		imp.ImportedRows,
+		imp.DuplicateRows,
+		imp.InvalidRows,
+		imp.CommittedAt,
+		imp.ImportId,
+		imp.UserId,
+		constants.ImportStatusPending,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return transaction_import.ErrImportNotPending
+	}
+
+	transactionQuery := `
+    INSERT INTO transactions (
+        transaction_id, user_id, category_id, type, description, description_embedding,
+        amount, source, transaction_date, ai_category_confidence, is_auto_categorized,
+        created_at, updated_at, confirmed, discount, payment_method, account_id, import_id, external_id,
+        currency_code
+    )
+    VALUES (
+        $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, NULLIF($17, ''), $18, NULLIF($19, ''),
+        (SELECT base_currency FROM users WHERE user_id = $2)
+    )`
+
+	stmt, err := tx.Prepare(transactionQuery)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, transaction := range transactions {
+		_, err = stmt.Exec(
+			transaction.TransactionId,
+			transaction.UserId,
+			transaction.CategoryId,
+			transaction.Type,
+			transaction.Description,
+			transaction.DescriptionEmbedding,
+			transaction.Amount,
+			transaction.Source,
+			transaction.TransactionDate,
+			transaction.AiCategoryConfidence,
+			transaction.IsAutoCategorized,
+			transaction.CreatedAt,
+			transaction.UpdatedAt,
+			transaction.Confirmed,
+			transaction.Discount,
+			transaction.PaymentMethod,
+			transaction.AccountId,
+			imp.ImportId,
+			transaction.ExternalId,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return r.DB.CommitTransaction(tx)
+}
+
+// RevertImport deletes the transactions of a committed import and marks it reverted, it returns the
+// number of deleted transactions or ErrImportNotCommitted when the import is not committed anymore
+func (r *transactionImportRepository) RevertImport(userId, importId string, revertedAt time.Time) (int64, error) {
+	tx, err := r.DB.StartTransaction()
+	if err != nil {
+		return 0, err
+	}
+	defer r.DB.RollbackTransaction(tx)
+
+	importQuery := `
+    UPDATE transaction_imports
+    SET status = $1, reverted_at = $2, updated_at = $2
+    WHERE import_id = $3 AND user_id = $4 AND status = $5`
+
+	result, err := tx.Exec(importQuery, constants.ImportStatusReverted, revertedAt, importId, userId, constants.ImportStatusCommitted)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if affected == 0 {
+		return 0, transaction_import.ErrImportNotCommitted
+	}
+
+	// Splits, tags and anomalies of the transactions are removed by ON DELETE CASCADE
+	result, err = tx.Exec(`DELETE FROM transactions WHERE import_id = $1 AND user_id = $2`, importId, userId)
+	if err != nil {
+		return 0, err
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return deleted, r.DB.CommitTransaction(tx)
+}
@@ -1,6 +1,11 @@
 package repositories
 
 import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/saufiroja/fin-ai/internal/constants"
 	"github.com/saufiroja/fin-ai/internal/contracts/requests"
 	"github.com/saufiroja/fin-ai/internal/contracts/responses"
 	"github.com/saufiroja/fin-ai/internal/domains/transaction"
@@ -18,27 +23,51 @@ func NewTransactionRepository(db databases.PostgresManager) transaction.Transact
 	}
 }
 
+// transactionLines expands every transaction into its split lines, or a single line when it has no splits.
+// Category level aggregates read from it so split amounts are counted in their own categories.
+const transactionLines = `(
+        SELECT
+            t.transaction_id, t.user_id, t.type, t.source, t.transaction_date,
+            COALESCE(s.category_id, t.category_id) AS category_id,
+            COALESCE(s.amount, t.amount) AS amount
+        FROM transactions t
+        LEFT JOIN transaction_splits s ON s.transaction_id = t.transaction_id
+    )`
+
+// transactionCurrencyColumns reads the currency, original amount and rate of a transaction, rows
+// written without them were paid in the base currency
+const transactionCurrencyColumns = `currency_code, COALESCE(original_amount, amount), fx_rate`
+
+// splitCategoryMatch matches a transaction whose own category or one of its split lines is the given category
+const splitCategoryMatch = `EXISTS (
+        SELECT 1 FROM transaction_splits s
+        WHERE s.transaction_id = transactions.transaction_id AND s.category_id = %s
+    )`
+
 func (t *transactionRepository) GetAllTransactions(req *requests.GetAllTransactionsQuery, userId string) ([]models.Transaction, error) {
 	db := t.DB.Connection()
 
 	query := `
         SELECT 
-            transaction_id, user_id, category_id, type, amount, 
+            transaction_id, user_id, COALESCE(category_id, ''), type, amount, 
             description, description_embedding, source, transaction_date, 
             ai_category_confidence, is_auto_categorized, created_at, updated_at,
-            confirmed, discount
+            confirmed, discount, COALESCE(account_id, ''), COALESCE(transfer_id, ''),
+            ` + transactionTagNames + `, ` + transactionCurrencyColumns + `
         FROM transactions
-        WHERE ($1 = '' OR category_id = $1)
+        WHERE ($1 = '' OR category_id = $1 OR ` + fmt.Sprintf(splitCategoryMatch, "$1") + `)
         AND ($2 = '' OR LOWER(description) LIKE LOWER('%' || $2 || '%'))
         AND (NULLIF($6, '') IS NULL OR NULLIF($7, '') IS NULL OR 
              transaction_date BETWEEN 
              ($6::date + INTERVAL '0 hours')::timestamp AND 
              ($7::date + INTERVAL '23 hours 59 minutes 59 seconds')::timestamp)
 		AND user_id = $5
+		AND ($8 = '' OR account_id = $8)
+		AND ` + fmt.Sprintf(transactionHasAllTags, "$9") + `
         ORDER BY transaction_date DESC
         LIMIT $3 OFFSET $4`
 
-	rows, err := db.Query(query, req.CategoryId, req.Search, req.Limit, req.Offset, userId, req.StartDate, req.EndDate)
+	rows, err := db.Query(query, req.CategoryId, req.Search, req.Limit, req.Offset, userId, req.StartDate, req.EndDate, req.AccountId, pq.Array(req.Tags))
 	if err != nil {
 		return nil, err
 	}
@@ -63,6 +92,12 @@ func (t *transactionRepository) GetAllTransactions(req *requests.GetAllTransacti
 			&transaction.UpdatedAt,
 			&transaction.Confirmed,
 			&transaction.Discount,
+			&transaction.AccountId,
+			&transaction.TransferId,
+			pq.Array(&transaction.Tags),
+			&transaction.CurrencyCode,
+			&transaction.OriginalAmount,
+			&transaction.FxRate,
 		)
 		if err != nil {
 			return nil, err
@@ -73,9 +108,89 @@ func (t *transactionRepository) GetAllTransactions(req *requests.GetAllTransacti
 	return transactions, nil
 }
 
-func (t *transactionRepository) InsertTransaction(transaction *models.Transaction) error {
+// ExportTransactions reads every transaction matching the list filters, oldest first, and hands them to
+// fn one row at a time. Pagination is ignored and fn stops the export by returning an error.
+func (t *transactionRepository) ExportTransactions(req *requests.GetAllTransactionsQuery, userId string, fn func(row *models.TransactionExport) error) error {
 	db := t.DB.Connection()
 
+	query := `
+        SELECT
+            transactions.transaction_id, transactions.type, transactions.amount, transactions.discount,
+            transactions.description, transactions.source, transactions.transaction_date,
+            transactions.created_at, transactions.confirmed, COALESCE(transactions.payment_method, ''),
+            COALESCE(transactions.category_id, ''), COALESCE(c.name, ''),
+            COALESCE(transactions.account_id, ''), COALESCE(a.name, ''),
+            COALESCE(transactions.transfer_id, ''), COALESCE(transactions.receipt_id, ''),
+            COALESCE((
+                SELECT array_agg(COALESCE(sc.name, s.category_id) || ': ' || s.amount ORDER BY s.amount DESC, s.split_id)
+                FROM transaction_splits s
+                LEFT JOIN categories sc ON sc.category_id = s.category_id
+                WHERE s.transaction_id = transactions.transaction_id
+            ), '{}'),
+            ` + transactionTagNames + `,
+            transactions.currency_code, COALESCE(transactions.original_amount, transactions.amount)
+        FROM transactions
+        LEFT JOIN categories c ON c.category_id = transactions.category_id
+        LEFT JOIN accounts a ON a.account_id = transactions.account_id
+        WHERE ($1 = '' OR transactions.category_id = $1 OR ` + fmt.Sprintf(splitCategoryMatch, "$1") + `)
+        AND ($2 = '' OR LOWER(transactions.description) LIKE LOWER('%' || $2 || '%'))
+        AND (NULLIF($4, '') IS NULL OR NULLIF($5, '') IS NULL OR
+             transactions.transaction_date BETWEEN
+             ($4::date + INTERVAL '0 hours')::timestamp AND
+             ($5::date + INTERVAL '23 hours 59 minutes 59 seconds')::timestamp)
+        AND transactions.user_id = $3
+        AND ($6 = '' OR transactions.account_id = $6)
+        AND ` + fmt.Sprintf(transactionHasAllTags, "$7") + `
+        ORDER BY transactions.transaction_date, transactions.transaction_id`
+
+	rows, err := db.Query(query, req.CategoryId, req.Search, userId, req.StartDate, req.EndDate, req.AccountId, pq.Array(req.Tags))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row := models.TransactionExport{}
+		err := rows.Scan(
+			&row.TransactionId,
+			&row.Type,
+			&row.Amount,
+			&row.Discount,
+			&row.Description,
+			&row.Source,
+			&row.TransactionDate,
+			&row.CreatedAt,
+			&row.Confirmed,
+			&row.PaymentMethod,
+			&row.CategoryId,
+			&row.CategoryName,
+			&row.AccountId,
+			&row.AccountName,
+			&row.TransferId,
+			&row.ReceiptId,
+			pq.Array(&row.SplitLines),
+			pq.Array(&row.Tags),
+			&row.CurrencyCode,
+			&row.OriginalAmount,
+		)
+		if err != nil {
+			return err
+		}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (t *transactionRepository) InsertTransaction(transaction *models.Transaction) error {
+	tx, err := t.DB.StartTransaction()
+	if err != nil {
+		return err
+	}
+	defer t.DB.RollbackTransaction(tx)
+
 	query := `
     INSERT INTO transactions (
     transaction_id, 
@@ -93,13 +208,19 @@ func (t *transactionRepository) InsertTransaction(transaction *models.Transactio
     updated_at,
 	confirmed,
 	discount,
-	payment_method
+	payment_method,
+	account_id,
+	receipt_id,
+	currency_code,
+	original_amount,
+	fx_rate
     )
     VALUES (
-		$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
+		$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, NULLIF($17, ''), NULLIF($18, ''),
+		$19, NULLIF($20, 0), $21
 	)
 `
-	_, err := db.Exec(query,
+	_, err = tx.Exec(query,
 		transaction.TransactionId,
 		transaction.UserId,
 		transaction.CategoryId,
@@ -116,9 +237,21 @@ func (t *transactionRepository) InsertTransaction(transaction *models.Transactio
 		transaction.Confirmed,
 		transaction.Discount,
 		transaction.PaymentMethod,
+		transaction.AccountId,
+		transaction.ReceiptId,
+		transaction.CurrencyCode,
+		transaction.OriginalAmount,
+		transaction.FxRate,
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	if err := t.insertSplits(tx, transaction.Splits); err != nil {
+		return err
+	}
+
+	return t.DB.CommitTransaction(tx)
 }
 
 func (t *transactionRepository) GetTransactionByID(id string) (*models.Transaction, error) {
@@ -128,7 +261,7 @@ func (t *transactionRepository) GetTransactionByID(id string) (*models.Transacti
     SELECT 
         transaction_id, 
         user_id, 
-        category_id, 
+        COALESCE(category_id, ''), 
         type, 
         amount, 
         description, 
@@ -141,7 +274,11 @@ func (t *transactionRepository) GetTransactionByID(id string) (*models.Transacti
         updated_at,
         confirmed,
         discount,
-		payment_method
+		payment_method,
+		COALESCE(account_id, ''),
+		COALESCE(transfer_id, ''),
+		` + transactionTagNames + `,
+		` + transactionCurrencyColumns + `
     FROM transactions
     WHERE transaction_id = $1
 `
@@ -166,6 +303,12 @@ func (t *transactionRepository) GetTransactionByID(id string) (*models.Transacti
 		&transaction.Confirmed,
 		&transaction.Discount,
 		&transaction.PaymentMethod,
+		&transaction.AccountId,
+		&transaction.TransferId,
+		pq.Array(&transaction.Tags),
+		&transaction.CurrencyCode,
+		&transaction.OriginalAmount,
+		&transaction.FxRate,
 	)
 
 	if err != nil {
@@ -175,8 +318,13 @@ func (t *transactionRepository) GetTransactionByID(id string) (*models.Transacti
 	return transaction, nil
 }
 
+// UpdateTransaction replaces the split lines as well when transaction.Splits is not nil
 func (t *transactionRepository) UpdateTransaction(transaction *models.Transaction) error {
-	db := t.DB.Connection()
+	tx, err := t.DB.StartTransaction()
+	if err != nil {
+		return err
+	}
+	defer t.DB.RollbackTransaction(tx)
 
 	query := `
     UPDATE transactions
@@ -194,11 +342,15 @@ func (t *transactionRepository) UpdateTransaction(transaction *models.Transactio
         updated_at = $11,
         confirmed = $12,
         discount = $13,
-		payment_method = $14
+		payment_method = $14,
+		account_id = NULLIF($16, ''),
+		currency_code = $17,
+		original_amount = NULLIF($18, 0),
+		fx_rate = $19
     WHERE transaction_id = $15
 `
 
-	_, err := db.Exec(query,
+	_, err = tx.Exec(query,
 		transaction.UserId,
 		transaction.CategoryId,
 		transaction.Type,
@@ -214,22 +366,118 @@ func (t *transactionRepository) UpdateTransaction(transaction *models.Transactio
 		transaction.Discount,
 		transaction.PaymentMethod,
 		transaction.TransactionId,
+		transaction.AccountId,
+		transaction.CurrencyCode,
+		transaction.OriginalAmount,
+		transaction.FxRate,
 	)
+	if err != nil {
+		return err
+	}
+
+	if transaction.Splits != nil {
+		if _, err := tx.Exec(`DELETE FROM transaction_splits WHERE transaction_id = $1`, transaction.TransactionId); err != nil {
+			return err
+		}
+		if err := t.insertSplits(tx, transaction.Splits); err != nil {
+			return err
+		}
+	}
+
+	return t.DB.CommitTransaction(tx)
+}
+
+func (t *transactionRepository) insertSplits(tx *sql.Tx, splits []models.TransactionSplit) error {
+	query := `
+    INSERT INTO transaction_splits (
+        split_id, transaction_id, user_id, category_id, amount, note, created_at
+    )
+    VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	for _, split := range splits {
+		_, err := tx.Exec(query,
+			split.SplitId,
+			split.TransactionId,
+			split.UserId,
+			split.CategoryId,
+			split.Amount,
+			split.Note,
+			split.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
 
-	return err
+	return nil
 }
 
-func (t *transactionRepository) DeleteTransaction(id string) error {
+func (t *transactionRepository) GetTransactionSplits(transactionIds []string) ([]models.TransactionSplit, error) {
 	db := t.DB.Connection()
 
 	query := `
-    DELETE FROM transactions
-    WHERE transaction_id = $1
+    SELECT
+        s.split_id, s.transaction_id, s.user_id, s.category_id, COALESCE(c.name, ''),
+        s.amount, COALESCE(s.note, ''), s.created_at
+    FROM transaction_splits s
+    LEFT JOIN categories c ON c.category_id = s.category_id
+    WHERE s.transaction_id = ANY($1)
+    ORDER BY s.transaction_id, s.amount DESC, s.split_id`
+
+	rows, err := db.Query(query, pq.Array(transactionIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var splits []models.TransactionSplit
+	for rows.Next() {
+		split := models.TransactionSplit{}
+		err := rows.Scan(
+			&split.SplitId,
+			&split.TransactionId,
+			&split.UserId,
+			&split.CategoryId,
+			&split.CategoryName,
+			&split.Amount,
+			&split.Note,
+			&split.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		splits = append(splits, split)
+	}
+
+	return splits, rows.Err()
+}
+
+func (t *transactionRepository) DeleteTransaction(userId, id string) error {
+	tx, err := t.DB.StartTransaction()
+	if err != nil {
+		return err
+	}
+	defer t.DB.RollbackTransaction(tx)
+
+	// Deleting a transfer leg deletes the transfer, which cascades to every other leg
+	transferQuery := `
+    DELETE FROM transfers
+    WHERE user_id = $2
+    AND transfer_id = (SELECT transfer_id FROM transactions WHERE transaction_id = $1 AND user_id = $2)
 `
+	if _, err := tx.Exec(transferQuery, id, userId); err != nil {
+		return err
+	}
 
-	_, err := db.Exec(query, id)
+	query := `
+    DELETE FROM transactions
+    WHERE transaction_id = $1 AND user_id = $2
+`
+	if _, err := tx.Exec(query, id, userId); err != nil {
+		return err
+	}
 
-	return err
+	return t.DB.CommitTransaction(tx)
 }
 
 func (t *transactionRepository) CountAllTransactions(req *requests.GetAllTransactionsQuery, userId string) (int64, error) {
@@ -238,16 +486,18 @@ func (t *transactionRepository) CountAllTransactions(req *requests.GetAllTransac
 	query := `
 	SELECT COUNT(*)
 	FROM transactions
-	WHERE ($1 = '' OR category_id = $1)
+	WHERE ($1 = '' OR category_id = $1 OR ` + fmt.Sprintf(splitCategoryMatch, "$1") + `)
 	AND ($2 = '' OR LOWER(description) LIKE LOWER('%' || $2 || '%'))
 	AND user_id = $3
 	AND (NULLIF($4, '') IS NULL OR NULLIF($5, '') IS NULL 
 	OR transaction_date BETWEEN ($4::date + INTERVAL '0 hours')::timestamp AND 
 	($5::date + INTERVAL '23 hours 59 minutes 59 seconds')::timestamp)
+	AND ($6 = '' OR account_id = $6)
+	AND ` + fmt.Sprintf(transactionHasAllTags, "$7") + `
 	`
 
 	var count int64
-	err := db.QueryRow(query, req.CategoryId, req.Search, userId, req.StartDate, req.EndDate).Scan(&count)
+	err := db.QueryRow(query, req.CategoryId, req.Search, userId, req.StartDate, req.EndDate, req.AccountId, pq.Array(req.Tags)).Scan(&count)
 	if err != nil {
 		return 0, err
 	}
@@ -261,9 +511,11 @@ func (t *transactionRepository) GetTransactionsStats(userId string, req *request
 	query := `
         SELECT 
             COALESCE(SUM(CASE WHEN type = 'income' THEN amount ELSE 0 END), 0) AS total_income,
-            COALESCE(SUM(CASE WHEN type = 'expense' THEN amount ELSE 0 END), 0) AS total_expense
-        FROM transactions
+            COALESCE(SUM(CASE WHEN type = 'expense' THEN amount ELSE 0 END), 0) AS total_expense,
+            COUNT(DISTINCT transaction_id) AS transaction_count
+        FROM ` + transactionLines + ` lines
 		WHERE user_id = $1
+		AND type IN ('income', 'expense')
 		AND ($4 = '' OR category_id = $4)
         AND (NULLIF($2, '') IS NULL OR NULLIF($3, '') IS NULL 
 		OR transaction_date BETWEEN ($2::date + INTERVAL '0 hours')::timestamp AND 
@@ -276,6 +528,7 @@ func (t *transactionRepository) GetTransactionsStats(userId string, req *request
 	err := row.Scan(
 		&stats.TotalIncome,
 		&stats.TotalExpense,
+		&stats.TransactionCount,
 	)
 
 	if err != nil {
@@ -284,3 +537,153 @@ func (t *transactionRepository) GetTransactionsStats(userId string, req *request
 
 	return stats, nil
 }
+
+func (t *transactionRepository) SearchTransactionsByEmbedding(userId, embedding string, limit int, threshold float64) ([]models.TransactionWithScore, error) {
+	tx, err := t.DB.StartTransaction()
+	if err != nil {
+		return nil, err
+	}
+	defer t.DB.RollbackTransaction(tx)
+
+	// Widen the HNSW candidate list so the user_id filter still leaves enough rows
+	if _, err := tx.Exec(fmt.Sprintf("SET LOCAL hnsw.ef_search = %d", constants.RAGHNSWEfSearch)); err != nil {
+		return nil, err
+	}
+
+	query := `
+    SELECT
+        transaction_id, user_id, COALESCE(category_id, ''), type, amount,
+        description, source, transaction_date,
+        ai_category_confidence, is_auto_categorized, created_at, updated_at,
+        confirmed, discount, payment_method,
+        1 - (description_embedding <=> $2::vector) AS score
+    FROM transactions
+    WHERE user_id = $1
+    AND 1 - (description_embedding <=> $2::vector) >= $3
+    ORDER BY description_embedding <=> $2::vector
+    LIMIT $4`
+
+	rows, err := tx.Query(query, userId, embedding, threshold, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []models.TransactionWithScore
+	for rows.Next() {
+		transaction := &models.Transaction{}
+		var score float64
+		err := rows.Scan(
+			&transaction.TransactionId,
+			&transaction.UserId,
+			&transaction.CategoryId,
+			&transaction.Type,
+			&transaction.Amount,
+			&transaction.Description,
+			&transaction.Source,
+			&transaction.TransactionDate,
+			&transaction.AiCategoryConfidence,
+			&transaction.IsAutoCategorized,
+			&transaction.CreatedAt,
+			&transaction.UpdatedAt,
+			&transaction.Confirmed,
+			&transaction.Discount,
+			&transaction.PaymentMethod,
+			&score,
+		)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, models.TransactionWithScore{
+			Transaction: transaction,
+			Score:       score,
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return transactions, t.DB.CommitTransaction(tx)
+}
+
+const transactionFilterConditions = `
+    WHERE user_id = $1
+    AND ($2 = '' OR transaction_date >= $2::date)
+    AND ($3 = '' OR transaction_date < $3::date + INTERVAL '1 day')
+    AND (cardinality($4::text[]) = 0 OR category_id = ANY($4))
+    AND ($5 = '' OR type = $5)
+    AND ($6::bigint IS NULL OR amount >= $6)
+    AND ($7::bigint IS NULL OR amount <= $7)
+    AND ($8 = '' OR LOWER(payment_method) = LOWER($8))
+    AND ($9 = '' OR LOWER(source) LIKE LOWER('%' || $9 || '%'))`
+
+func (t *transactionRepository) FilterTransactions(userId string, filter *requests.TransactionFilter, limit, offset int) ([]models.Transaction, error) {
+	db := t.DB.Connection()
+
+	query := `
+    SELECT
+        transaction_id, user_id, COALESCE(category_id, ''), type, amount,
+        description, source, transaction_date,
+        ai_category_confidence, is_auto_categorized, created_at, updated_at,
+        confirmed, discount, payment_method, COALESCE(account_id, ''), COALESCE(transfer_id, ''),
+        ` + transactionCurrencyColumns + `
+    FROM transactions` + transactionFilterConditions + `
+    ORDER BY transaction_date DESC
+    LIMIT $10 OFFSET $11`
+
+	rows, err := db.Query(query, userId, filter.StartDate, filter.EndDate, pq.Array(filter.CategoryIds), filter.Type,
+		filter.AmountMin, filter.AmountMax, filter.PaymentMethod, filter.Source, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []models.Transaction
+	for rows.Next() {
+		transaction := models.Transaction{}
+		err := rows.Scan(
+			&transaction.TransactionId,
+			&transaction.UserId,
+			&transaction.CategoryId,
+			&transaction.Type,
+			&transaction.Amount,
+			&transaction.Description,
+			&transaction.Source,
+			&transaction.TransactionDate,
+			&transaction.AiCategoryConfidence,
+			&transaction.IsAutoCategorized,
+			&transaction.CreatedAt,
+			&transaction.UpdatedAt,
+			&transaction.Confirmed,
+			&transaction.Discount,
+			&transaction.PaymentMethod,
+			&transaction.AccountId,
+			&transaction.TransferId,
+			&transaction.CurrencyCode,
+			&transaction.OriginalAmount,
+			&transaction.FxRate,
+		)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, nil
+}
+
+func (t *transactionRepository) CountFilteredTransactions(userId string, filter *requests.TransactionFilter) (int64, error) {
+	db := t.DB.Connection()
+
+	query := `SELECT COUNT(*) FROM transactions` + transactionFilterConditions
+
+	var count int64
+	err := db.QueryRow(query, userId, filter.StartDate, filter.EndDate, pq.Array(filter.CategoryIds), filter.Type,
+		filter.AmountMin, filter.AmountMax, filter.PaymentMethod, filter.Source).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
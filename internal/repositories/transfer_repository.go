@@ -0,0 +1,233 @@
+package repositories
+
+import (
+	"github.com/saufiroja/fin-ai/internal/domains/transfer"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/pkg/databases"
+)
+
+type transferRepository struct {
+	DB databases.PostgresManager
+}
+
+func NewTransferRepository(db databases.PostgresManager) transfer.TransferStorer {
+	return &transferRepository{
+		DB: db,
+	}
+}
+
+const transferColumns = `
+        tr.transfer_id, tr.user_id, COALESCE(tr.from_account_id, ''), COALESCE(tr.to_account_id, ''),
+        tr.amount, tr.fee, tr.description, tr.transfer_date, tr.created_at,
+        COALESCE(tr.updated_at, tr.created_at), COALESCE(fa.name, ''), COALESCE(ta.name, '')`
+
+const transferJoins = `
+    FROM transfers tr
+    LEFT JOIN accounts fa ON fa.account_id = tr.from_account_id
+    LEFT JOIN accounts ta ON ta.account_id = tr.to_account_id`
+
+func (r *transferRepository) scanTransfer(scanner interface{ Scan(...any) error }) (*models.TransferWithAccounts, error) {
+	transfer := &models.TransferWithAccounts{}
+	err := scanner.Scan(
+		&transfer.TransferId,
+		&transfer.UserId,
+		&transfer.FromAccountId,
+		&transfer.ToAccountId,
+		&transfer.Amount,
+		&transfer.Fee,
+		&transfer.Description,
+		&transfer.TransferDate,
+		&transfer.CreatedAt,
+		&transfer.UpdatedAt,
+		&transfer.FromAccountName,
+		&transfer.ToAccountName,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// InsertTransfer stores the transfer and all its legs atomically
+func (r *transferRepository) InsertTransfer(transfer *models.Transfer, legs []*models.Transaction) error {
+	tx, err := r.DB.StartTransaction()
+	if err != nil {
+		return err
+	}
+	defer r.DB.RollbackTransaction(tx)
+
+	transferQuery := `
+    INSERT INTO transfers (
+        transfer_id, user_id, from_account_id, to_account_id, amount, fee,
+        description, transfer_date, created_at, updated_at
+    )
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	_, err = tx.Exec(transferQuery,
+		transfer.TransferId,
+		transfer.UserId,
+		transfer.FromAccountId,
+		transfer.ToAccountId,
+		transfer.Amount,
+		transfer.Fee,
+		transfer.Description,
+		transfer.TransferDate,
+		transfer.CreatedAt,
+		transfer.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	legQuery := `
+    INSERT INTO transactions (
+        transaction_id, user_id, category_id, type, description, description_embedding,
+        amount, source, transaction_date, ai_category_confidence, is_auto_categorized,
+        created_at, updated_at, confirmed, discount, payment_method, account_id, transfer_id, currency_code
+    )
+    VALUES (
+        $1, $2, NULLIF($3, ''), $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18,
+        (SELECT base_currency FROM users WHERE user_id = $2)
+    )`
+
+	for _, leg := range legs {
+		_, err = tx.Exec(legQuery,
+			leg.TransactionId,
+			leg.UserId,
+			leg.CategoryId,
+			leg.Type,
+			leg.Description,
+			leg.DescriptionEmbedding,
+			leg.Amount,
+			leg.Source,
+			leg.TransactionDate,
+			leg.AiCategoryConfidence,
+			leg.IsAutoCategorized,
+			leg.CreatedAt,
+			leg.UpdatedAt,
+			leg.Confirmed,
+			leg.Discount,
+			leg.PaymentMethod,
+			leg.AccountId,
+			leg.TransferId,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return r.DB.CommitTransaction(tx)
+}
+
+func (r *transferRepository) GetTransfers(userId, accountId string, limit, offset int) ([]models.TransferWithAccounts, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT ` + transferColumns + transferJoins + `
+    WHERE tr.user_id = $1
+    AND ($2 = '' OR tr.from_account_id = $2 OR tr.to_account_id = $2)
+    ORDER BY tr.transfer_date DESC, tr.transfer_id DESC
+    LIMIT $3 OFFSET $4`
+
+	rows, err := db.Query(query, userId, accountId, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transfers []models.TransferWithAccounts
+	for rows.Next() {
+		transfer, err := r.scanTransfer(rows)
+		if err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, *transfer)
+	}
+
+	return transfers, rows.Err()
+}
+
+func (r *transferRepository) CountTransfers(userId, accountId string) (int64, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT COUNT(*)
+    FROM transfers
+    WHERE user_id = $1
+    AND ($2 = '' OR from_account_id = $2 OR to_account_id = $2)`
+
+	var count int64
+	err := db.QueryRow(query, userId, accountId).Scan(&count)
+
+	return count, err
+}
+
+func (r *transferRepository) GetTransferById(userId, transferId string) (*models.TransferWithAccounts, error) {
+	db := r.DB.Connection()
+
+	query := `SELECT ` + transferColumns + transferJoins + ` WHERE tr.user_id = $1 AND tr.transfer_id = $2`
+
+	return r.scanTransfer(db.QueryRow(query, userId, transferId))
+}
+
+func (r *transferRepository) GetTransferLegs(transferId string) ([]models.Transaction, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT
+        transaction_id, user_id, COALESCE(category_id, ''), type, amount,
+        description, source, transaction_date,
+        ai_category_confidence, is_auto_categorized, created_at, updated_at,
+        confirmed, discount, payment_method, COALESCE(account_id, ''), COALESCE(transfer_id, '')
+    FROM transactions
+    WHERE transfer_id = $1
+    ORDER BY CASE type WHEN 'transfer_out' THEN 0 WHEN 'transfer_in' THEN 1 ELSE 2 END`
+
+	rows, err := db.Query(query, transferId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []models.Transaction
+	for rows.Next() {
+		transaction := models.Transaction{}
+		err := rows.Scan(
+			&transaction.TransactionId,
+			&transaction.UserId,
+			&transaction.CategoryId,
+			&transaction.Type,
+			&transaction.Amount,
+			&transaction.Description,
+			&transaction.Source,
+			&transaction.TransactionDate,
+			&transaction.AiCategoryConfidence,
+			&transaction.IsAutoCategorized,
+			&transaction.CreatedAt,
+			&transaction.UpdatedAt,
+			&transaction.Confirmed,
+			&transaction.Discount,
+			&transaction.PaymentMethod,
+			&transaction.AccountId,
+			&transaction.TransferId,
+		)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, rows.Err()
+}
+
+func (r *transferRepository) DeleteTransfer(userId, transferId string) error {
+	db := r.DB.Connection()
+
+	// Legs are removed by ON DELETE CASCADE
+	query := `DELETE FROM transfers WHERE user_id = $1 AND transfer_id = $2`
+
+	_, err := db.Exec(query, userId, transferId)
+
+	return err
+}
@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/domains/usage"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/pkg/databases"
+)
+
+type usageRepository struct {
+	DB databases.PostgresManager
+}
+
+func NewUsageRepository(db databases.PostgresManager) usage.UsageStorer {
+	return &usageRepository{
+		DB: db,
+	}
+}
+
+// usageGroupColumns are the expressions the rows are grouped on, never built from the request
+var usageGroupColumns = map[string]string{
+	constants.UsageGroupByUser:  "COALESCE(lm.user_id, '')",
+	constants.UsageGroupByTopic: "lm.topic",
+	constants.UsageGroupByModel: "COALESCE(lm.model, '')",
+	constants.UsageGroupByDay:   "TO_CHAR(lm.created_at, 'YYYY-MM-DD')",
+}
+
+const usageFilterCondition = `
+    WHERE lm.created_at >= $1 AND lm.created_at < $2
+    AND ($3 = '' OR lm.user_id = $3)
+    AND ($4 = '' OR lm.topic = $4)
+    AND ($5 = '' OR lm.model = $5)`
+
+func (r *usageRepository) GetUsageGroups(filter *models.UsageFilter) ([]models.UsageGroup, error) {
+	db := r.DB.Connection()
+
+	groupColumn, ok := usageGroupColumns[filter.GroupBy]
+	if !ok {
+		groupColumn = usageGroupColumns[constants.UsageGroupByDay]
+	}
+	emailColumn := "''"
+	join := ""
+	if filter.GroupBy == constants.UsageGroupByUser {
+		emailColumn = "COALESCE(MAX(u.email), '')"
+		join = "LEFT JOIN users u ON u.user_id = lm.user_id"
+	}
+	// Days read as a time series, the other groups from the most expensive
+	order := "cost DESC, calls DESC, key"
+	if filter.GroupBy == constants.UsageGroupByDay {
+		order = "key"
+	}
+
+	query := `
+    SELECT ` + groupColumn + ` AS key, ` + emailColumn + `, COUNT(*) AS calls,
+        COALESCE(SUM(lm.input_token), 0), COALESCE(SUM(lm.output_token), 0), COALESCE(SUM(lm.cost), 0) AS cost
+    FROM log_messages lm
+    ` + join + usageFilterCondition + `
+    GROUP BY 1
+    ORDER BY ` + order + `
+    LIMIT $6`
+
+	rows, err := db.Query(query, filter.From, filter.To, filter.UserId, filter.Topic, filter.Model, filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []models.UsageGroup{}
+	for rows.Next() {
+		var group models.UsageGroup
+		err := rows.Scan(
+			&group.Key,
+			&group.Email,
+			&group.Calls,
+			&group.InputTokens,
+			&group.OutputTokens,
+			&group.Cost,
+		)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+
+	return groups, rows.Err()
+}
+
+func (r *usageRepository) GetUsageTotal(filter *models.UsageFilter) (*models.UsageGroup, error) {
+	db := r.DB.Connection()
+
+	query := `
+    SELECT COUNT(*), COALESCE(SUM(lm.input_token), 0), COALESCE(SUM(lm.output_token), 0), COALESCE(SUM(lm.cost), 0)
+    FROM log_messages lm` + usageFilterCondition
+
+	total := &models.UsageGroup{}
+	err := db.QueryRow(query, filter.From, filter.To, filter.UserId, filter.Topic, filter.Model).Scan(
+		&total.Calls,
+		&total.InputTokens,
+		&total.OutputTokens,
+		&total.Cost,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return total, nil
+}
@@ -0,0 +1,372 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/account"
+	"github.com/saufiroja/fin-ai/internal/domains/currency"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/internal/utils"
+	"github.com/saufiroja/fin-ai/pkg/fx"
+	logging "github.com/saufiroja/fin-ai/pkg/loggings"
+)
+
+type accountService struct {
+	accountRepository account.AccountStorer
+	currencyService   currency.CurrencyManager
+	logging           logging.Logger
+}
+
+func NewAccountService(accountRepository account.AccountStorer, currencyService currency.CurrencyManager, logging logging.Logger) account.AccountManager {
+	return &accountService{
+		accountRepository: accountRepository,
+		currencyService:   currencyService,
+		logging:           logging,
+	}
+}
+
+func (s *accountService) CreateAccount(userId string, req *requests.AccountRequest) (*models.Account, error) {
+	s.logging.LogInfo(fmt.Sprintf("Creating account for user %s: %+v", userId, req))
+
+	now := time.Now()
+	acc := &models.Account{
+		AccountId: ulid.Make().String(),
+		UserId:    userId,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.applyAccountRequest(acc, req, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.accountRepository.InsertAccount(acc); err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to insert account: %v", err))
+		return nil, fmt.Errorf("failed to insert account: %w", err)
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Account %s created successfully", acc.AccountId))
+	return acc, nil
+}
+
+func (s *accountService) GetAccounts(userId string, req *requests.AccountQuery) (*responses.AccountsResponse, error) {
+	base, err := s.currencyService.GetBaseCurrency(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := s.accountRepository.GetAccountsByUserId(userId, req.IncludeArchived)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get accounts for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to get accounts: %w", err)
+	}
+
+	res := &responses.AccountsResponse{
+		Currency: responses.NewCurrencyMeta(base),
+		Accounts: accounts,
+	}
+	if res.Accounts == nil {
+		res.Accounts = []models.AccountWithBalance{}
+	}
+	for _, acc := range accounts {
+		if !acc.IsArchived {
+			res.TotalBalance += acc.Balance
+		}
+	}
+
+	return res, nil
+}
+
+func (s *accountService) GetAccountById(userId, accountId string) (*models.AccountWithBalance, error) {
+	acc, err := s.getAccount(userId, accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Balance as of now, transactions dated in the future are not counted yet
+	balance, err := s.accountRepository.GetAccountBalance(accountId, time.Now())
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get balance of account %s: %v", accountId, err))
+		return nil, fmt.Errorf("failed to get account balance: %w", err)
+	}
+
+	count, err := s.accountRepository.CountAccountTransactions(accountId)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to count transactions of account %s: %v", accountId, err))
+		return nil, fmt.Errorf("failed to count account transactions: %w", err)
+	}
+
+	return &models.AccountWithBalance{
+		Account:          *acc,
+		Balance:          balance,
+		TransactionCount: count,
+	}, nil
+}
+
+func (s *accountService) UpdateAccount(userId, accountId string, req *requests.AccountRequest) (*models.Account, error) {
+	s.logging.LogInfo(fmt.Sprintf("Updating account %s for user %s: %+v", accountId, userId, req))
+
+	acc, err := s.getAccount(userId, accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.applyAccountRequest(acc, req, now); err != nil {
+		return nil, err
+	}
+	acc.UpdatedAt = now
+
+	if err := s.accountRepository.UpdateAccount(acc); err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to update account %s: %v", accountId, err))
+		return nil, fmt.Errorf("failed to update account: %w", err)
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Account %s updated successfully", accountId))
+	return acc, nil
+}
+
+func (s *accountService) DeleteAccount(userId, accountId string) error {
+	if _, err := s.getAccount(userId, accountId); err != nil {
+		return err
+	}
+
+	// Transactions are kept and become unassigned (ON DELETE SET NULL)
+	if err := s.accountRepository.DeleteAccount(userId, accountId); err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to delete account %s: %v", accountId, err))
+		return fmt.Errorf("failed to delete account: %w", err)
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Account %s deleted successfully", accountId))
+	return nil
+}
+
+func (s *accountService) GetLedger(userId, accountId string, req *requests.AccountLedgerQuery) (*responses.AccountLedgerResponse, error) {
+	if _, err := s.getAccount(userId, accountId); err != nil {
+		return nil, err
+	}
+
+	start, end, err := utils.ResolveDateRange(req.StartDate, req.EndDate, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	until := end.AddDate(0, 0, 1)
+
+	openingBalance, err := s.accountRepository.GetAccountBalance(accountId, start)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get opening balance of account %s: %v", accountId, err))
+		return nil, fmt.Errorf("failed to get account balance: %w", err)
+	}
+
+	entries, err := s.accountRepository.GetLedgerEntries(accountId, start, until)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get ledger of account %s: %v", accountId, err))
+		return nil, fmt.Errorf("failed to get account ledger: %w", err)
+	}
+	if entries == nil {
+		entries = []models.AccountLedgerEntry{}
+	}
+
+	closingBalance := openingBalance
+	if len(entries) > 0 {
+		closingBalance = entries[len(entries)-1].RunningBalance
+	}
+
+	base, err := s.currencyService.GetBaseCurrency(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses.AccountLedgerResponse{
+		Currency:       responses.NewCurrencyMeta(base),
+		AccountId:      accountId,
+		StartDate:      start.Format(utils.DateLayout),
+		EndDate:        end.Format(utils.DateLayout),
+		OpeningBalance: openingBalance,
+		ClosingBalance: closingBalance,
+		Entries:        entries,
+	}, nil
+}
+
+func (s *accountService) AssignTransactions(userId, accountId string, req *requests.AssignAccountTransactionsRequest) (*responses.AssignAccountTransactionsResponse, error) {
+	if _, err := s.getAccount(userId, accountId); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(req.TransactionIds))
+	transactionIds := make([]string, 0, len(req.TransactionIds))
+	for _, id := range req.TransactionIds {
+		if !seen[id] {
+			seen[id] = true
+			transactionIds = append(transactionIds, id)
+		}
+	}
+
+	assigned, err := s.accountRepository.AssignTransactions(userId, accountId, transactionIds)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to assign transactions to account %s: %v", accountId, err))
+		return nil, fmt.Errorf("failed to assign transactions: %w", err)
+	}
+	if assigned == 0 {
+		return nil, account.ErrAccountTransactionsOwner
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Assigned %d transactions to account %s", assigned, accountId))
+	return &responses.AssignAccountTransactionsResponse{Assigned: assigned}, nil
+}
+
+func (s *accountService) ReconcileAccount(userId, accountId string, req *requests.ReconcileAccountRequest) (*responses.ReconcileAccountResponse, error) {
+	s.logging.LogInfo(fmt.Sprintf("Reconciling account %s for user %s: %+v", accountId, userId, req))
+
+	acc, err := s.getAccount(userId, accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	statementDate, err := time.Parse(utils.DateLayout, req.StatementDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid statement date: %w", err)
+	}
+	if statementDate.Before(acc.OpeningDate) {
+		return nil, account.ErrInvalidReconciliation
+	}
+	until := statementDate.AddDate(0, 0, 1)
+
+	computed, err := s.accountRepository.GetAccountBalance(accountId, until)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get balance of account %s: %v", accountId, err))
+		return nil, fmt.Errorf("failed to get account balance: %w", err)
+	}
+
+	previous, err := s.accountRepository.GetLastMatchedReconciliation(accountId, statementDate)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get previous reconciliation of account %s: %v", accountId, err))
+		return nil, fmt.Errorf("failed to get previous reconciliation: %w", err)
+	}
+
+	reconciliation := &models.AccountReconciliation{
+		ReconciliationId: ulid.Make().String(),
+		AccountId:        accountId,
+		UserId:           userId,
+		StatementDate:    statementDate,
+		StatementBalance: *req.StatementBalance,
+		ComputedBalance:  computed,
+		Difference:       *req.StatementBalance - computed,
+		CreatedAt:        time.Now(),
+	}
+	if err := s.accountRepository.InsertReconciliation(reconciliation); err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to insert reconciliation for account %s: %v", accountId, err))
+		return nil, fmt.Errorf("failed to insert reconciliation: %w", err)
+	}
+
+	res := &responses.ReconcileAccountResponse{
+		Reconciliation: reconciliation,
+		Matched:        reconciliation.Difference == 0,
+		PreviousMatch:  previous,
+		Entries:        []models.AccountLedgerEntry{},
+	}
+
+	// On a mismatch the missing or wrong transaction is after the last statement that matched
+	if !res.Matched {
+		from := acc.OpeningDate
+		if previous != nil {
+			from = previous.StatementDate.AddDate(0, 0, 1)
+		}
+		entries, err := s.accountRepository.GetLedgerEntries(accountId, from, until)
+		if err != nil {
+			s.logging.LogError(fmt.Sprintf("Failed to get ledger of account %s: %v", accountId, err))
+			return nil, fmt.Errorf("failed to get account ledger: %w", err)
+		}
+		if entries != nil {
+			res.Entries = entries
+		}
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Account %s reconciled with difference %d", accountId, reconciliation.Difference))
+	return res, nil
+}
+
+func (s *accountService) GetReconciliations(userId, accountId string) ([]models.AccountReconciliation, error) {
+	if _, err := s.getAccount(userId, accountId); err != nil {
+		return nil, err
+	}
+
+	reconciliations, err := s.accountRepository.GetReconciliations(accountId)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get reconciliations of account %s: %v", accountId, err))
+		return nil, fmt.Errorf("failed to get reconciliations: %w", err)
+	}
+	if reconciliations == nil {
+		reconciliations = []models.AccountReconciliation{}
+	}
+
+	return reconciliations, nil
+}
+
+func (s *accountService) getAccount(userId, accountId string) (*models.Account, error) {
+	acc, err := s.accountRepository.GetAccountById(userId, accountId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, account.ErrAccountNotFound
+		}
+		s.logging.LogError(fmt.Sprintf("Failed to get account %s: %v", accountId, err))
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	return acc, nil
+}
+
+// applyAccountRequest copies the request onto the account, the opening date defaults to today for new accounts
+func (s *accountService) applyAccountRequest(acc *models.Account, req *requests.AccountRequest, now time.Time) error {
+	name := strings.TrimSpace(req.Name)
+	taken, err := s.accountRepository.IsAccountNameTaken(acc.UserId, name, acc.AccountId)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to check account name: %v", err))
+		return fmt.Errorf("failed to check account name: %w", err)
+	}
+	if taken {
+		return account.ErrAccountNameTaken
+	}
+
+	acc.Name = name
+	acc.Type = req.Type
+	acc.Institution = strings.TrimSpace(req.Institution)
+	acc.OpeningBalance = req.OpeningBalance
+
+	if req.OpeningDate != "" {
+		openingDate, err := time.Parse(utils.DateLayout, req.OpeningDate)
+		if err != nil {
+			return fmt.Errorf("invalid opening date: %w", err)
+		}
+		acc.OpeningDate = openingDate
+	} else if acc.OpeningDate.IsZero() {
+		acc.OpeningDate = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	if req.IsArchived != nil {
+		acc.IsArchived = *req.IsArchived
+	}
+
+	switch {
+	case req.CurrencyCode != "":
+		accountCurrency, err := fx.Lookup(req.CurrencyCode)
+		if err != nil {
+			return err
+		}
+		acc.CurrencyCode = accountCurrency.Code
+	case acc.CurrencyCode == "":
+		base, err := s.currencyService.GetBaseCurrency(acc.UserId)
+		if err != nil {
+			return err
+		}
+		acc.CurrencyCode = base.Code
+	}
+
+	return nil
+}
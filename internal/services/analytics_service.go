@@ -0,0 +1,284 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/analytics"
+	"github.com/saufiroja/fin-ai/internal/domains/currency"
+	"github.com/saufiroja/fin-ai/internal/domains/transaction"
+	"github.com/saufiroja/fin-ai/internal/utils"
+	logging "github.com/saufiroja/fin-ai/pkg/loggings"
+)
+
+type analyticsService struct {
+	analyticsRepository analytics.AnalyticsStorer
+	transactionService  transaction.TransactionManager
+	currencyService     currency.CurrencyManager
+	logging             logging.Logger
+}
+
+func NewAnalyticsService(
+	analyticsRepository analytics.AnalyticsStorer,
+	transactionService transaction.TransactionManager,
+	currencyService currency.CurrencyManager,
+	logging logging.Logger,
+) analytics.AnalyticsManager {
+	return &analyticsService{
+		analyticsRepository: analyticsRepository,
+		transactionService:  transactionService,
+		currencyService:     currencyService,
+		logging:             logging,
+	}
+}
+
+func (a *analyticsService) GetTimeSeries(userId string, req *requests.AnalyticsQuery) (*responses.TimeSeriesResponse, error) {
+	start, end, err := utils.ResolveDateRange(req.StartDate, req.EndDate, time.Now())
+	if err != nil {
+		a.logging.LogError(fmt.Sprintf("Invalid date range for time series: %v", err))
+		return nil, err
+	}
+
+	interval := req.Interval
+	if interval == "" {
+		interval = string(constants.PeriodTypeDaily)
+	}
+
+	// date_trunc units for each supported bucket size
+	truncUnits := map[string]string{
+		string(constants.PeriodTypeDaily):   "day",
+		string(constants.PeriodTypeWeekly):  "week",
+		string(constants.PeriodTypeMonthly): "month",
+	}
+	truncUnit, ok := truncUnits[interval]
+	if !ok {
+		return nil, fmt.Errorf("unsupported interval: %s", interval)
+	}
+
+	points, err := a.analyticsRepository.GetTimeSeries(userId, req, truncUnit, start, end)
+	if err != nil {
+		a.logging.LogError(fmt.Sprintf("Failed to get time series for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to get time series: %w", err)
+	}
+
+	if points == nil {
+		points = []responses.TimeSeriesPoint{}
+	}
+
+	baseCurrency, err := a.baseCurrency(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses.TimeSeriesResponse{
+		Currency:  baseCurrency,
+		Interval:  interval,
+		StartDate: start.Format(utils.DateLayout),
+		EndDate:   end.Format(utils.DateLayout),
+		Points:    points,
+	}, nil
+}
+
+func (a *analyticsService) GetCategoryBreakdown(userId string, req *requests.AnalyticsQuery) (*responses.CategoryBreakdownResponse, error) {
+	start, end, err := utils.ResolveDateRange(req.StartDate, req.EndDate, time.Now())
+	if err != nil {
+		a.logging.LogError(fmt.Sprintf("Invalid date range for category breakdown: %v", err))
+		return nil, err
+	}
+
+	query := *req
+	if query.Type == "" {
+		query.Type = string(constants.ExpenseCategory)
+	}
+
+	items, err := a.analyticsRepository.GetCategoryBreakdown(userId, &query, start, end)
+	if err != nil {
+		a.logging.LogError(fmt.Sprintf("Failed to get category breakdown for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to get category breakdown: %w", err)
+	}
+
+	var total int64
+	for _, item := range items {
+		total += item.Total
+	}
+
+	for i := range items {
+		items[i].Percentage = a.percentage(items[i].Total, total)
+	}
+
+	if items == nil {
+		items = []responses.CategoryBreakdownItem{}
+	}
+
+	baseCurrency, err := a.baseCurrency(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses.CategoryBreakdownResponse{
+		Currency:   baseCurrency,
+		Type:       query.Type,
+		StartDate:  start.Format(utils.DateLayout),
+		EndDate:    end.Format(utils.DateLayout),
+		Total:      total,
+		Categories: items,
+	}, nil
+}
+
+func (a *analyticsService) GetTopSources(userId string, req *requests.AnalyticsQuery) (*responses.TopSourcesResponse, error) {
+	start, end, err := utils.ResolveDateRange(req.StartDate, req.EndDate, time.Now())
+	if err != nil {
+		a.logging.LogError(fmt.Sprintf("Invalid date range for top sources: %v", err))
+		return nil, err
+	}
+
+	query := *req
+	if query.Type == "" {
+		query.Type = string(constants.ExpenseCategory)
+	}
+	if query.Limit == 0 {
+		query.Limit = 10
+	}
+
+	items, err := a.analyticsRepository.GetTopSources(userId, &query, start, end)
+	if err != nil {
+		a.logging.LogError(fmt.Sprintf("Failed to get top sources for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to get top sources: %w", err)
+	}
+
+	if items == nil {
+		items = []responses.TopSourceItem{}
+	}
+
+	baseCurrency, err := a.baseCurrency(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses.TopSourcesResponse{
+		Currency:  baseCurrency,
+		Type:      query.Type,
+		StartDate: start.Format(utils.DateLayout),
+		EndDate:   end.Format(utils.DateLayout),
+		Sources:   items,
+	}, nil
+}
+
+func (a *analyticsService) GetTagBreakdown(userId string, req *requests.AnalyticsQuery) (*responses.TagBreakdownResponse, error) {
+	start, end, err := utils.ResolveDateRange(req.StartDate, req.EndDate, time.Now())
+	if err != nil {
+		a.logging.LogError(fmt.Sprintf("Invalid date range for tag breakdown: %v", err))
+		return nil, err
+	}
+
+	query := *req
+	if query.Type == "" {
+		query.Type = string(constants.ExpenseCategory)
+	}
+
+	items, err := a.analyticsRepository.GetTagBreakdown(userId, &query, start, end)
+	if err != nil {
+		a.logging.LogError(fmt.Sprintf("Failed to get tag breakdown for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to get tag breakdown: %w", err)
+	}
+
+	total, tagged, err := a.analyticsRepository.GetTaggedTotals(userId, &query, start, end)
+	if err != nil {
+		a.logging.LogError(fmt.Sprintf("Failed to get tagged totals for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to get tagged totals: %w", err)
+	}
+
+	// Tags overlap, so percentages are taken against the whole period rather than the sum of the tags
+	for i := range items {
+		items[i].Percentage = a.percentage(items[i].Total, total)
+	}
+
+	if items == nil {
+		items = []responses.TagBreakdownItem{}
+	}
+
+	baseCurrency, err := a.baseCurrency(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses.TagBreakdownResponse{
+		Currency:      baseCurrency,
+		Type:          query.Type,
+		StartDate:     start.Format(utils.DateLayout),
+		EndDate:       end.Format(utils.DateLayout),
+		Total:         total,
+		TaggedTotal:   tagged,
+		UntaggedTotal: total - tagged,
+		Tags:          items,
+	}, nil
+}
+
+func (a *analyticsService) GetPeriodComparison(userId string, req *requests.AnalyticsQuery) (*responses.PeriodComparisonResponse, error) {
+	start, end, err := utils.ResolveDateRange(req.StartDate, req.EndDate, time.Now())
+	if err != nil {
+		a.logging.LogError(fmt.Sprintf("Invalid date range for period comparison: %v", err))
+		return nil, err
+	}
+
+	// The previous period has the same length and ends the day before the current one starts
+	days := utils.DaysInRange(start, end)
+	previousEnd := start.AddDate(0, 0, -1)
+	previousStart := previousEnd.AddDate(0, 0, -(days - 1))
+
+	current, err := a.transactionService.GetTransactionsStats(userId, &requests.OverviewTransactionsQuery{
+		StartDate:  start.Format(utils.DateLayout),
+		EndDate:    end.Format(utils.DateLayout),
+		CategoryId: req.CategoryId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current period stats: %w", err)
+	}
+
+	previous, err := a.transactionService.GetTransactionsStats(userId, &requests.OverviewTransactionsQuery{
+		StartDate:  previousStart.Format(utils.DateLayout),
+		EndDate:    previousEnd.Format(utils.DateLayout),
+		CategoryId: req.CategoryId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get previous period stats: %w", err)
+	}
+
+	return &responses.PeriodComparisonResponse{
+		Current:          current,
+		Previous:         previous,
+		IncomeChange:     current.TotalIncome - previous.TotalIncome,
+		ExpenseChange:    current.TotalExpense - previous.TotalExpense,
+		NetChange:        current.Net - previous.Net,
+		IncomeChangePct:  a.changePercentage(current.TotalIncome, previous.TotalIncome),
+		ExpenseChangePct: a.changePercentage(current.TotalExpense, previous.TotalExpense),
+	}, nil
+}
+
+// baseCurrency is the currency all stored amounts of the user are in
+func (a *analyticsService) baseCurrency(userId string) (responses.CurrencyMeta, error) {
+	base, err := a.currencyService.GetBaseCurrency(userId)
+	if err != nil {
+		return responses.CurrencyMeta{}, err
+	}
+	return responses.NewCurrencyMeta(base), nil
+}
+
+func (a *analyticsService) percentage(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) * 100 / float64(total)
+}
+
+// changePercentage returns nil when there is no baseline to compare against
+func (a *analyticsService) changePercentage(current, previous int64) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	change := float64(current-previous) * 100 / float64(previous)
+	return &change
+}
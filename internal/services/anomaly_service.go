@@ -0,0 +1,599 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/saufiroja/fin-ai/config"
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/anomaly"
+	"github.com/saufiroja/fin-ai/internal/domains/recommendation"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/internal/utils"
+	"github.com/saufiroja/fin-ai/pkg/fx"
+	logging "github.com/saufiroja/fin-ai/pkg/loggings"
+)
+
+// maxAnomalyScore keeps scores within the DECIMAL(10,2) column when the history has no spread at all
+const maxAnomalyScore = 9999.99
+
+type anomalyService struct {
+	anomalyRepository     anomaly.AnomalyStorer
+	recommendationService recommendation.RecommendationManager
+	logging               logging.Logger
+	scanScheduled         bool
+	queue                 chan string
+	cancel                context.CancelFunc
+	wg                    sync.WaitGroup
+}
+
+func NewAnomalyService(
+	anomalyRepository anomaly.AnomalyStorer,
+	recommendationService recommendation.RecommendationManager,
+	logging logging.Logger,
+	conf *config.AppConfig,
+) anomaly.AnomalyManager {
+	return &anomalyService{
+		anomalyRepository:     anomalyRepository,
+		recommendationService: recommendationService,
+		logging:               logging,
+		scanScheduled:         conf.Scheduler.Enabled,
+		queue:                 make(chan string, constants.AnomalyCheckQueueSize),
+	}
+}
+
+func (s *anomalyService) QueueCheck(transactionId string) {
+	select {
+	case s.queue <- transactionId:
+		return
+	default:
+	}
+
+	// Bulk inserts can outpace the workers, the scheduled scan covers a skipped transaction when it runs
+	if s.scanScheduled {
+		s.logging.LogInfo(fmt.Sprintf("Anomaly check queue is full, leaving transaction %s to the scheduled scan", transactionId))
+		return
+	}
+
+	// Without the scheduled scan a skipped transaction is never checked, so wait for a worker to free a slot
+	timer := time.NewTimer(constants.AnomalyCheckQueueWait)
+	defer timer.Stop()
+	select {
+	case s.queue <- transactionId:
+	case <-timer.C:
+		s.logging.LogError(fmt.Sprintf("Anomaly check queue stayed full for %s, transaction %s is not checked, run POST /api/v1/anomalies/scan to cover it",
+			constants.AnomalyCheckQueueWait, transactionId))
+	}
+}
+
+func (s *anomalyService) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for range constants.AnomalyCheckWorkers {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case transactionId := <-s.queue:
+					s.runCheck(ctx, transactionId)
+				}
+			}
+		}()
+	}
+}
+
+func (s *anomalyService) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *anomalyService) runCheck(ctx context.Context, transactionId string) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logging.LogError(fmt.Sprintf("Panic in anomaly check of transaction %s: %v", transactionId, r))
+		}
+	}()
+
+	if _, err := s.CheckTransaction(ctx, transactionId); err != nil {
+		s.logging.LogError(fmt.Sprintf("Anomaly check failed for transaction %s: %v", transactionId, err))
+	}
+}
+
+func (s *anomalyService) CheckTransaction(ctx context.Context, transactionId string) ([]models.TransactionAnomaly, error) {
+	candidate, err := s.anomalyRepository.GetCandidate(transactionId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("transaction %s not found", transactionId)
+		}
+		s.logging.LogError(fmt.Sprintf("Failed to get transaction %s for anomaly check: %v", transactionId, err))
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	found, err := s.detect(candidate, true)
+	if err != nil {
+		return nil, err
+	}
+
+	recorded, _ := s.record(ctx, found)
+	return recorded, nil
+}
+
+func (s *anomalyService) ScanAnomalies(ctx context.Context, userId string, req *requests.AnomalyScanRequest) (*responses.AnomalyScanResponse, error) {
+	start, end, err := s.resolveRange(req.StartDate, req.EndDate, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Scanning anomalies for user %s from %s to %s", userId, start.Format(utils.DateLayout), end.Format(utils.DateLayout)))
+
+	candidates, err := s.anomalyRepository.GetCandidates(userId, start, end.AddDate(0, 0, 1))
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get transactions to scan for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to get transactions to scan: %w", err)
+	}
+
+	res := &responses.AnomalyScanResponse{
+		StartDate:           start.Format(utils.DateLayout),
+		EndDate:             end.Format(utils.DateLayout),
+		TransactionsScanned: len(candidates),
+		Anomalies:           []models.TransactionAnomaly{},
+	}
+
+	// A category spike is recorded once per month, so stop re-checking it after the first hit
+	spikeChecked := make(map[string]bool)
+	for i := range candidates {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		candidate := &candidates[i]
+		spikeKey := s.spikeReferenceKey(candidate.CategoryId, candidate.TransactionDate)
+
+		found, err := s.detect(candidate, !spikeChecked[spikeKey])
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range found {
+			if a.AnomalyType == constants.AnomalyTypeCategorySpike {
+				spikeChecked[spikeKey] = true
+			}
+		}
+
+		recorded, created := s.record(ctx, found)
+		res.Anomalies = append(res.Anomalies, recorded...)
+		res.RecommendationsCreated += created
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Anomaly scan for user %s recorded %d anomalies in %d transactions", userId, len(res.Anomalies), len(candidates)))
+	return res, nil
+}
+
+func (s *anomalyService) ScanAnomaliesForAllUsers(ctx context.Context, now time.Time) error {
+	since := now.AddDate(0, 0, -constants.AnomalyScanDefaultDays)
+
+	userIds, err := s.anomalyRepository.GetUserIdsWithExpensesSince(since)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get users for anomaly scan: %v", err))
+		return fmt.Errorf("failed to get users for anomaly scan: %w", err)
+	}
+
+	recorded := 0
+	for _, userId := range userIds {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		res, err := s.ScanAnomalies(ctx, userId, &requests.AnomalyScanRequest{})
+		if err != nil {
+			// One user's failure must not block the others
+			s.logging.LogError(fmt.Sprintf("Anomaly scan failed for user %s: %v", userId, err))
+			continue
+		}
+		recorded += len(res.Anomalies)
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Anomaly scan finished for %d users, %d anomalies recorded", len(userIds), recorded))
+	return nil
+}
+
+func (s *anomalyService) GetAnomalies(userId string, req *requests.AnomalyQuery) (*responses.AnomaliesResponse, error) {
+	start, end, err := s.resolveRange(req.StartDate, req.EndDate, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert page-based offset to row offset, same as the other list endpoints
+	offset := 0
+	if req.Offset > 1 {
+		offset = (req.Offset - 1) * req.Limit
+	}
+
+	queryReq := &requests.AnomalyQuery{
+		Limit:  req.Limit,
+		Offset: offset,
+		Type:   req.Type,
+	}
+	until := end.AddDate(0, 0, 1)
+
+	anomalies, err := s.anomalyRepository.GetAnomalies(userId, queryReq, start, until)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get anomalies for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to get anomalies: %w", err)
+	}
+
+	count, err := s.anomalyRepository.CountAnomalies(userId, queryReq, start, until)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to count anomalies for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to count anomalies: %w", err)
+	}
+
+	if anomalies == nil {
+		anomalies = []models.TransactionAnomaly{}
+	}
+
+	totalPages := math.Ceil(float64(count) / float64(req.Limit))
+	currentPage := math.Min(float64(req.Offset), totalPages)
+
+	return &responses.AnomaliesResponse{
+		Anomalies:   anomalies,
+		CurrentPage: int64(currentPage),
+		TotalPages:  int64(totalPages),
+		Total:       count,
+	}, nil
+}
+
+// detect runs every detector against the user's history before the transaction
+func (s *anomalyService) detect(candidate *models.AnomalyCandidate, checkSpike bool) ([]*models.TransactionAnomaly, error) {
+	// Income and charges generated from recurring rules are expected by definition
+	if candidate.Type != constants.ExpenseCategory || candidate.Source == constants.RecurringSource {
+		return nil, nil
+	}
+
+	var found []*models.TransactionAnomaly
+	until := candidate.TransactionDate
+	since := until.AddDate(0, 0, -constants.AnomalyLookbackDays)
+
+	if candidate.CategoryId != "" {
+		amounts, err := s.anomalyRepository.GetCategoryAmounts(candidate.UserId, candidate.CategoryId, candidate.TransactionId, since, until)
+		if err != nil {
+			return nil, s.detectError("category amounts", candidate, err)
+		}
+		if a := s.checkAmount(candidate, constants.AnomalyTypeCategoryAmount, amounts, fmt.Sprintf("%s expenses", candidate.CategoryName), constants.AnomalyLookbackDays); a != nil {
+			found = append(found, a)
+		}
+	}
+
+	merchantSince := until.AddDate(0, 0, -constants.AnomalyNewMerchantDays)
+	merchantAmounts, err := s.anomalyRepository.GetSimilarDescriptionAmounts(candidate.UserId, candidate.TransactionId, merchantSince, until, constants.AnomalyMerchantSimilarity)
+	if err != nil {
+		return nil, s.detectError("merchant amounts", candidate, err)
+	}
+
+	if len(merchantAmounts) > 0 {
+		if a := s.checkAmount(candidate, constants.AnomalyTypeMerchantAmount, merchantAmounts, fmt.Sprintf("charges similar to %q", candidate.Description), constants.AnomalyNewMerchantDays); a != nil {
+			found = append(found, a)
+		}
+	} else {
+		expenseAmounts, err := s.anomalyRepository.GetExpenseAmounts(candidate.UserId, candidate.TransactionId, since, until)
+		if err != nil {
+			return nil, s.detectError("expense amounts", candidate, err)
+		}
+		if a := s.checkNewMerchant(candidate, expenseAmounts); a != nil {
+			found = append(found, a)
+		}
+	}
+
+	if candidate.TransactionDate.Hour() != 0 || candidate.TransactionDate.Minute() != 0 {
+		hours, err := s.anomalyRepository.GetExpenseHours(candidate.UserId, candidate.TransactionId, since, until)
+		if err != nil {
+			return nil, s.detectError("expense hours", candidate, err)
+		}
+		if a := s.checkTime(candidate, hours); a != nil {
+			found = append(found, a)
+		}
+	}
+
+	if checkSpike && candidate.CategoryId != "" {
+		a, err := s.checkCategorySpike(candidate)
+		if err != nil {
+			return nil, s.detectError("category spike", candidate, err)
+		}
+		if a != nil {
+			found = append(found, a)
+		}
+	}
+
+	return found, nil
+}
+
+// checkAmount flags an amount whose modified z-score (0.6745 * (x - median) / MAD) is above the threshold.
+// The median absolute deviation is used so a few earlier outliers do not hide a new one.
+func (s *anomalyService) checkAmount(candidate *models.AnomalyCandidate, anomalyType constants.AnomalyType, history []int64, label string, windowDays int) *models.TransactionAnomaly {
+	if len(history) < constants.AnomalyMinSamples {
+		return nil
+	}
+
+	values := s.toFloats(history)
+	amount := float64(candidate.Amount)
+	median := utils.Median(values)
+	if median <= 0 || amount < median*constants.AnomalyMinAmountRatio {
+		return nil
+	}
+
+	method := "modified z-score"
+	score := maxAnomalyScore
+	if mad := utils.MedianAbsoluteDeviation(values); mad > 0 {
+		score = 0.6745 * (amount - median) / mad
+	} else if mean, stdDev := utils.MeanStdDev(values); stdDev > 0 {
+		// More than half of the history has the exact same amount, fall back to the classic z-score
+		method = "z-score"
+		score = (amount - mean) / stdDev
+	}
+	if score < constants.AnomalyRobustZThreshold {
+		return nil
+	}
+	score = math.Min(score, maxAnomalyScore)
+
+	reason := fmt.Sprintf(
+		"%s is %.1fx the median of %s across %d %s in the previous %d days (%s %.1f, threshold %.1f)",
+		s.formatAmount(candidate, candidate.Amount), amount/median, s.formatAmount(candidate, int64(median)), len(history), label,
+		windowDays, method, score, constants.AnomalyRobustZThreshold,
+	)
+
+	return s.newTransactionAnomaly(candidate, anomalyType, reason, int64(median), score, len(history))
+}
+
+// checkNewMerchant flags the first charge from a merchant when it is above the usual large expense
+func (s *anomalyService) checkNewMerchant(candidate *models.AnomalyCandidate, expenses []int64) *models.TransactionAnomaly {
+	if len(expenses) < constants.AnomalyMinSamples {
+		return nil
+	}
+
+	percentile := utils.Percentile(s.toFloats(expenses), constants.AnomalyNewMerchantPercentile)
+	if percentile <= 0 || float64(candidate.Amount) <= percentile {
+		return nil
+	}
+
+	score := math.Min(float64(candidate.Amount)/percentile, maxAnomalyScore)
+	reason := fmt.Sprintf(
+		"First charge like %q in %d days, and %s is above %d%% of your %d expenses in the previous %d days (P%d %s)",
+		candidate.Description, constants.AnomalyNewMerchantDays, s.formatAmount(candidate, candidate.Amount),
+		constants.AnomalyNewMerchantPercentile, len(expenses), constants.AnomalyLookbackDays,
+		constants.AnomalyNewMerchantPercentile, s.formatAmount(candidate, int64(percentile)),
+	)
+
+	return s.newTransactionAnomaly(candidate, constants.AnomalyTypeNewMerchant, reason, int64(percentile), score, len(expenses))
+}
+
+// checkTime flags an expense made in an hour window (the hour and its neighbours) the user almost never spends in
+func (s *anomalyService) checkTime(candidate *models.AnomalyCandidate, hours []int) *models.TransactionAnomaly {
+	if len(hours) < constants.AnomalyMinHourSamples {
+		return nil
+	}
+
+	hour := candidate.TransactionDate.Hour()
+	matching := 0
+	for _, h := range hours {
+		diff := (h - hour + 24) % 24
+		if diff <= 1 || diff == 23 {
+			matching++
+		}
+	}
+
+	share := float64(matching) / float64(len(hours))
+	if share >= constants.AnomalyUnusualHourShare {
+		return nil
+	}
+
+	reason := fmt.Sprintf(
+		"Made at %s, only %d of %d (%.1f%%) of your timed expenses in the previous %d days happened between %02d:00 and %02d:59 (threshold %.0f%%)",
+		candidate.TransactionDate.Format("15:04"), matching, len(hours), share*100, constants.AnomalyLookbackDays,
+		(hour+23)%24, (hour+1)%24, constants.AnomalyUnusualHourShare*100,
+	)
+
+	// observed is the hour, expected the number of past expenses around that hour, score the share in percent
+	a := s.newTransactionAnomaly(candidate, constants.AnomalyTypeUnusualTime, reason, int64(matching), share*100, len(hours))
+	a.ObservedValue = int64(hour)
+	return a
+}
+
+// checkCategorySpike compares month-to-date spending in the category with the average of the previous months
+func (s *anomalyService) checkCategorySpike(candidate *models.AnomalyCandidate) (*models.TransactionAnomaly, error) {
+	date := candidate.TransactionDate
+	monthStart := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+
+	totals, err := s.anomalyRepository.GetCategoryMonthlyTotals(candidate.UserId, candidate.CategoryId, monthStart.AddDate(0, -constants.AnomalySpikeBaselineMonths, 0), monthStart)
+	if err != nil {
+		return nil, err
+	}
+	if len(totals) < constants.AnomalySpikeMinActiveMonths {
+		return nil, nil
+	}
+
+	var baselineTotal int64
+	for _, total := range totals {
+		baselineTotal += total
+	}
+	// Months without spending count as zero
+	average := float64(baselineTotal) / constants.AnomalySpikeBaselineMonths
+
+	monthToDate, err := s.anomalyRepository.SumCategoryExpenses(candidate.UserId, candidate.CategoryId, monthStart, until)
+	if err != nil {
+		return nil, err
+	}
+	if average <= 0 || float64(monthToDate) < average*constants.AnomalySpikeRatio {
+		return nil, nil
+	}
+
+	ratio := float64(monthToDate) / average
+	daysLeft := monthStart.AddDate(0, 1, 0).Sub(until).Hours() / 24
+	reason := fmt.Sprintf(
+		"%s spending in %s is already %s by %s, %.1fx your average month of %s over the previous %d months (threshold %.1fx), with %.0f days of the month left",
+		candidate.CategoryName, monthStart.Format("January 2006"), s.formatAmount(candidate, monthToDate), until.AddDate(0, 0, -1).Format(utils.DateLayout),
+		ratio, s.formatAmount(candidate, int64(average)), constants.AnomalySpikeBaselineMonths, constants.AnomalySpikeRatio, daysLeft,
+	)
+
+	categoryId := candidate.CategoryId
+	return &models.TransactionAnomaly{
+		UserId:        candidate.UserId,
+		CategoryId:    &categoryId,
+		AnomalyType:   constants.AnomalyTypeCategorySpike,
+		ReferenceKey:  s.spikeReferenceKey(candidate.CategoryId, date),
+		Reason:        reason,
+		ObservedValue: monthToDate,
+		ExpectedValue: int64(average),
+		Score:         math.Min(ratio, maxAnomalyScore),
+		SampleSize:    len(totals),
+		OccurredAt:    monthStart,
+		CategoryName:  candidate.CategoryName,
+	}, nil
+}
+
+// record stores new anomalies and turns each into a spending warning, already known ones are skipped
+func (s *anomalyService) record(ctx context.Context, found []*models.TransactionAnomaly) ([]models.TransactionAnomaly, int) {
+	var recorded []models.TransactionAnomaly
+	created := 0
+
+	for _, a := range found {
+		a.AnomalyId = ulid.Make().String()
+		a.DetectedAt = time.Now()
+		a.Score = math.Round(a.Score*100) / 100
+
+		inserted, err := s.anomalyRepository.InsertAnomaly(a)
+		if err != nil {
+			s.logging.LogError(fmt.Sprintf("Failed to insert anomaly %s: %v", a.ReferenceKey, err))
+			continue
+		}
+		if !inserted {
+			continue
+		}
+		recorded = append(recorded, *a)
+
+		saved, err := s.recommendationService.SaveRecommendation(ctx, s.toRecommendation(a))
+		if err != nil {
+			s.logging.LogError(fmt.Sprintf("Failed to save recommendation for anomaly %s: %v", a.ReferenceKey, err))
+			continue
+		}
+		if saved {
+			created++
+		}
+	}
+
+	if len(recorded) > 0 {
+		s.logging.LogInfo(fmt.Sprintf("Recorded %d anomalies", len(recorded)))
+	}
+	return recorded, created
+}
+
+func (s *anomalyService) toRecommendation(a *models.TransactionAnomaly) *models.AIRecommendation {
+	expiresAt := time.Now().AddDate(0, 0, constants.AnomalyRecommendationTTLDays)
+
+	priority := constants.RecommendationPriorityMedium
+	var title string
+	switch a.AnomalyType {
+	case constants.AnomalyTypeCategorySpike:
+		title = fmt.Sprintf("Spending spike: %s", a.CategoryName)
+		if a.Score >= 2 {
+			priority = constants.RecommendationPriorityHigh
+		}
+	case constants.AnomalyTypeNewMerchant:
+		title = fmt.Sprintf("Large charge from a new merchant: %s", a.Description)
+	case constants.AnomalyTypeUnusualTime:
+		title = fmt.Sprintf("Expense at an unusual time: %s", a.Description)
+		priority = constants.RecommendationPriorityLow
+	default:
+		title = fmt.Sprintf("Unusually large expense: %s", a.Description)
+		if a.ExpectedValue > 0 && float64(a.ObservedValue)/float64(a.ExpectedValue) >= constants.AnomalyHighPriorityRatio {
+			priority = constants.RecommendationPriorityHigh
+		}
+	}
+
+	if runes := []rune(title); len(runes) > 200 {
+		title = string(runes[:200])
+	}
+
+	return &models.AIRecommendation{
+		UserId:             a.UserId,
+		RecommendationType: constants.RecommendationTypeSpendingWarning,
+		Title:              title,
+		Content:            a.Reason,
+		Priority:           priority,
+		ExpiredAt:          &expiresAt,
+		ReferenceKey:       "anomaly:" + a.ReferenceKey,
+	}
+}
+
+func (s *anomalyService) newTransactionAnomaly(candidate *models.AnomalyCandidate, anomalyType constants.AnomalyType, reason string, expected int64, score float64, sampleSize int) *models.TransactionAnomaly {
+	transactionId := candidate.TransactionId
+	a := &models.TransactionAnomaly{
+		UserId:        candidate.UserId,
+		TransactionId: &transactionId,
+		AnomalyType:   anomalyType,
+		ReferenceKey:  fmt.Sprintf("%s:%s", anomalyType, candidate.TransactionId),
+		Reason:        reason,
+		ObservedValue: candidate.Amount,
+		ExpectedValue: expected,
+		Score:         score,
+		SampleSize:    sampleSize,
+		OccurredAt:    candidate.TransactionDate,
+		Description:   candidate.Description,
+		CategoryName:  candidate.CategoryName,
+	}
+	if candidate.CategoryId != "" {
+		categoryId := candidate.CategoryId
+		a.CategoryId = &categoryId
+	}
+
+	return a
+}
+
+// resolveRange defaults to the last AnomalyScanDefaultDays days when no dates are given
+func (s *anomalyService) resolveRange(startDate, endDate string, now time.Time) (time.Time, time.Time, error) {
+	if startDate == "" && endDate == "" {
+		end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		return end.AddDate(0, 0, -constants.AnomalyScanDefaultDays+1), end, nil
+	}
+
+	return utils.ResolveDateRange(startDate, endDate, now)
+}
+
+func (s *anomalyService) spikeReferenceKey(categoryId string, date time.Time) string {
+	return fmt.Sprintf("%s:%s:%s", constants.AnomalyTypeCategorySpike, categoryId, date.Format("2006-01"))
+}
+
+func (s *anomalyService) detectError(step string, candidate *models.AnomalyCandidate, err error) error {
+	s.logging.LogError(fmt.Sprintf("Failed to get %s for anomaly check of transaction %s: %v", step, candidate.TransactionId, err))
+	return fmt.Errorf("failed to get %s: %w", step, err)
+}
+
+func (s *anomalyService) toFloats(values []int64) []float64 {
+	floats := make([]float64, len(values))
+	for i, v := range values {
+		floats[i] = float64(v)
+	}
+	return floats
+}
+
+func (s *anomalyService) formatAmount(candidate *models.AnomalyCandidate, amount int64) string {
+	currency, err := fx.Lookup(candidate.BaseCurrency)
+	if err != nil {
+		currency, _ = fx.Lookup(constants.DefaultCurrencyCode)
+	}
+	return fx.Format(amount, currency)
+}
@@ -0,0 +1,315 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/domains/anomaly"
+	"github.com/saufiroja/fin-ai/internal/models"
+	logging "github.com/saufiroja/fin-ai/pkg/loggings"
+)
+
+type fakeAnomalyStorer struct {
+	anomaly.AnomalyStorer
+	monthlyTotals []int64
+	monthToDate   int64
+}
+
+func (f *fakeAnomalyStorer) GetCategoryMonthlyTotals(userId, categoryId string, since, until time.Time) ([]int64, error) {
+	return f.monthlyTotals, nil
+}
+
+func (f *fakeAnomalyStorer) SumCategoryExpenses(userId, categoryId string, since, until time.Time) (int64, error) {
+	return f.monthToDate, nil
+}
+
+func anomalyCandidate(amount int64, date time.Time) *models.AnomalyCandidate {
+	return &models.AnomalyCandidate{
+		TransactionId:   "01JB2R3C4D5E6F7G8H9J0K1M2N",
+		UserId:          "01JB2Q4K7N3P8S2V5X9Z1B4D6F",
+		CategoryId:      "01JB2Q4M1Q6T9W3Y7A2C5E8G0J",
+		CategoryName:    "Makanan & Minuman",
+		Type:            constants.ExpenseCategory,
+		Description:     "Sushi Tei",
+		Amount:          amount,
+		TransactionDate: date,
+		BaseCurrency:    "IDR",
+	}
+}
+
+// checkAnomaly compares a detector result, a zero score means nothing should be flagged
+func checkAnomaly(t *testing.T, got *models.TransactionAnomaly, wantScore float64, wantExpected int64) {
+	t.Helper()
+	if wantScore == 0 {
+		if got != nil {
+			t.Fatalf("flagged with score %.2f (%s), want nothing", got.Score, got.Reason)
+		}
+		return
+	}
+	if got == nil {
+		t.Fatalf("nothing flagged, want score %.2f", wantScore)
+	}
+	if math.Abs(got.Score-wantScore) > 0.01 {
+		t.Errorf("score = %.4f, want %.2f", got.Score, wantScore)
+	}
+	if got.ExpectedValue != wantExpected {
+		t.Errorf("expected value = %d, want %d", got.ExpectedValue, wantExpected)
+	}
+}
+
+func TestCheckAmount(t *testing.T) {
+	date := time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		history      []int64
+		amount       int64
+		wantScore    float64
+		wantExpected int64
+	}{
+		{
+			name:    "too little history",
+			history: []int64{100, 110, 90, 105},
+			amount:  1000,
+		},
+		{
+			name:         "modified z-score above the threshold",
+			history:      []int64{100, 110, 90, 105, 95},
+			amount:       1000,
+			wantScore:    121.41,
+			wantExpected: 100,
+		},
+		{
+			name:    "below the minimum ratio to the median",
+			history: []int64{100, 110, 90, 105, 95},
+			amount:  190,
+		},
+		{
+			name:    "modified z-score below the threshold",
+			history: []int64{100, 200, 300, 400, 500},
+			amount:  700,
+		},
+		{
+			name:         "wide history with a large outlier",
+			history:      []int64{100, 200, 300, 400, 500},
+			amount:       1000,
+			wantScore:    4.72,
+			wantExpected: 300,
+		},
+		{
+			name:         "z-score fallback when most amounts are equal",
+			history:      []int64{100, 100, 100, 100, 500},
+			amount:       1000,
+			wantScore:    5.13,
+			wantExpected: 100,
+		},
+		{
+			name:    "z-score fallback below the threshold",
+			history: []int64{100, 100, 100, 100, 500},
+			amount:  600,
+		},
+		{
+			name:         "history without any spread",
+			history:      []int64{100, 100, 100, 100, 100},
+			amount:       300,
+			wantScore:    maxAnomalyScore,
+			wantExpected: 100,
+		},
+	}
+
+	s := &anomalyService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.checkAmount(anomalyCandidate(tt.amount, date), constants.AnomalyTypeCategoryAmount, tt.history, "expenses", constants.AnomalyLookbackDays)
+			checkAnomaly(t, got, tt.wantScore, tt.wantExpected)
+		})
+	}
+}
+
+func TestCheckNewMerchant(t *testing.T) {
+	date := time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC)
+	expenses := []int64{10000, 20000, 30000, 40000, 50000, 60000, 70000, 80000, 90000, 100000}
+
+	tests := []struct {
+		name         string
+		expenses     []int64
+		amount       int64
+		wantScore    float64
+		wantExpected int64
+	}{
+		{
+			name:     "too little history",
+			expenses: expenses[:4],
+			amount:   500000,
+		},
+		{
+			name:     "at the percentile",
+			expenses: expenses,
+			amount:   91000,
+		},
+		{
+			name:         "above the percentile",
+			expenses:     expenses,
+			amount:       182000,
+			wantScore:    2,
+			wantExpected: 91000,
+		},
+	}
+
+	s := &anomalyService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkAnomaly(t, s.checkNewMerchant(anomalyCandidate(tt.amount, date), tt.expenses), tt.wantScore, tt.wantExpected)
+		})
+	}
+}
+
+func TestCheckTime(t *testing.T) {
+	hours := func(usual, usualCount, rare, rareCount int) []int {
+		var values []int
+		for range usualCount {
+			values = append(values, usual)
+		}
+		for range rareCount {
+			values = append(values, rare)
+		}
+		return values
+	}
+
+	tests := []struct {
+		name      string
+		hours     []int
+		at        time.Time
+		flagged   bool
+		wantShare float64
+	}{
+		{
+			name:  "too few timed expenses",
+			hours: hours(12, 19, 3, 0),
+			at:    time.Date(2024, 4, 10, 3, 30, 0, 0, time.UTC),
+		},
+		{
+			name:    "never spent around that hour",
+			hours:   hours(12, 20, 3, 0),
+			at:      time.Date(2024, 4, 10, 3, 30, 0, 0, time.UTC),
+			flagged: true,
+		},
+		{
+			name:  "share at the threshold",
+			hours: hours(12, 49, 3, 1),
+			at:    time.Date(2024, 4, 10, 3, 30, 0, 0, time.UTC),
+		},
+		{
+			name:      "share just below the threshold",
+			hours:     hours(12, 50, 3, 1),
+			at:        time.Date(2024, 4, 10, 3, 30, 0, 0, time.UTC),
+			flagged:   true,
+			wantShare: 1.96,
+		},
+		{
+			name:  "neighbouring hour across midnight",
+			hours: hours(23, 20, 0, 0),
+			at:    time.Date(2024, 4, 10, 0, 30, 0, 0, time.UTC),
+		},
+	}
+
+	s := &anomalyService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.checkTime(anomalyCandidate(50000, tt.at), tt.hours)
+			if !tt.flagged {
+				checkAnomaly(t, got, 0, 0)
+				return
+			}
+			if got == nil {
+				t.Fatalf("nothing flagged, want share %.2f%%", tt.wantShare)
+			}
+			if math.Abs(got.Score-tt.wantShare) > 0.01 {
+				t.Errorf("share = %.4f%%, want %.2f%%", got.Score, tt.wantShare)
+			}
+			if got.ObservedValue != int64(tt.at.Hour()) {
+				t.Errorf("observed value = %d, want hour %d", got.ObservedValue, tt.at.Hour())
+			}
+		})
+	}
+}
+
+func TestCheckCategorySpike(t *testing.T) {
+	date := time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		monthlyTotals []int64
+		monthToDate   int64
+		wantScore     float64
+		wantExpected  int64
+	}{
+		{
+			name:          "too few active months",
+			monthlyTotals: []int64{300000},
+			monthToDate:   900000,
+		},
+		{
+			name:          "below the spike ratio",
+			monthlyTotals: []int64{100000, 100000, 100000},
+			monthToDate:   149999,
+		},
+		{
+			name:          "at the spike ratio",
+			monthlyTotals: []int64{100000, 100000, 100000},
+			monthToDate:   150000,
+			wantScore:     1.5,
+			wantExpected:  100000,
+		},
+		{
+			name:          "month without spending counts as zero",
+			monthlyTotals: []int64{150000, 150000},
+			monthToDate:   150000,
+			wantScore:     1.5,
+			wantExpected:  100000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &anomalyService{anomalyRepository: &fakeAnomalyStorer{monthlyTotals: tt.monthlyTotals, monthToDate: tt.monthToDate}}
+			got, err := s.checkCategorySpike(anomalyCandidate(50000, date))
+			if err != nil {
+				t.Fatalf("checkCategorySpike() error = %v", err)
+			}
+			checkAnomaly(t, got, tt.wantScore, tt.wantExpected)
+			if got != nil && got.ReferenceKey != "category_spike:01JB2Q4M1Q6T9W3Y7A2C5E8G0J:2024-04" {
+				t.Errorf("reference key = %s, want one per category and month", got.ReferenceKey)
+			}
+		})
+	}
+}
+
+func TestQueueCheckWhenFull(t *testing.T) {
+	t.Run("left to the scheduled scan", func(t *testing.T) {
+		s := &anomalyService{logging: logging.NewLogrusAdapter(), scanScheduled: true, queue: make(chan string, 1)}
+		s.queue <- "first"
+
+		s.QueueCheck("second")
+		if got := <-s.queue; got != "first" || len(s.queue) != 0 {
+			t.Errorf("queue = %s with %d more, want only the first check", got, len(s.queue))
+		}
+	})
+
+	t.Run("waits for a worker without the scheduled scan", func(t *testing.T) {
+		s := &anomalyService{logging: logging.NewLogrusAdapter(), queue: make(chan string, 1)}
+		s.queue <- "first"
+
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			<-s.queue
+		}()
+
+		s.QueueCheck("second")
+		if got := <-s.queue; got != "second" {
+			t.Errorf("queued %s, want the second check", got)
+		}
+	})
+}
@@ -10,9 +10,11 @@ import (
 	"github.com/oklog/ulid/v2"
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/packages/param"
+	"github.com/saufiroja/fin-ai/internal/constants"
 	"github.com/saufiroja/fin-ai/internal/contracts/requests"
 	"github.com/saufiroja/fin-ai/internal/contracts/responses"
 	"github.com/saufiroja/fin-ai/internal/domains/categories"
+	"github.com/saufiroja/fin-ai/internal/domains/log_message"
 	"github.com/saufiroja/fin-ai/internal/models"
 	"github.com/saufiroja/fin-ai/pkg/llm"
 	logging "github.com/saufiroja/fin-ai/pkg/loggings"
@@ -22,13 +24,15 @@ type categoryService struct {
 	categoryRepository categories.CategoryStorer
 	logging            logging.Logger
 	openaiClient       llm.OpenAI
+	logMessageService  log_message.LogMessageManager
 }
 
-func NewCategoryService(categoryRepository categories.CategoryStorer, logging logging.Logger, openaiClient llm.OpenAI) categories.CategoryManager {
+func NewCategoryService(categoryRepository categories.CategoryStorer, logging logging.Logger, openaiClient llm.OpenAI, logMessageService log_message.LogMessageManager) categories.CategoryManager {
 	return &categoryService{
 		categoryRepository: categoryRepository,
 		logging:            logging,
 		openaiClient:       openaiClient,
+		logMessageService:  logMessageService,
 	}
 }
 
@@ -52,6 +56,8 @@ func (c *categoryService) CreateCategory(req *requests.CategoryRequest) error {
 		embedding := c.openaiClient.CreateEmbedding(context.Background(), input)
 
 		if embedding != nil && embedding.Embeddings != "" {
+			// Categories are shared by every user
+			c.logMessageService.LogUsage("", constants.TopicCategoryEmbedding, embedding.Model, embedding.InputToken, 0)
 			embeddingChan <- embedding.Embeddings
 		} else {
 			errorChan <- fmt.Errorf("failed to create embedding")
@@ -192,6 +198,8 @@ func (c *categoryService) UpdateCategoryById(categoryId string, req *requests.Up
 		embedding := c.openaiClient.CreateEmbedding(context.Background(), input)
 
 		if embedding != nil && embedding.Embeddings != "" {
+			// Categories are shared by every user
+			c.logMessageService.LogUsage("", constants.TopicCategoryEmbedding, embedding.Model, embedding.InputToken, 0)
 			embeddingChan <- embedding.Embeddings
 		} else {
 			errorChan <- fmt.Errorf("failed to create embedding for updated category name")
@@ -5,23 +5,25 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"math"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/oklog/ulid/v2"
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/packages/param"
+	"github.com/saufiroja/fin-ai/internal/constants"
 	"github.com/saufiroja/fin-ai/internal/constants/prompt"
 	"github.com/saufiroja/fin-ai/internal/contracts/requests"
 	"github.com/saufiroja/fin-ai/internal/contracts/responses"
 	"github.com/saufiroja/fin-ai/internal/domains/categories"
 	"github.com/saufiroja/fin-ai/internal/domains/chat"
+	"github.com/saufiroja/fin-ai/internal/domains/forecast"
 	"github.com/saufiroja/fin-ai/internal/domains/log_message"
 	"github.com/saufiroja/fin-ai/internal/domains/model_registry"
+	"github.com/saufiroja/fin-ai/internal/domains/prompt_registry"
 	"github.com/saufiroja/fin-ai/internal/domains/receipt"
 	"github.com/saufiroja/fin-ai/internal/domains/transaction"
+	"github.com/saufiroja/fin-ai/internal/domains/user"
 	"github.com/saufiroja/fin-ai/internal/models"
 	"github.com/saufiroja/fin-ai/pkg/llm"
 	logging "github.com/saufiroja/fin-ai/pkg/loggings"
@@ -38,6 +40,8 @@ type chatService struct {
 	transactionService transaction.TransactionManager
 	categoryService    categories.CategoryManager
 	receiptService     receipt.ReceiptManager
+	userService        user.UserManager
+	promptRegistry     prompt_registry.PromptRegistryManager
 }
 
 func NewChatService(
@@ -50,11 +54,16 @@ func NewChatService(
 	transactionService transaction.TransactionManager,
 	categoryService categories.CategoryManager,
 	receiptService receipt.ReceiptManager,
+	forecastService forecast.ForecastManager,
+	userService user.UserManager,
+	promptRegistry prompt_registry.PromptRegistryManager,
 ) chat.ChatManager {
 	// Set transaction service to gemini client
 	geminiClient.SetTransactionService(transactionService)
 	// Set category service to gemini client
 	geminiClient.SetCategoryService(categoryService)
+	// Set forecast service to gemini client
+	geminiClient.SetForecastService(forecastService)
 
 	return &chatService{
 		chatRepository:     chatRepository,
@@ -66,6 +75,8 @@ func NewChatService(
 		transactionService: transactionService,
 		categoryService:    categoryService,
 		receiptService:     receiptService,
+		userService:        userService,
+		promptRegistry:     promptRegistry,
 	}
 }
 
@@ -191,14 +202,14 @@ func (s *chatService) validateMode(mode models.Mode) error {
 }
 
 // getSystemPromptByMode returns the appropriate system prompt based on the mode
-func (s *chatService) getSystemPromptByMode(mode models.Mode) string {
+func (s *chatService) getSystemPromptByMode(mode models.Mode, resolve promptResolver) models.ResolvedPrompt {
 	switch mode {
 	case models.ModeAgent:
-		return prompt.ChatAgentSystemPrompt
+		return resolve(prompt.ChatAgentSystem)
 	case models.ModeChat:
 		fallthrough
 	default:
-		return prompt.ChatSystemPrompt
+		return resolve(prompt.ChatSystem)
 	}
 }
 
@@ -235,9 +246,15 @@ func (s *chatService) getChatHistory(ctx context.Context, chatSessionId, userId
 }
 
 func (s *chatService) SendChatMessage(ctx context.Context, req *models.ChatMessageRequest) (*responses.ChatMessageResponse, error) {
+	prefs, err := s.userService.GetPreferences(req.UserId)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get preferences: %s", err.Error()))
+		return nil, err
+	}
+
 	// Set default mode if empty
 	if req.Mode == "" {
-		req.Mode = models.ModeChat
+		req.Mode = prefs.DefaultChatMode
 	}
 
 	// Validate mode
@@ -248,7 +265,12 @@ func (s *chatService) SendChatMessage(ctx context.Context, req *models.ChatMessa
 
 	s.logging.LogInfo(fmt.Sprintf("Processing chat message in %s mode for session: %s", req.Mode, req.ChatSessionId))
 
-	err := s.chatRepository.InsertChatMessage(&models.ChatMessage{
+	locale := prefs.PromptLocale(ctx)
+	resolve := resolverFor(s.promptRegistry, req.UserId, locale)
+	preferences, preferencePrompts := preferencePrompt(resolve, prefs, locale, time.Now())
+	promptVersions := models.PromptVersions{}.Add(preferencePrompts...)
+
+	err = s.chatRepository.InsertChatMessage(&models.ChatMessage{
 		ChatMessageId: ulid.Make().String(),
 		ChatSessionId: req.ChatSessionId,
 		Message:       req.Message,
@@ -293,6 +315,8 @@ func (s *chatService) SendChatMessage(ctx context.Context, req *models.ChatMessa
 		} else {
 			messageWithContext = req.Message
 		}
+		// The agent has its own system prompt, the preferences travel with the message
+		messageWithContext = strings.TrimSpace(preferences) + "\n\n" + messageWithContext
 
 		response, err := s.geminiClient.RunAgent(ctx, messageWithContext, req.UserId)
 		if err != nil {
@@ -300,7 +324,7 @@ func (s *chatService) SendChatMessage(ctx context.Context, req *models.ChatMessa
 			return nil, fmt.Errorf("failed to run Gemini agent: %w", err)
 		}
 
-		if err := s.logAIResponse(req.Message, response, req.UserId); err != nil {
+		if err := s.logAIResponse(req.Message, response, req.UserId, constants.TopicAgentChat, promptVersions); err != nil {
 			return nil, fmt.Errorf("failed to log AI response: %w", err)
 		}
 
@@ -312,6 +336,7 @@ func (s *chatService) SendChatMessage(ctx context.Context, req *models.ChatMessa
 			s.logging.LogError("Failed to create embedding: returned nil")
 			return nil, fmt.Errorf("failed to create embedding")
 		}
+		s.logMessageService.LogUsage(req.UserId, constants.TopicChatEmbedding, embedding.Model, embedding.InputToken, 0)
 
 		err = s.chatRepository.InsertChatMessage(&models.ChatMessage{
 			ChatMessageId: ulid.Make().String(),
@@ -351,11 +376,14 @@ func (s *chatService) SendChatMessage(ctx context.Context, req *models.ChatMessa
 		s.logging.LogInfo("Using Run for chat mode")
 
 		// Get appropriate system prompt based on mode with user knowledge using RAG
-		systemPrompt, err := s.getSystemPromptWithKnowledge(req.Mode, req.UserId, req.Message, ctx)
+		basePrompt := s.getSystemPromptByMode(req.Mode, resolve)
+		promptVersions.Add(basePrompt)
+		systemPrompt, err := s.getSystemPromptWithKnowledge(req.Mode, basePrompt.Content, req.UserId, req.Message, prefs, ctx)
 		if err != nil {
 			s.logging.LogWarn(fmt.Sprintf("Failed to get enhanced system prompt: %s", err.Error()))
-			systemPrompt = s.getSystemPromptByMode(req.Mode) // Fallback to base prompt
+			systemPrompt = basePrompt.Content // Fallback to base prompt
 		}
+		systemPrompt += preferences
 
 		// Get chat history
 		chatHistory, err := s.getChatHistory(ctx, req.ChatSessionId, req.UserId)
@@ -379,12 +407,16 @@ func (s *chatService) SendChatMessage(ctx context.Context, req *models.ChatMessa
 			genai.NewPartFromText(req.Message),
 		}, genai.RoleUser))
 
-		response, err := s.geminiClient.Run(ctx, "gemini-2.5-flash", message)
+		response, err := s.geminiClient.Run(ctx, prefs.DefaultModel, message)
 		if err != nil {
 			s.logging.LogError(fmt.Sprintf("Failed to run Gemini client: %s", err.Error()))
 			return nil, fmt.Errorf("failed to run Gemini client: %w", err)
 		}
 
+		if err := s.logAIResponse(req.Message, response, req.UserId, constants.TopicChat, promptVersions); err != nil {
+			return nil, fmt.Errorf("failed to log AI response: %w", err)
+		}
+
 		input := openai.EmbeddingNewParamsInputUnion{
 			OfString: param.NewOpt(req.Message),
 		}
@@ -393,6 +425,7 @@ func (s *chatService) SendChatMessage(ctx context.Context, req *models.ChatMessa
 			s.logging.LogError("Failed to create embedding: returned nil")
 			return nil, fmt.Errorf("failed to create embedding")
 		}
+		s.logMessageService.LogUsage(req.UserId, constants.TopicChatEmbedding, embedding.Model, embedding.InputToken, 0)
 
 		err = s.chatRepository.InsertChatMessage(&models.ChatMessage{
 			ChatMessageId: ulid.Make().String(),
@@ -430,8 +463,8 @@ func (s *chatService) SendChatMessage(ctx context.Context, req *models.ChatMessa
 	return responseAi, nil
 }
 
-func (c *chatService) logAIResponse(responseString string, responseAi *responses.ResponseAI, userId string) error {
-	messagePromptJSON, err := json.Marshal(responseString)
+func (c *chatService) logAIResponse(message string, responseAi *responses.ResponseAI, userId, topic string, promptVersions models.PromptVersions) error {
+	messagePromptJSON, err := json.Marshal(message)
 	if err != nil {
 		c.logging.LogError(fmt.Sprintf("Failed to marshal message prompt: %v", err))
 		return fmt.Errorf("failed to marshal message prompt: %w", err)
@@ -442,13 +475,15 @@ func (c *chatService) logAIResponse(responseString string, responseAi *responses
 		LogMessageId: ulid.Make().String(),
 		UserId:       userId,
 		Message:      string(messagePromptJSON),
-		Response:     responseString,
+		Response:     fmt.Sprint(responseAi.Response),
 		InputToken:   responseAi.InputToken,
 		OutputToken:  responseAi.OutputToken,
-		Topic:        "agent chat",
-		Model:        "gemini-2.5-flash",
+		Topic:        topic,
+		Model:        responseAi.Model,
 		CreatedAt:    dateNow,
 		UpdatedAt:    dateNow,
+
+		PromptVersions: promptVersions,
 	}
 
 	err = c.logMessageService.InsertLogMessage(logMessage)
@@ -460,47 +495,7 @@ func (c *chatService) logAIResponse(responseString string, responseAi *responses
 	return nil
 }
 
-func (s *chatService) calculateCosineSimilarity(embedding1, embedding2 []float64) float64 {
-	if len(embedding1) != len(embedding2) {
-		return 0.0
-	}
-
-	var dotProduct, norm1, norm2 float64
-	for i := 0; i < len(embedding1); i++ {
-		dotProduct += embedding1[i] * embedding2[i]
-		norm1 += embedding1[i] * embedding1[i]
-		norm2 += embedding2[i] * embedding2[i]
-	}
-
-	if norm1 == 0.0 || norm2 == 0.0 {
-		return 0.0
-	}
-
-	return dotProduct / (math.Sqrt(norm1) * math.Sqrt(norm2))
-}
-
-func (s *chatService) parseEmbedding(embeddingStr string) ([]float64, error) {
-	// Remove brackets and split by comma
-	embeddingStr = strings.Trim(embeddingStr, "[]")
-	if embeddingStr == "" {
-		return nil, fmt.Errorf("empty embedding string")
-	}
-
-	parts := strings.Split(embeddingStr, ",")
-	embedding := make([]float64, len(parts))
-
-	for i, part := range parts {
-		val, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse embedding value: %w", err)
-		}
-		embedding[i] = val
-	}
-
-	return embedding, nil
-}
-
-func (s *chatService) createQueryEmbedding(ctx context.Context, query string) ([]float64, error) {
+func (s *chatService) createQueryEmbedding(ctx context.Context, userId, query string) (string, error) {
 	input := openai.EmbeddingNewParamsInputUnion{
 		OfString: param.NewOpt(query),
 	}
@@ -508,17 +503,18 @@ func (s *chatService) createQueryEmbedding(ctx context.Context, query string) ([
 	embedding := s.openaiClient.CreateEmbedding(ctx, input)
 
 	if embedding == nil {
-		return nil, fmt.Errorf("failed to create embedding")
+		return "", fmt.Errorf("failed to create embedding")
 	}
+	s.logMessageService.LogUsage(userId, constants.TopicChatEmbedding, embedding.Model, embedding.InputToken, 0)
 
-	return s.parseEmbedding(embedding.Embeddings)
+	return embedding.Embeddings, nil
 }
 
 func (s *chatService) gatherRelevantFinancialData(ctx context.Context, userId, query string) (*models.RelevantFinancialData, error) {
 	s.logging.LogInfo(fmt.Sprintf("Gathering relevant financial data for user: %s", userId))
 
 	// Create embedding for user query
-	queryEmbedding, err := s.createQueryEmbedding(ctx, query)
+	queryEmbedding, err := s.createQueryEmbedding(ctx, userId, query)
 	if err != nil {
 		s.logging.LogWarn(fmt.Sprintf("Failed to create query embedding: %s", err.Error()))
 		return nil, err
@@ -526,125 +522,33 @@ func (s *chatService) gatherRelevantFinancialData(ctx context.Context, userId, q
 
 	relevantData := &models.RelevantFinancialData{}
 
-	// Get all user transactions
-	transactionQuery := &requests.GetAllTransactionsQuery{
-		Limit: 100, // Get more transactions for better RAG
-	}
-
-	transactionResponse, err := s.transactionService.GetAllTransactions(transactionQuery, userId)
+	// Similarity ranking and top-k are done by pgvector, results come back ordered by score
+	transactions, err := s.transactionService.SearchSimilarTransactions(userId, queryEmbedding,
+		constants.RAGTransactionTopK, constants.RAGSimilarityThreshold)
 	if err != nil {
-		s.logging.LogWarn(fmt.Sprintf("Failed to get transactions: %s", err.Error()))
-	} else if transactionResponse != nil {
-		// Calculate similarity scores for transactions
-		for i := range transactionResponse.Transactions {
-			tx := &transactionResponse.Transactions[i]
-
-			// Create searchable text from transaction
-			searchText := fmt.Sprintf("%s %s %s", tx.Description, tx.Source, tx.Type)
-
-			// Create embedding for transaction (if not already exists)
-			var txEmbedding []float64
-			if tx.DescriptionEmbedding != nil {
-				if embeddingStr, ok := tx.DescriptionEmbedding.(string); ok {
-					txEmbedding, err = s.parseEmbedding(embeddingStr)
-					if err != nil {
-						s.logging.LogWarn(fmt.Sprintf("Failed to parse transaction embedding: %s", err.Error()))
-						continue
-					}
-				}
-			} else {
-				// Create new embedding for transaction
-				txEmbedding, err = s.createQueryEmbedding(ctx, searchText)
-				if err != nil {
-					s.logging.LogWarn(fmt.Sprintf("Failed to create transaction embedding: %s", err.Error()))
-					continue
-				}
-			}
-
-			// Calculate similarity
-			similarity := s.calculateCosineSimilarity(queryEmbedding, txEmbedding)
-
-			// Only include transactions with similarity above threshold
-			if similarity > 0.5 {
-				relevantData.Transactions = append(relevantData.Transactions, models.TransactionWithScore{
-					Transaction: tx,
-					Score:       similarity,
-				})
-			}
-		}
+		s.logging.LogWarn(fmt.Sprintf("Failed to search relevant transactions: %s", err.Error()))
+	} else {
+		relevantData.Transactions = transactions
 	}
 
-	// Get all user receipts
-	receipts, err := s.receiptService.GetReceiptsByUserId(userId)
+	receipts, err := s.receiptService.SearchSimilarReceipts(userId, queryEmbedding,
+		constants.RAGReceiptTopK, constants.RAGSimilarityThreshold)
 	if err != nil {
-		s.logging.LogWarn(fmt.Sprintf("Failed to get receipts: %s", err.Error()))
+		s.logging.LogWarn(fmt.Sprintf("Failed to search relevant receipts: %s", err.Error()))
 	} else {
-		// Calculate similarity scores for receipts
-		for _, receipt := range receipts {
-			// Create searchable text from receipt
-			searchText := fmt.Sprintf("%s %s", receipt.MerchantName, receipt.TransactionDate.Format("2006-01-02"))
-
-			// Create embedding for receipt (if not already exists)
-			var receiptEmbedding []float64
-			if receipt.ExtractedReceiptEmbedding != nil {
-				if embeddingStr, ok := receipt.ExtractedReceiptEmbedding.(string); ok {
-					receiptEmbedding, err = s.parseEmbedding(embeddingStr)
-					if err != nil {
-						s.logging.LogWarn(fmt.Sprintf("Failed to parse receipt embedding: %s", err.Error()))
-						continue
-					}
-				}
-			} else {
-				// Create new embedding for receipt
-				receiptEmbedding, err = s.createQueryEmbedding(ctx, searchText)
-				if err != nil {
-					s.logging.LogWarn(fmt.Sprintf("Failed to create receipt embedding: %s", err.Error()))
-					continue
-				}
-			}
-
-			// Calculate similarity
-			similarity := s.calculateCosineSimilarity(queryEmbedding, receiptEmbedding)
-
-			// Only include receipts with similarity above threshold
-			if similarity > 0.5 {
-				relevantData.Receipts = append(relevantData.Receipts, models.ReceiptWithScore{
-					Receipt: receipt,
-					Score:   similarity,
-				})
-			}
-		}
+		relevantData.Receipts = receipts
 	}
 
-	// Sort by similarity score (highest first)
-	// Sort transactions
-	for i := 0; i < len(relevantData.Transactions); i++ {
-		for j := i + 1; j < len(relevantData.Transactions); j++ {
-			if relevantData.Transactions[i].Score < relevantData.Transactions[j].Score {
-				relevantData.Transactions[i], relevantData.Transactions[j] = relevantData.Transactions[j], relevantData.Transactions[i]
-			}
-		}
-	}
-
-	// Sort receipts
-	for i := 0; i < len(relevantData.Receipts); i++ {
-		for j := i + 1; j < len(relevantData.Receipts); j++ {
-			if relevantData.Receipts[i].Score < relevantData.Receipts[j].Score {
-				relevantData.Receipts[i], relevantData.Receipts[j] = relevantData.Receipts[j], relevantData.Receipts[i]
-			}
-		}
-	}
-
-	// Limit to top results for efficiency
-	if len(relevantData.Transactions) > 15 {
-		relevantData.Transactions = relevantData.Transactions[:15]
-	}
-	if len(relevantData.Receipts) > 10 {
-		relevantData.Receipts = relevantData.Receipts[:10]
+	receiptItems, err := s.receiptService.SearchSimilarReceiptItems(userId, queryEmbedding,
+		constants.RAGReceiptItemTopK, constants.RAGSimilarityThreshold)
+	if err != nil {
+		s.logging.LogWarn(fmt.Sprintf("Failed to search relevant receipt items: %s", err.Error()))
+	} else {
+		relevantData.ReceiptItems = receiptItems
 	}
 
-	s.logging.LogInfo(fmt.Sprintf("Found %d relevant transactions and %d relevant receipts",
-		len(relevantData.Transactions), len(relevantData.Receipts)))
+	s.logging.LogInfo(fmt.Sprintf("Found %d relevant transactions, %d relevant receipts and %d relevant receipt items",
+		len(relevantData.Transactions), len(relevantData.Receipts), len(relevantData.ReceiptItems)))
 
 	return relevantData, nil
 }
@@ -692,7 +596,7 @@ func (s *chatService) gatherUserKnowledge(ctx context.Context, userId string) (*
 	return knowledge, nil
 }
 
-func (s *chatService) buildKnowledgeContext(knowledge *models.UserKnowledge) string {
+func (s *chatService) buildKnowledgeContext(knowledge *models.UserKnowledge, prefs *responses.UserPreferences) string {
 	if knowledge == nil {
 		return ""
 	}
@@ -707,10 +611,10 @@ func (s *chatService) buildKnowledgeContext(knowledge *models.UserKnowledge) str
 				context += fmt.Sprintf("... and %d more transactions\n", len(knowledge.Transactions)-10)
 				break
 			}
-			context += fmt.Sprintf("- %s: %s Rp %.0f (%s) - %s\n",
+			context += fmt.Sprintf("- %s: %s %s (%s) - %s\n",
 				tx.TransactionDate.Format("2006-01-02"),
 				tx.Type,
-				float64(tx.Amount), // Amount in Rupiah (no conversion needed)
+				prefs.FormatAmount(tx.Amount),
 				tx.Source,
 				tx.Description)
 		}
@@ -724,11 +628,11 @@ func (s *chatService) buildKnowledgeContext(knowledge *models.UserKnowledge) str
 				context += fmt.Sprintf("... and %d more receipts\n", len(knowledge.Receipts)-5)
 				break
 			}
-			context += fmt.Sprintf("- %s: %s - Total: Rp %.0f (Discount: Rp %.0f)\n",
+			context += fmt.Sprintf("- %s: %s - Total: %s (Discount: %s)\n",
 				receipt.TransactionDate.Format("2006-01-02"),
 				receipt.MerchantName,
-				float64(receipt.TotalShopping), // Amount in Rupiah (no conversion needed)
-				float64(receipt.TotalDiscount))
+				prefs.FormatAmount(receipt.TotalShopping),
+				prefs.FormatAmount(receipt.TotalDiscount))
 		}
 	}
 
@@ -737,7 +641,7 @@ func (s *chatService) buildKnowledgeContext(knowledge *models.UserKnowledge) str
 	return context
 }
 
-func (s *chatService) buildRelevantKnowledgeContext(relevantData *models.RelevantFinancialData) string {
+func (s *chatService) buildRelevantKnowledgeContext(relevantData *models.RelevantFinancialData, prefs *responses.UserPreferences) string {
 	if relevantData == nil {
 		return ""
 	}
@@ -753,10 +657,10 @@ func (s *chatService) buildRelevantKnowledgeContext(relevantData *models.Relevan
 				break
 			}
 			tx := txWithScore.Transaction
-			context += fmt.Sprintf("- %s: %s Rp %.0f (%s) - %s (Relevance: %.2f)\n",
+			context += fmt.Sprintf("- %s: %s %s (%s) - %s (Relevance: %.2f)\n",
 				tx.TransactionDate.Format("2006-01-02"),
 				tx.Type,
-				float64(tx.Amount), // Amount in Rupiah (no conversion needed)
+				prefs.FormatAmount(tx.Amount),
 				tx.Source,
 				tx.Description,
 				txWithScore.Score)
@@ -772,23 +676,41 @@ func (s *chatService) buildRelevantKnowledgeContext(relevantData *models.Relevan
 				break
 			}
 			receipt := receiptWithScore.Receipt
-			context += fmt.Sprintf("- %s: %s - Total: Rp %.0f (Discount: Rp %.0f) (Relevance: %.2f)\n",
+			context += fmt.Sprintf("- %s: %s - Total: %s (Discount: %s) (Relevance: %.2f)\n",
 				receipt.TransactionDate.Format("2006-01-02"),
 				receipt.MerchantName,
-				float64(receipt.TotalShopping), // Amount in Rupiah (no conversion needed)
-				float64(receipt.TotalDiscount),
+				prefs.FormatAmount(receipt.TotalShopping),
+				prefs.FormatAmount(receipt.TotalDiscount),
 				receiptWithScore.Score)
 		}
 	}
 
+	// Add relevant receipt item context
+	if len(relevantData.ReceiptItems) > 0 {
+		context += fmt.Sprintf("\nMOST RELEVANT RECEIPT ITEMS (%d):\n", len(relevantData.ReceiptItems))
+		for i, itemWithScore := range relevantData.ReceiptItems {
+			if i >= 10 { // Limit display to 10 most relevant for prompt efficiency
+				context += fmt.Sprintf("... and %d more relevant receipt items\n", len(relevantData.ReceiptItems)-10)
+				break
+			}
+			item := itemWithScore.ReceiptItem
+			context += fmt.Sprintf("- %s: %s at %s - %d x %s = %s (Relevance: %.2f)\n",
+				itemWithScore.TransactionDate.Format("2006-01-02"),
+				item.ItemName,
+				itemWithScore.MerchantName,
+				item.ItemQuantity,
+				prefs.FormatAmount(item.ItemPrice),
+				prefs.FormatAmount(item.ItemPriceTotal),
+				itemWithScore.Score)
+		}
+	}
+
 	context += "\n--- END OF RELEVANT FINANCIAL DATA CONTEXT ---\n\n"
 
 	return context
 }
 
-func (s *chatService) getSystemPromptWithKnowledge(mode models.Mode, userId string, userQuery string, ctx context.Context) (string, error) {
-	basePrompt := s.getSystemPromptByMode(mode)
-
+func (s *chatService) getSystemPromptWithKnowledge(mode models.Mode, basePrompt string, userId string, userQuery string, prefs *responses.UserPreferences, ctx context.Context) (string, error) {
 	// Only enhance Ask mode with user knowledge
 	if mode != models.ModeChat {
 		return basePrompt, nil
@@ -802,17 +724,17 @@ func (s *chatService) getSystemPromptWithKnowledge(mode models.Mode, userId stri
 	}
 
 	// Build relevant knowledge context
-	knowledgeContext := s.buildRelevantKnowledgeContext(relevantData)
+	knowledgeContext := s.buildRelevantKnowledgeContext(relevantData, prefs)
 
 	// Check if we have relevant data
-	hasRelevantData := len(relevantData.Transactions) > 0 || len(relevantData.Receipts) > 0
+	hasRelevantData := len(relevantData.Transactions) > 0 || len(relevantData.Receipts) > 0 || len(relevantData.ReceiptItems) > 0
 
 	if !hasRelevantData {
 		// Fallback to basic knowledge if no relevant data found
 		s.logging.LogInfo("No relevant financial data found, using basic knowledge gathering")
 		basicKnowledge, err := s.gatherUserKnowledge(ctx, userId)
 		if err == nil {
-			knowledgeContext = s.buildKnowledgeContext(basicKnowledge)
+			knowledgeContext = s.buildKnowledgeContext(basicKnowledge, prefs)
 		}
 	}
 
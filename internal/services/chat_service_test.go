@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/chat"
+	"github.com/saufiroja/fin-ai/internal/domains/receipt"
+	"github.com/saufiroja/fin-ai/internal/domains/transaction"
+	"github.com/saufiroja/fin-ai/internal/domains/user"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/pkg/fx"
+	"github.com/saufiroja/fin-ai/pkg/llm"
+	"github.com/saufiroja/fin-ai/pkg/llm/cassette"
+	logging "github.com/saufiroja/fin-ai/pkg/loggings"
+)
+
+// sendChatMessageCassette was recorded from SendChatMessage in ask mode: the RAG query embedding, the Gemini
+// answer and the embedding of the message
+const sendChatMessageCassette = "send_chat_message.json"
+
+type fakeChatStorer struct {
+	chat.ChatStorer
+	history  []*models.ChatSessionDetail
+	messages []*models.ChatMessage
+}
+
+func (f *fakeChatStorer) InsertChatMessage(chatMessage *models.ChatMessage) error {
+	f.messages = append(f.messages, chatMessage)
+	return nil
+}
+
+func (f *fakeChatStorer) FindChatSessionDetailByChatSessionIdAndUserId(chatSessionId, userId string) ([]*models.ChatSessionDetail, error) {
+	return f.history, nil
+}
+
+type fakeUserManager struct {
+	user.UserManager
+}
+
+func (fakeUserManager) GetPreferences(userId string) (*responses.UserPreferences, error) {
+	idr, err := fx.Lookup("IDR")
+	if err != nil {
+		return nil, err
+	}
+	return &responses.UserPreferences{BaseCurrency: idr, UserPreferences: models.DefaultUserPreferences()}, nil
+}
+
+type fakeTransactionManager struct {
+	transaction.TransactionManager
+	similar   []models.TransactionWithScore
+	inserted  []*requests.TransactionRequest
+	insertErr error
+}
+
+func (f *fakeTransactionManager) InsertTransaction(req *requests.TransactionRequest) error {
+	if f.insertErr != nil {
+		return f.insertErr
+	}
+	f.inserted = append(f.inserted, req)
+	return nil
+}
+
+func (f *fakeTransactionManager) SearchSimilarTransactions(userId, embedding string, limit int, threshold float64) ([]models.TransactionWithScore, error) {
+	return f.similar, nil
+}
+
+type fakeReceiptManager struct {
+	receipt.ReceiptManager
+}
+
+func (fakeReceiptManager) SearchSimilarReceipts(userId, embedding string, limit int, threshold float64) ([]models.ReceiptWithScore, error) {
+	return nil, nil
+}
+
+func (fakeReceiptManager) SearchSimilarReceiptItems(userId, embedding string, limit int, threshold float64) ([]models.ReceiptItemWithScore, error) {
+	return nil, nil
+}
+
+func TestSendChatMessageReplaysCassette(t *testing.T) {
+	conf := cassetteConfig(sendChatMessageCassette, cassette.ModeReplay)
+	repository := &fakeChatStorer{
+		history: []*models.ChatSessionDetail{
+			{Message: "Berapa pengeluaran makan saya minggu ini?", Sender: models.ChatMessageSenderUser},
+			{Message: "Minggu ini pengeluaran makan Anda Rp 210.000.", Sender: models.ChatMessageSenderAssistant},
+		},
+	}
+	usage := &fakeLogMessageManager{}
+	transactions := &fakeTransactionManager{
+		similar: []models.TransactionWithScore{{
+			Transaction: &models.Transaction{
+				Type:            constants.ExpenseCategory,
+				Description:     "Kopi Kenangan",
+				Amount:          28000,
+				Source:          "manual",
+				TransactionDate: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC),
+			},
+			Score: 0.83,
+		}},
+	}
+	service := NewChatService(
+		repository,
+		logging.NewLogrusAdapter(),
+		llm.NewGemini(conf),
+		llm.NewOpenAI(conf),
+		nil,
+		usage,
+		transactions,
+		nil,
+		fakeReceiptManager{},
+		nil,
+		fakeUserManager{},
+		nil,
+	)
+
+	res, err := service.SendChatMessage(context.Background(), &models.ChatMessageRequest{
+		ChatSessionId: "01JB2R0A1B2C3D4E5F6G7H8J9K",
+		UserId:        "01JB2Q4K7N3P8S2V5X9Z1B4D6F",
+		Mode:          models.ModeChat,
+		Message:       "Berapa kali saya beli kopi bulan ini?",
+	})
+	if err != nil {
+		t.Fatalf("SendChatMessage() error = %v", err)
+	}
+
+	if len(res.Conversation) != 2 {
+		t.Fatalf("conversation has %d messages, want 2", len(res.Conversation))
+	}
+	answer := res.Conversation[1]
+	if answer.Sender != models.ChatMessageSenderAssistant || !strings.Contains(answer.Text, "Kopi Kenangan") {
+		t.Errorf("answer = %+v, want the recorded assistant answer", answer)
+	}
+	if len(repository.messages) != 2 || repository.messages[1].Message != answer.Text {
+		t.Errorf("stored messages = %d, want the question and the recorded answer", len(repository.messages))
+	}
+
+	if len(usage.logged) != 1 {
+		t.Fatalf("logged %d AI responses, want 1", len(usage.logged))
+	}
+	logged := usage.logged[0]
+	if logged.Topic != constants.TopicChat || logged.Model != "gemini-2.5-flash" || logged.InputToken != 412 || logged.OutputToken != 38 {
+		t.Errorf("log = %s %s %d/%d, want chat gemini-2.5-flash 412/38", logged.Topic, logged.Model, logged.InputToken, logged.OutputToken)
+	}
+
+	want := usageCall{constants.TopicChatEmbedding, llm.EmbeddingModel, 9, 0}
+	if len(usage.usage) != 2 {
+		t.Fatalf("usage logged %d times, want 2: %+v", len(usage.usage), usage.usage)
+	}
+	for _, call := range usage.usage {
+		if call != want {
+			t.Errorf("usage = %+v, want %+v", call, want)
+		}
+	}
+}
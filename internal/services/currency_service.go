@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/currency"
+	"github.com/saufiroja/fin-ai/internal/domains/user"
+	"github.com/saufiroja/fin-ai/internal/utils"
+	"github.com/saufiroja/fin-ai/pkg/fx"
+	logging "github.com/saufiroja/fin-ai/pkg/loggings"
+)
+
+type currencyService struct {
+	currencyRepository currency.CurrencyStorer
+	rateProvider       fx.Provider
+	logging            logging.Logger
+}
+
+// NewCurrencyService resolves rates from rateProvider, which is usually a chain of the fx_rates table
+// and an offline rate file
+func NewCurrencyService(currencyRepository currency.CurrencyStorer, rateProvider fx.Provider, logging logging.Logger) currency.CurrencyManager {
+	return &currencyService{
+		currencyRepository: currencyRepository,
+		rateProvider:       rateProvider,
+		logging:            logging,
+	}
+}
+
+func (s *currencyService) GetCurrencies(userId string) (*responses.CurrenciesResponse, error) {
+	base, err := s.GetBaseCurrency(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses.CurrenciesResponse{
+		BaseCurrency: responses.NewCurrencyMeta(base),
+		Currencies:   fx.Supported(),
+	}, nil
+}
+
+func (s *currencyService) GetBaseCurrency(userId string) (fx.Currency, error) {
+	code, err := s.currencyRepository.GetBaseCurrency(userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fx.Currency{}, user.ErrUserNotFound
+		}
+		s.logging.LogError(fmt.Sprintf("Error getting base currency of user %s: %v", userId, err))
+		return fx.Currency{}, fmt.Errorf("failed to get base currency: %w", err)
+	}
+
+	return fx.Lookup(code)
+}
+
+func (s *currencyService) SetBaseCurrency(userId string, req *requests.BaseCurrencyRequest) (*responses.CurrencyMeta, error) {
+	s.logging.LogInfo(fmt.Sprintf("Setting base currency of user %s to %s", userId, req.CurrencyCode))
+
+	target, err := fx.Lookup(req.CurrencyCode)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.GetBaseCurrency(userId)
+	if err != nil {
+		return nil, err
+	}
+	if current.Code == target.Code {
+		meta := responses.NewCurrencyMeta(current)
+		return &meta, nil
+	}
+
+	// Stored amounts are in the base currency, switching later would silently change their meaning
+	count, err := s.currencyRepository.CountUserTransactions(userId)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Error counting transactions of user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to count transactions: %w", err)
+	}
+	if count > 0 {
+		return nil, currency.ErrBaseCurrencyLocked
+	}
+
+	if err := s.currencyRepository.SetBaseCurrency(userId, target.Code); err != nil {
+		s.logging.LogError(fmt.Sprintf("Error setting base currency of user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to set base currency: %w", err)
+	}
+
+	meta := responses.NewCurrencyMeta(target)
+	return &meta, nil
+}
+
+func (s *currencyService) GetRate(ctx context.Context, req *requests.ExchangeRateQuery) (*fx.Rate, error) {
+	base, err := fx.Lookup(req.Base)
+	if err != nil {
+		return nil, err
+	}
+	quote, err := fx.Lookup(req.Quote)
+	if err != nil {
+		return nil, err
+	}
+
+	date := time.Now()
+	if req.Date != "" {
+		date, err = time.Parse(utils.DateLayout, req.Date)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid date", currency.ErrInvalidRate)
+		}
+	}
+
+	rate, err := s.rateProvider.Rate(ctx, base.Code, quote.Code, date)
+	if err != nil {
+		if !errors.Is(err, fx.ErrRateNotFound) {
+			s.logging.LogError(fmt.Sprintf("Error getting rate %s/%s: %v", base.Code, quote.Code, err))
+		}
+		return nil, err
+	}
+
+	return &rate, nil
+}
+
+func (s *currencyService) UpsertRates(req *requests.UpsertExchangeRatesRequest) (*responses.UpsertExchangeRatesResponse, error) {
+	rates := make([]fx.Rate, 0, len(req.Rates))
+	for i, line := range req.Rates {
+		base, err := fx.Lookup(line.Base)
+		if err != nil {
+			return nil, fmt.Errorf("rate %d: %w", i+1, err)
+		}
+		quote, err := fx.Lookup(line.Quote)
+		if err != nil {
+			return nil, fmt.Errorf("rate %d: %w", i+1, err)
+		}
+		if base.Code == quote.Code {
+			return nil, fmt.Errorf("%w: rate %d converts %s into itself", currency.ErrInvalidRate, i+1, base.Code)
+		}
+		date, err := time.Parse(utils.DateLayout, line.Date)
+		if err != nil {
+			return nil, fmt.Errorf("%w: rate %d has an invalid date", currency.ErrInvalidRate, i+1)
+		}
+		if line.Rate <= 0 {
+			return nil, fmt.Errorf("%w: rate %d must be positive", currency.ErrInvalidRate, i+1)
+		}
+
+		rates = append(rates, fx.Rate{Base: base.Code, Quote: quote.Code, Date: date, Value: line.Rate, Source: "manual"})
+	}
+
+	if err := s.currencyRepository.UpsertRates(rates); err != nil {
+		s.logging.LogError(fmt.Sprintf("Error saving exchange rates: %v", err))
+		return nil, fmt.Errorf("failed to save exchange rates: %w", err)
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Saved %d exchange rates", len(rates)))
+	return &responses.UpsertExchangeRatesResponse{Saved: len(rates)}, nil
+}
+
+func (s *currencyService) ToBase(ctx context.Context, userId string, amount int64, code string, date time.Time) (*fx.Conversion, error) {
+	base, err := s.GetBaseCurrency(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	from := base
+	if code != "" {
+		from, err = fx.Lookup(code)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rate, err := s.rateProvider.Rate(ctx, from.Code, base.Code, date)
+	if err != nil {
+		if !errors.Is(err, fx.ErrRateNotFound) {
+			s.logging.LogError(fmt.Sprintf("Error getting rate %s/%s: %v", from.Code, base.Code, err))
+		}
+		return nil, err
+	}
+
+	return &fx.Conversion{
+		From:      from,
+		To:        base,
+		Amount:    amount,
+		Converted: fx.Convert(amount, from, base, rate.Value),
+		Rate:      rate,
+	}, nil
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/currency"
+	"github.com/saufiroja/fin-ai/internal/domains/forecast"
+	"github.com/saufiroja/fin-ai/internal/domains/recurring"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/internal/utils"
+	cashflow "github.com/saufiroja/fin-ai/pkg/forecast"
+	logging "github.com/saufiroja/fin-ai/pkg/loggings"
+)
+
+type forecastService struct {
+	forecastRepository forecast.ForecastStorer
+	recurringService   recurring.RecurringManager
+	currencyService    currency.CurrencyManager
+	logging            logging.Logger
+}
+
+func NewForecastService(
+	forecastRepository forecast.ForecastStorer,
+	recurringService recurring.RecurringManager,
+	currencyService currency.CurrencyManager,
+	logging logging.Logger,
+) forecast.ForecastManager {
+	return &forecastService{
+		forecastRepository: forecastRepository,
+		recurringService:   recurringService,
+		currencyService:    currencyService,
+		logging:            logging,
+	}
+}
+
+// GetForecast gathers the inputs and delegates the math to pkg/forecast, which is deterministic
+func (s *forecastService) GetForecast(userId string, req *requests.ForecastQuery, now time.Time) (*responses.ForecastResponse, error) {
+	months := req.Months
+	if months <= 0 {
+		months = constants.ForecastDefaultMonths
+	}
+	if months > constants.ForecastMaxMonths {
+		months = constants.ForecastMaxMonths
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Forecasting cash flow for user %s over %d months", userId, months))
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	until := today.AddDate(0, 0, 1)
+	monthStart := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	horizonEnd := time.Date(today.Year(), today.Month()+time.Month(months), 0, 0, 0, 0, 0, time.UTC)
+
+	var startingBalance int64
+	if req.CurrentBalance != "" {
+		balance, err := strconv.ParseInt(req.CurrentBalance, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid current balance: %w", err)
+		}
+		startingBalance = balance
+	} else {
+		balance, err := s.forecastRepository.GetBalance(userId, until)
+		if err != nil {
+			s.logging.LogError(fmt.Sprintf("Failed to get balance for user %s: %v", userId, err))
+			return nil, fmt.Errorf("failed to get balance: %w", err)
+		}
+		startingBalance = balance
+	}
+
+	// New users have less history, averaging over days before their first transaction would understate spending
+	historyStart := today.AddDate(0, 0, -constants.ForecastHistoryDays+1)
+	firstDate, err := s.forecastRepository.GetFirstTransactionDate(userId)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get first transaction date for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to get first transaction date: %w", err)
+	}
+	if firstDate != nil {
+		first := time.Date(firstDate.Year(), firstDate.Month(), firstDate.Day(), 0, 0, 0, 0, time.UTC)
+		if first.After(historyStart) && !first.After(today) {
+			historyStart = first
+		}
+	}
+
+	// Recurring transactions are projected from their rules, so they are left out of the daily averages
+	history, err := s.forecastRepository.GetDailyEntries(userId, historyStart, until, true)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get forecast history for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to get forecast history: %w", err)
+	}
+
+	actual, err := s.forecastRepository.GetDailyEntries(userId, monthStart, until, false)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get month-to-date transactions for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to get month-to-date transactions: %w", err)
+	}
+
+	bills, err := s.recurringService.GetUpcomingBills(userId, utils.DaysInRange(today, horizonEnd))
+	if err != nil {
+		return nil, err
+	}
+
+	scheduled := make([]cashflow.Entry, 0, len(bills.Bills))
+	for _, bill := range bills.Bills {
+		scheduled = append(scheduled, cashflow.Entry{
+			Date:       bill.DueDate,
+			CategoryId: bill.CategoryId,
+			Income:     bill.Type == constants.IncomeCategory,
+			Amount:     bill.Amount,
+		})
+	}
+
+	out := cashflow.Project(cashflow.Input{
+		Today:           today,
+		Months:          months,
+		StartingBalance: startingBalance,
+		HistoryStart:    historyStart,
+		History:         s.toEntries(history),
+		Actual:          s.toEntries(actual),
+		Scheduled:       scheduled,
+		Z:               constants.ForecastConfidenceZScore,
+	})
+
+	base, err := s.currencyService.GetBaseCurrency(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &responses.ForecastResponse{
+		Currency:        responses.NewCurrencyMeta(base),
+		AsOf:            today.Format(utils.DateLayout),
+		HistoryDays:     utils.DaysInRange(historyStart, today),
+		ConfidenceLevel: constants.ForecastConfidenceLevel,
+		StartingBalance: startingBalance,
+		Months:          make([]responses.ForecastMonth, 0, len(out.Months)),
+		Daily:           make([]responses.ForecastDay, 0, len(out.Days)),
+		LowestBalance:   s.toDay(out.Lowest),
+	}
+
+	var categoryIds []string
+	for _, month := range out.Months {
+		for _, category := range month.Categories {
+			categoryIds = append(categoryIds, category.CategoryId)
+		}
+	}
+	names, err := s.forecastRepository.GetCategoryNames(categoryIds)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get category names: %v", err))
+		return nil, fmt.Errorf("failed to get category names: %w", err)
+	}
+
+	for _, month := range out.Months {
+		forecastMonth := responses.ForecastMonth{
+			Month:            month.Month.Format("2006-01"),
+			StartDate:        month.Start.Format(utils.DateLayout),
+			EndDate:          month.End.Format(utils.DateLayout),
+			Income:           s.toBand(month.Income),
+			Expense:          s.toBand(month.Expense),
+			ScheduledIncome:  month.ScheduledIncome,
+			ScheduledExpense: month.ScheduledExpense,
+			EndBalance:       s.toBand(month.EndBalance),
+			Categories:       make([]responses.ForecastCategory, 0, len(month.Categories)),
+		}
+		for _, category := range month.Categories {
+			forecastMonth.Categories = append(forecastMonth.Categories, responses.ForecastCategory{
+				CategoryId:   category.CategoryId,
+				CategoryName: names[category.CategoryId],
+				Spend:        s.toBand(category.Spend),
+				Actual:       category.Actual,
+				Scheduled:    category.Scheduled,
+			})
+		}
+		res.Months = append(res.Months, forecastMonth)
+	}
+
+	for _, day := range out.Days {
+		res.Daily = append(res.Daily, s.toDay(day))
+	}
+	if out.FirstNegative != nil {
+		day := s.toDay(*out.FirstNegative)
+		res.FirstNegative = &day
+	}
+	if out.FirstAtRisk != nil {
+		day := s.toDay(*out.FirstAtRisk)
+		res.FirstAtRisk = &day
+	}
+	res.NextPayday = s.nextPayday(bills.Bills, out, today, startingBalance)
+
+	return res, nil
+}
+
+// nextPayday reports the first scheduled income after today with the balance right before it arrives
+func (s *forecastService) nextPayday(bills []responses.UpcomingBill, out cashflow.Output, today time.Time, startingBalance int64) *responses.ForecastDay {
+	for _, bill := range bills {
+		if bill.Type != constants.IncomeCategory || !bill.DueDate.After(today) {
+			continue
+		}
+
+		dayBefore := bill.DueDate.AddDate(0, 0, -1)
+		balance := responses.ForecastBand{Expected: startingBalance, Low: startingBalance, High: startingBalance}
+		for _, day := range out.Days {
+			if day.Date.Equal(dayBefore) {
+				balance = s.toBand(day.Balance)
+				break
+			}
+		}
+
+		return &responses.ForecastDay{
+			Date:    bill.DueDate.Format(utils.DateLayout),
+			Balance: balance,
+		}
+	}
+
+	return nil
+}
+
+func (s *forecastService) toEntries(entries []models.ForecastEntry) []cashflow.Entry {
+	result := make([]cashflow.Entry, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, cashflow.Entry{
+			Date:       entry.Date,
+			CategoryId: entry.CategoryId,
+			Income:     entry.Type == constants.IncomeCategory,
+			Amount:     entry.Amount,
+		})
+	}
+	return result
+}
+
+func (s *forecastService) toBand(band cashflow.Band) responses.ForecastBand {
+	return responses.ForecastBand{
+		Expected: band.Expected,
+		Low:      band.Low,
+		High:     band.High,
+	}
+}
+
+func (s *forecastService) toDay(day cashflow.DayProjection) responses.ForecastDay {
+	return responses.ForecastDay{
+		Date:    day.Date.Format(utils.DateLayout),
+		Balance: s.toBand(day.Balance),
+	}
+}
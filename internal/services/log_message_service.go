@@ -1,6 +1,12 @@
 package services
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/saufiroja/fin-ai/internal/constants"
 	"github.com/saufiroja/fin-ai/internal/domains/log_message"
 	"github.com/saufiroja/fin-ai/internal/models"
 	logging "github.com/saufiroja/fin-ai/pkg/loggings"
@@ -23,6 +29,13 @@ func NewLogMessageService(
 
 func (s *logMessageService) InsertLogMessage(logMessage *models.LogMessage) error {
 	s.logging.LogInfo("inserting log message: " + logMessage.Topic)
+
+	cost, ok := modelCost(logMessage.Model, logMessage.InputToken, logMessage.OutputToken)
+	if !ok {
+		s.logging.LogWarn(fmt.Sprintf("no price for model %q, the %s call is logged without cost", logMessage.Model, logMessage.Topic))
+	}
+	logMessage.Cost = cost
+
 	err := s.logMessageRepository.InsertLogMessage(logMessage)
 	if err != nil {
 		s.logging.LogError("failed to insert log message: " + err.Error())
@@ -32,3 +45,35 @@ func (s *logMessageService) InsertLogMessage(logMessage *models.LogMessage) erro
 	s.logging.LogInfo("log message inserted successfully")
 	return nil
 }
+
+func (s *logMessageService) LogUsage(userId, topic, model string, inputTokens, outputTokens int) {
+	dateNow := time.Now()
+	_ = s.InsertLogMessage(&models.LogMessage{
+		LogMessageId: ulid.Make().String(),
+		UserId:       userId,
+		InputToken:   inputTokens,
+		OutputToken:  outputTokens,
+		Topic:        topic,
+		Model:        model,
+		CreatedAt:    dateNow,
+		UpdatedAt:    dateNow,
+	})
+}
+
+// modelCost prices the tokens of a call in USD, false when the model has no price
+func modelCost(model string, inputTokens, outputTokens int) (float64, bool) {
+	price, ok := constants.ModelPrices[model]
+	if !ok {
+		// Dated versions like gpt-4o-mini-2024-07-18 use the price of the longest matching name
+		matched := ""
+		for name, candidate := range constants.ModelPrices {
+			if strings.HasPrefix(model, name) && len(name) > len(matched) {
+				matched, price, ok = name, candidate, true
+			}
+		}
+	}
+	if !ok {
+		return 0, false
+	}
+	return (float64(inputTokens)*price.Input + float64(outputTokens)*price.Output) / 1_000_000, true
+}
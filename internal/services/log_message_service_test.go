@@ -0,0 +1,109 @@
+package services
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/domains/log_message"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/pkg/llm"
+	logging "github.com/saufiroja/fin-ai/pkg/loggings"
+)
+
+type fakeLogMessageStorer struct {
+	log_message.LogMessageStorer
+	inserted []*models.LogMessage
+}
+
+func (f *fakeLogMessageStorer) InsertLogMessage(logMessage *models.LogMessage) error {
+	f.inserted = append(f.inserted, logMessage)
+	return nil
+}
+
+// warnLogger records warnings and drops everything else
+type warnLogger struct {
+	logging.Logger
+	warnings []string
+}
+
+func (l *warnLogger) LogInfo(message string)  {}
+func (l *warnLogger) LogError(message string) {}
+func (l *warnLogger) LogWarn(message string)  { l.warnings = append(l.warnings, message) }
+
+func TestModelCost(t *testing.T) {
+	tests := []struct {
+		name         string
+		model        string
+		inputTokens  int
+		outputTokens int
+		want         float64
+		wantPriced   bool
+	}{
+		{name: "gemini flash", model: "gemini-2.5-flash", inputTokens: 1_000_000, outputTokens: 1_000_000, want: 2.80, wantPriced: true},
+		{name: "gemini flash lite", model: "gemini-2.5-flash-lite", inputTokens: 2000, outputTokens: 500, want: 0.0004, wantPriced: true},
+		{name: "gemini pro", model: "gemini-2.5-pro", inputTokens: 1000, outputTokens: 1000, want: 0.01125, wantPriced: true},
+		{name: "gpt-4o", model: "gpt-4o", inputTokens: 1000, outputTokens: 100, want: 0.0035, wantPriced: true},
+		{name: "gpt-4o-mini", model: "gpt-4o-mini", inputTokens: 1000, outputTokens: 500, want: 0.00045, wantPriced: true},
+		{name: "dated model uses the longest matching name", model: "gpt-4o-mini-2024-07-18", inputTokens: 1000, outputTokens: 500, want: 0.00045, wantPriced: true},
+		{name: "dated gpt-4o", model: "gpt-4o-2024-08-06", inputTokens: 1000, outputTokens: 100, want: 0.0035, wantPriced: true},
+		{name: "preview of a priced model", model: "gemini-2.5-flash-lite-preview-06-17", inputTokens: 2000, outputTokens: 500, want: 0.0004, wantPriced: true},
+		{name: "embedding call", model: llm.EmbeddingModel, inputTokens: 1_000_000, want: 0.02, wantPriced: true},
+		{name: "no tokens", model: "gemini-2.5-flash", want: 0, wantPriced: true},
+		{name: "unknown model", model: "llama-3.1-70b", inputTokens: 1000, outputTokens: 1000, want: 0, wantPriced: false},
+		{name: "empty model", model: "", inputTokens: 1000, want: 0, wantPriced: false},
+		{name: "name that only shares a prefix", model: "gpt-4", inputTokens: 1000, want: 0, wantPriced: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, priced := modelCost(tt.model, tt.inputTokens, tt.outputTokens)
+			if priced != tt.wantPriced {
+				t.Errorf("modelCost() priced = %v, want %v", priced, tt.wantPriced)
+			}
+			if math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("modelCost() = %.10f, want %.10f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogUsageCost(t *testing.T) {
+	tests := []struct {
+		name         string
+		model        string
+		inputTokens  int
+		outputTokens int
+		want         float64
+		wantWarning  bool
+	}{
+		{name: "embedding only", model: llm.EmbeddingModel, inputTokens: 9, want: 9 * 0.02 / 1_000_000},
+		{name: "chat completion", model: "gemini-2.5-flash", inputTokens: 412, outputTokens: 38, want: (412*0.30 + 38*2.50) / 1_000_000},
+		{name: "unknown model", model: "mistral-large", inputTokens: 412, outputTokens: 38, want: 0, wantWarning: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &fakeLogMessageStorer{}
+			logger := &warnLogger{}
+			service := NewLogMessageService(repository, logger)
+
+			service.LogUsage("01JB2Q4K7N3P8S2V5X9Z1B4D6F", constants.TopicChatEmbedding, tt.model, tt.inputTokens, tt.outputTokens)
+
+			if len(repository.inserted) != 1 {
+				t.Fatalf("inserted %d log messages, want 1", len(repository.inserted))
+			}
+			logged := repository.inserted[0]
+			if math.Abs(logged.Cost-tt.want) > 1e-12 {
+				t.Errorf("cost = %.10f, want %.10f", logged.Cost, tt.want)
+			}
+			if logged.Model != tt.model || logged.InputToken != tt.inputTokens || logged.OutputToken != tt.outputTokens {
+				t.Errorf("logged %s %d/%d, want %s %d/%d", logged.Model, logged.InputToken, logged.OutputToken, tt.model, tt.inputTokens, tt.outputTokens)
+			}
+			if warned := len(logger.warnings) == 1 && strings.Contains(logger.warnings[0], tt.model); warned != tt.wantWarning {
+				t.Errorf("warnings = %v, want a warning about the model: %v", logger.warnings, tt.wantWarning)
+			}
+		})
+	}
+}
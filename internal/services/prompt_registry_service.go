@@ -0,0 +1,505 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/constants/prompt"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/prompt_registry"
+	"github.com/saufiroja/fin-ai/internal/i18n"
+	"github.com/saufiroja/fin-ai/internal/models"
+	logging "github.com/saufiroja/fin-ai/pkg/loggings"
+)
+
+var promptVersionPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+type promptRegistryService struct {
+	promptRegistryRepository prompt_registry.PromptRegistryStorer
+	logging                  logging.Logger
+
+	mu       sync.Mutex
+	snapshot *promptSnapshot
+}
+
+// promptSnapshot is the registry as it was read at loadedAt, it is replaced and never changed
+type promptSnapshot struct {
+	loadedAt    time.Time
+	contents    map[string]string                  // by promptRef
+	active      map[string]string                  // active version by prompt key
+	experiments map[string]models.PromptExperiment // running experiment by prompt key
+}
+
+func NewPromptRegistryService(
+	promptRegistryRepository prompt_registry.PromptRegistryStorer,
+	logging logging.Logger,
+) prompt_registry.PromptRegistryManager {
+	return &promptRegistryService{
+		promptRegistryRepository: promptRegistryRepository,
+		logging:                  logging,
+	}
+}
+
+// promptResolver returns a prompt in the locale of a call and the version it comes from
+type promptResolver func(key prompt.Key) models.ResolvedPrompt
+
+// resolverFor binds the user and the locale of a call, without a registry the built-in prompts are served
+func resolverFor(registry prompt_registry.PromptRegistryManager, userId, locale string) promptResolver {
+	if registry == nil {
+		return builtinPrompts(locale)
+	}
+	return func(key prompt.Key) models.ResolvedPrompt {
+		return registry.Resolve(userId, locale, key)
+	}
+}
+
+// builtinPrompts serves the prompts of the catalog in internal/constants/prompt
+func builtinPrompts(locale string) promptResolver {
+	return func(key prompt.Key) models.ResolvedPrompt {
+		return models.ResolvedPrompt{
+			Key:     string(key),
+			Version: constants.PromptVersionBuiltin,
+			Content: prompt.Get(locale, key),
+		}
+	}
+}
+
+func promptRef(key, version, locale string) string {
+	return key + "/" + version + "/" + locale
+}
+
+// promptBucket places a user in one of 100 buckets, the same user always lands in the same bucket of an
+// experiment and the buckets of two experiments are independent
+func promptBucket(experimentId, userId string) int {
+	h := fnv.New32a()
+	h.Write([]byte(experimentId + ":" + userId))
+	return int(h.Sum32() % 100)
+}
+
+func (s *promptRegistryService) Resolve(userId, locale string, key prompt.Key) models.ResolvedPrompt {
+	locale = i18n.Match(i18n.Default, locale)
+	snapshot := s.current()
+
+	version, hasActive := snapshot.active[string(key)]
+	if experiment, ok := snapshot.experiments[string(key)]; ok {
+		version = experiment.ControlVersion
+		if promptBucket(experiment.PromptExperimentId, userId) < experiment.CandidatePercent {
+			version = experiment.CandidateVersion
+		}
+	}
+
+	// A version that is not recorded in the locale falls back to the active version, then to the built-in one
+	if version != "" && version != constants.PromptVersionBuiltin {
+		candidates := []string{version}
+		if hasActive {
+			candidates = append(candidates, snapshot.active[string(key)])
+		}
+		for _, candidate := range candidates {
+			if content, ok := snapshot.contents[promptRef(string(key), candidate, locale)]; ok {
+				return models.ResolvedPrompt{Key: string(key), Version: candidate, Content: content}
+			}
+		}
+	}
+
+	return builtinPrompts(locale)(key)
+}
+
+// current returns the snapshot, reading the registry again once it is older than PromptCacheTTL
+func (s *promptRegistryService) current() *promptSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.snapshot != nil && time.Since(s.snapshot.loadedAt) < constants.PromptCacheTTL {
+		return s.snapshot
+	}
+
+	snapshot, err := s.load()
+	if err != nil {
+		// Keep serving what was read before, or the built-in prompts, and try again after the TTL
+		s.logging.LogError(fmt.Sprintf("Failed to load prompt registry: %v", err))
+		if s.snapshot == nil {
+			snapshot = &promptSnapshot{
+				contents:    map[string]string{},
+				active:      map[string]string{},
+				experiments: map[string]models.PromptExperiment{},
+			}
+		} else {
+			snapshot = &promptSnapshot{
+				contents:    s.snapshot.contents,
+				active:      s.snapshot.active,
+				experiments: s.snapshot.experiments,
+			}
+		}
+		snapshot.loadedAt = time.Now()
+	}
+
+	s.snapshot = snapshot
+	return s.snapshot
+}
+
+func (s *promptRegistryService) load() (*promptSnapshot, error) {
+	versions, err := s.promptRegistryRepository.GetPromptVersions()
+	if err != nil {
+		return nil, err
+	}
+	experiments, err := s.promptRegistryRepository.GetPromptExperiments()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &promptSnapshot{
+		loadedAt:    time.Now(),
+		contents:    make(map[string]string, len(versions)),
+		active:      map[string]string{},
+		experiments: map[string]models.PromptExperiment{},
+	}
+	for _, version := range versions {
+		snapshot.contents[promptRef(version.PromptKey, version.Version, version.Locale)] = version.Content
+		if version.IsActive {
+			snapshot.active[version.PromptKey] = version.Version
+		}
+	}
+	for _, experiment := range experiments {
+		if experiment.EndedAt == nil {
+			snapshot.experiments[experiment.PromptKey] = experiment
+		}
+	}
+
+	return snapshot, nil
+}
+
+// invalidate makes the next Resolve read the registry again
+func (s *promptRegistryService) invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snapshot = nil
+}
+
+// validateVersion checks a version before it is recorded, the placeholders must match the built-in prompt
+// because the callers fill them in the same order
+func (s *promptRegistryService) validateVersion(key, version, locale, content string) error {
+	if !prompt.IsKey(key) {
+		return fmt.Errorf("%w: unknown prompt key %q", prompt_registry.ErrInvalidPromptVersion, key)
+	}
+	if version == constants.PromptVersionBuiltin || !promptVersionPattern.MatchString(version) {
+		return fmt.Errorf("%w: version %q must be letters, numbers, dots, dashes or underscores and not %q",
+			prompt_registry.ErrInvalidPromptVersion, version, constants.PromptVersionBuiltin)
+	}
+	if !slices.Contains(i18n.Supported, locale) {
+		return fmt.Errorf("%w: unsupported locale %q", prompt_registry.ErrInvalidPromptVersion, locale)
+	}
+	if strings.TrimSpace(content) == "" {
+		return fmt.Errorf("%w: content is empty", prompt_registry.ErrInvalidPromptVersion)
+	}
+	if err := prompt.CheckPlaceholders(prompt.Key(key), content); err != nil {
+		return fmt.Errorf("%w: %v", prompt_registry.ErrInvalidPromptVersion, err)
+	}
+	return nil
+}
+
+func (s *promptRegistryService) SyncPromptFiles(dir string) error {
+	s.logging.LogInfo(fmt.Sprintf("Syncing prompt files from %s", dir))
+
+	recorded, err := s.promptRegistryRepository.GetPromptVersions()
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get prompt versions: %v", err))
+		return fmt.Errorf("failed to get prompt versions: %w", err)
+	}
+	contents := make(map[string]string, len(recorded))
+	for _, version := range recorded {
+		contents[promptRef(version.PromptKey, version.Version, version.Locale)] = version.Content
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*", "*", "*.txt"))
+	if err != nil {
+		return fmt.Errorf("failed to list prompt files: %w", err)
+	}
+
+	inserted := 0
+	for _, path := range files {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		key, version, locale := parts[0], parts[1], strings.TrimSuffix(parts[2], ".txt")
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read prompt file %s: %w", path, err)
+		}
+		if err := s.validateVersion(key, version, locale, string(content)); err != nil {
+			s.logging.LogWarn(fmt.Sprintf("Skipping prompt file %s: %v", path, err))
+			continue
+		}
+
+		// Versions never change, a file that was edited after it was recorded needs a new version
+		if existing, ok := contents[promptRef(key, version, locale)]; ok {
+			if existing != string(content) {
+				s.logging.LogWarn(fmt.Sprintf("Prompt file %s differs from the recorded version %s, keeping the recorded one", path, version))
+			}
+			continue
+		}
+
+		ok, err := s.promptRegistryRepository.InsertPromptVersion(&models.PromptVersion{
+			PromptVersionId: ulid.Make().String(),
+			PromptKey:       key,
+			Version:         version,
+			Locale:          locale,
+			Content:         string(content),
+			Source:          constants.PromptSourceFile,
+			CreatedAt:       time.Now(),
+		})
+		if err != nil {
+			s.logging.LogError(fmt.Sprintf("Failed to record prompt file %s: %v", path, err))
+			return fmt.Errorf("failed to record prompt file %s: %w", path, err)
+		}
+		if ok {
+			inserted++
+		}
+	}
+
+	s.invalidate()
+	s.logging.LogInfo(fmt.Sprintf("Recorded %d new prompt versions from %d files", inserted, len(files)))
+	return nil
+}
+
+func (s *promptRegistryService) GetPrompts() ([]responses.PromptSummary, error) {
+	versions, err := s.promptRegistryRepository.GetPromptVersions()
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get prompt versions: %v", err))
+		return nil, fmt.Errorf("failed to get prompt versions: %w", err)
+	}
+
+	summaries := make([]responses.PromptSummary, 0, len(prompt.Keys))
+	for _, key := range prompt.Keys {
+		summary := responses.PromptSummary{
+			PromptKey:     string(key),
+			ActiveVersion: constants.PromptVersionBuiltin,
+			Versions:      []models.PromptVersion{},
+		}
+		for _, version := range versions {
+			if version.PromptKey != string(key) {
+				continue
+			}
+			summary.Versions = append(summary.Versions, version)
+			if version.IsActive {
+				summary.ActiveVersion = version.Version
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, nil
+}
+
+func (s *promptRegistryService) CreatePromptVersion(req *requests.CreatePromptVersionRequest) (*models.PromptVersion, error) {
+	s.logging.LogInfo(fmt.Sprintf("Recording prompt %s version %s (%s)", req.PromptKey, req.Version, req.Locale))
+
+	if err := s.validateVersion(req.PromptKey, req.Version, req.Locale, req.Content); err != nil {
+		return nil, err
+	}
+
+	version := &models.PromptVersion{
+		PromptVersionId: ulid.Make().String(),
+		PromptKey:       req.PromptKey,
+		Version:         req.Version,
+		Locale:          req.Locale,
+		Content:         req.Content,
+		Description:     strings.TrimSpace(req.Description),
+		Source:          constants.PromptSourceAPI,
+		CreatedAt:       time.Now(),
+	}
+	ok, err := s.promptRegistryRepository.InsertPromptVersion(version)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to insert prompt version: %v", err))
+		return nil, fmt.Errorf("failed to insert prompt version: %w", err)
+	}
+	if !ok {
+		return nil, prompt_registry.ErrPromptVersionExists
+	}
+
+	s.invalidate()
+	return version, nil
+}
+
+func (s *promptRegistryService) ActivatePromptVersion(promptKey string, req *requests.ActivatePromptVersionRequest) error {
+	s.logging.LogInfo(fmt.Sprintf("Activating prompt %s version %s", promptKey, req.Version))
+
+	if !prompt.IsKey(promptKey) {
+		return fmt.Errorf("%w: unknown prompt key %q", prompt_registry.ErrInvalidPromptVersion, promptKey)
+	}
+
+	version := req.Version
+	if version == constants.PromptVersionBuiltin {
+		version = ""
+	}
+	err := s.promptRegistryRepository.ActivatePromptVersion(promptKey, version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return prompt_registry.ErrPromptVersionNotFound
+	}
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to activate prompt version: %v", err))
+		return fmt.Errorf("failed to activate prompt version: %w", err)
+	}
+
+	s.invalidate()
+	return nil
+}
+
+func (s *promptRegistryService) GetPromptExperiments() ([]models.PromptExperiment, error) {
+	experiments, err := s.promptRegistryRepository.GetPromptExperiments()
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get prompt experiments: %v", err))
+		return nil, fmt.Errorf("failed to get prompt experiments: %w", err)
+	}
+	if experiments == nil {
+		experiments = []models.PromptExperiment{}
+	}
+	return experiments, nil
+}
+
+func (s *promptRegistryService) StartPromptExperiment(req *requests.StartPromptExperimentRequest) (*models.PromptExperiment, error) {
+	s.logging.LogInfo(fmt.Sprintf("Starting prompt experiment on %s: %s vs %s (%d%%)",
+		req.PromptKey, req.ControlVersion, req.CandidateVersion, req.CandidatePercent))
+
+	if !prompt.IsKey(req.PromptKey) {
+		return nil, fmt.Errorf("%w: unknown prompt key %q", prompt_registry.ErrInvalidPromptVersion, req.PromptKey)
+	}
+
+	versions, err := s.promptRegistryRepository.GetPromptVersions()
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get prompt versions: %v", err))
+		return nil, fmt.Errorf("failed to get prompt versions: %w", err)
+	}
+	for _, wanted := range []string{req.ControlVersion, req.CandidateVersion} {
+		found := wanted == constants.PromptVersionBuiltin || slices.ContainsFunc(versions, func(v models.PromptVersion) bool {
+			return v.PromptKey == req.PromptKey && v.Version == wanted
+		})
+		if !found {
+			return nil, fmt.Errorf("%w: %s", prompt_registry.ErrPromptVersionNotFound, wanted)
+		}
+	}
+
+	experiments, err := s.promptRegistryRepository.GetPromptExperiments()
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get prompt experiments: %v", err))
+		return nil, fmt.Errorf("failed to get prompt experiments: %w", err)
+	}
+	for _, experiment := range experiments {
+		if experiment.PromptKey == req.PromptKey && experiment.EndedAt == nil {
+			return nil, prompt_registry.ErrPromptExperimentRunning
+		}
+	}
+
+	experimentId := ulid.Make().String()
+	err = s.promptRegistryRepository.InsertPromptExperiment(&models.PromptExperiment{
+		PromptExperimentId: experimentId,
+		PromptKey:          req.PromptKey,
+		ControlVersion:     req.ControlVersion,
+		CandidateVersion:   req.CandidateVersion,
+		CandidatePercent:   req.CandidatePercent,
+	})
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to insert prompt experiment: %v", err))
+		return nil, fmt.Errorf("failed to insert prompt experiment: %w", err)
+	}
+
+	s.invalidate()
+	return s.getPromptExperiment(experimentId)
+}
+
+func (s *promptRegistryService) getPromptExperiment(experimentId string) (*models.PromptExperiment, error) {
+	experiment, err := s.promptRegistryRepository.GetPromptExperimentById(experimentId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, prompt_registry.ErrPromptExperimentNotFound
+	}
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get prompt experiment %s: %v", experimentId, err))
+		return nil, fmt.Errorf("failed to get prompt experiment: %w", err)
+	}
+	return experiment, nil
+}
+
+func (s *promptRegistryService) EndPromptExperiment(experimentId string) (*models.PromptExperiment, error) {
+	s.logging.LogInfo(fmt.Sprintf("Ending prompt experiment %s", experimentId))
+
+	experiment, err := s.getPromptExperiment(experimentId)
+	if err != nil {
+		return nil, err
+	}
+	if experiment.EndedAt != nil {
+		return nil, prompt_registry.ErrPromptExperimentEnded
+	}
+
+	err = s.promptRegistryRepository.EndPromptExperiment(experimentId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, prompt_registry.ErrPromptExperimentEnded
+	}
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to end prompt experiment %s: %v", experimentId, err))
+		return nil, fmt.Errorf("failed to end prompt experiment: %w", err)
+	}
+
+	s.invalidate()
+	return s.getPromptExperiment(experimentId)
+}
+
+func (s *promptRegistryService) GetPromptExperimentResult(experimentId string) (*responses.PromptExperimentResult, error) {
+	experiment, err := s.getPromptExperiment(experimentId)
+	if err != nil {
+		return nil, err
+	}
+
+	outcomes, err := s.promptRegistryRepository.GetPromptVersionOutcomes(experiment.PromptKey,
+		[]string{experiment.ControlVersion, experiment.CandidateVersion}, experiment.StartedAt, experiment.EndedAt)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get outcomes of prompt experiment %s: %v", experimentId, err))
+		return nil, fmt.Errorf("failed to get prompt experiment outcomes: %w", err)
+	}
+
+	result := &responses.PromptExperimentResult{
+		Experiment: *experiment,
+		Control:    promptVersionResult(experiment.ControlVersion, outcomes),
+		Candidate:  promptVersionResult(experiment.CandidateVersion, outcomes),
+	}
+	return result, nil
+}
+
+func promptVersionResult(version string, outcomes []models.PromptVersionOutcome) responses.PromptVersionResult {
+	result := responses.PromptVersionResult{
+		PromptVersionOutcome: models.PromptVersionOutcome{Version: version},
+	}
+	for _, outcome := range outcomes {
+		if outcome.Version == version {
+			result.PromptVersionOutcome = outcome
+		}
+	}
+
+	result.ParseFailureRate = perCall(result.ParseFailures, result.Calls)
+	result.CorrectionRate = perCall(result.CorrectedCalls, result.Calls)
+	result.AverageInputTokens = perCall(result.InputTokens, result.Calls)
+	result.AverageOutputTokens = perCall(result.OutputTokens, result.Calls)
+	return result
+}
+
+// perCall divides a total by the number of calls, nil without calls
+func perCall(total, calls int64) *float64 {
+	if calls == 0 {
+		return nil
+	}
+	value := float64(total) / float64(calls)
+	return &value
+}
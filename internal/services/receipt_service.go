@@ -15,14 +15,18 @@ import (
 	"github.com/oklog/ulid/v2"
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/packages/param"
+	"github.com/saufiroja/fin-ai/internal/constants"
 	"github.com/saufiroja/fin-ai/internal/constants/prompt"
 	"github.com/saufiroja/fin-ai/internal/contracts/requests"
 	"github.com/saufiroja/fin-ai/internal/contracts/responses"
 	"github.com/saufiroja/fin-ai/internal/domains/categories"
 	"github.com/saufiroja/fin-ai/internal/domains/log_message"
+	"github.com/saufiroja/fin-ai/internal/domains/prompt_registry"
 	"github.com/saufiroja/fin-ai/internal/domains/receipt"
 	"github.com/saufiroja/fin-ai/internal/domains/transaction"
+	"github.com/saufiroja/fin-ai/internal/domains/user"
 	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/pkg/fx"
 	"github.com/saufiroja/fin-ai/pkg/llm"
 	logging "github.com/saufiroja/fin-ai/pkg/loggings"
 	"github.com/saufiroja/fin-ai/pkg/minio"
@@ -38,6 +42,9 @@ type receiptService struct {
 	logging            logging.Logger
 	openaiClient       llm.OpenAI
 	geminiClient       llm.Gemini
+	userService        user.UserManager
+	promptRegistry     prompt_registry.PromptRegistryManager
+	extractionModel    string
 	bucketName         string
 	objectName         string
 }
@@ -51,6 +58,8 @@ func NewReceiptService(
 	logging logging.Logger,
 	openaiClient llm.OpenAI,
 	geminiClient llm.Gemini,
+	userService user.UserManager,
+	promptRegistry prompt_registry.PromptRegistryManager,
 ) receipt.ReceiptManager {
 	return &receiptService{
 		receiptRepository:  receiptRepository,
@@ -63,10 +72,22 @@ func NewReceiptService(
 		bucketName:         "receipts",
 		objectName:         "receipt",
 		geminiClient:       geminiClient,
+		userService:        userService,
+		promptRegistry:     promptRegistry,
+		extractionModel:    constants.ReceiptExtractionModel,
 	}
 }
 
-func (s *receiptService) UploadReceipt(filePath *multipart.FileHeader, userId string) (*models.Receipt, error) {
+// receiptExtraction is the outcome of one extraction call, Data is nil when the response could not be parsed
+type receiptExtraction struct {
+	Data     *responses.ReceiptExtractionResponse
+	Response string
+	Usage    *responses.ResponseAI
+	Prompts  models.PromptVersions
+	ParseErr error
+}
+
+func (s *receiptService) UploadReceipt(ctx context.Context, filePath *multipart.FileHeader, userId string) (*models.Receipt, error) {
 	s.logging.LogInfo(fmt.Sprintf("Uploading receipt for user %s from file %s", userId, filePath.Filename))
 
 	if err := s.validateFileSize(filePath); err != nil {
@@ -83,20 +104,31 @@ func (s *receiptService) UploadReceipt(filePath *multipart.FileHeader, userId st
 		return nil, fmt.Errorf("failed to get categories: %w", err)
 	}
 
+	prefs, err := s.userService.GetPreferences(userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get preferences: %w", err)
+	}
+
 	if err := s.uploadToMinIO(filePath, userId); err != nil {
 		return nil, fmt.Errorf("failed to upload to MinIO: %w", err)
 	}
 
-	extractedData, responseString, responseAi, err := s.processReceiptWithGemini(optimizedImageBytes, categoriesOfString, filePath.Filename)
+	resolve := resolverFor(s.promptRegistry, userId, prefs.PromptLocale(ctx))
+	extraction, err := s.processReceiptWithGemini(optimizedImageBytes, categoriesOfString, filePath.Filename, prefs, resolve)
 	if err != nil {
 		return nil, fmt.Errorf("failed to process receipt with AI: %w", err)
 	}
 
-	if err := s.logAIResponse(responseString, responseAi, userId); err != nil {
+	// Unparseable responses are logged too, they count against the prompt version that produced them
+	logMessageId, err := s.logAIResponse(extraction, userId)
+	if err != nil {
 		return nil, fmt.Errorf("failed to log AI response: %w", err)
 	}
+	if extraction.ParseErr != nil {
+		return nil, fmt.Errorf("failed to process receipt with AI: %w", extraction.ParseErr)
+	}
 
-	receipt, err := s.saveReceipt(extractedData, filePath, userId)
+	receipt, err := s.saveReceipt(extraction.Data, filePath, userId, logMessageId)
 	if err != nil {
 		return nil, fmt.Errorf("failed to save receipt to database: %w", err)
 	}
@@ -233,13 +265,18 @@ func (s *receiptService) uploadToMinIO(filePath *multipart.FileHeader, userId st
 	return nil
 }
 
-func (s *receiptService) processReceiptWithGemini(optimizedImageBytes []byte, categoriesOfString, filename string) (*responses.ReceiptExtractionResponse, string, *responses.ResponseAI, error) {
+func (s *receiptService) processReceiptWithGemini(optimizedImageBytes []byte, categoriesOfString, filename string, prefs *responses.UserPreferences, resolve promptResolver) (*receiptExtraction, error) {
 	imageType := "image/png"
 	if strings.HasSuffix(strings.ToLower(filename), ".jpg") || strings.HasSuffix(strings.ToLower(filename), ".jpeg") {
 		imageType = "image/jpeg"
 	}
 
-	messagePrompt := fmt.Sprintf(prompt.ReceiptExtractionUserPromptTemplate, categoriesOfString)
+	systemPrompt := resolve(prompt.ReceiptExtractionSystem)
+	userPrompt := resolve(prompt.ReceiptExtractionUser)
+	preferencesPrompt := resolve(prompt.ReceiptPreferences)
+	messagePrompt := systemPrompt.Content + "\n" +
+		fmt.Sprintf(userPrompt.Content, categoriesOfString) +
+		fmt.Sprintf(preferencesPrompt.Content, prefs.BaseCurrency.Code, prefs.BaseCurrency.Decimals, prefs.Timezone)
 	parts := []*genai.Part{
 		genai.NewPartFromBytes(optimizedImageBytes, imageType),
 		genai.NewPartFromText(messagePrompt),
@@ -249,16 +286,22 @@ func (s *receiptService) processReceiptWithGemini(optimizedImageBytes []byte, ca
 		genai.NewContentFromParts(parts, genai.RoleUser),
 	}
 
-	responseAi, err := s.geminiClient.Run(context.Background(), "gemini-2.5-flash", messages)
+	responseAi, err := s.geminiClient.Run(context.Background(), s.extractionModel, messages)
 	if err != nil {
 		s.logging.LogError(fmt.Sprintf("Failed to process receipt with AI: %v", err))
-		return nil, "", nil, fmt.Errorf("failed to process receipt with AI: %w", err)
+		return nil, fmt.Errorf("failed to process receipt with AI: %w", err)
 	}
 
 	responseString, ok := responseAi.Response.(string)
 	if !ok {
 		s.logging.LogError("Failed to convert AI response to string")
-		return nil, "", nil, fmt.Errorf("failed to convert AI response to string")
+		return nil, fmt.Errorf("failed to convert AI response to string")
+	}
+
+	extraction := &receiptExtraction{
+		Response: responseString,
+		Usage:    responseAi,
+		Prompts:  models.PromptVersions{}.Add(systemPrompt, userPrompt, preferencesPrompt),
 	}
 
 	// Clean the response string to extract JSON content
@@ -270,17 +313,20 @@ func (s *receiptService) processReceiptWithGemini(optimizedImageBytes []byte, ca
 		s.logging.LogError(fmt.Sprintf("Failed to parse JSON response: %v", err))
 		s.logging.LogError(fmt.Sprintf("Raw response: %s", responseString))
 		s.logging.LogError(fmt.Sprintf("Cleaned response: %s", cleanedResponse))
-		return nil, "", nil, fmt.Errorf("failed to parse JSON response: %w", err)
+		extraction.ParseErr = fmt.Errorf("failed to parse JSON response: %w", err)
+		return extraction, nil
 	}
 
-	return &extractedData, responseString, responseAi, nil
+	extraction.Data = &extractedData
+	return extraction, nil
 }
 
-func (s *receiptService) logAIResponse(responseString string, responseAi *responses.ResponseAI, userId string) error {
-	messagePromptJSON, err := json.Marshal(responseString)
+// logAIResponse records the extraction call and returns the id of the log row
+func (s *receiptService) logAIResponse(extraction *receiptExtraction, userId string) (string, error) {
+	messagePromptJSON, err := json.Marshal(extraction.Response)
 	if err != nil {
 		s.logging.LogError(fmt.Sprintf("Failed to marshal message prompt: %v", err))
-		return fmt.Errorf("failed to marshal message prompt: %w", err)
+		return "", fmt.Errorf("failed to marshal message prompt: %w", err)
 	}
 
 	dateNow := time.Now()
@@ -288,25 +334,28 @@ func (s *receiptService) logAIResponse(responseString string, responseAi *respon
 		LogMessageId: ulid.Make().String(),
 		UserId:       userId,
 		Message:      string(messagePromptJSON),
-		Response:     responseString,
-		InputToken:   responseAi.InputToken,
-		OutputToken:  responseAi.OutputToken,
-		Topic:        "receipt_extraction",
-		Model:        "gemini-2.5-flash",
+		Response:     extraction.Response,
+		InputToken:   extraction.Usage.InputToken,
+		OutputToken:  extraction.Usage.OutputToken,
+		Topic:        constants.TopicReceiptExtraction,
+		Model:        s.extractionModel,
 		CreatedAt:    dateNow,
 		UpdatedAt:    dateNow,
+
+		PromptVersions: extraction.Prompts,
+		ParseFailed:    extraction.ParseErr != nil,
 	}
 
 	err = s.logMessageService.InsertLogMessage(logMessage)
 	if err != nil {
 		s.logging.LogError(fmt.Sprintf("Failed to insert log message: %v", err))
-		return fmt.Errorf("failed to insert log message: %w", err)
+		return "", fmt.Errorf("failed to insert log message: %w", err)
 	}
 
-	return nil
+	return logMessage.LogMessageId, nil
 }
 
-func (s *receiptService) saveReceipt(extractedData *responses.ReceiptExtractionResponse, filePath *multipart.FileHeader, userId string) (*models.Receipt, error) {
+func (s *receiptService) saveReceipt(extractedData *responses.ReceiptExtractionResponse, filePath *multipart.FileHeader, userId, logMessageId string) (*models.Receipt, error) {
 	dateNow := time.Now()
 
 	extractedReceiptJSON, err := json.Marshal(extractedData.ExtractedReceipt)
@@ -319,6 +368,9 @@ func (s *receiptService) saveReceipt(extractedData *responses.ReceiptExtractionR
 		OfString: param.NewOpt(string(extractedReceiptJSON)),
 	}
 	embedding := s.openaiClient.CreateEmbedding(context.Background(), input)
+	if embedding != nil {
+		s.logMessageService.LogUsage(userId, constants.TopicReceiptEmbedding, embedding.Model, embedding.InputToken, 0)
+	}
 
 	metaData := models.MetaData{
 		FileName: filePath.Filename,
@@ -344,6 +396,7 @@ func (s *receiptService) saveReceipt(extractedData *responses.ReceiptExtractionR
 		ExtractedReceipt:          extractedReceiptJSON,
 		ExtractedReceiptEmbedding: embedding.Embeddings,
 		Confirmed:                 false,
+		LogMessageId:              logMessageId,
 		TransactionDate:           dateNow,
 		CreatedAt:                 dateNow,
 		UpdatedAt:                 dateNow,
@@ -367,7 +420,24 @@ func (s *receiptService) saveReceipt(extractedData *responses.ReceiptExtractionR
 }
 
 func (s *receiptService) insertReceiptItems(items []responses.ReceiptItemResponse, receiptId, userId string, dateNow time.Time) error {
-	for _, item := range items {
+	// Embed all item names in one request so they can be retrieved by the chat RAG
+	itemNames := make([]string, len(items))
+	for i, item := range items {
+		itemNames[i] = item.ItemName
+	}
+
+	var embeddings []string
+	if len(itemNames) > 0 {
+		batch := s.openaiClient.CreateBatchEmbedding(context.Background(), itemNames)
+		if batch != nil {
+			embeddings = batch.Embeddings
+			s.logMessageService.LogUsage(userId, constants.TopicReceiptItemEmbedding, batch.Model, batch.InputToken, 0)
+		} else {
+			s.logging.LogWarn(fmt.Sprintf("Failed to embed receipt items for receipt %s, storing without embeddings", receiptId))
+		}
+	}
+
+	for i, item := range items {
 		// Create receipt item
 		receiptItem := &models.ReceiptItem{
 			ReceiptItemId:        ulid.Make().String(),
@@ -382,6 +452,9 @@ func (s *receiptService) insertReceiptItems(items []responses.ReceiptItemRespons
 			CategoryId:           item.CategoryId,
 			AiCategoryConfidence: item.AiCategoryConfidence,
 		}
+		if embeddings != nil {
+			receiptItem.ItemNameEmbedding = embeddings[i]
+		}
 
 		err := s.receiptRepository.InsertReceiptItem(receiptItem)
 		if err != nil {
@@ -510,22 +583,18 @@ func (s *receiptService) GetDetailReceiptUserById(userId string, receiptId strin
 		UpdatedAt:       receipt.UpdatedAt,
 		Items:           items,
 		Confirmed:       receipt.Confirmed,
+		Tags:            receipt.Tags,
 	}
 
 	s.logging.LogInfo(fmt.Sprintf("Fetched detail receipt for user %s and receipt ID %s successfully", userId, receiptId))
 	return detailResponse, nil
 }
 
-func (s *receiptService) UpdateReceiptConfirmed(userId, receiptId string, confirmed bool) error {
+func (s *receiptService) UpdateReceiptConfirmed(userId, receiptId string, confirmed, split bool) error {
 	s.logging.LogInfo(fmt.Sprintf("Updating receipt confirmation status for receipt ID %s to %t", receiptId, confirmed))
 
-	err := s.receiptRepository.UpdateReceiptConfirmed(receiptId, confirmed)
-	if err != nil {
-		s.logging.LogError(fmt.Sprintf("Failed to update receipt confirmation status for receipt ID %s: %v", receiptId, err))
-		return fmt.Errorf("failed to update receipt confirmation status: %w", err)
-	}
-
-	// insert transaction if confirmed
+	// Every transaction is built before anything is written so a receipt that cannot be split stays unconfirmed
+	var transactions []*requests.TransactionRequest
 	if confirmed {
 		receipt, err := s.GetDetailReceiptUserById(userId, receiptId)
 		if err != nil {
@@ -533,48 +602,188 @@ func (s *receiptService) UpdateReceiptConfirmed(userId, receiptId string, confir
 			return fmt.Errorf("failed to fetch receipt: %w", err)
 		}
 
-		for _, item := range receipt.Items {
-			// Handle empty category ID
-			if item.CategoryId != nil && *item.CategoryId == "" {
-				s.logging.LogInfo("Category ID is empty, setting it to nil")
-				item.CategoryId = nil
-			}
-
-			// Create transaction
-			categoryId := ""
-			if item.CategoryId != nil {
-				categoryId = *item.CategoryId
-			}
-
-			dateNow := time.Now()
-			newTransaction := &requests.TransactionRequest{
-				UserId:               userId,
-				Amount:               item.ItemPriceTotal,
-				Description:          item.ItemName,
-				CategoryId:           categoryId,
-				Type:                 "expense",
-				Source:               "receipt",
-				TransactionDate:      receipt.TransactionDate,
-				IsAutoCategorized:    true,
-				AiCategoryConfidence: item.AiCategoryConfidence,
-				CreatedAt:            dateNow,
-				UpdatedAt:            dateNow,
-				Confirmed:            false,
-				Discount:             item.ItemDiscount,
-			}
-
-			err := s.transactionService.InsertTransaction(newTransaction)
+		if split {
+			splitTransaction, err := s.buildSplitTransaction(userId, receipt)
 			if err != nil {
-				s.logging.LogError(fmt.Sprintf("Failed to insert transaction: %v", err))
-				return fmt.Errorf("failed to insert transaction: %w", err)
+				return err
 			}
+			transactions = append(transactions, splitTransaction)
+		} else {
+			transactions = s.buildItemTransactions(userId, receipt)
+		}
+	}
+
+	// The transactions are inserted before the receipt is marked confirmed, a failure on either side removes
+	// what was inserted so the confirmation can be retried without duplicates
+	var inserted []string
+	for _, transaction := range transactions {
+		transaction.TransactionId = ulid.Make().String()
+		if err := s.transactionService.InsertTransaction(transaction); err != nil {
+			s.logging.LogError(fmt.Sprintf("Failed to insert transaction: %v", err))
+			s.removeTransactions(userId, inserted)
+			return fmt.Errorf("failed to insert transaction: %w", err)
 		}
+		inserted = append(inserted, transaction.TransactionId)
+	}
+
+	err := s.receiptRepository.UpdateReceiptConfirmed(receiptId, confirmed)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to update receipt confirmation status for receipt ID %s: %v", receiptId, err))
+		s.removeTransactions(userId, inserted)
+		return fmt.Errorf("failed to update receipt confirmation status: %w", err)
 	}
 
 	s.logging.LogInfo(fmt.Sprintf("Receipt confirmation status for receipt ID %s updated successfully", receiptId))
 	return nil
 }
 
+// buildItemTransactions turns every item of the receipt into its own transaction
+func (s *receiptService) buildItemTransactions(userId string, detail *responses.DetailReceiptUserResponse) []*requests.TransactionRequest {
+	transactions := make([]*requests.TransactionRequest, 0, len(detail.Items))
+	for _, item := range detail.Items {
+		// Handle empty category ID
+		categoryId := ""
+		if item.CategoryId != nil {
+			categoryId = *item.CategoryId
+		}
+
+		dateNow := time.Now()
+		transactions = append(transactions, &requests.TransactionRequest{
+			UserId:               userId,
+			Amount:               item.ItemPriceTotal,
+			Description:          item.ItemName,
+			CategoryId:           categoryId,
+			Type:                 "expense",
+			Source:               "receipt",
+			TransactionDate:      detail.TransactionDate,
+			IsAutoCategorized:    true,
+			AiCategoryConfidence: item.AiCategoryConfidence,
+			CreatedAt:            dateNow,
+			UpdatedAt:            dateNow,
+			Confirmed:            false,
+			Discount:             item.ItemDiscount,
+			ReceiptId:            detail.ReceiptId,
+		})
+	}
+
+	return transactions
+}
+
+// removeTransactions deletes the transactions of a confirmation that did not complete
+func (s *receiptService) removeTransactions(userId string, transactionIds []string) {
+	for _, transactionId := range transactionIds {
+		if err := s.transactionService.DeleteTransaction(userId, transactionId); err != nil {
+			s.logging.LogError(fmt.Sprintf("Failed to remove transaction %s of an incomplete receipt confirmation: %v", transactionId, err))
+		}
+	}
+}
+
+// buildSplitTransaction turns the receipt into one transaction with a split line per item category
+func (s *receiptService) buildSplitTransaction(userId string, detail *responses.DetailReceiptUserResponse) (*requests.TransactionRequest, error) {
+	var (
+		order  []string
+		totals = make(map[string]int64)
+		names  = make(map[string][]string)
+		amount int64
+		disc   int64
+	)
+	for _, item := range detail.Items {
+		if item.ItemPriceTotal <= 0 {
+			continue
+		}
+		if item.CategoryId == nil || *item.CategoryId == "" {
+			return nil, fmt.Errorf("%w: item %q has no category", receipt.ErrReceiptNotSplittable, item.ItemName)
+		}
+
+		categoryId := *item.CategoryId
+		if _, ok := totals[categoryId]; !ok {
+			order = append(order, categoryId)
+		}
+		totals[categoryId] += item.ItemPriceTotal
+		names[categoryId] = append(names[categoryId], item.ItemName)
+		amount += item.ItemPriceTotal
+		disc += item.ItemDiscount
+	}
+	if len(order) == 0 {
+		return nil, fmt.Errorf("%w: receipt has no priced items", receipt.ErrReceiptNotSplittable)
+	}
+
+	description := strings.TrimSpace(detail.MerchantName)
+	if description == "" {
+		description = "Receipt"
+	}
+
+	// The transaction carries what was paid, the items only add up to it when the receipt has no tax,
+	// service charge or receipt-level discount
+	total := detail.TotalShopping
+	if total <= 0 {
+		total = amount
+	}
+
+	dateNow := time.Now()
+	req := &requests.TransactionRequest{
+		UserId:            userId,
+		Amount:            total,
+		Description:       description,
+		CategoryId:        order[0],
+		Type:              constants.ExpenseCategory,
+		Source:            "receipt",
+		TransactionDate:   detail.TransactionDate,
+		IsAutoCategorized: true,
+		CreatedAt:         dateNow,
+		UpdatedAt:         dateNow,
+		Confirmed:         false,
+		Discount:          disc,
+		ReceiptId:         detail.ReceiptId,
+	}
+
+	// Items of a single category need no split lines
+	if len(order) == 1 {
+		return req, nil
+	}
+
+	// A receipt-level discount is spread over the categories in proportion to their items
+	parts := make([]int64, len(order))
+	largest := 0
+	for i, categoryId := range order {
+		parts[i] = totals[categoryId]
+		if parts[i] > parts[largest] {
+			largest = i
+		}
+	}
+	if req.Amount < amount {
+		parts = fx.Distribute(parts, req.Amount)
+	}
+
+	req.CategoryId = ""
+	for i, categoryId := range order {
+		if parts[i] <= 0 {
+			return nil, fmt.Errorf("%w: the receipt total %d leaves nothing for category %s", receipt.ErrReceiptNotSplittable, req.Amount, categoryId)
+		}
+
+		note := []rune(strings.Join(names[categoryId], ", "))
+		if len(note) > 255 {
+			note = append(note[:252], []rune("...")...)
+		}
+		req.Splits = append(req.Splits, requests.TransactionSplitRequest{
+			CategoryId: categoryId,
+			Amount:     parts[i],
+			Note:       string(note),
+		})
+	}
+
+	// Taxes and charges the items do not include go on their own line in the largest category
+	if req.Amount > amount {
+		req.Splits = append(req.Splits, requests.TransactionSplitRequest{
+			CategoryId: order[largest],
+			Amount:     req.Amount - amount,
+			Note:       "Receipt total adjustment (tax, service charge, rounding)",
+		})
+	}
+
+	return req, nil
+}
+
 // cleanAIResponse removes markdown formatting and extracts JSON content from AI response
 func (s *receiptService) cleanAIResponse(response string) string {
 	// Remove common markdown code block patterns
@@ -600,3 +809,140 @@ func (s *receiptService) cleanAIResponse(response string) string {
 
 	return jsonContent
 }
+
+func (s *receiptService) SearchSimilarReceipts(userId, embedding string, limit int, threshold float64) ([]models.ReceiptWithScore, error) {
+	receipts, err := s.receiptRepository.SearchReceiptsByEmbedding(userId, embedding, limit, threshold)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to search similar receipts for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to search similar receipts: %w", err)
+	}
+
+	return receipts, nil
+}
+
+func (s *receiptService) SearchSimilarReceiptItems(userId, embedding string, limit int, threshold float64) ([]models.ReceiptItemWithScore, error) {
+	items, err := s.receiptRepository.SearchReceiptItemsByEmbedding(userId, embedding, limit, threshold)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to search similar receipt items for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to search similar receipt items: %w", err)
+	}
+
+	return items, nil
+}
+
+// BackfillItemEmbeddings embeds item names in rounds of ReceiptItemEmbeddingBatch until none is left. Items
+// are embedded with one request per user so the usage is logged against the owner of the receipt.
+func (s *receiptService) BackfillItemEmbeddings(ctx context.Context) (int, error) {
+	embedded := 0
+	for ctx.Err() == nil {
+		items, err := s.receiptRepository.GetUnembeddedReceiptItems(constants.ReceiptItemEmbeddingBatch)
+		if err != nil {
+			s.logging.LogError(fmt.Sprintf("Failed to get receipt items without embedding: %v", err))
+			return embedded, fmt.Errorf("failed to get receipt items without embedding: %w", err)
+		}
+		if len(items) == 0 {
+			break
+		}
+
+		embeddings := make(map[string]string, len(items))
+		var embedErr error
+		for start := 0; start < len(items); {
+			userId := items[start].UserId
+			end := start
+			var names []string
+			for end < len(items) && items[end].UserId == userId {
+				names = append(names, items[end].ItemName)
+				end++
+			}
+
+			batch := s.openaiClient.CreateBatchEmbedding(ctx, names)
+			if batch == nil {
+				embedErr = fmt.Errorf("failed to embed %d receipt items of user %s", len(names), userId)
+				break
+			}
+			s.logMessageService.LogUsage(userId, constants.TopicReceiptItemEmbedding, batch.Model, batch.InputToken, 0)
+			for i, item := range items[start:end] {
+				embeddings[item.ReceiptItemId] = batch.Embeddings[i]
+			}
+			start = end
+		}
+
+		// Embeddings already paid for are kept even when a later request of the round failed
+		if len(embeddings) > 0 {
+			if err := s.receiptRepository.UpdateReceiptItemEmbeddings(embeddings); err != nil {
+				s.logging.LogError(fmt.Sprintf("Failed to save receipt item embeddings: %v", err))
+				return embedded, fmt.Errorf("failed to save receipt item embeddings: %w", err)
+			}
+			embedded += len(embeddings)
+		}
+		if embedErr != nil {
+			s.logging.LogError(embedErr.Error())
+			return embedded, embedErr
+		}
+		if len(items) < constants.ReceiptItemEmbeddingBatch {
+			break
+		}
+	}
+
+	if embedded > 0 {
+		s.logging.LogInfo(fmt.Sprintf("Embedded the names of %d receipt items", embedded))
+	}
+	return embedded, ctx.Err()
+}
+
+// ReceiptExtractor runs the extraction call of UploadReceipt on its own, without storage or logging. It is
+// used by cmd/receipt_eval to score models and prompts offline.
+type ReceiptExtractor struct {
+	service *receiptService
+}
+
+// ReceiptExtractionResult is the outcome of one extraction, Receipt is nil when the response could not be parsed
+type ReceiptExtractionResult struct {
+	Receipt      *responses.ExtractedReceiptResponse
+	Response     string
+	InputTokens  int
+	OutputTokens int
+	ParseErr     error
+}
+
+// NewReceiptExtractor calls the model through geminiClient, an empty model uses the model of UploadReceipt
+func NewReceiptExtractor(geminiClient llm.Gemini, model string, logging logging.Logger) *ReceiptExtractor {
+	if model == "" {
+		model = constants.ReceiptExtractionModel
+	}
+	return &ReceiptExtractor{
+		service: &receiptService{
+			geminiClient:    geminiClient,
+			logging:         logging,
+			extractionModel: model,
+		},
+	}
+}
+
+// Extract reads the receipt image with the built-in prompts of the locale, prompts in overrides replace
+// the built-in ones
+func (e *ReceiptExtractor) Extract(image []byte, filename, categories string, prefs *responses.UserPreferences, locale string, overrides map[prompt.Key]string) (*ReceiptExtractionResult, error) {
+	builtin := builtinPrompts(locale)
+	resolve := func(key prompt.Key) models.ResolvedPrompt {
+		if content, ok := overrides[key]; ok {
+			return models.ResolvedPrompt{Key: string(key), Content: content}
+		}
+		return builtin(key)
+	}
+
+	extraction, err := e.service.processReceiptWithGemini(image, categories, filename, prefs, resolve)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReceiptExtractionResult{
+		Response:     extraction.Response,
+		InputTokens:  extraction.Usage.InputToken,
+		OutputTokens: extraction.Usage.OutputToken,
+		ParseErr:     extraction.ParseErr,
+	}
+	if extraction.Data != nil {
+		result.Receipt = &extraction.Data.ExtractedReceipt
+	}
+	return result, nil
+}
@@ -0,0 +1,320 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"mime/multipart"
+	"net/textproto"
+	"reflect"
+	"testing"
+
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/categories"
+	"github.com/saufiroja/fin-ai/internal/domains/receipt"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/pkg/llm"
+	"github.com/saufiroja/fin-ai/pkg/llm/cassette"
+	logging "github.com/saufiroja/fin-ai/pkg/loggings"
+	"github.com/saufiroja/fin-ai/pkg/minio"
+)
+
+// uploadReceiptCassette was recorded from UploadReceipt: the Gemini extraction of testReceiptImage, the
+// embedding of the extracted receipt and the batch embedding of the item names
+const uploadReceiptCassette = "upload_receipt.json"
+
+type fakeReceiptStorer struct {
+	receipt.ReceiptStorer
+	receipts []*models.Receipt
+	items    []*models.ReceiptItem
+
+	unembedded      []models.UnembeddedReceiptItem
+	unembeddedCalls int
+	embeddings      map[string]string
+}
+
+func (f *fakeReceiptStorer) InsertReceipt(receipt *models.Receipt) error {
+	f.receipts = append(f.receipts, receipt)
+	return nil
+}
+
+func (f *fakeReceiptStorer) InsertReceiptItem(receiptItem *models.ReceiptItem) error {
+	f.items = append(f.items, receiptItem)
+	return nil
+}
+
+func (f *fakeReceiptStorer) GetUnembeddedReceiptItems(limit int) ([]models.UnembeddedReceiptItem, error) {
+	f.unembeddedCalls++
+	var items []models.UnembeddedReceiptItem
+	for _, item := range f.unembedded {
+		if _, ok := f.embeddings[item.ReceiptItemId]; !ok && len(items) < limit {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (f *fakeReceiptStorer) UpdateReceiptItemEmbeddings(embeddings map[string]string) error {
+	if f.embeddings == nil {
+		f.embeddings = make(map[string]string)
+	}
+	for id, embedding := range embeddings {
+		f.embeddings[id] = embedding
+	}
+	return nil
+}
+
+// fakeEmbedder embeds a name as its length, failFor makes the request containing that name fail
+type fakeEmbedder struct {
+	llm.OpenAI
+	failFor  string
+	requests [][]string
+}
+
+func (f *fakeEmbedder) CreateBatchEmbedding(ctx context.Context, inputs []string) *responses.ResponseBatchEmbedding {
+	f.requests = append(f.requests, inputs)
+	embeddings := make([]string, len(inputs))
+	for i, input := range inputs {
+		if input == f.failFor {
+			return nil
+		}
+		embeddings[i] = fmt.Sprintf("[%d]", len(input))
+	}
+	return &responses.ResponseBatchEmbedding{Embeddings: embeddings, InputToken: len(inputs) * 3, Model: llm.EmbeddingModel}
+}
+
+type fakeCategoryManager struct {
+	categories.CategoryManager
+}
+
+func (fakeCategoryManager) FindAllCategories(req *requests.GetAllCategoryQuery) (responses.GetAllCategoryResponse, error) {
+	return responses.GetAllCategoryResponse{
+		Categories: []models.Category{
+			{CategoryId: "01JB2Q4M1Q6T9W3Y7A2C5E8G0J", Name: "Makanan & Minuman", Type: constants.ExpenseCategory},
+			{CategoryId: "01JB2Q4N5R8V2X6Z0B3D7F1H4K", Name: "Kebutuhan Rumah", Type: constants.ExpenseCategory},
+		},
+	}, nil
+}
+
+func (f fakeCategoryManager) FindCategoryById(categoryId string) (*models.Category, error) {
+	all, _ := f.FindAllCategories(nil)
+	for _, category := range all.Categories {
+		if category.CategoryId == categoryId {
+			return &category, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to fetch category by ID %s: %w", categoryId, sql.ErrNoRows)
+}
+
+type fakeMinio struct {
+	minio.MinioManager
+	uploaded []string
+}
+
+func (f *fakeMinio) BucketExists(bucketName string) (bool, error) {
+	return true, nil
+}
+
+func (f *fakeMinio) FileExists(bucketName, objectName string) (bool, error) {
+	return false, nil
+}
+
+func (f *fakeMinio) UploadFileFromMultipart(bucketName, objectName string, fileHeader *multipart.FileHeader) error {
+	f.uploaded = append(f.uploaded, bucketName+"/"+objectName)
+	return nil
+}
+
+// testReceiptImage is a small generated PNG, the cassette keeps only the digest of the optimized image
+func testReceiptImage(t *testing.T) *multipart.FileHeader {
+	t.Helper()
+
+	img := image.NewGray(image.Rect(0, 0, 60, 90))
+	for y := 0; y < 90; y++ {
+		for x := 0; x < 60; x++ {
+			img.SetGray(x, y, color.Gray{Y: uint8(255 - (x*y)%64)})
+		}
+	}
+	data := &bytes.Buffer{}
+	if err := png.Encode(data, img); err != nil {
+		t.Fatal(err)
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="file"; filename="receipt.png"`)
+	header.Set("Content-Type", "image/png")
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := part.Write(data.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	form, err := multipart.NewReader(body, writer.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { form.RemoveAll() })
+	return form.File["file"][0]
+}
+
+func TestUploadReceiptReplaysCassette(t *testing.T) {
+	conf := cassetteConfig(uploadReceiptCassette, cassette.ModeReplay)
+	repository := &fakeReceiptStorer{}
+	usage := &fakeLogMessageManager{}
+	storage := &fakeMinio{}
+	service := NewReceiptService(
+		repository,
+		nil,
+		usage,
+		fakeCategoryManager{},
+		storage,
+		logging.NewLogrusAdapter(),
+		llm.NewOpenAI(conf),
+		llm.NewGemini(conf),
+		fakeUserManager{},
+		nil,
+	)
+
+	userId := "01JB2Q4K7N3P8S2V5X9Z1B4D6F"
+	saved, err := service.UploadReceipt(context.Background(), testReceiptImage(t), userId)
+	if err != nil {
+		t.Fatalf("UploadReceipt() error = %v", err)
+	}
+
+	if len(storage.uploaded) != 1 || storage.uploaded[0] != "receipts/"+userId+"/receipt.png" {
+		t.Errorf("uploaded = %v, want the image in the receipts bucket", storage.uploaded)
+	}
+	if saved.MerchantName != "Indomaret" || saved.SubTotal != 52500 || saved.TotalDiscount != 2500 || saved.TotalShopping != 50000 {
+		t.Errorf("receipt = %s %d/%d/%d, want the recorded extraction", saved.MerchantName, saved.SubTotal, saved.TotalDiscount, saved.TotalShopping)
+	}
+	if saved.ExtractedReceiptEmbedding != "[0.021000,-0.013000,0.044000,0.008000]" {
+		t.Errorf("receipt embedding = %s, want the recorded one", saved.ExtractedReceiptEmbedding)
+	}
+	if len(repository.receipts) != 1 {
+		t.Errorf("inserted %d receipts, want 1", len(repository.receipts))
+	}
+
+	wantItems := []struct {
+		name      string
+		total     int64
+		embedding string
+	}{
+		{"Indomie Goreng", 17500, "[0.011000,0.022000,-0.033000,0.044000]"},
+		{"Sunlight 755ml", 35000, "[-0.051000,0.062000,0.073000,-0.084000]"},
+	}
+	if len(repository.items) != len(wantItems) {
+		t.Fatalf("inserted %d items, want %d", len(repository.items), len(wantItems))
+	}
+	for i, want := range wantItems {
+		item := repository.items[i]
+		if item.ItemName != want.name || item.ItemPriceTotal != want.total || item.ItemNameEmbedding != want.embedding {
+			t.Errorf("item %d = %s %d %s, want %s %d %s", i, item.ItemName, item.ItemPriceTotal, item.ItemNameEmbedding,
+				want.name, want.total, want.embedding)
+		}
+	}
+
+	if len(usage.logged) != 1 {
+		t.Fatalf("logged %d AI responses, want 1", len(usage.logged))
+	}
+	logged := usage.logged[0]
+	if logged.Topic != constants.TopicReceiptExtraction || logged.Model != constants.ReceiptExtractionModel ||
+		logged.InputToken != 1820 || logged.OutputToken != 214 || logged.ParseFailed {
+		t.Errorf("log = %s %s %d/%d parse failed %v, want the recorded extraction", logged.Topic, logged.Model,
+			logged.InputToken, logged.OutputToken, logged.ParseFailed)
+	}
+	if saved.LogMessageId != logged.LogMessageId {
+		t.Errorf("receipt log message = %s, want %s", saved.LogMessageId, logged.LogMessageId)
+	}
+
+	want := map[string]usageCall{
+		constants.TopicReceiptEmbedding:     {constants.TopicReceiptEmbedding, llm.EmbeddingModel, 96, 0},
+		constants.TopicReceiptItemEmbedding: {constants.TopicReceiptItemEmbedding, llm.EmbeddingModel, 8, 0},
+	}
+	if len(usage.usage) != len(want) {
+		t.Fatalf("usage logged %d times, want %d: %+v", len(usage.usage), len(want), usage.usage)
+	}
+	for _, call := range usage.usage {
+		if call != want[call.topic] {
+			t.Errorf("usage = %+v, want %+v", call, want[call.topic])
+		}
+	}
+}
+
+func TestBackfillItemEmbeddings(t *testing.T) {
+	unembedded := []models.UnembeddedReceiptItem{
+		{ReceiptItemId: "item-1", UserId: "user-a", ItemName: "Indomie Goreng"},
+		{ReceiptItemId: "item-2", UserId: "user-a", ItemName: "Teh Botol"},
+		{ReceiptItemId: "item-3", UserId: "user-b", ItemName: "Sunlight 755ml"},
+	}
+
+	tests := []struct {
+		name       string
+		failFor    string
+		embedded   int
+		wantErr    bool
+		embeddings map[string]string
+		requests   [][]string
+		usage      []usageCall
+	}{
+		{
+			name:       "one request per user",
+			embedded:   3,
+			embeddings: map[string]string{"item-1": "[14]", "item-2": "[9]", "item-3": "[14]"},
+			requests:   [][]string{{"Indomie Goreng", "Teh Botol"}, {"Sunlight 755ml"}},
+			usage: []usageCall{
+				{constants.TopicReceiptItemEmbedding, llm.EmbeddingModel, 6, 0},
+				{constants.TopicReceiptItemEmbedding, llm.EmbeddingModel, 3, 0},
+			},
+		},
+		{
+			name:       "keeps the embeddings made before a failed request",
+			failFor:    "Sunlight 755ml",
+			embedded:   2,
+			wantErr:    true,
+			embeddings: map[string]string{"item-1": "[14]", "item-2": "[9]"},
+			requests:   [][]string{{"Indomie Goreng", "Teh Botol"}, {"Sunlight 755ml"}},
+			usage:      []usageCall{{constants.TopicReceiptItemEmbedding, llm.EmbeddingModel, 6, 0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &fakeReceiptStorer{unembedded: unembedded}
+			embedder := &fakeEmbedder{failFor: tt.failFor}
+			usage := &fakeLogMessageManager{}
+			service := NewReceiptService(repository, nil, usage, nil, nil, logging.NewLogrusAdapter(), embedder, nil, nil, nil)
+
+			embedded, err := service.BackfillItemEmbeddings(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BackfillItemEmbeddings() error = %v, want error %v", err, tt.wantErr)
+			}
+			if embedded != tt.embedded {
+				t.Errorf("embedded = %d, want %d", embedded, tt.embedded)
+			}
+			if len(repository.embeddings) != len(tt.embeddings) || (len(tt.embeddings) > 0 && !reflect.DeepEqual(repository.embeddings, tt.embeddings)) {
+				t.Errorf("stored embeddings = %v, want %v", repository.embeddings, tt.embeddings)
+			}
+			if !reflect.DeepEqual(embedder.requests, tt.requests) {
+				t.Errorf("requests = %v, want %v", embedder.requests, tt.requests)
+			}
+			if !reflect.DeepEqual(usage.usage, tt.usage) {
+				t.Errorf("usage = %+v, want %+v", usage.usage, tt.usage)
+			}
+			if repository.unembeddedCalls != 1 {
+				t.Errorf("read unembedded items %d times, want once for a round below the batch size", repository.unembeddedCalls)
+			}
+		})
+	}
+}
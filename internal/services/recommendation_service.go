@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/packages/param"
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/log_message"
+	"github.com/saufiroja/fin-ai/internal/domains/recommendation"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/pkg/llm"
+	logging "github.com/saufiroja/fin-ai/pkg/loggings"
+)
+
+type recommendationService struct {
+	recommendationRepository recommendation.RecommendationStorer
+	logging                  logging.Logger
+	openaiClient             llm.OpenAI
+	logMessageService        log_message.LogMessageManager
+}
+
+func NewRecommendationService(
+	recommendationRepository recommendation.RecommendationStorer,
+	logging logging.Logger,
+	openaiClient llm.OpenAI,
+	logMessageService log_message.LogMessageManager,
+) recommendation.RecommendationManager {
+	return &recommendationService{
+		recommendationRepository: recommendationRepository,
+		logging:                  logging,
+		openaiClient:             openaiClient,
+		logMessageService:        logMessageService,
+	}
+}
+
+func (s *recommendationService) SaveRecommendation(ctx context.Context, rec *models.AIRecommendation) (bool, error) {
+	now := time.Now()
+
+	if rec.ReferenceKey != "" {
+		exists, err := s.recommendationRepository.HasActiveRecommendation(rec.UserId, rec.ReferenceKey, now)
+		if err != nil {
+			s.logging.LogError(fmt.Sprintf("Failed to check recommendation %s for user %s: %v", rec.ReferenceKey, rec.UserId, err))
+			return false, fmt.Errorf("failed to check existing recommendation: %w", err)
+		}
+		if exists {
+			return false, nil
+		}
+	}
+
+	embedding := s.openaiClient.CreateEmbedding(ctx, openai.EmbeddingNewParamsInputUnion{
+		OfString: param.NewOpt(rec.Title + "\n" + rec.Content),
+	})
+	if embedding == nil {
+		s.logging.LogError(fmt.Sprintf("Failed to create embedding for recommendation %q", rec.Title))
+		return false, fmt.Errorf("failed to create recommendation embedding")
+	}
+	s.logMessageService.LogUsage(rec.UserId, constants.TopicRecommendationEmbedding, embedding.Model, embedding.InputToken, 0)
+
+	if rec.RecommendationId == "" {
+		rec.RecommendationId = ulid.Make().String()
+	}
+	rec.ContentEmbedding = embedding.Embeddings
+	rec.CreatedAt = now
+	rec.UpdatedAt = now
+
+	if err := s.recommendationRepository.InsertRecommendation(rec); err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to insert recommendation for user %s: %v", rec.UserId, err))
+		return false, fmt.Errorf("failed to insert recommendation: %w", err)
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Recommendation %s (%s) created for user %s", rec.RecommendationId, rec.RecommendationType, rec.UserId))
+	return true, nil
+}
+
+func (s *recommendationService) GetRecommendations(userId string, req *requests.RecommendationQuery) (*responses.RecommendationsResponse, error) {
+	now := time.Now()
+
+	// Convert page-based offset to row offset, same as the other list endpoints
+	offset := 0
+	if req.Offset > 1 {
+		offset = (req.Offset - 1) * req.Limit
+	}
+
+	queryReq := &requests.RecommendationQuery{
+		Limit:      req.Limit,
+		Offset:     offset,
+		Type:       req.Type,
+		UnreadOnly: req.UnreadOnly,
+	}
+
+	recommendations, err := s.recommendationRepository.GetRecommendations(userId, queryReq, now)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get recommendations for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to get recommendations: %w", err)
+	}
+
+	count, err := s.recommendationRepository.CountRecommendations(userId, queryReq, now)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to count recommendations for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to count recommendations: %w", err)
+	}
+
+	if recommendations == nil {
+		recommendations = []models.AIRecommendation{}
+	}
+
+	totalPages := math.Ceil(float64(count) / float64(req.Limit))
+	currentPage := math.Min(float64(req.Offset), totalPages)
+
+	return &responses.RecommendationsResponse{
+		Recommendations: recommendations,
+		CurrentPage:     int64(currentPage),
+		TotalPages:      int64(totalPages),
+		Total:           count,
+	}, nil
+}
+
+func (s *recommendationService) MarkRecommendationAsRead(userId, recommendationId string) error {
+	affected, err := s.recommendationRepository.MarkRecommendationAsRead(userId, recommendationId)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to mark recommendation %s as read: %v", recommendationId, err))
+		return fmt.Errorf("failed to mark recommendation as read: %w", err)
+	}
+
+	if affected == 0 {
+		return recommendation.ErrRecommendationNotFound
+	}
+
+	return nil
+}
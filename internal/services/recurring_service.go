@@ -0,0 +1,637 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/categories"
+	"github.com/saufiroja/fin-ai/internal/domains/currency"
+	"github.com/saufiroja/fin-ai/internal/domains/recurring"
+	"github.com/saufiroja/fin-ai/internal/domains/transaction"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/internal/utils"
+	logging "github.com/saufiroja/fin-ai/pkg/loggings"
+)
+
+type recurringService struct {
+	recurringRepository recurring.RecurringStorer
+	transactionService  transaction.TransactionManager
+	categoryService     categories.CategoryManager
+	currencyService     currency.CurrencyManager
+	logging             logging.Logger
+}
+
+func NewRecurringService(
+	recurringRepository recurring.RecurringStorer,
+	transactionService transaction.TransactionManager,
+	categoryService categories.CategoryManager,
+	currencyService currency.CurrencyManager,
+	logging logging.Logger,
+) recurring.RecurringManager {
+	return &recurringService{
+		recurringRepository: recurringRepository,
+		transactionService:  transactionService,
+		categoryService:     categoryService,
+		currencyService:     currencyService,
+		logging:             logging,
+	}
+}
+
+func (s *recurringService) CreateRecurringRule(userId string, req *requests.RecurringRuleRequest) (*models.RecurringRule, error) {
+	s.logging.LogInfo(fmt.Sprintf("Creating recurring rule for user %s: %+v", userId, req))
+
+	now := time.Now()
+	rule := &models.RecurringRule{
+		RecurringRuleId: ulid.Make().String(),
+		UserId:          userId,
+		IsActive:        true,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	if err := s.applyRuleRequest(rule, req); err != nil {
+		return nil, err
+	}
+	rule.NextRunDate = s.nextOccurrenceOnOrAfter(rule, s.maxDate(rule.StartDate, s.today(now)))
+	if rule.NextRunDate == nil {
+		rule.IsActive = false
+	}
+
+	if err := s.recurringRepository.InsertRecurringRule(rule); err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to insert recurring rule: %v", err))
+		return nil, fmt.Errorf("failed to insert recurring rule: %w", err)
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Recurring rule %s created successfully", rule.RecurringRuleId))
+	return rule, nil
+}
+
+func (s *recurringService) GetRecurringRules(userId string) ([]*models.RecurringRule, error) {
+	rules, err := s.recurringRepository.GetRecurringRulesByUserId(userId)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get recurring rules for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to get recurring rules: %w", err)
+	}
+
+	if rules == nil {
+		rules = []*models.RecurringRule{}
+	}
+
+	return rules, nil
+}
+
+func (s *recurringService) GetRecurringRuleById(userId, ruleId string) (*models.RecurringRule, error) {
+	rule, err := s.recurringRepository.GetRecurringRuleById(userId, ruleId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, recurring.ErrRecurringRuleNotFound
+		}
+		s.logging.LogError(fmt.Sprintf("Failed to get recurring rule %s: %v", ruleId, err))
+		return nil, fmt.Errorf("failed to get recurring rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+func (s *recurringService) UpdateRecurringRule(userId, ruleId string, req *requests.RecurringRuleRequest) (*models.RecurringRule, error) {
+	s.logging.LogInfo(fmt.Sprintf("Updating recurring rule %s for user %s: %+v", ruleId, userId, req))
+
+	rule, err := s.GetRecurringRuleById(userId, ruleId)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := *rule
+	if err := s.applyRuleRequest(rule, req); err != nil {
+		return nil, err
+	}
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+
+	rule.NextRunDate = s.replanNextRunDate(&previous, rule)
+	if rule.NextRunDate == nil {
+		rule.IsActive = false
+	}
+	rule.UpdatedAt = time.Now()
+
+	if err := s.recurringRepository.UpdateRecurringRule(rule); err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to update recurring rule %s: %v", ruleId, err))
+		return nil, fmt.Errorf("failed to update recurring rule: %w", err)
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Recurring rule %s updated successfully", ruleId))
+	return rule, nil
+}
+
+func (s *recurringService) DeleteRecurringRule(userId, ruleId string) error {
+	if _, err := s.GetRecurringRuleById(userId, ruleId); err != nil {
+		return err
+	}
+
+	if err := s.recurringRepository.DeleteRecurringRule(userId, ruleId); err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to delete recurring rule %s: %v", ruleId, err))
+		return fmt.Errorf("failed to delete recurring rule: %w", err)
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Recurring rule %s deleted successfully", ruleId))
+	return nil
+}
+
+func (s *recurringService) SkipOccurrence(userId, ruleId string, req *requests.RecurringOccurrenceRequest) error {
+	return s.saveOccurrence(userId, ruleId, req, constants.OccurrenceStatusSkipped)
+}
+
+func (s *recurringService) EditOccurrence(userId, ruleId string, req *requests.RecurringOccurrenceRequest) error {
+	return s.saveOccurrence(userId, ruleId, req, constants.OccurrenceStatusOverridden)
+}
+
+func (s *recurringService) saveOccurrence(userId, ruleId string, req *requests.RecurringOccurrenceRequest, status constants.OccurrenceStatus) error {
+	rule, err := s.GetRecurringRuleById(userId, ruleId)
+	if err != nil {
+		return err
+	}
+
+	date, err := time.Parse(utils.DateLayout, req.Date)
+	if err != nil {
+		return fmt.Errorf("%w: invalid date", recurring.ErrInvalidRecurringRule)
+	}
+
+	if len(s.occurrencesBetween(rule, date, date)) == 0 {
+		return recurring.ErrOccurrenceNotScheduled
+	}
+
+	existing, err := s.recurringRepository.GetOccurrences(rule.RecurringRuleId, date, date)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get occurrence of rule %s on %s: %v", ruleId, req.Date, err))
+		return fmt.Errorf("failed to get occurrence: %w", err)
+	}
+	if len(existing) > 0 && (existing[0].Status == constants.OccurrenceStatusMaterialized || existing[0].Status == constants.OccurrenceStatusPending) {
+		return recurring.ErrOccurrenceMaterialized
+	}
+
+	now := time.Now()
+	occurrence := &models.RecurringOccurrence{
+		RecurringOccurrenceId: ulid.Make().String(),
+		RecurringRuleId:       rule.RecurringRuleId,
+		OccurrenceDate:        date,
+		Status:                status,
+		CreatedAt:             now,
+		UpdatedAt:             now,
+	}
+	if status == constants.OccurrenceStatusOverridden {
+		if req.Amount > 0 {
+			occurrence.Amount = &req.Amount
+		}
+		if req.Description != "" {
+			occurrence.Description = &req.Description
+		}
+	}
+
+	if err := s.recurringRepository.UpsertOccurrence(occurrence); err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to save occurrence of rule %s on %s: %v", ruleId, req.Date, err))
+		return fmt.Errorf("failed to save occurrence: %w", err)
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Occurrence of rule %s on %s marked as %s", ruleId, req.Date, status))
+	return nil
+}
+
+func (s *recurringService) GetUpcomingBills(userId string, days int) (*responses.UpcomingBillsResponse, error) {
+	if days <= 0 {
+		days = constants.RecurringUpcomingDays
+	}
+	if days > constants.RecurringMaxUpcomingDays {
+		days = constants.RecurringMaxUpcomingDays
+	}
+
+	start := s.today(time.Now())
+	end := start.AddDate(0, 0, days-1)
+
+	rules, err := s.recurringRepository.GetRecurringRulesByUserId(userId)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get recurring rules for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to get recurring rules: %w", err)
+	}
+
+	base, err := s.currencyService.GetBaseCurrency(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &responses.UpcomingBillsResponse{
+		Currency:  responses.NewCurrencyMeta(base),
+		StartDate: start.Format(utils.DateLayout),
+		EndDate:   end.Format(utils.DateLayout),
+		Bills:     []responses.UpcomingBill{},
+	}
+
+	for _, rule := range rules {
+		if !rule.IsActive {
+			continue
+		}
+
+		dates := s.occurrencesBetween(rule, start, end)
+		if len(dates) == 0 {
+			continue
+		}
+
+		overrides, err := s.occurrencesByDate(rule, start, end)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, date := range dates {
+			bill := responses.UpcomingBill{
+				RecurringRuleId: rule.RecurringRuleId,
+				CategoryId:      rule.CategoryId,
+				Type:            rule.Type,
+				Description:     rule.Description,
+				Amount:          rule.Amount,
+				DueDate:         date,
+			}
+
+			if occurrence, ok := overrides[date.Format(utils.DateLayout)]; ok {
+				if occurrence.Status != constants.OccurrenceStatusOverridden {
+					continue // skipped or already recorded
+				}
+				s.applyOverride(&bill.Amount, &bill.Description, occurrence)
+				bill.Overridden = true
+			}
+
+			if bill.Type == constants.IncomeCategory {
+				res.TotalIncome += bill.Amount
+			} else {
+				res.TotalExpense += bill.Amount
+			}
+			res.Bills = append(res.Bills, bill)
+		}
+	}
+
+	sort.SliceStable(res.Bills, func(i, j int) bool {
+		return res.Bills[i].DueDate.Before(res.Bills[j].DueDate)
+	})
+	return res, nil
+}
+
+// MaterializeDueTransactions records every due occurrence up to today as a transaction, it is safe to run repeatedly
+func (s *recurringService) MaterializeDueTransactions(ctx context.Context, now time.Time) (*responses.MaterializeRecurringResult, error) {
+	today := s.today(now)
+	result := &responses.MaterializeRecurringResult{}
+
+	rules, err := s.recurringRepository.FindDueRecurringRules(today)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to find due recurring rules: %v", err))
+		return nil, fmt.Errorf("failed to find due recurring rules: %w", err)
+	}
+
+	for _, rule := range rules {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+
+		created, skipped, err := s.materializeRule(rule, today)
+		if err != nil {
+			// One broken rule must not block the others, it is retried on the next run
+			s.logging.LogError(fmt.Sprintf("Failed to materialize recurring rule %s: %v", rule.RecurringRuleId, err))
+			continue
+		}
+
+		result.RulesProcessed++
+		result.Created += created
+		result.Skipped += skipped
+	}
+
+	if result.RulesProcessed > 0 {
+		s.logging.LogInfo(fmt.Sprintf("Materialized recurring rules: %+v", result))
+	}
+	return result, nil
+}
+
+func (s *recurringService) materializeRule(rule *models.RecurringRule, today time.Time) (int, int, error) {
+	from := rule.StartDate
+	if rule.NextRunDate != nil {
+		from = *rule.NextRunDate
+	}
+
+	// A long backlog is caught up oldest first over several runs, next_run_date stays at the first
+	// occurrence that was not processed so no date is dropped
+	dates := s.occurrencesBetween(rule, from, today)
+	nextFrom := today.AddDate(0, 0, 1)
+	if len(dates) > constants.RecurringMaxCatchUpPerRule {
+		dates = dates[:constants.RecurringMaxCatchUpPerRule]
+		nextFrom = dates[len(dates)-1].AddDate(0, 0, 1)
+		s.logging.LogInfo(fmt.Sprintf("Recurring rule %s has more than %d due occurrences, the rest is caught up on the next run",
+			rule.RecurringRuleId, constants.RecurringMaxCatchUpPerRule))
+	}
+
+	overrides, err := s.occurrencesByDate(rule, from, today)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	created, skipped := 0, 0
+	for _, date := range dates {
+		amount := rule.Amount
+		description := rule.Description
+
+		occurrence, ok := overrides[date.Format(utils.DateLayout)]
+		if ok {
+			switch occurrence.Status {
+			case constants.OccurrenceStatusSkipped:
+				skipped++
+				continue
+			case constants.OccurrenceStatusMaterialized, constants.OccurrenceStatusPending:
+				continue
+			case constants.OccurrenceStatusOverridden:
+				s.applyOverride(&amount, &description, occurrence)
+			}
+		}
+
+		inserted, err := s.materializeOccurrence(rule, date, amount, description, occurrence)
+		if err != nil {
+			return created, skipped, err
+		}
+		if inserted {
+			created++
+		}
+	}
+
+	nextRunDate := s.nextOccurrenceOnOrAfter(rule, nextFrom)
+	if err := s.recurringRepository.UpdateNextRunDate(rule.RecurringRuleId, nextRunDate, nextRunDate != nil); err != nil {
+		return created, skipped, fmt.Errorf("failed to update next run date: %w", err)
+	}
+
+	return created, skipped, nil
+}
+
+// materializeOccurrence claims the occurrence before inserting its transaction so it is recorded at most once,
+// even when two runs overlap or the final status update fails. override is the overridden occurrence, if any.
+func (s *recurringService) materializeOccurrence(rule *models.RecurringRule, date time.Time, amount int64, description string, override *models.RecurringOccurrence) (bool, error) {
+	now := time.Now()
+	claimed, err := s.recurringRepository.ClaimOccurrence(&models.RecurringOccurrence{
+		RecurringOccurrenceId: ulid.Make().String(),
+		RecurringRuleId:       rule.RecurringRuleId,
+		OccurrenceDate:        date,
+		Amount:                &amount,
+		Description:           &description,
+		CreatedAt:             now,
+		UpdatedAt:             now,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to claim occurrence for %s: %w", date.Format(utils.DateLayout), err)
+	}
+	if !claimed {
+		return false, nil // another run already handled it
+	}
+
+	transactionId := ulid.Make().String()
+	err = s.transactionService.InsertTransaction(&requests.TransactionRequest{
+		TransactionId:   transactionId,
+		UserId:          rule.UserId,
+		CategoryId:      rule.CategoryId,
+		Type:            rule.Type,
+		Description:     description,
+		Amount:          amount,
+		Source:          constants.RecurringSource,
+		TransactionDate: date,
+		Confirmed:       true,
+		PaymentMethod:   rule.PaymentMethod,
+	})
+	if err != nil {
+		s.releaseOccurrence(rule, date, override)
+		return false, fmt.Errorf("failed to insert transaction for %s: %w", date.Format(utils.DateLayout), err)
+	}
+
+	now = time.Now()
+	err = s.recurringRepository.UpsertOccurrence(&models.RecurringOccurrence{
+		RecurringOccurrenceId: ulid.Make().String(),
+		RecurringRuleId:       rule.RecurringRuleId,
+		OccurrenceDate:        date,
+		Status:                constants.OccurrenceStatusMaterialized,
+		Amount:                &amount,
+		Description:           &description,
+		TransactionId:         &transactionId,
+		CreatedAt:             now,
+		UpdatedAt:             now,
+	})
+	if err != nil {
+		// The occurrence stays pending so it is not inserted again
+		return true, fmt.Errorf("failed to record transaction %s for occurrence %s: %w", transactionId, date.Format(utils.DateLayout), err)
+	}
+
+	return true, nil
+}
+
+// releaseOccurrence gives back a claim whose transaction was not inserted so the next run retries it
+func (s *recurringService) releaseOccurrence(rule *models.RecurringRule, date time.Time, override *models.RecurringOccurrence) {
+	var err error
+	if override != nil {
+		err = s.recurringRepository.UpsertOccurrence(override)
+	} else {
+		err = s.recurringRepository.DeletePendingOccurrence(rule.RecurringRuleId, date)
+	}
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to release occurrence of rule %s on %s: %v", rule.RecurringRuleId, date.Format(utils.DateLayout), err))
+	}
+}
+
+// replanNextRunDate keeps the next run date unless the schedule changed, a changed schedule is planned from
+// its start date. Due occurrences that were not recorded yet are kept so the next run still catches them up,
+// and dates before the previous next run date were already handled under the old schedule.
+func (s *recurringService) replanNextRunDate(previous, rule *models.RecurringRule) *time.Time {
+	var handledUntil *time.Time
+	switch {
+	case previous.NextRunDate != nil:
+		handledUntil = previous.NextRunDate
+	case previous.EndDate != nil:
+		// The rule had finished, every occurrence up to its end date was handled
+		afterEnd := previous.EndDate.AddDate(0, 0, 1)
+		handledUntil = &afterEnd
+	}
+
+	if !s.scheduleChanged(previous, rule) && previous.NextRunDate != nil {
+		// Still checked against the end date, which may have moved before the next run
+		return s.nextOccurrenceOnOrAfter(rule, *previous.NextRunDate)
+	}
+
+	from := rule.StartDate
+	if handledUntil != nil {
+		from = s.maxDate(from, *handledUntil)
+	}
+	return s.nextOccurrenceOnOrAfter(rule, from)
+}
+
+func (s *recurringService) scheduleChanged(previous, rule *models.RecurringRule) bool {
+	if !previous.StartDate.Equal(rule.StartDate) || previous.Frequency != rule.Frequency {
+		return true
+	}
+	if (previous.DayOfMonth == nil) != (rule.DayOfMonth == nil) {
+		return true
+	}
+	return previous.DayOfMonth != nil && *previous.DayOfMonth != *rule.DayOfMonth
+}
+
+func (s *recurringService) applyRuleRequest(rule *models.RecurringRule, req *requests.RecurringRuleRequest) error {
+	if _, err := s.categoryService.FindCategoryById(req.CategoryId); err != nil {
+		return fmt.Errorf("%w: category not found", recurring.ErrInvalidRecurringRule)
+	}
+
+	startDate, err := time.Parse(utils.DateLayout, req.StartDate)
+	if err != nil {
+		return fmt.Errorf("%w: invalid start date", recurring.ErrInvalidRecurringRule)
+	}
+
+	var endDate *time.Time
+	if req.EndDate != "" {
+		parsed, err := time.Parse(utils.DateLayout, req.EndDate)
+		if err != nil {
+			return fmt.Errorf("%w: invalid end date", recurring.ErrInvalidRecurringRule)
+		}
+		if parsed.Before(startDate) {
+			return fmt.Errorf("%w: end date is before start date", recurring.ErrInvalidRecurringRule)
+		}
+		endDate = &parsed
+	}
+
+	// day_of_month only makes sense for monthly rules
+	dayOfMonth := req.DayOfMonth
+	if req.Frequency != constants.PeriodTypeMonthly {
+		dayOfMonth = nil
+	}
+
+	rule.CategoryId = req.CategoryId
+	rule.Type = req.Type
+	rule.Description = req.Description
+	rule.Amount = req.Amount
+	rule.PaymentMethod = req.PaymentMethod
+	rule.Frequency = req.Frequency
+	rule.DayOfMonth = dayOfMonth
+	rule.StartDate = startDate
+	rule.EndDate = endDate
+
+	return nil
+}
+
+func (s *recurringService) applyOverride(amount *int64, description *string, occurrence *models.RecurringOccurrence) {
+	if occurrence.Amount != nil {
+		*amount = *occurrence.Amount
+	}
+	if occurrence.Description != nil {
+		*description = *occurrence.Description
+	}
+}
+
+func (s *recurringService) occurrencesByDate(rule *models.RecurringRule, start, end time.Time) (map[string]*models.RecurringOccurrence, error) {
+	occurrences, err := s.recurringRepository.GetOccurrences(rule.RecurringRuleId, start, end)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get occurrences of rule %s: %v", rule.RecurringRuleId, err))
+		return nil, fmt.Errorf("failed to get occurrences: %w", err)
+	}
+
+	byDate := make(map[string]*models.RecurringOccurrence, len(occurrences))
+	for _, occurrence := range occurrences {
+		byDate[occurrence.OccurrenceDate.Format(utils.DateLayout)] = occurrence
+	}
+
+	return byDate, nil
+}
+
+// occurrenceAt returns the k-th scheduled date counted from the start date.
+// Monthly and yearly dates are clamped to the last day of shorter months.
+func (s *recurringService) occurrenceAt(rule *models.RecurringRule, k int) time.Time {
+	start := rule.StartDate
+	switch rule.Frequency {
+	case constants.PeriodTypeDaily:
+		return start.AddDate(0, 0, k)
+	case constants.PeriodTypeWeekly:
+		return start.AddDate(0, 0, 7*k)
+	case constants.PeriodTypeYearly:
+		return s.clampDay(start.Year()+k, start.Month(), start.Day())
+	default:
+		day := start.Day()
+		if rule.DayOfMonth != nil {
+			day = *rule.DayOfMonth
+		}
+		return s.clampDay(start.Year(), start.Month()+time.Month(k), day)
+	}
+}
+
+// occurrencesBetween lists scheduled dates within the inclusive range, honoring start and end dates
+func (s *recurringService) occurrencesBetween(rule *models.RecurringRule, from, to time.Time) []time.Time {
+	var dates []time.Time
+	if to.Before(from) {
+		return dates
+	}
+
+	// Jump close to `from` instead of walking every occurrence since the start date
+	k := 0
+	if from.After(rule.StartDate) {
+		days := int(from.Sub(rule.StartDate).Hours() / 24)
+		switch rule.Frequency {
+		case constants.PeriodTypeDaily:
+			k = days
+		case constants.PeriodTypeWeekly:
+			k = days / 7
+		case constants.PeriodTypeYearly:
+			k = from.Year() - rule.StartDate.Year() - 1
+		default:
+			k = (from.Year()-rule.StartDate.Year())*12 + int(from.Month()) - int(rule.StartDate.Month()) - 1
+		}
+		if k < 0 {
+			k = 0
+		}
+	}
+
+	for ; ; k++ {
+		date := s.occurrenceAt(rule, k)
+		if date.After(to) || (rule.EndDate != nil && date.After(*rule.EndDate)) {
+			break
+		}
+		if date.Before(from) || date.Before(rule.StartDate) {
+			continue
+		}
+		dates = append(dates, date)
+	}
+
+	return dates
+}
+
+// nextOccurrenceOnOrAfter returns nil once the rule has no occurrences left
+func (s *recurringService) nextOccurrenceOnOrAfter(rule *models.RecurringRule, date time.Time) *time.Time {
+	// A year always contains at least one occurrence of any supported frequency
+	dates := s.occurrencesBetween(rule, date, date.AddDate(1, 0, 0))
+	if len(dates) == 0 {
+		return nil
+	}
+	return &dates[0]
+}
+
+func (s *recurringService) clampDay(year int, month time.Month, day int) time.Time {
+	// time.Date normalizes month overflow (e.g. month 13), then the day is capped to that month's length
+	firstOfMonth := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(firstOfMonth.Year(), firstOfMonth.Month(), day, 0, 0, 0, 0, time.UTC)
+}
+
+func (s *recurringService) today(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func (s *recurringService) maxDate(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
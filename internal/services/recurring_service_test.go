@@ -0,0 +1,385 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/domains/recurring"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/internal/utils"
+	logging "github.com/saufiroja/fin-ai/pkg/loggings"
+)
+
+// fakeRecurringStorer keeps occurrences by date and claims them like the unique (rule, date) insert does
+type fakeRecurringStorer struct {
+	recurring.RecurringStorer
+	rule        *models.RecurringRule
+	occurrences map[string]*models.RecurringOccurrence
+	// hidden leaves occurrences out of GetOccurrences, like a claim made by a run that overlaps this one
+	hidden      bool
+	nextRunDate *time.Time
+	updated     *models.RecurringRule
+}
+
+func (f *fakeRecurringStorer) GetRecurringRuleById(userId, ruleId string) (*models.RecurringRule, error) {
+	rule := *f.rule
+	return &rule, nil
+}
+
+func (f *fakeRecurringStorer) UpdateRecurringRule(rule *models.RecurringRule) error {
+	f.updated = rule
+	return nil
+}
+
+func (f *fakeRecurringStorer) GetOccurrences(ruleId string, start, end time.Time) ([]*models.RecurringOccurrence, error) {
+	if f.hidden {
+		return nil, nil
+	}
+	var occurrences []*models.RecurringOccurrence
+	for _, occurrence := range f.occurrences {
+		if !occurrence.OccurrenceDate.Before(start) && !occurrence.OccurrenceDate.After(end) {
+			occurrences = append(occurrences, occurrence)
+		}
+	}
+	return occurrences, nil
+}
+
+func (f *fakeRecurringStorer) ClaimOccurrence(occurrence *models.RecurringOccurrence) (bool, error) {
+	key := occurrence.OccurrenceDate.Format(utils.DateLayout)
+	if existing, ok := f.occurrences[key]; ok && existing.Status != constants.OccurrenceStatusOverridden {
+		return false, nil
+	}
+	claimed := *occurrence
+	claimed.Status = constants.OccurrenceStatusPending
+	f.occurrences[key] = &claimed
+	return true, nil
+}
+
+func (f *fakeRecurringStorer) UpsertOccurrence(occurrence *models.RecurringOccurrence) error {
+	f.occurrences[occurrence.OccurrenceDate.Format(utils.DateLayout)] = occurrence
+	return nil
+}
+
+func (f *fakeRecurringStorer) DeletePendingOccurrence(ruleId string, date time.Time) error {
+	key := date.Format(utils.DateLayout)
+	if existing, ok := f.occurrences[key]; ok && existing.Status == constants.OccurrenceStatusPending {
+		delete(f.occurrences, key)
+	}
+	return nil
+}
+
+func (f *fakeRecurringStorer) UpdateNextRunDate(ruleId string, nextRunDate *time.Time, isActive bool) error {
+	f.nextRunDate = nextRunDate
+	return nil
+}
+
+func recurringDate(value string) time.Time {
+	date, err := time.Parse(utils.DateLayout, value)
+	if err != nil {
+		panic(err)
+	}
+	return date
+}
+
+func recurringDatePtr(value string) *time.Time {
+	date := recurringDate(value)
+	return &date
+}
+
+func recurringDates(values ...string) []time.Time {
+	dates := make([]time.Time, len(values))
+	for i, value := range values {
+		dates[i] = recurringDate(value)
+	}
+	return dates
+}
+
+func recurringRule(frequency constants.PeriodType, start string, end *time.Time) *models.RecurringRule {
+	return &models.RecurringRule{
+		RecurringRuleId: "01JB2R5P6Q7R8S9T0V1W2X3Y4Z",
+		UserId:          "01JB2Q4K7N3P8S2V5X9Z1B4D6F",
+		CategoryId:      "01JB2Q4M1Q6T9W3Y7A2C5E8G0J",
+		Type:            constants.ExpenseCategory,
+		Description:     "Internet",
+		Amount:          350000,
+		Frequency:       frequency,
+		StartDate:       recurringDate(start),
+		EndDate:         end,
+		NextRunDate:     recurringDatePtr(start),
+		IsActive:        true,
+	}
+}
+
+func newTestRecurringService(repository *fakeRecurringStorer, transactions *fakeTransactionManager) *recurringService {
+	if repository.occurrences == nil {
+		repository.occurrences = make(map[string]*models.RecurringOccurrence)
+	}
+	return NewRecurringService(repository, transactions, fakeCategoryManager{}, nil, logging.NewLogrusAdapter()).(*recurringService)
+}
+
+func TestMaterializeRuleCatchUp(t *testing.T) {
+	overridden := int64(99000)
+
+	tests := []struct {
+		name        string
+		rule        *models.RecurringRule
+		occurrences []*models.RecurringOccurrence
+		today       string
+		wantDates   []time.Time
+		wantAmounts map[string]int64
+		wantSkipped int
+		wantNextRun *time.Time
+	}{
+		{
+			name:        "monthly backlog clamped to short months",
+			rule:        recurringRule(constants.PeriodTypeMonthly, "2024-01-31", nil),
+			today:       "2024-05-15",
+			wantDates:   recurringDates("2024-01-31", "2024-02-29", "2024-03-31", "2024-04-30"),
+			wantNextRun: recurringDatePtr("2024-05-31"),
+		},
+		{
+			name: "skipped and overridden occurrences",
+			rule: recurringRule(constants.PeriodTypeWeekly, "2024-04-01", nil),
+			occurrences: []*models.RecurringOccurrence{
+				{OccurrenceDate: recurringDate("2024-04-08"), Status: constants.OccurrenceStatusSkipped},
+				{OccurrenceDate: recurringDate("2024-04-15"), Status: constants.OccurrenceStatusOverridden, Amount: &overridden},
+			},
+			today:       "2024-04-22",
+			wantDates:   recurringDates("2024-04-01", "2024-04-15", "2024-04-22"),
+			wantAmounts: map[string]int64{"2024-04-15": overridden},
+			wantSkipped: 1,
+			wantNextRun: recurringDatePtr("2024-04-29"),
+		},
+		{
+			name:        "rule that ended during the backlog",
+			rule:        recurringRule(constants.PeriodTypeMonthly, "2024-01-10", recurringDatePtr("2024-03-10")),
+			today:       "2024-06-01",
+			wantDates:   recurringDates("2024-01-10", "2024-02-10", "2024-03-10"),
+			wantNextRun: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &fakeRecurringStorer{occurrences: make(map[string]*models.RecurringOccurrence)}
+			for _, occurrence := range tt.occurrences {
+				repository.occurrences[occurrence.OccurrenceDate.Format(utils.DateLayout)] = occurrence
+			}
+			transactions := &fakeTransactionManager{}
+			s := newTestRecurringService(repository, transactions)
+
+			created, skipped, err := s.materializeRule(tt.rule, recurringDate(tt.today))
+			if err != nil {
+				t.Fatalf("materializeRule() error = %v", err)
+			}
+			if created != len(tt.wantDates) || skipped != tt.wantSkipped {
+				t.Errorf("created %d and skipped %d, want %d and %d", created, skipped, len(tt.wantDates), tt.wantSkipped)
+			}
+
+			var dates []time.Time
+			for _, req := range transactions.inserted {
+				dates = append(dates, req.TransactionDate)
+				want := tt.rule.Amount
+				if amount, ok := tt.wantAmounts[req.TransactionDate.Format(utils.DateLayout)]; ok {
+					want = amount
+				}
+				if req.Amount != want || req.Source != constants.RecurringSource {
+					t.Errorf("transaction on %s = %d from %s, want %d from recurring", req.TransactionDate.Format(utils.DateLayout), req.Amount, req.Source, want)
+				}
+			}
+			if !reflect.DeepEqual(dates, tt.wantDates) {
+				t.Errorf("transaction dates = %v, want %v", dates, tt.wantDates)
+			}
+			if !reflect.DeepEqual(repository.nextRunDate, tt.wantNextRun) {
+				t.Errorf("next run date = %v, want %v", repository.nextRunDate, tt.wantNextRun)
+			}
+			for _, date := range tt.wantDates {
+				if occurrence := repository.occurrences[date.Format(utils.DateLayout)]; occurrence.Status != constants.OccurrenceStatusMaterialized {
+					t.Errorf("occurrence on %s is %s, want materialized", date.Format(utils.DateLayout), occurrence.Status)
+				}
+			}
+		})
+	}
+}
+
+func TestMaterializeRuleCatchUpLimit(t *testing.T) {
+	repository := &fakeRecurringStorer{}
+	transactions := &fakeTransactionManager{}
+	s := newTestRecurringService(repository, transactions)
+	rule := recurringRule(constants.PeriodTypeDaily, "2023-01-01", nil)
+
+	created, _, err := s.materializeRule(rule, recurringDate("2024-12-31"))
+	if err != nil {
+		t.Fatalf("materializeRule() error = %v", err)
+	}
+	if created != constants.RecurringMaxCatchUpPerRule {
+		t.Errorf("created %d, want %d", created, constants.RecurringMaxCatchUpPerRule)
+	}
+
+	// The next run resumes right after the last recorded date instead of jumping to today
+	want := recurringDate("2023-01-01").AddDate(0, 0, constants.RecurringMaxCatchUpPerRule)
+	if repository.nextRunDate == nil || !repository.nextRunDate.Equal(want) {
+		t.Errorf("next run date = %v, want %s", repository.nextRunDate, want.Format(utils.DateLayout))
+	}
+}
+
+func TestClaimOccurrenceIdempotence(t *testing.T) {
+	today := recurringDate("2024-04-20")
+
+	t.Run("repeated run", func(t *testing.T) {
+		repository := &fakeRecurringStorer{}
+		transactions := &fakeTransactionManager{}
+		s := newTestRecurringService(repository, transactions)
+		rule := recurringRule(constants.PeriodTypeMonthly, "2024-01-05", nil)
+
+		for run := 1; run <= 2; run++ {
+			// The rule still carries the old next run date, like a run that started before the first one finished
+			created, _, err := s.materializeRule(rule, today)
+			if err != nil {
+				t.Fatalf("run %d: materializeRule() error = %v", run, err)
+			}
+			if want := map[int]int{1: 4, 2: 0}[run]; created != want {
+				t.Errorf("run %d created %d, want %d", run, created, want)
+			}
+		}
+		if len(transactions.inserted) != 4 {
+			t.Errorf("inserted %d transactions, want 4", len(transactions.inserted))
+		}
+	})
+
+	t.Run("claimed by an overlapping run", func(t *testing.T) {
+		repository := &fakeRecurringStorer{hidden: true}
+		transactions := &fakeTransactionManager{}
+		s := newTestRecurringService(repository, transactions)
+		rule := recurringRule(constants.PeriodTypeMonthly, "2024-04-05", nil)
+		repository.occurrences["2024-04-05"] = &models.RecurringOccurrence{OccurrenceDate: recurringDate("2024-04-05"), Status: constants.OccurrenceStatusPending}
+
+		created, _, err := s.materializeRule(rule, today)
+		if err != nil {
+			t.Fatalf("materializeRule() error = %v", err)
+		}
+		if created != 0 || len(transactions.inserted) != 0 {
+			t.Errorf("created %d with %d transactions, want none", created, len(transactions.inserted))
+		}
+	})
+
+	t.Run("failed insert is released and retried", func(t *testing.T) {
+		repository := &fakeRecurringStorer{}
+		transactions := &fakeTransactionManager{insertErr: errors.New("connection reset")}
+		s := newTestRecurringService(repository, transactions)
+		rule := recurringRule(constants.PeriodTypeMonthly, "2024-04-05", nil)
+
+		if _, _, err := s.materializeRule(rule, today); err == nil {
+			t.Fatal("materializeRule() error = nil, want the insert error")
+		}
+		if len(repository.occurrences) != 0 {
+			t.Fatalf("occurrences = %v, want the claim released", repository.occurrences)
+		}
+
+		transactions.insertErr = nil
+		created, _, err := s.materializeRule(rule, today)
+		if err != nil {
+			t.Fatalf("retry: materializeRule() error = %v", err)
+		}
+		if created != 1 || len(transactions.inserted) != 1 {
+			t.Errorf("retry created %d with %d transactions, want 1", created, len(transactions.inserted))
+		}
+	})
+}
+
+func TestUpdateRecurringRuleNextRunDate(t *testing.T) {
+	active := true
+	dayOfMonth := 20
+
+	tests := []struct {
+		name        string
+		stored      *models.RecurringRule
+		req         requests.RecurringRuleRequest
+		wantNextRun *time.Time
+		wantActive  bool
+	}{
+		{
+			name:        "amount change keeps the next run date",
+			req:         requests.RecurringRuleRequest{Frequency: constants.PeriodTypeMonthly, StartDate: "2024-01-05", Amount: 400000},
+			wantNextRun: recurringDatePtr("2024-02-05"),
+			wantActive:  true,
+		},
+		{
+			name:       "end date before the next run",
+			req:        requests.RecurringRuleRequest{Frequency: constants.PeriodTypeMonthly, StartDate: "2024-01-05", EndDate: "2024-01-31", Amount: 350000},
+			wantActive: false,
+		},
+		{
+			name:        "day of month change",
+			req:         requests.RecurringRuleRequest{Frequency: constants.PeriodTypeMonthly, StartDate: "2024-01-05", DayOfMonth: &dayOfMonth, Amount: 350000},
+			wantNextRun: recurringDatePtr("2024-02-20"),
+			wantActive:  true,
+		},
+		{
+			name:        "frequency change",
+			req:         requests.RecurringRuleRequest{Frequency: constants.PeriodTypeWeekly, StartDate: "2024-01-05", Amount: 350000},
+			wantNextRun: recurringDatePtr("2024-02-09"),
+			wantActive:  true,
+		},
+		{
+			name:        "start date moved back does not repeat handled dates",
+			req:         requests.RecurringRuleRequest{Frequency: constants.PeriodTypeMonthly, StartDate: "2023-06-01", Amount: 350000},
+			wantNextRun: recurringDatePtr("2024-03-01"),
+			wantActive:  true,
+		},
+		{
+			name:        "start date moved forward",
+			req:         requests.RecurringRuleRequest{Frequency: constants.PeriodTypeMonthly, StartDate: "2024-06-15", Amount: 350000},
+			wantNextRun: recurringDatePtr("2024-06-15"),
+			wantActive:  true,
+		},
+		{
+			name: "finished rule extended",
+			stored: func() *models.RecurringRule {
+				rule := recurringRule(constants.PeriodTypeMonthly, "2024-01-05", recurringDatePtr("2024-03-05"))
+				rule.NextRunDate = nil
+				rule.IsActive = false
+				return rule
+			}(),
+			req:         requests.RecurringRuleRequest{Frequency: constants.PeriodTypeMonthly, StartDate: "2024-01-05", Amount: 350000, IsActive: &active},
+			wantNextRun: recurringDatePtr("2024-04-05"),
+			wantActive:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := tt.stored
+			if stored == nil {
+				// Due on 2024-02-05 and not recorded yet
+				stored = recurringRule(constants.PeriodTypeMonthly, "2024-01-05", nil)
+				stored.NextRunDate = recurringDatePtr("2024-02-05")
+			}
+			req := tt.req
+			req.CategoryId = stored.CategoryId
+			req.Type = stored.Type
+			req.Description = stored.Description
+
+			repository := &fakeRecurringStorer{rule: stored}
+			s := newTestRecurringService(repository, &fakeTransactionManager{})
+
+			rule, err := s.UpdateRecurringRule(stored.UserId, stored.RecurringRuleId, &req)
+			if err != nil {
+				t.Fatalf("UpdateRecurringRule() error = %v", err)
+			}
+			if !reflect.DeepEqual(rule.NextRunDate, tt.wantNextRun) {
+				t.Errorf("next run date = %v, want %v", rule.NextRunDate, tt.wantNextRun)
+			}
+			if rule.IsActive != tt.wantActive {
+				t.Errorf("active = %v, want %v", rule.IsActive, tt.wantActive)
+			}
+			if repository.updated != rule {
+				t.Error("updated rule was not saved")
+			}
+		})
+	}
+}
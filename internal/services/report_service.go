@@ -0,0 +1,433 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/packages/param"
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/constants/prompt"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/analytics"
+	"github.com/saufiroja/fin-ai/internal/domains/log_message"
+	"github.com/saufiroja/fin-ai/internal/domains/prompt_registry"
+	"github.com/saufiroja/fin-ai/internal/domains/report"
+	"github.com/saufiroja/fin-ai/internal/domains/user"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/internal/utils"
+	"github.com/saufiroja/fin-ai/pkg/fx"
+	"github.com/saufiroja/fin-ai/pkg/llm"
+	logging "github.com/saufiroja/fin-ai/pkg/loggings"
+	"github.com/saufiroja/fin-ai/pkg/minio"
+	pdfreport "github.com/saufiroja/fin-ai/pkg/report"
+)
+
+type reportService struct {
+	reportRepository  report.ReportStorer
+	analyticsService  analytics.AnalyticsManager
+	userService       user.UserManager
+	minioClient       minio.MinioManager
+	openaiClient      llm.OpenAI
+	logMessageService log_message.LogMessageManager
+	promptRegistry    prompt_registry.PromptRegistryManager
+	logging           logging.Logger
+}
+
+func NewReportService(
+	reportRepository report.ReportStorer,
+	analyticsService analytics.AnalyticsManager,
+	userService user.UserManager,
+	minioClient minio.MinioManager,
+	openaiClient llm.OpenAI,
+	logMessageService log_message.LogMessageManager,
+	promptRegistry prompt_registry.PromptRegistryManager,
+	logging logging.Logger,
+) report.ReportManager {
+	return &reportService{
+		reportRepository:  reportRepository,
+		analyticsService:  analyticsService,
+		userService:       userService,
+		minioClient:       minioClient,
+		openaiClient:      openaiClient,
+		logMessageService: logMessageService,
+		promptRegistry:    promptRegistry,
+		logging:           logging,
+	}
+}
+
+func (s *reportService) GenerateMonthlyReport(ctx context.Context, userId string, req *requests.GenerateReportRequest) (*responses.MonthlyReportResponse, error) {
+	s.logging.LogInfo(fmt.Sprintf("Generating monthly report %d-%02d for user %s", req.Year, req.Month, userId))
+
+	now := time.Now()
+	periodStart := time.Date(req.Year, time.Month(req.Month), 1, 0, 0, 0, 0, time.UTC)
+	if periodStart.After(now) {
+		return nil, report.ErrInvalidReportPeriod
+	}
+	periodEnd := periodStart.AddDate(0, 1, -1)
+
+	owner, err := s.userService.GetMe(userId)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get user %s for report: %v", userId, err))
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	prefs, err := s.userService.GetPreferences(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.collectReportData(userId, periodStart, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+	data.Owner = owner.FullName
+	data.GeneratedAt = now
+	data.Locale = prefs.Locale
+	data.Narrative = s.periodNarrative(ctx, userId, data, prefs, periodEnd, req.RefreshSummary)
+
+	var buf bytes.Buffer
+	if err := pdfreport.RenderMonthly(data, &buf); err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to render report %d-%02d for user %s: %v", req.Year, req.Month, userId, err))
+		return nil, fmt.Errorf("failed to render report: %w", err)
+	}
+
+	if err := s.minioClient.CreateBucket(constants.ReportBucket); err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to create bucket %s: %v", constants.ReportBucket, err))
+		return nil, fmt.Errorf("failed to create report bucket: %w", err)
+	}
+	objectName := fmt.Sprintf("%s/monthly-%d-%02d.pdf", userId, req.Year, req.Month)
+	if err := s.minioClient.UploadBytes(constants.ReportBucket, objectName, buf.Bytes(), constants.ReportContentType); err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to upload report %s: %v", objectName, err))
+		return nil, fmt.Errorf("failed to upload report: %w", err)
+	}
+
+	rep := &models.MonthlyReport{
+		ReportId:     ulid.Make().String(),
+		UserId:       userId,
+		Year:         req.Year,
+		Month:        req.Month,
+		ObjectName:   objectName,
+		FileSize:     int64(buf.Len()),
+		TotalIncome:  data.TotalIncome,
+		TotalExpense: data.TotalExpense,
+		GeneratedAt:  now,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := s.reportRepository.UpsertReport(rep); err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to save report %d-%02d for user %s: %v", req.Year, req.Month, userId, err))
+		return nil, fmt.Errorf("failed to save report: %w", err)
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Monthly report %s generated for user %s (%d bytes)", rep.ReportId, userId, rep.FileSize))
+	return s.withDownloadURL(rep)
+}
+
+// collectReportData reads the figures of the month, the previous month is only used for the change
+func (s *reportService) collectReportData(userId string, periodStart, periodEnd time.Time) (*pdfreport.Monthly, error) {
+	period := &requests.AnalyticsQuery{
+		StartDate: periodStart.Format(utils.DateLayout),
+		EndDate:   periodEnd.Format(utils.DateLayout),
+	}
+
+	comparison, err := s.analyticsService.GetPeriodComparison(userId, period)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get totals for report of user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to get report totals: %w", err)
+	}
+
+	// The previous period of a month is the same number of days, compare with the whole previous month instead
+	previousStart := periodStart.AddDate(0, -1, 0)
+	previous, err := s.analyticsService.GetPeriodComparison(userId, &requests.AnalyticsQuery{
+		StartDate: previousStart.Format(utils.DateLayout),
+		EndDate:   periodStart.AddDate(0, 0, -1).Format(utils.DateLayout),
+	})
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get previous totals for report of user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to get report totals: %w", err)
+	}
+
+	categories, err := s.analyticsService.GetCategoryBreakdown(userId, &requests.AnalyticsQuery{
+		StartDate: period.StartDate,
+		EndDate:   period.EndDate,
+		Type:      string(constants.ExpenseCategory),
+	})
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get categories for report of user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to get report categories: %w", err)
+	}
+
+	merchants, err := s.analyticsService.GetTopSources(userId, &requests.AnalyticsQuery{
+		StartDate: period.StartDate,
+		EndDate:   period.EndDate,
+		Type:      string(constants.ExpenseCategory),
+		Limit:     constants.ReportTopMerchants,
+	})
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get merchants for report of user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to get report merchants: %w", err)
+	}
+
+	budgets, err := s.reportRepository.GetBudgetUsage(userId, periodStart.Year(), int(periodStart.Month()))
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get budgets for report of user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to get report budgets: %w", err)
+	}
+
+	goals, err := s.reportRepository.GetGoals(userId)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get goals for report of user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to get report goals: %w", err)
+	}
+
+	data := &pdfreport.Monthly{
+		PeriodStart:      periodStart,
+		Currency:         fx.Currency(comparison.Current.Currency),
+		TotalIncome:      comparison.Current.TotalIncome,
+		TotalExpense:     comparison.Current.TotalExpense,
+		TransactionCount: comparison.Current.TransactionCount,
+		PreviousIncome:   previous.Current.TotalIncome,
+		PreviousExpense:  previous.Current.TotalExpense,
+	}
+	for _, category := range categories.Categories {
+		data.Categories = append(data.Categories, pdfreport.Slice{Label: category.CategoryName, Value: category.Total})
+	}
+	for _, merchant := range merchants.Sources {
+		data.Merchants = append(data.Merchants, pdfreport.Merchant{Name: merchant.Source, Total: merchant.Total, Count: merchant.TransactionCount})
+	}
+	for _, budget := range budgets {
+		data.Budgets = append(data.Budgets, pdfreport.Budget{Category: budget.CategoryName, Limit: budget.AmountLimit, Spent: budget.Spent})
+	}
+	for _, goal := range goals {
+		data.Goals = append(data.Goals, pdfreport.Goal{
+			Title:      goal.Title,
+			Target:     int64(math.Round(goal.TargetAmount)),
+			Current:    int64(math.Round(goal.CurrentAmount)),
+			TargetDate: goal.TargetDate,
+			Status:     goal.Status,
+		})
+	}
+
+	return data, nil
+}
+
+// periodNarrative reuses the stored AI summary of the month or writes a new one. A failing LLM call
+// leaves the narrative empty so the report is still generated.
+func (s *reportService) periodNarrative(ctx context.Context, userId string, data *pdfreport.Monthly, prefs *responses.UserPreferences, periodEnd time.Time, refresh bool) string {
+	if !refresh {
+		summary, err := s.reportRepository.GetPeriodSummary(userId, data.PeriodStart)
+		if err == nil && strings.TrimSpace(summary.Narrative) != "" {
+			return summary.Narrative
+		}
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			s.logging.LogError(fmt.Sprintf("Failed to get period summary for user %s: %v", userId, err))
+		}
+	}
+
+	figures, err := json.MarshalIndent(map[string]any{
+		"total_income":           data.TotalIncome,
+		"total_expense":          data.TotalExpense,
+		"transaction_count":      data.TransactionCount,
+		"previous_total_income":  data.PreviousIncome,
+		"previous_total_expense": data.PreviousExpense,
+		"expense_by_category":    data.Categories,
+		"top_merchants":          data.Merchants,
+		"budgets":                data.Budgets,
+		"goals":                  data.Goals,
+	}, "", "  ")
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to encode report figures for user %s: %v", userId, err))
+		return ""
+	}
+
+	locale := prefs.PromptLocale(ctx)
+	resolve := resolverFor(s.promptRegistry, userId, locale)
+	preferences, preferencePrompts := preferencePrompt(resolve, prefs, locale, time.Now())
+	systemPrompt := resolve(prompt.MonthlySummarySystem)
+	userPrompt := resolve(prompt.MonthlySummaryUser)
+	promptVersions := models.PromptVersions{}.Add(preferencePrompts...).Add(systemPrompt, userPrompt)
+	messagePrompt := []openai.ChatCompletionMessageParamUnion{
+		{OfSystem: &openai.ChatCompletionSystemMessageParam{
+			Name: param.Opt[string]{Value: "system"},
+			Content: openai.ChatCompletionSystemMessageParamContentUnion{
+				OfString: param.NewOpt(systemPrompt.Content + preferences),
+			},
+		},
+		},
+		{OfUser: &openai.ChatCompletionUserMessageParam{
+			Name: param.Opt[string]{Value: "user"},
+			Content: openai.ChatCompletionUserMessageParamContentUnion{
+				OfString: param.NewOpt(fmt.Sprintf(userPrompt.Content, data.PeriodStart.Format("January 2006"), figures)),
+			},
+		},
+		},
+	}
+
+	responseAi, err := s.openaiClient.SendChat(ctx, "gpt-4o-mini", messagePrompt)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to generate report narrative for user %s: %v", userId, err))
+		return ""
+	}
+	if responseAi == nil {
+		s.logging.LogWarn(fmt.Sprintf("Empty report narrative for user %s", userId))
+		return ""
+	}
+	narrative, ok := responseAi.Response.(string)
+
+	dateNow := time.Now()
+	// The narrative is kept with the period summary, the log only accounts the call
+	_ = s.logMessageService.InsertLogMessage(&models.LogMessage{
+		LogMessageId: ulid.Make().String(),
+		UserId:       userId,
+		InputToken:   responseAi.InputToken,
+		OutputToken:  responseAi.OutputToken,
+		Topic:        constants.TopicMonthlyReport,
+		Model:        responseAi.Model,
+		CreatedAt:    dateNow,
+		UpdatedAt:    dateNow,
+
+		PromptVersions: promptVersions,
+		ParseFailed:    !ok || strings.TrimSpace(narrative) == "",
+	})
+
+	if !ok || strings.TrimSpace(narrative) == "" {
+		s.logging.LogWarn(fmt.Sprintf("Empty report narrative for user %s", userId))
+		return ""
+	}
+
+	now := time.Now()
+	err = s.reportRepository.InsertPeriodSummary(&models.AISummary{
+		SummaryId:   ulid.Make().String(),
+		UserId:      userId,
+		PeriodType:  constants.PeriodTypeMonthly,
+		PeriodStart: data.PeriodStart,
+		PeriodEnd:   periodEnd,
+		SummaryData: models.PeriodSummary{
+			Narrative:    narrative,
+			TotalIncome:  data.TotalIncome,
+			TotalExpense: data.TotalExpense,
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		// The narrative is still used, it is only written again next time
+		s.logging.LogError(fmt.Sprintf("Failed to save period summary for user %s: %v", userId, err))
+	}
+
+	return narrative
+}
+
+func (s *reportService) GetReports(userId string, req *requests.ReportQuery) (*responses.MonthlyReportsResponse, error) {
+	offset := 0
+	if req.Offset > 1 {
+		offset = (req.Offset - 1) * req.Limit
+	}
+
+	reports, err := s.reportRepository.GetReports(userId, req.Year, req.Limit, offset)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get reports for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to get reports: %w", err)
+	}
+
+	count, err := s.reportRepository.CountReports(userId, req.Year)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to count reports for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to count reports: %w", err)
+	}
+
+	if reports == nil {
+		reports = []models.MonthlyReport{}
+	}
+
+	totalPages := math.Ceil(float64(count) / float64(req.Limit))
+	currentPage := math.Min(float64(req.Offset), totalPages)
+
+	return &responses.MonthlyReportsResponse{
+		TotalPages:  int64(totalPages),
+		CurrentPage: int64(currentPage),
+		Total:       count,
+		Reports:     reports,
+	}, nil
+}
+
+func (s *reportService) GetReportById(userId, reportId string) (*responses.MonthlyReportResponse, error) {
+	rep, err := s.reportRepository.GetReportById(userId, reportId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, report.ErrReportNotFound
+		}
+		s.logging.LogError(fmt.Sprintf("Failed to get report %s: %v", reportId, err))
+		return nil, fmt.Errorf("failed to get report: %w", err)
+	}
+
+	return s.withDownloadURL(rep)
+}
+
+// withDownloadURL signs a fresh download link, the stored report only keeps the object name
+func (s *reportService) withDownloadURL(rep *models.MonthlyReport) (*responses.MonthlyReportResponse, error) {
+	fileName := fmt.Sprintf("laporan-keuangan-%d-%02d.pdf", rep.Year, rep.Month)
+	expiresAt := time.Now().Add(constants.ReportLinkExpiry)
+
+	url, err := s.minioClient.PresignedGetURL(constants.ReportBucket, rep.ObjectName, fileName, constants.ReportLinkExpiry)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to sign download link of report %s: %v", rep.ReportId, err))
+		return nil, fmt.Errorf("failed to sign report download link: %w", err)
+	}
+
+	return &responses.MonthlyReportResponse{
+		MonthlyReport:     *rep,
+		DownloadURL:       url,
+		DownloadExpiresAt: expiresAt,
+	}, nil
+}
+
+func (s *reportService) GenerateReportsForAllUsers(ctx context.Context, now time.Time) error {
+	periodStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+	periodEnd := periodStart.AddDate(0, 1, 0)
+
+	userIds, err := s.reportRepository.GetUserIdsWithTransactionsBetween(periodStart, periodEnd)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get users for monthly reports: %v", err))
+		return fmt.Errorf("failed to get users for monthly reports: %w", err)
+	}
+
+	generated := 0
+	for _, userId := range userIds {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		_, err := s.reportRepository.GetReportByPeriod(userId, periodStart.Year(), int(periodStart.Month()))
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			s.logging.LogError(fmt.Sprintf("Failed to check monthly report of user %s: %v", userId, err))
+			continue
+		}
+
+		_, err = s.GenerateMonthlyReport(ctx, userId, &requests.GenerateReportRequest{
+			Year:  periodStart.Year(),
+			Month: int(periodStart.Month()),
+		})
+		if err != nil {
+			// One user's failure must not block the others
+			s.logging.LogError(fmt.Sprintf("Monthly report failed for user %s: %v", userId, err))
+			continue
+		}
+		generated++
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Monthly reports for %s generated for %d of %d users", periodStart.Format("2006-01"), generated, len(userIds)))
+	return nil
+}
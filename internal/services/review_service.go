@@ -0,0 +1,185 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/saufiroja/fin-ai/config"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/categories"
+	"github.com/saufiroja/fin-ai/internal/domains/review"
+	"github.com/saufiroja/fin-ai/internal/models"
+	logging "github.com/saufiroja/fin-ai/pkg/loggings"
+)
+
+type reviewService struct {
+	reviewRepository review.ReviewStorer
+	categoryService  categories.CategoryManager
+	logging          logging.Logger
+	conf             *config.AppConfig
+}
+
+func NewReviewService(
+	reviewRepository review.ReviewStorer,
+	categoryService categories.CategoryManager,
+	logging logging.Logger,
+	conf *config.AppConfig,
+) review.ReviewManager {
+	return &reviewService{
+		reviewRepository: reviewRepository,
+		categoryService:  categoryService,
+		logging:          logging,
+		conf:             conf,
+	}
+}
+
+func (s *reviewService) GetReviewQueue(userId string, req *requests.ReviewQueueQuery) (*responses.ReviewQueueResponse, error) {
+	if req.Threshold <= 0 || req.Threshold > 1 {
+		req.Threshold = s.conf.Review.ConfidenceThreshold
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Fetching review queue for user %s with threshold %.2f", userId, req.Threshold))
+
+	// Convert page-based offset to row offset, same as the other list endpoints
+	offset := 0
+	if req.Offset > 1 {
+		offset = (req.Offset - 1) * req.Limit
+	}
+
+	queryReq := &requests.ReviewQueueQuery{
+		Limit:     req.Limit,
+		Offset:    offset,
+		Threshold: req.Threshold,
+		Type:      req.Type,
+	}
+
+	res := &responses.ReviewQueueResponse{
+		Threshold:    req.Threshold,
+		Transactions: []models.Transaction{},
+		ReceiptItems: []*responses.ReviewReceiptItem{},
+	}
+
+	if req.Type == "" || req.Type == "transactions" {
+		transactions, err := s.reviewRepository.FindTransactionsForReview(userId, queryReq)
+		if err != nil {
+			s.logging.LogError(fmt.Sprintf("Failed to fetch transactions for review: %v", err))
+			return nil, fmt.Errorf("failed to fetch transactions for review: %w", err)
+		}
+
+		count, err := s.reviewRepository.CountTransactionsForReview(userId, req.Threshold)
+		if err != nil {
+			s.logging.LogError(fmt.Sprintf("Failed to count transactions for review: %v", err))
+			return nil, fmt.Errorf("failed to count transactions for review: %w", err)
+		}
+
+		if transactions != nil {
+			res.Transactions = transactions
+		}
+		res.TotalTransactions = count
+	}
+
+	if req.Type == "" || req.Type == "receipt_items" {
+		items, err := s.reviewRepository.FindReceiptItemsForReview(userId, queryReq)
+		if err != nil {
+			s.logging.LogError(fmt.Sprintf("Failed to fetch receipt items for review: %v", err))
+			return nil, fmt.Errorf("failed to fetch receipt items for review: %w", err)
+		}
+
+		count, err := s.reviewRepository.CountReceiptItemsForReview(userId, req.Threshold)
+		if err != nil {
+			s.logging.LogError(fmt.Sprintf("Failed to count receipt items for review: %v", err))
+			return nil, fmt.Errorf("failed to count receipt items for review: %w", err)
+		}
+
+		if items != nil {
+			res.ReceiptItems = items
+		}
+		res.TotalReceiptItems = count
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Review queue for user %s has %d transactions and %d receipt items",
+		userId, res.TotalTransactions, res.TotalReceiptItems))
+	return res, nil
+}
+
+func (s *reviewService) AcceptReviewItems(userId string, req *requests.ReviewActionRequest) (*responses.ReviewActionResponse, error) {
+	if len(req.TransactionIds) == 0 && len(req.ReceiptItemIds) == 0 {
+		return nil, review.ErrNoItemsSelected
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Accepting %d transactions and %d receipt items for user %s",
+		len(req.TransactionIds), len(req.ReceiptItemIds), userId))
+
+	res := &responses.ReviewActionResponse{}
+
+	if len(req.TransactionIds) > 0 {
+		updated, err := s.reviewRepository.ConfirmTransactions(userId, req.TransactionIds)
+		if err != nil {
+			s.logging.LogError(fmt.Sprintf("Failed to confirm transactions: %v", err))
+			return nil, fmt.Errorf("failed to confirm transactions: %w", err)
+		}
+		res.UpdatedTransactions = updated
+	}
+
+	if len(req.ReceiptItemIds) > 0 {
+		updated, err := s.reviewRepository.ConfirmReceiptItems(userId, req.ReceiptItemIds)
+		if err != nil {
+			s.logging.LogError(fmt.Sprintf("Failed to confirm receipt items: %v", err))
+			return nil, fmt.Errorf("failed to confirm receipt items: %w", err)
+		}
+		res.UpdatedReceiptItems = updated
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Accepted %d transactions and %d receipt items",
+		res.UpdatedTransactions, res.UpdatedReceiptItems))
+	return res, nil
+}
+
+func (s *reviewService) RecategorizeReviewItems(userId string, req *requests.ReviewActionRequest) (*responses.ReviewActionResponse, error) {
+	if len(req.TransactionIds) == 0 && len(req.ReceiptItemIds) == 0 {
+		return nil, review.ErrNoItemsSelected
+	}
+
+	if req.CategoryId == "" {
+		return nil, review.ErrCategoryIdRequired
+	}
+
+	category, err := s.categoryService.FindCategoryById(req.CategoryId)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		s.logging.LogError(fmt.Sprintf("Failed to fetch category %s for recategorization: %v", req.CategoryId, err))
+		return nil, fmt.Errorf("failed to fetch category: %w", err)
+	}
+	if category == nil {
+		s.logging.LogInfo(fmt.Sprintf("Category %s not found for recategorization", req.CategoryId))
+		return nil, review.ErrCategoryNotFound
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Recategorizing %d transactions and %d receipt items to %s for user %s",
+		len(req.TransactionIds), len(req.ReceiptItemIds), req.CategoryId, userId))
+
+	res := &responses.ReviewActionResponse{}
+
+	if len(req.TransactionIds) > 0 {
+		updated, err := s.reviewRepository.RecategorizeTransactions(userId, req.TransactionIds, req.CategoryId)
+		if err != nil {
+			s.logging.LogError(fmt.Sprintf("Failed to recategorize transactions: %v", err))
+			return nil, fmt.Errorf("failed to recategorize transactions: %w", err)
+		}
+		res.UpdatedTransactions = updated
+	}
+
+	if len(req.ReceiptItemIds) > 0 {
+		updated, err := s.reviewRepository.RecategorizeReceiptItems(userId, req.ReceiptItemIds, req.CategoryId)
+		if err != nil {
+			s.logging.LogError(fmt.Sprintf("Failed to recategorize receipt items: %v", err))
+			return nil, fmt.Errorf("failed to recategorize receipt items: %w", err)
+		}
+		res.UpdatedReceiptItems = updated
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Recategorized %d transactions and %d receipt items",
+		res.UpdatedTransactions, res.UpdatedReceiptItems))
+	return res, nil
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/domains/review"
+	logging "github.com/saufiroja/fin-ai/pkg/loggings"
+)
+
+type fakeReviewStorer struct {
+	review.ReviewStorer
+}
+
+func (fakeReviewStorer) ConfirmTransactions(userId string, transactionIds []string) (int64, error) {
+	return int64(len(transactionIds)), nil
+}
+
+func (fakeReviewStorer) ConfirmReceiptItems(userId string, receiptItemIds []string) (int64, error) {
+	return int64(len(receiptItemIds)), nil
+}
+
+func (fakeReviewStorer) RecategorizeTransactions(userId string, transactionIds []string, categoryId string) (int64, error) {
+	return int64(len(transactionIds)), nil
+}
+
+func (fakeReviewStorer) RecategorizeReceiptItems(userId string, receiptItemIds []string, categoryId string) (int64, error) {
+	return int64(len(receiptItemIds)), nil
+}
+
+func TestReviewActionErrors(t *testing.T) {
+	service := NewReviewService(fakeReviewStorer{}, fakeCategoryManager{}, logging.NewLogrusAdapter(), nil)
+	userId := "01JB2Q4K7N3P8S2V5X9Z1B4D6F"
+
+	tests := []struct {
+		name         string
+		recategorize bool
+		req          *requests.ReviewActionRequest
+		want         error
+	}{
+		{
+			name: "accept without items",
+			req:  &requests.ReviewActionRequest{},
+			want: review.ErrNoItemsSelected,
+		},
+		{
+			name: "accept selected items",
+			req:  &requests.ReviewActionRequest{TransactionIds: []string{"a"}, ReceiptItemIds: []string{"b"}},
+		},
+		{
+			name:         "recategorize without items",
+			recategorize: true,
+			req:          &requests.ReviewActionRequest{CategoryId: "01JB2Q4M1Q6T9W3Y7A2C5E8G0J"},
+			want:         review.ErrNoItemsSelected,
+		},
+		{
+			name:         "recategorize without a category",
+			recategorize: true,
+			req:          &requests.ReviewActionRequest{TransactionIds: []string{"a"}},
+			want:         review.ErrCategoryIdRequired,
+		},
+		{
+			name:         "recategorize to an unknown category",
+			recategorize: true,
+			req:          &requests.ReviewActionRequest{TransactionIds: []string{"a"}, CategoryId: "missing"},
+			want:         review.ErrCategoryNotFound,
+		},
+		{
+			name:         "recategorize to a known category",
+			recategorize: true,
+			req:          &requests.ReviewActionRequest{ReceiptItemIds: []string{"b"}, CategoryId: "01JB2Q4M1Q6T9W3Y7A2C5E8G0J"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			if tt.recategorize {
+				_, err = service.RecategorizeReviewItems(userId, tt.req)
+			} else {
+				_, err = service.AcceptReviewItems(userId, tt.req)
+			}
+			if !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/packages/param"
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/log_message"
+	"github.com/saufiroja/fin-ai/internal/domains/search"
+	"github.com/saufiroja/fin-ai/pkg/llm"
+	logging "github.com/saufiroja/fin-ai/pkg/loggings"
+)
+
+type searchService struct {
+	searchRepository  search.SearchStorer
+	logging           logging.Logger
+	openaiClient      llm.OpenAI
+	logMessageService log_message.LogMessageManager
+}
+
+func NewSearchService(
+	searchRepository search.SearchStorer,
+	logging logging.Logger,
+	openaiClient llm.OpenAI,
+	logMessageService log_message.LogMessageManager,
+) search.SearchManager {
+	return &searchService{
+		searchRepository:  searchRepository,
+		logging:           logging,
+		openaiClient:      openaiClient,
+		logMessageService: logMessageService,
+	}
+}
+
+func (s *searchService) Search(userId string, req *requests.SearchQuery) (*responses.SearchResponse, error) {
+	s.logging.LogInfo(fmt.Sprintf("Searching for user %s with query: %+v", userId, req))
+
+	// Semantic ranking is best effort, keyword results are still returned without it
+	embedding := ""
+	res := s.openaiClient.CreateEmbedding(context.Background(), openai.EmbeddingNewParamsInputUnion{
+		OfString: param.NewOpt(req.Query),
+	})
+	if res != nil {
+		embedding = res.Embeddings
+		s.logMessageService.LogUsage(userId, constants.TopicSearchEmbedding, res.Model, res.InputToken, 0)
+	} else {
+		s.logging.LogWarn("Failed to create search query embedding, falling back to keyword search only")
+	}
+
+	fused := make(map[string]*responses.SearchResult)
+
+	if req.Type == "" || req.Type == string(constants.SearchResultTransaction) {
+		if err := s.searchTransactions(userId, embedding, req, fused); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Type == "" || req.Type == string(constants.SearchResultReceipt) {
+		if err := s.searchReceipts(userId, embedding, req, fused); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Type == "" || req.Type == string(constants.SearchResultReceiptItem) {
+		if err := s.searchReceiptItems(userId, embedding, req, fused); err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]*responses.SearchResult, 0, len(fused))
+	for _, result := range fused {
+		results = append(results, result)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].TransactionDate.After(results[j].TransactionDate)
+	})
+
+	// Convert page-based offset to a slice window, same as the other list endpoints
+	total := len(results)
+	start := 0
+	if req.Offset > 1 {
+		start = (req.Offset - 1) * req.Limit
+	}
+	end := start + req.Limit
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+
+	totalPages := math.Ceil(float64(total) / float64(req.Limit))
+	currentPage := math.Min(float64(req.Offset), totalPages)
+
+	s.logging.LogInfo(fmt.Sprintf("Search for user %s matched %d results", userId, total))
+
+	return &responses.SearchResponse{
+		TotalPages:  int64(totalPages),
+		CurrentPage: int64(currentPage),
+		Total:       int64(total),
+		Results:     results[start:end],
+	}, nil
+}
+
+func (s *searchService) searchTransactions(userId, embedding string, req *requests.SearchQuery, fused map[string]*responses.SearchResult) error {
+	keyword, err := s.searchRepository.KeywordSearchTransactions(userId, req, constants.SearchCandidateLimit)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to keyword search transactions: %v", err))
+		return fmt.Errorf("failed to keyword search transactions: %w", err)
+	}
+
+	for i, tx := range keyword {
+		result := s.fuseResult(fused, constants.SearchResultTransaction, tx.Transaction.TransactionId)
+		result.KeywordRank = i + 1
+		result.Score += s.reciprocalRank(i + 1)
+		result.TransactionDate = tx.Transaction.TransactionDate
+		result.Transaction = tx.Transaction
+	}
+
+	if embedding == "" {
+		return nil
+	}
+
+	semantic, err := s.searchRepository.SemanticSearchTransactions(userId, embedding, req, constants.SearchCandidateLimit)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to semantic search transactions: %v", err))
+		return fmt.Errorf("failed to semantic search transactions: %w", err)
+	}
+
+	for i, tx := range semantic {
+		result := s.fuseResult(fused, constants.SearchResultTransaction, tx.Transaction.TransactionId)
+		result.SemanticRank = i + 1
+		result.Score += s.reciprocalRank(i + 1)
+		result.TransactionDate = tx.Transaction.TransactionDate
+		result.Transaction = tx.Transaction
+	}
+
+	return nil
+}
+
+func (s *searchService) searchReceipts(userId, embedding string, req *requests.SearchQuery, fused map[string]*responses.SearchResult) error {
+	keyword, err := s.searchRepository.KeywordSearchReceipts(userId, req, constants.SearchCandidateLimit)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to keyword search receipts: %v", err))
+		return fmt.Errorf("failed to keyword search receipts: %w", err)
+	}
+
+	for i, receipt := range keyword {
+		result := s.fuseResult(fused, constants.SearchResultReceipt, receipt.Receipt.ReceiptId)
+		result.KeywordRank = i + 1
+		result.Score += s.reciprocalRank(i + 1)
+		result.TransactionDate = receipt.Receipt.TransactionDate
+		result.MerchantName = receipt.Receipt.MerchantName
+		result.Receipt = receipt.Receipt
+	}
+
+	if embedding == "" {
+		return nil
+	}
+
+	semantic, err := s.searchRepository.SemanticSearchReceipts(userId, embedding, req, constants.SearchCandidateLimit)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to semantic search receipts: %v", err))
+		return fmt.Errorf("failed to semantic search receipts: %w", err)
+	}
+
+	for i, receipt := range semantic {
+		result := s.fuseResult(fused, constants.SearchResultReceipt, receipt.Receipt.ReceiptId)
+		result.SemanticRank = i + 1
+		result.Score += s.reciprocalRank(i + 1)
+		result.TransactionDate = receipt.Receipt.TransactionDate
+		result.MerchantName = receipt.Receipt.MerchantName
+		result.Receipt = receipt.Receipt
+	}
+
+	return nil
+}
+
+func (s *searchService) searchReceiptItems(userId, embedding string, req *requests.SearchQuery, fused map[string]*responses.SearchResult) error {
+	keyword, err := s.searchRepository.KeywordSearchReceiptItems(userId, req, constants.SearchCandidateLimit)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to keyword search receipt items: %v", err))
+		return fmt.Errorf("failed to keyword search receipt items: %w", err)
+	}
+
+	for i, item := range keyword {
+		result := s.fuseResult(fused, constants.SearchResultReceiptItem, item.ReceiptItem.ReceiptItemId)
+		result.KeywordRank = i + 1
+		result.Score += s.reciprocalRank(i + 1)
+		result.TransactionDate = item.TransactionDate
+		result.MerchantName = item.MerchantName
+		result.ReceiptItem = item.ReceiptItem
+	}
+
+	if embedding == "" {
+		return nil
+	}
+
+	semantic, err := s.searchRepository.SemanticSearchReceiptItems(userId, embedding, req, constants.SearchCandidateLimit)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to semantic search receipt items: %v", err))
+		return fmt.Errorf("failed to semantic search receipt items: %w", err)
+	}
+
+	for i, item := range semantic {
+		result := s.fuseResult(fused, constants.SearchResultReceiptItem, item.ReceiptItem.ReceiptItemId)
+		result.SemanticRank = i + 1
+		result.Score += s.reciprocalRank(i + 1)
+		result.TransactionDate = item.TransactionDate
+		result.MerchantName = item.MerchantName
+		result.ReceiptItem = item.ReceiptItem
+	}
+
+	return nil
+}
+
+// fuseResult returns the fused entry for an entity, creating it on first sight
+func (s *searchService) fuseResult(fused map[string]*responses.SearchResult, resultType constants.SearchResultType, id string) *responses.SearchResult {
+	key := string(resultType) + ":" + id
+	if result, ok := fused[key]; ok {
+		return result
+	}
+
+	result := &responses.SearchResult{
+		Type: resultType,
+		Id:   id,
+	}
+	fused[key] = result
+	return result
+}
+
+// reciprocalRank is the RRF contribution of a 1-based rank from a single ranker
+func (s *searchService) reciprocalRank(rank int) float64 {
+	return 1.0 / float64(constants.SearchRRFK+rank)
+}
@@ -0,0 +1,584 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/currency"
+	"github.com/saufiroja/fin-ai/internal/domains/recommendation"
+	"github.com/saufiroja/fin-ai/internal/domains/recurring"
+	"github.com/saufiroja/fin-ai/internal/domains/subscription"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/internal/utils"
+	"github.com/saufiroja/fin-ai/pkg/fx"
+	logging "github.com/saufiroja/fin-ai/pkg/loggings"
+)
+
+var (
+	// subscriptionNoise strips reference numbers and punctuation, e.g. "NETFLIX.COM 1234" -> "netflix com"
+	subscriptionNoise = regexp.MustCompile(`[^a-z]+`)
+
+	subscriptionStopwords = map[string]bool{
+		"com": true, "www": true, "co": true, "id": true, "pt": true, "tbk": true,
+		"payment": true, "pembayaran": true, "bayar": true, "langganan": true,
+	}
+)
+
+// subscriptionFrequency describes a supported billing cadence
+type subscriptionFrequency struct {
+	period         constants.PeriodType
+	nominalDays    float64
+	toleranceDays  float64
+	periodsPerYear int64
+}
+
+var subscriptionFrequencies = []subscriptionFrequency{
+	{period: constants.PeriodTypeWeekly, nominalDays: 7, toleranceDays: 2, periodsPerYear: 52},
+	{period: constants.PeriodTypeMonthly, nominalDays: 30.44, toleranceDays: 4, periodsPerYear: 12},
+	{period: constants.PeriodTypeYearly, nominalDays: 365.25, toleranceDays: 15, periodsPerYear: 1},
+}
+
+// chargeGroup collects charges of one merchant, keyed by their normalized descriptions
+type chargeGroup struct {
+	keys         []string
+	charges      []models.SubscriptionCharge
+	centroid     []float64
+	medianAmount float64
+}
+
+type subscriptionService struct {
+	subscriptionRepository subscription.SubscriptionStorer
+	recurringService       recurring.RecurringManager
+	recommendationService  recommendation.RecommendationManager
+	currencyService        currency.CurrencyManager
+	logging                logging.Logger
+}
+
+func NewSubscriptionService(
+	subscriptionRepository subscription.SubscriptionStorer,
+	recurringService recurring.RecurringManager,
+	recommendationService recommendation.RecommendationManager,
+	currencyService currency.CurrencyManager,
+	logging logging.Logger,
+) subscription.SubscriptionManager {
+	return &subscriptionService{
+		subscriptionRepository: subscriptionRepository,
+		recurringService:       recurringService,
+		recommendationService:  recommendationService,
+		currencyService:        currencyService,
+		logging:                logging,
+	}
+}
+
+func (s *subscriptionService) DetectSubscriptions(ctx context.Context, userId string, now time.Time, saveRecommendations bool) (*responses.SubscriptionDetectionResponse, error) {
+	s.logging.LogInfo(fmt.Sprintf("Detecting subscriptions for user %s", userId))
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	since := today.AddDate(0, 0, -constants.SubscriptionLookbackDays)
+
+	charges, err := s.subscriptionRepository.GetExpenseCharges(userId, since, constants.SubscriptionMaxCharges)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get expense charges for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to get expense charges: %w", err)
+	}
+
+	rules, err := s.recurringService.GetRecurringRules(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleIdsByKey := make(map[string]string, len(rules))
+	for _, rule := range rules {
+		ruleIdsByKey[s.normalizeDescription(rule.Description)] = rule.RecurringRuleId
+	}
+
+	base, err := s.currencyService.GetBaseCurrency(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &responses.SubscriptionDetectionResponse{
+		Currency:      responses.NewCurrencyMeta(base),
+		Subscriptions: []responses.DetectedSubscription{},
+	}
+
+	for _, group := range s.groupCharges(charges) {
+		detected := s.analyzeGroup(group, today)
+		if detected == nil {
+			continue
+		}
+
+		for _, key := range group.keys {
+			if ruleId, ok := ruleIdsByKey[key]; ok {
+				detected.ExistingRuleId = ruleId
+				break
+			}
+		}
+		if detected.IsActive && detected.ExistingRuleId == "" {
+			detected.SuggestedRule = s.suggestRule(group, detected)
+		}
+
+		if detected.IsActive {
+			res.ActiveCount++
+			res.EstimatedYearlyCost += detected.EstimatedYearlyCost
+		}
+		res.Subscriptions = append(res.Subscriptions, *detected)
+	}
+	res.EstimatedMonthlyCost = int64(math.Round(float64(res.EstimatedYearlyCost) / 12))
+
+	sort.SliceStable(res.Subscriptions, func(i, j int) bool {
+		if res.Subscriptions[i].IsActive != res.Subscriptions[j].IsActive {
+			return res.Subscriptions[i].IsActive
+		}
+		return res.Subscriptions[i].EstimatedYearlyCost > res.Subscriptions[j].EstimatedYearlyCost
+	})
+
+	if saveRecommendations {
+		res.RecommendationsCreated = s.saveRecommendations(ctx, userId, base, res.Subscriptions, today)
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Detected %d subscriptions (%d active) for user %s", len(res.Subscriptions), res.ActiveCount, userId))
+	return res, nil
+}
+
+func (s *subscriptionService) DetectSubscriptionsForAllUsers(ctx context.Context, now time.Time) error {
+	since := now.AddDate(0, 0, -constants.SubscriptionLookbackDays)
+
+	userIds, err := s.subscriptionRepository.GetUserIdsWithExpensesSince(since)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get users for subscription detection: %v", err))
+		return fmt.Errorf("failed to get users for subscription detection: %w", err)
+	}
+
+	created := 0
+	for _, userId := range userIds {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		res, err := s.DetectSubscriptions(ctx, userId, now, true)
+		if err != nil {
+			// One user's failure must not block the others
+			s.logging.LogError(fmt.Sprintf("Subscription detection failed for user %s: %v", userId, err))
+			continue
+		}
+		created += res.RecommendationsCreated
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Subscription detection finished for %d users, %d recommendations created", len(userIds), created))
+	return nil
+}
+
+// groupCharges buckets charges by normalized description, then merges buckets whose
+// descriptions are semantically close (e.g. "NETFLIX.COM 1234" and "Netflix") and whose amounts are similar
+func (s *subscriptionService) groupCharges(charges []models.SubscriptionCharge) []*chargeGroup {
+	byKey := make(map[string]*chargeGroup)
+	var groups []*chargeGroup
+	for _, charge := range charges {
+		key := s.normalizeDescription(charge.Description)
+		group, ok := byKey[key]
+		if !ok {
+			group = &chargeGroup{keys: []string{key}}
+			byKey[key] = group
+			groups = append(groups, group)
+		}
+		group.charges = append(group.charges, charge)
+	}
+
+	for _, group := range groups {
+		group.centroid = s.centroid(group.charges)
+		group.medianAmount = s.medianAmount(group.charges)
+	}
+
+	// Only groups within the amount tolerance can be the same subscription, so compare neighbours by amount
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].medianAmount < groups[j].medianAmount
+	})
+
+	parent := make([]int, len(groups))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := range groups {
+		maxAmount := groups[i].medianAmount * (1 + constants.SubscriptionAmountTolerance)
+		for j := i + 1; j < len(groups) && groups[j].medianAmount <= maxAmount; j++ {
+			if utils.CosineSimilarity(groups[i].centroid, groups[j].centroid) >= constants.SubscriptionSimilarityThreshold {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	merged := make(map[int]*chargeGroup)
+	var result []*chargeGroup
+	for i, group := range groups {
+		root := find(i)
+		target, ok := merged[root]
+		if !ok {
+			target = &chargeGroup{}
+			merged[root] = target
+			result = append(result, target)
+		}
+		target.keys = append(target.keys, group.keys...)
+		target.charges = append(target.charges, group.charges...)
+	}
+
+	for _, group := range result {
+		sort.SliceStable(group.charges, func(i, j int) bool {
+			return group.charges[i].TransactionDate.Before(group.charges[j].TransactionDate)
+		})
+	}
+
+	return result
+}
+
+// analyzeGroup returns nil unless the charges repeat at a regular interval with similar amounts, apart from
+// at most SubscriptionMaxPriceSteps price changes
+func (s *subscriptionService) analyzeGroup(group *chargeGroup, today time.Time) *responses.DetectedSubscription {
+	charges := group.charges
+	if len(charges) < constants.SubscriptionMinYearlyOccurrences {
+		return nil
+	}
+
+	intervals := make([]float64, 0, len(charges)-1)
+	for i := 1; i < len(charges); i++ {
+		intervals = append(intervals, charges[i].TransactionDate.Sub(charges[i-1].TransactionDate).Hours()/24)
+	}
+
+	sorted := append([]float64(nil), intervals...)
+	sort.Float64s(sorted)
+	frequency, ok := s.matchFrequency(sorted[len(sorted)/2])
+	if !ok {
+		return nil
+	}
+
+	minOccurrences := constants.SubscriptionMinOccurrences
+	if frequency.period == constants.PeriodTypeYearly {
+		minOccurrences = constants.SubscriptionMinYearlyOccurrences
+	}
+	if len(charges) < minOccurrences {
+		return nil
+	}
+
+	regular := 0
+	totalInterval := 0.0
+	for _, interval := range intervals {
+		if math.Abs(interval-frequency.nominalDays) <= frequency.toleranceDays {
+			regular++
+		}
+		totalInterval += interval
+	}
+	if float64(regular)/float64(len(intervals)) < constants.SubscriptionMinRegularity {
+		return nil
+	}
+
+	// A jump beyond the tolerance is a new price as long as it happens once, it shows up as PriceIncrease.
+	// Amounts that keep jumping are not one subscription.
+	steps := 0
+	var totalAmount int64
+	for i, charge := range charges {
+		if i > 0 && s.relativeChange(charges[i-1].Amount, charge.Amount) > constants.SubscriptionAmountTolerance {
+			steps++
+			if steps > constants.SubscriptionMaxPriceSteps {
+				return nil
+			}
+		}
+		totalAmount += charge.Amount
+	}
+
+	first := charges[0]
+	latest := charges[len(charges)-1]
+	nextExpected := s.addPeriod(latest.TransactionDate, frequency.period)
+	grace := time.Duration(frequency.toleranceDays*2) * 24 * time.Hour
+
+	detected := &responses.DetectedSubscription{
+		Merchant:            strings.TrimSpace(latest.Description),
+		Descriptions:        s.distinctDescriptions(charges),
+		Frequency:           frequency.period,
+		AverageIntervalDays: math.Round(totalInterval/float64(len(intervals))*10) / 10,
+		Occurrences:         len(charges),
+		LatestAmount:        latest.Amount,
+		AverageAmount:       int64(math.Round(float64(totalAmount) / float64(len(charges)))),
+		EstimatedYearlyCost: latest.Amount * frequency.periodsPerYear,
+		FirstChargeDate:     first.TransactionDate,
+		LastChargeDate:      latest.TransactionDate,
+		NextExpectedDate:    nextExpected,
+		IsActive:            !today.After(nextExpected.Add(grace)),
+		PriceIncrease:       s.detectPriceIncrease(charges),
+		TransactionIds:      make([]string, 0, len(charges)),
+	}
+	for _, charge := range charges {
+		detected.TransactionIds = append(detected.TransactionIds, charge.TransactionId)
+	}
+
+	return detected
+}
+
+// detectPriceIncrease compares the latest amount with the last amount that differed from it beyond noise
+func (s *subscriptionService) detectPriceIncrease(charges []models.SubscriptionCharge) *responses.PriceIncrease {
+	latest := charges[len(charges)-1].Amount
+
+	i := len(charges) - 2
+	for i >= 0 && s.relativeChange(charges[i].Amount, latest) < constants.SubscriptionPriceChangeThreshold {
+		i--
+	}
+	if i < 0 || charges[i].Amount >= latest {
+		return nil
+	}
+
+	previous := charges[i].Amount
+	return &responses.PriceIncrease{
+		PreviousAmount: previous,
+		NewAmount:      latest,
+		ChangePercent:  math.Round(float64(latest-previous)/float64(previous)*1000) / 10,
+		ChangedAt:      charges[i+1].TransactionDate,
+	}
+}
+
+// suggestRule prepares a recurring rule starting at the next expected charge so past charges are not recorded twice
+func (s *subscriptionService) suggestRule(group *chargeGroup, detected *responses.DetectedSubscription) *requests.RecurringRuleRequest {
+	latest := group.charges[len(group.charges)-1]
+
+	description := detected.Merchant
+	if runes := []rune(description); len(runes) > 255 {
+		description = string(runes[:255])
+	}
+
+	rule := &requests.RecurringRuleRequest{
+		CategoryId:    s.mostCommonCategory(group.charges),
+		Type:          constants.ExpenseCategory,
+		Description:   description,
+		Amount:        detected.LatestAmount,
+		PaymentMethod: latest.PaymentMethod,
+		Frequency:     detected.Frequency,
+		StartDate:     detected.NextExpectedDate.Format(utils.DateLayout),
+	}
+	if detected.Frequency == constants.PeriodTypeMonthly {
+		day := latest.TransactionDate.Day()
+		rule.DayOfMonth = &day
+	}
+
+	return rule
+}
+
+func (s *subscriptionService) saveRecommendations(ctx context.Context, userId string, base fx.Currency, subscriptions []responses.DetectedSubscription, today time.Time) int {
+	expiresAt := today.AddDate(0, 0, constants.SubscriptionRecommendationTTLDays)
+	recentSince := today.AddDate(0, 0, -constants.SubscriptionPriceIncreaseDays)
+
+	var recommendations []*models.AIRecommendation
+	for _, sub := range subscriptions {
+		if !sub.IsActive {
+			continue
+		}
+		key := s.normalizeDescription(sub.Merchant)
+		period := s.periodLabel(sub.Frequency)
+
+		if sub.PriceIncrease != nil && !sub.PriceIncrease.ChangedAt.Before(recentSince) {
+			increase := sub.PriceIncrease
+			priority := constants.RecommendationPriorityMedium
+			if increase.ChangePercent >= 20 {
+				priority = constants.RecommendationPriorityHigh
+			}
+			extraPerYear := (increase.NewAmount - increase.PreviousAmount) * s.periodsPerYear(sub.Frequency)
+
+			recommendations = append(recommendations, &models.AIRecommendation{
+				UserId:             userId,
+				RecommendationType: constants.RecommendationTypeSavingTips,
+				Title:              fmt.Sprintf("Price increase: %s", sub.Merchant),
+				Content: fmt.Sprintf(
+					"%s went up from %s to %s per %s (+%.1f%%) on %s, an extra %s a year. Check whether a cheaper plan or annual billing is available, or cancel it if you rarely use it.",
+					sub.Merchant, fx.Format(increase.PreviousAmount, base), fx.Format(increase.NewAmount, base), period,
+					increase.ChangePercent, increase.ChangedAt.Format(utils.DateLayout), fx.Format(extraPerYear, base),
+				),
+				Priority:     priority,
+				ExpiredAt:    &expiresAt,
+				ReferenceKey: fmt.Sprintf("subscription-price:%s:%d", key, increase.NewAmount),
+			})
+		}
+
+		// Subscriptions the user already tracks as a recurring rule are known to them
+		if sub.ExistingRuleId != "" {
+			continue
+		}
+
+		recommendations = append(recommendations, &models.AIRecommendation{
+			UserId:             userId,
+			RecommendationType: constants.RecommendationTypeSavingTips,
+			Title:              fmt.Sprintf("Recurring charge detected: %s", sub.Merchant),
+			Content: fmt.Sprintf(
+				"You pay %s every %s for %s, about %s a year (%d charges since %s). If you no longer use it, cancelling saves that amount; otherwise add it as a recurring rule to see it in upcoming bills.",
+				fx.Format(sub.LatestAmount, base), period, sub.Merchant, fx.Format(sub.EstimatedYearlyCost, base),
+				sub.Occurrences, sub.FirstChargeDate.Format(utils.DateLayout),
+			),
+			Priority:     constants.RecommendationPriorityLow,
+			ExpiredAt:    &expiresAt,
+			ReferenceKey: fmt.Sprintf("subscription:%s", key),
+		})
+	}
+
+	created := 0
+	for _, rec := range recommendations {
+		saved, err := s.recommendationService.SaveRecommendation(ctx, rec)
+		if err != nil {
+			s.logging.LogError(fmt.Sprintf("Failed to save subscription recommendation %s: %v", rec.ReferenceKey, err))
+			continue
+		}
+		if saved {
+			created++
+		}
+	}
+
+	return created
+}
+
+func (s *subscriptionService) normalizeDescription(description string) string {
+	cleaned := subscriptionNoise.ReplaceAllString(strings.ToLower(description), " ")
+
+	var tokens []string
+	for _, token := range strings.Fields(cleaned) {
+		if len(token) < 2 || subscriptionStopwords[token] {
+			continue
+		}
+		tokens = append(tokens, token)
+	}
+
+	if len(tokens) == 0 {
+		return strings.ToLower(strings.TrimSpace(description))
+	}
+	return strings.Join(tokens, " ")
+}
+
+func (s *subscriptionService) matchFrequency(intervalDays float64) (subscriptionFrequency, bool) {
+	for _, frequency := range subscriptionFrequencies {
+		if math.Abs(intervalDays-frequency.nominalDays) <= frequency.toleranceDays {
+			return frequency, true
+		}
+	}
+	return subscriptionFrequency{}, false
+}
+
+func (s *subscriptionService) periodsPerYear(period constants.PeriodType) int64 {
+	for _, frequency := range subscriptionFrequencies {
+		if frequency.period == period {
+			return frequency.periodsPerYear
+		}
+	}
+	return 1
+}
+
+func (s *subscriptionService) periodLabel(period constants.PeriodType) string {
+	switch period {
+	case constants.PeriodTypeWeekly:
+		return "week"
+	case constants.PeriodTypeYearly:
+		return "year"
+	default:
+		return "month"
+	}
+}
+
+// addPeriod keeps the day of month, clamped to the last day of shorter months
+func (s *subscriptionService) addPeriod(date time.Time, period constants.PeriodType) time.Time {
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+	months := 1
+	switch period {
+	case constants.PeriodTypeWeekly:
+		return date.AddDate(0, 0, 7)
+	case constants.PeriodTypeYearly:
+		months = 12
+	}
+
+	firstOfMonth := time.Date(date.Year(), date.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+	day := date.Day()
+	if lastDay := firstOfMonth.AddDate(0, 1, -1).Day(); day > lastDay {
+		day = lastDay
+	}
+	return time.Date(firstOfMonth.Year(), firstOfMonth.Month(), day, 0, 0, 0, 0, time.UTC)
+}
+
+func (s *subscriptionService) centroid(charges []models.SubscriptionCharge) []float64 {
+	var centroid []float64
+	count := 0
+	for _, charge := range charges {
+		if len(charge.Embedding) == 0 {
+			continue
+		}
+		if centroid == nil {
+			centroid = make([]float64, len(charge.Embedding))
+		}
+		if len(charge.Embedding) != len(centroid) {
+			continue
+		}
+		for i, v := range charge.Embedding {
+			centroid[i] += v
+		}
+		count++
+	}
+
+	for i := range centroid {
+		centroid[i] /= float64(count)
+	}
+	return centroid
+}
+
+func (s *subscriptionService) medianAmount(charges []models.SubscriptionCharge) float64 {
+	amounts := make([]float64, len(charges))
+	for i, charge := range charges {
+		amounts[i] = float64(charge.Amount)
+	}
+	sort.Float64s(amounts)
+	return amounts[len(amounts)/2]
+}
+
+func (s *subscriptionService) relativeChange(from, to int64) float64 {
+	if from == 0 {
+		if to == 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	return math.Abs(float64(to-from)) / float64(from)
+}
+
+func (s *subscriptionService) distinctDescriptions(charges []models.SubscriptionCharge) []string {
+	seen := make(map[string]bool)
+	var descriptions []string
+	for _, charge := range charges {
+		if !seen[charge.Description] {
+			seen[charge.Description] = true
+			descriptions = append(descriptions, charge.Description)
+		}
+	}
+	return descriptions
+}
+
+func (s *subscriptionService) mostCommonCategory(charges []models.SubscriptionCharge) string {
+	counts := make(map[string]int)
+	best := ""
+	for _, charge := range charges {
+		if charge.CategoryId == "" {
+			continue
+		}
+		counts[charge.CategoryId]++
+		if counts[charge.CategoryId] > counts[best] || best == "" {
+			best = charge.CategoryId
+		}
+	}
+	return best
+}
@@ -0,0 +1,303 @@
+package services
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/models"
+)
+
+func subscriptionDate(value string) time.Time {
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		panic(err)
+	}
+	return date
+}
+
+// subscriptionCharges builds charges of one description, dates and amounts pair up
+func subscriptionCharges(description string, dates []string, amounts []int64) []models.SubscriptionCharge {
+	charges := make([]models.SubscriptionCharge, len(dates))
+	for i, date := range dates {
+		charges[i] = models.SubscriptionCharge{
+			TransactionId:   description + " " + date,
+			Description:     description,
+			Amount:          amounts[i],
+			TransactionDate: subscriptionDate(date),
+		}
+	}
+	return charges
+}
+
+func TestNormalizeSubscriptionDescription(t *testing.T) {
+	tests := []struct {
+		description string
+		want        string
+	}{
+		{"NETFLIX.COM 1234", "netflix"},
+		{"Netflix", "netflix"},
+		{"Pembayaran Spotify Premium", "spotify premium"},
+		{"PT Telkom Indonesia Tbk", "telkom indonesia"},
+		{"www.youtube.com/premium", "youtube premium"},
+		{"COM 123", "com 123"},
+	}
+
+	s := &subscriptionService{}
+	for _, tt := range tests {
+		if got := s.normalizeDescription(tt.description); got != tt.want {
+			t.Errorf("normalizeDescription(%q) = %q, want %q", tt.description, got, tt.want)
+		}
+	}
+}
+
+func TestGroupCharges(t *testing.T) {
+	netflix := []float64{1, 0, 0}
+	netflixVariant := []float64{0.98, 0.1, 0}
+	gym := []float64{0, 1, 0}
+
+	charge := func(id, description string, amount int64, embedding []float64) models.SubscriptionCharge {
+		return models.SubscriptionCharge{
+			TransactionId:   id,
+			Description:     description,
+			Amount:          amount,
+			TransactionDate: subscriptionDate("2024-01-05"),
+			Embedding:       embedding,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		charges []models.SubscriptionCharge
+		want    [][]string
+	}{
+		{
+			name: "same normalized description",
+			charges: []models.SubscriptionCharge{
+				charge("a", "NETFLIX.COM 1234", 54000, nil),
+				charge("b", "Netflix.com 5678", 54000, nil),
+			},
+			want: [][]string{{"a", "b"}},
+		},
+		{
+			name: "similar description and amount",
+			charges: []models.SubscriptionCharge{
+				charge("a", "NETFLIX.COM", 54000, netflix),
+				charge("b", "NFLX Streaming", 56000, netflixVariant),
+			},
+			want: [][]string{{"a", "b"}},
+		},
+		{
+			name: "different merchant with the same amount",
+			charges: []models.SubscriptionCharge{
+				charge("a", "NETFLIX.COM", 54000, netflix),
+				charge("b", "Gold Gym", 54000, gym),
+			},
+			want: [][]string{{"a"}, {"b"}},
+		},
+		{
+			name: "similar description beyond the amount tolerance",
+			charges: []models.SubscriptionCharge{
+				charge("a", "NETFLIX.COM", 54000, netflix),
+				charge("b", "NFLX Streaming", 186000, netflixVariant),
+			},
+			want: [][]string{{"a"}, {"b"}},
+		},
+	}
+
+	s := &subscriptionService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][]string
+			for _, group := range s.groupCharges(tt.charges) {
+				var ids []string
+				for _, charge := range group.charges {
+					ids = append(ids, charge.TransactionId)
+				}
+				sort.Strings(ids)
+				got = append(got, ids)
+			}
+			sort.Slice(got, func(i, j int) bool { return got[i][0] < got[j][0] })
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("groups = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeGroup(t *testing.T) {
+	today := subscriptionDate("2024-04-20")
+
+	tests := []struct {
+		name       string
+		dates      []string
+		amounts    []int64
+		want       bool
+		frequency  constants.PeriodType
+		yearlyCost int64
+		active     bool
+		increase   *responses.PriceIncrease
+	}{
+		{
+			name:       "monthly",
+			dates:      []string{"2024-01-05", "2024-02-05", "2024-03-05", "2024-04-05"},
+			amounts:    []int64{54000, 54000, 54000, 54000},
+			want:       true,
+			frequency:  constants.PeriodTypeMonthly,
+			yearlyCost: 648000,
+			active:     true,
+		},
+		{
+			name:       "weekly",
+			dates:      []string{"2024-03-25", "2024-04-01", "2024-04-08", "2024-04-15"},
+			amounts:    []int64{25000, 25000, 26000, 25000},
+			want:       true,
+			frequency:  constants.PeriodTypeWeekly,
+			yearlyCost: 1300000,
+			active:     true,
+		},
+		{
+			name:       "yearly",
+			dates:      []string{"2023-03-01", "2024-03-01"},
+			amounts:    []int64{599000, 599000},
+			want:       true,
+			frequency:  constants.PeriodTypeYearly,
+			yearlyCost: 599000,
+			active:     true,
+		},
+		{
+			name:       "monthly that stopped",
+			dates:      []string{"2023-09-10", "2023-10-10", "2023-11-10", "2023-12-10"},
+			amounts:    []int64{99000, 99000, 99000, 99000},
+			want:       true,
+			frequency:  constants.PeriodTypeMonthly,
+			yearlyCost: 1188000,
+			active:     false,
+		},
+		{
+			name:    "irregular intervals",
+			dates:   []string{"2024-01-05", "2024-01-15", "2024-03-01", "2024-03-21"},
+			amounts: []int64{54000, 54000, 54000, 54000},
+		},
+		{
+			name:    "too few monthly charges",
+			dates:   []string{"2024-03-05", "2024-04-05"},
+			amounts: []int64{54000, 54000},
+		},
+		{
+			name:       "price increase within the tolerance",
+			dates:      []string{"2024-01-05", "2024-02-05", "2024-03-05", "2024-04-05"},
+			amounts:    []int64{54000, 54000, 54000, 65000},
+			want:       true,
+			frequency:  constants.PeriodTypeMonthly,
+			yearlyCost: 780000,
+			active:     true,
+			increase: &responses.PriceIncrease{
+				PreviousAmount: 54000, NewAmount: 65000, ChangePercent: 20.4, ChangedAt: subscriptionDate("2024-04-05"),
+			},
+		},
+		{
+			name:       "one price step beyond the tolerance",
+			dates:      []string{"2023-12-05", "2024-01-05", "2024-02-05", "2024-03-05", "2024-04-05"},
+			amounts:    []int64{54000, 54000, 54000, 86000, 86000},
+			want:       true,
+			frequency:  constants.PeriodTypeMonthly,
+			yearlyCost: 1032000,
+			active:     true,
+			increase: &responses.PriceIncrease{
+				PreviousAmount: 54000, NewAmount: 86000, ChangePercent: 59.3, ChangedAt: subscriptionDate("2024-03-05"),
+			},
+		},
+		{
+			name:       "one price cut beyond the tolerance",
+			dates:      []string{"2024-01-05", "2024-02-05", "2024-03-05", "2024-04-05"},
+			amounts:    []int64{186000, 186000, 54000, 54000},
+			want:       true,
+			frequency:  constants.PeriodTypeMonthly,
+			yearlyCost: 648000,
+			active:     true,
+		},
+		{
+			name:    "amounts that keep jumping",
+			dates:   []string{"2024-01-05", "2024-02-05", "2024-03-05", "2024-04-05"},
+			amounts: []int64{54000, 90000, 54000, 90000},
+		},
+	}
+
+	s := &subscriptionService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			group := &chargeGroup{charges: subscriptionCharges("NETFLIX.COM", tt.dates, tt.amounts)}
+			got := s.analyzeGroup(group, today)
+
+			if !tt.want {
+				if got != nil {
+					t.Fatalf("analyzeGroup() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("analyzeGroup() = nil, want a subscription")
+			}
+			if got.Frequency != tt.frequency {
+				t.Errorf("frequency = %s, want %s", got.Frequency, tt.frequency)
+			}
+			if got.EstimatedYearlyCost != tt.yearlyCost {
+				t.Errorf("yearly cost = %d, want %d", got.EstimatedYearlyCost, tt.yearlyCost)
+			}
+			if got.IsActive != tt.active {
+				t.Errorf("active = %v, want %v", got.IsActive, tt.active)
+			}
+			if got.Occurrences != len(tt.dates) || len(got.TransactionIds) != len(tt.dates) {
+				t.Errorf("occurrences = %d with %d ids, want %d", got.Occurrences, len(got.TransactionIds), len(tt.dates))
+			}
+			if !reflect.DeepEqual(got.PriceIncrease, tt.increase) {
+				t.Errorf("price increase = %+v, want %+v", got.PriceIncrease, tt.increase)
+			}
+		})
+	}
+}
+
+func TestDetectPriceIncrease(t *testing.T) {
+	dates := []string{"2024-01-05", "2024-02-05", "2024-03-05", "2024-04-05"}
+
+	tests := []struct {
+		name    string
+		amounts []int64
+		want    *responses.PriceIncrease
+	}{
+		{
+			name:    "steady price",
+			amounts: []int64{54000, 54000, 54000, 54000},
+		},
+		{
+			name:    "noise below the threshold",
+			amounts: []int64{54000, 54000, 54000, 55000},
+		},
+		{
+			name:    "price went down",
+			amounts: []int64{65000, 65000, 54000, 54000},
+		},
+		{
+			name:    "increase compared with the last different price",
+			amounts: []int64{49000, 54000, 65000, 65000},
+			want: &responses.PriceIncrease{
+				PreviousAmount: 54000, NewAmount: 65000, ChangePercent: 20.4, ChangedAt: subscriptionDate("2024-03-05"),
+			},
+		},
+	}
+
+	s := &subscriptionService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.detectPriceIncrease(subscriptionCharges("Netflix", dates, tt.amounts))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("detectPriceIncrease() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,297 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/openai/openai-go"
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/constants/prompt"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/log_message"
+	"github.com/saufiroja/fin-ai/internal/domains/tag"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/pkg/llm"
+	logging "github.com/saufiroja/fin-ai/pkg/loggings"
+)
+
+const maxTagSuggestions = 5
+
+var (
+	tagSeparatorPattern = regexp.MustCompile(`\s+`)
+	tagNamePattern      = regexp.MustCompile(`^[\p{L}\p{N}_-]{1,50}$`)
+)
+
+type tagService struct {
+	tagRepository     tag.TagStorer
+	openaiClient      llm.OpenAI
+	logMessageService log_message.LogMessageManager
+	logging           logging.Logger
+}
+
+func NewTagService(tagRepository tag.TagStorer, openaiClient llm.OpenAI, logMessageService log_message.LogMessageManager, logging logging.Logger) tag.TagManager {
+	return &tagService{
+		tagRepository:     tagRepository,
+		openaiClient:      openaiClient,
+		logMessageService: logMessageService,
+		logging:           logging,
+	}
+}
+
+// normalizeTagName lowercases the name and joins words with dashes, "Trip Bali 2026" becomes "trip-bali-2026"
+func normalizeTagName(name string) (string, error) {
+	normalized := tagSeparatorPattern.ReplaceAllString(strings.ToLower(strings.TrimSpace(name)), "-")
+	if !tagNamePattern.MatchString(normalized) {
+		return "", fmt.Errorf("%w: %q", tag.ErrInvalidTagName, name)
+	}
+	return normalized, nil
+}
+
+// normalizeTagNames normalizes and de-duplicates names while keeping their order
+func normalizeTagNames(names []string) ([]string, error) {
+	seen := make(map[string]bool, len(names))
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		normalized, err := normalizeTagName(name)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[normalized] {
+			seen[normalized] = true
+			result = append(result, normalized)
+		}
+	}
+	return result, nil
+}
+
+func (s *tagService) CreateTag(userId string, req *requests.TagRequest) (*models.Tag, error) {
+	name, err := normalizeTagName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	taken, err := s.tagRepository.IsTagNameTaken(userId, name, "")
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to check tag name for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to check tag name: %w", err)
+	}
+	if taken {
+		return nil, tag.ErrTagNameTaken
+	}
+
+	now := time.Now()
+	t := &models.Tag{
+		TagId:     ulid.Make().String(),
+		UserId:    userId,
+		Name:      name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.tagRepository.InsertTag(t); err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to insert tag for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to insert tag: %w", err)
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Tag %s created for user %s", t.Name, userId))
+	return t, nil
+}
+
+func (s *tagService) GetTags(userId string) ([]models.TagWithUsage, error) {
+	tags, err := s.tagRepository.GetTagsByUserId(userId)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get tags for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	if tags == nil {
+		tags = []models.TagWithUsage{}
+	}
+
+	return tags, nil
+}
+
+func (s *tagService) getTag(userId, tagId string) (*models.Tag, error) {
+	t, err := s.tagRepository.GetTagById(userId, tagId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, tag.ErrTagNotFound
+		}
+		s.logging.LogError(fmt.Sprintf("Failed to get tag %s: %v", tagId, err))
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+	return t, nil
+}
+
+func (s *tagService) UpdateTag(userId, tagId string, req *requests.TagRequest) (*models.Tag, error) {
+	t, err := s.getTag(userId, tagId)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := normalizeTagName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	taken, err := s.tagRepository.IsTagNameTaken(userId, name, tagId)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to check tag name for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to check tag name: %w", err)
+	}
+	if taken {
+		return nil, tag.ErrTagNameTaken
+	}
+
+	t.Name = name
+	t.UpdatedAt = time.Now()
+	if err := s.tagRepository.UpdateTag(t); err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to update tag %s: %v", tagId, err))
+		return nil, fmt.Errorf("failed to update tag: %w", err)
+	}
+
+	return t, nil
+}
+
+func (s *tagService) DeleteTag(userId, tagId string) error {
+	if _, err := s.getTag(userId, tagId); err != nil {
+		return err
+	}
+
+	if err := s.tagRepository.DeleteTag(userId, tagId); err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to delete tag %s: %v", tagId, err))
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Tag %s deleted for user %s", tagId, userId))
+	return nil
+}
+
+func (s *tagService) SetTransactionTags(userId, transactionId string, req *requests.AssignTagsRequest) (*responses.TagAssignmentResponse, error) {
+	names, err := normalizeTagNames(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := s.tagRepository.TransactionExists(userId, transactionId)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to check transaction %s: %v", transactionId, err))
+		return nil, fmt.Errorf("failed to check transaction: %w", err)
+	}
+	if !exists {
+		return nil, tag.ErrTagTargetNotFound
+	}
+
+	if err := s.tagRepository.ReplaceTransactionTags(userId, transactionId, names); err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to tag transaction %s: %v", transactionId, err))
+		return nil, fmt.Errorf("failed to tag transaction: %w", err)
+	}
+
+	return &responses.TagAssignmentResponse{Tags: names}, nil
+}
+
+func (s *tagService) SetReceiptTags(userId, receiptId string, req *requests.AssignTagsRequest) (*responses.TagAssignmentResponse, error) {
+	names, err := normalizeTagNames(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := s.tagRepository.ReceiptExists(userId, receiptId)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to check receipt %s: %v", receiptId, err))
+		return nil, fmt.Errorf("failed to check receipt: %w", err)
+	}
+	if !exists {
+		return nil, tag.ErrTagTargetNotFound
+	}
+
+	if err := s.tagRepository.ReplaceReceiptTags(userId, receiptId, names); err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to tag receipt %s: %v", receiptId, err))
+		return nil, fmt.Errorf("failed to tag receipt: %w", err)
+	}
+
+	return &responses.TagAssignmentResponse{Tags: names}, nil
+}
+
+func (s *tagService) SuggestTags(userId string, req *requests.TagSuggestionRequest) (*responses.TagSuggestionResponse, error) {
+	existingTags, err := s.GetTags(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]bool, len(existingTags))
+	existingNames := make([]string, 0, len(existingTags))
+	for _, t := range existingTags {
+		existing[t.Name] = true
+		existingNames = append(existingNames, t.Name)
+	}
+
+	tagList := "none yet"
+	if len(existingNames) > 0 {
+		tagList = strings.Join(existingNames, ", ")
+	}
+
+	messagePrompt := []openai.ChatCompletionMessageParamUnion{
+		openai.SystemMessage(fmt.Sprintf(prompt.TagSuggestionSystemPromptTemplate, tagList)),
+		openai.UserMessage(req.Description),
+	}
+
+	schema := map[string]any{
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties": map[string]any{
+			"tags": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+		},
+		"required": []string{"tags"},
+	}
+
+	responseAi, err := s.openaiClient.SendChatWithSchema(context.Background(), "gpt-4o-mini", messagePrompt, "tag_suggestions", schema)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get tag suggestions: %v", err))
+		return nil, fmt.Errorf("failed to get tag suggestions: %w", err)
+	}
+	if responseAi == nil {
+		return nil, errors.New("failed to get tag suggestions: empty AI response")
+	}
+	s.logMessageService.LogUsage(userId, constants.TopicTagSuggestion, responseAi.Model, responseAi.InputToken, responseAi.OutputToken)
+	responseStr, ok := responseAi.Response.(string)
+	if !ok || responseStr == "" {
+		return nil, errors.New("failed to get tag suggestions: empty AI response")
+	}
+
+	var parsed struct {
+		Tags []string `json:"tags"`
+	}
+	if err := json.Unmarshal([]byte(responseStr), &parsed); err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to decode tag suggestions: %v", err))
+		return nil, fmt.Errorf("failed to decode tag suggestions: %w", err)
+	}
+
+	// Invalid names from the LLM are dropped rather than failing the whole request
+	suggestions := []responses.TagSuggestion{}
+	seen := make(map[string]bool)
+	for _, name := range parsed.Tags {
+		normalized, err := normalizeTagName(name)
+		if err != nil || seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		suggestions = append(suggestions, responses.TagSuggestion{
+			Name:     normalized,
+			Existing: existing[normalized],
+		})
+		if len(suggestions) == maxTagSuggestions {
+			break
+		}
+	}
+
+	return &responses.TagSuggestionResponse{Suggestions: suggestions}, nil
+}
@@ -0,0 +1,609 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"mime/multipart"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/account"
+	"github.com/saufiroja/fin-ai/internal/domains/categories"
+	"github.com/saufiroja/fin-ai/internal/domains/log_message"
+	"github.com/saufiroja/fin-ai/internal/domains/transaction_import"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/pkg/bankstatement"
+	"github.com/saufiroja/fin-ai/pkg/importer"
+	"github.com/saufiroja/fin-ai/pkg/llm"
+	logging "github.com/saufiroja/fin-ai/pkg/loggings"
+)
+
+type transactionImportService struct {
+	importRepository  transaction_import.TransactionImportStorer
+	categoryService   categories.CategoryManager
+	accountService    account.AccountManager
+	openaiClient      llm.OpenAI
+	logMessageService log_message.LogMessageManager
+	logging           logging.Logger
+}
+
+func NewTransactionImportService(
+	importRepository transaction_import.TransactionImportStorer,
+	categoryService categories.CategoryManager,
+	accountService account.AccountManager,
+	openaiClient llm.OpenAI,
+	logMessageService log_message.LogMessageManager,
+	logging logging.Logger,
+) transaction_import.TransactionImportManager {
+	return &transactionImportService{
+		importRepository:  importRepository,
+		categoryService:   categoryService,
+		accountService:    accountService,
+		openaiClient:      openaiClient,
+		logMessageService: logMessageService,
+		logging:           logging,
+	}
+}
+
+// importPlan is the dry-run outcome of an import, transactions holds the valid rows that are not duplicates
+type importPlan struct {
+	results      []responses.ImportRowResult
+	transactions []*models.Transaction
+	valid        int
+	duplicates   int
+	invalid      int
+}
+
+func (s *transactionImportService) UploadImport(userId string, file *multipart.FileHeader, format string) (*responses.ImportUploadResponse, error) {
+	s.logging.LogInfo(fmt.Sprintf("Uploading import file %s for user %s", file.Filename, userId))
+
+	if file.Size > constants.ImportMaxFileSize {
+		return nil, fmt.Errorf("%w: file exceeds the maximum size of %d MB", transaction_import.ErrInvalidImport, constants.ImportMaxFileSize/(1024*1024))
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to open import file %s: %v", file.Filename, err))
+		return nil, fmt.Errorf("failed to open import file: %w", err)
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(src)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to read import file %s: %v", file.Filename, err))
+		return nil, fmt.Errorf("failed to read import file: %w", err)
+	}
+
+	table, fileType, statementFormat, err := s.readImportFile(file.Filename, data, format)
+	if err != nil {
+		return nil, err
+	}
+	if len(table.Rows) == 0 {
+		return nil, fmt.Errorf("%w: file has no data rows", transaction_import.ErrInvalidImport)
+	}
+	if len(table.Rows) > constants.ImportMaxRows {
+		return nil, fmt.Errorf("%w: file has more than %d rows", transaction_import.ErrInvalidImport, constants.ImportMaxRows)
+	}
+
+	now := time.Now()
+	imp := &models.TransactionImport{
+		ImportId:        ulid.Make().String(),
+		UserId:          userId,
+		FileName:        file.Filename,
+		FileType:        fileType,
+		StatementFormat: statementFormat,
+		Status:          constants.ImportStatusPending,
+		Headers:         table.Headers,
+		Rows:            table.Rows,
+		TotalRows:       len(table.Rows),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	if err := s.importRepository.InsertImport(imp); err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to insert import for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to insert import: %w", err)
+	}
+
+	sampleRows := table.Rows
+	if len(sampleRows) > constants.ImportSampleRows {
+		sampleRows = sampleRows[:constants.ImportSampleRows]
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Import %s uploaded with %d rows", imp.ImportId, imp.TotalRows))
+	return &responses.ImportUploadResponse{
+		TransactionImport: *imp,
+		DetectedMapping:   importer.DetectMapping(table.Headers),
+		SampleRows:        sampleRows,
+	}, nil
+}
+
+// readImportFile parses a bank statement when the format is given or detected, otherwise the file is read
+// as a generic CSV/XLSX sheet. It returns the table, the file type and the statement format.
+func (s *transactionImportService) readImportFile(fileName string, data []byte, format string) (*importer.Table, string, string, error) {
+	statementFormat := bankstatement.Format(format)
+	if format == "" {
+		detected, err := bankstatement.Detect(fileName, data)
+		if err != nil && !errors.Is(err, bankstatement.ErrUnknownFormat) {
+			return nil, "", "", fmt.Errorf("%w: %v", transaction_import.ErrInvalidImport, err)
+		}
+		statementFormat = detected
+	} else if !statementFormat.Valid() {
+		return nil, "", "", fmt.Errorf("%w: unknown statement format %q", transaction_import.ErrInvalidImport, format)
+	}
+
+	if statementFormat != "" {
+		statement, err := bankstatement.Parse(statementFormat, data)
+		if err != nil {
+			return nil, "", "", fmt.Errorf("%w: %v", transaction_import.ErrInvalidImport, err)
+		}
+		fileType := statementFormat.FileType()
+		if !bytes.HasPrefix(data, []byte("%PDF")) && fileType == "pdf" {
+			fileType = "txt"
+		}
+		s.logging.LogInfo(fmt.Sprintf("Parsed %s statement of account %s with %d transactions", statementFormat, statement.AccountNumber, len(statement.Transactions)))
+		return statement.Table(), fileType, string(statementFormat), nil
+	}
+
+	fileType, err := importer.FileType(fileName)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("%w: %v", transaction_import.ErrInvalidImport, err)
+	}
+	table, err := importer.Read(fileType, bytes.NewReader(data))
+	if err != nil {
+		return nil, "", "", fmt.Errorf("%w: %v", transaction_import.ErrInvalidImport, err)
+	}
+	return table, fileType, "", nil
+}
+
+func (s *transactionImportService) GetImports(userId string, req *requests.ImportQuery) (*responses.TransactionImportsResponse, error) {
+	offset := 0
+	if req.Offset > 1 {
+		offset = (req.Offset - 1) * req.Limit
+	}
+
+	imports, err := s.importRepository.GetImports(userId, req.Status, req.Limit, offset)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get imports for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to get imports: %w", err)
+	}
+
+	count, err := s.importRepository.CountImports(userId, req.Status)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to count imports for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to count imports: %w", err)
+	}
+
+	if imports == nil {
+		imports = []models.TransactionImport{}
+	}
+
+	totalPages := math.Ceil(float64(count) / float64(req.Limit))
+	currentPage := math.Min(float64(req.Offset), totalPages)
+
+	return &responses.TransactionImportsResponse{
+		TotalPages:  int64(totalPages),
+		CurrentPage: int64(currentPage),
+		Total:       count,
+		Imports:     imports,
+	}, nil
+}
+
+func (s *transactionImportService) GetImportById(userId, importId string) (*models.TransactionImport, error) {
+	imp, err := s.importRepository.GetImportById(userId, importId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, transaction_import.ErrImportNotFound
+		}
+		s.logging.LogError(fmt.Sprintf("Failed to get import %s: %v", importId, err))
+		return nil, fmt.Errorf("failed to get import: %w", err)
+	}
+	return imp, nil
+}
+
+func (s *transactionImportService) getPendingImport(userId, importId string) (*models.TransactionImport, error) {
+	imp, err := s.GetImportById(userId, importId)
+	if err != nil {
+		return nil, err
+	}
+	if imp.Status != constants.ImportStatusPending {
+		return nil, transaction_import.ErrImportNotPending
+	}
+	return imp, nil
+}
+
+func (s *transactionImportService) ValidateImport(userId, importId string, req *requests.ImportMappingRequest) (*responses.ImportValidationResponse, error) {
+	imp, err := s.getPendingImport(userId, importId)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := s.planImport(imp, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Problem rows are always listed, valid rows only as a short preview
+	rows := []responses.ImportRowResult{}
+	previewed := 0
+	for _, result := range plan.results {
+		if result.Status == constants.ImportRowValid {
+			if previewed == constants.ImportPreviewValidRows {
+				continue
+			}
+			previewed++
+		}
+		rows = append(rows, result)
+	}
+
+	return &responses.ImportValidationResponse{
+		ImportId:      imp.ImportId,
+		TotalRows:     imp.TotalRows,
+		ValidRows:     plan.valid,
+		DuplicateRows: plan.duplicates,
+		InvalidRows:   plan.invalid,
+		Rows:          rows,
+	}, nil
+}
+
+func (s *transactionImportService) CommitImport(userId, importId string, req *requests.CommitImportRequest) (*models.TransactionImport, error) {
+	s.logging.LogInfo(fmt.Sprintf("Committing import %s for user %s", importId, userId))
+
+	imp, err := s.getPendingImport(userId, importId)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := s.planImport(imp, &req.ImportMappingRequest)
+	if err != nil {
+		return nil, err
+	}
+	if plan.invalid > 0 && !req.SkipInvalid {
+		return nil, fmt.Errorf("%w: %d of %d rows", transaction_import.ErrImportHasInvalidRows, plan.invalid, imp.TotalRows)
+	}
+
+	if err := s.embedDescriptions(userId, plan.transactions); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	imp.Status = constants.ImportStatusCommitted
+	imp.Mapping = &req.Mapping
+	imp.ImportedRows = len(plan.transactions)
+	imp.DuplicateRows = plan.duplicates
+	imp.InvalidRows = plan.invalid
+	imp.CommittedAt = &now
+	imp.UpdatedAt = now
+
+	if err := s.importRepository.CommitImport(imp, plan.transactions); err != nil {
+		if errors.Is(err, transaction_import.ErrImportNotPending) {
+			return nil, err
+		}
+		s.logging.LogError(fmt.Sprintf("Failed to commit import %s: %v", importId, err))
+		return nil, fmt.Errorf("failed to commit import: %w", err)
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Import %s committed: %d imported, %d duplicates, %d invalid", importId, imp.ImportedRows, imp.DuplicateRows, imp.InvalidRows))
+	return imp, nil
+}
+
+func (s *transactionImportService) RevertImport(userId, importId string) (*models.TransactionImport, error) {
+	imp, err := s.GetImportById(userId, importId)
+	if err != nil {
+		return nil, err
+	}
+	if imp.Status != constants.ImportStatusCommitted {
+		return nil, transaction_import.ErrImportNotCommitted
+	}
+
+	deleted, err := s.importRepository.RevertImport(userId, importId, time.Now())
+	if err != nil {
+		if errors.Is(err, transaction_import.ErrImportNotCommitted) {
+			return nil, err
+		}
+		s.logging.LogError(fmt.Sprintf("Failed to revert import %s: %v", importId, err))
+		return nil, fmt.Errorf("failed to revert import: %w", err)
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Import %s reverted, %d transactions deleted", importId, deleted))
+	return s.GetImportById(userId, importId)
+}
+
+// embedDescriptions fills the description embeddings in batches, one failed batch fails the commit
+func (s *transactionImportService) embedDescriptions(userId string, transactions []*models.Transaction) error {
+	for start := 0; start < len(transactions); start += constants.ImportEmbeddingBatchSize {
+		end := min(start+constants.ImportEmbeddingBatchSize, len(transactions))
+		batch := transactions[start:end]
+
+		descriptions := make([]string, len(batch))
+		for i, transaction := range batch {
+			descriptions[i] = transaction.Description
+		}
+
+		embeddings := s.openaiClient.CreateBatchEmbedding(context.Background(), descriptions)
+		if embeddings == nil || len(embeddings.Embeddings) != len(batch) {
+			s.logging.LogError(fmt.Sprintf("Failed to create embeddings for import rows %d-%d", start+1, end))
+			return fmt.Errorf("failed to create embeddings for imported transactions")
+		}
+		s.logMessageService.LogUsage(userId, constants.TopicImportEmbedding, embeddings.Model, embeddings.InputToken, 0)
+		for i, transaction := range batch {
+			transaction.DescriptionEmbedding = embeddings.Embeddings[i]
+		}
+	}
+	return nil
+}
+
+// importDuplicateKey identifies a transaction by day, type, amount and description
+func importDuplicateKey(date time.Time, txType constants.TypeCategory, amount int64, description string) string {
+	return fmt.Sprintf("%s|%s|%d|%s", date.Format("2006-01-02"), txType, amount, strings.ToLower(strings.TrimSpace(description)))
+}
+
+// planImport validates every row against the mapping and marks rows that already exist as duplicates.
+// Rows with an external ID are duplicates when the ID was imported before or repeats in the file. Other
+// identical rows inside the file are kept, each existing transaction only cancels out one of them.
+func (s *transactionImportService) planImport(imp *models.TransactionImport, req *requests.ImportMappingRequest) (*importPlan, error) {
+	columns, err := req.Mapping.Resolve(imp.Headers)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", transaction_import.ErrInvalidImport, err)
+	}
+
+	if req.AccountId != "" {
+		if _, err := s.accountService.GetAccountById(imp.UserId, req.AccountId); err != nil {
+			return nil, err
+		}
+	}
+
+	categoryList, err := s.categoryService.FindAllCategories(&requests.GetAllCategoryQuery{Limit: 100, Offset: 0})
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get categories: %v", err))
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+	categoriesByName := make(map[string]models.Category, len(categoryList.Categories))
+	categoriesById := make(map[string]models.Category, len(categoryList.Categories))
+	for _, category := range categoryList.Categories {
+		categoriesByName[strings.ToLower(strings.TrimSpace(category.Name))] = category
+		categoriesById[category.CategoryId] = category
+	}
+
+	defaults := map[constants.TypeCategory]models.Category{}
+	for txType, categoryId := range map[constants.TypeCategory]string{
+		constants.ExpenseCategory: req.DefaultExpenseCategoryId,
+		constants.IncomeCategory:  req.DefaultIncomeCategoryId,
+	} {
+		if categoryId == "" {
+			continue
+		}
+		category, ok := categoriesById[categoryId]
+		if !ok || category.Type != txType {
+			return nil, fmt.Errorf("%w: default %s category must be an existing %s category", transaction_import.ErrInvalidImport, txType, txType)
+		}
+		defaults[txType] = category
+	}
+
+	now := time.Now()
+	plan := &importPlan{results: make([]responses.ImportRowResult, len(imp.Rows))}
+	rowTransactions := make([]*models.Transaction, len(imp.Rows))
+	var minDate, maxDate time.Time
+	var externalIds []string
+
+	for i, row := range imp.Rows {
+		result := responses.ImportRowResult{Row: i + 1}
+		cell := func(field string) string {
+			if columns[field] < 0 {
+				return ""
+			}
+			return row[columns[field]]
+		}
+
+		date, err := importer.ParseDate(cell("date"), req.DateFormat)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("date: %v", err))
+		}
+
+		description := strings.TrimSpace(cell("description"))
+		if description == "" {
+			result.Errors = append(result.Errors, "description: value is empty")
+		}
+
+		txType, amount, err := importRowAmount(cell, columns["amount"] >= 0)
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+		}
+
+		var category models.Category
+		confirmed := false
+		if txType != "" {
+			name := strings.TrimSpace(cell("category"))
+			found, ok := categoriesByName[strings.ToLower(name)]
+			switch {
+			case ok && found.Type == txType:
+				category, confirmed = found, true
+			case defaults[txType].CategoryId != "":
+				category = defaults[txType]
+			case name == "":
+				result.Errors = append(result.Errors, fmt.Sprintf("category: value is empty and no default %s category is set", txType))
+			case ok:
+				result.Errors = append(result.Errors, fmt.Sprintf("category: %q is not an %s category", name, txType))
+			default:
+				result.Errors = append(result.Errors, fmt.Sprintf("category: unknown category %q", name))
+			}
+		}
+
+		if len(result.Errors) > 0 {
+			result.Status = constants.ImportRowInvalid
+			plan.results[i] = result
+			plan.invalid++
+			continue
+		}
+
+		source := strings.TrimSpace(cell("source"))
+		if source == "" {
+			source = constants.ImportSource
+		}
+
+		transaction := &models.Transaction{
+			TransactionId:   ulid.Make().String(),
+			UserId:          imp.UserId,
+			CategoryId:      category.CategoryId,
+			Type:            txType,
+			Description:     description,
+			Amount:          amount,
+			Source:          source,
+			TransactionDate: date,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+			// Rows that fell back to a default category are left for the user to confirm
+			Confirmed:     confirmed,
+			PaymentMethod: strings.TrimSpace(cell("payment_method")),
+			AccountId:     req.AccountId,
+			ExternalId:    strings.TrimSpace(cell("external_id")),
+		}
+		rowTransactions[i] = transaction
+		if transaction.ExternalId != "" {
+			externalIds = append(externalIds, transaction.ExternalId)
+		}
+
+		result.Status = constants.ImportRowValid
+		result.Transaction = &responses.ImportRowTransaction{
+			TransactionDate: transaction.TransactionDate,
+			Description:     transaction.Description,
+			Amount:          transaction.Amount,
+			Type:            transaction.Type,
+			CategoryId:      category.CategoryId,
+			CategoryName:    category.Name,
+			PaymentMethod:   transaction.PaymentMethod,
+			Source:          transaction.Source,
+		}
+		plan.results[i] = result
+
+		if minDate.IsZero() || date.Before(minDate) {
+			minDate = date
+		}
+		if date.After(maxDate) {
+			maxDate = date
+		}
+	}
+
+	existing := map[string]int{}
+	if !minDate.IsZero() {
+		start := time.Date(minDate.Year(), minDate.Month(), minDate.Day(), 0, 0, 0, 0, minDate.Location())
+		end := time.Date(maxDate.Year(), maxDate.Month(), maxDate.Day()+1, 0, 0, 0, 0, maxDate.Location())
+		transactions, err := s.importRepository.GetExistingTransactions(imp.UserId, start, end)
+		if err != nil {
+			s.logging.LogError(fmt.Sprintf("Failed to get existing transactions for import %s: %v", imp.ImportId, err))
+			return nil, fmt.Errorf("failed to check duplicates: %w", err)
+		}
+		for _, t := range transactions {
+			existing[importDuplicateKey(t.TransactionDate, t.Type, t.Amount, t.Description)]++
+		}
+	}
+
+	// Rows with an external ID are matched by it first, the fingerprint it shares with the stored
+	// transaction is consumed so it does not cancel out another row
+	knownIds := map[string]bool{}
+	if len(externalIds) > 0 {
+		ids, err := s.importRepository.GetExistingExternalIds(imp.UserId, externalIds)
+		if err != nil {
+			s.logging.LogError(fmt.Sprintf("Failed to get existing external IDs for import %s: %v", imp.ImportId, err))
+			return nil, fmt.Errorf("failed to check duplicates: %w", err)
+		}
+		for _, id := range ids {
+			knownIds[id] = true
+		}
+	}
+
+	for i, transaction := range rowTransactions {
+		if transaction == nil {
+			continue
+		}
+		key := importDuplicateKey(transaction.TransactionDate, transaction.Type, transaction.Amount, transaction.Description)
+		if transaction.ExternalId != "" && knownIds[transaction.ExternalId] {
+			if existing[key] > 0 {
+				existing[key]--
+			}
+			plan.results[i].Status = constants.ImportRowDuplicate
+			plan.duplicates++
+			continue
+		}
+		if existing[key] > 0 {
+			existing[key]--
+			plan.results[i].Status = constants.ImportRowDuplicate
+			plan.duplicates++
+			continue
+		}
+		if transaction.ExternalId != "" {
+			knownIds[transaction.ExternalId] = true
+		}
+		plan.valid++
+		plan.transactions = append(plan.transactions, transaction)
+	}
+
+	return plan, nil
+}
+
+// importRowAmount returns the type and positive amount of a row from either the signed amount column,
+// optionally overridden by the type column, or the debit and credit columns
+func importRowAmount(cell func(field string) string, signed bool) (constants.TypeCategory, int64, error) {
+	if signed {
+		amount, err := importer.ParseAmount(cell("amount"))
+		if err != nil {
+			return "", 0, fmt.Errorf("amount: %v", err)
+		}
+		if amount == 0 {
+			return "", 0, errors.New("amount: value is zero")
+		}
+
+		txType := constants.IncomeCategory
+		if amount < 0 {
+			txType = constants.ExpenseCategory
+		}
+		if value := cell("type"); strings.TrimSpace(value) != "" {
+			direction, err := importer.ParseDirection(value)
+			if err != nil {
+				return "", 0, fmt.Errorf("type: %v %q", err, value)
+			}
+			txType = constants.IncomeCategory
+			if direction == importer.DirectionExpense {
+				txType = constants.ExpenseCategory
+			}
+		}
+
+		return txType, absAmount(amount), nil
+	}
+
+	debit, debitErr := importer.ParseAmount(cell("debit"))
+	if debitErr != nil && !errors.Is(debitErr, importer.ErrEmptyValue) {
+		return "", 0, fmt.Errorf("debit: %v", debitErr)
+	}
+	credit, creditErr := importer.ParseAmount(cell("credit"))
+	if creditErr != nil && !errors.Is(creditErr, importer.ErrEmptyValue) {
+		return "", 0, fmt.Errorf("credit: %v", creditErr)
+	}
+
+	switch {
+	case debit != 0 && credit != 0:
+		return "", 0, errors.New("amount: both debit and credit have a value")
+	case debit != 0:
+		return constants.ExpenseCategory, absAmount(debit), nil
+	case credit != 0:
+		return constants.IncomeCategory, absAmount(credit), nil
+	default:
+		return "", 0, errors.New("amount: debit and credit are empty")
+	}
+}
+
+func absAmount(amount int64) int64 {
+	if amount < 0 {
+		return -amount
+	}
+	return amount
+}
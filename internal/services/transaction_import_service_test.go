@@ -0,0 +1,286 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/categories"
+	"github.com/saufiroja/fin-ai/internal/domains/transaction_import"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/pkg/importer"
+	logging "github.com/saufiroja/fin-ai/pkg/loggings"
+)
+
+type fakeImportStorer struct {
+	transaction_import.TransactionImportStorer
+	existing    []models.Transaction
+	externalIds []string
+}
+
+func (f *fakeImportStorer) GetExistingTransactions(userId string, start, end time.Time) ([]models.Transaction, error) {
+	return f.existing, nil
+}
+
+func (f *fakeImportStorer) GetExistingExternalIds(userId string, externalIds []string) ([]string, error) {
+	return f.externalIds, nil
+}
+
+type fakeImportCategories struct {
+	categories.CategoryManager
+}
+
+func (fakeImportCategories) FindAllCategories(req *requests.GetAllCategoryQuery) (responses.GetAllCategoryResponse, error) {
+	return responses.GetAllCategoryResponse{
+		Categories: []models.Category{
+			{CategoryId: "01JB2Q4M1Q6T9W3Y7A2C5E8G0J", Name: "Makanan & Minuman", Type: constants.ExpenseCategory},
+			{CategoryId: "01JB2Q4P8S1W4Y7A0C3E6G9J2M", Name: "Gaji", Type: constants.IncomeCategory},
+		},
+	}, nil
+}
+
+func importCell(values map[string]string) func(field string) string {
+	return func(field string) string { return values[field] }
+}
+
+func TestImportRowAmount(t *testing.T) {
+	tests := []struct {
+		name    string
+		signed  bool
+		values  map[string]string
+		txType  constants.TypeCategory
+		amount  int64
+		wantErr bool
+	}{
+		{name: "negative signed amount", signed: true, values: map[string]string{"amount": "-50.000"}, txType: constants.ExpenseCategory, amount: 50000},
+		{name: "positive signed amount", signed: true, values: map[string]string{"amount": "1.250.000"}, txType: constants.IncomeCategory, amount: 1250000},
+		{name: "debit suffix", signed: true, values: map[string]string{"amount": "50.000 DB"}, txType: constants.ExpenseCategory, amount: 50000},
+		{name: "type column overrides the sign", signed: true, values: map[string]string{"amount": "50000", "type": "DB"}, txType: constants.ExpenseCategory, amount: 50000},
+		{name: "type column marks income", signed: true, values: map[string]string{"amount": "-50000", "type": "Kredit"}, txType: constants.IncomeCategory, amount: 50000},
+		{name: "unknown type", signed: true, values: map[string]string{"amount": "50000", "type": "transfer"}, wantErr: true},
+		{name: "zero amount", signed: true, values: map[string]string{"amount": "0"}, wantErr: true},
+		{name: "invalid amount", signed: true, values: map[string]string{"amount": "lima puluh"}, wantErr: true},
+		{name: "debit column", values: map[string]string{"debit": "75.000"}, txType: constants.ExpenseCategory, amount: 75000},
+		{name: "credit column", values: map[string]string{"credit": "Rp 2.000.000,00"}, txType: constants.IncomeCategory, amount: 2000000},
+		{name: "dash in the other column", values: map[string]string{"debit": "-", "credit": "10.000"}, txType: constants.IncomeCategory, amount: 10000},
+		{name: "both debit and credit", values: map[string]string{"debit": "10.000", "credit": "10.000"}, wantErr: true},
+		{name: "neither debit nor credit", values: map[string]string{}, wantErr: true},
+		{name: "invalid debit", values: map[string]string{"debit": "abc", "credit": "10.000"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txType, amount, err := importRowAmount(importCell(tt.values), tt.signed)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("importRowAmount() = %s %d, want an error", txType, amount)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("importRowAmount() error = %v", err)
+			}
+			if txType != tt.txType || amount != tt.amount {
+				t.Errorf("importRowAmount() = %s %d, want %s %d", txType, amount, tt.txType, tt.amount)
+			}
+		})
+	}
+}
+
+func TestImportDuplicateKey(t *testing.T) {
+	base := importDuplicateKey(time.Date(2024, 4, 5, 0, 0, 0, 0, time.UTC), constants.ExpenseCategory, 50000, "Kopi Kenangan")
+
+	tests := []struct {
+		name string
+		key  string
+		same bool
+	}{
+		{"time of day is ignored", importDuplicateKey(time.Date(2024, 4, 5, 14, 30, 0, 0, time.UTC), constants.ExpenseCategory, 50000, "Kopi Kenangan"), true},
+		{"case and surrounding spaces are ignored", importDuplicateKey(time.Date(2024, 4, 5, 0, 0, 0, 0, time.UTC), constants.ExpenseCategory, 50000, "  kopi kenangan "), true},
+		{"other day", importDuplicateKey(time.Date(2024, 4, 6, 0, 0, 0, 0, time.UTC), constants.ExpenseCategory, 50000, "Kopi Kenangan"), false},
+		{"other type", importDuplicateKey(time.Date(2024, 4, 5, 0, 0, 0, 0, time.UTC), constants.IncomeCategory, 50000, "Kopi Kenangan"), false},
+		{"other amount", importDuplicateKey(time.Date(2024, 4, 5, 0, 0, 0, 0, time.UTC), constants.ExpenseCategory, 50001, "Kopi Kenangan"), false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.key == base; got != tt.same {
+			t.Errorf("%s: key %q equal to %q = %v, want %v", tt.name, tt.key, base, got, tt.same)
+		}
+	}
+}
+
+func TestPlanImport(t *testing.T) {
+	const (
+		valid     = constants.ImportRowValid
+		duplicate = constants.ImportRowDuplicate
+		invalid   = constants.ImportRowInvalid
+	)
+	signedHeaders := []string{"Tanggal", "Keterangan", "Jumlah", "Kategori", "ID Transaksi"}
+	signedMapping := importer.Mapping{Date: "Tanggal", Description: "Keterangan", Amount: "Jumlah", Category: "Kategori", ExternalId: "ID Transaksi"}
+	stored := models.Transaction{
+		Type:            constants.ExpenseCategory,
+		Description:     "Kopi Kenangan",
+		Amount:          28000,
+		TransactionDate: time.Date(2024, 4, 5, 9, 15, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name        string
+		headers     []string
+		mapping     importer.Mapping
+		rows        [][]string
+		existing    []models.Transaction
+		externalIds []string
+		want        []constants.ImportRowStatus
+		wantTypes   []constants.TypeCategory
+	}{
+		{
+			name:    "in-file repeats without external IDs are kept",
+			headers: signedHeaders,
+			mapping: signedMapping,
+			rows: [][]string{
+				{"05/04/2024", "Parkir", "-5.000", "Makanan & Minuman", ""},
+				{"05/04/2024", "Parkir", "-5.000", "Makanan & Minuman", ""},
+			},
+			want: []constants.ImportRowStatus{valid, valid},
+		},
+		{
+			name:    "stored transaction cancels out one in-file repeat",
+			headers: signedHeaders,
+			mapping: signedMapping,
+			rows: [][]string{
+				{"05/04/2024", "Kopi Kenangan", "-28.000", "Makanan & Minuman", ""},
+				{"05/04/2024", "kopi kenangan", "-28.000", "Makanan & Minuman", ""},
+			},
+			existing: []models.Transaction{stored},
+			want:     []constants.ImportRowStatus{duplicate, valid},
+		},
+		{
+			name:    "external ID already imported",
+			headers: signedHeaders,
+			mapping: signedMapping,
+			rows: [][]string{
+				{"06/04/2024", "Transfer Gaji", "8.500.000", "Gaji", "TX-001"},
+				{"06/04/2024", "Makan Siang", "-45.000", "Makanan & Minuman", "TX-002"},
+			},
+			externalIds: []string{"TX-001"},
+			want:        []constants.ImportRowStatus{duplicate, valid},
+		},
+		{
+			name:    "external ID repeated in the file",
+			headers: signedHeaders,
+			mapping: signedMapping,
+			rows: [][]string{
+				{"06/04/2024", "Makan Siang", "-45.000", "Makanan & Minuman", "TX-002"},
+				{"07/04/2024", "Makan Malam", "-60.000", "Makanan & Minuman", "TX-002"},
+			},
+			want: []constants.ImportRowStatus{valid, duplicate},
+		},
+		{
+			name:    "fingerprint shared with a stored row",
+			headers: signedHeaders,
+			mapping: signedMapping,
+			rows: [][]string{
+				{"05/04/2024", "Kopi Kenangan", "-28.000", "Makanan & Minuman", ""},
+			},
+			existing: []models.Transaction{stored},
+			want:     []constants.ImportRowStatus{duplicate},
+		},
+		{
+			name:    "external ID match consumes the shared fingerprint",
+			headers: signedHeaders,
+			mapping: signedMapping,
+			rows: [][]string{
+				{"05/04/2024", "Kopi Kenangan", "-28.000", "Makanan & Minuman", "TX-003"},
+				{"05/04/2024", "Kopi Kenangan", "-28.000", "Makanan & Minuman", ""},
+			},
+			existing:    []models.Transaction{stored},
+			externalIds: []string{"TX-003"},
+			want:        []constants.ImportRowStatus{duplicate, valid},
+		},
+		{
+			name:    "invalid rows",
+			headers: signedHeaders,
+			mapping: signedMapping,
+			rows: [][]string{
+				{"31/02/2024", "Parkir", "-5.000", "Makanan & Minuman", ""},
+				{"05/04/2024", " ", "-5.000", "Makanan & Minuman", ""},
+				{"05/04/2024", "Parkir", "-5.000", "Transportasi", ""},
+				{"05/04/2024", "Bonus", "1.000.000", "Makanan & Minuman", ""},
+			},
+			want: []constants.ImportRowStatus{invalid, invalid, invalid, invalid},
+		},
+		{
+			name:    "signed amounts",
+			headers: signedHeaders,
+			mapping: signedMapping,
+			rows: [][]string{
+				{"05/04/2024", "Makan Siang", "-45.000", "Makanan & Minuman", ""},
+				{"05/04/2024", "Transfer Gaji", "8.500.000", "Gaji", ""},
+			},
+			want:      []constants.ImportRowStatus{valid, valid},
+			wantTypes: []constants.TypeCategory{constants.ExpenseCategory, constants.IncomeCategory},
+		},
+		{
+			name:    "debit and credit columns",
+			headers: []string{"Tanggal", "Keterangan", "Debet", "Kredit", "Kategori"},
+			mapping: importer.Mapping{Date: "Tanggal", Description: "Keterangan", Debit: "Debet", Credit: "Kredit", Category: "Kategori"},
+			rows: [][]string{
+				{"05/04/2024", "Makan Siang", "45.000", "", "Makanan & Minuman"},
+				{"05/04/2024", "Transfer Gaji", "", "8.500.000", "Gaji"},
+			},
+			want:      []constants.ImportRowStatus{valid, valid},
+			wantTypes: []constants.TypeCategory{constants.ExpenseCategory, constants.IncomeCategory},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewTransactionImportService(
+				&fakeImportStorer{existing: tt.existing, externalIds: tt.externalIds},
+				fakeImportCategories{},
+				nil,
+				nil,
+				nil,
+				logging.NewLogrusAdapter(),
+			).(*transactionImportService)
+
+			imp := &models.TransactionImport{
+				ImportId: "01JB2R7A8B9C0D1E2F3G4H5J6K",
+				UserId:   "01JB2Q4K7N3P8S2V5X9Z1B4D6F",
+				Headers:  tt.headers,
+				Rows:     tt.rows,
+			}
+			plan, err := s.planImport(imp, &requests.ImportMappingRequest{Mapping: tt.mapping})
+			if err != nil {
+				t.Fatalf("planImport() error = %v", err)
+			}
+
+			var got []constants.ImportRowStatus
+			counts := map[constants.ImportRowStatus]int{}
+			for _, result := range plan.results {
+				got = append(got, result.Status)
+				counts[result.Status]++
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("row statuses = %v, want %v", got, tt.want)
+			}
+			if plan.valid != counts[valid] || plan.duplicates != counts[duplicate] || plan.invalid != counts[invalid] || len(plan.transactions) != plan.valid {
+				t.Errorf("plan counts %d/%d/%d with %d transactions do not match the rows %v", plan.valid, plan.duplicates, plan.invalid, len(plan.transactions), counts)
+			}
+
+			if tt.wantTypes != nil {
+				var types []constants.TypeCategory
+				for _, transaction := range plan.transactions {
+					types = append(types, transaction.Type)
+				}
+				if !reflect.DeepEqual(types, tt.wantTypes) {
+					t.Errorf("transaction types = %v, want %v", types, tt.wantTypes)
+				}
+			}
+		})
+	}
+}
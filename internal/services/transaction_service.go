@@ -2,7 +2,11 @@ package services
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"math"
 	"strconv"
 	"strings"
@@ -12,38 +16,66 @@ import (
 	"github.com/oklog/ulid/v2"
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/packages/param"
+	"github.com/saufiroja/fin-ai/internal/constants"
 	"github.com/saufiroja/fin-ai/internal/constants/prompt"
 	"github.com/saufiroja/fin-ai/internal/contracts/requests"
 	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/account"
+	"github.com/saufiroja/fin-ai/internal/domains/anomaly"
+	"github.com/saufiroja/fin-ai/internal/domains/categories"
+	"github.com/saufiroja/fin-ai/internal/domains/currency"
+	"github.com/saufiroja/fin-ai/internal/domains/log_message"
 	"github.com/saufiroja/fin-ai/internal/domains/transaction"
 	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/internal/utils"
+	"github.com/saufiroja/fin-ai/pkg/exporter"
+	"github.com/saufiroja/fin-ai/pkg/fx"
 	"github.com/saufiroja/fin-ai/pkg/llm"
 	logging "github.com/saufiroja/fin-ai/pkg/loggings"
-	"golang.org/x/text/language"
-	"golang.org/x/text/message"
 )
 
 type transactionService struct {
 	transactionRepository transaction.TransactionStorer
+	categoryService       categories.CategoryManager
 	logging               logging.Logger
 	openaiClient          llm.OpenAI
+	logMessageService     log_message.LogMessageManager
+	anomalyService        anomaly.AnomalyManager
+	accountService        account.AccountManager
+	currencyService       currency.CurrencyManager
 }
 
 func NewTransactionService(
 	transactionRepository transaction.TransactionStorer,
+	categoryService categories.CategoryManager,
 	logging logging.Logger,
 	openaiClient llm.OpenAI,
+	logMessageService log_message.LogMessageManager,
+	anomalyService anomaly.AnomalyManager,
+	accountService account.AccountManager,
+	currencyService currency.CurrencyManager,
 ) transaction.TransactionManager {
 	return &transactionService{
 		transactionRepository: transactionRepository,
+		categoryService:       categoryService,
 		logging:               logging,
 		openaiClient:          openaiClient,
+		logMessageService:     logMessageService,
+		anomalyService:        anomalyService,
+		accountService:        accountService,
+		currencyService:       currencyService,
 	}
 }
 
 func (t *transactionService) GetAllTransactions(req *requests.GetAllTransactionsQuery, userId string) (*responses.GetAllTransactionsResponse, error) {
 	t.logging.LogInfo(fmt.Sprintf("Fetching all transactions with query: %+v", req))
 
+	tags, err := normalizeTagNames(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	req.Tags = tags
+
 	// Calculate offset for pagination (convert page-based to offset-based)
 	offset := 0
 	if req.Offset > 1 {
@@ -58,6 +90,8 @@ func (t *transactionService) GetAllTransactions(req *requests.GetAllTransactions
 		Search:     req.Search,
 		StartDate:  req.StartDate,
 		EndDate:    req.EndDate,
+		AccountId:  req.AccountId,
+		Tags:       req.Tags,
 	}
 
 	transactions, err := t.transactionRepository.GetAllTransactions(queryReq, userId)
@@ -73,6 +107,11 @@ func (t *transactionService) GetAllTransactions(req *requests.GetAllTransactions
 		return nil, err
 	}
 
+	if err := t.attachSplits(transactions); err != nil {
+		t.logging.LogError(fmt.Sprintf("Error fetching transaction splits: %v", err))
+		return nil, err
+	}
+
 	totalPages := math.Ceil(float64(count) / float64(req.Limit))
 	currentPage := math.Min(float64(req.Offset), float64(totalPages))
 
@@ -87,16 +126,106 @@ func (t *transactionService) GetAllTransactions(req *requests.GetAllTransactions
 	return res, nil
 }
 
-func (t *transactionService) DeleteTransaction(id string) error {
+// ExportTransactions checks the export request, the returned Write streams the matching transactions
+// straight from the database cursor into the chosen format
+func (t *transactionService) ExportTransactions(userId string, req *requests.ExportTransactionsQuery) (*responses.TransactionExport, error) {
+	t.logging.LogInfo(fmt.Sprintf("Exporting transactions for user %s with query: %+v", userId, req))
+
+	format, err := exporter.ParseFormat(req.Format)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := normalizeTagNames(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &requests.GetAllTransactionsQuery{
+		CategoryId: req.CategoryId,
+		Search:     req.Search,
+		StartDate:  req.StartDate,
+		EndDate:    req.EndDate,
+		AccountId:  req.AccountId,
+		Tags:       tags,
+	}
+
+	write := func(w io.Writer) error {
+		writer, err := exporter.NewWriter(format, w, constants.TransactionExportColumns)
+		if err != nil {
+			return err
+		}
+
+		count := 0
+		err = t.transactionRepository.ExportTransactions(filter, userId, func(row *models.TransactionExport) error {
+			count++
+			return writer.Write(transactionExportValues(row))
+		})
+		if err != nil {
+			t.logging.LogError(fmt.Sprintf("Error exporting transactions for user %s after %d rows: %v", userId, count, err))
+			return err
+		}
+		if err := writer.Close(); err != nil {
+			t.logging.LogError(fmt.Sprintf("Error finishing transaction export for user %s: %v", userId, err))
+			return err
+		}
+
+		t.logging.LogInfo(fmt.Sprintf("Exported %d transactions for user %s", count, userId))
+		return nil
+	}
+
+	return &responses.TransactionExport{
+		Format:   format,
+		FileName: fmt.Sprintf("transactions-%s.%s", time.Now().Format("20060102-150405"), format),
+		Write:    write,
+	}, nil
+}
+
+// transactionExportValues returns the values of a row in the order of constants.TransactionExportColumns
+func transactionExportValues(row *models.TransactionExport) []any {
+	receiptURL := ""
+	if row.ReceiptId != "" {
+		receiptURL = constants.ReceiptDetailPath + row.ReceiptId
+	}
+
+	return []any{
+		row.TransactionId,
+		row.TransactionDate.Format("2006-01-02"),
+		string(row.Type),
+		row.Amount,
+		row.Discount,
+		row.Description,
+		row.CategoryId,
+		row.CategoryName,
+		row.SplitLines,
+		row.PaymentMethod,
+		row.Source,
+		row.AccountId,
+		row.AccountName,
+		row.Tags,
+		row.ReceiptId,
+		receiptURL,
+		row.TransferId,
+		row.Confirmed,
+		row.CreatedAt.Format(time.RFC3339),
+		row.CurrencyCode,
+		row.OriginalAmount,
+	}
+}
+
+func (t *transactionService) DeleteTransaction(userId, id string) error {
 	t.logging.LogInfo(fmt.Sprintf("Deleting transaction with ID: %s", id))
 
-	_, err := t.GetDetailedTransaction(id)
+	existing, err := t.GetDetailedTransaction(id)
 	if err != nil {
 		t.logging.LogError(fmt.Sprintf("Transaction not found for deletion: %s", id))
 		return fmt.Errorf("transaction not found: %w", err)
 	}
+	if existing.UserId != userId {
+		t.logging.LogError(fmt.Sprintf("Transaction %s does not belong to user %s", id, userId))
+		return fmt.Errorf("transaction not found: %w", sql.ErrNoRows)
+	}
 
-	err = t.transactionRepository.DeleteTransaction(id)
+	err = t.transactionRepository.DeleteTransaction(userId, id)
 	if err != nil {
 		t.logging.LogError(fmt.Sprintf("Error deleting transaction: %v", err))
 		return fmt.Errorf("failed to delete transaction: %w", err)
@@ -118,17 +247,93 @@ func (t *transactionService) GetDetailedTransaction(id string) (*models.Transact
 		return nil, fmt.Errorf("transaction not found")
 	}
 
+	splits, err := t.transactionRepository.GetTransactionSplits([]string{id})
+	if err != nil {
+		t.logging.LogError(fmt.Sprintf("Error fetching splits of transaction %s: %v", id, err))
+		return nil, fmt.Errorf("failed to get transaction splits: %w", err)
+	}
+	transaction.Splits = splits
+
 	t.logging.LogInfo(fmt.Sprintf("Successfully fetched transaction with ID: %s", id))
 	return transaction, nil
 }
 
-func (t *transactionService) GetTransactionsStats() (*models.Transaction, error) {
-	panic("unimplemented")
+func (t *transactionService) GetTransactionsStats(userId string, req *requests.OverviewTransactionsQuery) (*responses.TransactionStatsResponse, error) {
+	start, end, err := utils.ResolveDateRange(req.StartDate, req.EndDate, time.Now())
+	if err != nil {
+		t.logging.LogError(fmt.Sprintf("Invalid date range for transaction stats: %v", err))
+		return nil, err
+	}
+
+	query := &requests.OverviewTransactionsQuery{
+		StartDate:  start.Format(utils.DateLayout),
+		EndDate:    end.Format(utils.DateLayout),
+		CategoryId: req.CategoryId,
+	}
+
+	stats, err := t.transactionRepository.GetTransactionsStats(userId, query)
+	if err != nil {
+		t.logging.LogError(fmt.Sprintf("Error getting transaction stats: %v", err))
+		return nil, fmt.Errorf("failed to get transaction stats: %w", err)
+	}
+
+	days := utils.DaysInRange(start, end)
+
+	base, err := t.currencyService.GetBaseCurrency(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses.TransactionStatsResponse{
+		Currency:          responses.NewCurrencyMeta(base),
+		StartDate:         query.StartDate,
+		EndDate:           query.EndDate,
+		Days:              days,
+		TotalIncome:       stats.TotalIncome,
+		TotalExpense:      stats.TotalExpense,
+		Net:               stats.TotalIncome - stats.TotalExpense,
+		TransactionCount:  stats.TransactionCount,
+		AverageDailySpend: stats.TotalExpense / int64(days),
+	}, nil
 }
 
 func (t *transactionService) InsertTransaction(req *requests.TransactionRequest) error {
 	t.logging.LogInfo(fmt.Sprintf("Inserting transaction: %+v", req))
 
+	// Transfer legs are only created together by the transfer service
+	if req.Type != constants.IncomeCategory && req.Type != constants.ExpenseCategory {
+		return transaction.ErrInvalidTransactionType
+	}
+
+	// The account must belong to the same user, checked before any LLM call is made
+	currencyCode := req.CurrencyCode
+	if req.AccountId != "" {
+		acc, err := t.accountService.GetAccountById(req.UserId, req.AccountId)
+		if err != nil {
+			return err
+		}
+		if currencyCode == "" {
+			currencyCode = acc.CurrencyCode
+		}
+	}
+
+	splits, err := t.buildSplits(req.UserId, req.Type, req.Amount, req.Splits)
+	if err != nil {
+		return err
+	}
+	rateDate := req.TransactionDate
+	if rateDate.IsZero() {
+		rateDate = time.Now()
+	}
+	conversion, discount, err := t.convertToBase(req.UserId, currencyCode, req.Amount, req.Discount, splits, rateDate)
+	if err != nil {
+		return err
+	}
+	// A split transaction without its own category is shown under its largest line
+	if req.CategoryId == "" && len(splits) > 0 {
+		req.CategoryId = largestSplitCategory(splits)
+	}
+
 	// Use channels to communicate between goroutines
 	embeddingChan := make(chan *responses.ResponseEmbedding)
 	confidenceChan := make(chan float64)
@@ -151,6 +356,7 @@ func (t *transactionService) InsertTransaction(req *requests.TransactionRequest)
 		embedding := t.openaiClient.CreateEmbedding(context.Background(), input)
 
 		if embedding != nil && embedding.Embeddings != "" {
+			t.logMessageService.LogUsage(req.UserId, constants.TopicTransactionEmbedding, embedding.Model, embedding.InputToken, 0)
 			embeddingChan <- embedding
 		} else {
 			errorChan <- fmt.Errorf("failed to create embedding")
@@ -193,7 +399,10 @@ func (t *transactionService) InsertTransaction(req *requests.TransactionRequest)
 		if err != nil {
 			t.logging.LogError(fmt.Sprintf("Error creating chat completion for confidence: %v", err))
 			confidenceChan <- 0.0 // Default confidence on error
+		} else if responseAi == nil {
+			confidenceChan <- 0.0
 		} else {
+			t.logMessageService.LogUsage(req.UserId, constants.TopicCategoryConfidence, responseAi.Model, responseAi.InputToken, responseAi.OutputToken)
 			// Parse the AI response to extract confidence score
 			if responseStr, ok := responseAi.Response.(string); ok && len(responseStr) > 0 {
 				if confidence, parseErr := t.parseConfidenceFromResponse(responseStr); parseErr == nil {
@@ -238,34 +447,180 @@ func (t *transactionService) InsertTransaction(req *requests.TransactionRequest)
 		}
 	}
 
+	// Internal callers (recurring rules, imports) may supply their own ID and date
+	transactionId := req.TransactionId
+	if transactionId == "" {
+		transactionId = ulid.Make().String()
+	}
+	transactionDate := req.TransactionDate
+	if transactionDate.IsZero() {
+		transactionDate = timestamp
+	}
+
 	transaction := &models.Transaction{
-		TransactionId:        ulid.Make().String(),
+		TransactionId:        transactionId,
 		UserId:               req.UserId,
 		CategoryId:           req.CategoryId,
 		Type:                 req.Type,
 		Description:          req.Description,
 		DescriptionEmbedding: embedding.Embeddings,
-		Amount:               req.Amount,
+		Amount:               conversion.Converted,
 		Source:               req.Source,
-		TransactionDate:      timestamp, // Using prepared timestamp
+		TransactionDate:      transactionDate,
 		AiCategoryConfidence: aiCategoryConfidence,
 		IsAutoCategorized:    req.IsAutoCategorized,
 		CreatedAt:            timestamp,
 		UpdatedAt:            timestamp,
 		Confirmed:            req.Confirmed,
-		Discount:             req.Discount,
+		Discount:             discount,
+		PaymentMethod:        req.PaymentMethod,
+		AccountId:            req.AccountId,
+		ReceiptId:            req.ReceiptId,
+		Splits:               splits,
+		CurrencyCode:         conversion.From.Code,
+		OriginalAmount:       originalAmount(conversion),
+		FxRate:               conversion.Rate.Value,
+	}
+	for i := range transaction.Splits {
+		transaction.Splits[i].TransactionId = transaction.TransactionId
+		transaction.Splits[i].CreatedAt = timestamp
 	}
 
-	err := t.transactionRepository.InsertTransaction(transaction)
+	err = t.transactionRepository.InsertTransaction(transaction)
 	if err != nil {
 		t.logging.LogError(fmt.Sprintf("Error inserting transaction: %v", err))
 		return err
 	}
 
 	t.logging.LogInfo(fmt.Sprintf("Transaction inserted successfully with ID: %s, AI confidence: %.2f", transaction.TransactionId, aiCategoryConfidence))
+
+	// Anomaly detection must not fail the insert, it only waits when the queue is full and no scan is scheduled
+	t.anomalyService.QueueCheck(transaction.TransactionId)
+
+	return nil
+}
+
+// buildSplits validates the split lines of a transaction, nil means the transaction is not split
+func (t *transactionService) buildSplits(userId string, txType constants.TypeCategory, amount int64, lines []requests.TransactionSplitRequest) ([]models.TransactionSplit, error) {
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	if len(lines) < 2 {
+		return nil, fmt.Errorf("%w: at least two lines are required, use category_id for a single category", transaction.ErrInvalidSplits)
+	}
+
+	categoryTypes := make(map[string]string)
+	splits := make([]models.TransactionSplit, 0, len(lines))
+	var total int64
+	for _, line := range lines {
+		if line.CategoryId == "" || line.Amount <= 0 {
+			return nil, fmt.Errorf("%w: every line needs a category_id and a positive amount", transaction.ErrInvalidSplits)
+		}
+		if len(line.Note) > 255 {
+			return nil, fmt.Errorf("%w: note must be at most 255 characters", transaction.ErrInvalidSplits)
+		}
+
+		categoryType, ok := categoryTypes[line.CategoryId]
+		if !ok {
+			category, err := t.categoryService.FindCategoryById(line.CategoryId)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("failed to get split category: %w", err)
+			}
+			if category == nil {
+				return nil, fmt.Errorf("%w: category %s not found", transaction.ErrInvalidSplits, line.CategoryId)
+			}
+			categoryType = string(category.Type)
+			categoryTypes[line.CategoryId] = categoryType
+		}
+		if categoryType != string(txType) {
+			return nil, fmt.Errorf("%w: category %s is not an %s category", transaction.ErrInvalidSplits, line.CategoryId, txType)
+		}
+
+		total += line.Amount
+		splits = append(splits, models.TransactionSplit{
+			SplitId:    ulid.Make().String(),
+			UserId:     userId,
+			CategoryId: line.CategoryId,
+			Amount:     line.Amount,
+			Note:       strings.TrimSpace(line.Note),
+		})
+	}
+
+	if total != amount {
+		return nil, fmt.Errorf("%w: lines add up to %d but the amount is %d", transaction.ErrInvalidSplits, total, amount)
+	}
+
+	return splits, nil
+}
+
+// convertToBase converts the amounts of a transaction paid in currencyCode into the user's base currency.
+// The split lines are converted in place and keep adding up to the converted amount.
+func (t *transactionService) convertToBase(userId, currencyCode string, amount, discount int64, splits []models.TransactionSplit, date time.Time) (*fx.Conversion, int64, error) {
+	conversion, err := t.currencyService.ToBase(context.Background(), userId, amount, currencyCode, date)
+	if err != nil {
+		return nil, 0, err
+	}
+	if conversion.From.Code == conversion.To.Code {
+		return conversion, discount, nil
+	}
+
+	parts := make([]int64, len(splits))
+	for i, split := range splits {
+		parts[i] = split.Amount
+	}
+	for i, converted := range fx.Distribute(parts, conversion.Converted) {
+		splits[i].Amount = converted
+	}
+
+	return conversion, fx.Convert(discount, conversion.From, conversion.To, conversion.Rate.Value), nil
+}
+
+// originalAmount is what was paid in a foreign currency, zero for a payment in the base currency so
+// original_amount stays NULL
+func originalAmount(conversion *fx.Conversion) int64 {
+	if conversion.From.Code == conversion.To.Code {
+		return 0
+	}
+	return conversion.Amount
+}
+
+// attachSplits loads the split lines of the given transactions in one query
+func (t *transactionService) attachSplits(transactions []models.Transaction) error {
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(transactions))
+	for _, tr := range transactions {
+		ids = append(ids, tr.TransactionId)
+	}
+
+	splits, err := t.transactionRepository.GetTransactionSplits(ids)
+	if err != nil {
+		return fmt.Errorf("failed to get transaction splits: %w", err)
+	}
+
+	byTransaction := make(map[string][]models.TransactionSplit)
+	for _, split := range splits {
+		byTransaction[split.TransactionId] = append(byTransaction[split.TransactionId], split)
+	}
+	for i := range transactions {
+		transactions[i].Splits = byTransaction[transactions[i].TransactionId]
+	}
+
 	return nil
 }
 
+func largestSplitCategory(splits []models.TransactionSplit) string {
+	largest := splits[0]
+	for _, split := range splits[1:] {
+		if split.Amount > largest.Amount {
+			largest = split
+		}
+	}
+	return largest.CategoryId
+}
+
 func (t *transactionService) parseConfidenceFromResponse(response string) (float64, error) {
 	// Remove any whitespace and parse as float
 	response = strings.TrimSpace(response)
@@ -298,6 +653,57 @@ func (t *transactionService) UpdateTransaction(transactionId string, req *reques
 		return fmt.Errorf("transaction not found for update")
 	}
 
+	if existingTransaction.TransferId != "" {
+		return transaction.ErrTransferLegUpdate
+	}
+
+	if req.AccountId != "" {
+		if _, err := t.accountService.GetAccountById(existingTransaction.UserId, req.AccountId); err != nil {
+			return err
+		}
+	}
+
+	currencyCode := req.CurrencyCode
+	if currencyCode == "" {
+		currencyCode = existingTransaction.CurrencyCode
+	}
+
+	// nil keeps the current split lines, which then must still match the amount and type
+	var splits []models.TransactionSplit
+	if req.Splits != nil {
+		splits, err = t.buildSplits(existingTransaction.UserId, req.Type, req.Amount, req.Splits)
+		if err != nil {
+			return err
+		}
+		if splits == nil {
+			splits = []models.TransactionSplit{}
+		}
+		for i := range splits {
+			splits[i].TransactionId = transactionId
+			splits[i].CreatedAt = time.Now()
+		}
+		if req.CategoryId == "" && len(splits) > 0 {
+			req.CategoryId = largestSplitCategory(splits)
+		}
+	}
+
+	conversion, discount, err := t.convertToBase(existingTransaction.UserId, currencyCode, req.Amount, req.Discount, splits, existingTransaction.TransactionDate)
+	if err != nil {
+		return err
+	}
+
+	if req.Splits == nil {
+		currentSplits, err := t.transactionRepository.GetTransactionSplits([]string{transactionId})
+		if err != nil {
+			t.logging.LogError(fmt.Sprintf("Error fetching splits of transaction %s: %v", transactionId, err))
+			return fmt.Errorf("failed to get transaction splits: %w", err)
+		}
+		// The kept lines are in the base currency, so they only fit the same converted amount
+		if len(currentSplits) > 0 && (conversion.Converted != existingTransaction.Amount || req.Type != existingTransaction.Type) {
+			return fmt.Errorf("%w: send the splits again when changing the amount or type of a split transaction", transaction.ErrInvalidSplits)
+		}
+	}
+
 	// If the description has changed, we need to re-create the embedding and AI confidence
 	if existingTransaction.Description != req.Description {
 		t.logging.LogInfo("Description has changed, re-creating embedding and AI confidence")
@@ -312,6 +718,7 @@ func (t *transactionService) UpdateTransaction(transactionId string, req *reques
 			return fmt.Errorf("failed to create new embedding for updated transaction")
 		}
 		req.DescriptionEmbedding = embedding.Embeddings
+		t.logMessageService.LogUsage(existingTransaction.UserId, constants.TopicTransactionEmbedding, embedding.Model, embedding.InputToken, 0)
 		t.logging.LogInfo("Successfully created new embedding for updated transaction description")
 		// Re-calculate AI confidence
 		messagePrompt := []openai.ChatCompletionMessageParamUnion{
@@ -337,6 +744,10 @@ func (t *transactionService) UpdateTransaction(transactionId string, req *reques
 			t.logging.LogError(fmt.Sprintf("Error creating chat completion for updated transaction confidence: %v", err))
 			return fmt.Errorf("failed to get AI confidence for updated transaction: %w", err)
 		}
+		if responseAi == nil {
+			return errors.New("failed to get AI confidence for updated transaction: empty AI response")
+		}
+		t.logMessageService.LogUsage(existingTransaction.UserId, constants.TopicCategoryConfidence, responseAi.Model, responseAi.InputToken, responseAi.OutputToken)
 		if responseStr, ok := responseAi.Response.(string); ok && len(responseStr) > 0 {
 			if confidence, parseErr := t.parseConfidenceFromResponse(responseStr); parseErr == nil {
 				req.AiCategoryConfidence = confidence
@@ -363,7 +774,7 @@ func (t *transactionService) UpdateTransaction(transactionId string, req *reques
 		Type:                 req.Type,
 		Description:          req.Description,
 		DescriptionEmbedding: req.DescriptionEmbedding,
-		Amount:               req.Amount,
+		Amount:               conversion.Converted,
 		Source:               req.Source,
 		IsAutoCategorized:    req.IsAutoCategorized,
 		AiCategoryConfidence: req.AiCategoryConfidence,
@@ -371,8 +782,13 @@ func (t *transactionService) UpdateTransaction(transactionId string, req *reques
 		CreatedAt:            existingTransaction.CreatedAt,       // Keep original created at
 		UpdatedAt:            time.Now(),                          // Update to current time
 		Confirmed:            req.Confirmed,
-		Discount:             req.Discount,
+		Discount:             discount,
 		PaymentMethod:        req.PaymentMethod,
+		AccountId:            req.AccountId,
+		Splits:               splits,
+		CurrencyCode:         conversion.From.Code,
+		OriginalAmount:       originalAmount(conversion),
+		FxRate:               conversion.Rate.Value,
 	}
 
 	// Update the transaction in the repository
@@ -393,19 +809,206 @@ func (t *transactionService) OverviewTransactions(userId string, req *requests.O
 		return nil, fmt.Errorf("failed to get transaction stats: %w", err)
 	}
 
+	base, err := t.currencyService.GetBaseCurrency(userId)
+	if err != nil {
+		return nil, err
+	}
+
 	res := &responses.OverviewTransactionsResponse{
-		TotalTransactions: t.formatToRupiah(stats.TotalIncome + stats.TotalExpense),
-		TotalIncome:       t.formatToRupiah(stats.TotalIncome),
-		TotalExpense:      t.formatToRupiah(stats.TotalExpense),
+		TotalTransactions: fx.Format(stats.TotalIncome+stats.TotalExpense, base),
+		TotalIncome:       fx.Format(stats.TotalIncome, base),
+		TotalExpense:      fx.Format(stats.TotalExpense, base),
 	}
 
 	return res, nil
 }
 
-func (t *transactionService) formatToRupiah(amount int64) string {
-	// Create a printer for Indonesian locale
-	p := message.NewPrinter(language.Indonesian)
+func (t *transactionService) FilterTransactions(userId string, req *requests.TransactionFilterRequest) (*responses.TransactionFilterResponse, error) {
+	if req.Filter == nil && strings.TrimSpace(req.Query) == "" {
+		return nil, errors.New("query or filter is required")
+	}
+
+	categoriesList, err := t.categoryService.FindAllCategories(&requests.GetAllCategoryQuery{Limit: 100})
+	if err != nil {
+		t.logging.LogError(fmt.Sprintf("Error fetching categories for transaction filter: %v", err))
+		return nil, fmt.Errorf("failed to fetch categories: %w", err)
+	}
+
+	// An explicit filter wins over the query so the UI can re-run an edited filter without the LLM
+	filter := req.Filter
+	if filter == nil {
+		filter, err = t.parseTransactionFilter(userId, req.Query, categoriesList.Categories)
+		if err != nil {
+			return nil, err
+		}
+	}
+	t.normalizeTransactionFilter(filter, categoriesList.Categories)
+
+	offset := 0
+	if req.Offset > 1 {
+		offset = (req.Offset - 1) * req.Limit
+	}
+
+	transactions, err := t.transactionRepository.FilterTransactions(userId, filter, req.Limit, offset)
+	if err != nil {
+		t.logging.LogError(fmt.Sprintf("Error filtering transactions: %v", err))
+		return nil, fmt.Errorf("failed to filter transactions: %w", err)
+	}
+
+	count, err := t.transactionRepository.CountFilteredTransactions(userId, filter)
+	if err != nil {
+		t.logging.LogError(fmt.Sprintf("Error counting filtered transactions: %v", err))
+		return nil, fmt.Errorf("failed to count filtered transactions: %w", err)
+	}
+
+	if transactions == nil {
+		transactions = []models.Transaction{}
+	}
+
+	totalPages := math.Ceil(float64(count) / float64(req.Limit))
+	currentPage := math.Min(float64(req.Offset), float64(totalPages))
+
+	t.logging.LogInfo(fmt.Sprintf("Filtered %d transactions for user %s with filter: %+v", count, userId, filter))
+	return &responses.TransactionFilterResponse{
+		Filter:       filter,
+		Transactions: transactions,
+		CurrentPage:  int64(currentPage),
+		TotalPages:   int64(totalPages),
+		Total:        count,
+	}, nil
+}
+
+func (t *transactionService) parseTransactionFilter(userId, query string, categoriesList []models.Category) (*requests.TransactionFilter, error) {
+	t.logging.LogInfo(fmt.Sprintf("Parsing natural-language transaction filter: %s", query))
+
+	categoryIds := make([]any, len(categoriesList))
+	categoryStrings := make([]string, len(categoriesList))
+	for i, category := range categoriesList {
+		categoryIds[i] = category.CategoryId
+		categoryStrings[i] = fmt.Sprintf("%s (%s)", category.Name, category.CategoryId)
+	}
+
+	systemPrompt := fmt.Sprintf(prompt.TransactionFilterSystemPromptTemplate,
+		time.Now().Format("2006-01-02 (Monday)"), strings.Join(categoryStrings, ", "))
+
+	messagePrompt := []openai.ChatCompletionMessageParamUnion{
+		openai.SystemMessage(systemPrompt),
+		openai.UserMessage(query),
+	}
+
+	responseAi, err := t.openaiClient.SendChatWithSchema(context.Background(), "gpt-4o-mini", messagePrompt,
+		"transaction_filter", t.transactionFilterSchema(categoryIds))
+	if err != nil {
+		t.logging.LogError(fmt.Sprintf("Error parsing transaction filter with AI: %v", err))
+		return nil, fmt.Errorf("failed to parse transaction filter: %w", err)
+	}
+
+	if responseAi == nil {
+		return nil, errors.New("failed to parse transaction filter: empty AI response")
+	}
+	t.logMessageService.LogUsage(userId, constants.TopicTransactionFilter, responseAi.Model, responseAi.InputToken, responseAi.OutputToken)
+
+	responseStr, ok := responseAi.Response.(string)
+	if !ok || responseStr == "" {
+		return nil, errors.New("failed to parse transaction filter: empty AI response")
+	}
+
+	filter := &requests.TransactionFilter{}
+	if err := json.Unmarshal([]byte(responseStr), filter); err != nil {
+		t.logging.LogError(fmt.Sprintf("Error decoding transaction filter: %v", err))
+		return nil, fmt.Errorf("failed to decode transaction filter: %w", err)
+	}
+
+	return filter, nil
+}
+
+// transactionFilterSchema is the strict JSON schema the LLM must follow, every field is required but nullable
+func (t *transactionService) transactionFilterSchema(categoryIds []any) map[string]any {
+	categoryItems := map[string]any{"type": "string"}
+	if len(categoryIds) > 0 {
+		categoryItems["enum"] = categoryIds
+	}
+
+	nullableString := func(description string) map[string]any {
+		return map[string]any{"type": []string{"string", "null"}, "description": description}
+	}
+	nullableInteger := func(description string) map[string]any {
+		return map[string]any{"type": []string{"integer", "null"}, "description": description}
+	}
+
+	return map[string]any{
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties": map[string]any{
+			"start_date":     nullableString("Inclusive start date, YYYY-MM-DD"),
+			"end_date":       nullableString("Inclusive end date, YYYY-MM-DD"),
+			"category_ids":   map[string]any{"type": "array", "items": categoryItems},
+			"type":           map[string]any{"type": []string{"string", "null"}, "enum": []any{"income", "expense", nil}},
+			"amount_min":     nullableInteger("Minimum amount in Rupiah"),
+			"amount_max":     nullableInteger("Maximum amount in Rupiah"),
+			"payment_method": nullableString("Payment method named by the user"),
+			"source":         nullableString("Merchant or source named by the user"),
+		},
+		"required": []string{"start_date", "end_date", "category_ids", "type", "amount_min", "amount_max", "payment_method", "source"},
+	}
+}
+
+// normalizeTransactionFilter drops values that cannot be applied and fixes inverted ranges,
+// the result is echoed back so the UI always shows what actually ran
+func (t *transactionService) normalizeTransactionFilter(filter *requests.TransactionFilter, categoriesList []models.Category) {
+	knownCategories := make(map[string]bool, len(categoriesList))
+	for _, category := range categoriesList {
+		knownCategories[category.CategoryId] = true
+	}
+
+	categoryIds := []string{}
+	for _, categoryId := range filter.CategoryIds {
+		if knownCategories[categoryId] {
+			categoryIds = append(categoryIds, categoryId)
+		} else {
+			t.logging.LogWarn(fmt.Sprintf("Dropping unknown category %s from transaction filter", categoryId))
+		}
+	}
+	filter.CategoryIds = categoryIds
+
+	if filter.StartDate != "" {
+		if _, err := time.Parse("2006-01-02", filter.StartDate); err != nil {
+			filter.StartDate = ""
+		}
+	}
+	if filter.EndDate != "" {
+		if _, err := time.Parse("2006-01-02", filter.EndDate); err != nil {
+			filter.EndDate = ""
+		}
+	}
+	if filter.StartDate != "" && filter.EndDate != "" && filter.StartDate > filter.EndDate {
+		filter.StartDate, filter.EndDate = filter.EndDate, filter.StartDate
+	}
+
+	if filter.Type != string(constants.IncomeCategory) && filter.Type != string(constants.ExpenseCategory) {
+		filter.Type = ""
+	}
+
+	if filter.AmountMin != nil && *filter.AmountMin < 0 {
+		filter.AmountMin = nil
+	}
+	if filter.AmountMax != nil && *filter.AmountMax < 0 {
+		filter.AmountMax = nil
+	}
+	if filter.AmountMin != nil && filter.AmountMax != nil && *filter.AmountMin > *filter.AmountMax {
+		filter.AmountMin, filter.AmountMax = filter.AmountMax, filter.AmountMin
+	}
+
+	filter.PaymentMethod = strings.TrimSpace(filter.PaymentMethod)
+	filter.Source = strings.TrimSpace(filter.Source)
+}
+
+func (t *transactionService) SearchSimilarTransactions(userId, embedding string, limit int, threshold float64) ([]models.TransactionWithScore, error) {
+	transactions, err := t.transactionRepository.SearchTransactionsByEmbedding(userId, embedding, limit, threshold)
+	if err != nil {
+		t.logging.LogError(fmt.Sprintf("Error searching similar transactions for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to search similar transactions: %w", err)
+	}
 
-	// Format the amount with thousand separators and add "Rp" prefix
-	return fmt.Sprintf("Rp %s", p.Sprintf("%d", amount))
+	return transactions, nil
 }
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/saufiroja/fin-ai/config"
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/domains/anomaly"
+	"github.com/saufiroja/fin-ai/internal/domains/currency"
+	"github.com/saufiroja/fin-ai/internal/domains/log_message"
+	"github.com/saufiroja/fin-ai/internal/domains/transaction"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/pkg/fx"
+	"github.com/saufiroja/fin-ai/pkg/llm"
+	"github.com/saufiroja/fin-ai/pkg/llm/cassette"
+	logging "github.com/saufiroja/fin-ai/pkg/loggings"
+)
+
+// The fakes embed the interface they stand in for, only the methods InsertTransaction reaches are implemented
+
+type fakeTransactionStorer struct {
+	transaction.TransactionStorer
+	inserted []*models.Transaction
+}
+
+func (f *fakeTransactionStorer) InsertTransaction(transaction *models.Transaction) error {
+	f.inserted = append(f.inserted, transaction)
+	return nil
+}
+
+type fakeCurrencyManager struct {
+	currency.CurrencyManager
+}
+
+func (fakeCurrencyManager) ToBase(ctx context.Context, userId string, amount int64, code string, date time.Time) (*fx.Conversion, error) {
+	idr, err := fx.Lookup("IDR")
+	if err != nil {
+		return nil, err
+	}
+	return &fx.Conversion{From: idr, To: idr, Amount: amount, Converted: amount, Rate: fx.Rate{Base: "IDR", Quote: "IDR", Date: date, Value: 1}}, nil
+}
+
+type usageCall struct {
+	topic, model        string
+	inputTokens, output int
+}
+
+type fakeLogMessageManager struct {
+	log_message.LogMessageManager
+	mu     sync.Mutex
+	usage  []usageCall
+	logged []*models.LogMessage
+}
+
+func (f *fakeLogMessageManager) InsertLogMessage(logMessage *models.LogMessage) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.logged = append(f.logged, logMessage)
+	return nil
+}
+
+func (f *fakeLogMessageManager) LogUsage(userId, topic, model string, inputTokens, outputTokens int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.usage = append(f.usage, usageCall{topic, model, inputTokens, outputTokens})
+}
+
+type fakeAnomalyManager struct {
+	anomaly.AnomalyManager
+	queued []string
+}
+
+func (f *fakeAnomalyManager) QueueCheck(transactionId string) {
+	f.queued = append(f.queued, transactionId)
+}
+
+// insertTransactionCassette was recorded from InsertTransaction, the description carries an email address and
+// a phone number that are redacted in the fixture
+const insertTransactionCassette = "insert_transaction.json"
+
+// cassetteConfig points the LLM clients at a cassette in testdata/cassettes, each client built from it
+// gets its own transport
+func cassetteConfig(name string, mode cassette.Mode) *config.AppConfig {
+	conf := &config.AppConfig{}
+	conf.OpenAI.ApiKey = "sk-test-00000000000000000000000000000000"
+	conf.Gemini.ApiKey = "AIzaTest000000000000000000000000000000"
+	conf.LLM.CassetteMode = string(mode)
+	conf.LLM.CassettePath = filepath.Join("testdata", "cassettes", name)
+	return conf
+}
+
+func TestInsertTransactionReplaysCassette(t *testing.T) {
+	repository := &fakeTransactionStorer{}
+	usage := &fakeLogMessageManager{}
+	anomalies := &fakeAnomalyManager{}
+	service := NewTransactionService(
+		repository,
+		nil,
+		logging.NewLogrusAdapter(),
+		llm.NewOpenAI(cassetteConfig(insertTransactionCassette, cassette.ModeReplay)),
+		usage,
+		anomalies,
+		nil,
+		fakeCurrencyManager{},
+	)
+
+	err := service.InsertTransaction(&requests.TransactionRequest{
+		TransactionId:     "01JB2Q5Z8X4M7R3T9V6W1Y0C2D",
+		UserId:            "01JB2Q4K7N3P8S2V5X9Z1B4D6F",
+		CategoryId:        "01JB2Q4M1Q6T9W3Y7A2C5E8G0J",
+		Type:              constants.ExpenseCategory,
+		Description:       "Arisan transfer to budi.santoso@example.com 081234567890",
+		Amount:            150000,
+		Source:            "manual",
+		TransactionDate:   time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC),
+		IsAutoCategorized: true,
+	})
+	if err != nil {
+		t.Fatalf("InsertTransaction() error = %v", err)
+	}
+
+	if len(repository.inserted) != 1 {
+		t.Fatalf("inserted %d transactions, want 1", len(repository.inserted))
+	}
+	inserted := repository.inserted[0]
+	if inserted.DescriptionEmbedding != "[0.012300,-0.045600,0.078900,-0.001200,0.034500,-0.067800,0.090100,-0.023400]" {
+		t.Errorf("embedding = %s, want the recorded one", inserted.DescriptionEmbedding)
+	}
+	if inserted.AiCategoryConfidence != 0.92 {
+		t.Errorf("AI confidence = %v, want the recorded 0.92", inserted.AiCategoryConfidence)
+	}
+	if inserted.Amount != 150000 || inserted.CurrencyCode != "IDR" {
+		t.Errorf("amount = %d %s, want 150000 IDR", inserted.Amount, inserted.CurrencyCode)
+	}
+	if inserted.OriginalAmount != 0 {
+		t.Errorf("original amount = %d, want none for a payment in the base currency", inserted.OriginalAmount)
+	}
+	if len(anomalies.queued) != 1 || anomalies.queued[0] != inserted.TransactionId {
+		t.Errorf("anomaly checks queued = %v, want %s", anomalies.queued, inserted.TransactionId)
+	}
+
+	want := map[string]usageCall{
+		constants.TopicTransactionEmbedding: {constants.TopicTransactionEmbedding, llm.EmbeddingModel, 14, 0},
+		constants.TopicCategoryConfidence:   {constants.TopicCategoryConfidence, "gpt-4o-mini", 61, 3},
+	}
+	if len(usage.usage) != len(want) {
+		t.Fatalf("usage logged %d times, want %d: %+v", len(usage.usage), len(want), usage.usage)
+	}
+	for _, call := range usage.usage {
+		if call != want[call.topic] {
+			t.Errorf("usage = %+v, want %+v", call, want[call.topic])
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/account"
+	"github.com/saufiroja/fin-ai/internal/domains/categories"
+	"github.com/saufiroja/fin-ai/internal/domains/log_message"
+	"github.com/saufiroja/fin-ai/internal/domains/transfer"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/internal/utils"
+	"github.com/saufiroja/fin-ai/pkg/llm"
+	logging "github.com/saufiroja/fin-ai/pkg/loggings"
+)
+
+type transferService struct {
+	transferRepository transfer.TransferStorer
+	accountService     account.AccountManager
+	categoryService    categories.CategoryManager
+	openaiClient       llm.OpenAI
+	logMessageService  log_message.LogMessageManager
+	logging            logging.Logger
+}
+
+func NewTransferService(
+	transferRepository transfer.TransferStorer,
+	accountService account.AccountManager,
+	categoryService categories.CategoryManager,
+	openaiClient llm.OpenAI,
+	logMessageService log_message.LogMessageManager,
+	logging logging.Logger,
+) transfer.TransferManager {
+	return &transferService{
+		transferRepository: transferRepository,
+		accountService:     accountService,
+		categoryService:    categoryService,
+		openaiClient:       openaiClient,
+		logMessageService:  logMessageService,
+		logging:            logging,
+	}
+}
+
+func (s *transferService) CreateTransfer(userId string, req *requests.TransferRequest) (*responses.TransferResponse, error) {
+	s.logging.LogInfo(fmt.Sprintf("Creating transfer for user %s: %+v", userId, req))
+
+	if req.FromAccountId == req.ToAccountId {
+		return nil, fmt.Errorf("%w: source and destination account must differ", transfer.ErrInvalidTransfer)
+	}
+
+	from, err := s.accountService.GetAccountById(userId, req.FromAccountId)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.accountService.GetAccountById(userId, req.ToAccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Fee > 0 {
+		if req.FeeCategoryId == "" {
+			return nil, fmt.Errorf("%w: fee_category_id is required when a fee is charged", transfer.ErrInvalidTransfer)
+		}
+		category, err := s.categoryService.FindCategoryById(req.FeeCategoryId)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get fee category: %w", err)
+		}
+		if category == nil || category.Type != constants.ExpenseCategory {
+			return nil, fmt.Errorf("%w: fee_category_id must be an expense category", transfer.ErrInvalidTransfer)
+		}
+	}
+
+	now := time.Now()
+	transferDate := now
+	if req.TransferDate != "" {
+		transferDate, err = time.Parse(utils.DateLayout, req.TransferDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid transfer date", transfer.ErrInvalidTransfer)
+		}
+	}
+
+	description := strings.TrimSpace(req.Description)
+	if description == "" {
+		description = fmt.Sprintf("Transfer from %s to %s", from.Name, to.Name)
+	}
+
+	tr := &models.Transfer{
+		TransferId:    ulid.Make().String(),
+		UserId:        userId,
+		FromAccountId: from.AccountId,
+		ToAccountId:   to.AccountId,
+		Amount:        req.Amount,
+		Fee:           req.Fee,
+		Description:   description,
+		TransferDate:  transferDate,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	legs := []*models.Transaction{
+		s.newLeg(tr, from.AccountId, "", constants.TransferOutType, fmt.Sprintf("Transfer to %s: %s", to.Name, description), req.Amount),
+		s.newLeg(tr, to.AccountId, "", constants.TransferInType, fmt.Sprintf("Transfer from %s: %s", from.Name, description), req.Amount),
+	}
+	// The fee leaves the source account for good, so it is a real expense
+	if req.Fee > 0 {
+		legs = append(legs, s.newLeg(tr, from.AccountId, req.FeeCategoryId, constants.ExpenseCategory, fmt.Sprintf("Transfer fee to %s: %s", to.Name, description), req.Fee))
+	}
+
+	descriptions := make([]string, 0, len(legs))
+	for _, leg := range legs {
+		descriptions = append(descriptions, leg.Description)
+	}
+	embeddings := s.openaiClient.CreateBatchEmbedding(context.Background(), descriptions)
+	if embeddings == nil || len(embeddings.Embeddings) != len(legs) {
+		s.logging.LogError("Failed to create embeddings for transfer legs")
+		return nil, fmt.Errorf("failed to create embeddings for transfer")
+	}
+	s.logMessageService.LogUsage(userId, constants.TopicTransferEmbedding, embeddings.Model, embeddings.InputToken, 0)
+	for i, leg := range legs {
+		leg.DescriptionEmbedding = embeddings.Embeddings[i]
+	}
+
+	if err := s.transferRepository.InsertTransfer(tr, legs); err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to insert transfer: %v", err))
+		return nil, fmt.Errorf("failed to insert transfer: %w", err)
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Transfer %s created successfully", tr.TransferId))
+	return s.GetTransferById(userId, tr.TransferId)
+}
+
+func (s *transferService) GetTransfers(userId string, req *requests.TransferQuery) (*responses.TransfersResponse, error) {
+	offset := 0
+	if req.Offset > 1 {
+		offset = (req.Offset - 1) * req.Limit
+	}
+
+	transfers, err := s.transferRepository.GetTransfers(userId, req.AccountId, req.Limit, offset)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get transfers for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to get transfers: %w", err)
+	}
+
+	count, err := s.transferRepository.CountTransfers(userId, req.AccountId)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to count transfers for user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to count transfers: %w", err)
+	}
+
+	if transfers == nil {
+		transfers = []models.TransferWithAccounts{}
+	}
+
+	totalPages := math.Ceil(float64(count) / float64(req.Limit))
+	currentPage := math.Min(float64(req.Offset), totalPages)
+
+	return &responses.TransfersResponse{
+		TotalPages:  int64(totalPages),
+		CurrentPage: int64(currentPage),
+		Total:       count,
+		Transfers:   transfers,
+	}, nil
+}
+
+func (s *transferService) GetTransferById(userId, transferId string) (*responses.TransferResponse, error) {
+	tr, err := s.transferRepository.GetTransferById(userId, transferId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, transfer.ErrTransferNotFound
+		}
+		s.logging.LogError(fmt.Sprintf("Failed to get transfer %s: %v", transferId, err))
+		return nil, fmt.Errorf("failed to get transfer: %w", err)
+	}
+
+	legs, err := s.transferRepository.GetTransferLegs(transferId)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get legs of transfer %s: %v", transferId, err))
+		return nil, fmt.Errorf("failed to get transfer transactions: %w", err)
+	}
+	if legs == nil {
+		legs = []models.Transaction{}
+	}
+
+	return &responses.TransferResponse{
+		TransferWithAccounts: *tr,
+		Transactions:         legs,
+	}, nil
+}
+
+func (s *transferService) DeleteTransfer(userId, transferId string) error {
+	if _, err := s.GetTransferById(userId, transferId); err != nil {
+		return err
+	}
+
+	if err := s.transferRepository.DeleteTransfer(userId, transferId); err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to delete transfer %s: %v", transferId, err))
+		return fmt.Errorf("failed to delete transfer: %w", err)
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Transfer %s deleted successfully", transferId))
+	return nil
+}
+
+func (s *transferService) newLeg(tr *models.Transfer, accountId, categoryId string, legType constants.TypeCategory, description string, amount int64) *models.Transaction {
+	return &models.Transaction{
+		TransactionId:   ulid.Make().String(),
+		UserId:          tr.UserId,
+		CategoryId:      categoryId,
+		Type:            legType,
+		Description:     description,
+		Amount:          amount,
+		Source:          constants.TransferSource,
+		TransactionDate: tr.TransferDate,
+		CreatedAt:       tr.CreatedAt,
+		UpdatedAt:       tr.UpdatedAt,
+		Confirmed:       true,
+		PaymentMethod:   constants.TransferSource,
+		AccountId:       accountId,
+		TransferId:      tr.TransferId,
+	}
+}
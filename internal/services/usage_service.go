@@ -0,0 +1,101 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/usage"
+	"github.com/saufiroja/fin-ai/internal/models"
+	logging "github.com/saufiroja/fin-ai/pkg/loggings"
+)
+
+type usageService struct {
+	usageRepository usage.UsageStorer
+	logging         logging.Logger
+}
+
+func NewUsageService(usageRepository usage.UsageStorer, logging logging.Logger) usage.UsageManager {
+	return &usageService{
+		usageRepository: usageRepository,
+		logging:         logging,
+	}
+}
+
+func (s *usageService) GetUsage(query *requests.UsageQuery) (*responses.UsageResponse, error) {
+	return s.getUsage(query.UserId, query)
+}
+
+func (s *usageService) GetUserUsage(userId string, query *requests.UsageQuery) (*responses.UsageResponse, error) {
+	return s.getUsage(userId, query)
+}
+
+func (s *usageService) getUsage(userId string, query *requests.UsageQuery) (*responses.UsageResponse, error) {
+	filter, err := usageFilter(userId, query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := s.usageRepository.GetUsageGroups(filter)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get usage by %s: %v", filter.GroupBy, err))
+		return nil, err
+	}
+	total, err := s.usageRepository.GetUsageTotal(filter)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get usage total: %v", err))
+		return nil, err
+	}
+
+	return &responses.UsageResponse{
+		GroupBy: filter.GroupBy,
+		From:    filter.From.Format(time.DateOnly),
+		To:      filter.To.AddDate(0, 0, -1).Format(time.DateOnly),
+		Total:   *total,
+		Groups:  groups,
+	}, nil
+}
+
+// usageFilter applies the defaults to the query: grouped by day over the last constants.UsageDefaultDays
+// days up to today. The to date is inclusive in the query and exclusive in the filter.
+func usageFilter(userId string, query *requests.UsageQuery, now time.Time) (*models.UsageFilter, error) {
+	filter := &models.UsageFilter{
+		GroupBy: query.GroupBy,
+		UserId:  userId,
+		Topic:   query.Topic,
+		Model:   query.Model,
+		Limit:   query.Limit,
+	}
+	if filter.GroupBy == "" {
+		filter.GroupBy = constants.UsageGroupByDay
+	}
+	if filter.Limit == 0 {
+		filter.Limit = constants.UsageDefaultLimit
+	}
+
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if query.To != "" {
+		parsed, err := time.Parse(time.DateOnly, query.To)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to date: %w", err)
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, 1-constants.UsageDefaultDays)
+	if query.From != "" {
+		parsed, err := time.Parse(time.DateOnly, query.From)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from date: %w", err)
+		}
+		from = parsed
+	}
+	if from.After(to) {
+		return nil, usage.ErrInvalidUsagePeriod
+	}
+
+	filter.From = from
+	filter.To = to.AddDate(0, 0, 1)
+	return filter, nil
+}
@@ -1,24 +1,44 @@
 package services
 
 import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/saufiroja/fin-ai/internal/constants"
+	"github.com/saufiroja/fin-ai/internal/constants/prompt"
 	"github.com/saufiroja/fin-ai/internal/contracts/requests"
 	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/internal/domains/currency"
 	"github.com/saufiroja/fin-ai/internal/domains/user"
+	"github.com/saufiroja/fin-ai/internal/models"
+	"github.com/saufiroja/fin-ai/pkg/fx"
 	logging "github.com/saufiroja/fin-ai/pkg/loggings"
+	"github.com/saufiroja/fin-ai/pkg/minio"
+	"golang.org/x/text/language"
 )
 
 type userService struct {
-	UserRepository user.UserStorer
-	logging        logging.Logger
+	UserRepository  user.UserStorer
+	minioClient     minio.MinioManager
+	currencyService currency.CurrencyManager
+	logging         logging.Logger
 }
 
-func NewUserService(userRepository user.UserStorer, logger logging.Logger) user.UserManager {
+func NewUserService(userRepository user.UserStorer, minioClient minio.MinioManager, currencyService currency.CurrencyManager, logger logging.Logger) user.UserManager {
 	return &userService{
-		UserRepository: userRepository,
-		logging:        logger,
+		UserRepository:  userRepository,
+		minioClient:     minioClient,
+		currencyService: currencyService,
+		logging:         logger,
 	}
 }
 
@@ -27,7 +47,7 @@ func (s *userService) UpdateUserById(userId string, req *requests.UpdateUserRequ
 	_, err := s.UserRepository.FindUserById(userId)
 	if err != nil {
 		s.logging.LogError(fmt.Sprintf("User with ID %s not found: %v", userId, err))
-		return errors.New("user not found")
+		return user.ErrUserNotFound
 	}
 
 	err = s.UserRepository.UpdateUserById(userId, req)
@@ -40,18 +60,219 @@ func (s *userService) UpdateUserById(userId string, req *requests.UpdateUserRequ
 	return nil
 }
 
-func (s *userService) DeleteUserById(userId string) error {
-	s.logging.LogInfo(fmt.Sprintf("Deleting user with ID: %s", userId))
+// RequestAccountDeletion schedules the deletion, the account keeps working and can be restored with
+// CancelAccountDeletion until the grace period ends
+func (s *userService) RequestAccountDeletion(userId string) (*responses.AccountDeletion, error) {
+	s.logging.LogInfo(fmt.Sprintf("Requesting deletion of user with ID: %s", userId))
 	_, err := s.UserRepository.FindUserById(userId)
 	if err != nil {
 		s.logging.LogError(fmt.Sprintf("User with ID %s not found: %v", userId, err))
-		return errors.New("user not found")
+		return nil, user.ErrUserNotFound
 	}
 
-	err = s.UserRepository.DeleteUserById(userId)
+	now := time.Now()
+	deletion := &responses.AccountDeletion{
+		UserId:              userId,
+		DeletionRequestedAt: now,
+		DeletionScheduledAt: now.Add(constants.AccountDeletionGracePeriod),
+	}
+	err = s.UserRepository.ScheduleDeletion(deletion)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to schedule deletion of user with ID %s: %v", userId, err))
+		return nil, errors.New("failed to delete user")
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("User with ID %s scheduled for deletion at %s", userId, deletion.DeletionScheduledAt.Format(time.RFC3339)))
+	return deletion, nil
+}
+
+func (s *userService) CancelAccountDeletion(userId string) error {
+	s.logging.LogInfo(fmt.Sprintf("Cancelling deletion of user with ID: %s", userId))
+
+	cancelled, err := s.UserRepository.CancelDeletion(userId)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to cancel deletion of user with ID %s: %v", userId, err))
+		return errors.New("failed to cancel account deletion")
+	}
+	if !cancelled {
+		return user.ErrAccountDeletionNotScheduled
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Deletion of user with ID %s cancelled", userId))
+	return nil
+}
+
+// ExportUserData prepares the personal data archive. The zip holds data/<table>.json with the user's
+// rows of every table, the receipt images and generated reports from MinIO and a manifest.json.
+func (s *userService) ExportUserData(userId string) (*responses.UserDataExport, error) {
+	s.logging.LogInfo(fmt.Sprintf("Exporting personal data of user with ID: %s", userId))
+	if _, err := s.UserRepository.FindUserById(userId); err != nil {
+		s.logging.LogError(fmt.Sprintf("User with ID %s not found: %v", userId, err))
+		return nil, user.ErrUserNotFound
+	}
+
+	now := time.Now()
+	write := func(w io.Writer) error {
+		archive := zip.NewWriter(w)
+		manifest := &userDataManifest{
+			UserId:     userId,
+			ExportedAt: now,
+			Tables:     make(map[string]int, len(constants.UserDataTables)),
+		}
+
+		for _, table := range constants.UserDataTables {
+			count, err := s.writeUserTable(archive, userId, table)
+			if err != nil {
+				s.logging.LogError(fmt.Sprintf("Error exporting table %s for user %s: %v", table, userId, err))
+				return err
+			}
+			manifest.Tables[table] = count
+		}
+
+		for _, folder := range []struct{ bucket, dir string }{
+			{constants.ReceiptBucket, "receipts"},
+			{constants.ReportBucket, "reports"},
+		} {
+			files, err := s.writeUserObjects(archive, userId, folder.bucket, folder.dir)
+			if err != nil {
+				s.logging.LogError(fmt.Sprintf("Error exporting %s files for user %s: %v", folder.dir, userId, err))
+				return err
+			}
+			manifest.Files = append(manifest.Files, files...)
+		}
+
+		entry, err := archive.Create("manifest.json")
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(entry)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(manifest); err != nil {
+			return err
+		}
+
+		if err := archive.Close(); err != nil {
+			s.logging.LogError(fmt.Sprintf("Error finishing data export for user %s: %v", userId, err))
+			return err
+		}
+
+		s.logging.LogInfo(fmt.Sprintf("Exported personal data of user %s with %d files", userId, len(manifest.Files)))
+		return nil
+	}
+
+	return &responses.UserDataExport{
+		FileName: fmt.Sprintf("fin-ai-data-%s.zip", now.Format("20060102-150405")),
+		Write:    write,
+	}, nil
+}
+
+type userDataManifest struct {
+	UserId     string         `json:"user_id"`
+	ExportedAt time.Time      `json:"exported_at"`
+	Tables     map[string]int `json:"tables"` // row count per data/<table>.json
+	Files      []string       `json:"files"`
+}
+
+// writeUserTable writes the table rows as a JSON array with one object per line
+func (s *userService) writeUserTable(archive *zip.Writer, userId, table string) (int, error) {
+	entry, err := archive.Create("data/" + table + ".json")
+	if err != nil {
+		return 0, err
+	}
+
+	writer := bufio.NewWriter(entry)
+	count := 0
+	writer.WriteString("[")
+	err = s.UserRepository.ExportUserTable(userId, table, func(row json.RawMessage) error {
+		if count > 0 {
+			writer.WriteString(",")
+		}
+		count++
+		writer.WriteString("\n")
+		_, err := writer.Write(row)
+		return err
+	})
+	if err != nil {
+		return count, err
+	}
+	writer.WriteString("\n]\n")
+
+	return count, writer.Flush()
+}
+
+// writeUserObjects copies the objects under the user's prefix into dir, it returns the archive paths
+func (s *userService) writeUserObjects(archive *zip.Writer, userId, bucket, dir string) ([]string, error) {
+	prefix := userId + "/"
+	names, err := s.minioClient.ListObjectNames(bucket, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(names))
+	for _, name := range names {
+		path := dir + "/" + strings.TrimPrefix(name, prefix)
+		if err := s.copyObject(archive, bucket, name, path); err != nil {
+			return nil, err
+		}
+		files = append(files, path)
+	}
+
+	return files, nil
+}
+
+func (s *userService) copyObject(archive *zip.Writer, bucket, objectName, path string) error {
+	object, err := s.minioClient.GetObjectReader(bucket, objectName)
+	if err != nil {
+		return err
+	}
+	defer object.Close()
+
+	// Images and PDFs are already compressed
+	entry, err := archive.CreateHeader(&zip.FileHeader{Name: path, Method: zip.Store, Modified: time.Now()})
 	if err != nil {
-		s.logging.LogError(fmt.Sprintf("Failed to delete user with ID %s: %v", userId, err))
-		return errors.New("failed to delete user")
+		return err
+	}
+	_, err = io.Copy(entry, object)
+	return err
+}
+
+func (s *userService) PurgeDueAccounts(ctx context.Context, now time.Time) error {
+	userIds, err := s.UserRepository.GetUserIdsDueForDeletion(now)
+	if err != nil {
+		s.logging.LogError(fmt.Sprintf("Failed to get accounts due for deletion: %v", err))
+		return fmt.Errorf("failed to get accounts due for deletion: %w", err)
+	}
+
+	purged := 0
+	for _, userId := range userIds {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err := s.purgeAccount(userId); err != nil {
+			// The account stays scheduled and is retried on the next run
+			s.logging.LogError(fmt.Sprintf("Failed to purge account of user %s: %v", userId, err))
+			continue
+		}
+		purged++
+	}
+
+	s.logging.LogInfo(fmt.Sprintf("Purged %d of %d accounts due for deletion", purged, len(userIds)))
+	return nil
+}
+
+// purgeAccount removes the stored files before the rows, the user id is the only way to find them
+func (s *userService) purgeAccount(userId string) error {
+	for _, bucket := range []string{constants.ReceiptBucket, constants.ReportBucket} {
+		removed, err := s.minioClient.RemoveObjectsWithPrefix(bucket, userId+"/")
+		if err != nil {
+			return fmt.Errorf("failed to remove files from %s: %w", bucket, err)
+		}
+		s.logging.LogInfo(fmt.Sprintf("Removed %d files of user %s from %s", removed, userId, bucket))
+	}
+
+	if err := s.UserRepository.DeleteUserById(userId); err != nil {
+		return fmt.Errorf("failed to delete user rows: %w", err)
 	}
 
 	s.logging.LogInfo(fmt.Sprintf("User with ID %s deleted successfully", userId))
@@ -70,3 +291,141 @@ func (s *userService) GetMe(userId string) (*responses.FindUserById, error) {
 	s.logging.LogInfo("User information retrieved successfully")
 	return user, nil
 }
+
+func (s *userService) GetPreferences(userId string) (*responses.UserPreferences, error) {
+	prefs, err := s.UserRepository.GetPreferences(userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, user.ErrUserNotFound
+		}
+		s.logging.LogError(fmt.Sprintf("Failed to get preferences of user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to get preferences: %w", err)
+	}
+
+	base, err := s.currencyService.GetBaseCurrency(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses.UserPreferences{BaseCurrency: base, UserPreferences: *prefs}, nil
+}
+
+func (s *userService) UpdatePreferences(userId string, req *requests.UpdatePreferencesRequest) (*responses.UserPreferences, error) {
+	s.logging.LogInfo(fmt.Sprintf("Updating preferences of user %s", userId))
+
+	current, err := s.GetPreferences(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	prefs := current.UserPreferences
+	defaults := models.DefaultUserPreferences()
+	if req.Locale != nil {
+		prefs.Locale = preferenceValue(*req.Locale, defaults.Locale)
+	}
+	if req.Timezone != nil {
+		prefs.Timezone = preferenceValue(*req.Timezone, defaults.Timezone)
+	}
+	if req.Language != nil {
+		prefs.Language = preferenceValue(*req.Language, defaults.Language)
+	}
+	if req.Tone != nil {
+		prefs.Tone = preferenceValue(*req.Tone, defaults.Tone)
+	}
+	if req.DefaultChatMode != nil {
+		prefs.DefaultChatMode = models.Mode(preferenceValue(*req.DefaultChatMode, string(defaults.DefaultChatMode)))
+	}
+	if req.DefaultModel != nil {
+		prefs.DefaultModel = preferenceValue(*req.DefaultModel, defaults.DefaultModel)
+	}
+	if req.Notifications != nil {
+		if req.Notifications.MonthlyReport != nil {
+			prefs.Notifications.MonthlyReport = *req.Notifications.MonthlyReport
+		}
+		if req.Notifications.AnomalyAlerts != nil {
+			prefs.Notifications.AnomalyAlerts = *req.Notifications.AnomalyAlerts
+		}
+		if req.Notifications.SubscriptionAlerts != nil {
+			prefs.Notifications.SubscriptionAlerts = *req.Notifications.SubscriptionAlerts
+		}
+	}
+
+	if err := s.validatePreferences(&prefs); err != nil {
+		return nil, err
+	}
+
+	// The base currency lives in its own column, it is validated with the rest and written in the same
+	// transaction. The repository refuses it once the user has transactions.
+	baseCurrency := ""
+	if req.BaseCurrency != nil && *req.BaseCurrency != "" {
+		target, err := fx.Lookup(*req.BaseCurrency)
+		if err != nil {
+			return nil, fmt.Errorf("%w: base_currency must be one of %s", user.ErrInvalidPreference, supportedCurrencyCodes())
+		}
+		if target.Code != current.BaseCurrency.Code {
+			baseCurrency = target.Code
+		}
+	}
+
+	if err := s.UserRepository.UpdatePreferences(userId, &prefs, baseCurrency); err != nil {
+		if errors.Is(err, currency.ErrBaseCurrencyLocked) {
+			return nil, err
+		}
+		s.logging.LogError(fmt.Sprintf("Failed to update preferences of user %s: %v", userId, err))
+		return nil, fmt.Errorf("failed to update preferences: %w", err)
+	}
+
+	return s.GetPreferences(userId)
+}
+
+func (s *userService) validatePreferences(prefs *models.UserPreferences) error {
+	if _, err := language.Parse(prefs.Locale); err != nil {
+		return fmt.Errorf("%w: unknown locale %q", user.ErrInvalidPreference, prefs.Locale)
+	}
+	if _, err := time.LoadLocation(prefs.Timezone); err != nil {
+		return fmt.Errorf("%w: unknown time zone %q", user.ErrInvalidPreference, prefs.Timezone)
+	}
+	if !slices.Contains(constants.ChatModels, prefs.DefaultModel) {
+		return fmt.Errorf("%w: default_model must be one of %s", user.ErrInvalidPreference, strings.Join(constants.ChatModels, ", "))
+	}
+	return nil
+}
+
+// supportedCurrencyCodes lists the codes of fx.Supported for error messages
+func supportedCurrencyCodes() string {
+	supported := fx.Supported()
+	codes := make([]string, len(supported))
+	for i, c := range supported {
+		codes[i] = c.Code
+	}
+	return strings.Join(codes, ", ")
+}
+
+// preferenceValue is the requested value, an empty one restores the default
+func preferenceValue(value, fallback string) string {
+	if strings.TrimSpace(value) == "" {
+		return fallback
+	}
+	return strings.TrimSpace(value)
+}
+
+// preferencePrompt tells the model how the user wants to be answered, it is appended to system prompts
+// written in the same locale. The prompts it is made of are returned for the log of the call.
+func preferencePrompt(resolve promptResolver, prefs *responses.UserPreferences, locale string, now time.Time) (string, []models.ResolvedPrompt) {
+	tone := resolve(prompt.ToneKey(prefs.Tone))
+	if tone.Content == "" {
+		tone = resolve(prompt.ToneKey(constants.DefaultTone))
+	}
+	language := resolve(prompt.LanguageKey(locale))
+	template := resolve(prompt.Preferences)
+
+	text := fmt.Sprintf(template.Content,
+		language.Content,
+		tone.Content,
+		prefs.BaseCurrency.Code,
+		prefs.BaseCurrency.Decimals,
+		prefs.FormatAmount(1500000),
+		now.In(prefs.Location()).Format("Monday, 2 January 2006 15:04 MST"),
+	)
+	return text, []models.ResolvedPrompt{template, language, tone}
+}
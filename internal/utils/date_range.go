@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+)
+
+const DateLayout = "2006-01-02"
+
+// ResolveDateRange parses an inclusive YYYY-MM-DD range, defaulting to the current month up to today
+func ResolveDateRange(startDate, endDate string, now time.Time) (time.Time, time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	start := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if startDate != "" {
+		parsed, err := time.Parse(DateLayout, startDate)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid start date: %w", err)
+		}
+		start = parsed
+	}
+
+	end := today
+	if endDate != "" {
+		parsed, err := time.Parse(DateLayout, endDate)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid end date: %w", err)
+		}
+		end = parsed
+	}
+
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("end date %s is before start date %s", end.Format(DateLayout), start.Format(DateLayout))
+	}
+
+	return start, end, nil
+}
+
+// DaysInRange counts the days of an inclusive date range
+func DaysInRange(start, end time.Time) int {
+	return int(end.Sub(start).Hours()/24) + 1
+}
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"math"
+	"sort"
+)
+
+// Median returns 0 for an empty slice
+func Median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// MedianAbsoluteDeviation is a spread measure that, unlike the standard deviation, ignores a few extreme values
+func MedianAbsoluteDeviation(values []float64) float64 {
+	median := Median(values)
+
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - median)
+	}
+
+	return Median(deviations)
+}
+
+// MeanStdDev returns the mean and population standard deviation
+func MeanStdDev(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+
+	return mean, math.Sqrt(variance / float64(len(values)))
+}
+
+// Percentile uses linear interpolation between the closest ranks, p is between 0 and 100
+func Percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower < 0 {
+		lower = 0
+	}
+	if upper >= len(sorted) {
+		upper = len(sorted) - 1
+	}
+
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
@@ -0,0 +1,12 @@
+\c finaidb;
+
+ALTER TABLE receipt_items
+ADD COLUMN confirmed BOOLEAN DEFAULT FALSE;
+
+CREATE INDEX idx_transactions_review_queue
+ON transactions (user_id, ai_category_confidence ASC)
+WHERE is_auto_categorized = TRUE AND confirmed = FALSE;
+
+CREATE INDEX idx_receipt_items_review_queue
+ON receipt_items (receipt_id, ai_category_confidence ASC)
+WHERE confirmed = FALSE;
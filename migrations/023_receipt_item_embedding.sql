@@ -0,0 +1,7 @@
+\c finaidb;
+
+ALTER TABLE receipt_items
+ADD COLUMN item_name_embedding vector(1536); -- for OpenAI embeddings
+
+CREATE INDEX idx_receipt_items_item_name_embedding
+ON receipt_items USING hnsw (item_name_embedding vector_cosine_ops);
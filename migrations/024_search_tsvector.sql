@@ -0,0 +1,26 @@
+\c finaidb;
+
+-- 'simple' config because descriptions mix Indonesian and English
+ALTER TABLE transactions
+ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    to_tsvector('simple', COALESCE(description, '') || ' ' || COALESCE(source, ''))
+) STORED;
+
+CREATE INDEX idx_transactions_search_vector
+ON transactions USING gin (search_vector);
+
+ALTER TABLE receipts
+ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    to_tsvector('simple', COALESCE(merchant_name, ''))
+) STORED;
+
+CREATE INDEX idx_receipts_search_vector
+ON receipts USING gin (search_vector);
+
+ALTER TABLE receipt_items
+ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    to_tsvector('simple', COALESCE(item_name, ''))
+) STORED;
+
+CREATE INDEX idx_receipt_items_search_vector
+ON receipt_items USING gin (search_vector);
@@ -0,0 +1,44 @@
+\c finaidb;
+
+DROP TABLE IF EXISTS recurring_rules;
+CREATE TABLE recurring_rules (
+    recurring_rule_id VARCHAR(250) PRIMARY KEY,
+    user_id VARCHAR(250) NOT NULL,
+    category_id VARCHAR(250) NOT NULL,
+    type VARCHAR(20) CHECK (type IN ('income', 'expense')),
+    description TEXT NOT NULL,
+    amount INTEGER NOT NULL,
+    payment_method VARCHAR(255) DEFAULT '',
+    frequency VARCHAR(20) NOT NULL CHECK (frequency IN ('daily', 'weekly', 'monthly', 'yearly')),
+    day_of_month INTEGER CHECK (day_of_month BETWEEN 1 AND 31),
+    start_date DATE NOT NULL,
+    end_date DATE,
+    next_run_date DATE,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP,
+    CONSTRAINT fk_recurring_rules_user FOREIGN KEY (user_id) REFERENCES users(user_id),
+    CONSTRAINT fk_recurring_rules_category FOREIGN KEY (category_id) REFERENCES categories(category_id)
+);
+
+CREATE INDEX idx_recurring_rules_user_id ON recurring_rules(user_id);
+CREATE INDEX idx_recurring_rules_due
+ON recurring_rules (next_run_date)
+WHERE is_active = TRUE;
+
+-- One row per occurrence that deviates from the rule or has already been materialized
+DROP TABLE IF EXISTS recurring_occurrences;
+CREATE TABLE recurring_occurrences (
+    recurring_occurrence_id VARCHAR(250) PRIMARY KEY,
+    recurring_rule_id VARCHAR(250) NOT NULL,
+    occurrence_date DATE NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('skipped', 'overridden', 'materialized')),
+    amount INTEGER,
+    description TEXT,
+    transaction_id VARCHAR(250),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP,
+    CONSTRAINT fk_recurring_occurrences_rule FOREIGN KEY (recurring_rule_id) REFERENCES recurring_rules(recurring_rule_id) ON DELETE CASCADE,
+    CONSTRAINT fk_recurring_occurrences_transaction FOREIGN KEY (transaction_id) REFERENCES transactions(transaction_id) ON DELETE SET NULL,
+    CONSTRAINT uq_recurring_occurrences_rule_date UNIQUE (recurring_rule_id, occurrence_date)
+);
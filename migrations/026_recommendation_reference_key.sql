@@ -0,0 +1,12 @@
+\c finaidb;
+
+-- reference_key identifies the finding behind a recommendation (e.g. a detected subscription)
+-- so detectors running repeatedly do not create duplicates while one is still active
+ALTER TABLE ai_recommendations
+ADD COLUMN reference_key VARCHAR(250);
+
+CREATE INDEX idx_recommendations_user_reference
+ON ai_recommendations (user_id, reference_key, expires_at);
+
+CREATE INDEX idx_recommendations_user_created
+ON ai_recommendations (user_id, created_at DESC);
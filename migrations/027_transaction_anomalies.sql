@@ -0,0 +1,24 @@
+\c finaidb;
+
+DROP TABLE IF EXISTS transaction_anomalies;
+CREATE TABLE transaction_anomalies (
+    anomaly_id VARCHAR(250) PRIMARY KEY,
+    user_id VARCHAR(250) NOT NULL,
+    transaction_id VARCHAR(250), -- NULL for category spikes
+    category_id VARCHAR(250),
+    anomaly_type VARCHAR(50) NOT NULL CHECK (anomaly_type IN ('category_amount', 'merchant_amount', 'unusual_time', 'new_merchant', 'category_spike')),
+    reference_key VARCHAR(250) NOT NULL, -- one anomaly per finding, e.g. per transaction and type or per category and month
+    reason TEXT NOT NULL,
+    observed_value BIGINT NOT NULL,
+    expected_value BIGINT NOT NULL,
+    score DECIMAL(10,2) NOT NULL,
+    sample_size INTEGER NOT NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    detected_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_anomalies_user FOREIGN KEY (user_id) REFERENCES users(user_id),
+    CONSTRAINT fk_anomalies_transaction FOREIGN KEY (transaction_id) REFERENCES transactions(transaction_id) ON DELETE CASCADE,
+    CONSTRAINT fk_anomalies_category FOREIGN KEY (category_id) REFERENCES categories(category_id) ON DELETE SET NULL,
+    CONSTRAINT uq_anomalies_user_reference UNIQUE (user_id, reference_key)
+);
+
+CREATE INDEX idx_anomalies_user_occurred ON transaction_anomalies (user_id, occurred_at DESC);
@@ -0,0 +1,45 @@
+\c finaidb;
+
+DROP TABLE IF EXISTS accounts;
+CREATE TABLE accounts (
+    account_id VARCHAR(250) PRIMARY KEY,
+    user_id VARCHAR(250) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('cash', 'bank', 'e_wallet', 'credit_card', 'other')),
+    institution VARCHAR(100) DEFAULT '', -- e.g. BCA, GoPay, OVO
+    opening_balance BIGINT NOT NULL DEFAULT 0,
+    opening_date DATE NOT NULL, -- transactions before this date do not count towards the balance
+    is_archived BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP,
+    CONSTRAINT fk_accounts_user FOREIGN KEY (user_id) REFERENCES users(user_id),
+    CONSTRAINT uq_accounts_user_name UNIQUE (user_id, name)
+);
+
+CREATE INDEX idx_accounts_user_id ON accounts(user_id);
+
+ALTER TABLE transactions
+ADD COLUMN account_id VARCHAR(250),
+ADD CONSTRAINT fk_transactions_account FOREIGN KEY (account_id) REFERENCES accounts(account_id) ON DELETE SET NULL;
+
+CREATE INDEX idx_transactions_account_date
+ON transactions (account_id, transaction_date, transaction_id)
+INCLUDE (amount, type)
+WHERE account_id IS NOT NULL;
+
+-- Statement balances entered by the user and what the recorded transactions add up to
+DROP TABLE IF EXISTS account_reconciliations;
+CREATE TABLE account_reconciliations (
+    reconciliation_id VARCHAR(250) PRIMARY KEY,
+    account_id VARCHAR(250) NOT NULL,
+    user_id VARCHAR(250) NOT NULL,
+    statement_date DATE NOT NULL,
+    statement_balance BIGINT NOT NULL,
+    computed_balance BIGINT NOT NULL,
+    difference BIGINT NOT NULL, -- statement_balance - computed_balance
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_account_reconciliations_account FOREIGN KEY (account_id) REFERENCES accounts(account_id) ON DELETE CASCADE,
+    CONSTRAINT fk_account_reconciliations_user FOREIGN KEY (user_id) REFERENCES users(user_id)
+);
+
+CREATE INDEX idx_account_reconciliations_account_date ON account_reconciliations(account_id, statement_date DESC);
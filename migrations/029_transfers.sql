@@ -0,0 +1,32 @@
+\c finaidb;
+
+DROP TABLE IF EXISTS transfers;
+CREATE TABLE transfers (
+    transfer_id VARCHAR(250) PRIMARY KEY,
+    user_id VARCHAR(250) NOT NULL,
+    from_account_id VARCHAR(250),
+    to_account_id VARCHAR(250),
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    fee BIGINT NOT NULL DEFAULT 0 CHECK (fee >= 0),
+    description TEXT NOT NULL,
+    transfer_date TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP,
+    CONSTRAINT fk_transfers_user FOREIGN KEY (user_id) REFERENCES users(user_id),
+    CONSTRAINT fk_transfers_from_account FOREIGN KEY (from_account_id) REFERENCES accounts(account_id) ON DELETE SET NULL,
+    CONSTRAINT fk_transfers_to_account FOREIGN KEY (to_account_id) REFERENCES accounts(account_id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_transfers_user_date ON transfers(user_id, transfer_date DESC);
+
+-- A transfer is recorded as a transfer_out leg, a transfer_in leg and an optional fee expense, all linked by transfer_id
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_type_check;
+ALTER TABLE transactions
+ADD CONSTRAINT transactions_type_check CHECK (type IN ('income', 'expense', 'transfer_in', 'transfer_out'));
+
+-- Deleting the transfer deletes every leg
+ALTER TABLE transactions
+ADD COLUMN transfer_id VARCHAR(250),
+ADD CONSTRAINT fk_transactions_transfer FOREIGN KEY (transfer_id) REFERENCES transfers(transfer_id) ON DELETE CASCADE;
+
+CREATE INDEX idx_transactions_transfer_id ON transactions(transfer_id) WHERE transfer_id IS NOT NULL;
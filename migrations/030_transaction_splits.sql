@@ -0,0 +1,19 @@
+\c finaidb;
+
+-- Split lines of a single payment across categories, the amounts always add up to the parent amount
+DROP TABLE IF EXISTS transaction_splits;
+CREATE TABLE transaction_splits (
+    split_id VARCHAR(250) PRIMARY KEY,
+    transaction_id VARCHAR(250) NOT NULL,
+    user_id VARCHAR(250) NOT NULL,
+    category_id VARCHAR(250) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    note VARCHAR(255) DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_transaction_splits_transaction FOREIGN KEY (transaction_id) REFERENCES transactions(transaction_id) ON DELETE CASCADE,
+    CONSTRAINT fk_transaction_splits_user FOREIGN KEY (user_id) REFERENCES users(user_id),
+    CONSTRAINT fk_transaction_splits_category FOREIGN KEY (category_id) REFERENCES categories(category_id)
+);
+
+CREATE INDEX idx_transaction_splits_transaction ON transaction_splits(transaction_id);
+CREATE INDEX idx_transaction_splits_user_category ON transaction_splits(user_id, category_id);
@@ -0,0 +1,37 @@
+\c finaidb;
+
+-- Free-form labels owned by a user, names are stored normalized (lowercase, dashes instead of spaces)
+DROP TABLE IF EXISTS tags CASCADE;
+CREATE TABLE tags (
+    tag_id VARCHAR(250) PRIMARY KEY,
+    user_id VARCHAR(250) NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP,
+    CONSTRAINT fk_tags_user FOREIGN KEY (user_id) REFERENCES users(user_id),
+    CONSTRAINT uq_tags_user_name UNIQUE (user_id, name)
+);
+
+DROP TABLE IF EXISTS transaction_tags;
+CREATE TABLE transaction_tags (
+    transaction_id VARCHAR(250) NOT NULL,
+    tag_id VARCHAR(250) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (transaction_id, tag_id),
+    CONSTRAINT fk_transaction_tags_transaction FOREIGN KEY (transaction_id) REFERENCES transactions(transaction_id) ON DELETE CASCADE,
+    CONSTRAINT fk_transaction_tags_tag FOREIGN KEY (tag_id) REFERENCES tags(tag_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_transaction_tags_tag ON transaction_tags(tag_id);
+
+DROP TABLE IF EXISTS receipt_tags;
+CREATE TABLE receipt_tags (
+    receipt_id VARCHAR(250) NOT NULL,
+    tag_id VARCHAR(250) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (receipt_id, tag_id),
+    CONSTRAINT fk_receipt_tags_receipt FOREIGN KEY (receipt_id) REFERENCES receipts(receipt_id) ON DELETE CASCADE,
+    CONSTRAINT fk_receipt_tags_tag FOREIGN KEY (tag_id) REFERENCES tags(tag_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_receipt_tags_tag ON receipt_tags(tag_id);
@@ -0,0 +1,32 @@
+\c finaidb;
+
+DROP TABLE IF EXISTS transaction_imports;
+CREATE TABLE transaction_imports (
+    import_id VARCHAR(250) PRIMARY KEY,
+    user_id VARCHAR(250) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    file_type VARCHAR(10) NOT NULL CHECK (file_type IN ('csv', 'xlsx')),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'committed', 'reverted')),
+    -- The parsed sheet is kept until commit so validation can be re-run with a different mapping
+    headers JSONB NOT NULL,
+    raw_rows JSONB NOT NULL,
+    column_mapping JSONB,
+    total_rows INT NOT NULL DEFAULT 0,
+    imported_rows INT NOT NULL DEFAULT 0,
+    duplicate_rows INT NOT NULL DEFAULT 0,
+    invalid_rows INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP,
+    committed_at TIMESTAMP,
+    reverted_at TIMESTAMP,
+    CONSTRAINT fk_transaction_imports_user FOREIGN KEY (user_id) REFERENCES users(user_id)
+);
+
+CREATE INDEX idx_transaction_imports_user_created ON transaction_imports(user_id, created_at DESC);
+
+-- Reverting an import deletes its transactions explicitly, the import row itself is kept as history
+ALTER TABLE transactions
+ADD COLUMN import_id VARCHAR(250),
+ADD CONSTRAINT fk_transactions_import FOREIGN KEY (import_id) REFERENCES transaction_imports(import_id) ON DELETE SET NULL;
+
+CREATE INDEX idx_transactions_import_id ON transactions(import_id) WHERE import_id IS NOT NULL;
@@ -0,0 +1,15 @@
+\c finaidb;
+
+-- Stable transaction ID from a bank statement, used to skip lines that were imported before
+ALTER TABLE transactions
+ADD COLUMN external_id VARCHAR(250);
+
+CREATE UNIQUE INDEX idx_transactions_user_external_id ON transactions(user_id, external_id) WHERE external_id IS NOT NULL;
+
+-- Bank statements are parsed into the same import table as generic CSV/XLSX files
+ALTER TABLE transaction_imports DROP CONSTRAINT IF EXISTS transaction_imports_file_type_check;
+ALTER TABLE transaction_imports
+ADD CONSTRAINT transaction_imports_file_type_check CHECK (file_type IN ('csv', 'xlsx', 'pdf', 'txt', 'ofx', 'qif', 'mt940'));
+
+ALTER TABLE transaction_imports
+ADD COLUMN statement_format VARCHAR(20);
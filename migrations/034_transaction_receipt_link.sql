@@ -0,0 +1,10 @@
+\c finaidb;
+
+-- Transactions created by confirming a receipt point back to it, used by exports to link the receipt
+ALTER TABLE transactions
+ADD COLUMN receipt_id VARCHAR(250);
+
+ALTER TABLE transactions
+ADD CONSTRAINT fk_transactions_receipt FOREIGN KEY (receipt_id) REFERENCES receipts(receipt_id) ON DELETE SET NULL;
+
+CREATE INDEX idx_transactions_receipt ON transactions(receipt_id);
@@ -0,0 +1,25 @@
+\c finaidb;
+
+-- Generated monthly PDF reports, the file itself is stored in MinIO
+DROP TABLE IF EXISTS monthly_reports;
+CREATE TABLE monthly_reports (
+    report_id VARCHAR(250) PRIMARY KEY,
+    user_id VARCHAR(250) NOT NULL,
+    year INTEGER NOT NULL,
+    month INTEGER NOT NULL CHECK (month BETWEEN 1 AND 12),
+    object_name TEXT NOT NULL,
+    file_size BIGINT NOT NULL DEFAULT 0,
+    total_income BIGINT NOT NULL DEFAULT 0,
+    total_expense BIGINT NOT NULL DEFAULT 0,
+    generated_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP,
+    CONSTRAINT fk_monthly_reports_user FOREIGN KEY (user_id) REFERENCES users(user_id),
+    -- Regenerating a month replaces its report
+    CONSTRAINT uq_monthly_reports_period UNIQUE (user_id, year, month)
+);
+
+CREATE INDEX idx_monthly_reports_user ON monthly_reports(user_id, year DESC, month DESC);
+
+-- The report reuses the stored AI narrative of the month
+CREATE INDEX idx_ai_summaries_user_period ON ai_summaries(user_id, period_type, period_start);
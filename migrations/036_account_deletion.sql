@@ -0,0 +1,11 @@
+\c finaidb;
+
+-- Account deletion is scheduled first, the purge job removes the user and all of their data once
+-- deletion_scheduled_at has passed. Cancelling clears both columns.
+ALTER TABLE users
+ADD COLUMN deletion_requested_at TIMESTAMP,
+ADD COLUMN deletion_scheduled_at TIMESTAMP;
+
+CREATE INDEX idx_users_deletion_scheduled
+ON users (deletion_scheduled_at)
+WHERE deletion_scheduled_at IS NOT NULL;
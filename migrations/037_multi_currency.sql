@@ -0,0 +1,30 @@
+\c finaidb;
+
+-- Amounts stay in the user's base currency so every aggregate keeps working, transactions paid in
+-- another currency also keep what was actually paid and the rate used
+ALTER TABLE users
+ADD COLUMN base_currency VARCHAR(3) NOT NULL DEFAULT 'IDR';
+
+ALTER TABLE accounts
+ADD COLUMN currency_code VARCHAR(3) NOT NULL DEFAULT 'IDR';
+
+-- Converted amounts can exceed the INTEGER range, e.g. a large USD payment in Rupiah, so amount is widened
+-- to BIGINT like original_amount, transfers and account balances
+ALTER TABLE transactions
+ALTER COLUMN amount TYPE BIGINT,
+ADD COLUMN currency_code VARCHAR(3) NOT NULL DEFAULT 'IDR',
+ADD COLUMN original_amount BIGINT, -- in minor units of currency_code, NULL when it equals amount
+ADD COLUMN fx_rate NUMERIC(20,10) NOT NULL DEFAULT 1; -- price of one currency_code in the base currency
+
+-- Exchange rates entered by users or synced, read when no other provider has the pair
+DROP TABLE IF EXISTS fx_rates;
+CREATE TABLE fx_rates (
+    base_code VARCHAR(3) NOT NULL,
+    quote_code VARCHAR(3) NOT NULL,
+    rate_date DATE NOT NULL,
+    rate NUMERIC(20,10) NOT NULL CHECK (rate > 0),
+    source VARCHAR(50) NOT NULL DEFAULT 'manual',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP,
+    PRIMARY KEY (base_code, quote_code, rate_date)
+);
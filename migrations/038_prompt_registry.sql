@@ -0,0 +1,54 @@
+\c finaidb;
+
+-- Versions of the prompts in internal/constants/prompt, a version is never edited once recorded so the
+-- log rows that reference it stay meaningful. Prompts without an active version use the built-in text.
+DROP TABLE IF EXISTS prompt_versions;
+CREATE TABLE prompt_versions (
+    prompt_version_id VARCHAR(250) PRIMARY KEY,
+    prompt_key VARCHAR(100) NOT NULL,
+    version VARCHAR(50) NOT NULL,
+    locale VARCHAR(10) NOT NULL,
+    content TEXT NOT NULL,
+    description TEXT,
+    source VARCHAR(10) NOT NULL DEFAULT 'api', -- api or file
+    is_active BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP,
+    CONSTRAINT uq_prompt_versions UNIQUE (prompt_key, version, locale)
+);
+
+-- Splits the users between two versions of a prompt, at most one running experiment per prompt
+DROP TABLE IF EXISTS prompt_experiments;
+CREATE TABLE prompt_experiments (
+    prompt_experiment_id VARCHAR(250) PRIMARY KEY,
+    prompt_key VARCHAR(100) NOT NULL,
+    control_version VARCHAR(50) NOT NULL,
+    candidate_version VARCHAR(50) NOT NULL,
+    candidate_percent INT NOT NULL CHECK (candidate_percent BETWEEN 0 AND 100),
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ended_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX uq_prompt_experiments_running
+ON prompt_experiments (prompt_key)
+WHERE ended_at IS NULL;
+
+-- prompt_versions maps every prompt key used by the call to its version
+ALTER TABLE log_messages
+ADD COLUMN prompt_versions JSONB NOT NULL DEFAULT '{}',
+ADD COLUMN parse_failed BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_log_messages_prompt_versions
+ON log_messages USING GIN (prompt_versions);
+
+-- Links a receipt to its extraction call, items recategorized by the user count as corrections
+ALTER TABLE receipts
+ADD COLUMN log_message_id VARCHAR(250);
+
+CREATE INDEX idx_receipts_log_message
+ON receipts (log_message_id);
+
+ALTER TABLE receipt_items
+ADD COLUMN corrected BOOLEAN NOT NULL DEFAULT FALSE;
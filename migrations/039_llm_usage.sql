@@ -0,0 +1,34 @@
+\c finaidb;
+
+-- Cost in USD of the call, computed from constants.ModelPrices when the row is inserted so a later price
+-- change does not rewrite the history. Calls that are not made for a user, like category embeddings,
+-- have no user_id.
+ALTER TABLE log_messages
+ADD COLUMN cost NUMERIC(14, 8) NOT NULL DEFAULT 0,
+ALTER COLUMN user_id DROP NOT NULL;
+
+UPDATE log_messages SET topic = 'agent_chat' WHERE topic = 'agent chat';
+
+-- Rows logged before costs were recorded get the prices of this migration
+UPDATE log_messages
+SET cost = (
+    COALESCE(input_token, 0) * CASE
+        WHEN model LIKE 'gemini-2.5-flash-lite%' THEN 0.10
+        WHEN model LIKE 'gemini-2.5-flash%' THEN 0.30
+        WHEN model LIKE 'gemini-2.5-pro%' THEN 1.25
+        WHEN model LIKE 'gpt-4o-mini%' THEN 0.15
+        WHEN model LIKE 'gpt-4o%' THEN 2.50
+        ELSE 0
+    END
+    + COALESCE(output_token, 0) * CASE
+        WHEN model LIKE 'gemini-2.5-flash-lite%' THEN 0.40
+        WHEN model LIKE 'gemini-2.5-flash%' THEN 2.50
+        WHEN model LIKE 'gemini-2.5-pro%' THEN 10.00
+        WHEN model LIKE 'gpt-4o-mini%' THEN 0.60
+        WHEN model LIKE 'gpt-4o%' THEN 10.00
+        ELSE 0
+    END
+) / 1000000;
+
+CREATE INDEX idx_log_messages_created_at ON log_messages (created_at);
+CREATE INDEX idx_log_messages_user_created_at ON log_messages (user_id, created_at);
@@ -0,0 +1,10 @@
+\c finaidb;
+
+-- The scheduler claims an occurrence as pending before it inserts the transaction, so two runs or a retry
+-- after a failed status update never record the same occurrence twice
+ALTER TABLE recurring_occurrences
+DROP CONSTRAINT IF EXISTS recurring_occurrences_status_check;
+
+ALTER TABLE recurring_occurrences
+ADD CONSTRAINT recurring_occurrences_status_check
+CHECK (status IN ('skipped', 'overridden', 'materialized', 'pending'));
@@ -0,0 +1,206 @@
+package bankstatement
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/saufiroja/fin-ai/pkg/importer"
+)
+
+var (
+	// bcaPeriodPattern matches "Periode : 01/03/2024 - 31/03/2024" in CSV downloads
+	bcaPeriodPattern = regexp.MustCompile(`(?i)periode\s*:\s*(\d{2}/\d{2}/\d{4})`)
+	// bcaPDFPeriodPattern matches "PERIODE : MARET 2024" in e-statements
+	bcaPDFPeriodPattern = regexp.MustCompile(`(?i)^periode\s*:\s*([a-z]+)\s+(\d{4})$`)
+	bcaAccountPattern   = regexp.MustCompile(`(?i)^no\.?\s*rekening\s*:\s*([\d-]+)`)
+	bcaLinePattern      = regexp.MustCompile(`^(\d{2}/\d{2})\s+(.+)$`)
+	// bcaAmountPattern is a formatted mutation or balance, description numbers are printed without separators
+	bcaAmountPattern = regexp.MustCompile(`^\d{1,3}(,\d{3})*\.\d{2}$`)
+	bcaBranchPattern = regexp.MustCompile(`^\d{4}$`)
+)
+
+// bcaMonths are the month names printed in e-statement periods
+var bcaMonths = map[string]time.Month{
+	"JANUARI": time.January, "FEBRUARI": time.February, "MARET": time.March, "APRIL": time.April,
+	"MEI": time.May, "JUNI": time.June, "JULI": time.July, "AGUSTUS": time.August,
+	"SEPTEMBER": time.September, "OKTOBER": time.October, "NOVEMBER": time.November, "DESEMBER": time.December,
+}
+
+// bcaDate resolves a "dd/mm" date against the first day of the statement period, a month before the
+// period start belongs to the next year
+func bcaDate(value string, periodStart time.Time) (time.Time, error) {
+	date, err := time.Parse("02/01", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid date %q", ErrMalformed, value)
+	}
+	year := periodStart.Year()
+	if date.Month() < periodStart.Month() {
+		year++
+	}
+	return time.Date(year, date.Month(), date.Day(), 0, 0, 0, 0, time.UTC), nil
+}
+
+// parseBCACSV reads the KlikBCA "mutasi rekening" download: a preamble with the account and period,
+// then Tanggal, Keterangan, Cabang, Jumlah, DB/CR, Saldo rows whose cells start with an apostrophe.
+// Pending rows (dated PEND) are skipped since they are not booked yet.
+func parseBCACSV(data []byte) (*Statement, error) {
+	records, err := importer.ReadCSVRecords(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+
+	statement := &Statement{Currency: "IDR"}
+	var periodStart time.Time
+	inRows := false
+	for _, record := range records {
+		for i := range record {
+			record[i] = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(record[i]), "'"))
+		}
+		first := record[0]
+
+		if !inRows {
+			if match := bcaAccountPattern.FindStringSubmatch(first); match != nil {
+				statement.AccountNumber = match[1]
+			}
+			if match := bcaPeriodPattern.FindStringSubmatch(first); match != nil {
+				periodStart, _ = time.Parse("02/01/2006", match[1])
+			}
+			inRows = strings.EqualFold(first, "Tanggal Transaksi")
+			continue
+		}
+
+		// The summary after the rows starts with "Saldo Awal"
+		if strings.HasPrefix(strings.ToUpper(first), "SALDO") {
+			break
+		}
+		if len(record) < 5 || strings.EqualFold(first, "PEND") || first == "" {
+			continue
+		}
+		if periodStart.IsZero() {
+			return nil, fmt.Errorf("%w: missing statement period", ErrMalformed)
+		}
+
+		date, err := bcaDate(first, periodStart)
+		if err != nil {
+			return nil, err
+		}
+		amount, err := importer.ParseAmount(record[3])
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid amount %q", ErrMalformed, record[3])
+		}
+		direction := importer.DirectionIncome
+		if strings.EqualFold(record[4], "DB") {
+			direction = importer.DirectionExpense
+		}
+
+		statement.Transactions = append(statement.Transactions, Transaction{
+			Date:        date,
+			Description: joinDescription(record[1]),
+			Amount:      amount,
+			Direction:   direction,
+		})
+	}
+
+	return statement, nil
+}
+
+// parseBCALines reads the monthly e-statement. A transaction line is "dd/mm KETERANGAN [CBG] MUTASI [DB] [SALDO]"
+// and the lines below it without a date continue its description.
+func parseBCALines(lines []string) (*Statement, error) {
+	statement := &Statement{Currency: "IDR"}
+	var periodStart time.Time
+	var current *Transaction
+
+	for _, line := range lines {
+		upper := strings.ToUpper(line)
+
+		if match := bcaAccountPattern.FindStringSubmatch(line); match != nil {
+			statement.AccountNumber = match[1]
+			current = nil
+			continue
+		}
+		if match := bcaPDFPeriodPattern.FindStringSubmatch(line); match != nil {
+			month, ok := bcaMonths[strings.ToUpper(match[1])]
+			if !ok {
+				return nil, fmt.Errorf("%w: unknown month %q", ErrMalformed, match[1])
+			}
+			year, _ := time.Parse("2006", match[2])
+			periodStart = time.Date(year.Year(), month, 1, 0, 0, 0, 0, time.UTC)
+			current = nil
+			continue
+		}
+		// Page headers and the closing summary end the running description
+		if strings.Contains(upper, " : ") || strings.HasPrefix(upper, "TANGGAL KETERANGAN") || strings.HasPrefix(upper, "BERSAMBUNG") {
+			current = nil
+			continue
+		}
+
+		match := bcaLinePattern.FindStringSubmatch(line)
+		if match == nil {
+			if current != nil {
+				current.Description = joinDescription(current.Description, line)
+			}
+			continue
+		}
+		if strings.HasPrefix(strings.ToUpper(match[2]), "SALDO AWAL") {
+			current = nil
+			continue
+		}
+		if periodStart.IsZero() {
+			return nil, fmt.Errorf("%w: missing statement period", ErrMalformed)
+		}
+
+		date, err := bcaDate(match[1], periodStart)
+		if err != nil {
+			return nil, err
+		}
+		transaction, err := parseBCAMutation(strings.Fields(match[2]))
+		if err != nil {
+			return nil, err
+		}
+		transaction.Date = date
+
+		statement.Transactions = append(statement.Transactions, transaction)
+		current = &statement.Transactions[len(statement.Transactions)-1]
+	}
+
+	return statement, nil
+}
+
+// parseBCAMutation reads the amounts from the end of a line: "MUTASI DB SALDO", "MUTASI DB", "MUTASI SALDO" or "MUTASI"
+func parseBCAMutation(tokens []string) (Transaction, error) {
+	end := len(tokens)
+	direction := importer.DirectionIncome
+	var amountToken string
+
+	switch {
+	case end >= 3 && tokens[end-2] == "DB" && bcaAmountPattern.MatchString(tokens[end-1]) && bcaAmountPattern.MatchString(tokens[end-3]):
+		direction, amountToken, end = importer.DirectionExpense, tokens[end-3], end-3
+	case end >= 2 && tokens[end-1] == "DB" && bcaAmountPattern.MatchString(tokens[end-2]):
+		direction, amountToken, end = importer.DirectionExpense, tokens[end-2], end-2
+	case end >= 2 && bcaAmountPattern.MatchString(tokens[end-1]) && bcaAmountPattern.MatchString(tokens[end-2]):
+		amountToken, end = tokens[end-2], end-2
+	case end >= 1 && bcaAmountPattern.MatchString(tokens[end-1]):
+		amountToken, end = tokens[end-1], end-1
+	default:
+		return Transaction{}, fmt.Errorf("%w: no amount in %q", ErrMalformed, strings.Join(tokens, " "))
+	}
+
+	amount, err := parseDecimal(amountToken, '.')
+	if err != nil {
+		return Transaction{}, err
+	}
+	// The branch code sits between the description and the amount
+	if end > 1 && bcaBranchPattern.MatchString(tokens[end-1]) {
+		end--
+	}
+
+	return Transaction{
+		Description: joinDescription(tokens[:end]...),
+		Amount:      amount,
+		Direction:   direction,
+	}, nil
+}
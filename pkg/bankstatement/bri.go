@@ -0,0 +1,146 @@
+package bankstatement
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/saufiroja/fin-ai/pkg/importer"
+)
+
+var (
+	briAccountPattern = regexp.MustCompile(`(?i)^no\.?\s*rekening\s*:\s*([\d-]+)`)
+	// briLinePattern is "dd/mm/yy hh:mm:ss Uraian [Teller] Debet Kredit Saldo"
+	briLinePattern   = regexp.MustCompile(`^(\d{2}/\d{2}/\d{2}) (\d{2}:\d{2}:\d{2}) (.*?)\s*([\d,]+\.\d{2}) ([\d,]+\.\d{2}) (-?[\d,]+\.\d{2})$`)
+	briTellerPattern = regexp.MustCompile(`^\d{7,}$`)
+)
+
+// parseBRICSV reads the BRI internet banking download. Both the TGL_TRAN/DESK_TRAN/MUTASI_DEBET/MUTASI_KREDIT
+// layout and the Tanggal Transaksi/Uraian Transaksi/Debet/Kredit layout are accepted.
+func parseBRICSV(data []byte) (*Statement, error) {
+	records, err := importer.ReadCSVRecords(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+
+	statement := &Statement{Currency: "IDR"}
+	var date, description, debit, credit int = -1, -1, -1, -1
+	for _, record := range records {
+		if date < 0 {
+			if match := briAccountPattern.FindStringSubmatch(strings.Join(record, " ")); match != nil {
+				statement.AccountNumber = match[1]
+			}
+			columns := headerColumns(record, "tgl_tran", "desk_tran", "mutasi_debet", "mutasi_kredit",
+				"tanggal transaksi", "uraian transaksi", "debet", "kredit")
+			date, description = firstColumn(columns, "tgl_tran", "tanggal transaksi"), firstColumn(columns, "desk_tran", "uraian transaksi")
+			debit, credit = firstColumn(columns, "mutasi_debet", "debet"), firstColumn(columns, "mutasi_kredit", "kredit")
+			if description < 0 || debit < 0 || credit < 0 {
+				date = -1
+			}
+			continue
+		}
+
+		if len(record) <= max(date, description, debit, credit) || strings.TrimSpace(record[date]) == "" {
+			continue
+		}
+
+		transactionDate, err := parseDayFirst(strings.TrimSpace(record[date]), "02/01/06 15:04:05", "02/01/06", "02/01/2006 15:04:05", "02/01/2006")
+		if err != nil {
+			return nil, err
+		}
+		debitAmount, err := parseDecimal(orZero(record[debit]), '.')
+		if err != nil {
+			return nil, err
+		}
+		creditAmount, err := parseDecimal(orZero(record[credit]), '.')
+		if err != nil {
+			return nil, err
+		}
+
+		transaction := Transaction{
+			Date:        transactionDate,
+			Description: joinDescription(record[description]),
+			Amount:      creditAmount,
+			Direction:   importer.DirectionIncome,
+		}
+		if debitAmount > 0 {
+			transaction.Amount, transaction.Direction = debitAmount, importer.DirectionExpense
+		}
+		statement.Transactions = append(statement.Transactions, transaction)
+	}
+
+	if date < 0 {
+		return nil, fmt.Errorf("%w: missing BRI header row", ErrMalformed)
+	}
+	return statement, nil
+}
+
+// parseBRILines reads the "Laporan Transaksi Finansial" PDF, lines below a transaction without a date
+// continue its description
+func parseBRILines(lines []string) (*Statement, error) {
+	statement := &Statement{Currency: "IDR"}
+	var current *Transaction
+
+	for _, line := range lines {
+		if match := briAccountPattern.FindStringSubmatch(line); match != nil {
+			statement.AccountNumber = match[1]
+			current = nil
+			continue
+		}
+
+		match := briLinePattern.FindStringSubmatch(line)
+		if match == nil {
+			if strings.Contains(line, " : ") || strings.HasPrefix(strings.ToUpper(line), "TANGGAL TRANSAKSI") ||
+				strings.HasPrefix(strings.ToUpper(line), "SALDO") {
+				current = nil
+			} else if current != nil {
+				current.Description = joinDescription(current.Description, line)
+			}
+			continue
+		}
+
+		date, err := parseDayFirst(match[1]+" "+match[2], "02/01/06 15:04:05")
+		if err != nil {
+			return nil, err
+		}
+		debit, err := parseDecimal(match[4], '.')
+		if err != nil {
+			return nil, err
+		}
+		credit, err := parseDecimal(match[5], '.')
+		if err != nil {
+			return nil, err
+		}
+
+		// The teller ID is printed between the description and the amounts
+		words := strings.Fields(match[3])
+		if len(words) > 1 && briTellerPattern.MatchString(words[len(words)-1]) {
+			words = words[:len(words)-1]
+		}
+
+		transaction := Transaction{
+			Date:        date,
+			Description: joinDescription(words...),
+			Amount:      credit,
+			Direction:   importer.DirectionIncome,
+		}
+		if debit > 0 {
+			transaction.Amount, transaction.Direction = debit, importer.DirectionExpense
+		}
+		statement.Transactions = append(statement.Transactions, transaction)
+		current = &statement.Transactions[len(statement.Transactions)-1]
+	}
+
+	return statement, nil
+}
+
+// firstColumn returns the index of the first header that is present, or -1
+func firstColumn(columns map[string]int, names ...string) int {
+	for _, name := range names {
+		if columns[name] >= 0 {
+			return columns[name]
+		}
+	}
+	return -1
+}
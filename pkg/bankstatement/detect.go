@@ -0,0 +1,53 @@
+package bankstatement
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+)
+
+// Detect guesses the statement format from the file name and content. It returns ErrUnknownFormat for
+// files that should go through the generic CSV/XLSX import instead.
+func Detect(fileName string, data []byte) (Format, error) {
+	ext := strings.ToLower(filepath.Ext(fileName))
+	head := strings.ToUpper(string(data[:min(len(data), 4096)]))
+
+	switch {
+	case ext == ".ofx" || ext == ".qfx" || strings.Contains(head, "<OFX>") || strings.Contains(head, "OFXHEADER"):
+		return FormatOFX, nil
+	case ext == ".qif" || strings.HasPrefix(strings.TrimSpace(head), "!TYPE:"):
+		return FormatQIF, nil
+	case ext == ".sta" || ext == ".mt940" || (strings.Contains(head, ":20:") && strings.Contains(head, ":61:")):
+		return FormatMT940, nil
+	case bytes.HasPrefix(data, []byte("%PDF")):
+		lines, err := pdfLines(data)
+		if err != nil {
+			return "", err
+		}
+		return detectBank(strings.ToUpper(strings.Join(lines, "\n")), FormatBCAPDF, FormatMandiriPDF, FormatBRIPDF)
+	case ext == ".txt":
+		// Text copied out of an e-statement PDF
+		return detectBank(head, FormatBCAPDF, FormatMandiriPDF, FormatBRIPDF)
+	case ext == ".csv":
+		return detectBank(head, FormatBCACSV, FormatMandiriCSV, FormatBRICSV)
+	default:
+		return "", ErrUnknownFormat
+	}
+}
+
+// detectBank looks for the layout markers of each bank in upper-cased text
+func detectBank(text string, bca, mandiri, bri Format) (Format, error) {
+	switch {
+	case strings.Contains(text, "BANK CENTRAL ASIA") || strings.Contains(text, "KODE MATA UANG") ||
+		strings.Contains(text, "TANGGAL TRANSAKSI,KETERANGAN,CABANG") || strings.Contains(text, "KETERANGAN CBG MUTASI"):
+		return bca, nil
+	case strings.Contains(text, "BANK MANDIRI") || strings.Contains(text, "DESCRIPTION1") ||
+		strings.Contains(text, "NOMINAL (IDR) SALDO (IDR)"):
+		return mandiri, nil
+	case strings.Contains(text, "BANK RAKYAT INDONESIA") || strings.Contains(text, "TGL_TRAN") ||
+		strings.Contains(text, "URAIAN TRANSAKSI"):
+		return bri, nil
+	default:
+		return "", ErrUnknownFormat
+	}
+}
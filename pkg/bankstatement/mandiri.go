@@ -0,0 +1,166 @@
+package bankstatement
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/saufiroja/fin-ai/pkg/importer"
+)
+
+var (
+	mandiriAccountPattern  = regexp.MustCompile(`(?i)^nomor rekening(?:/account number)?\s*:\s*([\d-]+)`)
+	mandiriCurrencyPattern = regexp.MustCompile(`(?i)^mata uang(?:/currency)?\s*:\s*([a-z]{3})`)
+	// mandiriLinePattern is "No dd Mon yyyy Keterangan +/-Nominal Saldo" with Indonesian number formatting
+	mandiriLinePattern = regexp.MustCompile(`^\d+\s+(\d{1,2} [A-Za-z]{3} \d{4})\s+(.*?)\s*([+-][\d.]+,\d{2})\s+(-?[\d.]+,\d{2})$`)
+	mandiriTimePattern = regexp.MustCompile(`^\d{2}:\d{2}:\d{2}( WIB)?\s*`)
+)
+
+// parseMandiriCSV reads the mandiri online download with the columns Account No, Date, Val. Date,
+// Transaction Code, Description1, Description2, Reference No., Debit and Credit. Dates are dd/mm/yy.
+func parseMandiriCSV(data []byte) (*Statement, error) {
+	records, err := importer.ReadCSVRecords(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+
+	statement := &Statement{Currency: "IDR"}
+	var columns map[string]int
+	for _, record := range records {
+		if columns == nil {
+			columns = headerColumns(record, "account no", "date", "description1", "description2", "reference no.", "debit", "credit")
+			if columns["date"] < 0 || columns["debit"] < 0 || columns["credit"] < 0 || columns["description1"] < 0 {
+				columns = nil
+			}
+			continue
+		}
+
+		cell := func(name string) string {
+			if i := columns[name]; i >= 0 && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		if cell("date") == "" {
+			continue
+		}
+
+		date, err := parseDayFirst(cell("date"), "02/01/06", "02/01/2006")
+		if err != nil {
+			return nil, err
+		}
+		debit, err := parseDecimal(orZero(cell("debit")), '.')
+		if err != nil {
+			return nil, err
+		}
+		credit, err := parseDecimal(orZero(cell("credit")), '.')
+		if err != nil {
+			return nil, err
+		}
+		if statement.AccountNumber == "" {
+			statement.AccountNumber = cell("account no")
+		}
+
+		transaction := Transaction{
+			Date:        date,
+			Description: joinDescription(cell("description1"), cell("description2")),
+			Reference:   cell("reference no."),
+			Amount:      credit,
+			Direction:   importer.DirectionIncome,
+		}
+		if debit > 0 {
+			transaction.Amount, transaction.Direction = debit, importer.DirectionExpense
+		}
+		statement.Transactions = append(statement.Transactions, transaction)
+	}
+
+	if columns == nil {
+		return nil, fmt.Errorf("%w: missing mandiri header row", ErrMalformed)
+	}
+	return statement, nil
+}
+
+// parseMandiriLines reads the e-statement: "No dd Mon yyyy Keterangan +/-Nominal Saldo", followed by the
+// transaction time and further description lines
+func parseMandiriLines(lines []string) (*Statement, error) {
+	statement := &Statement{Currency: "IDR"}
+	var current *Transaction
+
+	for _, line := range lines {
+		if match := mandiriAccountPattern.FindStringSubmatch(line); match != nil {
+			statement.AccountNumber = match[1]
+			current = nil
+			continue
+		}
+		if match := mandiriCurrencyPattern.FindStringSubmatch(line); match != nil {
+			statement.Currency = strings.ToUpper(match[1])
+			current = nil
+			continue
+		}
+
+		match := mandiriLinePattern.FindStringSubmatch(line)
+		if match == nil {
+			if strings.Contains(line, " : ") || strings.HasPrefix(strings.ToUpper(line), "NO TANGGAL") {
+				current = nil
+			} else if current != nil {
+				current.Description = joinDescription(current.Description, mandiriTimePattern.ReplaceAllString(line, ""))
+			}
+			continue
+		}
+
+		date, err := importer.ParseDate(match[1], "2 Jan 2006")
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid date %q", ErrMalformed, match[1])
+		}
+		signed, err := parseDecimal(match[3], ',')
+		if err != nil {
+			return nil, err
+		}
+		direction, amount := directionOf(signed)
+
+		statement.Transactions = append(statement.Transactions, Transaction{
+			Date:        date,
+			Description: joinDescription(match[2]),
+			Amount:      amount,
+			Direction:   direction,
+		})
+		current = &statement.Transactions[len(statement.Transactions)-1]
+	}
+
+	return statement, nil
+}
+
+// headerColumns returns the index of each wanted header, matched case-insensitively, or -1
+func headerColumns(record []string, names ...string) map[string]int {
+	columns := make(map[string]int, len(names))
+	for _, name := range names {
+		columns[name] = -1
+		for i, header := range record {
+			if strings.EqualFold(strings.TrimSpace(header), name) {
+				columns[name] = i
+				break
+			}
+		}
+	}
+	return columns
+}
+
+// parseDayFirst tries the layouts in order
+func parseDayFirst(value string, layouts ...string) (time.Time, error) {
+	for _, layout := range layouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: invalid date %q", ErrMalformed, value)
+}
+
+// orZero treats an empty amount cell as zero
+func orZero(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "0"
+	}
+	return value
+}
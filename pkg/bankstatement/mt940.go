@@ -0,0 +1,111 @@
+package bankstatement
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/saufiroja/fin-ai/pkg/importer"
+)
+
+var (
+	mt940TagPattern = regexp.MustCompile(`^:(\d{2}[A-Z]?):(.*)$`)
+	// mt940LinePattern is the :61: statement line: value date, optional entry date, (R)C/D mark, optional
+	// funds code, amount with a decimal comma, transaction type, customer reference and optional //bank reference
+	mt940LinePattern = regexp.MustCompile(`^(\d{6})(\d{4})?(RC|RD|C|D)([A-Z])?(\d+,\d{0,2})[NSF][A-Z0-9]{3}([^/]*)(?://(\S*))?`)
+)
+
+// parseMT940 reads a SWIFT MT940 customer statement. The :86: information that follows a :61: line,
+// possibly over several lines, becomes the description.
+func parseMT940(data []byte) (*Statement, error) {
+	statement := &Statement{}
+	var tag string
+	var value strings.Builder
+	var current *Transaction
+
+	flush := func() error {
+		text := strings.TrimSpace(value.String())
+		switch tag {
+		case "25":
+			statement.AccountNumber = text
+		case "60F", "60M":
+			if len(text) >= 10 {
+				statement.Currency = text[7:10]
+			}
+		case "61":
+			transaction, err := mt940Transaction(text)
+			if err != nil {
+				return err
+			}
+			statement.Transactions = append(statement.Transactions, transaction)
+			current = &statement.Transactions[len(statement.Transactions)-1]
+		case "86":
+			if current != nil {
+				current.Description = joinDescription(text)
+				current = nil
+			}
+		}
+		value.Reset()
+		return nil
+	}
+
+	for _, raw := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		line := strings.TrimRight(raw, " ")
+		if match := mt940TagPattern.FindStringSubmatch(line); match != nil {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			tag = match[1]
+			value.WriteString(match[2])
+			continue
+		}
+		if line == "-" || line == "" {
+			continue
+		}
+		value.WriteString(" " + line)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return statement, nil
+}
+
+func mt940Transaction(line string) (Transaction, error) {
+	match := mt940LinePattern.FindStringSubmatch(line)
+	if match == nil {
+		return Transaction{}, fmt.Errorf("%w: invalid :61: line %q", ErrMalformed, line)
+	}
+
+	date, err := time.Parse("060102", match[1])
+	if err != nil {
+		return Transaction{}, fmt.Errorf("%w: invalid value date %q", ErrMalformed, match[1])
+	}
+	amount, err := parseDecimal(match[5], ',')
+	if err != nil {
+		return Transaction{}, err
+	}
+
+	// A reversed debit returns money to the account and a reversed credit takes it out
+	direction := importer.DirectionIncome
+	if match[3] == "D" || match[3] == "RC" {
+		direction = importer.DirectionExpense
+	}
+
+	reference := strings.TrimSpace(match[6])
+	if strings.EqualFold(reference, "NONREF") {
+		reference = ""
+	}
+	if match[7] != "" {
+		reference = joinDescription(reference, match[7])
+	}
+
+	return Transaction{
+		Date:        date,
+		Description: reference,
+		Amount:      amount,
+		Direction:   direction,
+		Reference:   reference,
+	}, nil
+}
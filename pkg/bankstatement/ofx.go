@@ -0,0 +1,83 @@
+package bankstatement
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ofxTagPattern matches an SGML or XML tag and the text up to the next tag
+var ofxTagPattern = regexp.MustCompile(`<(/?)([A-Za-z0-9.]+)>([^<]*)`)
+
+// parseOFX reads OFX 1.x (SGML, closing tags optional) and 2.x (XML) statements. FITID is unique per
+// account by specification and is used for the external ID.
+func parseOFX(data []byte) (*Statement, error) {
+	statement := &Statement{}
+	var current map[string]string
+
+	finish := func() error {
+		if current == nil {
+			return nil
+		}
+		defer func() { current = nil }()
+
+		posted := current["DTPOSTED"]
+		if len(posted) < 8 {
+			return fmt.Errorf("%w: invalid DTPOSTED %q", ErrMalformed, posted)
+		}
+		date, err := time.Parse("20060102", posted[:8])
+		if err != nil {
+			return fmt.Errorf("%w: invalid DTPOSTED %q", ErrMalformed, posted)
+		}
+
+		amountText := current["TRNAMT"]
+		decimal := byte('.')
+		if strings.Contains(amountText, ",") && !strings.Contains(amountText, ".") {
+			decimal = ','
+		}
+		signed, err := parseDecimal(amountText, decimal)
+		if err != nil {
+			return err
+		}
+		direction, amount := directionOf(signed)
+
+		statement.Transactions = append(statement.Transactions, Transaction{
+			Date:        date,
+			Description: joinDescription(current["NAME"], current["MEMO"]),
+			Amount:      amount,
+			Direction:   direction,
+			Reference:   current["CHECKNUM"],
+			uniqueId:    current["FITID"],
+		})
+		return nil
+	}
+
+	for _, match := range ofxTagPattern.FindAllStringSubmatch(string(data), -1) {
+		closing, tag, value := match[1] == "/", strings.ToUpper(match[2]), strings.TrimSpace(match[3])
+
+		switch {
+		case tag == "STMTTRN" && !closing:
+			if err := finish(); err != nil {
+				return nil, err
+			}
+			current = map[string]string{}
+		case tag == "STMTTRN" || tag == "BANKTRANLIST":
+			if err := finish(); err != nil {
+				return nil, err
+			}
+		case closing:
+		case current != nil:
+			current[tag] = value
+		case tag == "ACCTID":
+			statement.AccountNumber = value
+		case tag == "CURDEF":
+			statement.Currency = value
+		}
+	}
+	if err := finish(); err != nil {
+		return nil, err
+	}
+
+	return statement, nil
+}
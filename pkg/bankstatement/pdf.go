@@ -0,0 +1,64 @@
+package bankstatement
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// pdfParser wraps a line parser so it reads PDFs, or text that was already extracted from one
+func pdfParser(parseLines func(lines []string) (*Statement, error)) func(data []byte) (*Statement, error) {
+	return func(data []byte) (*Statement, error) {
+		if !bytes.HasPrefix(data, []byte("%PDF")) {
+			return parseLines(textLines(string(data)))
+		}
+		lines, err := pdfLines(data)
+		if err != nil {
+			return nil, err
+		}
+		return parseLines(lines)
+	}
+}
+
+// pdfLines extracts the text of every page as visual rows, top to bottom
+func pdfLines(data []byte) ([]string, error) {
+	reader, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+
+	var lines []string
+	for i := 1; i <= reader.NumPage(); i++ {
+		page := reader.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+		rows, err := page.GetTextByRow()
+		if err != nil {
+			return nil, fmt.Errorf("%w: page %d: %v", ErrMalformed, i, err)
+		}
+		for _, row := range rows {
+			words := make([]string, 0, len(row.Content))
+			for _, text := range row.Content {
+				words = append(words, text.S)
+			}
+			if line := joinDescription(words...); line != "" {
+				lines = append(lines, line)
+			}
+		}
+	}
+	return lines, nil
+}
+
+// textLines splits text into trimmed, non-empty lines with single spaces
+func textLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if line = joinDescription(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,68 @@
+package bankstatement
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// parseQIF reads a Quicken Interchange Format bank or cash account. Dates follow the US month-first
+// convention, including the "3/ 1'24" short form.
+func parseQIF(data []byte) (*Statement, error) {
+	statement := &Statement{}
+	current := map[byte]string{}
+
+	for _, raw := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "!") {
+			continue
+		}
+		if line[0] != '^' {
+			current[line[0]] = strings.TrimSpace(line[1:])
+			continue
+		}
+
+		if len(current) > 0 {
+			transaction, err := qifTransaction(current)
+			if err != nil {
+				return nil, err
+			}
+			statement.Transactions = append(statement.Transactions, transaction)
+		}
+		current = map[byte]string{}
+	}
+
+	return statement, nil
+}
+
+func qifTransaction(fields map[byte]string) (Transaction, error) {
+	value := strings.NewReplacer("'", "/", " ", "").Replace(fields['D'])
+	var date time.Time
+	var err error
+	for _, layout := range []string{"1/2/2006", "1/2/06", "2006-01-02", "01-02-2006"} {
+		if date, err = time.Parse(layout, value); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return Transaction{}, fmt.Errorf("%w: invalid date %q", ErrMalformed, fields['D'])
+	}
+
+	amountText := fields['T']
+	if amountText == "" {
+		amountText = fields['U']
+	}
+	signed, err := parseDecimal(amountText, '.')
+	if err != nil {
+		return Transaction{}, err
+	}
+	direction, amount := directionOf(signed)
+
+	return Transaction{
+		Date:        date,
+		Description: joinDescription(fields['P'], fields['M']),
+		Amount:      amount,
+		Direction:   direction,
+		Reference:   fields['N'],
+	}, nil
+}
@@ -0,0 +1,185 @@
+// Package bankstatement parses bank statements into normalized transactions. It covers the CSV and
+// e-statement PDF exports of BCA, Mandiri and BRI, plus the OFX, QIF and MT940 interchange formats.
+// Every transaction gets an external ID that stays the same when the same statement is parsed again,
+// so overlapping imports can be de-duplicated.
+package bankstatement
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/saufiroja/fin-ai/pkg/importer"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown bank statement format")
+	ErrMalformed     = errors.New("malformed bank statement")
+	ErrNoTransaction = errors.New("bank statement has no transactions")
+)
+
+type Format string
+
+const (
+	FormatBCACSV     Format = "bca_csv"
+	FormatBCAPDF     Format = "bca_pdf"
+	FormatMandiriCSV Format = "mandiri_csv"
+	FormatMandiriPDF Format = "mandiri_pdf"
+	FormatBRICSV     Format = "bri_csv"
+	FormatBRIPDF     Format = "bri_pdf"
+	FormatOFX        Format = "ofx"
+	FormatQIF        Format = "qif"
+	FormatMT940      Format = "mt940"
+)
+
+// parsers turn the raw file into a statement, PDF parsers also accept text that was already extracted
+var parsers = map[Format]func(data []byte) (*Statement, error){
+	FormatBCACSV:     parseBCACSV,
+	FormatBCAPDF:     pdfParser(parseBCALines),
+	FormatMandiriCSV: parseMandiriCSV,
+	FormatMandiriPDF: pdfParser(parseMandiriLines),
+	FormatBRICSV:     parseBRICSV,
+	FormatBRIPDF:     pdfParser(parseBRILines),
+	FormatOFX:        parseOFX,
+	FormatQIF:        parseQIF,
+	FormatMT940:      parseMT940,
+}
+
+// Valid reports whether the format has a parser
+func (f Format) Valid() bool {
+	_, ok := parsers[f]
+	return ok
+}
+
+// FileType is the kind of file the format is read from, e.g. "csv" or "pdf"
+func (f Format) FileType() string {
+	if i := strings.LastIndex(string(f), "_"); i >= 0 {
+		return string(f)[i+1:]
+	}
+	return string(f)
+}
+
+// source is the bank or interchange format, it prefixes the external IDs
+func (f Format) source() string {
+	return strings.SplitN(string(f), "_", 2)[0]
+}
+
+// Transaction is one booked statement line, Amount is always positive and Direction tells the flow
+type Transaction struct {
+	ExternalId  string
+	Date        time.Time
+	Description string
+	Amount      int64
+	Direction   importer.Direction
+	Reference   string
+	// uniqueId is an identifier the format guarantees to be unique per account, such as the OFX FITID
+	uniqueId string
+}
+
+type Statement struct {
+	Format        Format
+	AccountNumber string
+	Currency      string
+	Transactions  []Transaction
+}
+
+// Parse reads a statement in the given format and assigns external IDs
+func Parse(format Format, data []byte) (*Statement, error) {
+	parse, ok := parsers[format]
+	if !ok {
+		return nil, ErrUnknownFormat
+	}
+
+	statement, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(statement.Transactions) == 0 {
+		return nil, ErrNoTransaction
+	}
+
+	statement.Format = format
+	statement.assignExternalIds()
+	return statement, nil
+}
+
+// assignExternalIds hashes the account with the format's unique ID, or with the transaction content and
+// its occurrence number when the format has none, so identical lines on one day stay distinct
+func (s *Statement) assignExternalIds() {
+	occurrences := make(map[string]int)
+	for i := range s.Transactions {
+		t := &s.Transactions[i]
+
+		key := t.uniqueId
+		if key == "" {
+			content := fmt.Sprintf("%s|%d|%d|%s|%s", t.Date.Format("2006-01-02"), t.Direction, t.Amount,
+				strings.ToLower(strings.Join(strings.Fields(t.Description), " ")), t.Reference)
+			occurrences[content]++
+			key = fmt.Sprintf("%s#%d", content, occurrences[content])
+		}
+
+		sum := sha256.Sum256([]byte(s.AccountNumber + "|" + key))
+		t.ExternalId = s.Format.source() + ":" + hex.EncodeToString(sum[:16])
+	}
+}
+
+// Table converts the statement into an import table whose headers are recognized by importer.DetectMapping
+func (s *Statement) Table() *importer.Table {
+	table := &importer.Table{
+		Headers: []string{"Date", "Description", "Amount", "Type", "Reference", "External ID"},
+		Rows:    make([][]string, 0, len(s.Transactions)),
+	}
+	for _, t := range s.Transactions {
+		direction := "income"
+		if t.Direction == importer.DirectionExpense {
+			direction = "expense"
+		}
+		table.Rows = append(table.Rows, []string{
+			t.Date.Format("2006-01-02"),
+			t.Description,
+			strconv.FormatInt(t.Amount, 10),
+			direction,
+			t.Reference,
+			t.ExternalId,
+		})
+	}
+	return table
+}
+
+// directionOf splits a signed amount into its direction and positive value
+func directionOf(amount int64) (importer.Direction, int64) {
+	if amount < 0 {
+		return importer.DirectionExpense, -amount
+	}
+	return importer.DirectionIncome, amount
+}
+
+// parseDecimal reads an amount whose decimal separator is known, thousands separators are dropped
+// and the result is rounded to whole units
+func parseDecimal(value string, decimal byte) (int64, error) {
+	s := strings.TrimSpace(value)
+	thousands := ","
+	if decimal == ',' {
+		thousands = "."
+	}
+	s = strings.ReplaceAll(strings.ReplaceAll(s, thousands, ""), " ", "")
+	if decimal == ',' {
+		s = strings.Replace(s, ",", ".", 1)
+	}
+
+	number, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid amount %q", ErrMalformed, value)
+	}
+	return int64(math.Round(number)), nil
+}
+
+// joinDescription joins non-empty parts with single spaces
+func joinDescription(parts ...string) string {
+	return strings.Join(strings.Fields(strings.Join(parts, " ")), " ")
+}
@@ -0,0 +1,244 @@
+package bankstatement
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/saufiroja/fin-ai/pkg/importer"
+)
+
+// row is a normalized transaction, amounts are signed: negative for money leaving the account
+type row struct {
+	date        string
+	amount      int64
+	description string
+}
+
+func TestParseFixtures(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   Format
+		file     string
+		account  string
+		currency string
+		rows     []row
+		// opening and closing are the balances printed on the statement, zero when it has none
+		opening, closing int64
+	}{
+		{
+			name:     "BCA CSV",
+			format:   FormatBCACSV,
+			file:     "bca.csv",
+			account:  "0123456789",
+			currency: "IDR",
+			rows: []row{
+				{"2023-12-28", -150000, "TRSF E-BANKING DB 2812/FTSCY/WS95051 150000.00 SITI AMINAH"},
+				{"2023-12-29", -45500, "KARTU DEBIT INDOMARET JKT"},
+				{"2023-12-29", -45500, "KARTU DEBIT INDOMARET JKT"},
+				{"2024-01-02", 8500000, "TRSF E-BANKING CR 0201/FTSCY/WS95051 PT MAJU JAYA GAJI DESEMBER"},
+				{"2024-01-05", 1235, "BUNGA"},
+			},
+			opening: 2500000,
+			closing: 10760235,
+		},
+		{
+			name:     "BCA PDF",
+			format:   FormatBCAPDF,
+			file:     "bca_estatement.pdf",
+			account:  "0123456789",
+			currency: "IDR",
+			rows: []row{
+				{"2024-03-01", -150000, "TRSF E-BANKING DB 0103/FTSCY/WS95051 150000.00 SITI AMINAH"},
+				{"2024-03-01", -45500, "KARTU DEBIT INDOMARET JKT"},
+				{"2024-03-05", 8500000, "TRSF E-BANKING CR 0503/FTSCY/WS95051 PT MAJU JAYA GAJI MARET"},
+				{"2024-03-31", 1235, "BUNGA"},
+				{"2024-03-31", -247, "PAJAK BUNGA"},
+			},
+			opening: 2500000,
+			closing: 10805488,
+		},
+		{
+			name:     "Mandiri CSV",
+			format:   FormatMandiriCSV,
+			file:     "mandiri.csv",
+			account:  "1230004567890",
+			currency: "IDR",
+			rows: []row{
+				{"2024-03-01", 8500000, "TRANSFER DARI PT MAJU JAYA GAJI"},
+				{"2024-03-02", -45000, "PEMBAYARAN QRIS KOPI KENANGAN"},
+				{"2024-03-02", -45000, "PEMBAYARAN QRIS KOPI KENANGAN"},
+				{"2024-03-05", -500000, "TARIK TUNAI ATM MANDIRI SUDIRMAN"},
+			},
+		},
+		{
+			name:     "Mandiri PDF",
+			format:   FormatMandiriPDF,
+			file:     "mandiri_estatement.pdf",
+			account:  "1230004567890",
+			currency: "IDR",
+			rows: []row{
+				{"2024-03-01", 8500000, "Transfer dari PT MAJU JAYA Gaji Maret"},
+				{"2024-03-02", -45000, "Pembayaran QRIS KOPI KENANGAN"},
+				{"2024-03-05", -500000, "Tarik Tunai ATM MANDIRI SUDIRMAN"},
+			},
+			opening: 2500000,
+			closing: 10455000,
+		},
+		{
+			name:     "BRI CSV",
+			format:   FormatBRICSV,
+			file:     "bri.csv",
+			currency: "IDR",
+			rows: []row{
+				{"2024-03-01", -150000, "TRANSFER KE 0021 SITI AMINAH"},
+				{"2024-03-03", -200000, "BRIVA PLN PRABAYAR 5123456789"},
+				{"2024-03-05", 8500000, "TRANSFER DARI PT MAJU JAYA"},
+			},
+			opening: 2500000,
+			closing: 10650000,
+		},
+		{
+			name:     "BRI PDF",
+			format:   FormatBRIPDF,
+			file:     "bri_estatement.pdf",
+			account:  "0123-01-000123-56-7",
+			currency: "IDR",
+			rows: []row{
+				{"2024-03-01", -150000, "TRANSFER KE 0021 SITI AMINAH"},
+				{"2024-03-03", -200000, "BRIVA PLN PRABAYAR 5123456789"},
+				{"2024-03-05", 8500000, "TRANSFER DARI PT MAJU JAYA"},
+			},
+			opening: 2500000,
+			closing: 10650000,
+		},
+		{
+			name:     "OFX",
+			format:   FormatOFX,
+			file:     "statement.ofx",
+			account:  "987654321",
+			currency: "USD",
+			rows: []row{
+				{"2024-03-02", -43, "BLUE BOTTLE COFFEE CARD 1234"},
+				{"2024-03-15", 3200, "ACME CORP PAYROLL"},
+				{"2024-03-20", -1250, "RENT MARCH"},
+			},
+		},
+		{
+			name:   "QIF",
+			format: FormatQIF,
+			file:   "statement.qif",
+			rows: []row{
+				{"2024-03-02", -43, "Blue Bottle Coffee Card 1234"},
+				{"2024-03-15", 3200, "Acme Corp Payroll"},
+				{"2024-03-20", -1250, "Landlord Rent March"},
+			},
+		},
+		{
+			name:     "MT940",
+			format:   FormatMT940,
+			file:     "statement.mt940",
+			account:  "BMRIIDJA/1230004567890",
+			currency: "IDR",
+			rows: []row{
+				{"2024-03-01", 8500000, "TRANSFER DARI PT MAJU JAYA GAJI MARET"},
+				{"2024-03-02", -45000, "PEMBAYARAN QRIS KOPI KENANGAN"},
+				{"2024-03-05", -500000, "TARIK TUNAI ATM MANDIRI SUDIRMAN"},
+			},
+			opening: 2500000,
+			closing: 10455000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			statement, err := Parse(tt.format, data)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if statement.AccountNumber != tt.account {
+				t.Errorf("AccountNumber = %q, want %q", statement.AccountNumber, tt.account)
+			}
+			if statement.Currency != tt.currency {
+				t.Errorf("Currency = %q, want %q", statement.Currency, tt.currency)
+			}
+			if len(statement.Transactions) != len(tt.rows) {
+				t.Fatalf("got %d transactions, want %d", len(statement.Transactions), len(tt.rows))
+			}
+
+			var net int64
+			for i, want := range tt.rows {
+				got := statement.Transactions[i]
+				amount := got.Amount
+				if got.Direction == importer.DirectionExpense {
+					amount = -amount
+				}
+				net += amount
+
+				if date := got.Date.Format("2006-01-02"); date != want.date {
+					t.Errorf("row %d: date = %s, want %s", i, date, want.date)
+				}
+				if amount != want.amount {
+					t.Errorf("row %d: amount = %d, want %d", i, amount, want.amount)
+				}
+				if got.Description != want.description {
+					t.Errorf("row %d: description = %q, want %q", i, got.Description, want.description)
+				}
+			}
+
+			// The rows must carry the whole movement between the printed balances
+			if tt.opening != 0 || tt.closing != 0 {
+				if net != tt.closing-tt.opening {
+					t.Errorf("net flow = %d, want closing - opening = %d", net, tt.closing-tt.opening)
+				}
+			}
+
+			seen := make(map[string]bool)
+			for i, transaction := range statement.Transactions {
+				if transaction.ExternalId == "" {
+					t.Errorf("row %d has no external ID", i)
+				}
+				if seen[transaction.ExternalId] {
+					t.Errorf("row %d: external ID %s is not unique", i, transaction.ExternalId)
+				}
+				seen[transaction.ExternalId] = true
+			}
+
+			again, err := Parse(tt.format, data)
+			if err != nil {
+				t.Fatalf("second Parse() error = %v", err)
+			}
+			for i := range statement.Transactions {
+				if again.Transactions[i].ExternalId != statement.Transactions[i].ExternalId {
+					t.Errorf("row %d: external ID changed between parses: %s != %s", i,
+						again.Transactions[i].ExternalId, statement.Transactions[i].ExternalId)
+				}
+			}
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		data   string
+		want   error
+	}{
+		{"unknown format", Format("xls"), "a,b", ErrUnknownFormat},
+		{"empty QIF", FormatQIF, "!Type:Bank\n", ErrNoTransaction},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.format, []byte(tt.data)); err != tt.want {
+				t.Errorf("Parse() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
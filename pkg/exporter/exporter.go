@@ -0,0 +1,237 @@
+// Package exporter writes rows with a fixed column order as CSV, XLSX or JSON. Rows are written one
+// at a time so callers can stream a database cursor without holding the result in memory.
+package exporter
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported export format, use csv, xlsx or json")
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+	FormatJSON Format = "json"
+)
+
+// listSeparator joins list values in CSV and XLSX cells
+const listSeparator = "; "
+
+// ParseFormat returns the format for a name, an empty name is CSV
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(name))) {
+	case "", FormatCSV:
+		return FormatCSV, nil
+	case FormatXLSX:
+		return FormatXLSX, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	}
+	return "", ErrUnsupportedFormat
+}
+
+// ContentType is the MIME type of the format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatJSON:
+		return "application/json"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Writer writes rows whose values follow the column order. Values may be string, int64, int, float64,
+// bool, []string or nil. Close must be called to complete the output.
+type Writer interface {
+	Write(values []any) error
+	Close() error
+}
+
+// NewWriter starts an export, CSV and XLSX write the columns as the header row
+func NewWriter(format Format, w io.Writer, columns []string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, columns)
+	case FormatXLSX:
+		return newXLSXWriter(w, columns)
+	case FormatJSON:
+		return newJSONWriter(w, columns), nil
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+	record []string
+}
+
+func newCSVWriter(w io.Writer, columns []string) (*csvWriter, error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return nil, err
+	}
+	return &csvWriter{writer: writer, record: make([]string, len(columns))}, nil
+}
+
+func (c *csvWriter) Write(values []any) error {
+	for i := range c.record {
+		c.record[i] = ""
+		if i < len(values) {
+			c.record[i] = formatText(values[i])
+		}
+	}
+	return c.writer.Write(c.record)
+}
+
+func (c *csvWriter) Close() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+// xlsxWriter uses the excelize stream writer, which spills large sheets to a temporary file. The
+// workbook can only be written out once the last row is known, so the output is sent on Close.
+type xlsxWriter struct {
+	out    io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXLSXWriter(w io.Writer, columns []string) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+	stream, err := file.NewStreamWriter("Sheet1")
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	x := &xlsxWriter{out: w, file: file, stream: stream}
+	header := make([]any, len(columns))
+	for i, column := range columns {
+		header[i] = column
+	}
+	if err := x.Write(header); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return x, nil
+}
+
+func (x *xlsxWriter) Write(values []any) error {
+	x.row++
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+
+	row := make([]any, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case []string:
+			row[i] = strings.Join(v, listSeparator)
+		case nil:
+			row[i] = ""
+		default:
+			row[i] = v
+		}
+	}
+	return x.stream.SetRow(cell, row)
+}
+
+func (x *xlsxWriter) Close() error {
+	defer x.file.Close()
+	if err := x.stream.Flush(); err != nil {
+		return err
+	}
+	_, err := x.file.WriteTo(x.out)
+	return err
+}
+
+// jsonWriter writes an array of objects whose keys keep the column order
+type jsonWriter struct {
+	writer  *bufio.Writer
+	columns []string
+	keys    [][]byte
+	rows    int
+}
+
+func newJSONWriter(w io.Writer, columns []string) *jsonWriter {
+	keys := make([][]byte, len(columns))
+	for i, column := range columns {
+		keys[i], _ = json.Marshal(column)
+	}
+	return &jsonWriter{writer: bufio.NewWriter(w), columns: columns, keys: keys}
+}
+
+func (j *jsonWriter) Write(values []any) error {
+	if j.rows == 0 {
+		j.writer.WriteString("[\n")
+	} else {
+		j.writer.WriteString(",\n")
+	}
+	j.rows++
+
+	j.writer.WriteByte('{')
+	for i, key := range j.keys {
+		if i > 0 {
+			j.writer.WriteByte(',')
+		}
+		var value any
+		if i < len(values) {
+			value = values[i]
+		}
+		if list, ok := value.([]string); ok && list == nil {
+			value = []string{}
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("column %s: %w", j.columns[i], err)
+		}
+		j.writer.Write(key)
+		j.writer.WriteByte(':')
+		j.writer.Write(encoded)
+	}
+	_, err := j.writer.WriteString("}")
+	return err
+}
+
+func (j *jsonWriter) Close() error {
+	if j.rows == 0 {
+		j.writer.WriteString("[")
+	}
+	j.writer.WriteString("\n]\n")
+	return j.writer.Flush()
+}
+
+// formatText renders a value for a CSV cell
+func formatText(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []string:
+		return strings.Join(v, listSeparator)
+	}
+	return fmt.Sprint(value)
+}
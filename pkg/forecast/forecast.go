@@ -0,0 +1,301 @@
+// Package forecast projects a cash balance day by day from past transactions and scheduled ones.
+// It is pure and deterministic: the same input always produces the same output.
+package forecast
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Entry is an income or expense on a given day
+type Entry struct {
+	Date       time.Time
+	CategoryId string
+	Income     bool
+	Amount     int64
+}
+
+type Input struct {
+	// Today is the last observed day, the projection starts the day after
+	Today time.Time
+	// Months is the number of calendar months to project, the current month counts as the first
+	Months int
+	// StartingBalance is the balance at the end of Today
+	StartingBalance int64
+	// HistoryStart is the first day of History, days without entries count as zero
+	HistoryStart time.Time
+	// History holds unscheduled entries used to estimate day-to-day spending and income
+	History []Entry
+	// Actual holds every entry of the current month up to Today, it is added to the current month's totals
+	Actual []Entry
+	// Scheduled holds known future entries (e.g. recurring rules), entries due on or before Today are applied on the first day
+	Scheduled []Entry
+	// Z is the normal quantile of the confidence band, e.g. 1.2816 for an 80% band
+	Z float64
+}
+
+// Band is an expected value with its confidence interval
+type Band struct {
+	Expected int64
+	Low      int64
+	High     int64
+}
+
+type CategoryProjection struct {
+	CategoryId string
+	Spend      Band
+	Actual     int64 // Already spent this month, only set for the current month
+	Scheduled  int64
+}
+
+type MonthProjection struct {
+	Month            time.Time // First day of the month
+	Start            time.Time // First projected day
+	End              time.Time // Last day of the month
+	Income           Band
+	Expense          Band
+	ScheduledIncome  int64
+	ScheduledExpense int64
+	EndBalance       Band
+	Categories       []CategoryProjection
+}
+
+type DayProjection struct {
+	Date    time.Time
+	Balance Band
+}
+
+type Output struct {
+	Months []MonthProjection
+	Days   []DayProjection
+	// Lowest is the day with the lowest expected balance
+	Lowest DayProjection
+	// FirstNegative is the first day the expected balance drops below zero
+	FirstNegative *DayProjection
+	// FirstAtRisk is the first day the low end of the band drops below zero
+	FirstAtRisk *DayProjection
+}
+
+// stats is the mean and variance of a daily amount
+type stats struct {
+	mean     float64
+	variance float64
+}
+
+// Project runs the forecast. Unscheduled spending and income are modelled as independent
+// daily draws with the historical mean and variance, so over n days the expected value grows
+// with n and the band with sqrt(n).
+func Project(in Input) Output {
+	today := truncateDay(in.Today)
+	start := today.AddDate(0, 0, 1)
+	months := in.Months
+	if months < 1 {
+		months = 1
+	}
+	horizonEnd := time.Date(today.Year(), today.Month()+time.Month(months), 0, 0, 0, 0, 0, time.UTC)
+
+	historyDays := int(today.Sub(truncateDay(in.HistoryStart)).Hours()/24) + 1
+	if historyDays < 1 {
+		historyDays = 1
+	}
+
+	net := dailyStats(in.History, historyDays, func(e Entry) (string, float64) {
+		if e.Income {
+			return "", float64(e.Amount)
+		}
+		return "", -float64(e.Amount)
+	})[""]
+	income := dailyStats(in.History, historyDays, func(e Entry) (string, float64) {
+		if !e.Income {
+			return "", 0
+		}
+		return "", float64(e.Amount)
+	})[""]
+	expense := dailyStats(in.History, historyDays, func(e Entry) (string, float64) {
+		if e.Income {
+			return "", 0
+		}
+		return "", float64(e.Amount)
+	})[""]
+	categories := dailyStats(in.History, historyDays, func(e Entry) (string, float64) {
+		if e.Income {
+			return "", 0
+		}
+		return e.CategoryId, float64(e.Amount)
+	})
+	delete(categories, "")
+
+	scheduledByDay := make(map[time.Time][]Entry)
+	for _, e := range in.Scheduled {
+		day := truncateDay(e.Date)
+		if day.Before(start) {
+			day = start
+		}
+		if day.After(horizonEnd) {
+			continue
+		}
+		scheduledByDay[day] = append(scheduledByDay[day], e)
+	}
+
+	out := Output{}
+	expected := float64(in.StartingBalance)
+	variance := 0.0
+	out.Lowest = DayProjection{Date: today, Balance: band(expected, variance, in.Z)}
+
+	for monthStart := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC); !monthStart.After(horizonEnd); monthStart = monthStart.AddDate(0, 1, 0) {
+		monthEnd := monthStart.AddDate(0, 1, -1)
+		from := monthStart
+		if from.Before(start) {
+			from = start
+		}
+		days := 0
+		if !from.After(monthEnd) {
+			days = int(monthEnd.Sub(from).Hours()/24) + 1
+		}
+
+		month := MonthProjection{Month: monthStart, Start: from, End: monthEnd}
+		scheduledByCategory := make(map[string]int64)
+
+		for day := from; !day.After(monthEnd); day = day.AddDate(0, 0, 1) {
+			for _, e := range scheduledByDay[day] {
+				if e.Income {
+					month.ScheduledIncome += e.Amount
+					expected += float64(e.Amount)
+				} else {
+					month.ScheduledExpense += e.Amount
+					scheduledByCategory[e.CategoryId] += e.Amount
+					expected -= float64(e.Amount)
+				}
+			}
+			expected += net.mean
+			variance += net.variance
+
+			projection := DayProjection{Date: day, Balance: band(expected, variance, in.Z)}
+			out.Days = append(out.Days, projection)
+
+			if projection.Balance.Expected < out.Lowest.Balance.Expected {
+				out.Lowest = projection
+			}
+			if out.FirstNegative == nil && projection.Balance.Expected < 0 {
+				p := projection
+				out.FirstNegative = &p
+			}
+			if out.FirstAtRisk == nil && projection.Balance.Low < 0 {
+				p := projection
+				out.FirstAtRisk = &p
+			}
+		}
+
+		n := float64(days)
+		var actualIncome, actualExpense int64
+		actualByCategory := make(map[string]int64)
+		if monthStart.Year() == today.Year() && monthStart.Month() == today.Month() {
+			for _, e := range in.Actual {
+				if e.Income {
+					actualIncome += e.Amount
+				} else {
+					actualExpense += e.Amount
+					actualByCategory[e.CategoryId] += e.Amount
+				}
+			}
+		}
+
+		month.Income = shift(totalBand(income, n, in.Z), actualIncome+month.ScheduledIncome)
+		month.Expense = shift(totalBand(expense, n, in.Z), actualExpense+month.ScheduledExpense)
+		month.EndBalance = band(expected, variance, in.Z)
+
+		categoryIds := make(map[string]bool)
+		for id := range categories {
+			categoryIds[id] = true
+		}
+		for id := range scheduledByCategory {
+			categoryIds[id] = true
+		}
+		for id := range actualByCategory {
+			categoryIds[id] = true
+		}
+
+		for id := range categoryIds {
+			fixed := actualByCategory[id] + scheduledByCategory[id]
+			month.Categories = append(month.Categories, CategoryProjection{
+				CategoryId: id,
+				Spend:      shift(totalBand(categories[id], n, in.Z), fixed),
+				Actual:     actualByCategory[id],
+				Scheduled:  scheduledByCategory[id],
+			})
+		}
+		sort.Slice(month.Categories, func(i, j int) bool {
+			if month.Categories[i].Spend.Expected != month.Categories[j].Spend.Expected {
+				return month.Categories[i].Spend.Expected > month.Categories[j].Spend.Expected
+			}
+			return month.Categories[i].CategoryId < month.Categories[j].CategoryId
+		})
+
+		out.Months = append(out.Months, month)
+	}
+
+	return out
+}
+
+// dailyStats computes the per-day mean and variance of the values keyed by keyFn, counting days without entries as zero
+func dailyStats(entries []Entry, days int, keyFn func(Entry) (string, float64)) map[string]stats {
+	perDay := make(map[string]map[time.Time]float64)
+	for _, e := range entries {
+		key, value := keyFn(e)
+		if value == 0 {
+			continue
+		}
+		if perDay[key] == nil {
+			perDay[key] = make(map[time.Time]float64)
+		}
+		perDay[key][truncateDay(e.Date)] += value
+	}
+
+	result := make(map[string]stats, len(perDay))
+	for key, totals := range perDay {
+		var sum, sumSquares float64
+		for _, v := range totals {
+			sum += v
+			sumSquares += v * v
+		}
+		mean := sum / float64(days)
+		variance := sumSquares/float64(days) - mean*mean
+		if variance < 0 {
+			variance = 0
+		}
+		result[key] = stats{mean: mean, variance: variance}
+	}
+
+	return result
+}
+
+// totalBand is the band of the sum of n independent daily draws, the low end never goes below zero
+func totalBand(s stats, n, z float64) Band {
+	b := band(s.mean*n, s.variance*n, z)
+	if b.Low < 0 {
+		b.Low = 0
+	}
+	return b
+}
+
+func band(expected, variance, z float64) Band {
+	spread := z * math.Sqrt(variance)
+	return Band{
+		Expected: int64(math.Round(expected)),
+		Low:      int64(math.Round(expected - spread)),
+		High:     int64(math.Round(expected + spread)),
+	}
+}
+
+func shift(b Band, amount int64) Band {
+	return Band{
+		Expected: b.Expected + amount,
+		Low:      b.Low + amount,
+		High:     b.High + amount,
+	}
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
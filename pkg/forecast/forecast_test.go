@@ -0,0 +1,172 @@
+package forecast
+
+import (
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// fixture is ten days of history: 10,000 on food every day and one 50,000 transport expense, so the daily
+// spend has a mean of 15,000 and a standard deviation of 15,000. Rent of 2,000,000 is due on the 15th and a
+// salary of 5,000,000 on the 1st.
+func fixture(months int) Input {
+	in := Input{
+		Today:           date(2024, 3, 10),
+		Months:          months,
+		StartingBalance: 3000000,
+		HistoryStart:    date(2024, 3, 1),
+		Z:               2,
+	}
+	for day := 1; day <= 10; day++ {
+		in.History = append(in.History, Entry{Date: date(2024, 3, day), CategoryId: "food", Amount: 10000})
+	}
+	in.History = append(in.History, Entry{Date: date(2024, 3, 5), CategoryId: "transport", Amount: 50000})
+	in.Actual = in.History
+
+	for month := time.March; month <= time.May; month++ {
+		in.Scheduled = append(in.Scheduled, Entry{Date: date(2024, month, 15), CategoryId: "rent", Amount: 2000000})
+		if month > time.March {
+			in.Scheduled = append(in.Scheduled, Entry{Date: date(2024, month, 1), CategoryId: "salary", Income: true, Amount: 5000000})
+		}
+	}
+	return in
+}
+
+type wantMonth struct {
+	month      time.Time
+	endBalance Band
+	income     Band
+	expense    Band
+	categories []CategoryProjection
+}
+
+// Expected values are worked out by hand: n projected days add n * -15,000 to the balance and
+// 2 * 15,000 * sqrt(n) to the spread of the band
+var months = []wantMonth{
+	{
+		month:      date(2024, 3, 1),
+		endBalance: Band{Expected: 685000, Low: 547523, High: 822477},
+		expense:    Band{Expected: 2465000, Low: 2327523, High: 2602477},
+		categories: []CategoryProjection{
+			{CategoryId: "rent", Spend: Band{2000000, 2000000, 2000000}, Scheduled: 2000000},
+			{CategoryId: "food", Spend: Band{310000, 310000, 310000}, Actual: 100000},
+			{CategoryId: "transport", Spend: Band{155000, 50000, 292477}, Actual: 50000},
+		},
+	},
+	{
+		month:      date(2024, 4, 1),
+		endBalance: Band{Expected: 3235000, Low: 3020757, High: 3449243},
+		income:     Band{5000000, 5000000, 5000000},
+		expense:    Band{Expected: 2450000, Low: 2285683, High: 2614317},
+		categories: []CategoryProjection{
+			{CategoryId: "rent", Spend: Band{2000000, 2000000, 2000000}, Scheduled: 2000000},
+			{CategoryId: "food", Spend: Band{300000, 300000, 300000}},
+			{CategoryId: "transport", Spend: Band{150000, 0, 314317}},
+		},
+	},
+	{
+		month:      date(2024, 5, 1),
+		endBalance: Band{Expected: 5770000, Low: 5498338, High: 6041662},
+		income:     Band{5000000, 5000000, 5000000},
+		expense:    Band{Expected: 2465000, Low: 2297967, High: 2632033},
+		categories: []CategoryProjection{
+			{CategoryId: "rent", Spend: Band{2000000, 2000000, 2000000}, Scheduled: 2000000},
+			{CategoryId: "food", Spend: Band{310000, 310000, 310000}},
+			{CategoryId: "transport", Spend: Band{155000, 0, 322033}},
+		},
+	},
+}
+
+func TestProject(t *testing.T) {
+	tests := []struct {
+		name   string
+		months int
+		days   int
+	}{
+		{"one month", 1, 21},
+		{"two months", 2, 51},
+		{"three months", 3, 82},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := Project(fixture(tt.months))
+
+			if len(out.Months) != tt.months {
+				t.Fatalf("got %d months, want %d", len(out.Months), tt.months)
+			}
+			if len(out.Days) != tt.days {
+				t.Errorf("got %d days, want %d", len(out.Days), tt.days)
+			}
+
+			for i, got := range out.Months {
+				want := months[i]
+				if !got.Month.Equal(want.month) {
+					t.Errorf("month %d = %s, want %s", i, got.Month.Format("2006-01"), want.month.Format("2006-01"))
+				}
+				if got.EndBalance != want.endBalance {
+					t.Errorf("%s end balance = %+v, want %+v", want.month.Format("2006-01"), got.EndBalance, want.endBalance)
+				}
+				if got.EndBalance.Low > got.EndBalance.Expected || got.EndBalance.Expected > got.EndBalance.High {
+					t.Errorf("%s end balance %+v is not ordered", want.month.Format("2006-01"), got.EndBalance)
+				}
+				if got.Income != want.income {
+					t.Errorf("%s income = %+v, want %+v", want.month.Format("2006-01"), got.Income, want.income)
+				}
+				if got.Expense != want.expense {
+					t.Errorf("%s expense = %+v, want %+v", want.month.Format("2006-01"), got.Expense, want.expense)
+				}
+
+				if len(got.Categories) != len(want.categories) {
+					t.Fatalf("%s has %d categories, want %d", want.month.Format("2006-01"), len(got.Categories), len(want.categories))
+				}
+				for j, category := range got.Categories {
+					if category != want.categories[j] {
+						t.Errorf("%s category %d = %+v, want %+v", want.month.Format("2006-01"), j, category, want.categories[j])
+					}
+				}
+			}
+
+			// The balance bottoms out the day before the first salary
+			if !out.Lowest.Date.Equal(date(2024, 3, 31)) || out.Lowest.Balance.Expected != 685000 {
+				t.Errorf("lowest = %s %+v, want 2024-03-31 685000", out.Lowest.Date.Format("2006-01-02"), out.Lowest.Balance)
+			}
+			if out.FirstNegative != nil || out.FirstAtRisk != nil {
+				t.Errorf("balance never drops below zero, got first negative %v, first at risk %v", out.FirstNegative, out.FirstAtRisk)
+			}
+		})
+	}
+}
+
+func TestProjectRunsOut(t *testing.T) {
+	in := fixture(2)
+	in.StartingBalance = 2100000
+
+	out := Project(in)
+
+	// Five days of spending and the rent on the 15th leave 25,000, two days later the balance is below zero
+	if out.FirstNegative == nil || !out.FirstNegative.Date.Equal(date(2024, 3, 17)) {
+		t.Fatalf("first negative = %+v, want 2024-03-17", out.FirstNegative)
+	}
+	if out.FirstNegative.Balance.Expected != -5000 {
+		t.Errorf("first negative balance = %d, want -5000", out.FirstNegative.Balance.Expected)
+	}
+	if out.FirstAtRisk == nil || out.FirstAtRisk.Date.After(out.FirstNegative.Date) {
+		t.Errorf("first at risk = %+v, want on or before the first negative day", out.FirstAtRisk)
+	}
+}
+
+func TestProjectWithoutHistory(t *testing.T) {
+	out := Project(Input{Today: date(2024, 3, 10), Months: 1, StartingBalance: 1000, HistoryStart: date(2024, 3, 10), Z: 2})
+
+	want := Band{1000, 1000, 1000}
+	if got := out.Months[0].EndBalance; got != want {
+		t.Errorf("end balance = %+v, want %+v", got, want)
+	}
+	if len(out.Months[0].Categories) != 0 {
+		t.Errorf("got categories %+v, want none", out.Months[0].Categories)
+	}
+}
@@ -0,0 +1,88 @@
+// Package fx knows the supported currencies and converts amounts between them. Amounts are integers in
+// the minor unit of their currency (whole Rupiah and Yen, cents for Dollars). Exchange rates come from
+// a Provider, Table is an in-memory rate table that can be loaded from a file or from the database.
+package fx
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+var ErrUnknownCurrency = errors.New("unsupported currency code")
+
+type Currency struct {
+	Code     string `json:"code"`
+	Symbol   string `json:"symbol"`
+	Decimals int    `json:"decimals"`
+}
+
+var currencies = map[string]Currency{
+	"IDR": {Code: "IDR", Symbol: "Rp", Decimals: 0},
+	"SGD": {Code: "SGD", Symbol: "S$", Decimals: 2},
+	"USD": {Code: "USD", Symbol: "US$", Decimals: 2},
+	"JPY": {Code: "JPY", Symbol: "¥", Decimals: 0},
+	"MYR": {Code: "MYR", Symbol: "RM", Decimals: 2},
+	"THB": {Code: "THB", Symbol: "฿", Decimals: 2},
+	"EUR": {Code: "EUR", Symbol: "€", Decimals: 2},
+	"AUD": {Code: "AUD", Symbol: "A$", Decimals: 2},
+	"KRW": {Code: "KRW", Symbol: "₩", Decimals: 0},
+	"CNY": {Code: "CNY", Symbol: "CN¥", Decimals: 2},
+	"SAR": {Code: "SAR", Symbol: "SR", Decimals: 2},
+}
+
+// Lookup returns the currency of an ISO 4217 code, the code is case insensitive
+func Lookup(code string) (Currency, error) {
+	currency, ok := currencies[strings.ToUpper(strings.TrimSpace(code))]
+	if !ok {
+		return Currency{}, fmt.Errorf("%w: %s", ErrUnknownCurrency, code)
+	}
+	return currency, nil
+}
+
+// Supported lists every supported currency ordered by code
+func Supported() []Currency {
+	list := make([]Currency, 0, len(currencies))
+	for _, currency := range currencies {
+		list = append(list, currency)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Code < list[j].Code })
+	return list
+}
+
+// Format renders an amount in minor units with the Indonesian thousand and decimal separators,
+// e.g. "Rp 1.500.000" or "US$ 12,50"
+func Format(amount int64, currency Currency) string {
+	return FormatLocale(amount, currency, "id")
+}
+
+// FormatLocale renders an amount in minor units with the separators of a BCP 47 locale, e.g.
+// "US$ 12.50" for "en-US". An unknown locale uses the English separators.
+func FormatLocale(amount int64, currency Currency, locale string) string {
+	p := message.NewPrinter(language.Make(locale))
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	if currency.Decimals == 0 {
+		return fmt.Sprintf("%s %s%s", currency.Symbol, sign, p.Sprintf("%d", amount))
+	}
+
+	value := float64(amount) / float64(pow10(currency.Decimals))
+	return fmt.Sprintf("%s %s%s", currency.Symbol, sign, p.Sprint(number.Decimal(value, number.Scale(currency.Decimals))))
+}
+
+func pow10(n int) int64 {
+	result := int64(1)
+	for i := 0; i < n; i++ {
+		result *= 10
+	}
+	return result
+}
@@ -0,0 +1,80 @@
+package fx
+
+import (
+	"errors"
+	"sort"
+	"testing"
+)
+
+func mustLookup(t *testing.T, code string) Currency {
+	t.Helper()
+	currency, err := Lookup(code)
+	if err != nil {
+		t.Fatalf("Lookup(%q) error = %v", code, err)
+	}
+	return currency
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		code     string
+		want     string
+		decimals int
+		err      error
+	}{
+		{code: "IDR", want: "IDR", decimals: 0},
+		{code: " usd ", want: "USD", decimals: 2},
+		{code: "jpy", want: "JPY", decimals: 0},
+		{code: "XXX", err: ErrUnknownCurrency},
+		{code: "", err: ErrUnknownCurrency},
+	}
+
+	for _, tt := range tests {
+		got, err := Lookup(tt.code)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Lookup(%q) error = %v, want %v", tt.code, err, tt.err)
+			continue
+		}
+		if got.Code != tt.want || got.Decimals != tt.decimals {
+			t.Errorf("Lookup(%q) = %s with %d decimals, want %s with %d", tt.code, got.Code, got.Decimals, tt.want, tt.decimals)
+		}
+	}
+}
+
+func TestSupported(t *testing.T) {
+	list := Supported()
+	if len(list) != len(currencies) {
+		t.Fatalf("Supported() has %d currencies, want %d", len(list), len(currencies))
+	}
+	if !sort.SliceIsSorted(list, func(i, j int) bool { return list[i].Code < list[j].Code }) {
+		t.Errorf("Supported() is not ordered by code: %v", list)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		currency string
+		locale   string
+		want     string
+	}{
+		{amount: 150000000, currency: "IDR", locale: "id", want: "Rp 150.000.000"},
+		{amount: -2500000, currency: "IDR", locale: "id", want: "Rp -2.500.000"},
+		{amount: 1250, currency: "USD", locale: "id", want: "US$ 12,50"},
+		{amount: 125050, currency: "USD", locale: "en-US", want: "US$ 1,250.50"},
+		{amount: -125050, currency: "USD", locale: "en-US", want: "US$ -1,250.50"},
+		{amount: 1500, currency: "JPY", locale: "id", want: "¥ 1.500"},
+		{amount: 1500000, currency: "IDR", locale: "xx", want: "Rp 1,500,000"},
+	}
+
+	for _, tt := range tests {
+		got := FormatLocale(tt.amount, mustLookup(t, tt.currency), tt.locale)
+		if got != tt.want {
+			t.Errorf("FormatLocale(%d, %s, %s) = %q, want %q", tt.amount, tt.currency, tt.locale, got, tt.want)
+		}
+	}
+
+	if got := Format(1250, mustLookup(t, "USD")); got != "US$ 12,50" {
+		t.Errorf("Format() = %q, want the Indonesian separators", got)
+	}
+}
@@ -0,0 +1,87 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+var ErrRateNotFound = errors.New("no exchange rate available")
+
+// Rate is the price of one unit of Base in Quote, in major units, valid from Date until a newer rate
+type Rate struct {
+	Base   string    `json:"base"`
+	Quote  string    `json:"quote"`
+	Date   time.Time `json:"date"`
+	Value  float64   `json:"rate"`
+	Source string    `json:"source,omitempty"`
+}
+
+// Provider returns the rate from base to quote in effect on date
+type Provider interface {
+	Rate(ctx context.Context, base, quote string, date time.Time) (Rate, error)
+}
+
+// Chain asks each provider in turn and returns the first rate found, so a live or database provider
+// can be backed by an offline file
+type Chain []Provider
+
+func (c Chain) Rate(ctx context.Context, base, quote string, date time.Time) (Rate, error) {
+	for _, provider := range c {
+		rate, err := provider.Rate(ctx, base, quote, date)
+		if err == nil {
+			return rate, nil
+		}
+		if !errors.Is(err, ErrRateNotFound) {
+			return Rate{}, err
+		}
+	}
+	return Rate{}, fmt.Errorf("%w for %s/%s on %s", ErrRateNotFound, base, quote, date.Format("2006-01-02"))
+}
+
+// Convert turns an amount in minor units of from into minor units of to, rate is the price of one
+// from in to. The result is rounded half away from zero.
+func Convert(amount int64, from, to Currency, rate float64) int64 {
+	if from.Code == to.Code {
+		return amount
+	}
+	major := float64(amount) / float64(pow10(from.Decimals))
+	return int64(math.Round(major * rate * float64(pow10(to.Decimals))))
+}
+
+// Distribute converts parts of a total so that the converted parts still add up to converted, the
+// rounding difference is put on the largest part
+func Distribute(parts []int64, converted int64) []int64 {
+	var total int64
+	largest := 0
+	for i, part := range parts {
+		total += part
+		if part > parts[largest] {
+			largest = i
+		}
+	}
+
+	result := make([]int64, len(parts))
+	if total == 0 {
+		return result
+	}
+
+	var sum int64
+	for i, part := range parts {
+		result[i] = int64(math.Round(float64(part) * float64(converted) / float64(total)))
+		sum += result[i]
+	}
+	result[largest] += converted - sum
+	return result
+}
+
+// Conversion is an amount converted at Rate, both amounts are in minor units
+type Conversion struct {
+	From      Currency `json:"from"`
+	To        Currency `json:"to"`
+	Amount    int64    `json:"amount"`
+	Converted int64    `json:"converted"`
+	Rate      Rate     `json:"rate"`
+}
@@ -0,0 +1,134 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   int64
+		from, to string
+		rate     float64
+		want     int64
+	}{
+		{name: "same currency ignores the rate", amount: 150000, from: "IDR", to: "IDR", rate: 2, want: 150000},
+		{name: "cents to whole units", amount: 1250, from: "USD", to: "IDR", rate: 16250, want: 203125},
+		{name: "whole units to cents", amount: 15000, from: "IDR", to: "USD", rate: 1.0 / 16000, want: 94},
+		{name: "half rounds away from zero", amount: 50, from: "USD", to: "JPY", rate: 3, want: 2},
+		{name: "negative half rounds away from zero", amount: -50, from: "USD", to: "JPY", rate: 3, want: -2},
+		{name: "zero decimals to two decimals", amount: 100000, from: "KRW", to: "SGD", rate: 0.001, want: 10000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Convert(tt.amount, mustLookup(t, tt.from), mustLookup(t, tt.to), tt.rate)
+			if got != tt.want {
+				t.Errorf("Convert(%d %s -> %s at %v) = %d, want %d", tt.amount, tt.from, tt.to, tt.rate, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDistribute(t *testing.T) {
+	tests := []struct {
+		name      string
+		parts     []int64
+		converted int64
+		want      []int64
+	}{
+		{name: "proportional", parts: []int64{100, 200, 300}, converted: 1000, want: []int64{167, 333, 500}},
+		{name: "rounding difference on the largest part", parts: []int64{1, 1, 1}, converted: 100, want: []int64{34, 33, 33}},
+		{name: "zero total", parts: []int64{0, 0}, converted: 100, want: []int64{0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Distribute(tt.parts, tt.converted)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Distribute(%v, %d) = %v, want %v", tt.parts, tt.converted, got, tt.want)
+			}
+		})
+	}
+}
+
+// stubProvider answers with a fixed rate or error and counts how often it was asked
+type stubProvider struct {
+	rate  Rate
+	err   error
+	calls int
+}
+
+func (p *stubProvider) Rate(ctx context.Context, base, quote string, date time.Time) (Rate, error) {
+	p.calls++
+	return p.rate, p.err
+}
+
+func TestChain(t *testing.T) {
+	errDown := errors.New("provider down")
+	live := Rate{Base: "USD", Quote: "IDR", Value: 16250, Source: "live"}
+	file := Rate{Base: "USD", Quote: "IDR", Value: 16000, Source: "file"}
+
+	tests := []struct {
+		name      string
+		providers []*stubProvider
+		want      Rate
+		err       error
+		calls     []int
+	}{
+		{
+			name:      "first provider wins",
+			providers: []*stubProvider{{rate: live}, {rate: file}},
+			want:      live,
+			calls:     []int{1, 0},
+		},
+		{
+			name:      "falls back when a rate is missing",
+			providers: []*stubProvider{{err: ErrRateNotFound}, {rate: file}},
+			want:      file,
+			calls:     []int{1, 1},
+		},
+		{
+			name:      "other errors stop the chain",
+			providers: []*stubProvider{{err: errDown}, {rate: file}},
+			err:       errDown,
+			calls:     []int{1, 0},
+		},
+		{
+			name:      "no provider has the rate",
+			providers: []*stubProvider{{err: ErrRateNotFound}, {err: ErrRateNotFound}},
+			err:       ErrRateNotFound,
+			calls:     []int{1, 1},
+		},
+		{
+			name: "empty chain",
+			err:  ErrRateNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var chain Chain
+			for _, provider := range tt.providers {
+				chain = append(chain, provider)
+			}
+
+			got, err := chain.Rate(context.Background(), "USD", "IDR", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Rate() error = %v, want %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("Rate() = %+v, want %+v", got, tt.want)
+			}
+			for i, provider := range tt.providers {
+				if provider.calls != tt.calls[i] {
+					t.Errorf("provider %d asked %d times, want %d", i, provider.calls, tt.calls[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package fx
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// RateStore is a persistent rate table, typically a database table maintained by users or a sync job
+type RateStore interface {
+	// LatestRates returns for every stored pair its latest rate on or before date
+	LatestRates(date time.Time) ([]Rate, error)
+}
+
+type storeProvider struct {
+	store RateStore
+}
+
+// NewStoreProvider resolves rates from a RateStore with the same direct, inverse and cross lookups as Table
+func NewStoreProvider(store RateStore) Provider {
+	return &storeProvider{store: store}
+}
+
+func (s *storeProvider) Rate(ctx context.Context, base, quote string, date time.Time) (Rate, error) {
+	// Same currency needs no lookup, most transactions are in the base currency
+	if strings.EqualFold(base, quote) {
+		return NewTable(nil).Rate(ctx, base, quote, date)
+	}
+	rates, err := s.store.LatestRates(date)
+	if err != nil {
+		return Rate{}, err
+	}
+	return NewTable(rates).Rate(ctx, base, quote, date)
+}
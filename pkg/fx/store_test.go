@@ -0,0 +1,58 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+type stubStore struct {
+	rates []Rate
+	err   error
+	dates []time.Time
+}
+
+func (s *stubStore) LatestRates(date time.Time) ([]Rate, error) {
+	s.dates = append(s.dates, date)
+	return s.rates, s.err
+}
+
+func TestStoreProvider(t *testing.T) {
+	store := &stubStore{rates: []Rate{
+		{Base: "USD", Quote: "IDR", Date: day("2024-03-01"), Value: 16000, Source: "manual"},
+		{Base: "SGD", Quote: "IDR", Date: day("2024-03-01"), Value: 11800, Source: "manual"},
+	}}
+	provider := NewStoreProvider(store)
+	date := day("2024-03-05")
+
+	same, err := provider.Rate(context.Background(), "IDR", "idr", date)
+	if err != nil || same.Value != 1 {
+		t.Fatalf("same currency = %v, %v, want 1", same.Value, err)
+	}
+	if len(store.dates) != 0 {
+		t.Errorf("store read %d times for the same currency, want none", len(store.dates))
+	}
+
+	cross, err := provider.Rate(context.Background(), "USD", "SGD", date)
+	if err != nil {
+		t.Fatalf("Rate() error = %v", err)
+	}
+	if math.Abs(cross.Value-16000.0/11800) > 1e-12 || cross.Source != "cross:IDR" {
+		t.Errorf("cross rate = %v from %s, want %v through IDR", cross.Value, cross.Source, 16000.0/11800)
+	}
+	if len(store.dates) != 1 || !store.dates[0].Equal(date) {
+		t.Errorf("store read on %v, want once on %v", store.dates, date)
+	}
+
+	if _, err := provider.Rate(context.Background(), "JPY", "IDR", date); !errors.Is(err, ErrRateNotFound) {
+		t.Errorf("missing pair error = %v, want ErrRateNotFound so a Chain falls back", err)
+	}
+
+	errDown := errors.New("database down")
+	failing := NewStoreProvider(&stubStore{err: errDown})
+	if _, err := failing.Rate(context.Background(), "USD", "IDR", date); !errors.Is(err, errDown) {
+		t.Errorf("store error = %v, want %v", err, errDown)
+	}
+}
@@ -0,0 +1,196 @@
+package fx
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Table is an in-memory rate table. A pair is resolved from its own rates, the inverse pair, or
+// through a third currency both sides have a rate with, always using the latest rate on or before
+// the requested date.
+type Table struct {
+	mu    sync.RWMutex
+	rates map[string][]Rate // by "BASE/QUOTE", sorted by date
+}
+
+func NewTable(rates []Rate) *Table {
+	table := &Table{rates: make(map[string][]Rate)}
+	table.Add(rates...)
+	return table
+}
+
+// Add stores rates, a rate for a pair and date that already exists is replaced
+func (t *Table) Add(rates ...Rate) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, rate := range rates {
+		rate.Base = strings.ToUpper(rate.Base)
+		rate.Quote = strings.ToUpper(rate.Quote)
+		rate.Date = truncateDay(rate.Date)
+		key := rate.Base + "/" + rate.Quote
+
+		list := t.rates[key]
+		i := sort.Search(len(list), func(i int) bool { return !list[i].Date.Before(rate.Date) })
+		if i < len(list) && list[i].Date.Equal(rate.Date) {
+			list[i] = rate
+			continue
+		}
+		list = append(list, Rate{})
+		copy(list[i+1:], list[i:])
+		list[i] = rate
+		t.rates[key] = list
+	}
+}
+
+func (t *Table) Rate(ctx context.Context, base, quote string, date time.Time) (Rate, error) {
+	base = strings.ToUpper(base)
+	quote = strings.ToUpper(quote)
+	date = truncateDay(date)
+	if base == quote {
+		return Rate{Base: base, Quote: quote, Date: date, Value: 1}, nil
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if rate, ok := t.pair(base, quote, date); ok {
+		return rate, nil
+	}
+
+	// Cross rate through a currency quoted against both sides, e.g. SGD/IDR from USD/SGD and USD/IDR
+	for _, via := range t.currencies() {
+		if via == base || via == quote {
+			continue
+		}
+		first, ok := t.pair(base, via, date)
+		if !ok {
+			continue
+		}
+		second, ok := t.pair(via, quote, date)
+		if !ok {
+			continue
+		}
+		rateDate := first.Date
+		if second.Date.Before(rateDate) {
+			rateDate = second.Date
+		}
+		return Rate{Base: base, Quote: quote, Date: rateDate, Value: first.Value * second.Value, Source: "cross:" + via}, nil
+	}
+
+	return Rate{}, fmt.Errorf("%w for %s/%s on %s", ErrRateNotFound, base, quote, date.Format("2006-01-02"))
+}
+
+// pair resolves a direct or inverse rate
+func (t *Table) pair(base, quote string, date time.Time) (Rate, bool) {
+	if rate, ok := latest(t.rates[base+"/"+quote], date); ok {
+		return rate, true
+	}
+	if rate, ok := latest(t.rates[quote+"/"+base], date); ok && rate.Value > 0 {
+		return Rate{Base: base, Quote: quote, Date: rate.Date, Value: 1 / rate.Value, Source: rate.Source}, true
+	}
+	return Rate{}, false
+}
+
+func (t *Table) currencies() []string {
+	seen := make(map[string]bool)
+	for key := range t.rates {
+		base, quote, _ := strings.Cut(key, "/")
+		seen[base] = true
+		seen[quote] = true
+	}
+	codes := make([]string, 0, len(seen))
+	for code := range seen {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+func latest(list []Rate, date time.Time) (Rate, bool) {
+	i := sort.Search(len(list), func(i int) bool { return list[i].Date.After(date) })
+	if i == 0 {
+		return Rate{}, false
+	}
+	return list[i-1], true
+}
+
+func truncateDay(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// LoadFile reads a CSV rate table with the header date,base,quote,rate. Dates use YYYY-MM-DD.
+func LoadFile(path string) (*Table, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	rates, err := ReadCSV(file, "file")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return NewTable(rates), nil
+}
+
+// ReadCSV parses rates in the LoadFile format, source is set on every rate
+func ReadCSV(r io.Reader, source string) ([]Rate, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"date", "base", "quote", "rate"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing column %q", name)
+		}
+	}
+
+	var rates []Rate
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(record[columns["date"]]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date: %w", line, err)
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(record[columns["rate"]]), 64)
+		if err != nil || value <= 0 {
+			return nil, fmt.Errorf("line %d: rate must be a positive number", line)
+		}
+		base, err := Lookup(record[columns["base"]])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		quote, err := Lookup(record[columns["quote"]])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		rates = append(rates, Rate{Base: base.Code, Quote: quote.Code, Date: date, Value: value, Source: source})
+	}
+
+	return rates, nil
+}
@@ -0,0 +1,131 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func day(value string) time.Time {
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		panic(err)
+	}
+	return date
+}
+
+// rupiahTable quotes USD and SGD against the Rupiah, the base currency of most users
+func rupiahTable() *Table {
+	return NewTable([]Rate{
+		{Base: "USD", Quote: "IDR", Date: day("2024-03-01"), Value: 16000, Source: "file"},
+		{Base: "USD", Quote: "IDR", Date: day("2024-03-10"), Value: 16250, Source: "file"},
+		{Base: "sgd", Quote: "idr", Date: day("2024-03-05"), Value: 11800, Source: "file"},
+	})
+}
+
+func TestTableRate(t *testing.T) {
+	tests := []struct {
+		name        string
+		base, quote string
+		date        string
+		want        float64
+		rateDate    string
+		source      string
+		err         error
+	}{
+		{name: "direct", base: "USD", quote: "IDR", date: "2024-03-05", want: 16000, rateDate: "2024-03-01", source: "file"},
+		{name: "latest on the day", base: "USD", quote: "IDR", date: "2024-03-10", want: 16250, rateDate: "2024-03-10", source: "file"},
+		{name: "case insensitive", base: "usd", quote: "idr", date: "2024-03-12", want: 16250, rateDate: "2024-03-10", source: "file"},
+		{name: "inverse", base: "IDR", quote: "USD", date: "2024-03-12", want: 1.0 / 16250, rateDate: "2024-03-10", source: "file"},
+		{name: "cross through the base currency", base: "USD", quote: "SGD", date: "2024-03-12", want: 16250.0 / 11800, rateDate: "2024-03-05", source: "cross:IDR"},
+		{name: "inverse cross", base: "SGD", quote: "USD", date: "2024-03-12", want: 11800.0 / 16250, rateDate: "2024-03-05", source: "cross:IDR"},
+		{name: "same currency", base: "JPY", quote: "jpy", date: "2024-03-12", want: 1, rateDate: "2024-03-12"},
+		{name: "before the first rate", base: "USD", quote: "IDR", date: "2024-02-28", err: ErrRateNotFound},
+		{name: "cross leg missing on the date", base: "USD", quote: "SGD", date: "2024-03-03", err: ErrRateNotFound},
+		{name: "unknown pair", base: "JPY", quote: "IDR", date: "2024-03-12", err: ErrRateNotFound},
+	}
+
+	table := rupiahTable()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := table.Rate(context.Background(), tt.base, tt.quote, day(tt.date).Add(15*time.Hour))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Rate() error = %v, want %v", err, tt.err)
+			}
+			if tt.err != nil {
+				return
+			}
+			if math.Abs(got.Value-tt.want) > 1e-12*math.Max(1, tt.want) {
+				t.Errorf("rate = %v, want %v", got.Value, tt.want)
+			}
+			if !got.Date.Equal(day(tt.rateDate)) || got.Source != tt.source {
+				t.Errorf("rate of %s from %s, want %s from %s", got.Date.Format("2006-01-02"), got.Source, tt.rateDate, tt.source)
+			}
+			if got.Base != strings.ToUpper(tt.base) || got.Quote != strings.ToUpper(tt.quote) {
+				t.Errorf("pair = %s/%s, want %s/%s", got.Base, got.Quote, tt.base, tt.quote)
+			}
+		})
+	}
+}
+
+func TestTableAddReplacesTheSameDay(t *testing.T) {
+	table := rupiahTable()
+	table.Add(Rate{Base: "USD", Quote: "IDR", Date: day("2024-03-10").Add(9 * time.Hour), Value: 16300, Source: "manual"})
+
+	got, err := table.Rate(context.Background(), "USD", "IDR", day("2024-03-10"))
+	if err != nil {
+		t.Fatalf("Rate() error = %v", err)
+	}
+	if got.Value != 16300 || got.Source != "manual" {
+		t.Errorf("rate = %v from %s, want the replacing 16300 from manual", got.Value, got.Source)
+	}
+	if n := len(table.rates["USD/IDR"]); n != 2 {
+		t.Errorf("USD/IDR has %d rates, want 2", n)
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  int
+		err   string
+	}{
+		{
+			name:  "valid",
+			input: "# rates of March\ndate, base, quote, rate\n2024-03-01, usd, IDR, 16000\n2024-03-05, SGD, IDR, 11800.5\n",
+			want:  2,
+		},
+		{name: "columns in any order", input: "rate,quote,base,date\n16000,IDR,USD,2024-03-01\n", want: 1},
+		{name: "missing column", input: "date,base,quote\n2024-03-01,USD,IDR\n", err: `missing column "rate"`},
+		{name: "invalid date", input: "date,base,quote,rate\n01/03/2024,USD,IDR,16000\n", err: "line 2: invalid date"},
+		{name: "zero rate", input: "date,base,quote,rate\n2024-03-01,USD,IDR,0\n", err: "line 2: rate must be a positive number"},
+		{name: "unknown currency", input: "date,base,quote,rate\n2024-03-01,XYZ,IDR,2\n", err: "line 2: unsupported currency code"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rates, err := ReadCSV(strings.NewReader(tt.input), "file")
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("ReadCSV() error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadCSV() error = %v", err)
+			}
+			if len(rates) != tt.want {
+				t.Fatalf("ReadCSV() = %d rates, want %d", len(rates), tt.want)
+			}
+			for _, rate := range rates {
+				if rate.Base != strings.ToUpper(rate.Base) || rate.Quote != "IDR" || rate.Source != "file" {
+					t.Errorf("rate = %+v, want upper case codes from file", rate)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidMapping = errors.New("invalid column mapping")
+
+// Mapping assigns file headers to transaction fields, an empty value means the field is not in the file.
+// The amount comes either from a signed Amount column (negative is an expense unless Type says otherwise)
+// or from separate Debit (expense) and Credit (income) columns.
+type Mapping struct {
+	Date          string `json:"date"`
+	Description   string `json:"description"`
+	Amount        string `json:"amount"`
+	Debit         string `json:"debit"`
+	Credit        string `json:"credit"`
+	Type          string `json:"type"`
+	Category      string `json:"category"`
+	PaymentMethod string `json:"payment_method"`
+	Source        string `json:"source"`
+	// ExternalId is a unique transaction ID from the bank, rows whose ID was imported before are duplicates
+	ExternalId string `json:"external_id"`
+}
+
+// headerSynonyms are normalized header names per field in English and Indonesian, most specific first
+var headerSynonyms = []struct {
+	field    func(m *Mapping) *string
+	synonyms []string
+}{
+	{func(m *Mapping) *string { return &m.Date }, []string{"date", "transaction date", "tanggal", "tanggal transaksi", "tgl", "tgl transaksi", "posting date", "tanggal posting", "waktu"}},
+	{func(m *Mapping) *string { return &m.Description }, []string{"description", "keterangan", "deskripsi", "uraian", "uraian transaksi", "details", "remark", "remarks", "memo", "catatan", "note", "notes"}},
+	{func(m *Mapping) *string { return &m.Debit }, []string{"debit", "debet", "db", "withdrawal", "withdrawals", "pengeluaran", "uang keluar", "keluar", "mutasi debet", "mutasi debit"}},
+	{func(m *Mapping) *string { return &m.Credit }, []string{"credit", "kredit", "cr", "deposit", "deposits", "pemasukan", "uang masuk", "masuk", "mutasi kredit"}},
+	{func(m *Mapping) *string { return &m.Amount }, []string{"amount", "jumlah", "nominal", "nilai", "mutasi", "total"}},
+	{func(m *Mapping) *string { return &m.Type }, []string{"type", "tipe", "jenis", "jenis transaksi", "d/k", "db/cr", "dr/cr"}},
+	{func(m *Mapping) *string { return &m.Category }, []string{"category", "kategori"}},
+	{func(m *Mapping) *string { return &m.PaymentMethod }, []string{"payment method", "metode pembayaran", "metode", "pembayaran"}},
+	{func(m *Mapping) *string { return &m.Source }, []string{"merchant", "source", "toko", "penerima", "payee"}},
+	{func(m *Mapping) *string { return &m.ExternalId }, []string{"external id", "external_id", "transaction id", "id transaksi"}},
+}
+
+func normalizeHeader(header string) string {
+	return strings.Join(strings.Fields(strings.ToLower(header)), " ")
+}
+
+// DetectMapping guesses the mapping from header names. Exact synonym matches win over partial ones
+// and a header is never assigned to two fields.
+func DetectMapping(headers []string) Mapping {
+	mapping := Mapping{}
+	used := make(map[int]bool, len(headers))
+
+	assign := func(match func(header, synonym string) bool) {
+		for _, entry := range headerSynonyms {
+			field := entry.field(&mapping)
+			if *field != "" {
+				continue
+			}
+		search:
+			for _, synonym := range entry.synonyms {
+				for i, header := range headers {
+					if !used[i] && match(normalizeHeader(header), synonym) {
+						*field = header
+						used[i] = true
+						break search
+					}
+				}
+			}
+		}
+	}
+
+	assign(func(header, synonym string) bool { return header == synonym })
+	// Short synonyms such as "db" or "cr" would match too many headers as substrings
+	assign(func(header, synonym string) bool { return len(synonym) > 3 && strings.Contains(header, synonym) })
+
+	// A lone debit or credit column without its pair is more likely a signed amount
+	if mapping.Amount == "" && (mapping.Debit == "") != (mapping.Credit == "") {
+		mapping.Amount = mapping.Debit + mapping.Credit
+		mapping.Debit, mapping.Credit = "", ""
+	}
+
+	return mapping
+}
+
+// Resolve checks the mapping against the headers and returns the column index of each field, -1 when unmapped
+func (m Mapping) Resolve(headers []string) (map[string]int, error) {
+	fields := map[string]string{
+		"date":           m.Date,
+		"description":    m.Description,
+		"amount":         m.Amount,
+		"debit":          m.Debit,
+		"credit":         m.Credit,
+		"type":           m.Type,
+		"category":       m.Category,
+		"payment_method": m.PaymentMethod,
+		"source":         m.Source,
+		"external_id":    m.ExternalId,
+	}
+
+	if m.Date == "" || m.Description == "" {
+		return nil, fmt.Errorf("%w: date and description columns are required", ErrInvalidMapping)
+	}
+	if m.Amount == "" && m.Debit == "" && m.Credit == "" {
+		return nil, fmt.Errorf("%w: an amount column or debit/credit columns are required", ErrInvalidMapping)
+	}
+	if m.Amount != "" && (m.Debit != "" || m.Credit != "") {
+		return nil, fmt.Errorf("%w: use either an amount column or debit/credit columns, not both", ErrInvalidMapping)
+	}
+
+	index := make(map[string]int, len(fields))
+	for field, header := range fields {
+		index[field] = -1
+		if header == "" {
+			continue
+		}
+		for i, h := range headers {
+			if h == header {
+				index[field] = i
+				break
+			}
+		}
+		if index[field] < 0 {
+			return nil, fmt.Errorf("%w: column %q for %s is not in the file", ErrInvalidMapping, header, field)
+		}
+	}
+
+	return index, nil
+}
@@ -0,0 +1,115 @@
+package importer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDetectMapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers []string
+		want    Mapping
+	}{
+		{
+			name:    "english headers",
+			headers: []string{"Date", "Description", "Amount", "Type", "Category", "Payment Method"},
+			want:    Mapping{Date: "Date", Description: "Description", Amount: "Amount", Type: "Type", Category: "Category", PaymentMethod: "Payment Method"},
+		},
+		{
+			name:    "indonesian bank export with debit and credit",
+			headers: []string{"Tanggal Transaksi", "Keterangan", "Cabang", "Mutasi Debet", "Mutasi Kredit", "Saldo"},
+			want:    Mapping{Date: "Tanggal Transaksi", Description: "Keterangan", Debit: "Mutasi Debet", Credit: "Mutasi Kredit"},
+		},
+		{
+			name:    "partial matches and extra spacing",
+			headers: []string{"Tanggal Posting (WIB)", "Uraian   Transaksi Lengkap", "Nominal (IDR)", "DB/CR", "ID Transaksi"},
+			want:    Mapping{Date: "Tanggal Posting (WIB)", Description: "Uraian   Transaksi Lengkap", Amount: "Nominal (IDR)", Type: "DB/CR", ExternalId: "ID Transaksi"},
+		},
+		{
+			name:    "lone debit column is a signed amount",
+			headers: []string{"Date", "Description", "Debit"},
+			want:    Mapping{Date: "Date", Description: "Description", Amount: "Debit"},
+		},
+		{
+			name:    "short synonym is not matched inside a header",
+			headers: []string{"Tanggal", "Keterangan", "Jumlah", "Kode DB"},
+			want:    Mapping{Date: "Tanggal", Description: "Keterangan", Amount: "Jumlah"},
+		},
+		{
+			name:    "header is assigned to one field only",
+			headers: []string{"Tanggal", "Keterangan Merchant", "Jumlah"},
+			want:    Mapping{Date: "Tanggal", Description: "Keterangan Merchant", Amount: "Jumlah"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectMapping(tt.headers); got != tt.want {
+				t.Errorf("DetectMapping() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	headers := []string{"Tanggal", "Keterangan", "Jumlah", "Debet", "Kredit"}
+
+	tests := []struct {
+		name    string
+		mapping Mapping
+		want    map[string]int
+		wantErr bool
+	}{
+		{
+			name:    "signed amount",
+			mapping: Mapping{Date: "Tanggal", Description: "Keterangan", Amount: "Jumlah"},
+			want:    map[string]int{"date": 0, "description": 1, "amount": 2, "debit": -1, "credit": -1},
+		},
+		{
+			name:    "debit and credit",
+			mapping: Mapping{Date: "Tanggal", Description: "Keterangan", Debit: "Debet", Credit: "Kredit"},
+			want:    map[string]int{"date": 0, "description": 1, "amount": -1, "debit": 3, "credit": 4},
+		},
+		{
+			name:    "missing description",
+			mapping: Mapping{Date: "Tanggal", Amount: "Jumlah"},
+			wantErr: true,
+		},
+		{
+			name:    "no amount column",
+			mapping: Mapping{Date: "Tanggal", Description: "Keterangan"},
+			wantErr: true,
+		},
+		{
+			name:    "amount and debit together",
+			mapping: Mapping{Date: "Tanggal", Description: "Keterangan", Amount: "Jumlah", Debit: "Debet"},
+			wantErr: true,
+		},
+		{
+			name:    "column not in the file",
+			mapping: Mapping{Date: "Tanggal", Description: "Keterangan", Amount: "Nominal"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.mapping.Resolve(headers)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidMapping) {
+					t.Fatalf("Resolve() error = %v, want ErrInvalidMapping", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			for field, index := range tt.want {
+				if got[field] != index {
+					t.Errorf("%s index = %d, want %d", field, got[field], index)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+// Package importer reads tabular transaction exports (CSV and XLSX) and turns their loosely formatted
+// cells into dates, amounts and directions. It knows nothing about the database or the domain.
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var (
+	ErrUnsupportedFile = errors.New("unsupported file type, use .csv or .xlsx")
+	ErrEmptyFile       = errors.New("file has no header row")
+)
+
+const (
+	FileTypeCSV  = "csv"
+	FileTypeXLSX = "xlsx"
+)
+
+// Table is a parsed sheet, Rows never include the header row
+type Table struct {
+	Headers []string
+	Rows    [][]string
+}
+
+// FileType returns the import type of a file name, or ErrUnsupportedFile
+func FileType(name string) (string, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv", ".txt":
+		return FileTypeCSV, nil
+	case ".xlsx":
+		return FileTypeXLSX, nil
+	default:
+		return "", ErrUnsupportedFile
+	}
+}
+
+// Read parses a CSV or XLSX file. The first row with at least two non-empty cells is the header,
+// blank rows are dropped and every row is padded or trimmed to the header width.
+func Read(fileType string, r io.Reader) (*Table, error) {
+	var records [][]string
+	var err error
+	switch fileType {
+	case FileTypeCSV:
+		records, err = ReadCSVRecords(r)
+	case FileTypeXLSX:
+		records, err = readXLSX(r)
+	default:
+		return nil, ErrUnsupportedFile
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newTable(records)
+}
+
+func newTable(records [][]string) (*Table, error) {
+	headerIndex := -1
+	for i, record := range records {
+		if nonEmptyCells(record) >= 2 {
+			headerIndex = i
+			break
+		}
+	}
+	if headerIndex < 0 {
+		return nil, ErrEmptyFile
+	}
+
+	headers := make([]string, len(records[headerIndex]))
+	for i, header := range records[headerIndex] {
+		headers[i] = strings.TrimSpace(header)
+		if headers[i] == "" {
+			headers[i] = fmt.Sprintf("Column %d", i+1)
+		}
+	}
+
+	table := &Table{Headers: headers}
+	for _, record := range records[headerIndex+1:] {
+		if nonEmptyCells(record) == 0 {
+			continue
+		}
+		row := make([]string, len(headers))
+		for i := range row {
+			if i < len(record) {
+				row[i] = strings.TrimSpace(record[i])
+			}
+		}
+		table.Rows = append(table.Rows, row)
+	}
+
+	return table, nil
+}
+
+func nonEmptyCells(record []string) int {
+	count := 0
+	for _, cell := range record {
+		if strings.TrimSpace(cell) != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// ReadCSVRecords reads every record of a CSV file with a detected delimiter, records may differ in width
+func ReadCSVRecords(r io.Reader) ([][]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = detectDelimiter(data)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv: %w", err)
+	}
+	return records, nil
+}
+
+// detectDelimiter picks the most frequent of comma, semicolon and tab in the first lines, spreadsheets
+// with an Indonesian locale export with semicolons and bank exports often start with a preamble
+func detectDelimiter(data []byte) rune {
+	head := data
+	for i, lines := 0, 0; i < len(data); i++ {
+		if data[i] == '\n' {
+			if lines++; lines == 20 {
+				head = data[:i]
+				break
+			}
+		}
+	}
+
+	delimiter, best := ',', 0
+	for _, candidate := range []rune{',', ';', '\t'} {
+		if count := bytes.Count(head, []byte(string(candidate))); count > best {
+			delimiter, best = candidate, count
+		}
+	}
+	return delimiter
+}
+
+// readXLSX reads the first sheet with raw cell values so dates come through as serial numbers
+func readXLSX(r io.Reader) ([][]string, error) {
+	file, err := excelize.OpenReader(r, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, fmt.Errorf("invalid xlsx: %w", err)
+	}
+	defer file.Close()
+
+	sheets := file.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, ErrEmptyFile
+	}
+
+	rows, err := file.GetRows(sheets[0], excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, fmt.Errorf("invalid xlsx: %w", err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,193 @@
+package importer
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var (
+	ErrEmptyValue    = errors.New("value is empty")
+	ErrInvalidAmount = errors.New("invalid amount")
+	ErrInvalidDate   = errors.New("invalid date")
+	ErrUnknownType   = errors.New("unknown transaction type")
+)
+
+const (
+	excelSerialMin = 1.0
+	excelSerialMax = 2958465.0 // 9999-12-31
+)
+
+// dayFirstLayouts are tried in order when no layout is given, Indonesian exports write the day first
+var dayFirstLayouts = []string{
+	"2006-01-02", "2006-01-02 15:04:05", "2006-01-02 15:04", time.RFC3339, "2006/01/02",
+	"2/1/2006", "2/1/2006 15:04:05", "2/1/2006 15:04", "2-1-2006", "2-1-2006 15:04:05", "2.1.2006",
+	"2/1/06", "2-1-06", "2 Jan 2006", "2 January 2006", "2 Jan 2006 15:04", "2 Jan 2006 15:04:05",
+	"2-Jan-2006", "2-Jan-06", "Jan 2, 2006", "January 2, 2006", "20060102",
+}
+
+// indonesianMonths maps month names that differ from English
+var indonesianMonths = map[string]string{
+	"januari": "January", "februari": "February", "pebruari": "February", "maret": "March",
+	"mei": "May", "juni": "June", "juli": "July", "agustus": "August", "agu": "Aug", "ags": "Aug",
+	"oktober": "October", "okt": "Oct", "nopember": "November", "nop": "Nov", "desember": "December", "des": "Dec",
+}
+
+var (
+	incomeDirections  = map[string]bool{"income": true, "pemasukan": true, "credit": true, "kredit": true, "cr": true, "k": true, "c": true, "in": true, "masuk": true, "deposit": true}
+	expenseDirections = map[string]bool{"expense": true, "pengeluaran": true, "debit": true, "debet": true, "db": true, "dr": true, "d": true, "out": true, "keluar": true, "withdrawal": true}
+)
+
+// Direction is the money flow of a row
+type Direction int
+
+const (
+	DirectionUnknown Direction = iota
+	DirectionIncome
+	DirectionExpense
+)
+
+// ParseDirection reads a type cell such as "expense", "Kredit", "DB" or "CR"
+func ParseDirection(value string) (Direction, error) {
+	normalized := strings.Trim(strings.ToLower(strings.TrimSpace(value)), ".")
+	switch {
+	case normalized == "":
+		return DirectionUnknown, ErrEmptyValue
+	case incomeDirections[normalized]:
+		return DirectionIncome, nil
+	case expenseDirections[normalized]:
+		return DirectionExpense, nil
+	default:
+		return DirectionUnknown, ErrUnknownType
+	}
+}
+
+// ParseAmount reads a money cell into whole units, the result is negative for outflows.
+// It accepts "Rp 1.250.000,00", "1,250,000.00", "-50.000", "(50.000)", "50.000 DB" and "50.000 CR".
+// A single separator followed by exactly three digits is read as a thousands separator since
+// Rupiah amounts rarely carry decimals.
+func ParseAmount(value string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(strings.ReplaceAll(value, "\u00a0", " ")))
+	if s == "" || s == "-" {
+		return 0, ErrEmptyValue
+	}
+
+	negative := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		negative = true
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+	for _, suffix := range []string{"DB", "DR", "D"} {
+		if strings.HasSuffix(s, suffix) {
+			negative = true
+			s = strings.TrimSpace(strings.TrimSuffix(s, suffix))
+			break
+		}
+	}
+	for _, suffix := range []string{"CR", "K"} {
+		if strings.HasSuffix(s, suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, suffix))
+			break
+		}
+	}
+	s = trimCurrency(s)
+	if strings.HasPrefix(s, "-") {
+		negative = !negative
+		s = strings.TrimSpace(s[1:])
+	} else if strings.HasSuffix(s, "-") {
+		negative = !negative
+		s = strings.TrimSpace(s[:len(s)-1])
+	}
+	// The currency may sit on either side of the minus sign, "Rp -50.000" or "-Rp 50.000"
+	s = strings.ReplaceAll(trimCurrency(s), " ", "")
+
+	number, err := strconv.ParseFloat(normalizeSeparators(s), 64)
+	if err != nil || s == "" {
+		return 0, ErrInvalidAmount
+	}
+
+	amount := int64(math.Round(number))
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+func trimCurrency(s string) string {
+	for _, prefix := range []string{"IDR", "RP.", "RP"} {
+		s = strings.TrimSpace(strings.TrimPrefix(s, prefix))
+	}
+	return s
+}
+
+// normalizeSeparators rewrites a number to use "." as the decimal separator and no thousands separator
+func normalizeSeparators(s string) string {
+	lastDot, lastComma := strings.LastIndex(s, "."), strings.LastIndex(s, ",")
+	switch {
+	case lastDot >= 0 && lastComma >= 0:
+		if lastComma > lastDot {
+			return strings.Replace(strings.ReplaceAll(s, ".", ""), ",", ".", 1)
+		}
+		return strings.ReplaceAll(s, ",", "")
+	case lastDot >= 0 || lastComma >= 0:
+		separator := "."
+		if lastComma >= 0 {
+			separator = ","
+		}
+		last := strings.LastIndex(s, separator)
+		if strings.Count(s, separator) > 1 || len(s)-last-1 == 3 {
+			return strings.ReplaceAll(s, separator, "")
+		}
+		return strings.Replace(s, separator, ".", 1)
+	default:
+		return s
+	}
+}
+
+// ParseDate reads a date cell with the given Go layout, or tries common day-first layouts when layout
+// is empty. Excel serial numbers and Indonesian month names are understood.
+func ParseDate(value, layout string) (time.Time, error) {
+	s := strings.TrimSpace(value)
+	if s == "" {
+		return time.Time{}, ErrEmptyValue
+	}
+
+	if layout != "" {
+		t, err := time.Parse(layout, translateMonths(s))
+		if err != nil {
+			return time.Time{}, ErrInvalidDate
+		}
+		return t, nil
+	}
+
+	if serial, err := strconv.ParseFloat(s, 64); err == nil && len(s) != 8 && serial >= excelSerialMin && serial <= excelSerialMax {
+		t, err := excelize.ExcelDateToTime(serial, false)
+		if err != nil {
+			return time.Time{}, ErrInvalidDate
+		}
+		return t, nil
+	}
+
+	s = translateMonths(s)
+	for _, candidate := range dayFirstLayouts {
+		if t, err := time.Parse(candidate, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, ErrInvalidDate
+}
+
+// translateMonths replaces Indonesian month names that differ from English, time.Parse matches month names case-insensitively
+func translateMonths(s string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == '-' || r == '/' || r == ',' })
+	for _, word := range words {
+		if english, ok := indonesianMonths[strings.ToLower(word)]; ok {
+			s = strings.Replace(s, word, english, 1)
+		}
+	}
+	return s
+}
@@ -3,9 +3,11 @@ package agents
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/saufiroja/fin-ai/config"
 	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/pkg/llm/cassette"
 	"github.com/saufiroja/fin-ai/pkg/llm/tools"
 	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/llms/googleai"
@@ -16,6 +18,9 @@ type Agent interface {
 	Execute(ctx context.Context, message string, toolCtx *tools.ToolContext) (*responses.ResponseAI, error)
 }
 
+// Model is the Gemini model of the agents
+const Model = "gemini-2.5-flash"
+
 // BaseAgent provides common functionality for all agents
 type BaseAgent struct {
 	config       *config.AppConfig
@@ -39,7 +44,7 @@ func (ba *BaseAgent) Execute(ctx context.Context, message string, toolCtx *tools
 	}
 
 	// Initialize message history and get initial response
-	messageHistory, err := ba.getInitialResponse(ctx, llm, message)
+	messageHistory, initialInputTokens, initialOutputTokens, err := ba.getInitialResponse(ctx, llm, message)
 	if err != nil {
 		return nil, err
 	}
@@ -51,31 +56,67 @@ func (ba *BaseAgent) Execute(ctx context.Context, message string, toolCtx *tools
 	}
 
 	// Generate final response
-	return ba.generateFinalResponse(ctx, llm, messageHistory)
+	response, err := ba.generateFinalResponse(ctx, llm, messageHistory)
+	if err != nil {
+		return nil, err
+	}
+
+	// The usage covers both calls of the agent
+	response.InputToken += initialInputTokens
+	response.OutputToken += initialOutputTokens
+	return response, nil
 }
 
 // initializeLLMClient initializes the LLM client with proper configuration
 func (ba *BaseAgent) initializeLLMClient(ctx context.Context) (llms.Model, error) {
 	geminiKey := ba.config.Gemini.ApiKey
-	llm, err := googleai.New(ctx,
+	opts := []googleai.Option{
 		googleai.WithAPIKey(geminiKey),
-		googleai.WithDefaultModel("gemini-2.5-flash"),
-	)
+		googleai.WithDefaultModel(Model),
+	}
+
+	httpClient, err := cassette.HTTPClient(ba.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize LLM client: %w", err)
+	}
+	if httpClient != nil {
+		// The API key option is ignored next to a custom HTTP client, so the transport adds it
+		opts = append(opts, googleai.WithHTTPClient(&http.Client{
+			Transport: &apiKeyTransport{apiKey: geminiKey, next: httpClient.Transport},
+		}))
+	}
+
+	llm, err := googleai.New(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize LLM client: %w", err)
 	}
 	return llm, nil
 }
 
-// getInitialResponse gets the initial response from the LLM with the user message
-func (ba *BaseAgent) getInitialResponse(ctx context.Context, llm llms.Model, message string) ([]llms.MessageContent, error) {
+// apiKeyTransport adds the Gemini API key as the key query parameter
+type apiKeyTransport struct {
+	apiKey string
+	next   http.RoundTripper
+}
+
+func (t *apiKeyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	query := req.URL.Query()
+	query.Set("key", t.apiKey)
+	req.URL.RawQuery = query.Encode()
+	return t.next.RoundTrip(req)
+}
+
+// getInitialResponse gets the initial response from the LLM with the user message, with the input and
+// output tokens of the call
+func (ba *BaseAgent) getInitialResponse(ctx context.Context, llm llms.Model, message string) ([]llms.MessageContent, int, int, error) {
 	messageHistory := []llms.MessageContent{
 		llms.TextParts(llms.ChatMessageTypeHuman, message),
 	}
 
 	resp, err := llm.GenerateContent(ctx, messageHistory, llms.WithTools(ba.toolRegistry.GetAvailableTools()))
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate initial response: %w", err)
+		return nil, 0, 0, fmt.Errorf("failed to generate initial response: %w", err)
 	}
 
 	// Translate the model's response into a MessageContent element
@@ -86,7 +127,8 @@ func (ba *BaseAgent) getInitialResponse(ctx context.Context, llm llms.Model, mes
 	}
 	messageHistory = append(messageHistory, assistantResponse)
 
-	return messageHistory, nil
+	inputTokens, outputTokens := ba.extractTokenCounts(respchoice.GenerationInfo)
+	return messageHistory, inputTokens, outputTokens, nil
 }
 
 // processToolCalls processes all tool calls from the LLM response
@@ -128,6 +170,7 @@ func (ba *BaseAgent) generateFinalResponse(ctx context.Context, llm llms.Model,
 		Response:    finalChoice.Content,
 		InputToken:  inputTokens,
 		OutputToken: outputTokens,
+		Model:       Model,
 	}, nil
 }
 
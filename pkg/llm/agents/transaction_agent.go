@@ -18,6 +18,7 @@ func NewTransactionAgent(config *config.AppConfig) *TransactionAgent {
 	// Create tool registry and register transaction tools
 	toolRegistry := tools.NewToolRegistry()
 	toolRegistry.RegisterTool(tools.NewTransactionTool())
+	toolRegistry.RegisterTool(tools.NewForecastTool())
 
 	baseAgent := NewBaseAgent(config, toolRegistry)
 	return &TransactionAgent{
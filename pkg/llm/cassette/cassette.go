@@ -0,0 +1,326 @@
+// Package cassette records the HTTP traffic of the LLM clients to a fixture file and replays it offline,
+// so flows that call Gemini or OpenAI run deterministically in CI without network access or API keys.
+//
+// The recorder is an http.RoundTripper given to the genai, openai-go and langchaingo clients. API keys
+// and user data are redacted before anything is written, see Redactor.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/saufiroja/fin-ai/config"
+)
+
+// Mode tells a Recorder whether requests reach the network
+type Mode string
+
+const (
+	// ModeOff sends requests to the network and records nothing
+	ModeOff Mode = ""
+	// ModeRecord sends requests to the network and writes every interaction to the cassette
+	ModeRecord Mode = "record"
+	// ModeReplay answers requests from the cassette, nothing reaches the network
+	ModeReplay Mode = "replay"
+)
+
+var ErrNoInteraction = errors.New("no recorded interaction matches the request")
+
+// Cassette is the fixture file, interactions are kept in the order they were recorded
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is stored redacted, headers are left out since they carry the API keys
+type Request struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+	Body   json.RawMessage `json:"body,omitempty"`
+	Text   string          `json:"text,omitempty"` // body that is not JSON
+}
+
+type Response struct {
+	Status      int             `json:"status"`
+	ContentType string          `json:"content_type,omitempty"`
+	Body        json.RawMessage `json:"body,omitempty"`
+	Text        string          `json:"text,omitempty"` // body that is not JSON, e.g. a server-sent event stream
+}
+
+type Options struct {
+	// Secrets are redacted everywhere, e.g. the API keys and the name or id of the test user
+	Secrets []string
+	// Ignore are left out when requests are matched, on top of ids and timestamps
+	Ignore []*regexp.Regexp
+	// Transport sends the requests in record mode, http.DefaultTransport by default
+	Transport http.RoundTripper
+}
+
+type Recorder struct {
+	path     string
+	mode     Mode
+	next     http.RoundTripper
+	redactor *Redactor
+	ignore   []*regexp.Regexp
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// New opens the cassette at path. Replay reads the recorded interactions, record starts an empty cassette
+// that is written after every interaction.
+func New(path string, mode Mode, opts Options) (*Recorder, error) {
+	if mode != ModeRecord && mode != ModeReplay {
+		return nil, fmt.Errorf("unknown cassette mode %q", mode)
+	}
+	if path == "" {
+		return nil, errors.New("cassette path is required")
+	}
+
+	r := &Recorder{
+		path:     path,
+		mode:     mode,
+		next:     opts.Transport,
+		redactor: NewRedactor(opts.Secrets...),
+		ignore:   append(append([]*regexp.Regexp{}, defaultIgnore...), opts.Ignore...),
+	}
+	if r.next == nil {
+		r.next = http.DefaultTransport
+	}
+
+	if mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &r.cassette); err != nil {
+			return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+	return r, nil
+}
+
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Client returns an HTTP client that goes through the recorder
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	recorded := r.request(req, body)
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+	return r.record(req, recorded)
+}
+
+func (r *Recorder) replay(req *http.Request, recorded Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// The first unused match keeps the recorded order for repeated calls, a call made more often than it
+	// was recorded gets the last matching answer again
+	key := r.matchKey(recorded)
+	found := -1
+	for i, interaction := range r.cassette.Interactions {
+		if r.matchKey(interaction.Request) != key {
+			continue
+		}
+		found = i
+		if !r.used[i] {
+			break
+		}
+	}
+	if found < 0 {
+		return nil, fmt.Errorf("%w: %s %s in %s", ErrNoInteraction, recorded.Method, recorded.URL, r.path)
+	}
+	r.used[found] = true
+
+	response := r.cassette.Interactions[found].Response
+	body := []byte(response.Text)
+	if len(response.Body) > 0 {
+		body = response.Body
+	}
+	header := http.Header{}
+	if response.ContentType != "" {
+		header.Set("Content-Type", response.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", response.Status, http.StatusText(response.Status)),
+		StatusCode:    response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func (r *Recorder) record(req *http.Request, recorded Request) (*http.Response, error) {
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	// Streams are read to the end, the caller still gets every event
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	response := Response{Status: resp.StatusCode, ContentType: resp.Header.Get("Content-Type")}
+	response.Body, response.Text = r.body(body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{Request: recorded, Response: response})
+	if err := r.save(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// request is the redacted form of the request, the API key query parameter is dropped
+func (r *Recorder) request(req *http.Request, body []byte) Request {
+	u := *req.URL
+	query := u.Query()
+	query.Del("key")
+	u.RawQuery = query.Encode()
+
+	recorded := Request{Method: req.Method, URL: r.redactor.Redact(u.String())}
+	recorded.Body, recorded.Text = r.body(body)
+	return recorded
+}
+
+// body redacts a body, JSON stays readable in the cassette
+func (r *Recorder) body(body []byte) (json.RawMessage, string) {
+	if len(body) == 0 {
+		return nil, ""
+	}
+	text := r.redactor.Redact(string(body))
+	if json.Valid([]byte(text)) {
+		compact := &bytes.Buffer{}
+		if err := json.Compact(compact, []byte(text)); err == nil {
+			return compact.Bytes(), ""
+		}
+	}
+	return nil, text
+}
+
+func (r *Recorder) matchKey(req Request) string {
+	body := req.Text
+	if len(req.Body) > 0 {
+		body = canonical(req.Body)
+	}
+	key := req.Method + " " + req.URL + "\n" + body
+	for _, pattern := range r.ignore {
+		key = pattern.ReplaceAllString(key, "")
+	}
+	return key
+}
+
+// canonical re-encodes a JSON body with sorted keys and no HTML escaping, the form it has in the cassette
+// does not change the match
+func canonical(body json.RawMessage) string {
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return string(body)
+	}
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return string(body)
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+func (r *Recorder) save() error {
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(r.cassette); err != nil {
+		return err
+	}
+	data := buf.Bytes()
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+
+	tmp := r.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, r.path)
+}
+
+var (
+	sharedMu  sync.Mutex
+	recorders = map[string]*Recorder{}
+)
+
+// Shared returns the recorder of the cassette at path, the clients of a process record into one cassette
+// so their interactions are not overwritten by each other
+func Shared(path string, mode Mode, opts Options) (*Recorder, error) {
+	sharedMu.Lock()
+	defer sharedMu.Unlock()
+
+	key := strings.Join([]string{string(mode), path}, ":")
+	if r, ok := recorders[key]; ok {
+		return r, nil
+	}
+	r, err := New(path, mode, opts)
+	if err != nil {
+		return nil, err
+	}
+	recorders[key] = r
+	return r, nil
+}
+
+// HTTPClient returns the client for the LLM SDKs when LLM_CASSETTE_MODE is set, nil otherwise so the SDKs
+// keep their own transport
+func HTTPClient(conf *config.AppConfig) (*http.Client, error) {
+	if conf.LLM.CassetteMode == "" {
+		return nil, nil
+	}
+	r, err := Shared(conf.LLM.CassettePath, Mode(conf.LLM.CassetteMode), Options{
+		Secrets: []string{conf.OpenAI.ApiKey, conf.Gemini.ApiKey},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.Client(), nil
+}
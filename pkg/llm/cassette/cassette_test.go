@@ -0,0 +1,82 @@
+package cassette
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestRecordThenReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	const apiKey = "test-api-key-value"
+
+	calls := 0
+	upstream := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		body, _ := io.ReadAll(req.Body)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"echo":` + string(body) + `}`)),
+		}, nil
+	})
+
+	send := func(client *http.Client, body string) (string, error) {
+		resp, err := client.Post("https://llm.example.com/v1/generate?key="+apiKey, "application/json", strings.NewReader(body))
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		return string(data), err
+	}
+
+	recorder, err := New(path, ModeRecord, Options{Secrets: []string{apiKey}, Transport: upstream})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The ULID and the timestamp differ between record and replay, they must not break the match
+	recorded, err := send(recorder.Client(), `{"prompt":"hi siti@example.com","id":"01JB2Q5Z8X4M7R3T9V6W1Y0C2D","at":"2024-03-01T09:30:00Z"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, leaked := range []string{apiKey, "siti@example.com"} {
+		if strings.Contains(string(data), leaked) {
+			t.Errorf("cassette contains %q", leaked)
+		}
+	}
+
+	replayer, err := New(path, ModeReplay, Options{Secrets: []string{apiKey}, Transport: upstream})
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed, err := send(replayer.Client(), `{"prompt":"hi siti@example.com","id":"01JB2Q6A0B1C2D3E4F5G6H7J8K","at":"2024-03-02T10:00:00Z"}`)
+	if err != nil {
+		t.Fatalf("replay error = %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("upstream called %d times, want 1", calls)
+	}
+	if !strings.Contains(replayed, "hi <email>") || !strings.Contains(recorded, "siti@example.com") {
+		t.Errorf("recorded %s, replayed %s", recorded, replayed)
+	}
+
+	if _, err := send(replayer.Client(), `{"prompt":"something else"}`); !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("unmatched request error = %v, want ErrNoInteraction", err)
+	}
+}
@@ -0,0 +1,72 @@
+package cassette
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+const redacted = "<redacted>"
+
+// rule replaces the matches of pattern, replace gets the matched text
+type rule struct {
+	pattern *regexp.Regexp
+	replace func(match string) string
+}
+
+func fixed(replacement string) func(string) string {
+	return func(string) string { return replacement }
+}
+
+// digest keeps inline images out of the cassette, two different images still give two different requests
+func digest(match string) string {
+	key, value, _ := strings.Cut(match, ":")
+	sum := sha256.Sum256([]byte(strings.Trim(strings.TrimSpace(value), `"`)))
+	return key + `:"sha256:` + hex.EncodeToString(sum[:]) + `"`
+}
+
+// defaultRules redact what must never be committed with a cassette
+var defaultRules = []rule{
+	{regexp.MustCompile(`AIza[0-9A-Za-z_\-]{35}`), fixed(redacted)},                  // Google API keys
+	{regexp.MustCompile(`sk-[A-Za-z0-9_\-]{20,}`), fixed(redacted)},                  // OpenAI API keys
+	{regexp.MustCompile(`(?i)bearer [A-Za-z0-9._\-]+`), fixed("Bearer " + redacted)}, // tokens in prompts or errors
+	{regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`), fixed("<email>")},
+	{regexp.MustCompile(`(?:\+62|\b0)8[0-9]{8,11}\b`), fixed("<phone>")}, // Indonesian mobile numbers
+	{regexp.MustCompile(`"data":\s*"[A-Za-z0-9+/=]{256,}"`), digest},     // base64 receipt images
+}
+
+// defaultIgnore are left out when requests are matched, they change from one run to the next
+var defaultIgnore = []*regexp.Regexp{
+	regexp.MustCompile(`\b[0-9A-HJKMNP-TV-Z]{26}\b`), // ULIDs of users, sessions and rows
+	regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+\-]\d{2}:?\d{2})?`),
+	regexp.MustCompile(`(?:Monday|Tuesday|Wednesday|Thursday|Friday|Saturday|Sunday), \d{1,2} [A-Za-z]+ \d{4} \d{2}:\d{2} [A-Za-z0-9+\-]+`), // local time in the preference prompt
+}
+
+// Redactor removes API keys and user data from what is written to a cassette. It is deterministic so a
+// request redacted at replay matches the request redacted at record.
+type Redactor struct {
+	secrets []string
+	rules   []rule
+}
+
+func NewRedactor(secrets ...string) *Redactor {
+	r := &Redactor{rules: defaultRules}
+	for _, secret := range secrets {
+		// Short values would redact unrelated text
+		if len(strings.TrimSpace(secret)) >= 4 {
+			r.secrets = append(r.secrets, secret)
+		}
+	}
+	return r
+}
+
+func (r *Redactor) Redact(text string) string {
+	for _, secret := range r.secrets {
+		text = strings.ReplaceAll(text, secret, redacted)
+	}
+	for _, rule := range r.rules {
+		text = rule.pattern.ReplaceAllStringFunc(text, rule.replace)
+	}
+	return text
+}
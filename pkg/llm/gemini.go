@@ -6,8 +6,10 @@ import (
 	"github.com/saufiroja/fin-ai/config"
 	"github.com/saufiroja/fin-ai/internal/contracts/responses"
 	"github.com/saufiroja/fin-ai/internal/domains/categories"
+	"github.com/saufiroja/fin-ai/internal/domains/forecast"
 	"github.com/saufiroja/fin-ai/internal/domains/transaction"
 	"github.com/saufiroja/fin-ai/pkg/llm/agents"
+	"github.com/saufiroja/fin-ai/pkg/llm/cassette"
 	"github.com/saufiroja/fin-ai/pkg/llm/tools"
 	"google.golang.org/genai"
 )
@@ -17,12 +19,14 @@ type Gemini interface {
 	RunAgent(ctx context.Context, message string, userId string) (*responses.ResponseAI, error)
 	SetTransactionService(transactionService transaction.TransactionManager)
 	SetCategoryService(categoryService categories.CategoryManager)
+	SetForecastService(forecastService forecast.ForecastManager)
 }
 
 type GeminiClient struct {
 	conf               *config.AppConfig
 	transactionService transaction.TransactionManager
 	categoryService    categories.CategoryManager
+	forecastService    forecast.ForecastManager
 	transactionAgent   agents.Agent
 }
 
@@ -34,19 +38,29 @@ func NewGemini(conf *config.AppConfig) Gemini {
 }
 
 func (g *GeminiClient) Run(ctx context.Context, modelName string, messages []*genai.Content) (*responses.ResponseAI, error) {
+	httpClient, err := cassette.HTTPClient(g.conf)
+	if err != nil {
+		return nil, err
+	}
+
 	client, err := genai.NewClient(
 		ctx,
 		&genai.ClientConfig{
-			APIKey: g.conf.Gemini.ApiKey,
+			APIKey:     g.conf.Gemini.ApiKey,
+			HTTPClient: httpClient,
 		},
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	if modelName == "" {
+		modelName = "gemini-2.5-flash"
+	}
+
 	result, err := client.Models.GenerateContent(
 		ctx,
-		"gemini-2.5-flash",
+		modelName,
 		messages,
 		nil,
 	)
@@ -58,6 +72,7 @@ func (g *GeminiClient) Run(ctx context.Context, modelName string, messages []*ge
 		Response:    result.Text(),
 		InputToken:  int(result.UsageMetadata.PromptTokenCount),
 		OutputToken: int(result.UsageMetadata.CandidatesTokenCount),
+		Model:       modelName,
 	}, nil
 }
 
@@ -66,6 +81,7 @@ func (g *GeminiClient) RunAgent(ctx context.Context, message string, userId stri
 	toolCtx := &tools.ToolContext{
 		TransactionService: g.transactionService,
 		CategoryService:    g.categoryService,
+		ForecastService:    g.forecastService,
 		UserId:             userId,
 	}
 
@@ -80,3 +96,7 @@ func (g *GeminiClient) SetTransactionService(transactionService transaction.Tran
 func (g *GeminiClient) SetCategoryService(categoryService categories.CategoryManager) {
 	g.categoryService = categoryService
 }
+
+func (g *GeminiClient) SetForecastService(forecastService forecast.ForecastManager) {
+	g.forecastService = forecastService
+}
@@ -4,42 +4,69 @@ import (
 	"context"
 	"fmt"
 	"strings"
-	"sync"
 
 	openai "github.com/openai/openai-go"
 	"github.com/openai/openai-go/option"
 	"github.com/openai/openai-go/packages/ssestream"
+	"github.com/openai/openai-go/shared"
 	"github.com/saufiroja/fin-ai/config"
 	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/saufiroja/fin-ai/pkg/llm/cassette"
 )
 
 type OpenAI interface {
 	SendChat(ctx context.Context, modelName string, messages []openai.ChatCompletionMessageParamUnion) (*responses.ResponseAI, error)
+	SendChatWithSchema(ctx context.Context, modelName string, messages []openai.ChatCompletionMessageParamUnion, schemaName string, schema map[string]any) (*responses.ResponseAI, error)
 	SendChatStream(ctx context.Context, modelName string, messages string) (*ssestream.Stream[openai.ChatCompletionChunk], error)
 	CreateEmbedding(ctx context.Context, input openai.EmbeddingNewParamsInputUnion) *responses.ResponseEmbedding
+	CreateBatchEmbedding(ctx context.Context, inputs []string) *responses.ResponseBatchEmbedding
 }
 
+// EmbeddingModel is the model of CreateEmbedding and CreateBatchEmbedding
+const EmbeddingModel = "text-embedding-3-small"
+
 type OpenAIClient struct {
 	client openai.Client
 }
 
-var (
-	instance *OpenAIClient
-	once     sync.Once
-)
-
+// NewOpenAI builds a client for conf, every call gets its own transport so each test can load its own
+// cassette
 func NewOpenAI(conf *config.AppConfig) OpenAI {
-	once.Do(func() {
-		instance = &OpenAIClient{
-			client: openai.NewClient(
-				option.WithAPIKey(conf.OpenAI.ApiKey),
-			),
-		}
-	})
-	return instance
+	opts := []option.RequestOption{option.WithAPIKey(conf.OpenAI.ApiKey)}
+	httpClient, err := cassette.HTTPClient(conf)
+	if err != nil {
+		panic(fmt.Sprintf("failed to open LLM cassette: %v", err))
+	}
+	if httpClient != nil {
+		opts = append(opts, option.WithHTTPClient(httpClient))
+	}
+
+	return &OpenAIClient{
+		client: openai.NewClient(opts...),
+	}
 }
 
 func (o *OpenAIClient) SendChat(ctx context.Context, modelName string, messages []openai.ChatCompletionMessageParamUnion) (*responses.ResponseAI, error) {
+	return o.sendChat(ctx, o.newChatParams(modelName, messages))
+}
+
+// SendChatWithSchema forces the completion to follow the given JSON schema (structured outputs)
+func (o *OpenAIClient) SendChatWithSchema(ctx context.Context, modelName string, messages []openai.ChatCompletionMessageParamUnion, schemaName string, schema map[string]any) (*responses.ResponseAI, error) {
+	params := o.newChatParams(modelName, messages)
+	params.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{
+		OfJSONSchema: &shared.ResponseFormatJSONSchemaParam{
+			JSONSchema: shared.ResponseFormatJSONSchemaJSONSchemaParam{
+				Name:   schemaName,
+				Strict: openai.Bool(true),
+				Schema: schema,
+			},
+		},
+	}
+
+	return o.sendChat(ctx, params)
+}
+
+func (o *OpenAIClient) newChatParams(modelName string, messages []openai.ChatCompletionMessageParamUnion) openai.ChatCompletionNewParams {
 	// Enhanced parameters for better accuracy
 	var model openai.ChatModel
 	switch modelName {
@@ -51,14 +78,18 @@ func (o *OpenAIClient) SendChat(ctx context.Context, modelName string, messages
 		model = openai.ChatModelGPT4o // Default to GPT-4o for better accuracy
 	}
 
-	resp, err := o.client.Chat.Completions.New(ctx, openai.ChatCompletionNewParams{
+	return openai.ChatCompletionNewParams{
 		Model:       model,
 		Messages:    messages,
 		Temperature: openai.Float(0.0), // Zero temperature for maximum consistency
 		MaxTokens:   openai.Int(4000),  // Optimized for receipt data
 		Seed:        openai.Int(12345), // Fixed seed for reproducible results
 		TopP:        openai.Float(0.1), // Low top_p for more focused responses
-	})
+	}
+}
+
+func (o *OpenAIClient) sendChat(ctx context.Context, params openai.ChatCompletionNewParams) (*responses.ResponseAI, error) {
+	resp, err := o.client.Chat.Completions.New(ctx, params)
 	if err != nil {
 		return nil, err
 	}
@@ -71,6 +102,7 @@ func (o *OpenAIClient) SendChat(ctx context.Context, modelName string, messages
 		Response:    resp.Choices[0].Message.Content,
 		InputToken:  int(resp.Usage.PromptTokens),
 		OutputToken: int(resp.Usage.CompletionTokens),
+		Model:       string(params.Model),
 	}
 
 	return res, nil
@@ -90,7 +122,7 @@ func (o *OpenAIClient) SendChatStream(ctx context.Context, modelName string, mes
 
 func (o *OpenAIClient) CreateEmbedding(ctx context.Context, input openai.EmbeddingNewParamsInputUnion) *responses.ResponseEmbedding {
 	resp, err := o.client.Embeddings.New(ctx, openai.EmbeddingNewParams{
-		Model: "text-embedding-3-small",
+		Model: EmbeddingModel,
 		Input: input,
 	})
 	if err != nil {
@@ -101,18 +133,56 @@ func (o *OpenAIClient) CreateEmbedding(ctx context.Context, input openai.Embeddi
 		return nil
 	}
 
-	// float64 to pgvector
-	embedding := make([]string, len(resp.Data[0].Embedding))
-	for i, v := range resp.Data[0].Embedding {
-		embedding[i] = fmt.Sprintf("%f", v)
-	}
-
-	embeddingData := fmt.Sprintf("[%s]", strings.Join(embedding, ","))
 	res := &responses.ResponseEmbedding{
-		Embeddings:  embeddingData,
-		InputToken:  int(resp.Usage.PromptTokens),
-		OutputToken: int(resp.Usage.TotalTokens),
+		Embeddings: toPgVector(resp.Data[0].Embedding),
+		InputToken: int(resp.Usage.PromptTokens),
+		Model:      EmbeddingModel,
 	}
 
 	return res
 }
+
+func (o *OpenAIClient) CreateBatchEmbedding(ctx context.Context, inputs []string) *responses.ResponseBatchEmbedding {
+	if len(inputs) == 0 {
+		return &responses.ResponseBatchEmbedding{}
+	}
+
+	resp, err := o.client.Embeddings.New(ctx, openai.EmbeddingNewParams{
+		Model: EmbeddingModel,
+		Input: openai.EmbeddingNewParamsInputUnion{
+			OfArrayOfStrings: inputs,
+		},
+	})
+	if err != nil {
+		return nil
+	}
+
+	if len(resp.Data) != len(inputs) {
+		return nil
+	}
+
+	// Data is not guaranteed to be ordered, so place each vector by its index
+	embeddings := make([]string, len(inputs))
+	for _, data := range resp.Data {
+		if data.Index < 0 || int(data.Index) >= len(inputs) {
+			return nil
+		}
+		embeddings[data.Index] = toPgVector(data.Embedding)
+	}
+
+	return &responses.ResponseBatchEmbedding{
+		Embeddings: embeddings,
+		InputToken: int(resp.Usage.PromptTokens),
+		Model:      EmbeddingModel,
+	}
+}
+
+// toPgVector converts an embedding to the pgvector text representation
+func toPgVector(values []float64) string {
+	embedding := make([]string, len(values))
+	for i, v := range values {
+		embedding[i] = fmt.Sprintf("%f", v)
+	}
+
+	return fmt.Sprintf("[%s]", strings.Join(embedding, ","))
+}
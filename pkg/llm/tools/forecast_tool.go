@@ -0,0 +1,134 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// forecastTopCategories limits how many categories per month are handed to the LLM
+const forecastTopCategories = 5
+
+// ForecastTool handles cash-flow forecast tool calls
+type ForecastTool struct{}
+
+// NewForecastTool creates a new forecast tool
+func NewForecastTool() *ForecastTool {
+	return &ForecastTool{}
+}
+
+// Name returns the tool name
+func (ft *ForecastTool) Name() string {
+	return "getCashFlowForecast"
+}
+
+// forecastSummary is the compact view of the forecast returned to the LLM, the daily series is left out
+type forecastSummary struct {
+	AsOf                string                 `json:"as_of"`
+	Currency            string                 `json:"currency"`
+	ConfidenceLevel     float64                `json:"confidence_level"`
+	StartingBalance     int64                  `json:"starting_balance"`
+	Months              []forecastMonthSummary `json:"months"`
+	LowestBalance       responses.ForecastDay  `json:"lowest_balance"`
+	FirstNegative       *responses.ForecastDay `json:"first_negative_day,omitempty"`
+	FirstAtRisk         *responses.ForecastDay `json:"first_at_risk_day,omitempty"`
+	NextPayday          *responses.ForecastDay `json:"next_payday,omitempty"`
+	RunsOutBeforePayday *bool                  `json:"runs_out_before_payday,omitempty"`
+}
+
+type forecastMonthSummary struct {
+	Month         string                 `json:"month"`
+	Income        responses.ForecastBand `json:"income"`
+	Expense       responses.ForecastBand `json:"expense"`
+	EndBalance    responses.ForecastBand `json:"end_balance"`
+	TopCategories []categorySpend        `json:"top_categories"`
+}
+
+type categorySpend struct {
+	Category string                 `json:"category"`
+	Spend    responses.ForecastBand `json:"spend"`
+}
+
+// Handle handles the forecast tool call
+func (ft *ForecastTool) Handle(toolCall llms.ToolCall, ctx *ToolContext) (llms.MessageContent, error) {
+	var args ForecastArgs
+	if toolCall.FunctionCall.Arguments != "" {
+		if err := json.Unmarshal([]byte(toolCall.FunctionCall.Arguments), &args); err != nil {
+			return CreateErrorToolResponse(toolCall.FunctionCall.Name, fmt.Sprintf("Failed to parse arguments: %s", err.Error())), nil
+		}
+	}
+
+	// Check if forecast service is available
+	if ctx.ForecastService == nil {
+		return CreateErrorToolResponse(toolCall.FunctionCall.Name, "Forecast service not available"), nil
+	}
+
+	req := &requests.ForecastQuery{Months: args.Months}
+	if args.CurrentBalance != 0 {
+		req.CurrentBalance = strconv.FormatInt(int64(args.CurrentBalance), 10) // Convert to Rupiah integer
+	}
+
+	forecast, err := ctx.ForecastService.GetForecast(ctx.UserId, req, time.Now())
+	if err != nil {
+		return CreateErrorToolResponse(toolCall.FunctionCall.Name, fmt.Sprintf("Failed to forecast cash flow: %s", err.Error())), nil
+	}
+
+	content, err := json.Marshal(ft.summarize(forecast))
+	if err != nil {
+		return CreateErrorToolResponse(toolCall.FunctionCall.Name, fmt.Sprintf("Failed to encode forecast: %s", err.Error())), nil
+	}
+
+	return CreateSuccessToolResponse(toolCall.FunctionCall.Name, string(content)), nil
+}
+
+// summarize keeps what the LLM needs to answer balance questions without the per-day series
+func (ft *ForecastTool) summarize(forecast *responses.ForecastResponse) forecastSummary {
+	summary := forecastSummary{
+		AsOf:            forecast.AsOf,
+		Currency:        forecast.Currency.Code,
+		ConfidenceLevel: forecast.ConfidenceLevel,
+		StartingBalance: forecast.StartingBalance,
+		Months:          make([]forecastMonthSummary, 0, len(forecast.Months)),
+		LowestBalance:   forecast.LowestBalance,
+		FirstNegative:   forecast.FirstNegative,
+		FirstAtRisk:     forecast.FirstAtRisk,
+		NextPayday:      forecast.NextPayday,
+	}
+
+	for _, month := range forecast.Months {
+		monthSummary := forecastMonthSummary{
+			Month:         month.Month,
+			Income:        month.Income,
+			Expense:       month.Expense,
+			EndBalance:    month.EndBalance,
+			TopCategories: make([]categorySpend, 0, forecastTopCategories),
+		}
+		for i, category := range month.Categories {
+			if i == forecastTopCategories {
+				break
+			}
+			name := category.CategoryName
+			if name == "" {
+				name = category.CategoryId
+			}
+			monthSummary.TopCategories = append(monthSummary.TopCategories, categorySpend{
+				Category: name,
+				Spend:    category.Spend,
+			})
+		}
+		summary.Months = append(summary.Months, monthSummary)
+	}
+
+	if forecast.NextPayday != nil {
+		// Dates share the YYYY-MM-DD layout, so they compare as strings
+		runsOut := forecast.FirstNegative != nil && forecast.FirstNegative.Date < forecast.NextPayday.Date
+		summary.RunsOutBeforePayday = &runsOut
+	}
+
+	return summary
+}
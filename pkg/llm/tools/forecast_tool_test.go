@@ -0,0 +1,124 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/saufiroja/fin-ai/internal/contracts/requests"
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+	"github.com/tmc/langchaingo/llms"
+)
+
+type fakeForecastService struct {
+	forecast *responses.ForecastResponse
+	got      *requests.ForecastQuery
+}
+
+func (f *fakeForecastService) GetForecast(userId string, req *requests.ForecastQuery, now time.Time) (*responses.ForecastResponse, error) {
+	f.got = req
+	return f.forecast, nil
+}
+
+func forecastMonth(month string, categories int) responses.ForecastMonth {
+	m := responses.ForecastMonth{
+		Month:      month,
+		EndBalance: responses.ForecastBand{Expected: 685000, Low: 547523, High: 822477},
+	}
+	for i := 0; i < categories; i++ {
+		spend := int64(100000 * (categories - i))
+		m.Categories = append(m.Categories, responses.ForecastCategory{
+			CategoryId: fmt.Sprintf("category-%d", i),
+			Spend:      responses.ForecastBand{Expected: spend, Low: spend / 2, High: spend * 2},
+		})
+	}
+	m.Categories[0].CategoryName = "Rent"
+	return m
+}
+
+func TestForecastToolSummary(t *testing.T) {
+	tests := []struct {
+		name          string
+		months        int
+		firstNegative string
+		wantRunsOut   bool
+	}{
+		{"one month, never negative", 1, "", false},
+		{"two months, negative after payday", 2, "2024-04-20", false},
+		{"three months, negative before payday", 3, "2024-03-25", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forecast := &responses.ForecastResponse{
+				AsOf:            "2024-03-10",
+				Currency:        responses.CurrencyMeta{Code: "IDR"},
+				ConfidenceLevel: 0.8,
+				StartingBalance: 3000000,
+				Daily:           []responses.ForecastDay{{Date: "2024-03-11"}},
+				NextPayday:      &responses.ForecastDay{Date: "2024-04-01"},
+			}
+			for i := 0; i < tt.months; i++ {
+				forecast.Months = append(forecast.Months, forecastMonth(fmt.Sprintf("2024-%02d", 3+i), 7))
+			}
+			if tt.firstNegative != "" {
+				forecast.FirstNegative = &responses.ForecastDay{Date: tt.firstNegative}
+			}
+			service := &fakeForecastService{forecast: forecast}
+
+			toolCall := llms.ToolCall{FunctionCall: &llms.FunctionCall{
+				Name:      "getCashFlowForecast",
+				Arguments: fmt.Sprintf(`{"months":%d,"currentBalance":3000000}`, tt.months),
+			}}
+			message, err := NewForecastTool().Handle(toolCall, &ToolContext{ForecastService: service, UserId: "user"})
+			if err != nil {
+				t.Fatalf("Handle() error = %v", err)
+			}
+
+			if service.got.Months != tt.months || service.got.CurrentBalance != "3000000" {
+				t.Errorf("forecast query = %+v, want %d months and balance 3000000", service.got, tt.months)
+			}
+
+			var summary forecastSummary
+			content := message.Parts[0].(llms.ToolCallResponse).Content
+			if err := json.Unmarshal([]byte(content), &summary); err != nil {
+				t.Fatalf("tool response is not a summary: %v: %s", err, content)
+			}
+
+			if len(summary.Months) != tt.months {
+				t.Fatalf("summary has %d months, want %d", len(summary.Months), tt.months)
+			}
+			for _, month := range summary.Months {
+				if month.EndBalance != (responses.ForecastBand{Expected: 685000, Low: 547523, High: 822477}) {
+					t.Errorf("%s end balance = %+v", month.Month, month.EndBalance)
+				}
+				if len(month.TopCategories) != forecastTopCategories {
+					t.Errorf("%s has %d categories, want %d", month.Month, len(month.TopCategories), forecastTopCategories)
+				}
+				if month.TopCategories[0].Category != "Rent" || month.TopCategories[1].Category != "category-1" {
+					t.Errorf("%s categories are named %q and %q, want the name then the id", month.Month,
+						month.TopCategories[0].Category, month.TopCategories[1].Category)
+				}
+				if spend := month.TopCategories[0].Spend; spend.Low > spend.Expected || spend.Expected > spend.High {
+					t.Errorf("%s spend band %+v is not ordered", month.Month, spend)
+				}
+			}
+
+			if summary.RunsOutBeforePayday == nil || *summary.RunsOutBeforePayday != tt.wantRunsOut {
+				t.Errorf("runs out before payday = %v, want %v", summary.RunsOutBeforePayday, tt.wantRunsOut)
+			}
+		})
+	}
+}
+
+func TestForecastToolWithoutService(t *testing.T) {
+	toolCall := llms.ToolCall{FunctionCall: &llms.FunctionCall{Name: "getCashFlowForecast"}}
+	message, err := NewForecastTool().Handle(toolCall, &ToolContext{})
+	if err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+	if content := message.Parts[0].(llms.ToolCallResponse).Content; content != "Forecast service not available" {
+		t.Errorf("content = %q", content)
+	}
+}
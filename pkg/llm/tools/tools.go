@@ -84,6 +84,28 @@ func (tr *ToolRegistry) GetAvailableTools() []llms.Tool {
 		},
 	})
 
+	// Add cash-flow forecast tool
+	tools = append(tools, llms.Tool{
+		Type: "function",
+		Function: &llms.FunctionDefinition{
+			Name:        "getCashFlowForecast",
+			Description: "Forecast the user's balance and spending for the next months. Use it to answer questions like \"will I run out of money before payday?\" or \"how much will I spend on food this month?\"",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"months": map[string]any{
+						"type":        "integer",
+						"description": "Number of months to forecast including the current month, between 1 and 3 (default 1)",
+					},
+					"currentBalance": map[string]any{
+						"type":        "number",
+						"description": "The user's current balance if they mention it (optional - computed from recorded transactions if not provided)",
+					},
+				},
+			},
+		},
+	})
+
 	return tools
 }
 
@@ -2,6 +2,7 @@ package tools
 
 import (
 	"github.com/saufiroja/fin-ai/internal/domains/categories"
+	"github.com/saufiroja/fin-ai/internal/domains/forecast"
 	"github.com/saufiroja/fin-ai/internal/domains/transaction"
 	"github.com/tmc/langchaingo/llms"
 )
@@ -10,6 +11,7 @@ import (
 type ToolContext struct {
 	TransactionService transaction.TransactionManager
 	CategoryService    categories.CategoryManager
+	ForecastService    forecast.ForecastManager
 	UserId             string
 }
 
@@ -30,3 +32,9 @@ type TransactionArgs struct {
 	Confirmed         bool    `json:"confirmed"`
 	Discount          float64 `json:"discount"`
 }
+
+// ForecastArgs represents the arguments for cash-flow forecast tool
+type ForecastArgs struct {
+	Months         int     `json:"months"`
+	CurrentBalance float64 `json:"currentBalance"`
+}
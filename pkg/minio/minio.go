@@ -1,13 +1,16 @@
 package minio
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"log"
 	"mime/multipart"
+	"net/url"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
@@ -21,6 +24,11 @@ type MinioManager interface {
 	ReadAndEncodeFile(bucketName, objectName string) ([]byte, error)
 	BucketExists(bucketName string) (bool, error)
 	CreateBucket(bucketName string) error
+	UploadBytes(bucketName, objectName string, data []byte, contentType string) error
+	PresignedGetURL(bucketName, objectName, downloadName string, expiry time.Duration) (string, error)
+	ListObjectNames(bucketName, prefix string) ([]string, error)
+	GetObjectReader(bucketName, objectName string) (io.ReadCloser, error)
+	RemoveObjectsWithPrefix(bucketName, prefix string) (int, error)
 }
 
 type MinioClient struct {
@@ -146,3 +154,95 @@ func (m *MinioClient) CreateBucket(bucketName string) error {
 	log.Printf("Bucket %s created successfully\n", bucketName)
 	return nil
 }
+
+func (m *MinioClient) UploadBytes(bucketName, objectName string, data []byte, contentType string) error {
+	ctx := context.Background()
+
+	_, err := m.client.PutObject(ctx, bucketName, objectName, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload file: %w", err)
+	}
+
+	return nil
+}
+
+// PresignedGetURL returns a temporary download link, downloadName is the file name the browser saves
+func (m *MinioClient) PresignedGetURL(bucketName, objectName, downloadName string, expiry time.Duration) (string, error) {
+	ctx := context.Background()
+
+	params := url.Values{}
+	if downloadName != "" {
+		params.Set("response-content-disposition", fmt.Sprintf("attachment; filename=%q", downloadName))
+	}
+
+	presigned, err := m.client.PresignedGetObject(ctx, bucketName, objectName, expiry, params)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign object: %w", err)
+	}
+
+	return presigned.String(), nil
+}
+
+// ListObjectNames returns the names of all objects under prefix, a missing bucket has no objects
+func (m *MinioClient) ListObjectNames(bucketName, prefix string) ([]string, error) {
+	ctx := context.Background()
+
+	exists, err := m.client.BucketExists(ctx, bucketName)
+	if err != nil {
+		return nil, fmt.Errorf("error checking bucket existence: %w", err)
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	var names []string
+	for object := range m.client.ListObjects(ctx, bucketName, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if object.Err != nil {
+			return nil, fmt.Errorf("error listing objects: %w", object.Err)
+		}
+		names = append(names, object.Key)
+	}
+
+	return names, nil
+}
+
+// GetObjectReader streams an object, the caller closes the reader
+func (m *MinioClient) GetObjectReader(bucketName, objectName string) (io.ReadCloser, error) {
+	ctx := context.Background()
+
+	object, err := m.client.GetObject(ctx, bucketName, objectName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting object: %w", err)
+	}
+
+	return object, nil
+}
+
+// RemoveObjectsWithPrefix deletes every object under prefix and returns how many were removed
+func (m *MinioClient) RemoveObjectsWithPrefix(bucketName, prefix string) (int, error) {
+	ctx := context.Background()
+
+	names, err := m.ListObjectNames(bucketName, prefix)
+	if err != nil {
+		return 0, err
+	}
+	if len(names) == 0 {
+		return 0, nil
+	}
+
+	objects := make(chan minio.ObjectInfo, len(names))
+	for _, name := range names {
+		objects <- minio.ObjectInfo{Key: name}
+	}
+	close(objects)
+
+	for result := range m.client.RemoveObjects(ctx, bucketName, objects, minio.RemoveObjectsOptions{}) {
+		if result.Err != nil {
+			return 0, fmt.Errorf("failed to remove object %s: %w", result.ObjectName, result.Err)
+		}
+	}
+
+	return len(names), nil
+}
@@ -0,0 +1,115 @@
+package receipteval
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+)
+
+const (
+	categoriesFile = "categories.json"
+	expectedSuffix = ".expected.json"
+	responseSuffix = ".response.txt"
+)
+
+var imageExtensions = []string{".jpg", ".jpeg", ".png"}
+
+// Category is a category offered to the model, the labels refer to CategoryId
+type Category struct {
+	CategoryId string `json:"category_id"`
+	Name       string `json:"name"`
+}
+
+// Sample is one labelled receipt. ImagePath is empty when the image is not in the dataset, such a sample
+// can only be replayed.
+type Sample struct {
+	Name         string
+	ImagePath    string
+	ResponsePath string
+	Expected     responses.ExtractedReceiptResponse
+}
+
+// HasResponse reports whether a model response was recorded for the sample
+func (s Sample) HasResponse() bool {
+	_, err := os.Stat(s.ResponsePath)
+	return err == nil
+}
+
+// Dataset is a directory of labelled receipts:
+//
+//	categories.json         the categories offered to the model
+//	<name>.jpg|.jpeg|.png   the receipt image
+//	<name>.expected.json    the label, in the {"extracted_receipt": {...}} shape the model answers with
+//	<name>.response.txt     the recorded model response, used by the replay mode
+type Dataset struct {
+	Dir        string
+	Categories []Category
+	Samples    []Sample
+}
+
+func LoadDataset(dir string) (*Dataset, error) {
+	dataset := &Dataset{Dir: dir}
+
+	data, err := os.ReadFile(filepath.Join(dir, categoriesFile))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read %s: %w", categoriesFile, err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &dataset.Categories); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", categoriesFile, err)
+		}
+	}
+
+	labels, err := filepath.Glob(filepath.Join(dir, "*"+expectedSuffix))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(labels)
+
+	for _, label := range labels {
+		name := strings.TrimSuffix(filepath.Base(label), expectedSuffix)
+
+		data, err := os.ReadFile(label)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read label of %s: %w", name, err)
+		}
+		var expected responses.ReceiptExtractionResponse
+		if err := json.Unmarshal(data, &expected); err != nil {
+			return nil, fmt.Errorf("failed to parse label of %s: %w", name, err)
+		}
+
+		sample := Sample{
+			Name:         name,
+			ResponsePath: filepath.Join(dir, name+responseSuffix),
+			Expected:     expected.ExtractedReceipt,
+		}
+		for _, ext := range imageExtensions {
+			path := filepath.Join(dir, name+ext)
+			if _, err := os.Stat(path); err == nil {
+				sample.ImagePath = path
+				break
+			}
+		}
+		dataset.Samples = append(dataset.Samples, sample)
+	}
+
+	if len(dataset.Samples) == 0 {
+		return nil, fmt.Errorf("no *%s labels in %s", expectedSuffix, dir)
+	}
+	return dataset, nil
+}
+
+// CategoriesString lists the categories the way the receipt service offers them to the model
+func (d *Dataset) CategoriesString() string {
+	categories := make([]string, len(d.Categories))
+	for i, category := range d.Categories {
+		categories[i] = fmt.Sprintf("%s (%s)", category.Name, category.CategoryId)
+	}
+	return strings.Join(categories, ", ")
+}
@@ -0,0 +1,233 @@
+package receipteval
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+)
+
+// Field is a part of the receipt that is scored on its own
+type Field string
+
+const (
+	// FieldMerchant compares the merchant name, ignoring case and spacing
+	FieldMerchant Field = "merchant"
+	// FieldTotals compares the sub total, the total discount and the total shopping
+	FieldTotals Field = "totals"
+	// FieldItems matches the items by name, a matched item is right when its quantity and total price are
+	FieldItems Field = "items"
+	// FieldCategories compares the category of the items matched by name
+	FieldCategories Field = "categories"
+)
+
+var Fields = []Field{FieldMerchant, FieldTotals, FieldItems, FieldCategories}
+
+// Counts are the true positives, false positives and false negatives of a field. A wrong value counts as
+// both a false positive and a false negative.
+type Counts struct {
+	TruePositives  int `json:"true_positives"`
+	FalsePositives int `json:"false_positives"`
+	FalseNegatives int `json:"false_negatives"`
+}
+
+// Precision is the share of the predicted values that are right, 1 when nothing was predicted
+func (c Counts) Precision() float64 {
+	if c.TruePositives+c.FalsePositives == 0 {
+		return 1
+	}
+	return float64(c.TruePositives) / float64(c.TruePositives+c.FalsePositives)
+}
+
+// Recall is the share of the labelled values that were predicted right, 1 when nothing was labelled
+func (c Counts) Recall() float64 {
+	if c.TruePositives+c.FalseNegatives == 0 {
+		return 1
+	}
+	return float64(c.TruePositives) / float64(c.TruePositives+c.FalseNegatives)
+}
+
+func (c *Counts) compare(want, got string) {
+	switch {
+	case got != "" && got == want:
+		c.TruePositives++
+	default:
+		if got != "" {
+			c.FalsePositives++
+		}
+		if want != "" {
+			c.FalseNegatives++
+		}
+	}
+}
+
+func (c *Counts) compareAmount(want, got int64) {
+	if want == got {
+		c.TruePositives++
+		return
+	}
+	c.FalsePositives++
+	c.FalseNegatives++
+}
+
+func normalize(value string) string {
+	return strings.Join(strings.Fields(strings.ToLower(value)), " ")
+}
+
+func categoryOf(item responses.ReceiptItemResponse) string {
+	if item.CategoryId == nil {
+		return ""
+	}
+	return *item.CategoryId
+}
+
+// Compare scores an extracted receipt against its label, got is nil when the model call failed or its
+// response could not be parsed, every labelled value is then missed
+func Compare(want *responses.ExtractedReceiptResponse, got *responses.ExtractedReceiptResponse) map[Field]Counts {
+	var merchant, totals, items, categories Counts
+	counts := func() map[Field]Counts {
+		return map[Field]Counts{
+			FieldMerchant:   merchant,
+			FieldTotals:     totals,
+			FieldItems:      items,
+			FieldCategories: categories,
+		}
+	}
+
+	if got == nil {
+		// Nothing was predicted, the totals are missed even when they are zero
+		merchant.compare(normalize(want.MerchantName), "")
+		totals.FalseNegatives += 3
+		for _, wantItem := range want.Items {
+			items.FalseNegatives++
+			categories.compare(categoryOf(wantItem), "")
+		}
+		return counts()
+	}
+
+	merchant.compare(normalize(want.MerchantName), normalize(got.MerchantName))
+	totals.compareAmount(want.SubTotal, got.SubTotal)
+	totals.compareAmount(want.TotalDiscount, got.TotalDiscount)
+	totals.compareAmount(want.TotalShopping, got.TotalShopping)
+
+	// Items are paired by name in label order, each predicted item is used once
+	used := make([]bool, len(got.Items))
+	for _, wantItem := range want.Items {
+		match := -1
+		for i, gotItem := range got.Items {
+			if !used[i] && normalize(gotItem.ItemName) == normalize(wantItem.ItemName) {
+				match = i
+				break
+			}
+		}
+		if match < 0 {
+			items.FalseNegatives++
+			categories.compare(categoryOf(wantItem), "")
+			continue
+		}
+
+		used[match] = true
+		gotItem := got.Items[match]
+		if gotItem.ItemQuantity == wantItem.ItemQuantity && gotItem.ItemPriceTotal == wantItem.ItemPriceTotal {
+			items.TruePositives++
+		} else {
+			items.FalsePositives++
+			items.FalseNegatives++
+		}
+		categories.compare(categoryOf(wantItem), categoryOf(gotItem))
+	}
+	for i, gotItem := range got.Items {
+		if !used[i] {
+			items.FalsePositives++
+			categories.compare("", categoryOf(gotItem))
+		}
+	}
+
+	return counts()
+}
+
+// Report sums the scores of a run over the dataset
+type Report struct {
+	Samples       int
+	CallFailures  int
+	ParseFailures int
+	InputTokens   int
+	OutputTokens  int
+	Counts        map[Field]Counts
+}
+
+func NewReport() *Report {
+	return &Report{Counts: make(map[Field]Counts, len(Fields))}
+}
+
+// Add scores one sample, got is nil when the call or the parsing failed
+func (r *Report) Add(want, got *responses.ExtractedReceiptResponse) {
+	r.Samples++
+	for field, counts := range Compare(want, got) {
+		total := r.Counts[field]
+		total.TruePositives += counts.TruePositives
+		total.FalsePositives += counts.FalsePositives
+		total.FalseNegatives += counts.FalseNegatives
+		r.Counts[field] = total
+	}
+}
+
+// Score is the precision and recall of a field
+type Score struct {
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+}
+
+// Scores are kept as the baseline of later runs
+type Scores map[Field]Score
+
+func (r *Report) Scores() Scores {
+	scores := make(Scores, len(Fields))
+	for _, field := range Fields {
+		counts := r.Counts[field]
+		scores[field] = Score{Precision: counts.Precision(), Recall: counts.Recall()}
+	}
+	return scores
+}
+
+func LoadScores(path string) (Scores, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var scores Scores
+	if err := json.Unmarshal(data, &scores); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	return scores, nil
+}
+
+func (s Scores) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Regressions lists the scores that dropped more than threshold below the baseline, fields missing from
+// the baseline are not checked
+func Regressions(baseline, current Scores, threshold float64) []string {
+	var regressions []string
+	for _, field := range Fields {
+		before, ok := baseline[field]
+		if !ok {
+			continue
+		}
+		after := current[field]
+		if before.Precision-after.Precision > threshold {
+			regressions = append(regressions, fmt.Sprintf("%s precision %.3f -> %.3f", field, before.Precision, after.Precision))
+		}
+		if before.Recall-after.Recall > threshold {
+			regressions = append(regressions, fmt.Sprintf("%s recall %.3f -> %.3f", field, before.Recall, after.Recall))
+		}
+	}
+	return regressions
+}
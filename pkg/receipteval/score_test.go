@@ -0,0 +1,215 @@
+package receipteval
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/saufiroja/fin-ai/internal/contracts/responses"
+)
+
+func category(id string) *string {
+	return &id
+}
+
+func labelledReceipt() *responses.ExtractedReceiptResponse {
+	return &responses.ExtractedReceiptResponse{
+		MerchantName:  "Indomaret Sudirman",
+		SubTotal:      60000,
+		TotalDiscount: 5000,
+		TotalShopping: 55000,
+		Items: []responses.ReceiptItemResponse{
+			{ItemName: "Indomie Goreng", ItemQuantity: 5, ItemPriceTotal: 17500, CategoryId: category("food")},
+			{ItemName: "Aqua 600ml", ItemQuantity: 2, ItemPriceTotal: 7000, CategoryId: category("drinks")},
+			{ItemName: "Sabun Lifebuoy", ItemQuantity: 1, ItemPriceTotal: 35500, CategoryId: category("household")},
+		},
+	}
+}
+
+// partialReceipt gets the merchant up to case and spacing, misses the discount, gets one item right, one item
+// with the wrong quantity and category, misses the soap and adds a bag that is not on the label
+func partialReceipt() *responses.ExtractedReceiptResponse {
+	return &responses.ExtractedReceiptResponse{
+		MerchantName:  "  INDOMARET   sudirman ",
+		SubTotal:      60000,
+		TotalDiscount: 0,
+		TotalShopping: 55000,
+		Items: []responses.ReceiptItemResponse{
+			{ItemName: "aqua 600ML", ItemQuantity: 1, ItemPriceTotal: 3500, CategoryId: category("food")},
+			{ItemName: "indomie goreng", ItemQuantity: 5, ItemPriceTotal: 17500, CategoryId: category("food")},
+			{ItemName: "Kantong Plastik", ItemQuantity: 1, ItemPriceTotal: 200},
+		},
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name string
+		want *responses.ExtractedReceiptResponse
+		got  *responses.ExtractedReceiptResponse
+		out  map[Field]Counts
+	}{
+		{
+			name: "exact extraction",
+			want: labelledReceipt(),
+			got:  labelledReceipt(),
+			out: map[Field]Counts{
+				FieldMerchant:   {TruePositives: 1},
+				FieldTotals:     {TruePositives: 3},
+				FieldItems:      {TruePositives: 3},
+				FieldCategories: {TruePositives: 3},
+			},
+		},
+		{
+			name: "partial item matches and category mismatch",
+			want: labelledReceipt(),
+			got:  partialReceipt(),
+			out: map[Field]Counts{
+				FieldMerchant:   {TruePositives: 1},
+				FieldTotals:     {TruePositives: 2, FalsePositives: 1, FalseNegatives: 1},
+				FieldItems:      {TruePositives: 1, FalsePositives: 2, FalseNegatives: 2},
+				FieldCategories: {TruePositives: 1, FalsePositives: 1, FalseNegatives: 2},
+			},
+		},
+		{
+			name: "failed extraction",
+			want: labelledReceipt(),
+			got:  nil,
+			out: map[Field]Counts{
+				FieldMerchant:   {FalseNegatives: 1},
+				FieldTotals:     {FalseNegatives: 3},
+				FieldItems:      {FalseNegatives: 3},
+				FieldCategories: {FalseNegatives: 3},
+			},
+		},
+		{
+			name: "repeated item is matched once",
+			want: &responses.ExtractedReceiptResponse{Items: []responses.ReceiptItemResponse{
+				{ItemName: "Aqua 600ml", ItemQuantity: 1, ItemPriceTotal: 3500, CategoryId: category("drinks")},
+				{ItemName: "Aqua 600ml", ItemQuantity: 1, ItemPriceTotal: 3500, CategoryId: category("drinks")},
+			}},
+			got: &responses.ExtractedReceiptResponse{Items: []responses.ReceiptItemResponse{
+				{ItemName: "Aqua 600ml", ItemQuantity: 1, ItemPriceTotal: 3500, CategoryId: category("drinks")},
+				{ItemName: "Tissue", ItemQuantity: 1, ItemPriceTotal: 9000, CategoryId: category("household")},
+			}},
+			out: map[Field]Counts{
+				FieldMerchant:   {},
+				FieldTotals:     {TruePositives: 3},
+				FieldItems:      {TruePositives: 1, FalsePositives: 1, FalseNegatives: 1},
+				FieldCategories: {TruePositives: 1, FalsePositives: 1, FalseNegatives: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compare(tt.want, tt.got)
+			for _, field := range Fields {
+				if got[field] != tt.out[field] {
+					t.Errorf("%s = %+v, want %+v", field, got[field], tt.out[field])
+				}
+			}
+		})
+	}
+}
+
+func TestReportScores(t *testing.T) {
+	report := NewReport()
+	report.Add(labelledReceipt(), labelledReceipt())
+	report.Add(labelledReceipt(), partialReceipt())
+
+	want := Scores{
+		FieldMerchant:   {Precision: 1, Recall: 1},
+		FieldTotals:     {Precision: 5.0 / 6, Recall: 5.0 / 6},
+		FieldItems:      {Precision: 4.0 / 6, Recall: 4.0 / 6},
+		FieldCategories: {Precision: 4.0 / 5, Recall: 4.0 / 6},
+	}
+	if report.Samples != 2 {
+		t.Errorf("samples = %d, want 2", report.Samples)
+	}
+	if got := report.Scores(); !reflect.DeepEqual(got, want) {
+		t.Errorf("scores = %+v, want %+v", got, want)
+	}
+
+	// An empty report has nothing wrong
+	for field, score := range NewReport().Scores() {
+		if score != (Score{Precision: 1, Recall: 1}) {
+			t.Errorf("empty report %s = %+v, want 1/1", field, score)
+		}
+	}
+}
+
+func TestRegressions(t *testing.T) {
+	baseline := Scores{
+		FieldMerchant: {Precision: 1, Recall: 1},
+		FieldItems:    {Precision: 0.75, Recall: 0.75},
+		FieldTotals:   {Precision: 1, Recall: 1},
+	}
+
+	tests := []struct {
+		name      string
+		current   Scores
+		threshold float64
+		want      []string
+	}{
+		{
+			name:      "unchanged",
+			current:   baseline,
+			threshold: 0,
+		},
+		{
+			name: "drop within the threshold",
+			current: Scores{
+				FieldMerchant: {Precision: 1, Recall: 1},
+				FieldItems:    {Precision: 0.5, Recall: 0.625},
+				FieldTotals:   {Precision: 1, Recall: 1},
+			},
+			threshold: 0.25,
+		},
+		{
+			name: "drop beyond the threshold",
+			current: Scores{
+				FieldMerchant: {Precision: 0.5, Recall: 1},
+				FieldItems:    {Precision: 0.75, Recall: 0.25},
+				FieldTotals:   {Precision: 1, Recall: 1},
+			},
+			threshold: 0.25,
+			want:      []string{"merchant precision 1.000 -> 0.500", "items recall 0.750 -> 0.250"},
+		},
+		{
+			name: "improvement and fields missing from the baseline",
+			current: Scores{
+				FieldMerchant:   {Precision: 1, Recall: 1},
+				FieldItems:      {Precision: 1, Recall: 1},
+				FieldTotals:     {Precision: 1, Recall: 1},
+				FieldCategories: {Precision: 0, Recall: 0},
+			},
+			threshold: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Regressions(baseline, tt.current, tt.threshold)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Regressions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScoresSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	scores := Scores{FieldItems: {Precision: 0.8, Recall: 0.6}}
+
+	if err := scores.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadScores(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, scores) {
+		t.Errorf("loaded %+v, want %+v", loaded, scores)
+	}
+}
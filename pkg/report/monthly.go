@@ -0,0 +1,407 @@
+// Package report renders the printable monthly financial report. The PDF is drawn with the core
+// fonts only, so it needs no font files at runtime.
+package report
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/saufiroja/fin-ai/pkg/fx"
+)
+
+// Monthly is everything shown in the report, amounts are minor units of Currency
+type Monthly struct {
+	Owner            string
+	PeriodStart      time.Time
+	GeneratedAt      time.Time
+	Currency         fx.Currency
+	Locale           string // BCP 47 tag whose separators format the amounts
+	TotalIncome      int64
+	TotalExpense     int64
+	TransactionCount int64
+	PreviousIncome   int64
+	PreviousExpense  int64
+	// Categories are the expense totals per category, largest first
+	Categories []Slice
+	Budgets    []Budget
+	Merchants  []Merchant
+	Goals      []Goal
+	Narrative  string
+}
+
+type Slice struct {
+	Label string
+	Value int64
+}
+
+type Budget struct {
+	Category string
+	Limit    int64
+	Spent    int64
+}
+
+type Merchant struct {
+	Name  string
+	Total int64
+	Count int64
+}
+
+type Goal struct {
+	Title      string
+	Target     int64
+	Current    int64
+	TargetDate time.Time
+	Status     string
+}
+
+const (
+	pageWidth    = 210.0
+	margin       = 15.0
+	contentWidth = pageWidth - 2*margin
+	// maxSlices keeps the pie readable, smaller categories are merged into one slice
+	maxSlices = 6
+	maxBars   = 8
+)
+
+var months = [...]string{"Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli", "Agustus", "September", "Oktober", "November", "Desember"}
+
+// palette is used in order for chart slices and bars
+var palette = [][3]int{
+	{37, 99, 235}, {234, 88, 12}, {22, 163, 74}, {219, 39, 119}, {147, 51, 234}, {202, 138, 4}, {100, 116, 139},
+}
+
+// RenderMonthly writes the report as PDF
+func RenderMonthly(m *Monthly, w io.Writer) error {
+	r := &renderer{pdf: fpdf.New("P", "mm", "A4", ""), m: m}
+	r.translate = r.pdf.UnicodeTranslatorFromDescriptor("")
+	r.pdf.SetMargins(margin, margin, margin)
+	r.pdf.SetAutoPageBreak(true, margin)
+	r.pdf.SetTitle(r.title(), true)
+	r.pdf.SetFooterFunc(r.footer)
+
+	r.pdf.AddPage()
+	r.header()
+	r.totals()
+	r.categories()
+	r.budgets()
+	r.merchants()
+	r.goals()
+	r.narrative()
+
+	return r.pdf.Output(w)
+}
+
+type renderer struct {
+	pdf       *fpdf.Fpdf
+	m         *Monthly
+	translate func(string) string
+}
+
+func (r *renderer) title() string {
+	return fmt.Sprintf("Laporan Keuangan %s %d", months[r.m.PeriodStart.Month()-1], r.m.PeriodStart.Year())
+}
+
+func (r *renderer) header() {
+	r.pdf.SetFont("Helvetica", "B", 18)
+	r.pdf.CellFormat(contentWidth, 9, r.text(r.title()), "", 1, "L", false, 0, "")
+	r.pdf.SetFont("Helvetica", "", 10)
+	r.pdf.SetTextColor(100, 116, 139)
+	r.pdf.CellFormat(contentWidth, 5, r.text(fmt.Sprintf("%s - dibuat %s", r.m.Owner, r.m.GeneratedAt.Format("02/01/2006 15:04"))), "", 1, "L", false, 0, "")
+	r.pdf.SetTextColor(0, 0, 0)
+	r.pdf.Ln(4)
+}
+
+func (r *renderer) footer() {
+	r.pdf.SetY(-12)
+	r.pdf.SetFont("Helvetica", "", 8)
+	r.pdf.SetTextColor(100, 116, 139)
+	r.pdf.CellFormat(contentWidth, 5, fmt.Sprintf("Halaman %d", r.pdf.PageNo()), "", 0, "R", false, 0, "")
+	r.pdf.SetTextColor(0, 0, 0)
+}
+
+func (r *renderer) section(title string, height float64) {
+	// Keep the section title with its first lines
+	_, pageHeight := r.pdf.GetPageSize()
+	if r.pdf.GetY()+height+10 > pageHeight-margin {
+		r.pdf.AddPage()
+	}
+	r.pdf.Ln(3)
+	r.pdf.SetFont("Helvetica", "B", 13)
+	r.pdf.CellFormat(contentWidth, 7, r.text(title), "B", 1, "L", false, 0, "")
+	r.pdf.Ln(2)
+	r.pdf.SetFont("Helvetica", "", 10)
+}
+
+func (r *renderer) totals() {
+	r.section("Ringkasan", 30)
+
+	net := r.m.TotalIncome - r.m.TotalExpense
+	boxes := []struct {
+		label  string
+		value  int64
+		change string
+	}{
+		{"Pemasukan", r.m.TotalIncome, changeText(r.m.TotalIncome, r.m.PreviousIncome)},
+		{"Pengeluaran", r.m.TotalExpense, changeText(r.m.TotalExpense, r.m.PreviousExpense)},
+		{"Selisih", net, fmt.Sprintf("%d transaksi", r.m.TransactionCount)},
+	}
+
+	width := (contentWidth - 2*4) / 3
+	y := r.pdf.GetY()
+	for i, box := range boxes {
+		x := margin + float64(i)*(width+4)
+		r.pdf.SetFillColor(241, 245, 249)
+		r.pdf.Rect(x, y, width, 22, "F")
+
+		r.pdf.SetXY(x+3, y+2)
+		r.pdf.SetFont("Helvetica", "", 9)
+		r.pdf.CellFormat(width-6, 5, r.text(box.label), "", 2, "L", false, 0, "")
+		r.pdf.SetFont("Helvetica", "B", 13)
+		r.pdf.CellFormat(width-6, 7, r.text(r.amount(box.value)), "", 2, "L", false, 0, "")
+		r.pdf.SetFont("Helvetica", "", 8)
+		r.pdf.SetTextColor(100, 116, 139)
+		r.pdf.CellFormat(width-6, 5, r.text(box.change), "", 2, "L", false, 0, "")
+		r.pdf.SetTextColor(0, 0, 0)
+	}
+	r.pdf.SetXY(margin, y+26)
+}
+
+func (r *renderer) categories() {
+	r.section("Pengeluaran per Kategori", 70)
+	if len(r.m.Categories) == 0 {
+		r.empty("Tidak ada pengeluaran pada periode ini.")
+		return
+	}
+
+	var total int64
+	for _, c := range r.m.Categories {
+		total += c.Value
+	}
+
+	// Pie chart on the left, legend on the right
+	slices := mergeSlices(r.m.Categories, maxSlices, "Lainnya")
+	y := r.pdf.GetY()
+	centerX, centerY, radius := margin+30, y+30, 28.0
+	start := -math.Pi / 2
+	for i, s := range slices {
+		sweep := 2 * math.Pi * float64(s.Value) / float64(total)
+		r.fill(i)
+		r.pdf.Polygon(pieSlice(centerX, centerY, radius, start, start+sweep), "F")
+		start += sweep
+	}
+
+	legendX := margin + 70
+	r.pdf.SetXY(legendX, y+4)
+	for i, s := range slices {
+		r.fill(i)
+		r.pdf.Rect(legendX, r.pdf.GetY()+1.2, 3.5, 3.5, "F")
+		r.pdf.SetX(legendX + 6)
+		r.pdf.CellFormat(50, 6, r.text(truncate(s.Label, 28)), "", 0, "L", false, 0, "")
+		r.pdf.CellFormat(32, 6, r.text(r.amount(s.Value)), "", 0, "R", false, 0, "")
+		r.pdf.CellFormat(17, 6, percentText(s.Value, total), "", 1, "R", false, 0, "")
+		r.pdf.SetX(legendX)
+	}
+	r.pdf.SetXY(margin, y+64)
+
+	// Bar chart of the largest categories
+	bars := r.m.Categories
+	if len(bars) > maxBars {
+		bars = bars[:maxBars]
+	}
+	largest := bars[0].Value
+	labelWidth, amountWidth := 50.0, 32.0
+	barWidth := contentWidth - labelWidth - amountWidth - 4
+	for i, b := range bars {
+		y := r.pdf.GetY()
+		r.pdf.CellFormat(labelWidth, 6, r.text(truncate(b.Label, 28)), "", 0, "L", false, 0, "")
+		r.fill(i % len(palette))
+		if largest > 0 {
+			r.pdf.Rect(margin+labelWidth, y+1, math.Max(barWidth*float64(b.Value)/float64(largest), 0.5), 4, "F")
+		}
+		r.pdf.SetX(margin + labelWidth + barWidth + 4)
+		r.pdf.CellFormat(amountWidth, 6, r.text(r.amount(b.Value)), "", 1, "R", false, 0, "")
+	}
+}
+
+func (r *renderer) budgets() {
+	r.section("Kepatuhan Anggaran", 20)
+	if len(r.m.Budgets) == 0 {
+		r.empty("Belum ada anggaran untuk bulan ini.")
+		return
+	}
+
+	r.tableHeader([]string{"Kategori", "Anggaran", "Terpakai", "Progres", "%"}, []float64{50, 32, 32, 50, 16})
+	for _, b := range r.m.Budgets {
+		y := r.pdf.GetY()
+		r.pdf.CellFormat(50, 6, r.text(truncate(b.Category, 28)), "", 0, "L", false, 0, "")
+		r.pdf.CellFormat(32, 6, r.text(r.amount(b.Limit)), "", 0, "R", false, 0, "")
+		r.pdf.CellFormat(32, 6, r.text(r.amount(b.Spent)), "", 0, "R", false, 0, "")
+
+		used := 0.0
+		if b.Limit > 0 {
+			used = float64(b.Spent) / float64(b.Limit)
+		}
+		r.progress(margin+116, y+1.5, 46, used)
+		r.pdf.SetX(margin + 164)
+		if used > 1 {
+			r.pdf.SetTextColor(220, 38, 38)
+		}
+		r.pdf.CellFormat(16, 6, percentText(b.Spent, b.Limit), "", 1, "R", false, 0, "")
+		r.pdf.SetTextColor(0, 0, 0)
+	}
+}
+
+func (r *renderer) merchants() {
+	r.section("Merchant Teratas", 20)
+	if len(r.m.Merchants) == 0 {
+		r.empty("Tidak ada pengeluaran pada periode ini.")
+		return
+	}
+
+	r.tableHeader([]string{"Merchant", "Transaksi", "Total"}, []float64{110, 30, 40})
+	for _, merchant := range r.m.Merchants {
+		r.pdf.CellFormat(110, 6, r.text(truncate(merchant.Name, 60)), "", 0, "L", false, 0, "")
+		r.pdf.CellFormat(30, 6, strconv.FormatInt(merchant.Count, 10), "", 0, "R", false, 0, "")
+		r.pdf.CellFormat(40, 6, r.text(r.amount(merchant.Total)), "", 1, "R", false, 0, "")
+	}
+}
+
+func (r *renderer) goals() {
+	r.section("Progres Tujuan Keuangan", 20)
+	if len(r.m.Goals) == 0 {
+		r.empty("Belum ada tujuan keuangan.")
+		return
+	}
+
+	r.tableHeader([]string{"Tujuan", "Terkumpul", "Target", "Progres", "Tenggat"}, []float64{50, 32, 32, 42, 24})
+	for _, g := range r.m.Goals {
+		y := r.pdf.GetY()
+		r.pdf.CellFormat(50, 6, r.text(truncate(g.Title, 28)), "", 0, "L", false, 0, "")
+		r.pdf.CellFormat(32, 6, r.text(r.amount(g.Current)), "", 0, "R", false, 0, "")
+		r.pdf.CellFormat(32, 6, r.text(r.amount(g.Target)), "", 0, "R", false, 0, "")
+
+		progress := 0.0
+		if g.Target > 0 {
+			progress = float64(g.Current) / float64(g.Target)
+		}
+		r.progress(margin+116, y+1.5, 38, progress)
+		r.pdf.SetX(margin + 156)
+		deadline := "-"
+		if !g.TargetDate.IsZero() {
+			deadline = g.TargetDate.Format("02/01/2006")
+		}
+		r.pdf.CellFormat(24, 6, deadline, "", 1, "R", false, 0, "")
+	}
+}
+
+func (r *renderer) narrative() {
+	r.section("Analisis AI", 20)
+	if strings.TrimSpace(r.m.Narrative) == "" {
+		r.empty("Analisis AI belum tersedia untuk periode ini.")
+		return
+	}
+	r.pdf.MultiCell(contentWidth, 5.5, r.text(strings.TrimSpace(r.m.Narrative)), "", "L", false)
+}
+
+func (r *renderer) tableHeader(columns []string, widths []float64) {
+	r.pdf.SetFont("Helvetica", "B", 9)
+	r.pdf.SetFillColor(241, 245, 249)
+	for i, column := range columns {
+		align := "R"
+		if i == 0 {
+			align = "L"
+		}
+		ln := 0
+		if i == len(columns)-1 {
+			ln = 1
+		}
+		r.pdf.CellFormat(widths[i], 6, r.text(column), "", ln, align, true, 0, "")
+	}
+	r.pdf.SetFont("Helvetica", "", 10)
+}
+
+// progress draws a bar filled up to ratio, red once it goes past 100%
+func (r *renderer) progress(x, y, width, ratio float64) {
+	r.pdf.SetFillColor(226, 232, 240)
+	r.pdf.Rect(x, y, width, 3, "F")
+	if ratio > 1 {
+		r.pdf.SetFillColor(220, 38, 38)
+	} else {
+		r.pdf.SetFillColor(22, 163, 74)
+	}
+	if ratio > 0 {
+		r.pdf.Rect(x, y, width*math.Min(ratio, 1), 3, "F")
+	}
+}
+
+func (r *renderer) empty(message string) {
+	r.pdf.SetTextColor(100, 116, 139)
+	r.pdf.CellFormat(contentWidth, 6, r.text(message), "", 1, "L", false, 0, "")
+	r.pdf.SetTextColor(0, 0, 0)
+}
+
+func (r *renderer) fill(i int) {
+	c := palette[i%len(palette)]
+	r.pdf.SetFillColor(c[0], c[1], c[2])
+}
+
+// text converts UTF-8 into the code page of the core fonts
+func (r *renderer) text(s string) string {
+	return r.translate(s)
+}
+
+func (r *renderer) amount(value int64) string {
+	return fx.FormatLocale(value, r.m.Currency, r.m.Locale)
+}
+
+// mergeSlices keeps the largest slices and sums the rest into one labelled slice
+func mergeSlices(slices []Slice, limit int, other string) []Slice {
+	if len(slices) <= limit {
+		return slices
+	}
+	merged := append([]Slice{}, slices[:limit-1]...)
+	rest := Slice{Label: other}
+	for _, s := range slices[limit-1:] {
+		rest.Value += s.Value
+	}
+	return append(merged, rest)
+}
+
+// pieSlice approximates a pie slice with a polygon through the center
+func pieSlice(cx, cy, radius, from, to float64) []fpdf.PointType {
+	points := []fpdf.PointType{{X: cx, Y: cy}}
+	steps := int(math.Ceil((to-from)/(math.Pi/90))) + 1
+	for i := 0; i <= steps; i++ {
+		angle := from + (to-from)*float64(i)/float64(steps)
+		points = append(points, fpdf.PointType{X: cx + radius*math.Cos(angle), Y: cy + radius*math.Sin(angle)})
+	}
+	return points
+}
+
+func changeText(current, previous int64) string {
+	if previous == 0 {
+		return "Tidak ada data bulan lalu"
+	}
+	change := float64(current-previous) * 100 / float64(previous)
+	return fmt.Sprintf("%+.1f%% dari bulan lalu", change)
+}
+
+func percentText(part, total int64) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%%", float64(part)*100/float64(total))
+}
+
+func truncate(s string, max int) string {
+	runes := []rune(strings.TrimSpace(s))
+	if len(runes) <= max {
+		return string(runes)
+	}
+	return string(runes[:max-3]) + "..."
+}